@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+)
+
+// ActionMenuController defines methods for the action menu protocol controller.
+type ActionMenuController interface {
+
+	// Actions returns unfinished actions for the async usage.
+	Actions(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// SendMenu sends the menu to the agent on the other end of the connection
+	SendMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// RequestMenu asks the agent on the other end of the connection to send its menu
+	RequestMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ActiveMenu returns the last menu received over the connection
+	ActiveMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Perform asks the agent on the other end of the connection to perform the option of the active menu
+	Perform(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// AcceptMenuRequest responds to the menu request with the menu
+	AcceptMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeclineMenuRequest is used to reject the menu request
+	DeclineMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// AcceptPerform acknowledges that the menu option was performed
+	AcceptPerform(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeclinePerform is used to reject the perform request
+	DeclinePerform(request *models.RequestEnvelope) *models.ResponseEnvelope
+}
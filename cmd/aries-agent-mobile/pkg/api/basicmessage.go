@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+)
+
+// BasicMessageController defines methods for the basic message protocol controller.
+type BasicMessageController interface {
+
+	// SendMessage sends the basic message to the agent on the other end of the connection
+	SendMessage(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// History returns a page of the message history
+	History(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeleteHistory deletes the message history of the connection
+	DeleteHistory(request *models.RequestEnvelope) *models.ResponseEnvelope
+}
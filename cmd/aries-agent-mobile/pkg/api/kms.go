@@ -16,4 +16,13 @@ type KMSController interface {
 
 	// ImportKey imports a key.
 	ImportKey(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetKeyMetadata returns the metadata of a key set along with its versions.
+	GetKeyMetadata(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ListKeys returns the metadata of the keys filtered by key type and creation time.
+	ListKeys(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeleteKey deletes a key set along with all its versions.
+	DeleteKey(request *models.RequestEnvelope) *models.ResponseEnvelope
 }
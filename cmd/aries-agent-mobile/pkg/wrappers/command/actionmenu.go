@@ -0,0 +1,158 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+)
+
+// ActionMenu contains handler function for action menu protocol commands.
+type ActionMenu struct {
+	handlers map[string]command.Exec
+}
+
+// Actions returns unfinished actions for the async usage.
+func (a *ActionMenu) Actions(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	response, cmdErr := exec(a.handlers[cmdactionmenu.Actions], request.Payload)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// SendMenu sends the menu to the agent on the other end of the connection.
+func (a *ActionMenu) SendMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.SendMenuArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.SendMenu], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// RequestMenu asks the agent on the other end of the connection to send its menu.
+func (a *ActionMenu) RequestMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.RequestMenuArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.RequestMenu], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ActiveMenu returns the last menu received over the connection.
+func (a *ActionMenu) ActiveMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.ActiveMenuArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.ActiveMenu], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// Perform asks the agent on the other end of the connection to perform the option of the active menu.
+func (a *ActionMenu) Perform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.PerformArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.Perform], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// AcceptMenuRequest responds to the menu request with the menu.
+func (a *ActionMenu) AcceptMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.AcceptArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.AcceptMenuRequest], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DeclineMenuRequest is used to reject the menu request.
+func (a *ActionMenu) DeclineMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.DeclineArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.DeclineMenuRequest], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// AcceptPerform acknowledges that the menu option was performed.
+func (a *ActionMenu) AcceptPerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.AcceptArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.AcceptPerform], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DeclinePerform is used to reject the perform request.
+func (a *ActionMenu) DeclinePerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdactionmenu.DeclineArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[cmdactionmenu.DeclinePerform], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+)
+
+func getActionMenuController(t *testing.T) *ActionMenu {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	amc, err := a.GetActionMenuController()
+	require.NoError(t, err)
+	require.NotNil(t, amc)
+
+	am, ok := amc.(*ActionMenu)
+	require.Equal(t, ok, true)
+
+	return am
+}
+
+func TestActionMenu_Actions(t *testing.T) {
+	t.Run("test it performs an actions request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"actions":[{"PIID":"ID1","ConnectionID":"conn","Msg":null,"MyDID":"","TheirDID":""}]}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		am.handlers[cmdactionmenu.Actions] = fakeHandler.exec
+
+		resp := am.Actions(&models.RequestEnvelope{})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_SendMenu(t *testing.T) {
+	t.Run("test it sends a menu", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		am.handlers[cmdactionmenu.SendMenu] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{
+	"connectionID": "conn",
+	"menu": {"title": "Welcome", "options": [{"name": "balance", "title": "Check balance"}]}
+}`)}
+		resp := am.SendMenu(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+
+	t.Run("test it fails with invalid payload", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		resp := am.SendMenu(&models.RequestEnvelope{Payload: []byte(`{`)})
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+	})
+}
+
+func TestActionMenu_RequestMenu(t *testing.T) {
+	t.Run("test it requests a menu", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		am.handlers[cmdactionmenu.RequestMenu] = fakeHandler.exec
+
+		resp := am.RequestMenu(&models.RequestEnvelope{Payload: []byte(`{"connectionID": "conn"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_ActiveMenu(t *testing.T) {
+	t.Run("test it returns the active menu", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"menu":{"title":"Welcome"}}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		am.handlers[cmdactionmenu.ActiveMenu] = fakeHandler.exec
+
+		resp := am.ActiveMenu(&models.RequestEnvelope{Payload: []byte(`{"connectionID": "conn"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_Perform(t *testing.T) {
+	t.Run("test it performs a menu option", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		am.handlers[cmdactionmenu.Perform] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{
+	"connectionID": "conn",
+	"name": "balance",
+	"params": {"account": "savings"}
+}`)}
+		resp := am.Perform(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_AcceptMenuRequest(t *testing.T) {
+	t.Run("test it accepts a menu request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(``)}
+		am.handlers[cmdactionmenu.AcceptMenuRequest] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{
+	"piid": "a13832dc-88b8-4714-b697-e5410d23abe2",
+	"menu": {"title": "Welcome"}
+}`)}
+		resp := am.AcceptMenuRequest(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "", string(resp.Payload))
+	})
+}
+
+func TestActionMenu_DeclineMenuRequest(t *testing.T) {
+	t.Run("test it declines a menu request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(``)}
+		am.handlers[cmdactionmenu.DeclineMenuRequest] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{
+	"piid": "a13832dc-88b8-4714-b697-e5410d23abe2",
+	"reason": "no menu"
+}`)}
+		resp := am.DeclineMenuRequest(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "", string(resp.Payload))
+	})
+}
+
+func TestActionMenu_AcceptPerform(t *testing.T) {
+	t.Run("test it accepts a perform request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(``)}
+		am.handlers[cmdactionmenu.AcceptPerform] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"piid": "a13832dc-88b8-4714-b697-e5410d23abe2"}`)}
+		resp := am.AcceptPerform(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "", string(resp.Payload))
+	})
+}
+
+func TestActionMenu_DeclinePerform(t *testing.T) {
+	t.Run("test it declines a perform request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(``)}
+		am.handlers[cmdactionmenu.DeclinePerform] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{
+	"piid": "a13832dc-88b8-4714-b697-e5410d23abe2",
+	"reason": "not allowed"
+}`)}
+		resp := am.DeclinePerform(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "", string(resp.Payload))
+	})
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/controller"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
@@ -322,3 +324,23 @@ func (a *Aries) GetKMSController() (api.KMSController, error) {
 
 	return &KMS{handlers: handlers}, nil
 }
+
+// GetActionMenuController returns an ActionMenu instance.
+func (a *Aries) GetActionMenuController() (api.ActionMenuController, error) {
+	handlers, ok := a.handlers[actionmenu.CommandName]
+	if !ok {
+		return nil, fmt.Errorf("no handlers found for controller [%s]", actionmenu.CommandName)
+	}
+
+	return &ActionMenu{handlers: handlers}, nil
+}
+
+// GetBasicMessageController returns a BasicMessage instance.
+func (a *Aries) GetBasicMessageController() (api.BasicMessageController, error) {
+	handlers, ok := a.handlers[basicmessage.CommandName]
+	if !ok {
+		return nil, fmt.Errorf("no handlers found for controller [%s]", basicmessage.CommandName)
+	}
+
+	return &BasicMessage{handlers: handlers}, nil
+}
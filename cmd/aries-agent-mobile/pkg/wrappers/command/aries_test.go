@@ -207,3 +207,29 @@ func TestAries_GetKMSController(t *testing.T) {
 		require.NotNil(t, controller)
 	})
 }
+
+func TestAries_GetActionMenuController(t *testing.T) {
+	t.Run("it creates a controller", func(t *testing.T) {
+		opts := &config.Options{}
+		a, err := NewAries(opts)
+		require.NoError(t, err)
+		require.NotNil(t, a)
+
+		controller, err := a.GetActionMenuController()
+		require.NoError(t, err)
+		require.NotNil(t, controller)
+	})
+}
+
+func TestAries_GetBasicMessageController(t *testing.T) {
+	t.Run("it creates a controller", func(t *testing.T) {
+		opts := &config.Options{}
+		a, err := NewAries(opts)
+		require.NoError(t, err)
+		require.NotNil(t, a)
+
+		controller, err := a.GetBasicMessageController()
+		require.NoError(t, err)
+		require.NotNil(t, controller)
+	})
+}
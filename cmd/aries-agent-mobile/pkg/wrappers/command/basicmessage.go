@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	cmdbasicmessage "github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
+)
+
+// BasicMessage contains handler function for basic message protocol commands.
+type BasicMessage struct {
+	handlers map[string]command.Exec
+}
+
+// SendMessage sends the basic message to the agent on the other end of the connection.
+func (b *BasicMessage) SendMessage(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdbasicmessage.SendMessageArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(b.handlers[cmdbasicmessage.SendMessage], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// History returns a page of the message history.
+func (b *BasicMessage) History(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdbasicmessage.HistoryArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(b.handlers[cmdbasicmessage.History], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DeleteHistory deletes the message history of the connection.
+func (b *BasicMessage) DeleteHistory(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdbasicmessage.DeleteHistoryArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(b.handlers[cmdbasicmessage.DeleteHistory], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	cmdbasicmessage "github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
+)
+
+func getBasicMessageController(t *testing.T) *BasicMessage {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	bmc, err := a.GetBasicMessageController()
+	require.NoError(t, err)
+	require.NotNil(t, bmc)
+
+	bm, ok := bmc.(*BasicMessage)
+	require.Equal(t, ok, true)
+
+	return bm
+}
+
+func TestBasicMessage_SendMessage(t *testing.T) {
+	t.Run("test it sends a message", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		bm.handlers[cmdbasicmessage.SendMessage] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"conn","content":"Hello","locale":"en"}`)}
+		resp := bm.SendMessage(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+
+	t.Run("test it fails with invalid payload", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		resp := bm.SendMessage(&models.RequestEnvelope{Payload: []byte(`{`)})
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+	})
+}
+
+func TestBasicMessage_History(t *testing.T) {
+	t.Run("test it returns the message history", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		mockResponse := `{"messages":[{"message_id":"msg-1","connection_id":"conn","direction":"sent"}]}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		bm.handlers[cmdbasicmessage.History] = fakeHandler.exec
+
+		resp := bm.History(&models.RequestEnvelope{Payload: []byte(`{"connectionID":"conn","limit":10}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+
+	t.Run("test it fails with invalid payload", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		resp := bm.History(&models.RequestEnvelope{Payload: []byte(`{`)})
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+	})
+}
+
+func TestBasicMessage_DeleteHistory(t *testing.T) {
+	t.Run("test it deletes the message history", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(``)}
+		bm.handlers[cmdbasicmessage.DeleteHistory] = fakeHandler.exec
+
+		resp := bm.DeleteHistory(&models.RequestEnvelope{Payload: []byte(`{"connectionID":"conn"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+	})
+
+	t.Run("test it fails with invalid payload", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		resp := bm.DeleteHistory(&models.RequestEnvelope{Payload: []byte(`{`)})
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+	})
+}
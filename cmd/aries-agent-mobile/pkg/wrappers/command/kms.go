@@ -44,3 +44,51 @@ func (k *KMS) ImportKey(request *models.RequestEnvelope) *models.ResponseEnvelop
 
 	return &models.ResponseEnvelope{Payload: response}
 }
+
+// GetKeyMetadata returns the metadata of a key set along with its versions.
+func (k *KMS) GetKeyMetadata(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := kms.GetKeyMetadataRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(k.handlers[kms.GetKeyMetadataCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ListKeys returns the metadata of the keys filtered by key type and creation time.
+func (k *KMS) ListKeys(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := kms.ListKeysRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(k.handlers[kms.ListKeysCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DeleteKey deletes a key set along with all its versions.
+func (k *KMS) DeleteKey(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := kms.DeleteKeyRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(k.handlers[kms.DeleteKeyCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
@@ -72,3 +72,64 @@ func TestKMS_ImportKey(t *testing.T) {
 			string(resp.Payload))
 	})
 }
+
+func TestKMS_GetKeyMetadata(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getKMSController(t)
+
+		mockResponse := `{"keyID":"keyID","keyType":"ED25519","created":"2020-10-01T00:00:00Z",` +
+			`"versions":[{"version":1,"primary":true,"status":"enabled","created":"2020-10-01T00:00:00Z"}]}`
+
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		controller.handlers[kms.GetKeyMetadataCommandMethod] = fakeHandler.exec
+
+		payload := `{"keyID":"keyID"}`
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := controller.GetKeyMetadata(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t,
+			mockResponse,
+			string(resp.Payload))
+	})
+}
+
+func TestKMS_ListKeys(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getKMSController(t)
+
+		mockResponse := `{"keys":[{"keyID":"keyID","keyType":"ED25519","created":"2020-10-01T00:00:00Z",` +
+			`"versions":[{"version":1,"primary":true,"status":"enabled","created":"2020-10-01T00:00:00Z"}]}]}`
+
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		controller.handlers[kms.ListKeysCommandMethod] = fakeHandler.exec
+
+		payload := `{"keyType":"ED25519","createdAfter":"2020-09-01T00:00:00Z"}`
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := controller.ListKeys(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t,
+			mockResponse,
+			string(resp.Payload))
+	})
+}
+
+func TestKMS_DeleteKey(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getKMSController(t)
+
+		fakeHandler := mockCommandRunner{data: []byte("")}
+		controller.handlers[kms.DeleteKeyCommandMethod] = fakeHandler.exec
+
+		payload := `{"keyID":"keyID"}`
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := controller.DeleteKey(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "", string(resp.Payload))
+	})
+}
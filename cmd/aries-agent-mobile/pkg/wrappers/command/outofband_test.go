@@ -143,13 +143,13 @@ func TestOutOfBand_CreateInvitation(t *testing.T) {
 		controller := getOutOfBandController(t)
 
 		mockResponse := `{"invitation":{"@id":"2429a5d3-c500-4647-9bb5-e34207bce406",
-"@type":"https://didcomm.org/oob-invitation/1.0/invitation","label":"label","goal":"goal",
-"goal-code":"goal_code","service":["s1"],"protocols":["s1"]}}
+"@type":"https://didcomm.org/out-of-band/1.0/invitation","label":"label","goal":"goal",
+"goal_code":"goal_code","handshake_protocols":["s1"],"service":["s1"]}}
 `
 		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
 		controller.handlers[outofband.CreateInvitation] = fakeHandler.exec
 
-		payload := `{"label":"label","goal":"goal","goal_code":"goal_code","service":["s1"],"protocols":["s1"]}`
+		payload := `{"label":"label","goal":"goal","goal_code":"goal_code","service":["s1"],"handshake_protocols":["s1"]}`
 
 		req := &models.RequestEnvelope{Payload: []byte(payload)}
 		resp := controller.CreateInvitation(req)
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+)
+
+// ActionMenu contains necessary fields for each of its operations.
+type ActionMenu struct {
+	httpClient httpClient
+	endpoints  map[string]*endpoint
+
+	URL   string
+	Token string
+}
+
+// Actions returns unfinished actions for the async usage (via HTTP).
+func (am *ActionMenu) Actions(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.Actions)
+}
+
+// SendMenu sends the menu to the agent on the other end of the connection (via HTTP).
+func (am *ActionMenu) SendMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.SendMenu)
+}
+
+// RequestMenu asks the agent on the other end of the connection to send its menu (via HTTP).
+func (am *ActionMenu) RequestMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.RequestMenu)
+}
+
+// ActiveMenu returns the last menu received over the connection (via HTTP).
+func (am *ActionMenu) ActiveMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.ActiveMenu)
+}
+
+// Perform asks the agent on the other end of the connection to perform the option of the active menu (via HTTP).
+func (am *ActionMenu) Perform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.Perform)
+}
+
+// AcceptMenuRequest responds to the menu request with the menu (via HTTP).
+func (am *ActionMenu) AcceptMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.AcceptMenuRequest)
+}
+
+// DeclineMenuRequest is used to reject the menu request (via HTTP).
+func (am *ActionMenu) DeclineMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.DeclineMenuRequest)
+}
+
+// AcceptPerform acknowledges that the menu option was performed (via HTTP).
+func (am *ActionMenu) AcceptPerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.AcceptPerform)
+}
+
+// DeclinePerform is used to reject the perform request (via HTTP).
+func (am *ActionMenu) DeclinePerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return am.createRespEnvelope(request, cmdactionmenu.DeclinePerform)
+}
+
+func (am *ActionMenu) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
+	return exec(&restOperation{
+		url:        am.URL,
+		token:      am.Token,
+		httpClient: am.httpClient,
+		endpoint:   am.endpoints[endpoint],
+		request:    request,
+	})
+}
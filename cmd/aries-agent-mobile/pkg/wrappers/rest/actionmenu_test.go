@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	opactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
+)
+
+func getActionMenuController(t *testing.T) *ActionMenu {
+	a, err := getAgent()
+	require.NoError(t, err)
+	require.NotNil(t, a)
+
+	amc, err := a.GetActionMenuController()
+	require.NoError(t, err)
+	require.NotNil(t, amc)
+
+	am, ok := amc.(*ActionMenu)
+	require.Equal(t, ok, true)
+
+	return am
+}
+
+func TestActionMenu_Actions(t *testing.T) {
+	t.Run("test it performs an actions request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"actions":[{"PIID":"ID1","ConnectionID":"conn","Msg":null,"MyDID":"","TheirDID":""}]}`
+		am.httpClient = &mockHTTPClient{data: mockResponse, method: http.MethodGet, url: mockAgentURL + opactionmenu.Actions}
+
+		resp := am.Actions(&models.RequestEnvelope{})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_SendMenu(t *testing.T) {
+	t.Run("test it sends a menu", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		am.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + opactionmenu.SendMenu,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID": "conn", "menu": {"title": "Welcome"}}`)}
+		resp := am.SendMenu(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_RequestMenu(t *testing.T) {
+	t.Run("test it requests a menu", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		am.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + opactionmenu.RequestMenu,
+		}
+
+		resp := am.RequestMenu(&models.RequestEnvelope{Payload: []byte(`{"connectionID": "conn"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_ActiveMenu(t *testing.T) {
+	t.Run("test it returns the active menu", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"menu":{"title":"Welcome"}}`
+		am.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + opactionmenu.ActiveMenu,
+		}
+
+		resp := am.ActiveMenu(&models.RequestEnvelope{Payload: []byte(`{"connectionID": "conn"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_Perform(t *testing.T) {
+	t.Run("test it performs a menu option", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		am.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + opactionmenu.Perform,
+		}
+
+		resp := am.Perform(&models.RequestEnvelope{Payload: []byte(`{"connectionID": "conn", "name": "balance"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_AcceptMenuRequest(t *testing.T) {
+	t.Run("test it accepts a menu request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		reqData := `{"piid": "a13832dc-88b8-4714-b697-e5410d23abe2", "menu": {"title": "Welcome"}}`
+
+		mockURL, err := parseURL(mockAgentURL, opactionmenu.AcceptMenuRequest, reqData)
+		require.NoError(t, err, "failed to parse test url")
+
+		am.httpClient = &mockHTTPClient{data: emptyJSON, method: http.MethodPost, url: mockURL}
+
+		resp := am.AcceptMenuRequest(&models.RequestEnvelope{Payload: []byte(reqData)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, emptyJSON, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_DeclineMenuRequest(t *testing.T) {
+	t.Run("test it declines a menu request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		reqData := `{"piid": "a13832dc-88b8-4714-b697-e5410d23abe2", "reason": "no menu"}`
+
+		mockURL, err := parseURL(mockAgentURL, opactionmenu.DeclineMenuRequest, reqData)
+		require.NoError(t, err, "failed to parse test url")
+
+		am.httpClient = &mockHTTPClient{data: emptyJSON, method: http.MethodPost, url: mockURL}
+
+		resp := am.DeclineMenuRequest(&models.RequestEnvelope{Payload: []byte(reqData)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, emptyJSON, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_AcceptPerform(t *testing.T) {
+	t.Run("test it accepts a perform request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		reqData := `{"piid": "a13832dc-88b8-4714-b697-e5410d23abe2"}`
+
+		mockURL, err := parseURL(mockAgentURL, opactionmenu.AcceptPerform, reqData)
+		require.NoError(t, err, "failed to parse test url")
+
+		am.httpClient = &mockHTTPClient{data: emptyJSON, method: http.MethodPost, url: mockURL}
+
+		resp := am.AcceptPerform(&models.RequestEnvelope{Payload: []byte(reqData)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, emptyJSON, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_DeclinePerform(t *testing.T) {
+	t.Run("test it declines a perform request", func(t *testing.T) {
+		am := getActionMenuController(t)
+
+		reqData := `{"piid": "a13832dc-88b8-4714-b697-e5410d23abe2", "reason": "not allowed"}`
+
+		mockURL, err := parseURL(mockAgentURL, opactionmenu.DeclinePerform, reqData)
+		require.NoError(t, err, "failed to parse test url")
+
+		am.httpClient = &mockHTTPClient{data: emptyJSON, method: http.MethodPost, url: mockURL}
+
+		resp := am.DeclinePerform(&models.RequestEnvelope{Payload: []byte(reqData)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, emptyJSON, string(resp.Payload))
+	})
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/api"
 	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/config"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/basicmessage"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
@@ -266,3 +268,23 @@ func (ar *Aries) GetKMSController() (api.KMSController, error) {
 
 	return &KMS{endpoints: endpoints, URL: ar.URL, Token: ar.Token, httpClient: &http.Client{}}, nil
 }
+
+// GetActionMenuController returns an ActionMenu instance.
+func (ar *Aries) GetActionMenuController() (api.ActionMenuController, error) {
+	endpoints, ok := ar.endpoints[actionmenu.OperationID]
+	if !ok {
+		return nil, fmt.Errorf("no endpoints found for controller [%s]", actionmenu.OperationID)
+	}
+
+	return &ActionMenu{endpoints: endpoints, URL: ar.URL, Token: ar.Token, httpClient: &http.Client{}}, nil
+}
+
+// GetBasicMessageController returns a BasicMessage instance.
+func (ar *Aries) GetBasicMessageController() (api.BasicMessageController, error) {
+	endpoints, ok := ar.endpoints[basicmessage.OperationID]
+	if !ok {
+		return nil, fmt.Errorf("no endpoints found for controller [%s]", basicmessage.OperationID)
+	}
+
+	return &BasicMessage{endpoints: endpoints, URL: ar.URL, Token: ar.Token, httpClient: &http.Client{}}, nil
+}
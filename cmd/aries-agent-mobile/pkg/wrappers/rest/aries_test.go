@@ -236,3 +236,27 @@ func TestAries_GetKMSController(t *testing.T) {
 		require.NotNil(t, controller)
 	})
 }
+
+func TestAries_GetActionMenuController(t *testing.T) {
+	t.Run("it creates a controller", func(t *testing.T) {
+		a, err := NewAries(&config.Options{AgentURL: mockAgentURL})
+		require.NoError(t, err)
+		require.NotNil(t, a)
+
+		controller, err := a.GetActionMenuController()
+		require.NoError(t, err)
+		require.NotNil(t, controller)
+	})
+}
+
+func TestAries_GetBasicMessageController(t *testing.T) {
+	t.Run("it creates a controller", func(t *testing.T) {
+		a, err := NewAries(&config.Options{AgentURL: mockAgentURL})
+		require.NoError(t, err)
+		require.NotNil(t, a)
+
+		controller, err := a.GetBasicMessageController()
+		require.NoError(t, err)
+		require.NotNil(t, controller)
+	})
+}
@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	cmdbasicmessage "github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
+)
+
+// BasicMessage contains necessary fields for each of its operations.
+type BasicMessage struct {
+	httpClient httpClient
+	endpoints  map[string]*endpoint
+
+	URL   string
+	Token string
+}
+
+// SendMessage sends the basic message to the agent on the other end of the connection (via HTTP).
+func (bm *BasicMessage) SendMessage(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return bm.createRespEnvelope(request, cmdbasicmessage.SendMessage)
+}
+
+// History returns a page of the message history (via HTTP).
+func (bm *BasicMessage) History(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return bm.createRespEnvelope(request, cmdbasicmessage.History)
+}
+
+// DeleteHistory deletes the message history of the connection (via HTTP).
+func (bm *BasicMessage) DeleteHistory(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return bm.createRespEnvelope(request, cmdbasicmessage.DeleteHistory)
+}
+
+func (bm *BasicMessage) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
+	return exec(&restOperation{
+		url:        bm.URL,
+		token:      bm.Token,
+		httpClient: bm.httpClient,
+		endpoint:   bm.endpoints[endpoint],
+		request:    request,
+	})
+}
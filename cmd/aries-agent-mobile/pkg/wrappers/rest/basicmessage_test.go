@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	opbasicmessage "github.com/hyperledger/aries-framework-go/pkg/controller/rest/basicmessage"
+)
+
+func getBasicMessageController(t *testing.T) *BasicMessage {
+	a, err := getAgent()
+	require.NoError(t, err)
+	require.NotNil(t, a)
+
+	bmc, err := a.GetBasicMessageController()
+	require.NoError(t, err)
+	require.NotNil(t, bmc)
+
+	bm, ok := bmc.(*BasicMessage)
+	require.Equal(t, ok, true)
+
+	return bm
+}
+
+func TestBasicMessage_SendMessage(t *testing.T) {
+	t.Run("test it sends a message", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		mockResponse := `{"id":"a13832dc-88b8-4714-b697-e5410d23abe2"}`
+		bm.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + opbasicmessage.SendMessage,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"conn","content":"Hello"}`)}
+		resp := bm.SendMessage(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestBasicMessage_History(t *testing.T) {
+	t.Run("test it returns the message history", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		mockResponse := `{"messages":[{"message_id":"msg-1","connection_id":"conn","direction":"sent"}]}`
+		bm.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodGet, url: mockAgentURL + opbasicmessage.History,
+		}
+
+		resp := bm.History(&models.RequestEnvelope{})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestBasicMessage_DeleteHistory(t *testing.T) {
+	t.Run("test it deletes the message history", func(t *testing.T) {
+		bm := getBasicMessageController(t)
+
+		bm.httpClient = &mockHTTPClient{
+			data:   ``,
+			method: http.MethodDelete, url: mockAgentURL + "/basicmessage/history/conn",
+		}
+
+		resp := bm.DeleteHistory(&models.RequestEnvelope{Payload: []byte(`{"connectionID":"conn"}`)})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+	})
+}
@@ -9,6 +9,8 @@ package rest
 import (
 	"net/http"
 
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	cmdbasicmessage "github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
 	cmddidexch "github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	cmdintroduce "github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	cmdisscred "github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
@@ -19,6 +21,8 @@ import (
 	cmdpresproof "github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
 	cmdvdr "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	cmdverifiable "github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
+	opactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
+	opbasicmessage "github.com/hyperledger/aries-framework-go/pkg/controller/rest/basicmessage"
 	opdidexch "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	opintroduce "github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	opisscred "github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
@@ -50,6 +54,8 @@ func getControllerEndpoints() map[string]map[string]*endpoint {
 	allEndpoints[opmessaging.MsgServiceOperationID] = getMessagingEndpoints()
 	allEndpoints[opoob.OperationID] = getOutOfBandEndpoints()
 	allEndpoints[opkms.KmsOperationID] = getKMSEndpoints()
+	allEndpoints[opactionmenu.OperationID] = getActionMenuEndpoints()
+	allEndpoints[opbasicmessage.OperationID] = getBasicMessageEndpoints()
 
 	return allEndpoints
 }
@@ -433,5 +439,75 @@ func getKMSEndpoints() map[string]*endpoint {
 			Path:   opkms.ImportKeyPath,
 			Method: http.MethodPost,
 		},
+		cmdkms.GetKeyMetadataCommandMethod: {
+			Path:   opkms.GetKeyMetadataPath,
+			Method: http.MethodGet,
+		},
+		cmdkms.ListKeysCommandMethod: {
+			Path:   opkms.ListKeysPath,
+			Method: http.MethodGet,
+		},
+		cmdkms.DeleteKeyCommandMethod: {
+			Path:   opkms.DeleteKeyPath,
+			Method: http.MethodDelete,
+		},
+	}
+}
+
+func getActionMenuEndpoints() map[string]*endpoint {
+	return map[string]*endpoint{
+		cmdactionmenu.Actions: {
+			Path:   opactionmenu.Actions,
+			Method: http.MethodGet,
+		},
+		cmdactionmenu.SendMenu: {
+			Path:   opactionmenu.SendMenu,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.RequestMenu: {
+			Path:   opactionmenu.RequestMenu,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.ActiveMenu: {
+			Path:   opactionmenu.ActiveMenu,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.Perform: {
+			Path:   opactionmenu.Perform,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.AcceptMenuRequest: {
+			Path:   opactionmenu.AcceptMenuRequest,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.DeclineMenuRequest: {
+			Path:   opactionmenu.DeclineMenuRequest,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.AcceptPerform: {
+			Path:   opactionmenu.AcceptPerform,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.DeclinePerform: {
+			Path:   opactionmenu.DeclinePerform,
+			Method: http.MethodPost,
+		},
+	}
+}
+
+func getBasicMessageEndpoints() map[string]*endpoint {
+	return map[string]*endpoint{
+		cmdbasicmessage.SendMessage: {
+			Path:   opbasicmessage.SendMessage,
+			Method: http.MethodPost,
+		},
+		cmdbasicmessage.History: {
+			Path:   opbasicmessage.History,
+			Method: http.MethodGet,
+		},
+		cmdbasicmessage.DeleteHistory: {
+			Path:   opbasicmessage.DeleteHistory,
+			Method: http.MethodDelete,
+		},
 	}
 }
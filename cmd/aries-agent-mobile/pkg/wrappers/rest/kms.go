@@ -30,6 +30,21 @@ func (k *KMS) ImportKey(request *models.RequestEnvelope) *models.ResponseEnvelop
 	return k.createRespEnvelope(request, kms.ImportKeyCommandMethod)
 }
 
+// GetKeyMetadata returns the metadata of a key set along with its versions.
+func (k *KMS) GetKeyMetadata(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return k.createRespEnvelope(request, kms.GetKeyMetadataCommandMethod)
+}
+
+// ListKeys returns the metadata of the keys filtered by key type and creation time.
+func (k *KMS) ListKeys(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return k.createRespEnvelope(request, kms.ListKeysCommandMethod)
+}
+
+// DeleteKey deletes a key set along with all its versions.
+func (k *KMS) DeleteKey(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return k.createRespEnvelope(request, kms.DeleteKeyCommandMethod)
+}
+
 func (k *KMS) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
 	return exec(&restOperation{
 		url:        k.URL,
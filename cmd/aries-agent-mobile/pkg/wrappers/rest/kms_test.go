@@ -73,3 +73,68 @@ func TestKMS_ImportKey(t *testing.T) {
 		require.Equal(t, mockResponse, string(resp.Payload))
 	})
 }
+
+func TestKMS_GetKeyMetadata(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getKMSController(t)
+
+		reqData := `{"keyID":"keyID"}`
+		mockResponse := `{"keyID":"keyID","keyType":"ED25519","created":"2020-10-01T00:00:00Z",` +
+			`"versions":[{"version":1,"primary":true,"status":"enabled","created":"2020-10-01T00:00:00Z"}]}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodGet, url: mockAgentURL + "/kms/keyset/keyID/metadata",
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := controller.GetKeyMetadata(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestKMS_ListKeys(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getKMSController(t)
+
+		reqData := `{"keyType":"ED25519"}`
+		mockResponse := `{"keys":[{"keyID":"keyID","keyType":"ED25519","created":"2020-10-01T00:00:00Z",` +
+			`"versions":[{"version":1,"primary":true,"status":"enabled","created":"2020-10-01T00:00:00Z"}]}]}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodGet, url: mockAgentURL + "/kms/keys",
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := controller.ListKeys(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestKMS_DeleteKey(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getKMSController(t)
+
+		reqData := `{"keyID":"keyID"}`
+		mockResponse := emptyJSON
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodDelete, url: mockAgentURL + "/kms/keyset/keyID",
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := controller.DeleteKey(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
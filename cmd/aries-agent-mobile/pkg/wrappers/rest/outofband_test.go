@@ -154,10 +154,10 @@ func TestOutOfBand_CreateInvitation(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		controller := getOutOfBandController(t)
 
-		reqData := `{"label":"label","goal":"goal","goal_code":"goal_code","service":["s1"],"protocols":["s1"]}`
+		reqData := `{"label":"label","goal":"goal","goal_code":"goal_code","service":["s1"],"handshake_protocols":["s1"]}`
 		mockResponse := `{"invitation":{"@id":"2429a5d3-c500-4647-9bb5-e34207bce406",
-"@type":"https://didcomm.org/oob-invitation/1.0/invitation","label":"label","goal":"goal",
-"goal-code":"goal_code","service":["s1"],"protocols":["s1"]}}
+"@type":"https://didcomm.org/out-of-band/1.0/invitation","label":"label","goal":"goal",
+"goal_code":"goal_code","handshake_protocols":["s1"],"service":["s1"]}}
 `
 
 		controller.httpClient = &mockHTTPClient{
@@ -93,6 +93,32 @@ const (
 		" Possible values [INFO] [DEBUG] [ERROR] [WARNING] [CRITICAL] . Defaults to INFO if not set." +
 		" Alternatively, this can be set with the following environment variable: " + agentLogLevelEnvKey
 
+	// module log level.
+	agentLogModuleLevelFlagName  = "log-module-level"
+	agentLogModuleLevelEnvKey    = "ARIESD_LOG_MODULE_LEVEL"
+	agentLogModuleLevelFlagUsage = "Log level of a single module, overrides log-level for that module." +
+		" Values should be in `module=LEVEL` format." +
+		" This flag can be repeated, allowing to configure multiple modules." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentLogModuleLevelEnvKey
+
+	// log format.
+	agentLogFormatFlagName  = "log-format"
+	agentLogFormatEnvKey    = "ARIESD_LOG_FORMAT"
+	agentLogFormatFlagUsage = "Log format." +
+		" Possible values [text] [json] [logfmt]. Defaults to text if not set." +
+		" Alternatively, this can be set with the following environment variable: " + agentLogFormatEnvKey
+
+	// log debug sampling.
+	agentLogDebugSamplingFlagName  = "log-debug-sampling"
+	agentLogDebugSamplingEnvKey    = "ARIESD_LOG_DEBUG_SAMPLING"
+	agentLogDebugSamplingFlagUsage = "Limits DEBUG lines of a noisy module. Values should be in" +
+		" `module=first:thereafter` format: every second the first lines of the module are logged," +
+		" after that only every thereafter-th line is logged. Supported by json and logfmt log formats only." +
+		" This flag can be repeated, allowing to configure multiple modules." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentLogDebugSamplingEnvKey
+
 	// http resolver url flag.
 	agentHTTPResolverFlagName      = "http-resolver-url"
 	agentHTTPResolverEnvKey        = "ARIESD_HTTP_RESOLVER"
@@ -159,6 +185,8 @@ const (
 	httpProtocol      = "http"
 	websocketProtocol = "ws"
 
+	logFormatText = "text"
+
 	databaseTypeMemOption     = "mem"
 	databaseTypeLevelDBOption = "leveldb"
 )
@@ -227,13 +255,7 @@ func createStartCMD(server server) *cobra.Command { //nolint: funlen, gocyclo
 		Short: "Start an agent",
 		Long:  `Start an Aries agent controller`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// log level
-			logLevel, err := getUserSetVar(cmd, agentLogLevelFlagName, agentLogLevelEnvKey, true)
-			if err != nil {
-				return err
-			}
-
-			err = setLogLevel(logLevel)
+			err := setLogging(cmd)
 			if err != nil {
 				return err
 			}
@@ -402,6 +424,15 @@ func createFlags(startCmd *cobra.Command) {
 	// log level
 	startCmd.Flags().StringP(agentLogLevelFlagName, "", "", agentLogLevelFlagUsage)
 
+	// module log level
+	startCmd.Flags().StringSliceP(agentLogModuleLevelFlagName, "", []string{}, agentLogModuleLevelFlagUsage)
+
+	// log format
+	startCmd.Flags().StringP(agentLogFormatFlagName, "", "", agentLogFormatFlagUsage)
+
+	// log debug sampling
+	startCmd.Flags().StringSliceP(agentLogDebugSamplingFlagName, "", []string{}, agentLogDebugSamplingFlagUsage)
+
 	// http resolver url flag
 	startCmd.Flags().StringSliceP(agentHTTPResolverFlagName, agentHTTPResolverFlagShorthand, []string{},
 		agentHTTPResolverFlagUsage)
@@ -576,6 +607,121 @@ func getInboundSchemeToURLMap(schemeHostStr []string) (map[string]string, error)
 	return schemeHostMap, nil
 }
 
+func setLogging(cmd *cobra.Command) error {
+	logFormat, err := getUserSetVar(cmd, agentLogFormatFlagName, agentLogFormatEnvKey, true)
+	if err != nil {
+		return err
+	}
+
+	debugSamplings, err := getUserSetVars(cmd, agentLogDebugSamplingFlagName, agentLogDebugSamplingEnvKey, true)
+	if err != nil {
+		return err
+	}
+
+	// the provider needs to be initialized before the first log line
+	err = setLogFormat(logFormat, debugSamplings)
+	if err != nil {
+		return err
+	}
+
+	logLevel, err := getUserSetVar(cmd, agentLogLevelFlagName, agentLogLevelEnvKey, true)
+	if err != nil {
+		return err
+	}
+
+	err = setLogLevel(logLevel)
+	if err != nil {
+		return err
+	}
+
+	moduleLogLevels, err := getUserSetVars(cmd, agentLogModuleLevelFlagName, agentLogModuleLevelEnvKey, true)
+	if err != nil {
+		return err
+	}
+
+	return setModuleLogLevels(moduleLogLevels)
+}
+
+func setLogFormat(logFormat string, debugSamplings []string) error {
+	if logFormat == "" || strings.EqualFold(logFormat, logFormatText) {
+		if len(debugSamplings) > 0 {
+			return errors.New("log debug sampling is supported by json and logfmt log formats only")
+		}
+
+		return nil
+	}
+
+	encoding, err := log.ParseEncoding(logFormat)
+	if err != nil {
+		return fmt.Errorf("failed to parse log format '%s' : %w", logFormat, err)
+	}
+
+	opts := []log.StructuredProviderOpt{log.WithEncoding(encoding)}
+
+	for _, debugSampling := range debugSamplings {
+		opt, err := parseDebugSampling(debugSampling)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, opt)
+	}
+
+	log.Initialize(log.NewStructuredProvider(opts...))
+
+	return nil
+}
+
+// parseDebugSampling parses `module=first:thereafter` option.
+func parseDebugSampling(debugSampling string) (log.StructuredProviderOpt, error) {
+	const validSliceLen = 2
+
+	moduleSampling := strings.Split(debugSampling, "=")
+	if len(moduleSampling) != validSliceLen || moduleSampling[0] == "" {
+		return nil, fmt.Errorf("invalid log debug sampling '%s': use module=first:thereafter", debugSampling)
+	}
+
+	sampling := strings.Split(moduleSampling[1], ":")
+	if len(sampling) != validSliceLen {
+		return nil, fmt.Errorf("invalid log debug sampling '%s': use module=first:thereafter", debugSampling)
+	}
+
+	first, err := strconv.Atoi(sampling[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid log debug sampling '%s' : %w", debugSampling, err)
+	}
+
+	thereafter, err := strconv.Atoi(sampling[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid log debug sampling '%s' : %w", debugSampling, err)
+	}
+
+	return log.WithDebugSampling(moduleSampling[0], first, thereafter, time.Second), nil
+}
+
+// setModuleLogLevels sets log levels from `module=LEVEL` options.
+func setModuleLogLevels(moduleLogLevels []string) error {
+	const validSliceLen = 2
+
+	for _, moduleLogLevel := range moduleLogLevels {
+		moduleLevel := strings.Split(moduleLogLevel, "=")
+		if len(moduleLevel) != validSliceLen || moduleLevel[0] == "" {
+			return fmt.Errorf("invalid module log level '%s': use module=LEVEL", moduleLogLevel)
+		}
+
+		level, err := log.ParseLevel(moduleLevel[1])
+		if err != nil {
+			return fmt.Errorf("failed to parse log level '%s' : %w", moduleLogLevel, err)
+		}
+
+		log.SetLevel(moduleLevel[0], level)
+
+		logger.Infof("logger level of module %s set to %s", moduleLevel[0], moduleLevel[1])
+	}
+
+	return nil
+}
+
 func setLogLevel(logLevel string) error {
 	if logLevel != "" {
 		level, err := log.ParseLevel(logLevel)
@@ -431,6 +431,88 @@ func TestStartCmdWithLogLevel(t *testing.T) {
 	})
 }
 
+func TestStartCmdWithLogFormat(t *testing.T) {
+	t.Run("start with log format, module levels and debug sampling - success", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		args := []string{
+			"--" + agentHostFlagName,
+			randomURL(),
+			"--" + agentInboundHostFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + databaseTypeFlagName,
+			databaseTypeMemOption,
+			"--" + agentAutoAcceptFlagName,
+			"true",
+			"--" + agentLogFormatFlagName,
+			"json",
+			"--" + agentLogModuleLevelFlagName,
+			"sample-module-a=DEBUG",
+			"--" + agentLogModuleLevelFlagName,
+			"sample-module-b=ERROR",
+			"--" + agentLogDebugSamplingFlagName,
+			"sample-module-a=10:100",
+		}
+		startCmd.SetArgs(args)
+
+		err = startCmd.Execute()
+		require.NoError(t, err)
+		require.Equal(t, log.DEBUG, log.GetLevel("sample-module-a"))
+		require.Equal(t, log.ERROR, log.GetLevel("sample-module-b"))
+	})
+
+	t.Run("start with log format - invalid", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs([]string{"--" + agentLogFormatFlagName, "xml"})
+
+		err = startCmd.Execute()
+		require.EqualError(t, err, "failed to parse log format 'xml' : logger: invalid encoding 'xml'")
+	})
+
+	t.Run("start with module log level - invalid", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs([]string{"--" + agentLogModuleLevelFlagName, "sample-module=INVALID"})
+
+		err = startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid log level")
+	})
+
+	t.Run("validate log format", func(t *testing.T) {
+		require.NoError(t, setLogFormat("", nil))
+		require.NoError(t, setLogFormat("TEXT", nil))
+		require.EqualError(t, setLogFormat("text", []string{"module=1:1"}),
+			"log debug sampling is supported by json and logfmt log formats only")
+		require.NoError(t, setLogFormat("logfmt", []string{"module=1:1"}))
+		require.Error(t, setLogFormat("logfmt", []string{"module=1"}))
+	})
+
+	t.Run("validate debug sampling", func(t *testing.T) {
+		opt, err := parseDebugSampling("module=10:100")
+		require.NoError(t, err)
+		require.NotNil(t, opt)
+
+		for _, invalid := range []string{"module", "=1:1", "module=1", "module=a:1", "module=1:a"} {
+			_, err = parseDebugSampling(invalid)
+			require.Error(t, err, invalid)
+			require.Contains(t, err.Error(), "invalid log debug sampling")
+		}
+	})
+
+	t.Run("validate module log levels", func(t *testing.T) {
+		require.NoError(t, setModuleLogLevels([]string{"sample-module-c=WARNING"}))
+		require.Equal(t, log.WARNING, log.GetLevel("sample-module-c"))
+
+		require.EqualError(t, setModuleLogLevels([]string{"WARNING"}),
+			"invalid module log level 'WARNING': use module=LEVEL")
+	})
+}
+
 func TestStartCmdWithoutWebhookURLAndAutoAccept(t *testing.T) {
 	startCmd, err := Cmd(&mockServer{})
 	require.NoError(t, err)
@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+)
+
+type (
+	// Menu presents the actions the requester may take.
+	Menu = actionmenu.Menu
+	// MenuOption is the action the requester may perform.
+	MenuOption = actionmenu.MenuOption
+	// Form describes the parameters the requester has to provide to perform the action.
+	Form = actionmenu.Form
+	// FormParam is the form parameter.
+	FormParam = actionmenu.FormParam
+	// Action contains helpful information about action.
+	Action = actionmenu.Action
+)
+
+// Provider contains dependencies for the action menu protocol and is typically created by using aries.Context().
+type Provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// ProtocolService defines the action menu service.
+type ProtocolService interface {
+	service.DIDComm
+	SendMenu(connectionID string, menu *Menu) (string, error)
+	RequestMenu(connectionID string) (string, error)
+	ActiveMenu(connectionID string) (*Menu, error)
+	Perform(connectionID, name string, params map[string]string) (string, error)
+	Actions() ([]Action, error)
+	ActionContinue(piID string, opt actionmenu.Opt) error
+	ActionStop(piID string, err error) error
+}
+
+// Client enables access to action menu API.
+type Client struct {
+	service.Event
+	service ProtocolService
+}
+
+// New returns new instance of action menu client.
+func New(ctx Provider) (*Client, error) {
+	svc, err := ctx.Service(actionmenu.ActionMenu)
+	if err != nil {
+		return nil, err
+	}
+
+	menuSvc, ok := svc.(ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to action menu service failed")
+	}
+
+	return &Client{
+		Event:   menuSvc,
+		service: menuSvc,
+	}, nil
+}
+
+// SendMenu sends the menu to the agent on the other end of the connection.
+func (c *Client) SendMenu(connectionID string, menu *Menu) (string, error) {
+	id, err := c.service.SendMenu(connectionID, menu)
+	if err != nil {
+		return "", fmt.Errorf("send menu: %w", err)
+	}
+
+	return id, nil
+}
+
+// RequestMenu asks the agent on the other end of the connection to send its menu.
+func (c *Client) RequestMenu(connectionID string) (string, error) {
+	id, err := c.service.RequestMenu(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("request menu: %w", err)
+	}
+
+	return id, nil
+}
+
+// ActiveMenu returns the last menu received over the connection.
+func (c *Client) ActiveMenu(connectionID string) (*Menu, error) {
+	menu, err := c.service.ActiveMenu(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("active menu: %w", err)
+	}
+
+	return menu, nil
+}
+
+// Perform asks the agent on the other end of the connection to perform the option of the active menu.
+func (c *Client) Perform(connectionID, name string, params map[string]string) (string, error) {
+	id, err := c.service.Perform(connectionID, name, params)
+	if err != nil {
+		return "", fmt.Errorf("perform: %w", err)
+	}
+
+	return id, nil
+}
+
+// Actions returns unfinished actions for the async usage.
+func (c *Client) Actions() ([]Action, error) {
+	return c.service.Actions()
+}
+
+// AcceptMenuRequest responds to the menu request with the menu.
+func (c *Client) AcceptMenuRequest(piID string, menu *Menu) error {
+	if menu == nil {
+		return errors.New("menu is required")
+	}
+
+	return c.service.ActionContinue(piID, WithMenu(menu))
+}
+
+// DeclineMenuRequest is used to reject the menu request.
+func (c *Client) DeclineMenuRequest(piID, reason string) error {
+	return c.service.ActionStop(piID, errors.New(reason))
+}
+
+// AcceptPerform acknowledges that the menu option was performed, the optional menu is sent as a response
+// (e.g when the menu changed as a result of the performed option).
+func (c *Client) AcceptPerform(piID string, menu *Menu) error {
+	var opt actionmenu.Opt
+
+	if menu != nil {
+		opt = WithMenu(menu)
+	}
+
+	return c.service.ActionContinue(piID, opt)
+}
+
+// DeclinePerform is used to reject the perform request.
+func (c *Client) DeclinePerform(piID, reason string) error {
+	return c.service.ActionStop(piID, errors.New(reason))
+}
+
+// WithMenu is used to respond to the action with the menu.
+// USAGE: event.Continue(WithMenu(menu)).
+func WithMenu(menu *Menu) actionmenu.Opt {
+	return actionmenu.WithMenu(menu)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	mockactionmenu "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/actionmenu"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+// Ensure Client can emit events.
+var _ service.Event = (*Client)(nil)
+
+// Ensure the service implements the client interface.
+var _ ProtocolService = (*actionmenu.Service)(nil)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, c)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: fmt.Errorf("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to action menu service failed")
+	})
+}
+
+func TestClient_Requester(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			RequestMenuFunc: func(connectionID string) (string, error) {
+				require.Equal(t, "conn", connectionID)
+
+				return "request-id", nil
+			},
+			ActiveMenuFunc: func(connectionID string) (*Menu, error) {
+				return &Menu{Title: "menu"}, nil
+			},
+			PerformFunc: func(connectionID, name string, params map[string]string) (string, error) {
+				require.Equal(t, "transfer", name)
+				require.Equal(t, map[string]string{"amount": "10"}, params)
+
+				return "perform-id", nil
+			},
+		}})
+		require.NoError(t, err)
+
+		id, err := c.RequestMenu("conn")
+		require.NoError(t, err)
+		require.Equal(t, "request-id", id)
+
+		menu, err := c.ActiveMenu("conn")
+		require.NoError(t, err)
+		require.Equal(t, "menu", menu.Title)
+
+		id, err = c.Perform("conn", "transfer", map[string]string{"amount": "10"})
+		require.NoError(t, err)
+		require.Equal(t, "perform-id", id)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			RequestMenuFunc: func(string) (string, error) {
+				return "", errors.New("request error")
+			},
+			ActiveMenuFunc: func(string) (*Menu, error) {
+				return nil, actionmenu.ErrNoActiveMenu
+			},
+			PerformFunc: func(string, string, map[string]string) (string, error) {
+				return "", errors.New("perform error")
+			},
+		}})
+		require.NoError(t, err)
+
+		_, err = c.RequestMenu("conn")
+		require.EqualError(t, err, "request menu: request error")
+
+		_, err = c.ActiveMenu("conn")
+		require.True(t, errors.Is(err, actionmenu.ErrNoActiveMenu))
+
+		_, err = c.Perform("conn", "transfer", nil)
+		require.EqualError(t, err, "perform: perform error")
+	})
+}
+
+func TestClient_Responder(t *testing.T) {
+	t.Run("send menu", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			SendMenuFunc: func(connectionID string, menu *Menu) (string, error) {
+				require.Equal(t, "conn", connectionID)
+				require.Equal(t, "menu", menu.Title)
+
+				return "menu-id", nil
+			},
+		}})
+		require.NoError(t, err)
+
+		id, err := c.SendMenu("conn", &Menu{Title: "menu"})
+		require.NoError(t, err)
+		require.Equal(t, "menu-id", id)
+	})
+
+	t.Run("send menu - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			SendMenuFunc: func(string, *Menu) (string, error) {
+				return "", errors.New("send error")
+			},
+		}})
+		require.NoError(t, err)
+
+		_, err = c.SendMenu("conn", &Menu{})
+		require.EqualError(t, err, "send menu: send error")
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		var (
+			continued []bool
+			stopped   []string
+		)
+
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			ActionsFunc: func() ([]Action, error) {
+				return []Action{{PIID: "piid"}}, nil
+			},
+			ActionContinueFunc: func(piID string, opt actionmenu.Opt) error {
+				require.Equal(t, "piid", piID)
+				continued = append(continued, opt != nil)
+
+				return nil
+			},
+			ActionStopFunc: func(piID string, err error) error {
+				stopped = append(stopped, err.Error())
+
+				return nil
+			},
+		}})
+		require.NoError(t, err)
+
+		actions, err := c.Actions()
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+
+		require.EqualError(t, c.AcceptMenuRequest("piid", nil), "menu is required")
+		require.NoError(t, c.AcceptMenuRequest("piid", &Menu{}))
+		require.NoError(t, c.AcceptPerform("piid", nil))
+		require.NoError(t, c.AcceptPerform("piid", &Menu{}))
+		require.NoError(t, c.DeclineMenuRequest("piid", "reason 1"))
+		require.NoError(t, c.DeclinePerform("piid", "reason 2"))
+
+		require.Equal(t, []bool{true, false, true}, continued)
+		require.Equal(t, []string{"reason 1", "reason 2"}, stopped)
+	})
+}
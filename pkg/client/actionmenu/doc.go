@@ -0,0 +1,17 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package actionmenu enables the agents to present and perform menu driven actions
+// (https://github.com/hyperledger/aries-rfcs/tree/master/features/0509-action-menu).
+//
+// The responder (e.g an issuer) sends a menu to the requester (e.g a mobile holder), the last menu received over the
+// connection is its active menu. The requester asks the responder to perform one of the menu options. Menu requests
+// and perform messages received by the responder trigger action events, they are continued by providing a menu:
+//
+//  event.Continue(actionmenu.WithMenu(menu))
+//
+// or by calling AcceptMenuRequest or AcceptPerform with the PIID of the action.
+package actionmenu
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+)
+
+type (
+	// Message is the basic message.
+	Message = basicmessage.Message
+	// L10n holds the locale of the message content.
+	L10n = basicmessage.L10n
+	// Record is the message saved in the history of the connection.
+	Record = basicmessage.Record
+	// QueryParams holds the filters and the page of the history query.
+	QueryParams = basicmessage.QueryParams
+	// QueryResult is the page of the message history.
+	QueryResult = basicmessage.QueryResult
+)
+
+// Provider contains dependencies for the basic message protocol and is typically created by using aries.Context().
+type Provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// ProtocolService defines the basic message service.
+type ProtocolService interface {
+	service.DIDComm
+	SendMessage(connectionID string, msg *Message) (string, error)
+	History(params *QueryParams) (*QueryResult, error)
+	DeleteHistory(connectionID string) error
+}
+
+// Client enables access to basic message API.
+type Client struct {
+	service.Event
+	service ProtocolService
+}
+
+// New returns new instance of basic message client.
+func New(ctx Provider) (*Client, error) {
+	svc, err := ctx.Service(basicmessage.BasicMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	messageSvc, ok := svc.(ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to basic message service failed")
+	}
+
+	return &Client{
+		Event:   messageSvc,
+		service: messageSvc,
+	}, nil
+}
+
+// SendMessage sends the message to the agent on the other end of the connection,
+// the locale of the content is optional.
+func (c *Client) SendMessage(connectionID, content, locale string) (string, error) {
+	if content == "" {
+		return "", errors.New("content is required")
+	}
+
+	msg := &Message{Content: content}
+
+	if locale != "" {
+		msg.L10n = &L10n{Locale: locale}
+	}
+
+	id, err := c.service.SendMessage(connectionID, msg)
+	if err != nil {
+		return "", fmt.Errorf("send message: %w", err)
+	}
+
+	return id, nil
+}
+
+// History returns the page of the message history matching the query parameters.
+func (c *Client) History(params *QueryParams) (*QueryResult, error) {
+	result, err := c.service.History(params)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteHistory deletes the message history of the connection.
+func (c *Client) DeleteHistory(connectionID string) error {
+	if err := c.service.DeleteHistory(connectionID); err != nil {
+		return fmt.Errorf("delete history: %w", err)
+	}
+
+	return nil
+}
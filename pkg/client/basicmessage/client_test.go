@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+	mockbasicmessage "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/basicmessage"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+// Ensure Client can emit events.
+var _ service.Event = (*Client)(nil)
+
+// Ensure the service implements the client interface.
+var _ ProtocolService = (*basicmessage.Service)(nil)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, c)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: fmt.Errorf("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to basic message service failed")
+	})
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{
+			SendMessageFunc: func(connectionID string, msg *Message) (string, error) {
+				require.Equal(t, "conn", connectionID)
+				require.Equal(t, "Bonjour", msg.Content)
+				require.Equal(t, "fr", msg.L10n.Locale)
+
+				return "message-id", nil
+			},
+		}})
+		require.NoError(t, err)
+
+		id, err := c.SendMessage("conn", "Bonjour", "fr")
+		require.NoError(t, err)
+		require.Equal(t, "message-id", id)
+	})
+
+	t.Run("without locale", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{
+			SendMessageFunc: func(_ string, msg *Message) (string, error) {
+				require.Nil(t, msg.L10n)
+
+				return "message-id", nil
+			},
+		}})
+		require.NoError(t, err)
+
+		_, err = c.SendMessage("conn", "Hello", "")
+		require.NoError(t, err)
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{}})
+		require.NoError(t, err)
+
+		_, err = c.SendMessage("conn", "", "")
+		require.EqualError(t, err, "content is required")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{
+			SendMessageFunc: func(string, *Message) (string, error) {
+				return "", errors.New("send error")
+			},
+		}})
+		require.NoError(t, err)
+
+		_, err = c.SendMessage("conn", "Hello", "")
+		require.EqualError(t, err, "send message: send error")
+	})
+}
+
+func TestClient_History(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{
+			HistoryFunc: func(params *QueryParams) (*QueryResult, error) {
+				require.Equal(t, "conn", params.ConnectionID)
+				require.Equal(t, 10, params.Limit)
+
+				return &QueryResult{Records: []*Record{{MessageID: "message-id"}}, NextCursor: "next"}, nil
+			},
+			DeleteHistoryFunc: func(connectionID string) error {
+				require.Equal(t, "conn", connectionID)
+
+				return nil
+			},
+		}})
+		require.NoError(t, err)
+
+		result, err := c.History(&QueryParams{ConnectionID: "conn", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Records, 1)
+		require.Equal(t, "next", result.NextCursor)
+
+		require.NoError(t, c.DeleteHistory("conn"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockbasicmessage.MockBasicMessageSvc{
+			HistoryFunc: func(*QueryParams) (*QueryResult, error) {
+				return nil, errors.New("history error")
+			},
+			DeleteHistoryFunc: func(string) error {
+				return errors.New("delete error")
+			},
+		}})
+		require.NoError(t, err)
+
+		_, err = c.History(&QueryParams{})
+		require.EqualError(t, err, "history: history error")
+
+		require.EqualError(t, c.DeleteHistory("conn"), "delete history: delete error")
+	})
+}
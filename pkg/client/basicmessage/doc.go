@@ -0,0 +1,17 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package basicmessage enables the agents to exchange human readable messages
+// (https://github.com/hyperledger/aries-rfcs/tree/master/features/0095-basic-message).
+//
+// The messages sent and received over a connection are saved in its history, the history is queried page
+// by page, oldest messages first unless sorted in descending order:
+//
+//  result, err := client.History(&basicmessage.QueryParams{ConnectionID: connectionID, Limit: 20})
+//
+// the NextCursor of the result is the cursor of the next page. Received messages trigger message events
+// with the message-received state.
+package basicmessage
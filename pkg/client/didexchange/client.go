@@ -10,12 +10,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
@@ -35,9 +38,17 @@ const (
 // ErrConnectionNotFound is returned when connection not found.
 var ErrConnectionNotFound = errors.New("connection not found")
 
+// ErrInvitationNotFound is returned when the invitation or its policy is not found.
+var ErrInvitationNotFound = errors.New("invitation not found")
+
+// ErrDIDRotateNotSupported is returned when the framework has no DID rotate service.
+var ErrDIDRotateNotSupported = errors.New("DID rotation is not supported")
+
 type options struct {
 	routerConnections  []string
 	routerConnectionID string
+	maxUses            int
+	expires            *time.Time
 }
 
 func applyOptions(args ...Opt) *options {
@@ -63,6 +74,20 @@ func WithRouterConnectionID(conn string) InvOpt {
 	}
 }
 
+// WithMaxUses limits the number of exchange requests accepted for the invitation, it must not be negative.
+func WithMaxUses(maxUses int) InvOpt {
+	return func(opts *options) {
+		opts.maxUses = maxUses
+	}
+}
+
+// WithExpiry sets the time after which the exchange requests for the invitation are not accepted.
+func WithExpiry(expires time.Time) InvOpt {
+	return func(opts *options) {
+		opts.expires = &expires
+	}
+}
+
 // WithRouterConnections allows you to specify the router connections.
 func WithRouterConnections(conns ...string) Opt {
 	return func(opts *options) {
@@ -92,6 +117,8 @@ type Client struct {
 	kms             kms.KeyManager
 	serviceEndpoint string
 	connectionStore *connection.Recorder
+	connectionLock  sync.Mutex
+	didRotateSvc    didRotateService
 }
 
 // protocolService defines DID Exchange service.
@@ -114,6 +141,12 @@ type protocolService interface {
 	CreateConnection(*connection.Record, *did.Doc) error
 }
 
+// didRotateService defines DID rotate service.
+type didRotateService interface {
+	// RotateDID replaces my DID on the connection.
+	RotateDID(connectionID, newDID string, routerConnections []string) (string, error)
+}
+
 // New return new instance of didexchange client.
 func New(ctx provider) (*Client, error) {
 	svc, err := ctx.Service(didexchange.DIDExchange)
@@ -141,6 +174,13 @@ func New(ctx provider) (*Client, error) {
 		return nil, err
 	}
 
+	// the DID rotate service is optional
+	var didRotateSvc didRotateService
+
+	if svc, e := ctx.Service(didrotate.DIDRotate); e == nil {
+		didRotateSvc, _ = svc.(didRotateService)
+	}
+
 	return &Client{
 		Event:           didexchangeSvc,
 		didexchangeSvc:  didexchangeSvc,
@@ -148,6 +188,7 @@ func New(ctx provider) (*Client, error) {
 		kms:             ctx.KMS(),
 		serviceEndpoint: ctx.ServiceEndpoint(),
 		connectionStore: connectionStore,
+		didRotateSvc:    didRotateSvc,
 	}, nil
 }
 
@@ -161,6 +202,10 @@ func (c *Client) CreateInvitation(label string, args ...InvOpt) (*Invitation, er
 		args[i](opts)
 	}
 
+	if opts.maxUses < 0 {
+		return nil, fmt.Errorf("createInvitation: invalid max uses %d", opts.maxUses)
+	}
+
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/623 'alias' should be passed as arg and persisted
 	//  with connection record
 	_, sigPubKey, err := c.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
@@ -202,12 +247,27 @@ func (c *Client) CreateInvitation(label string, args ...InvOpt) (*Invitation, er
 		return nil, fmt.Errorf("createInvitation: failed to save invitation: %w", err)
 	}
 
+	err = c.saveInvitationPolicy(invitation.ID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("createInvitation: %w", err)
+	}
+
 	return &Invitation{invitation}, nil
 }
 
 // CreateInvitationWithDID creates an invitation with specified public DID. This invitation will be stored
 // so client can cross reference this invitation during did exchange protocol.
-func (c *Client) CreateInvitationWithDID(label, publicDID string) (*Invitation, error) {
+func (c *Client) CreateInvitationWithDID(label, publicDID string, args ...InvOpt) (*Invitation, error) {
+	opts := &options{}
+
+	for i := range args {
+		args[i](opts)
+	}
+
+	if opts.maxUses < 0 {
+		return nil, fmt.Errorf("createInvitationWithDID: invalid max uses %d", opts.maxUses)
+	}
+
 	invitation := &didexchange.Invitation{
 		ID:    uuid.New().String(),
 		Label: label,
@@ -220,9 +280,61 @@ func (c *Client) CreateInvitationWithDID(label, publicDID string) (*Invitation,
 		return nil, fmt.Errorf("createInvitationWithDID: failed to save invitation with DID: %w", err)
 	}
 
+	err = c.saveInvitationPolicy(invitation.ID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("createInvitationWithDID: %w", err)
+	}
+
 	return &Invitation{invitation}, nil
 }
 
+// saveInvitationPolicy saves the policy of the invitation if its uses or lifetime are limited.
+func (c *Client) saveInvitationPolicy(invitationID string, opts *options) error {
+	if opts.maxUses == 0 && opts.expires == nil {
+		return nil
+	}
+
+	err := c.connectionStore.SaveInvitationPolicy(&connection.InvitationPolicy{
+		InvitationID: invitationID,
+		MaxUses:      opts.maxUses,
+		Expires:      opts.expires,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save invitation policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitationPolicy returns the policy and the number of uses of the invitation.
+// ErrInvitationNotFound is returned if the invitation has no policy and was not revoked.
+func (c *Client) GetInvitationPolicy(invitationID string) (*connection.InvitationPolicy, error) {
+	policy, err := c.connectionStore.GetInvitationPolicy(invitationID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+
+		return nil, fmt.Errorf("cannot fetch invitation policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// RevokeInvitation revokes the invitation, the exchange requests for the invitation are not accepted anymore.
+func (c *Client) RevokeInvitation(invitationID string) error {
+	err := c.connectionStore.RevokeInvitation(invitationID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return ErrInvitationNotFound
+		}
+
+		return fmt.Errorf("cannot revoke invitation: %w", err)
+	}
+
+	return nil
+}
+
 // HandleInvitation handle incoming invitation and returns the connectionID that can be used to query the state
 // of did exchange protocol. Upon successful completion of did exchange protocol connection details will be used
 // for securing communication between agents.
@@ -288,29 +400,38 @@ func (c *Client) CreateImplicitInvitationWithDID(inviter, invitee *DIDInfo) (str
 
 // QueryConnections queries connections matching given criteria(parameters).
 func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connection, error) {
-	// TODO https://github.com/hyperledger/aries-framework-go/issues/655 - query all connections from all criteria and
-	//  also results needs to be paged.
-	records, err := c.connectionStore.QueryConnectionRecords()
+	page, err := c.QueryConnectionsPage(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed query connections: %w", err)
+		return nil, err
 	}
 
-	var result []*Connection
-
-	for _, record := range records {
-		if request.State != "" && request.State != record.State {
-			continue
-		}
+	return page.Results, nil
+}
 
-		if request.MyDID != "" && request.MyDID != record.MyDID {
-			continue
-		}
+// QueryConnectionsPage queries the page of connections matching given criteria(parameters).
+// The NextCursor of the result is passed as the Cursor of the parameters to fetch the next page.
+func (c *Client) QueryConnectionsPage(request *QueryConnectionsParams) (*QueryConnectionsResult, error) {
+	page, err := c.connectionStore.QueryConnections(&connection.QueryParams{
+		State:        request.State,
+		MyDID:        request.MyDID,
+		TheirDID:     request.TheirDID,
+		InvitationID: request.InvitationID,
+		Namespace:    request.Namespace,
+		LabelPrefix:  request.LabelPrefix,
+		Tags:         request.Tags,
+		SortBy:       request.SortBy,
+		Descending:   request.Descending,
+		Limit:        request.Limit,
+		Cursor:       request.Cursor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed query connections: %w", err)
+	}
 
-		if request.TheirDID != "" && request.TheirDID != record.TheirDID {
-			continue
-		}
+	result := &QueryConnectionsResult{NextCursor: page.NextCursor}
 
-		result = append(result, &Connection{Record: record})
+	for _, record := range page.Records {
+		result.Results = append(result.Results, &Connection{Record: record})
 	}
 
 	return result, nil
@@ -389,6 +510,97 @@ func (c *Client) RemoveConnection(connectionID string) error {
 	return nil
 }
 
+// SetConnectionMetadata merges the given metadata into the metadata of the connection, the keys with
+// an empty value are removed.
+func (c *Client) SetConnectionMetadata(connectionID string, metadata map[string]string) error {
+	return c.updateConnection(connectionID, func(record *connection.Record) {
+		for k, v := range metadata {
+			if v == "" {
+				delete(record.Metadata, k)
+
+				continue
+			}
+
+			if record.Metadata == nil {
+				record.Metadata = make(map[string]string)
+			}
+
+			record.Metadata[k] = v
+		}
+	})
+}
+
+// AddConnectionTags adds the tags to the connection, the connections are filtered by tags with QueryConnections.
+func (c *Client) AddConnectionTags(connectionID string, tags ...string) error {
+	return c.updateConnection(connectionID, func(record *connection.Record) {
+		for _, tag := range tags {
+			if tag != "" && !record.HasTags(tag) {
+				record.Tags = append(record.Tags, tag)
+			}
+		}
+	})
+}
+
+// RemoveConnectionTags removes the tags from the connection.
+func (c *Client) RemoveConnectionTags(connectionID string, tags ...string) error {
+	removed := &connection.Record{Tags: tags}
+
+	return c.updateConnection(connectionID, func(record *connection.Record) {
+		var kept []string
+
+		for _, tag := range record.Tags {
+			if !removed.HasTags(tag) {
+				kept = append(kept, tag)
+			}
+		}
+
+		record.Tags = kept
+	})
+}
+
+func (c *Client) updateConnection(connectionID string, update func(*connection.Record)) error {
+	c.connectionLock.Lock()
+	defer c.connectionLock.Unlock()
+
+	record, err := c.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return ErrConnectionNotFound
+		}
+
+		return fmt.Errorf("cannot fetch state from store: connectionid=%s err=%w", connectionID, err)
+	}
+
+	update(record)
+
+	if err = c.connectionStore.SaveConnectionRecord(record); err != nil {
+		return fmt.Errorf("save connection record: %w", err)
+	}
+
+	return nil
+}
+
+// RotateDID replaces my DID on the completed connection with newDID (did-rotate protocol), a new peer DID
+// is created if newDID is empty (see WithRouterConnections). The connection record is updated once the other
+// agent acknowledges the rotation, the outcome is reported by the message events of the DID rotate service.
+// The previous DID remains valid for the grace period. Returns the thread ID of the rotation.
+func (c *Client) RotateDID(connectionID, newDID string, args ...Opt) (string, error) {
+	if c.didRotateSvc == nil {
+		return "", ErrDIDRotateNotSupported
+	}
+
+	thID, err := c.didRotateSvc.RotateDID(connectionID, newDID, applyOptions(args...).routerConnections)
+	if err != nil {
+		if errors.Is(err, didrotate.ErrConnectionNotFound) {
+			return "", ErrConnectionNotFound
+		}
+
+		return "", fmt.Errorf("rotate DID: %w", err)
+	}
+
+	return thID, nil
+}
+
 // ConnectionOption allows you to customize details of the connection record.
 type ConnectionOption func(*Connection)
 
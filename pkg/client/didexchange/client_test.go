@@ -23,12 +23,14 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	mockprotocol "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mocksvc "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	mockdidrotate "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didrotate"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
@@ -399,6 +401,120 @@ func TestClient_CreateInvitationWithDID(t *testing.T) {
 	})
 }
 
+func TestClient_InvitationPolicy(t *testing.T) {
+	newClient := func(t *testing.T, store *mockstore.MockStore) *Client {
+		t.Helper()
+
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		ed25519KH, err := mockkms.CreateMockED25519KeyHandle()
+		require.NoError(t, err)
+
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:              mockstore.NewCustomMockStoreProvider(store),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+			},
+			KMSValue:             &mockkms.KeyManager{CreateKeyValue: ed25519KH},
+			ServiceEndpointValue: "endpoint",
+		})
+		require.NoError(t, err)
+
+		return c
+	}
+
+	t.Run("test invitation with policy", func(t *testing.T) {
+		c := newClient(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		expires := time.Now().Add(time.Hour).UTC()
+		invitation, err := c.CreateInvitation("agent", WithMaxUses(3), WithExpiry(expires))
+		require.NoError(t, err)
+
+		policy, err := c.GetInvitationPolicy(invitation.ID)
+		require.NoError(t, err)
+		require.Equal(t, invitation.ID, policy.InvitationID)
+		require.Equal(t, 3, policy.MaxUses)
+		require.True(t, expires.Equal(*policy.Expires))
+		require.Zero(t, policy.Uses)
+		require.False(t, policy.Revoked)
+
+		require.NoError(t, c.RevokeInvitation(invitation.ID))
+
+		policy, err = c.GetInvitationPolicy(invitation.ID)
+		require.NoError(t, err)
+		require.True(t, policy.Revoked)
+	})
+
+	t.Run("test invitation with DID and policy", func(t *testing.T) {
+		c := newClient(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		invitation, err := c.CreateInvitationWithDID("agent", "did:example:123", WithMaxUses(1))
+		require.NoError(t, err)
+
+		policy, err := c.GetInvitationPolicy(invitation.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, policy.MaxUses)
+	})
+
+	t.Run("test invitation without policy", func(t *testing.T) {
+		c := newClient(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		invitation, err := c.CreateInvitation("agent")
+		require.NoError(t, err)
+
+		_, err = c.GetInvitationPolicy(invitation.ID)
+		require.True(t, errors.Is(err, ErrInvitationNotFound))
+
+		require.NoError(t, c.RevokeInvitation(invitation.ID))
+
+		policy, err := c.GetInvitationPolicy(invitation.ID)
+		require.NoError(t, err)
+		require.True(t, policy.Revoked)
+	})
+
+	t.Run("test invitation with negative max uses", func(t *testing.T) {
+		c := newClient(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		_, err := c.CreateInvitation("agent", WithMaxUses(-1))
+		require.EqualError(t, err, "createInvitation: invalid max uses -1")
+
+		_, err = c.CreateInvitationWithDID("agent", "did:example:123", WithMaxUses(-1))
+		require.EqualError(t, err, "createInvitationWithDID: invalid max uses -1")
+	})
+
+	t.Run("test revoke unknown invitation", func(t *testing.T) {
+		c := newClient(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		err := c.RevokeInvitation("unknown")
+		require.True(t, errors.Is(err, ErrInvitationNotFound))
+	})
+
+	t.Run("test invitation policy store errors", func(t *testing.T) {
+		c := newClient(t, &mockstore.MockStore{Store: make(map[string][]byte), ErrGet: errors.New("get error")})
+
+		_, err := c.GetInvitationPolicy("inv-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot fetch invitation policy")
+
+		err = c.RevokeInvitation("inv-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot revoke invitation")
+
+		c = newClient(t, &mockstore.MockStore{Store: make(map[string][]byte), ErrPut: errors.New("put error")})
+
+		_, err = c.CreateInvitationWithDID("agent", "did:example:123", WithMaxUses(1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+	})
+}
+
 func TestClient_QueryConnectionByID(t *testing.T) {
 	const (
 		connID   = "id1"
@@ -449,10 +565,7 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, svc)
 
-		store := &mockstore.MockStore{
-			Store:  make(map[string][]byte),
-			ErrGet: fmt.Errorf(errMsg),
-		}
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
 
 		c, err := New(&mockprovider.Provider{
 			ProtocolStateStorageProviderValue: mockstore.NewCustomMockStoreProvider(store),
@@ -470,6 +583,9 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NoError(t, c.connectionStore.SaveConnectionRecord(connRec))
+
+		store.ErrGet = fmt.Errorf(errMsg)
+
 		_, err = c.GetConnection(connID)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errMsg)
@@ -677,6 +793,180 @@ func TestClient_CreateConnection(t *testing.T) {
 	})
 }
 
+func TestClient_RotateDID(t *testing.T) {
+	newClient := func(t *testing.T, rotateSvc interface{}) *Client {
+		t.Helper()
+
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+				didrotate.DIDRotate:     rotateSvc,
+			},
+		})
+		require.NoError(t, err)
+
+		return c
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		c := newClient(t, &mockdidrotate.MockDIDRotateSvc{
+			RotateDIDFunc: func(connectionID, newDID string, routerConnections []string) (string, error) {
+				require.Equal(t, "id1", connectionID)
+				require.Equal(t, "did:example:new", newDID)
+				require.Equal(t, []string{"router1"}, routerConnections)
+
+				return "thid1", nil
+			},
+		})
+
+		thID, err := c.RotateDID("id1", "did:example:new", WithRouterConnections("router1"))
+		require.NoError(t, err)
+		require.Equal(t, "thid1", thID)
+	})
+	t.Run("test connection not found", func(t *testing.T) {
+		c := newClient(t, &mockdidrotate.MockDIDRotateSvc{
+			RotateDIDFunc: func(string, string, []string) (string, error) {
+				return "", fmt.Errorf("get connection: %w", didrotate.ErrConnectionNotFound)
+			},
+		})
+
+		_, err := c.RotateDID("id1", "")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+	t.Run("test rotate error", func(t *testing.T) {
+		c := newClient(t, &mockdidrotate.MockDIDRotateSvc{
+			RotateDIDFunc: func(string, string, []string) (string, error) {
+				return "", errors.New("rotate error")
+			},
+		})
+
+		_, err := c.RotateDID("id1", "")
+		require.EqualError(t, err, "rotate DID: rotate error")
+	})
+	t.Run("test DID rotate service not available", func(t *testing.T) {
+		c := newClient(t, nil)
+
+		_, err := c.RotateDID("id1", "")
+		require.True(t, errors.Is(err, ErrDIDRotateNotSupported))
+	})
+}
+
+func TestClient_ConnectionMetadataAndTags(t *testing.T) {
+	newClient := func(t *testing.T) *Client {
+		t.Helper()
+
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		for _, id := range []string{"id1", "id2"} {
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+				ConnectionID: id, ThreadID: "th" + id, State: "completed",
+			}))
+		}
+
+		return c
+	}
+
+	t.Run("test set metadata", func(t *testing.T) {
+		c := newClient(t)
+
+		require.NoError(t, c.SetConnectionMetadata("id1", map[string]string{"alias": "Alice", "note": "vip"}))
+		require.NoError(t, c.SetConnectionMetadata("id1", map[string]string{"note": "", "customerID": "42"}))
+
+		conn, err := c.GetConnection("id1")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"alias": "Alice", "customerID": "42"}, conn.Metadata)
+	})
+	t.Run("test add and remove tags", func(t *testing.T) {
+		c := newClient(t)
+
+		require.NoError(t, c.AddConnectionTags("id1", "customer", "gold", "customer"))
+		require.NoError(t, c.AddConnectionTags("id2", "customer"))
+
+		conn, err := c.GetConnection("id1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"customer", "gold"}, conn.Tags)
+
+		results, err := c.QueryConnections(&QueryConnectionsParams{Tags: []string{"customer"}})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		results, err = c.QueryConnections(&QueryConnectionsParams{Tags: []string{"customer", "gold"}})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "id1", results[0].ConnectionID)
+
+		require.NoError(t, c.RemoveConnectionTags("id1", "gold"))
+
+		results, err = c.QueryConnections(&QueryConnectionsParams{Tags: []string{"gold"}})
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+	t.Run("test connection not found", func(t *testing.T) {
+		c := newClient(t)
+
+		err := c.SetConnectionMetadata("unknown", map[string]string{"alias": "Alice"})
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+
+		err = c.AddConnectionTags("unknown", "gold")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+	t.Run("test save error", func(t *testing.T) {
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		protocolStateStore := mockstore.NewMockStoreProvider()
+
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: protocolStateStore,
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "id1", ThreadID: "thid1", State: "completed",
+		}))
+
+		protocolStateStore.Store.ErrPut = errors.New("put error")
+
+		err = c.RemoveConnectionTags("id1", "gold")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "save connection record")
+	})
+}
+
 func TestClient_RemoveConnection(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
 		connID := "id1"
@@ -925,15 +1215,12 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 		require.NoError(t, err)
 
 		const count = 10
-		const keyPrefix = "conn_"
 		const state = "completed"
 		for i := 0; i < count; i++ {
-			val, e := json.Marshal(&connection.Record{
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
 				ConnectionID: fmt.Sprint(i),
 				State:        state,
-			})
-			require.NoError(t, e)
-			require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("%sabc%d", keyPrefix, i), val))
+			}))
 		}
 
 		results, err := c.QueryConnections(&QueryConnectionsParams{})
@@ -966,7 +1253,6 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 
 		const count = 10
 		const countWithState = 5
-		const keyPrefix = "conn_"
 		const state = "completed"
 		const myDID = "my_did"
 		const theirDID = "their_did"
@@ -976,14 +1262,12 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 				queryState = state
 			}
 
-			val, e := json.Marshal(&connection.Record{
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
 				ConnectionID: fmt.Sprint(i),
 				State:        queryState,
 				MyDID:        myDID + strconv.Itoa(i),
 				TheirDID:     theirDID + strconv.Itoa(i),
-			})
-			require.NoError(t, e)
-			require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("%sabc%d", keyPrefix, i), val))
+			}))
 		}
 
 		results, err := c.QueryConnections(&QueryConnectionsParams{})
@@ -1033,6 +1317,54 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 		}
 	})
 
+	t.Run("test get connections page", func(t *testing.T) {
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, svc)
+
+		storageProvider := mockstore.NewMockStoreProvider()
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:              storageProvider,
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		const count = 5
+		for i := 0; i < count; i++ {
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+				ConnectionID: fmt.Sprint(i),
+				State:        "completed",
+				TheirLabel:   fmt.Sprintf("alice-%d", i),
+			}))
+		}
+
+		params := &QueryConnectionsParams{LabelPrefix: "alice", Descending: true, Limit: 3}
+		page, err := c.QueryConnectionsPage(params)
+		require.NoError(t, err)
+		require.Len(t, page.Results, 3)
+		require.Equal(t, "4", page.Results[0].ConnectionID)
+		require.NotEmpty(t, page.NextCursor)
+
+		params.Cursor = page.NextCursor
+		page, err = c.QueryConnectionsPage(params)
+		require.NoError(t, err)
+		require.Len(t, page.Results, 2)
+		require.Equal(t, "1", page.Results[0].ConnectionID)
+		require.Empty(t, page.NextCursor)
+
+		_, err = c.QueryConnectionsPage(&QueryConnectionsParams{SortBy: "label"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported sort order")
+	})
+
 	t.Run("test get connections error", func(t *testing.T) {
 		svc, err := didexchange.New(&mockprotocol.MockProvider{
 			ServiceMap: map[string]interface{}{
@@ -1054,6 +1386,10 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 		})
 		require.NoError(t, err)
 
+		require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "abc",
+			State:        "completed",
+		}))
 		require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("%sabc", keyPrefix), []byte("----")))
 
 		results, err := c.QueryConnections(&QueryConnectionsParams{})
@@ -37,6 +37,39 @@ type QueryConnectionsParams struct {
 
 	// TheirRole is other party's role
 	TheirRole string `json:"their_role,omitempty"`
+
+	// Tags the connection must have (all of them)
+	Tags []string `json:"tags,omitempty"`
+
+	// InvitationID of the connection
+	InvitationID string `json:"invitation_id,omitempty"`
+
+	// Namespace of the connection (my or their)
+	Namespace string `json:"namespace,omitempty"`
+
+	// LabelPrefix the label of the other party starts with
+	LabelPrefix string `json:"label_prefix,omitempty"`
+
+	// SortBy is the sort order of the connections (id, created or updated)
+	SortBy string `json:"sort_by,omitempty"`
+
+	// Descending reverses the sort order
+	Descending bool `json:"descending,omitempty"`
+
+	// Limit is the maximum number of connections returned, all of them if not set
+	Limit int `json:"limit,omitempty"`
+
+	// Cursor is the next cursor of the previous page
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// QueryConnectionsResult is the page of connections matching the query parameters.
+type QueryConnectionsResult struct {
+	// Results are the connections of the page
+	Results []*Connection `json:"results,omitempty"`
+
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // Connection model
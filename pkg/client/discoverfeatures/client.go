@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+)
+
+// provider contains dependencies for the discover-features protocol and is typically created by using aries.Context().
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// ProtocolDescriptor describes the protocol supported by the agent.
+type ProtocolDescriptor = discoverfeatures.ProtocolDescriptor
+
+// DisclosurePolicy decides which of the protocols matching the query are disclosed to the requester.
+type DisclosurePolicy = discoverfeatures.DisclosurePolicy
+
+// Client enables access to discover-features api.
+type Client struct {
+	service.Event
+	discoverSvc protocolService
+	options     []discoverfeatures.ClientOption
+}
+
+// protocolService defines discover-features service.
+type protocolService interface {
+	// DIDComm service
+	service.DIDComm
+
+	// Query asks the agent on the other end of the connection which protocols it supports
+	Query(connectionID, query string, options ...discoverfeatures.ClientOption) ([]ProtocolDescriptor, error)
+
+	// Features returns the protocols supported by the agent
+	Features(query string) ([]ProtocolDescriptor, error)
+
+	// SetDisclosurePolicy sets the policy which filters the disclosed protocols
+	SetDisclosurePolicy(policy DisclosurePolicy)
+}
+
+// WithTimeout option is for definition timeout value waiting for the disclose message.
+func WithTimeout(t time.Duration) discoverfeatures.ClientOption {
+	return func(opts *discoverfeatures.ClientOptions) {
+		opts.Timeout = t
+	}
+}
+
+// New returns new instance of discover-features client.
+func New(ctx provider, options ...discoverfeatures.ClientOption) (*Client, error) {
+	svc, err := ctx.Service(discoverfeatures.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	discoverSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to discover-features service failed")
+	}
+
+	return &Client{
+		Event:       discoverSvc,
+		discoverSvc: discoverSvc,
+		options:     options,
+	}, nil
+}
+
+// Query asks the agent on the other end of the connection which protocols matching the query it supports.
+// The query may contain '*' wildcards (e.g 'https://didcomm.org/*'). This function blocks until
+// the agent responds or it times out.
+func (c *Client) Query(connectionID, query string) ([]ProtocolDescriptor, error) {
+	protocols, err := c.discoverSvc.Query(connectionID, query, c.options...)
+	if err != nil {
+		return nil, fmt.Errorf("query features: %w", err)
+	}
+
+	return protocols, nil
+}
+
+// Features returns the protocols supported by this agent which match the query.
+func (c *Client) Features(query string) ([]ProtocolDescriptor, error) {
+	protocols, err := c.discoverSvc.Features(query)
+	if err != nil {
+		return nil, fmt.Errorf("features: %w", err)
+	}
+
+	return protocols, nil
+}
+
+// SetDisclosurePolicy sets the policy which filters the protocols disclosed to other agents.
+// Passing nil discloses all the protocols matching the query.
+func (c *Client) SetDisclosurePolicy(policy DisclosurePolicy) {
+	c.discoverSvc.SetDisclosurePolicy(policy)
+}
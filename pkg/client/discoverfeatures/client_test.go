@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	mockdiscover "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/discoverfeatures"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+// Ensure Client can emit events.
+var _ service.Event = (*Client)(nil)
+
+// Ensure the service implements the client interface.
+var _ protocolService = (*discoverfeatures.Service)(nil)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockdiscover.MockDiscoverFeaturesSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, c)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: fmt.Errorf("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to discover-features service failed")
+	})
+
+	t.Run("test timeout is applied to options", func(t *testing.T) {
+		timeout := 1 * time.Second
+
+		option := WithTimeout(timeout)
+		opts := &discoverfeatures.ClientOptions{}
+		option(opts)
+
+		require.Equal(t, timeout, opts.Timeout)
+	})
+}
+
+func TestClient_Query(t *testing.T) {
+	t.Run("test query - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscover.MockDiscoverFeaturesSvc{
+				QueryFunc: func(connectionID, query string, options ...discoverfeatures.ClientOption) ([]ProtocolDescriptor, error) { // nolint: lll
+					require.Equal(t, "conn", connectionID)
+					require.Equal(t, "*", query)
+					require.Len(t, options, 1)
+
+					return []ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, nil
+				},
+			},
+		}, WithTimeout(time.Second))
+		require.NoError(t, err)
+
+		protocols, err := c.Query("conn", "*")
+		require.NoError(t, err)
+		require.Equal(t, []ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, protocols)
+	})
+
+	t.Run("test query - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscover.MockDiscoverFeaturesSvc{
+				QueryFunc: func(string, string, ...discoverfeatures.ClientOption) ([]ProtocolDescriptor, error) {
+					return nil, errors.New("query error")
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = c.Query("conn", "*")
+		require.EqualError(t, err, "query features: query error")
+	})
+}
+
+func TestClient_Features(t *testing.T) {
+	t.Run("test features - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscover.MockDiscoverFeaturesSvc{
+				FeaturesFunc: func(query string) ([]ProtocolDescriptor, error) {
+					require.Equal(t, "*", query)
+
+					return []ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, nil
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		protocols, err := c.Features("*")
+		require.NoError(t, err)
+		require.Equal(t, []ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, protocols)
+	})
+
+	t.Run("test features - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscover.MockDiscoverFeaturesSvc{
+				FeaturesFunc: func(string) ([]ProtocolDescriptor, error) {
+					return nil, errors.New("query is empty")
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = c.Features("")
+		require.EqualError(t, err, "features: query is empty")
+	})
+}
+
+func TestClient_SetDisclosurePolicy(t *testing.T) {
+	svc := &mockdiscover.MockDiscoverFeaturesSvc{}
+
+	c, err := New(&mockprovider.Provider{ServiceValue: svc})
+	require.NoError(t, err)
+
+	c.SetDisclosurePolicy(func(*discoverfeatures.Query, string, string, []ProtocolDescriptor) []ProtocolDescriptor {
+		return nil
+	})
+	require.NotNil(t, svc.DisclosurePolicy)
+}
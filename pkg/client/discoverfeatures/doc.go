@@ -0,0 +1,11 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package discoverfeatures enables the agent to ask other agents which protocols they support
+// (https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features).
+// The agent answers such queries automatically with the protocols of its registered protocol and
+// message services; a disclosure policy can be set to limit what is disclosed.
+package discoverfeatures
@@ -29,8 +29,16 @@ type (
 	Recipient introduce.Recipient
 	// Action contains helpful information about action.
 	Action introduce.Action
+	// ProtocolInstance is the record of the protocol instance along with its history.
+	ProtocolInstance introduce.ProtocolInstance
 )
 
+// ResponsePolicy answers the introduce actions without waiting for the user.
+type ResponsePolicy = introduce.ResponsePolicy
+
+// Target describes the agent the introducer introduces on request without asking the user.
+type Target = introduce.Target
+
 // Provider contains dependencies for the introduce protocol and is typically created by using aries.Context().
 type Provider interface {
 	Service(id string) (interface{}, error)
@@ -42,6 +50,10 @@ type ProtocolService interface {
 	Actions() ([]introduce.Action, error)
 	ActionContinue(piID string, opt introduce.Opt) error
 	ActionStop(piID string, err error) error
+	ProtocolInstances() ([]*introduce.ProtocolInstance, error)
+	ProtocolInstance(piID string) (*introduce.ProtocolInstance, error)
+	DeleteProtocolInstance(piID string) error
+	SetResponsePolicy(policy *introduce.ResponsePolicy)
 }
 
 // Client enable access to introduce API.
@@ -97,6 +109,29 @@ func (c *Client) SendProposalWithOOBRequest(req *outofband.Request, recipient *R
 	return c.service.HandleOutbound(proposal, recipient.MyDID, recipient.TheirDID)
 }
 
+// SendProposalWithOOBInvitation sends a proposal to the introducee (the client has published an out-of-band
+// invitation).
+func (c *Client) SendProposalWithOOBInvitation(inv *outofband.Invitation, recipient *Recipient) (string, error) {
+	_recipient := introduce.Recipient(*recipient)
+	_inv := outofbandsvc.Invitation(*inv)
+
+	proposal := introduce.CreateProposal(&_recipient)
+	introduce.WrapWithMetadataPublicOOBInvitation(proposal, &_inv)
+
+	return c.service.HandleOutbound(proposal, recipient.MyDID, recipient.TheirDID)
+}
+
+// SendProposalWithPublicDID sends a proposal to the introducee to connect to the agent
+// which is reachable only by its public DID.
+func (c *Client) SendProposalWithPublicDID(publicDID string, recipient *Recipient) (string, error) {
+	_recipient := introduce.Recipient(*recipient)
+
+	proposal := introduce.CreateProposal(&_recipient)
+	introduce.WrapWithMetadataPublicOOBInvitation(proposal, introduce.CreatePublicDIDInvitation(publicDID, recipient.To))
+
+	return c.service.HandleOutbound(proposal, recipient.MyDID, recipient.TheirDID)
+}
+
 // SendRequest sends a request.
 // Sending a request means that the introducee is willing to share their own out-of-band message.
 func (c *Client) SendRequest(to *PleaseIntroduceTo, myDID, theirDID string) (string, error) {
@@ -125,6 +160,12 @@ func (c *Client) AcceptRequestWithPublicOOBRequest(piID string, req *outofband.R
 	return c.service.ActionContinue(piID, WithPublicOOBRequest(req, to))
 }
 
+// AcceptRequestWithPublicDID is used when the agent the introducer introduces is reachable only by its public DID.
+// Introducer can provide the public DID only after receiving RequestMsgType.
+func (c *Client) AcceptRequestWithPublicDID(piID, publicDID string, to *To) error {
+	return c.service.ActionContinue(piID, WithPublicDID(publicDID, to))
+}
+
 // AcceptRequestWithRecipients is used when the introducer does not have a published out-of-band message on hand
 // but he is willing to introduce agents to each other.
 // Introducer can provide recipients only after receiving RequestMsgType.
@@ -149,6 +190,13 @@ func (c *Client) AcceptProblemReport(piID string) error {
 	return c.service.ActionContinue(piID, nil)
 }
 
+// SetResponsePolicy sets the policy which accepts the proposals of the given introducers and forwards
+// the out-of-band messages of the requested agents without waiting for the user.
+// Passing nil restores the default behavior which emits all the actions as action events.
+func (c *Client) SetResponsePolicy(policy *ResponsePolicy) {
+	c.service.SetResponsePolicy(policy)
+}
+
 // Actions returns unfinished actions for the async usage.
 func (c *Client) Actions() ([]Action, error) {
 	actions, err := c.service.Actions()
@@ -164,6 +212,38 @@ func (c *Client) Actions() ([]Action, error) {
 	return result, nil
 }
 
+// ProtocolInstances returns the records of the in-flight and completed protocol instances.
+func (c *Client) ProtocolInstances() ([]ProtocolInstance, error) {
+	instances, err := c.service.ProtocolInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ProtocolInstance, len(instances))
+	for i, instance := range instances {
+		result[i] = ProtocolInstance(*instance)
+	}
+
+	return result, nil
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piID.
+func (c *Client) ProtocolInstance(piID string) (*ProtocolInstance, error) {
+	instance, err := c.service.ProtocolInstance(piID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ProtocolInstance(*instance)
+
+	return &result, nil
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piID.
+func (c *Client) DeleteProtocolInstance(piID string) error {
+	return c.service.DeleteProtocolInstance(piID)
+}
+
 // WithRecipients is used when the introducer does not have a published out-of-band message on hand
 // but he is willing to introduce agents to each other.
 // NOTE: Introducer can provide recipients only after receiving RequestMsgType.
@@ -185,6 +265,15 @@ func WithPublicOOBRequest(req *outofband.Request, to *To) introduce.Opt {
 	return introduce.WithPublicOOBRequest(&_req, &_to)
 }
 
+// WithPublicDID is used when the agent the introducer introduces is reachable only by its public DID.
+// NOTE: Introducer can provide the public DID only after receiving RequestMsgType
+// USAGE: event.Continue(WithPublicDID(publicDID, to)).
+func WithPublicDID(publicDID string, to *To) introduce.Opt {
+	_to := introduce.To(*to)
+
+	return introduce.WithPublicDID(publicDID, &_to)
+}
+
 // WithOOBRequest is used when introducee wants to provide an out-of-band request with an optional
 // series of attachments.
 // NOTE: Introducee can provide the request only after receiving ProposalMsgType
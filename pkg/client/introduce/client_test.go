@@ -156,6 +156,63 @@ func TestClient_SendProposalWithOOBRequest(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClient_SendProposalWithOOBInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mocksintroduce.NewMockProvider(ctrl)
+
+	svc := mocksintroduce.NewMockProtocolService(ctrl)
+	svc.EXPECT().
+		HandleOutbound(gomock.Any(), "firstMyDID", "firstTheirDID").
+		DoAndReturn(func(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+			require.Equal(t, msg.Type(), introduce.ProposalMsgType)
+			require.NotEmpty(t, msg.Metadata())
+
+			return expectedPIID, nil
+		})
+
+	provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	piid, err := client.SendProposalWithOOBInvitation(&outofband.Invitation{}, &Recipient{
+		MyDID:    "firstMyDID",
+		TheirDID: "firstTheirDID",
+	})
+	require.Equal(t, expectedPIID, piid)
+	require.NoError(t, err)
+}
+
+func TestClient_SendProposalWithPublicDID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mocksintroduce.NewMockProvider(ctrl)
+
+	svc := mocksintroduce.NewMockProtocolService(ctrl)
+	svc.EXPECT().
+		HandleOutbound(gomock.Any(), "firstMyDID", "firstTheirDID").
+		DoAndReturn(func(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+			require.Equal(t, msg.Type(), introduce.ProposalMsgType)
+			require.NotEmpty(t, msg.Metadata())
+
+			return expectedPIID, nil
+		})
+
+	provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	piid, err := client.SendProposalWithPublicDID("did:example:carol", &Recipient{
+		To:       &introduce.To{Name: "Carol"},
+		MyDID:    "firstMyDID",
+		TheirDID: "firstTheirDID",
+	})
+	require.Equal(t, expectedPIID, piid)
+	require.NoError(t, err)
+}
+
 func TestClient_SendRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -259,6 +316,44 @@ func TestClient_AcceptRequestWithPublicOOBRequest(t *testing.T) {
 	})
 }
 
+func TestClient_AcceptRequestWithPublicDID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mocksintroduce.NewMockProvider(ctrl)
+	svc := mocksintroduce.NewMockProtocolService(ctrl)
+	svc.EXPECT().ActionContinue(expectedPIID, gomock.Any()).DoAndReturn(
+		func(piid string, opt introduce.Opt) error {
+			require.NotNil(t, opt)
+
+			return nil
+		},
+	)
+	provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, client.AcceptRequestWithPublicDID(expectedPIID, "did:example:carol", &To{Name: "Carol"}))
+}
+
+func TestClient_SetResponsePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	policy := &ResponsePolicy{Introducers: []string{"did:example:alice"}}
+
+	provider := mocksintroduce.NewMockProvider(ctrl)
+	svc := mocksintroduce.NewMockProtocolService(ctrl)
+	svc.EXPECT().SetResponsePolicy(policy)
+	provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	client.SetResponsePolicy(policy)
+}
+
 func TestClient_DeclineProposal(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -306,3 +401,87 @@ func TestClient_AcceptProblemReport(t *testing.T) {
 
 	require.NoError(t, client.AcceptProblemReport("PIID"))
 }
+
+func TestClient_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstances().Return([]*introduce.ProtocolInstance{{PIID: "1"}, {PIID: "2"}}, nil)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		instances, err := client.ProtocolInstances()
+		require.NoError(t, err)
+		require.Equal(t, []ProtocolInstance{{PIID: "1"}, {PIID: "2"}}, instances)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstances().Return(nil, errors.New("error"))
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = client.ProtocolInstances()
+		require.EqualError(t, err, "error")
+	})
+}
+
+func TestClient_ProtocolInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstance("piid").Return(&introduce.ProtocolInstance{PIID: "piid"}, nil)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		instance, err := client.ProtocolInstance("piid")
+		require.NoError(t, err)
+		require.Equal(t, &ProtocolInstance{PIID: "piid"}, instance)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstance("piid").Return(nil, introduce.ErrProtocolInstanceNotFound)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = client.ProtocolInstance("piid")
+		require.True(t, errors.Is(err, introduce.ErrProtocolInstanceNotFound))
+	})
+}
+
+func TestClient_DeleteProtocolInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mocksintroduce.NewMockProvider(ctrl)
+
+	svc := mocksintroduce.NewMockProtocolService(ctrl)
+	svc.EXPECT().DeleteProtocolInstance("piid").Return(nil)
+
+	provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteProtocolInstance("piid"))
+}
@@ -79,6 +79,8 @@ func mockContext(agent string, tr map[string]chan payload, done chan struct{}) P
 	mProvider := messengerMocks.NewMockProvider(ctrl)
 	mProvider.EXPECT().StorageProvider().Return(storageProvider)
 	mProvider.EXPECT().OutboundDispatcher().Return(outbound)
+	mProvider.EXPECT().KMS().Return(nil)
+	mProvider.EXPECT().ServiceEndpoint().Return("")
 
 	provider := introduceServiceMocks.NewMockProvider(ctrl)
 	provider.EXPECT().StorageProvider().Return(storageProvider)
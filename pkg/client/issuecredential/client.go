@@ -38,6 +38,8 @@ type (
 	IssueCredential issuecredential.IssueCredential
 	// Action contains helpful information about action.
 	Action issuecredential.Action
+	// ProtocolInstance is the record of the protocol instance along with its history.
+	ProtocolInstance issuecredential.ProtocolInstance
 )
 
 // Provider contains dependencies for the issuecredential protocol and is typically created by using aries.Context().
@@ -51,6 +53,9 @@ type ProtocolService interface {
 	Actions() ([]issuecredential.Action, error)
 	ActionContinue(piID string, opt issuecredential.Opt) error
 	ActionStop(piID string, err error) error
+	ProtocolInstances() ([]*issuecredential.ProtocolInstance, error)
+	ProtocolInstance(piID string) (*issuecredential.ProtocolInstance, error)
+	DeleteProtocolInstance(piID string) error
 }
 
 // Client enable access to issuecredential API.
@@ -92,6 +97,38 @@ func (c *Client) Actions() ([]Action, error) {
 	return result, nil
 }
 
+// ProtocolInstances returns the records of the in-flight and completed protocol instances.
+func (c *Client) ProtocolInstances() ([]ProtocolInstance, error) {
+	instances, err := c.service.ProtocolInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ProtocolInstance, len(instances))
+	for i, instance := range instances {
+		result[i] = ProtocolInstance(*instance)
+	}
+
+	return result, nil
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piID.
+func (c *Client) ProtocolInstance(piID string) (*ProtocolInstance, error) {
+	instance, err := c.service.ProtocolInstance(piID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ProtocolInstance(*instance)
+
+	return &result, nil
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piID.
+func (c *Client) DeleteProtocolInstance(piID string) error {
+	return c.service.DeleteProtocolInstance(piID)
+}
+
 // SendOffer is used by the Issuer to send an offer.
 func (c *Client) SendOffer(offer *OfferCredential, myDID, theirDID string) (string, error) {
 	if offer == nil {
@@ -316,3 +316,87 @@ func TestClient_DeclineCredential(t *testing.T) {
 
 	require.NoError(t, client.DeclineCredential("PIID", "the reason"))
 }
+
+func TestClient_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstances().Return([]*issuecredential.ProtocolInstance{{PIID: "1"}, {PIID: "2"}}, nil)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		instances, err := client.ProtocolInstances()
+		require.NoError(t, err)
+		require.Equal(t, []ProtocolInstance{{PIID: "1"}, {PIID: "2"}}, instances)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstances().Return(nil, errors.New("error"))
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = client.ProtocolInstances()
+		require.EqualError(t, err, "error")
+	})
+}
+
+func TestClient_ProtocolInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstance("piid").Return(&issuecredential.ProtocolInstance{PIID: "piid"}, nil)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		instance, err := client.ProtocolInstance("piid")
+		require.NoError(t, err)
+		require.Equal(t, &ProtocolInstance{PIID: "piid"}, instance)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().ProtocolInstance("piid").Return(nil, issuecredential.ErrProtocolInstanceNotFound)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = client.ProtocolInstance("piid")
+		require.True(t, errors.Is(err, issuecredential.ErrProtocolInstanceNotFound))
+	})
+}
+
+func TestClient_DeleteProtocolInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mocks.NewMockProvider(ctrl)
+
+	svc := mocks.NewMockProtocolService(ctrl)
+	svc.EXPECT().DeleteProtocolInstance("piid").Return(nil)
+
+	provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteProtocolInstance("piid"))
+}
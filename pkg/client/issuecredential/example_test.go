@@ -56,6 +56,8 @@ func mockContext(agent string, tr map[string]chan payload) Provider {
 	mProvider := messengermocks.NewMockProvider(ctrl)
 	mProvider.EXPECT().StorageProvider().Return(store)
 	mProvider.EXPECT().OutboundDispatcher().Return(outbound)
+	mProvider.EXPECT().KMS().Return(nil)
+	mProvider.EXPECT().ServiceEndpoint().Return("")
 
 	msgSvc, err := messenger.NewMessenger(mProvider)
 	if err != nil {
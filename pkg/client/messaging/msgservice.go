@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
 )
 
 const (
@@ -56,6 +57,14 @@ func (m *msgService) Name() string {
 	return m.name
 }
 
+func (m *msgService) Protocols() []string {
+	if m.msgType == "" {
+		return nil
+	}
+
+	return []string{discoverfeatures.PIURIFromMsgType(m.msgType)}
+}
+
 func (m *msgService) Accept(msgType string, purpose []string) bool {
 	purposeMatched, typeMatched := len(m.purpose) == 0, m.msgType == ""
 
@@ -167,6 +167,18 @@ func TestMsgService_AcceptAndName(t *testing.T) {
 	}
 }
 
+func TestMsgService_Protocols(t *testing.T) {
+	t.Run("test protocols of message type", func(t *testing.T) {
+		msgsvc := newMessageService("test", "https://didcomm.org/generic/1.0/message", nil, nil)
+		require.Equal(t, []string{"https://didcomm.org/generic/1.0"}, msgsvc.Protocols())
+	})
+
+	t.Run("test no protocols when only purpose is set", func(t *testing.T) {
+		msgsvc := newMessageService("test", "", []string{"prp-01"}, nil)
+		require.Empty(t, msgsvc.Protocols())
+	})
+}
+
 func TestMsgService_HandleInbound(t *testing.T) {
 	const (
 		sampleName = "sample-msgsvc-01"
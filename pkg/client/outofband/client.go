@@ -9,6 +9,7 @@ package outofband
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
@@ -20,6 +21,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 type (
@@ -36,8 +38,20 @@ const (
 	RequestMsgType = outofband.RequestMsgType
 	// InvitationMsgType is the '@type' for the invitation message.
 	InvitationMsgType = outofband.InvitationMsgType
+	// LegacyInvitationMsgType is the '@type' for the pre-release invitation message.
+	// Deprecated: the invitation of this type is accepted as the out-of-band/1.0 invitation.
+	LegacyInvitationMsgType = outofband.LegacyInvitationMsgType
+	// HandshakeReuseMsgType is the '@type' for the handshake-reuse message.
+	HandshakeReuseMsgType = outofband.HandshakeReuseMsgType
+	// HandshakeReuseAcceptedMsgType is the '@type' for the handshake-reuse-accepted message.
+	HandshakeReuseAcceptedMsgType = outofband.HandshakeReuseAcceptedMsgType
 )
 
+// UnmarshalJSON reads the pre-release `oob-invitation/1.0` invitation as well.
+func (i *Invitation) UnmarshalJSON(data []byte) error {
+	return (*outofband.Invitation)(i).UnmarshalJSON(data)
+}
+
 // EventOptions are is a container of options that you can pass to an event's
 // Continue function to customize the reaction to incoming out-of-band messages.
 type EventOptions struct {
@@ -45,6 +59,10 @@ type EventOptions struct {
 	Label string
 	// Connections allows specifying router connections.
 	Connections []string
+	// ReuseConn is the ID of the existing connection to reuse instead of the new handshake.
+	ReuseConn string
+	// ReuseAny allows reusing any existing connection with the DIDs of the invitation's services.
+	ReuseAny bool
 }
 
 // RouterConnections return router connections.
@@ -57,6 +75,16 @@ func (e *EventOptions) MyLabel() string {
 	return e.Label
 }
 
+// ReuseConnection returns the ID of the existing connection to reuse.
+func (e *EventOptions) ReuseConnection() string {
+	return e.ReuseConn
+}
+
+// ReuseAnyConnection returns true if any existing connection with the invitation's DIDs can be reused.
+func (e *EventOptions) ReuseAnyConnection() bool {
+	return e.ReuseAny
+}
+
 // Event is a container of out-of-band protocol-specific properties for DIDCommActions and StateMsgs.
 type Event interface {
 	// ConnectionID of the connection record, once it's created.
@@ -70,11 +98,16 @@ type Event interface {
 type MessageOption func(*message) error
 
 type message struct {
-	Label             string
-	Goal              string
-	GoalCode          string
-	RouterConnections []string
-	Service           []interface{}
+	Label              string
+	Goal               string
+	GoalCode           string
+	RouterConnections  []string
+	Service            []interface{}
+	Attachments        []*decorator.Attachment
+	ReuseConnection    string
+	ReuseAnyConnection bool
+	MaxUses            int
+	Expires            *time.Time
 }
 
 func (m *message) RouterConnection() string {
@@ -89,9 +122,12 @@ func (m *message) RouterConnection() string {
 type OobService interface {
 	service.Event
 	AcceptRequest(*outofband.Request, string, []string) (string, error)
-	AcceptInvitation(*outofband.Invitation, string, []string) (string, error)
+	AcceptInvitation(*outofband.Invitation, outofband.Options) (string, error)
 	SaveRequest(*outofband.Request) error
 	SaveInvitation(*outofband.Invitation) error
+	SaveInvitationPolicy(*connection.InvitationPolicy) error
+	GetInvitationPolicy(string) (*connection.InvitationPolicy, error)
+	RevokeInvitation(string) error
 	Actions() ([]outofband.Action, error)
 	ActionContinue(string, outofband.Options) error
 	ActionStop(string, error) error
@@ -180,8 +216,9 @@ func (c *Client) CreateRequest(attchmnts []*decorator.Attachment, opts ...Messag
 }
 
 // CreateInvitation creates and saves an out-of-band invitation.
-// Protocols is an optional list of protocol identifier URIs that can be used to form connections. A default
-// will be set if none are provided.
+// Protocols is an optional list of handshake protocol identifier URIs that can be used to form connections.
+// A default will be set if none are provided unless requests are attached with WithAttachments,
+// the invitation without handshake protocols is handled by the other agent without a connection.
 func (c *Client) CreateInvitation(protocols []string, opts ...MessageOption) (*Invitation, error) {
 	msg := &message{}
 
@@ -192,13 +229,14 @@ func (c *Client) CreateInvitation(protocols []string, opts ...MessageOption) (*I
 	}
 
 	inv := &Invitation{
-		ID:        uuid.New().String(),
-		Type:      InvitationMsgType,
-		Label:     msg.Label,
-		Goal:      msg.Goal,
-		GoalCode:  msg.GoalCode,
-		Service:   msg.Service,
-		Protocols: protocols,
+		ID:                 uuid.New().String(),
+		Type:               InvitationMsgType,
+		Label:              msg.Label,
+		Goal:               msg.Goal,
+		GoalCode:           msg.GoalCode,
+		Service:            msg.Service,
+		HandshakeProtocols: protocols,
+		Requests:           msg.Attachments,
 	}
 
 	if len(inv.Service) == 0 {
@@ -210,10 +248,10 @@ func (c *Client) CreateInvitation(protocols []string, opts ...MessageOption) (*I
 		inv.Service = []interface{}{svc}
 	}
 
-	if len(inv.Protocols) == 0 {
+	if len(inv.HandshakeProtocols) == 0 && len(inv.Requests) == 0 {
 		// TODO should be injected into client
 		//  https://github.com/hyperledger/aries-framework-go/issues/1691
-		inv.Protocols = []string{didexchange.PIURI}
+		inv.HandshakeProtocols = []string{didexchange.PIURI}
 	}
 
 	cast := outofband.Invitation(*inv)
@@ -223,9 +261,41 @@ func (c *Client) CreateInvitation(protocols []string, opts ...MessageOption) (*I
 		return nil, fmt.Errorf("failed to save outofband invitation : %w", err)
 	}
 
+	if msg.MaxUses > 0 || msg.Expires != nil {
+		err = c.oobService.SaveInvitationPolicy(&connection.InvitationPolicy{
+			InvitationID: inv.ID,
+			MaxUses:      msg.MaxUses,
+			Expires:      msg.Expires,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save outofband invitation policy : %w", err)
+		}
+	}
+
 	return inv, nil
 }
 
+// GetInvitationPolicy returns the policy (max uses, expiry, revocation) and the number of uses of the invitation.
+// The invitations created without policy have none, their uses are not counted.
+func (c *Client) GetInvitationPolicy(invitationID string) (*connection.InvitationPolicy, error) {
+	policy, err := c.oobService.GetInvitationPolicy(invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outofband invitation policy : %w", err)
+	}
+
+	return policy, nil
+}
+
+// RevokeInvitation revokes the invitation, the requests for the invitation are not accepted anymore.
+func (c *Client) RevokeInvitation(invitationID string) error {
+	err := c.oobService.RevokeInvitation(invitationID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke outofband invitation : %w", err)
+	}
+
+	return nil
+}
+
 // Actions returns unfinished actions for the async usage.
 func (c *Client) Actions() ([]Action, error) {
 	actions, err := c.oobService.Actions()
@@ -263,6 +333,8 @@ func (c *Client) ActionStop(piID string, err error) error {
 }
 
 // AcceptRequest from another agent and return the ID of a new connection record.
+// No connection is created if the attached message has the ~service decorator (e.g present proof request),
+// in that case the message is handled connectionless and the returned ID is empty.
 func (c *Client) AcceptRequest(r *Request, myLabel string, opts ...MessageOption) (string, error) {
 	msg := &message{}
 
@@ -283,6 +355,9 @@ func (c *Client) AcceptRequest(r *Request, myLabel string, opts ...MessageOption
 }
 
 // AcceptInvitation from another agent and return the ID of the new connection records.
+// The ID of the existing connection is returned if it is reused (see ReuseConnection and ReuseAnyConnection).
+// No connection is created if the invitation has no handshake protocols, in that case the attached request
+// is handled connectionless and the returned ID is empty.
 func (c *Client) AcceptInvitation(i *Invitation, myLabel string, opts ...MessageOption) (string, error) {
 	msg := &message{}
 
@@ -294,7 +369,12 @@ func (c *Client) AcceptInvitation(i *Invitation, myLabel string, opts ...Message
 
 	cast := outofband.Invitation(*i)
 
-	connID, err := c.oobService.AcceptInvitation(&cast, myLabel, msg.RouterConnections)
+	connID, err := c.oobService.AcceptInvitation(&cast, &EventOptions{
+		Label:       myLabel,
+		Connections: msg.RouterConnections,
+		ReuseConn:   msg.ReuseConnection,
+		ReuseAny:    msg.ReuseAnyConnection,
+	})
 	if err != nil {
 		return "", fmt.Errorf("out-of-band service failed to accept invitation : %w", err)
 	}
@@ -335,6 +415,57 @@ func WithRouterConnections(conn ...string) MessageOption {
 	}
 }
 
+// WithAttachments allows you to attach the requests (e.g present proof request) to the invitation.
+func WithAttachments(attachments ...*decorator.Attachment) MessageOption {
+	return func(m *message) error {
+		m.Attachments = append(m.Attachments, attachments...)
+
+		return nil
+	}
+}
+
+// WithMaxUses limits the number of did-exchange requests accepted for the invitation.
+func WithMaxUses(maxUses int) MessageOption {
+	return func(m *message) error {
+		if maxUses < 0 {
+			return fmt.Errorf("invalid max uses %d", maxUses)
+		}
+
+		m.MaxUses = maxUses
+
+		return nil
+	}
+}
+
+// WithExpiry sets the time after which the did-exchange requests for the invitation are not accepted.
+func WithExpiry(expires time.Time) MessageOption {
+	return func(m *message) error {
+		m.Expires = &expires
+
+		return nil
+	}
+}
+
+// ReuseConnection allows you to reuse the existing connection instead of the new handshake
+// when accepting the invitation.
+func ReuseConnection(connID string) MessageOption {
+	return func(m *message) error {
+		m.ReuseConnection = connID
+
+		return nil
+	}
+}
+
+// ReuseAnyConnection allows you to reuse any existing connection with the DIDs of the invitation's services
+// when accepting the invitation.
+func ReuseAnyConnection() MessageOption {
+	return func(m *message) error {
+		m.ReuseAnyConnection = true
+
+		return nil
+	}
+}
+
 // WithServices allows you to specify service entries to include in the request message.
 // Each entry must be either a valid DID (string) or a `service` object.
 func WithServices(svcs ...interface{}) MessageOption {
@@ -26,6 +26,7 @@ import (
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 // Ensure Client can emit events.
@@ -248,7 +249,7 @@ func TestCreateInvitation(t *testing.T) {
 		require.NoError(t, err)
 		inv, err := c.CreateInvitation(nil)
 		require.NoError(t, err)
-		require.Equal(t, "https://didcomm.org/oob-invitation/1.0/invitation", inv.Type)
+		require.Equal(t, "https://didcomm.org/out-of-band/1.0/invitation", inv.Type)
 	})
 	t.Run("sets explicit protocols", func(t *testing.T) {
 		expected := []string{"protocol1", "protocol2"}
@@ -256,14 +257,23 @@ func TestCreateInvitation(t *testing.T) {
 		require.NoError(t, err)
 		inv, err := c.CreateInvitation(expected)
 		require.NoError(t, err)
-		require.Equal(t, expected, inv.Protocols)
+		require.Equal(t, expected, inv.HandshakeProtocols)
 	})
 	t.Run("sets default protocols", func(t *testing.T) {
 		c, err := New(withTestProvider())
 		require.NoError(t, err)
 		inv, err := c.CreateInvitation(nil)
 		require.NoError(t, err)
-		require.Equal(t, []string{didexchange.PIURI}, inv.Protocols)
+		require.Equal(t, []string{didexchange.PIURI}, inv.HandshakeProtocols)
+	})
+	t.Run("sets no default protocols if requests are attached", func(t *testing.T) {
+		c, err := New(withTestProvider())
+		require.NoError(t, err)
+		attachment := dummyAttachment(t)
+		inv, err := c.CreateInvitation(nil, WithAttachments(attachment))
+		require.NoError(t, err)
+		require.Empty(t, inv.HandshakeProtocols)
+		require.Equal(t, []*decorator.Attachment{attachment}, inv.Requests)
 	})
 	t.Run("includes the diddoc Service block returned by provider", func(t *testing.T) {
 		expected := &did.Service{
@@ -366,6 +376,105 @@ func TestCreateInvitation(t *testing.T) {
 	})
 }
 
+func TestClient_InvitationPolicy(t *testing.T) {
+	t.Run("saves the policy of the invitation", func(t *testing.T) {
+		var saved *connection.InvitationPolicy
+
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			savePolicyFunc: func(policy *connection.InvitationPolicy) error {
+				saved = policy
+
+				return nil
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		expires := time.Now().Add(time.Hour)
+		inv, err := c.CreateInvitation(nil, WithMaxUses(10), WithExpiry(expires))
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+		require.Equal(t, inv.ID, saved.InvitationID)
+		require.Equal(t, 10, saved.MaxUses)
+		require.Equal(t, expires, *saved.Expires)
+	})
+	t.Run("no policy saved for unlimited invitations", func(t *testing.T) {
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			savePolicyFunc: func(*connection.InvitationPolicy) error {
+				return errors.New("unexpected call")
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation(nil)
+		require.NoError(t, err)
+	})
+	t.Run("wraps error from outofband service", func(t *testing.T) {
+		expected := errors.New("test")
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			savePolicyFunc: func(*connection.InvitationPolicy) error {
+				return expected
+			},
+			getPolicyFunc: func(string) (*connection.InvitationPolicy, error) {
+				return nil, expected
+			},
+			revokeFunc: func(string) error {
+				return expected
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation(nil, WithMaxUses(1))
+		require.True(t, errors.Is(err, expected))
+
+		_, err = c.GetInvitationPolicy("inv-1")
+		require.True(t, errors.Is(err, expected))
+
+		err = c.RevokeInvitation("inv-1")
+		require.True(t, errors.Is(err, expected))
+	})
+	t.Run("returns and revokes the policy", func(t *testing.T) {
+		revoked := ""
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			getPolicyFunc: func(id string) (*connection.InvitationPolicy, error) {
+				return &connection.InvitationPolicy{InvitationID: id, Uses: 2}, nil
+			},
+			revokeFunc: func(id string) error {
+				revoked = id
+
+				return nil
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		policy, err := c.GetInvitationPolicy("inv-1")
+		require.NoError(t, err)
+		require.Equal(t, 2, policy.Uses)
+
+		require.NoError(t, c.RevokeInvitation("inv-1"))
+		require.Equal(t, "inv-1", revoked)
+	})
+	t.Run("rejects negative max uses", func(t *testing.T) {
+		c, err := New(withTestProvider())
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation(nil, WithMaxUses(-1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid max uses")
+	})
+}
+
 func TestClient_ActionContinue(t *testing.T) {
 	const (
 		PIID  = "piid"
@@ -485,14 +594,19 @@ func TestAcceptInvitation(t *testing.T) {
 		provider := withTestProvider()
 		provider.ServiceMap = map[string]interface{}{
 			outofband.Name: &stubOOBService{
-				acceptInvFunc: func(*outofband.Invitation, string, []string) (string, error) {
+				acceptInvFunc: func(_ *outofband.Invitation, opts outofband.Options) (string, error) {
+					require.Equal(t, "label", opts.MyLabel())
+					require.Equal(t, "conn-id", opts.ReuseConnection())
+					require.True(t, opts.ReuseAnyConnection())
+
 					return expected, nil
 				},
 			},
 		}
 		c, err := New(provider)
 		require.NoError(t, err)
-		result, err := c.AcceptInvitation(&Invitation{}, "")
+		result, err := c.AcceptInvitation(&Invitation{}, "label",
+			ReuseConnection("conn-id"), ReuseAnyConnection())
 		require.NoError(t, err)
 		require.Equal(t, expected, result)
 	})
@@ -501,7 +615,7 @@ func TestAcceptInvitation(t *testing.T) {
 		provider := withTestProvider()
 		provider.ServiceMap = map[string]interface{}{
 			outofband.Name: &stubOOBService{
-				acceptInvFunc: func(*outofband.Invitation, string, []string) (string, error) {
+				acceptInvFunc: func(*outofband.Invitation, outofband.Options) (string, error) {
 					return "", expected
 				},
 			},
@@ -564,12 +678,15 @@ func withTestProvider() *mockprovider.Provider {
 type stubOOBService struct {
 	service.Event
 	acceptReqFunc      func(*outofband.Request, string, []string) (string, error)
-	acceptInvFunc      func(*outofband.Invitation, string, []string) (string, error)
+	acceptInvFunc      func(*outofband.Invitation, outofband.Options) (string, error)
 	saveReqFunc        func(*outofband.Request) error
 	saveInvFunc        func(*outofband.Invitation) error
 	actionsFunc        func() ([]outofband.Action, error)
 	actionContinueFunc func(string, outofband.Options) error
 	actionStopFunc     func(piid string, err error) error
+	savePolicyFunc     func(*connection.InvitationPolicy) error
+	getPolicyFunc      func(string) (*connection.InvitationPolicy, error)
+	revokeFunc         func(string) error
 }
 
 func (s *stubOOBService) AcceptRequest(request *outofband.Request, myLabel string, conns []string) (string, error) {
@@ -580,9 +697,9 @@ func (s *stubOOBService) AcceptRequest(request *outofband.Request, myLabel strin
 	return "", nil
 }
 
-func (s *stubOOBService) AcceptInvitation(i *outofband.Invitation, myLabel string, conns []string) (string, error) {
+func (s *stubOOBService) AcceptInvitation(i *outofband.Invitation, opts outofband.Options) (string, error) {
 	if s.acceptInvFunc != nil {
-		return s.acceptInvFunc(i, myLabel, conns)
+		return s.acceptInvFunc(i, opts)
 	}
 
 	return "", nil
@@ -627,3 +744,27 @@ func (s *stubOOBService) ActionStop(piid string, err error) error {
 
 	return nil
 }
+
+func (s *stubOOBService) SaveInvitationPolicy(policy *connection.InvitationPolicy) error {
+	if s.savePolicyFunc != nil {
+		return s.savePolicyFunc(policy)
+	}
+
+	return nil
+}
+
+func (s *stubOOBService) GetInvitationPolicy(invitationID string) (*connection.InvitationPolicy, error) {
+	if s.getPolicyFunc != nil {
+		return s.getPolicyFunc(invitationID)
+	}
+
+	return nil, nil
+}
+
+func (s *stubOOBService) RevokeInvitation(invitationID string) error {
+	if s.revokeFunc != nil {
+		return s.revokeFunc(invitationID)
+	}
+
+	return nil
+}
@@ -23,6 +23,11 @@ SPDX-License-Identifier: Apache-2.0
 // client.AcceptInvitation() respectively. These return the ID of the newly-created connection
 // record.
 //
+// Invitations can be shared as URLs: outofband.InvitationURL() encodes the invitation in the `oob`
+// query parameter and outofband.ParseInvitationURL() decodes it, resolving shortened URLs if needed.
+// Pass outofband.ReuseAnyConnection() to client.AcceptInvitation() to reuse the existing connection
+// with the inviter instead of creating a new one.
+//
 // If you're expecting to receive out-of-band invitations or requests via a DIDComm channel then
 // you should register to the action event stream and the state event stream:
 //
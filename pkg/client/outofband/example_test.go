@@ -318,7 +318,7 @@ func getContext(agent string) *mockprovider.Provider {
 
 					return "xyz", nil
 				},
-				acceptInvFunc: func(i *outofband.Invitation, myLabel string, _ []string) (string, error) {
+				acceptInvFunc: func(i *outofband.Invitation, _ outofband.Options) (string, error) {
 					agentActions[i.Label] <- service.DIDCommAction{
 						ProtocolName: didsvc.DIDExchange,
 						Message: service.NewDIDCommMsgMap(&didsvc.Request{
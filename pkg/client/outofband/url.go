@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+)
+
+const (
+	// oobParam is the query parameter of the invitation URL which carries the base64url encoded invitation.
+	oobParam = "oob"
+	// defaultHTTPTimeout is the timeout of the default HTTP client which resolves the shortened URL.
+	defaultHTTPTimeout = 10 * time.Second
+	// maxInvitationSize limits the size of the response body which is read as the invitation.
+	maxInvitationSize = 1 << 20
+)
+
+// nolint:gochecknoglobals
+var logger = log.New("aries-framework/client/outofband")
+
+var errNoOOBParam = errors.New("the oob query parameter was not found")
+
+// URLOption configures the resolution of the shortened invitation URL.
+type URLOption func(*urlOptions)
+
+type urlOptions struct {
+	client *http.Client
+}
+
+// WithHTTPClient allows you to specify the HTTP client which resolves the shortened invitation URL.
+// By default the client with the 10 seconds timeout is used.
+func WithHTTPClient(client *http.Client) URLOption {
+	return func(opts *urlOptions) {
+		opts.client = client
+	}
+}
+
+// InvitationURL returns the invitation URL: the base URL with the base64url encoded invitation
+// in the `oob` query parameter (e.g https://example.com/path?oob=eyJAdHlwZSI6...).
+func InvitationURL(baseURL string, inv *Invitation) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("parse base URL: %w", err)
+	}
+
+	raw, err := json.Marshal(inv)
+	if err != nil {
+		return "", fmt.Errorf("marshal invitation: %w", err)
+	}
+
+	query := u.Query()
+	query.Set(oobParam, base64.RawURLEncoding.EncodeToString(raw))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// ParseInvitationURL returns the invitation of the invitation URL.
+// The shortened URL (without the `oob` query parameter) is resolved with the HTTP GET request,
+// the response either redirects to the invitation URL or has the invitation in the JSON body.
+func ParseInvitationURL(invitationURL string, opts ...URLOption) (*Invitation, error) {
+	inv, err := parseInvitationURL(invitationURL)
+	if !errors.Is(err, errNoOOBParam) {
+		return inv, err
+	}
+
+	options := &urlOptions{client: &http.Client{Timeout: defaultHTTPTimeout}}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return resolveShortenedURL(invitationURL, options.client)
+}
+
+func parseInvitationURL(invitationURL string) (*Invitation, error) {
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse invitation URL: %w", err)
+	}
+
+	encoded := u.Query().Get(oobParam)
+	if encoded == "" {
+		return nil, errNoOOBParam
+	}
+
+	// the padding is optional
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, fmt.Errorf("decode invitation: %w", err)
+	}
+
+	return unmarshalInvitation(raw)
+}
+
+func resolveShortenedURL(shortURL string, client *http.Client) (*Invitation, error) {
+	// the redirect is not followed, the invitation is read from its location
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := noRedirect.Get(shortURL) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("resolve shortened URL: %w", err)
+	}
+
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logger.Errorf("failed to close response body: %s", errClose)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest:
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return nil, errors.New("resolve shortened URL: the redirect location was not provided")
+		}
+
+		return parseInvitationURL(location)
+	case resp.StatusCode == http.StatusOK:
+		raw, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxInvitationSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("resolve shortened URL: read body: %w", err)
+		}
+
+		if len(raw) > maxInvitationSize {
+			return nil, fmt.Errorf("resolve shortened URL: the invitation exceeds %d bytes", maxInvitationSize)
+		}
+
+		return unmarshalInvitation(raw)
+	default:
+		return nil, fmt.Errorf("resolve shortened URL: unexpected status code %d", resp.StatusCode)
+	}
+}
+
+func unmarshalInvitation(raw []byte) (*Invitation, error) {
+	inv := &Invitation{}
+
+	if err := json.Unmarshal(raw, inv); err != nil {
+		return nil, fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	if inv.Type != InvitationMsgType && inv.Type != LegacyInvitationMsgType {
+		return nil, fmt.Errorf("unexpected invitation type %s", inv.Type)
+	}
+
+	return inv, nil
+}
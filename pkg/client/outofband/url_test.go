@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationURL(t *testing.T) {
+	inv := &Invitation{
+		ID:                 uuid.New().String(),
+		Type:               InvitationMsgType,
+		Label:              "Faber College",
+		HandshakeProtocols: []string{"https://didcomm.org/didexchange/1.0"},
+		Service:            []interface{}{"did:sov:LjgpST2rjsoxYegQDRm7EL"},
+	}
+
+	u, err := InvitationURL("https://example.com/path?a=b", inv)
+	require.NoError(t, err)
+	require.Contains(t, u, "https://example.com/path?")
+	require.Contains(t, u, "a=b")
+
+	parsed, err := ParseInvitationURL(u)
+	require.NoError(t, err)
+	require.Equal(t, inv, parsed)
+
+	t.Run("accepts the padded invitation", func(t *testing.T) {
+		raw, err := json.Marshal(inv)
+		require.NoError(t, err)
+
+		parsed, err := ParseInvitationURL("https://example.com?oob=" + base64.URLEncoding.EncodeToString(raw))
+		require.NoError(t, err)
+		require.Equal(t, inv, parsed)
+	})
+
+	t.Run("accepts the pre-release invitation", func(t *testing.T) {
+		raw := []byte(`{"@id":"legacy","@type":"https://didcomm.org/oob-invitation/1.0/invitation",` +
+			`"service":["did:example:123"],"protocols":["https://didcomm.org/didexchange/1.0"]}`)
+
+		parsed, err := ParseInvitationURL("https://example.com?oob=" + base64.RawURLEncoding.EncodeToString(raw))
+		require.NoError(t, err)
+		require.Equal(t, LegacyInvitationMsgType, parsed.Type)
+		require.Equal(t, []string{"https://didcomm.org/didexchange/1.0"}, parsed.HandshakeProtocols)
+	})
+
+	t.Run("fails to parse the invalid invitation", func(t *testing.T) {
+		_, err := ParseInvitationURL("https://example.com?oob=!")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode invitation")
+
+		_, err = ParseInvitationURL("https://example.com?oob=" + base64.RawURLEncoding.EncodeToString([]byte(`{}`)))
+		require.EqualError(t, err, "unexpected invitation type ")
+
+		_, err = InvitationURL(":", inv)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse base URL")
+	})
+
+	t.Run("resolves the shortened URL", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, u, http.StatusFound)
+		})
+		mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(inv))
+		})
+		mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusFound)
+		})
+		mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write(make([]byte, maxInvitationSize+1))
+			require.NoError(t, err)
+		})
+		mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		})
+
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		parsed, err := ParseInvitationURL(server.URL+"/redirect", WithHTTPClient(server.Client()))
+		require.NoError(t, err)
+		require.Equal(t, inv, parsed)
+
+		parsed, err = ParseInvitationURL(server.URL + "/json")
+		require.NoError(t, err)
+		require.Equal(t, inv, parsed)
+
+		_, err = ParseInvitationURL(server.URL + "/empty")
+		require.EqualError(t, err, "resolve shortened URL: the redirect location was not provided")
+
+		_, err = ParseInvitationURL(server.URL + "/large")
+		require.EqualError(t, err, "resolve shortened URL: the invitation exceeds 1048576 bytes")
+
+		client := server.Client()
+		client.Timeout = time.Millisecond
+
+		_, err = ParseInvitationURL(server.URL+"/slow", WithHTTPClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Client.Timeout exceeded")
+
+		_, err = ParseInvitationURL(server.URL + "/unknown")
+		require.EqualError(t, err, "resolve shortened URL: unexpected status code 404")
+
+		_, err = ParseInvitationURL("http://[::1]:namedport")
+		require.Error(t, err)
+	})
+}
@@ -24,6 +24,8 @@ type (
 	ProposePresentation presentproof.ProposePresentation
 	// Action contains helpful information about action.
 	Action presentproof.Action
+	// ProtocolInstance is the record of the protocol instance along with its history.
+	ProtocolInstance presentproof.ProtocolInstance
 )
 
 var (
@@ -42,6 +44,9 @@ type ProtocolService interface {
 	Actions() ([]presentproof.Action, error)
 	ActionContinue(piID string, opt presentproof.Opt) error
 	ActionStop(piID string, err error) error
+	ProtocolInstances() ([]*presentproof.ProtocolInstance, error)
+	ProtocolInstance(piID string) (*presentproof.ProtocolInstance, error)
+	DeleteProtocolInstance(piID string) error
 }
 
 // Client enable access to presentproof API
@@ -84,6 +89,38 @@ func (c *Client) Actions() ([]Action, error) {
 	return result, nil
 }
 
+// ProtocolInstances returns the records of the in-flight and completed protocol instances.
+func (c *Client) ProtocolInstances() ([]ProtocolInstance, error) {
+	instances, err := c.service.ProtocolInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ProtocolInstance, len(instances))
+	for i, instance := range instances {
+		result[i] = ProtocolInstance(*instance)
+	}
+
+	return result, nil
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piID.
+func (c *Client) ProtocolInstance(piID string) (*ProtocolInstance, error) {
+	instance, err := c.service.ProtocolInstance(piID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ProtocolInstance(*instance)
+
+	return &result, nil
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piID.
+func (c *Client) DeleteProtocolInstance(piID string) error {
+	return c.service.DeleteProtocolInstance(piID)
+}
+
 // SendRequestPresentation is used by the Verifier to send a request presentation.
 // It returns the threadID of the new instance of the protocol.
 func (c *Client) SendRequestPresentation(msg *RequestPresentation, myDID, theirDID string) (string, error) {
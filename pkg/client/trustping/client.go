@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+)
+
+// provider contains dependencies for the trust ping protocol and is typically created by using aries.Context().
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// Result contains the outcome of the ping.
+type Result = trustping.Result
+
+// Client enables access to trust ping api.
+type Client struct {
+	service.Event
+	trustPingSvc protocolService
+	options      []trustping.ClientOption
+}
+
+// protocolService defines trust ping service.
+type protocolService interface {
+	// DIDComm service
+	service.DIDComm
+
+	// Ping sends the ping over the connection and waits for the response
+	Ping(connectionID string, options ...trustping.ClientOption) (*Result, error)
+}
+
+// WithTimeout option is for definition timeout value waiting for the ping response.
+func WithTimeout(t time.Duration) trustping.ClientOption {
+	return func(opts *trustping.ClientOptions) {
+		opts.Timeout = t
+	}
+}
+
+// New returns new instance of trust ping client. The options are applied to every ping.
+func New(ctx provider, options ...trustping.ClientOption) (*Client, error) {
+	svc, err := ctx.Service(trustping.TrustPing)
+	if err != nil {
+		return nil, err
+	}
+
+	trustPingSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to trust ping service failed")
+	}
+
+	return &Client{
+		Event:        trustPingSvc,
+		trustPingSvc: trustPingSvc,
+		options:      options,
+	}, nil
+}
+
+// Ping sends a trust ping to the agent on the other end of the connection and waits for the response.
+// The options override the ones the client was created with (e.g WithTimeout). This function blocks
+// until the response is received or it times out.
+func (c *Client) Ping(connectionID string, options ...trustping.ClientOption) (*Result, error) {
+	opts := append(append([]trustping.ClientOption{}, c.options...), options...)
+
+	result, err := c.trustPingSvc.Ping(connectionID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("ping: %w", err)
+	}
+
+	return result, nil
+}
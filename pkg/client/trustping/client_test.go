@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+	mocktrustping "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/trustping"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+// Ensure Client can emit events.
+var _ service.Event = (*Client)(nil)
+
+// Ensure the service implements the client interface.
+var _ protocolService = (*trustping.Service)(nil)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, c)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: fmt.Errorf("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to trust ping service failed")
+	})
+
+	t.Run("test timeout is applied to options", func(t *testing.T) {
+		timeout := 1 * time.Second
+
+		option := WithTimeout(timeout)
+		opts := &trustping.ClientOptions{}
+		option(opts)
+
+		require.Equal(t, timeout, opts.Timeout)
+	})
+}
+
+func TestClient_Ping(t *testing.T) {
+	t.Run("test ping - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mocktrustping.MockTrustPingSvc{
+				PingFunc: func(connectionID string, options ...trustping.ClientOption) (*Result, error) {
+					require.Equal(t, "conn", connectionID)
+
+					opts := &trustping.ClientOptions{}
+					for _, option := range options {
+						option(opts)
+					}
+
+					require.Equal(t, time.Minute, opts.Timeout)
+
+					return &Result{RoundTripTime: time.Millisecond}, nil
+				},
+			},
+		}, WithTimeout(time.Second))
+		require.NoError(t, err)
+
+		result, err := c.Ping("conn", WithTimeout(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, time.Millisecond, result.RoundTripTime)
+	})
+
+	t.Run("test ping - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mocktrustping.MockTrustPingSvc{
+				PingFunc: func(string, ...trustping.ClientOption) (*Result, error) {
+					return nil, errors.New("ping error")
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = c.Ping("conn")
+		require.EqualError(t, err, "ping: ping error")
+	})
+}
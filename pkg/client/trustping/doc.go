@@ -0,0 +1,11 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package trustping enables the agent to check whether a connection is alive by sending a trust ping
+// (https://github.com/hyperledger/aries-rfcs/tree/master/features/0048-trust-ping).
+// The agent answers the pings it receives automatically; the time the last ping or response was seen and
+// the round-trip time of the last ping are recorded on the connection record.
+package trustping
@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+// Field names used to correlate DIDComm protocol flows across agents in logs.
+const (
+	// FieldThreadID is the DIDComm message thread ID (~thread.thid).
+	FieldThreadID = "thid"
+	// FieldParentThreadID is the DIDComm message parent thread ID (~thread.pthid).
+	FieldParentThreadID = "pthid"
+	// FieldMessageID is the DIDComm message ID (@id).
+	FieldMessageID = "msgID"
+	// FieldMessageType is the DIDComm message type (@type).
+	FieldMessageType = "msgType"
+	// FieldConnectionID is the ID of the connection record the message belongs to.
+	FieldConnectionID = "connectionID"
+	// FieldProtocol is the name of the protocol handling the message.
+	FieldProtocol = "protocol"
+	// FieldState is the current state of the protocol instance.
+	FieldState = "state"
+)
+
+// Fields contains structured key-value pairs attached to logged lines.
+type Fields map[string]interface{}
+
+// WithFields returns a logger of the same module which attaches given fields
+// (merged with the fields of the current logger) to every logged line.
+// Default logger renders fields as JSON object, custom loggers may implement
+// 'WithFields(map[string]interface{}) Logger' to receive them as they are.
+// The underlying logger of the module is resolved once and shared with the derived loggers.
+func (l *Log) WithFields(fields Fields) *Log {
+	root := l
+	if l.root != nil {
+		root = l.root
+	}
+
+	return &Log{module: l.module, fields: mergeFields(l.fields, fields), root: root}
+}
+
+// Fields returns fields attached by the logger.
+func (l *Log) Fields() Fields {
+	return mergeFields(l.fields)
+}
+
+// mergeFields returns a new set of fields, latter values override former ones, empty values are skipped.
+func mergeFields(sets ...Fields) Fields {
+	merged := Fields{}
+
+	for _, fields := range sets {
+		for k, v := range fields {
+			if v == nil || v == "" {
+				continue
+			}
+
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_WithFields(t *testing.T) {
+	defer func() { loggerProviderOnce = sync.Once{} }()
+
+	const module = "sample-module-fields"
+
+	logger := New(module).WithFields(Fields{FieldThreadID: "thID", FieldState: ""})
+	require.Equal(t, Fields{FieldThreadID: "thID"}, logger.Fields())
+
+	logger = logger.WithFields(Fields{FieldThreadID: "thID2", FieldProtocol: "protocol"})
+	require.Equal(t, Fields{FieldThreadID: "thID2", FieldProtocol: "protocol"}, logger.Fields())
+
+	// forces logger instance loading
+	logger.Debugf("sample output")
+	require.NotNil(t, logger.instance)
+
+	// the logger of the module is resolved once by the root logger
+	root := logger.root
+	require.NotNil(t, root.instance)
+	require.Equal(t, root, logger.WithFields(Fields{FieldState: "state"}).root)
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/aries-framework-go/pkg/internal/common/logging/metadata"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/logging/modlog"
 )
 
 //nolint:lll
@@ -24,7 +25,10 @@ const (
 type Log struct {
 	instance Logger
 	module   string
-	once     sync.Once
+	fields   Fields
+	// root is the logger of the module the logger with fields was derived from.
+	root *Log
+	once sync.Once
 }
 
 // New creates and returns a Logger implementation based on given module name.
@@ -69,7 +73,13 @@ func (l *Log) Errorf(msg string, args ...interface{}) {
 
 func (l *Log) logger() Logger {
 	l.once.Do(func() {
-		l.instance = loggerProvider().GetLogger(l.module)
+		if l.root != nil {
+			l.instance = modlog.WithFields(l.root.logger(), l.fields)
+
+			return
+		}
+
+		l.instance = modlog.WithFields(loggerProvider().GetLogger(l.module), l.fields)
 	})
 
 	return l.instance
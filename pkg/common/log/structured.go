@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/logging/modlog"
+)
+
+// Encoding defines the format of lines written by the structured logger.
+type Encoding string
+
+// Encodings supported by the structured logger.
+const (
+	// JSONEncoding writes every line as a JSON object.
+	JSONEncoding Encoding = "json"
+	// LogfmtEncoding writes every line as logfmt key=value pairs.
+	LogfmtEncoding Encoding = "logfmt"
+)
+
+// ParseEncoding returns the encoding from a string representation.
+func ParseEncoding(encoding string) (Encoding, error) {
+	for _, e := range []Encoding{JSONEncoding, LogfmtEncoding} {
+		if strings.EqualFold(string(e), encoding) {
+			return e, nil
+		}
+	}
+
+	return "", fmt.Errorf("logger: invalid encoding '%s'", encoding)
+}
+
+// StructuredProviderOpt configures the structured logger provider.
+type StructuredProviderOpt func(p *StructuredProvider)
+
+// WithEncoding sets the encoding of logged lines, defaults to JSONEncoding.
+func WithEncoding(encoding Encoding) StructuredProviderOpt {
+	return func(p *StructuredProvider) {
+		p.encoding = encoding
+	}
+}
+
+// WithOutput sets the output destination of logged lines, defaults to os.Stdout.
+func WithOutput(output io.Writer) StructuredProviderOpt {
+	return func(p *StructuredProvider) {
+		p.output = output
+	}
+}
+
+// WithDebugSampling limits DEBUG lines of a noisy module. Within every tick interval the first
+// 'first' lines are logged, after that only every 'thereafter'-th line is logged.
+func WithDebugSampling(module string, first, thereafter int, tick time.Duration) StructuredProviderOpt {
+	return func(p *StructuredProvider) {
+		p.samplers[module] = modlog.NewSampler(first, thereafter, tick)
+	}
+}
+
+// StructuredProvider is a logger provider which writes structured lines (JSON or logfmt) containing
+// timestamp, level, module, caller, message and fields attached by Log.WithFields.
+// Levels and caller info are configured per module in the same way as for the default logger
+// (see SetLevel and ShowCallerInfo).
+//
+// Usage:
+//  log.Initialize(log.NewStructuredProvider(log.WithEncoding(log.LogfmtEncoding)))
+type StructuredProvider struct {
+	encoding Encoding
+	output   io.Writer
+	samplers map[string]*modlog.Sampler
+
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewStructuredProvider returns new structured logger provider.
+func NewStructuredProvider(opts ...StructuredProviderOpt) *StructuredProvider {
+	p := &StructuredProvider{
+		encoding: JSONEncoding,
+		output:   os.Stdout,
+		samplers: map[string]*modlog.Sampler{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// GetLogger returns structured logger implementation for the module.
+func (p *StructuredProvider) GetLogger(module string) Logger {
+	encode := modlog.EncodeJSON
+	if p.encoding == LogfmtEncoding {
+		encode = modlog.EncodeLogfmt
+	}
+
+	return modlog.NewStructLog(module, encode, p.sharedOutput(), p.samplers[module])
+}
+
+// sharedOutput returns the writer shared by all loggers of the provider, so lines are never interleaved.
+func (p *StructuredProvider) sharedOutput() io.Writer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.writer == nil {
+		p.writer = &lockedWriter{out: p.output}
+	}
+
+	return p.writer
+}
+
+type lockedWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.out.Write(p)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredProvider(t *testing.T) {
+	defer func() { loggerProviderOnce = sync.Once{} }()
+
+	const module = "sample-module-structured"
+
+	var out bytes.Buffer
+
+	Initialize(NewStructuredProvider(WithOutput(&out), WithDebugSampling(module, 1, 0, time.Minute)))
+
+	SetLevel(module, DEBUG)
+	HideCallerInfo(module, INFO)
+
+	logger := New(module)
+	logger.WithFields(Fields{FieldThreadID: "thID", FieldProtocol: "issue-credential"}).Infof("state %s", "done")
+	logger.Debugf("first debug")
+	logger.Debugf("second debug")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	delete(line, "ts")
+
+	require.Equal(t, map[string]interface{}{
+		"level":       "INFO",
+		"module":      module,
+		"msg":         "state done",
+		FieldThreadID: "thID",
+		FieldProtocol: "issue-credential",
+	}, line)
+
+	require.Contains(t, lines[1], `"msg":"first debug"`)
+}
+
+func TestStructuredProvider_Logfmt(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := NewStructuredProvider(WithOutput(&out), WithEncoding(LogfmtEncoding)).GetLogger("logfmt-module")
+	logger.Errorf("failed")
+
+	require.Contains(t, out.String(), "level=ERROR module=logfmt-module")
+	require.Contains(t, out.String(), "msg=failed\n")
+}
+
+func TestParseEncoding(t *testing.T) {
+	encoding, err := ParseEncoding("JSON")
+	require.NoError(t, err)
+	require.Equal(t, JSONEncoding, encoding)
+
+	encoding, err = ParseEncoding("logfmt")
+	require.NoError(t, err)
+	require.Equal(t, LogfmtEncoding, encoding)
+
+	_, err = ParseEncoding("text")
+	require.EqualError(t, err, "logger: invalid encoding 'text'")
+}
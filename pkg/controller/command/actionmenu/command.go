@@ -0,0 +1,382 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+)
+
+var logger = log.New("aries-framework/controller/actionmenu")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.ActionMenu)
+	// ActionsErrorCode is for failures in actions command.
+	ActionsErrorCode
+	// SendMenuErrorCode is for failures in send menu command.
+	SendMenuErrorCode
+	// RequestMenuErrorCode is for failures in request menu command.
+	RequestMenuErrorCode
+	// ActiveMenuErrorCode is for failures in active menu command.
+	ActiveMenuErrorCode
+	// PerformErrorCode is for failures in perform command.
+	PerformErrorCode
+	// AcceptMenuRequestErrorCode is for failures in accept menu request command.
+	AcceptMenuRequestErrorCode
+	// DeclineMenuRequestErrorCode is for failures in decline menu request command.
+	DeclineMenuRequestErrorCode
+	// AcceptPerformErrorCode is for failures in accept perform command.
+	AcceptPerformErrorCode
+	// DeclinePerformErrorCode is for failures in decline perform command.
+	DeclinePerformErrorCode
+)
+
+// constants for the action menu controller.
+const (
+	// command name.
+	CommandName = "actionmenu"
+
+	// command methods.
+	Actions            = "Actions"
+	SendMenu           = "SendMenu"
+	RequestMenu        = "RequestMenu"
+	ActiveMenu         = "ActiveMenu"
+	Perform            = "Perform"
+	AcceptMenuRequest  = "AcceptMenuRequest"
+	DeclineMenuRequest = "DeclineMenuRequest"
+	AcceptPerform      = "AcceptPerform"
+	DeclinePerform     = "DeclinePerform"
+
+	// error messages.
+	errEmptyConnID = "empty connectionID"
+	errEmptyMenu   = "empty menu"
+	errEmptyName   = "empty name"
+	errEmptyPIID   = "empty piid"
+
+	// log constants.
+	connectionID  = "connectionID"
+	successString = "success"
+
+	_actions = "_actions"
+	_states  = "_states"
+)
+
+// Command is controller command for action menu.
+type Command struct {
+	client *actionmenu.Client
+}
+
+// New returns new action menu controller command instance.
+func New(ctx actionmenu.Provider, notifier command.Notifier) (*Command, error) {
+	client, err := actionmenu.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create action menu client : %w", err)
+	}
+
+	// creates action channel
+	actions := make(chan service.DIDCommAction)
+	// registers action channel to listen for events
+	if err := client.RegisterActionEvent(actions); err != nil {
+		return nil, fmt.Errorf("register action event: %w", err)
+	}
+
+	// creates state channel
+	states := make(chan service.StateMsg)
+	// registers state channel to listen for events
+	if err := client.RegisterMsgEvent(states); err != nil {
+		return nil, fmt.Errorf("register msg event: %w", err)
+	}
+
+	obs := webnotifier.NewObserver(notifier)
+	obs.RegisterAction(protocol.ActionMenu+_actions, actions)
+	obs.RegisterStateMsg(protocol.ActionMenu+_states, states)
+
+	return &Command{client: client}, nil
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (c *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, Actions, c.Actions),
+		cmdutil.NewCommandHandler(CommandName, SendMenu, c.SendMenu),
+		cmdutil.NewCommandHandler(CommandName, RequestMenu, c.RequestMenu),
+		cmdutil.NewCommandHandler(CommandName, ActiveMenu, c.ActiveMenu),
+		cmdutil.NewCommandHandler(CommandName, Perform, c.Perform),
+		cmdutil.NewCommandHandler(CommandName, AcceptMenuRequest, c.AcceptMenuRequest),
+		cmdutil.NewCommandHandler(CommandName, DeclineMenuRequest, c.DeclineMenuRequest),
+		cmdutil.NewCommandHandler(CommandName, AcceptPerform, c.AcceptPerform),
+		cmdutil.NewCommandHandler(CommandName, DeclinePerform, c.DeclinePerform),
+	}
+}
+
+// Actions returns pending actions that have not yet to be executed or canceled.
+func (c *Command) Actions(rw io.Writer, _ io.Reader) command.Error {
+	result, err := c.client.Actions()
+	if err != nil {
+		logutil.LogError(logger, CommandName, Actions, err.Error())
+		return command.NewExecuteError(ActionsErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ActionsResponse{
+		Actions: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, Actions, successString)
+
+	return nil
+}
+
+// SendMenu sends the menu to the agent on the other end of the connection.
+func (c *Command) SendMenu(rw io.Writer, req io.Reader) command.Error {
+	var args SendMenuArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, SendMenu, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, SendMenu, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	if args.Menu == nil {
+		logutil.LogDebug(logger, CommandName, SendMenu, errEmptyMenu)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyMenu))
+	}
+
+	id, err := c.client.SendMenu(args.ConnectionID, args.Menu)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SendMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(SendMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &MessageResponse{ID: id}, logger)
+
+	logutil.LogDebug(logger, CommandName, SendMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// RequestMenu asks the agent on the other end of the connection to send its menu.
+func (c *Command) RequestMenu(rw io.Writer, req io.Reader) command.Error {
+	var args RequestMenuArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, RequestMenu, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, RequestMenu, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	id, err := c.client.RequestMenu(args.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RequestMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(RequestMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &MessageResponse{ID: id}, logger)
+
+	logutil.LogDebug(logger, CommandName, RequestMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// ActiveMenu returns the last menu received over the connection.
+func (c *Command) ActiveMenu(rw io.Writer, req io.Reader) command.Error {
+	var args ActiveMenuArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, ActiveMenu, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, ActiveMenu, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	menu, err := c.client.ActiveMenu(args.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ActiveMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(ActiveMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ActiveMenuResponse{Menu: menu}, logger)
+
+	logutil.LogDebug(logger, CommandName, ActiveMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// Perform asks the agent on the other end of the connection to perform the option of the active menu.
+func (c *Command) Perform(rw io.Writer, req io.Reader) command.Error {
+	var args PerformArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, Perform, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, Perform, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	if args.Name == "" {
+		logutil.LogDebug(logger, CommandName, Perform, errEmptyName,
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyName))
+	}
+
+	id, err := c.client.Perform(args.ConnectionID, args.Name, args.Params)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Perform, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(PerformErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &MessageResponse{ID: id}, logger)
+
+	logutil.LogDebug(logger, CommandName, Perform, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// AcceptMenuRequest responds to the menu request with the menu.
+func (c *Command) AcceptMenuRequest(rw io.Writer, req io.Reader) command.Error {
+	var args AcceptArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, AcceptMenuRequest, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, AcceptMenuRequest, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if args.Menu == nil {
+		logutil.LogDebug(logger, CommandName, AcceptMenuRequest, errEmptyMenu)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyMenu))
+	}
+
+	if err := c.client.AcceptMenuRequest(args.PIID, args.Menu); err != nil {
+		logutil.LogError(logger, CommandName, AcceptMenuRequest, err.Error())
+		return command.NewExecuteError(AcceptMenuRequestErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &EmptyResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, AcceptMenuRequest, successString)
+
+	return nil
+}
+
+// DeclineMenuRequest is used to reject the menu request.
+// nolint: dupl
+func (c *Command) DeclineMenuRequest(rw io.Writer, req io.Reader) command.Error {
+	var args DeclineArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, DeclineMenuRequest, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, DeclineMenuRequest, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := c.client.DeclineMenuRequest(args.PIID, args.Reason); err != nil {
+		logutil.LogError(logger, CommandName, DeclineMenuRequest, err.Error())
+		return command.NewExecuteError(DeclineMenuRequestErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &EmptyResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, DeclineMenuRequest, successString)
+
+	return nil
+}
+
+// AcceptPerform acknowledges that the menu option was performed, the optional menu is sent as a response.
+func (c *Command) AcceptPerform(rw io.Writer, req io.Reader) command.Error {
+	var args AcceptArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, AcceptPerform, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, AcceptPerform, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := c.client.AcceptPerform(args.PIID, args.Menu); err != nil {
+		logutil.LogError(logger, CommandName, AcceptPerform, err.Error())
+		return command.NewExecuteError(AcceptPerformErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &EmptyResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, AcceptPerform, successString)
+
+	return nil
+}
+
+// DeclinePerform is used to reject the perform request.
+// nolint: dupl
+func (c *Command) DeclinePerform(rw io.Writer, req io.Reader) command.Error {
+	var args DeclineArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, DeclinePerform, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, DeclinePerform, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := c.client.DeclinePerform(args.PIID, args.Reason); err != nil {
+		logutil.LogError(logger, CommandName, DeclinePerform, err.Error())
+		return command.NewExecuteError(DeclinePerformErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &EmptyResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, DeclinePerform, successString)
+
+	return nil
+}
@@ -0,0 +1,366 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockactionmenu "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/actionmenu"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+const sampleErr = "sample-error"
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+		require.Equal(t, 9, len(cmd.GetHandlers()))
+	})
+
+	t.Run("Create client (error)", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{}, mocknotifier.NewMockNotifier(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create action menu client")
+		require.Nil(t, cmd)
+	})
+}
+
+func TestCommand_Actions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionsFunc: func() ([]actionmenu.Action, error) {
+				return []actionmenu.Action{{PIID: "piid", ConnectionID: "conn"}}, nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.Actions(&b, nil))
+
+		var res ActionsResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Len(t, res.Actions, 1)
+		require.Equal(t, "piid", res.Actions[0].PIID)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionsFunc: func() ([]actionmenu.Action, error) {
+				return nil, errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.Actions(&bytes.Buffer{}, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, ActionsErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_SendMenu(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			SendMenuFunc: func(connID string, menu *actionmenu.Menu) (string, error) {
+				require.Equal(t, "conn", connID)
+				require.Equal(t, "Menu", menu.Title)
+
+				return "msg-id", nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.SendMenu(&b, bytes.NewBufferString(`{"connectionID":"conn","menu":{"title":"Menu"}}`)))
+
+		var res MessageResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, "msg-id", res.ID)
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.SendMenu(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.SendMenu(&bytes.Buffer{}, bytes.NewBufferString(`{"menu":{}}`))
+		require.EqualError(t, cmdErr, errEmptyConnID)
+
+		cmdErr = cmd.SendMenu(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`))
+		require.EqualError(t, cmdErr, errEmptyMenu)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			SendMenuFunc: func(string, *actionmenu.Menu) (string, error) {
+				return "", errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.SendMenu(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn","menu":{}}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SendMenuErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+}
+
+func TestCommand_RequestMenu(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			RequestMenuFunc: func(connID string) (string, error) {
+				require.Equal(t, "conn", connID)
+
+				return "msg-id", nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.RequestMenu(&b, bytes.NewBufferString(`{"connectionID":"conn"}`)))
+		require.Contains(t, b.String(), "msg-id")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			RequestMenuFunc: func(string) (string, error) {
+				return "", errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.RequestMenu(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.RequestMenu(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyConnID)
+
+		cmdErr = cmd.RequestMenu(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`))
+		require.Equal(t, RequestMenuErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_ActiveMenu(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActiveMenuFunc: func(connID string) (*actionmenu.Menu, error) {
+				return &actionmenu.Menu{Title: "Menu"}, nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.ActiveMenu(&b, bytes.NewBufferString(`{"connectionID":"conn"}`)))
+
+		var res ActiveMenuResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, "Menu", res.Menu.Title)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActiveMenuFunc: func(string) (*actionmenu.Menu, error) {
+				return nil, actionmenu.ErrNoActiveMenu
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.ActiveMenu(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.ActiveMenu(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyConnID)
+
+		cmdErr = cmd.ActiveMenu(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`))
+		require.Equal(t, ActiveMenuErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_Perform(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			PerformFunc: func(connID, name string, params map[string]string) (string, error) {
+				require.Equal(t, "conn", connID)
+				require.Equal(t, "opt", name)
+				require.Equal(t, map[string]string{"a": "b"}, params)
+
+				return "msg-id", nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.Perform(&b, bytes.NewBufferString(
+			`{"connectionID":"conn","name":"opt","params":{"a":"b"}}`)))
+		require.Contains(t, b.String(), "msg-id")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			PerformFunc: func(string, string, map[string]string) (string, error) {
+				return "", errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.Perform(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.Perform(&bytes.Buffer{}, bytes.NewBufferString(`{"name":"opt"}`))
+		require.EqualError(t, cmdErr, errEmptyConnID)
+
+		cmdErr = cmd.Perform(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`))
+		require.EqualError(t, cmdErr, errEmptyName)
+
+		cmdErr = cmd.Perform(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn","name":"opt"}`))
+		require.Equal(t, PerformErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_AcceptMenuRequest(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionContinueFunc: func(piID string, opt actionmenu.Opt) error {
+				require.Equal(t, "piid", piID)
+
+				return nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		require.NoError(t, cmd.AcceptMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid","menu":{}}`)))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionContinueFunc: func(string, actionmenu.Opt) error {
+				return errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.AcceptMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.AcceptMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{"menu":{}}`))
+		require.EqualError(t, cmdErr, errEmptyPIID)
+
+		cmdErr = cmd.AcceptMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid"}`))
+		require.EqualError(t, cmdErr, errEmptyMenu)
+
+		cmdErr = cmd.AcceptMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid","menu":{}}`))
+		require.Equal(t, AcceptMenuRequestErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_DeclineMenuRequest(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionStopFunc: func(piID string, err error) error {
+				require.Equal(t, "piid", piID)
+				require.EqualError(t, err, "reason")
+
+				return nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		require.NoError(t, cmd.DeclineMenuRequest(&bytes.Buffer{},
+			bytes.NewBufferString(`{"piid":"piid","reason":"reason"}`)))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionStopFunc: func(string, error) error {
+				return errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.DeclineMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.DeclineMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyPIID)
+
+		cmdErr = cmd.DeclineMenuRequest(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid"}`))
+		require.Equal(t, DeclineMenuRequestErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_AcceptPerform(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		require.NoError(t, cmd.AcceptPerform(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid"}`)))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionContinueFunc: func(string, actionmenu.Opt) error {
+				return errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.AcceptPerform(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.AcceptPerform(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyPIID)
+
+		cmdErr = cmd.AcceptPerform(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid"}`))
+		require.Equal(t, AcceptPerformErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_DeclinePerform(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		require.NoError(t, cmd.DeclinePerform(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid"}`)))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionStopFunc: func(string, error) error {
+				return errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.DeclinePerform(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.DeclinePerform(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyPIID)
+
+		cmdErr = cmd.DeclinePerform(&bytes.Buffer{}, bytes.NewBufferString(`{"piid":"piid"}`))
+		require.Equal(t, DeclinePerformErrorCode, cmdErr.Code())
+	})
+}
+
+func newMockProvider(svc *mockactionmenu.MockActionMenuSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceValue: svc,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/client/actionmenu"
+)
+
+// ActionsResponse model
+//
+// Represents Actions response message
+//
+type ActionsResponse struct {
+	Actions []actionmenu.Action `json:"actions"`
+}
+
+// SendMenuArgs model
+//
+// This is used for sending a menu
+//
+type SendMenuArgs struct {
+	// ConnectionID of the connection the menu is sent over
+	ConnectionID string `json:"connectionID"`
+	// Menu to be sent
+	Menu *actionmenu.Menu `json:"menu"`
+}
+
+// RequestMenuArgs model
+//
+// This is used for requesting a menu
+//
+type RequestMenuArgs struct {
+	// ConnectionID of the connection the menu is requested over
+	ConnectionID string `json:"connectionID"`
+}
+
+// MessageResponse model
+//
+// Represents the ID of the sent message
+//
+type MessageResponse struct {
+	// ID of the sent message
+	ID string `json:"id"`
+}
+
+// ActiveMenuArgs model
+//
+// This is used for getting the active menu of the connection
+//
+type ActiveMenuArgs struct {
+	// ConnectionID of the connection the menu was received over
+	ConnectionID string `json:"connectionID"`
+}
+
+// ActiveMenuResponse model
+//
+// Represents the active menu
+//
+type ActiveMenuResponse struct {
+	Menu *actionmenu.Menu `json:"menu"`
+}
+
+// PerformArgs model
+//
+// This is used for performing the option of the active menu
+//
+type PerformArgs struct {
+	// ConnectionID of the connection the menu was received over
+	ConnectionID string `json:"connectionID"`
+	// Name of the menu option
+	Name string `json:"name"`
+	// Params are the values of the menu option form parameters
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// AcceptArgs model
+//
+// This is used for accepting the menu request or perform actions
+//
+type AcceptArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+	// Menu sent in response to the action (required for the menu request)
+	Menu *actionmenu.Menu `json:"menu,omitempty"`
+}
+
+// DeclineArgs model
+//
+// This is used for declining the menu request or perform actions
+//
+type DeclineArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+	// Reason why the action is declined
+	Reason string `json:"reason"`
+}
+
+// EmptyResponse model
+//
+// Represents an empty response message
+//
+type EmptyResponse struct{}
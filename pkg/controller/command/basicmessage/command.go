@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/basicmessage"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+)
+
+var logger = log.New("aries-framework/controller/basicmessage")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.BasicMessage)
+	// SendMessageErrorCode is for failures in send message command.
+	SendMessageErrorCode
+	// HistoryErrorCode is for failures in history command.
+	HistoryErrorCode
+	// DeleteHistoryErrorCode is for failures in delete history command.
+	DeleteHistoryErrorCode
+)
+
+// constants for the basic message controller.
+const (
+	// command name.
+	CommandName = "basicmessage"
+
+	// command methods.
+	SendMessage   = "SendMessage"
+	History       = "History"
+	DeleteHistory = "DeleteHistory"
+
+	// error messages.
+	errEmptyConnID  = "empty connectionID"
+	errEmptyContent = "empty content"
+
+	// log constants.
+	connectionID  = "connectionID"
+	successString = "success"
+
+	_states = "_states"
+)
+
+// Command is controller command for basic message.
+type Command struct {
+	client *basicmessage.Client
+}
+
+// New returns new basic message controller command instance.
+func New(ctx basicmessage.Provider, notifier command.Notifier) (*Command, error) {
+	client, err := basicmessage.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create basic message client : %w", err)
+	}
+
+	// creates state channel
+	states := make(chan service.StateMsg)
+	// registers state channel to listen for events
+	if err := client.RegisterMsgEvent(states); err != nil {
+		return nil, fmt.Errorf("register msg event: %w", err)
+	}
+
+	obs := webnotifier.NewObserver(notifier)
+	obs.RegisterStateMsg(protocol.BasicMessage+_states, states)
+
+	return &Command{client: client}, nil
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (c *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, SendMessage, c.SendMessage),
+		cmdutil.NewCommandHandler(CommandName, History, c.History),
+		cmdutil.NewCommandHandler(CommandName, DeleteHistory, c.DeleteHistory),
+	}
+}
+
+// SendMessage sends the basic message to the agent on the other end of the connection.
+func (c *Command) SendMessage(rw io.Writer, req io.Reader) command.Error {
+	var args SendMessageArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, SendMessage, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, SendMessage, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	if args.Content == "" {
+		logutil.LogDebug(logger, CommandName, SendMessage, errEmptyContent)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyContent))
+	}
+
+	id, err := c.client.SendMessage(args.ConnectionID, args.Content, args.Locale)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SendMessage, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(SendMessageErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &MessageResponse{ID: id}, logger)
+
+	logutil.LogDebug(logger, CommandName, SendMessage, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// History returns the page of the message history matching the query.
+func (c *Command) History(rw io.Writer, req io.Reader) command.Error {
+	var args HistoryArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, History, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	result, err := c.client.History(&basicmessage.QueryParams{
+		ConnectionID: args.ConnectionID,
+		Direction:    args.Direction,
+		Descending:   args.Descending,
+		Limit:        args.Limit,
+		Cursor:       args.Cursor,
+	})
+	if err != nil {
+		logutil.LogError(logger, CommandName, History, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(HistoryErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &HistoryResponse{
+		Messages:   result.Records,
+		NextCursor: result.NextCursor,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, History, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// DeleteHistory deletes the message history of the connection.
+func (c *Command) DeleteHistory(rw io.Writer, req io.Reader) command.Error {
+	var args DeleteHistoryArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, DeleteHistory, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, DeleteHistory, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	if err := c.client.DeleteHistory(args.ConnectionID); err != nil {
+		logutil.LogError(logger, CommandName, DeleteHistory, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(DeleteHistoryErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, DeleteHistory, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockbasicmessage "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/basicmessage"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+const sampleErr = "sample-error"
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+		require.Equal(t, 3, len(cmd.GetHandlers()))
+	})
+
+	t.Run("Create client (error)", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{}, mocknotifier.NewMockNotifier(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create basic message client")
+		require.Nil(t, cmd)
+	})
+}
+
+func TestCommand_SendMessage(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			SendMessageFunc: func(connID string, msg *basicmessage.Message) (string, error) {
+				require.Equal(t, "conn", connID)
+				require.Equal(t, "Bonjour", msg.Content)
+				require.Equal(t, "fr", msg.L10n.Locale)
+
+				return "msg-id", nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.SendMessage(&b,
+			bytes.NewBufferString(`{"connectionID":"conn","content":"Bonjour","locale":"fr"}`)))
+
+		var res MessageResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, "msg-id", res.ID)
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.SendMessage(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.SendMessage(&bytes.Buffer{}, bytes.NewBufferString(`{"content":"Hello"}`))
+		require.EqualError(t, cmdErr, errEmptyConnID)
+
+		cmdErr = cmd.SendMessage(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`))
+		require.EqualError(t, cmdErr, errEmptyContent)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			SendMessageFunc: func(string, *basicmessage.Message) (string, error) {
+				return "", errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.SendMessage(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn","content":"Hello"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SendMessageErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_History(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			HistoryFunc: func(params *basicmessage.QueryParams) (*basicmessage.QueryResult, error) {
+				require.Equal(t, &basicmessage.QueryParams{
+					ConnectionID: "conn",
+					Direction:    basicmessage.DirectionReceived,
+					Descending:   true,
+					Limit:        2,
+					Cursor:       "abc",
+				}, params)
+
+				return &basicmessage.QueryResult{
+					Records:    []*basicmessage.Record{{MessageID: "msg-1"}, {MessageID: "msg-2"}},
+					NextCursor: "def",
+				}, nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.History(&b, bytes.NewBufferString(
+			`{"connectionID":"conn","direction":"received","descending":true,"limit":2,"cursor":"abc"}`)))
+
+		var res HistoryResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Len(t, res.Messages, 2)
+		require.Equal(t, "msg-1", res.Messages[0].MessageID)
+		require.Equal(t, "def", res.NextCursor)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			HistoryFunc: func(*basicmessage.QueryParams) (*basicmessage.QueryResult, error) {
+				return nil, errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.History(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.History(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.Equal(t, HistoryErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_DeleteHistory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			DeleteHistoryFunc: func(connID string) error {
+				require.Equal(t, "conn", connID)
+
+				return nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		require.NoError(t, cmd.DeleteHistory(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`)))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			DeleteHistoryFunc: func(string) error {
+				return errors.New(sampleErr)
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		cmdErr := cmd.DeleteHistory(&bytes.Buffer{}, bytes.NewBufferString(`{`))
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.DeleteHistory(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyConnID)
+
+		cmdErr = cmd.DeleteHistory(&bytes.Buffer{}, bytes.NewBufferString(`{"connectionID":"conn"}`))
+		require.Equal(t, DeleteHistoryErrorCode, cmdErr.Code())
+	})
+}
+
+func newMockProvider(svc *mockbasicmessage.MockBasicMessageSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceValue: svc,
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/client/basicmessage"
+)
+
+// SendMessageArgs model
+//
+// This is used for sending a basic message
+//
+type SendMessageArgs struct {
+	// ConnectionID of the connection the message is sent over
+	ConnectionID string `json:"connectionID"`
+	// Content of the message
+	Content string `json:"content"`
+	// Locale of the content (optional)
+	Locale string `json:"locale,omitempty"`
+}
+
+// MessageResponse model
+//
+// Represents the ID of the sent message
+//
+type MessageResponse struct {
+	// ID of the sent message
+	ID string `json:"id"`
+}
+
+// HistoryArgs model
+//
+// This is used for querying the message history, the messages are sorted by time
+//
+type HistoryArgs struct {
+	// ConnectionID of the connection, the messages of all the connections are returned if empty
+	ConnectionID string `json:"connectionID,omitempty"`
+	// Direction of the messages (sent or received)
+	Direction string `json:"direction,omitempty"`
+	// Descending returns the newest messages first
+	Descending bool `json:"descending,omitempty"`
+	// Limit is the maximum number of messages of the page
+	Limit int `json:"limit,omitempty"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// HistoryResponse model
+//
+// Represents the page of the message history
+//
+type HistoryResponse struct {
+	Messages []*basicmessage.Record `json:"messages"`
+
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// DeleteHistoryArgs model
+//
+// This is used for deleting the message history of the connection
+//
+type DeleteHistoryArgs struct {
+	// ConnectionID of the connection
+	ConnectionID string `json:"connectionID"`
+}
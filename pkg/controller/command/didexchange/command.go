@@ -34,6 +34,7 @@ const (
 	// error messages.
 	errEmptyInviterDID = "empty inviter DID"
 	errEmptyConnID     = "empty connection ID"
+	errEmptyInvID      = "empty invitation ID"
 
 	AcceptExchangeRequestCommandMethod    = "AcceptExchangeRequest"
 	AcceptInvitationCommandMethod         = "AcceptInvitation"
@@ -44,6 +45,12 @@ const (
 	ReceiveInvitationCommandMethod        = "ReceiveInvitation"
 	CreateConnectionCommandMethod         = "CreateConnection"
 	RemoveConnectionCommandMethod         = "RemoveConnection"
+	RotateDIDCommandMethod                = "RotateDID"
+	SetConnectionMetadataCommandMethod    = "SetConnectionMetadata"
+	AddConnectionTagsCommandMethod        = "AddConnectionTags"
+	RemoveConnectionTagsCommandMethod     = "RemoveConnectionTags"
+	GetInvitationPolicyCommandMethod      = "GetInvitationPolicy"
+	RevokeInvitationCommandMethod         = "RevokeInvitation"
 
 	// log constants.
 	connectionIDString = "connectionID"
@@ -80,6 +87,15 @@ const (
 	// CreateConnectionErrorCode is for failures in create connection command.
 	CreateConnectionErrorCode
 
+	// RotateDIDErrorCode is for failures in rotate DID command.
+	RotateDIDErrorCode
+
+	// UpdateConnectionErrorCode is for failures in connection metadata and tags commands.
+	UpdateConnectionErrorCode
+
+	// InvitationPolicyErrorCode is for failures in get invitation policy and revoke invitation commands.
+	InvitationPolicyErrorCode
+
 	_actions = "_actions"
 	_states  = "_states"
 )
@@ -167,6 +183,12 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, QueryConnectionsCommandMethod, c.QueryConnections),
 		cmdutil.NewCommandHandler(CommandName, AcceptExchangeRequestCommandMethod, c.AcceptExchangeRequest),
 		cmdutil.NewCommandHandler(CommandName, CreateImplicitInvitationCommandMethod, c.CreateImplicitInvitation),
+		cmdutil.NewCommandHandler(CommandName, RotateDIDCommandMethod, c.RotateDID),
+		cmdutil.NewCommandHandler(CommandName, SetConnectionMetadataCommandMethod, c.SetConnectionMetadata),
+		cmdutil.NewCommandHandler(CommandName, AddConnectionTagsCommandMethod, c.AddConnectionTags),
+		cmdutil.NewCommandHandler(CommandName, RemoveConnectionTagsCommandMethod, c.RemoveConnectionTags),
+		cmdutil.NewCommandHandler(CommandName, GetInvitationPolicyCommandMethod, c.GetInvitationPolicy),
+		cmdutil.NewCommandHandler(CommandName, RevokeInvitationCommandMethod, c.RevokeInvitation),
 	}
 }
 
@@ -181,13 +203,18 @@ func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
+	opts := []didexchange.InvOpt{didexchange.WithMaxUses(request.MaxUses)}
+	if request.Expires != nil {
+		opts = append(opts, didexchange.WithExpiry(*request.Expires))
+	}
+
 	var invitation *didexchange.Invitation
 	// call didexchange client
 	if request.Public != "" {
-		invitation, err = c.client.CreateInvitationWithDID(c.defaultLabel, request.Public)
+		invitation, err = c.client.CreateInvitationWithDID(c.defaultLabel, request.Public, opts...)
 	} else {
 		invitation, err = c.client.CreateInvitation(c.defaultLabel,
-			didexchange.WithRouterConnectionID(request.RouterConnectionID))
+			append(opts, didexchange.WithRouterConnectionID(request.RouterConnectionID))...)
 	}
 
 	if err != nil {
@@ -369,7 +396,7 @@ func (c *Command) QueryConnections(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	results, err := c.client.QueryConnections(&request.QueryConnectionsParams)
+	page, err := c.client.QueryConnectionsPage(&request.QueryConnectionsParams)
 	if err != nil {
 		logutil.LogError(logger, CommandName, QueryConnectionsCommandMethod, err.Error())
 
@@ -377,7 +404,8 @@ func (c *Command) QueryConnections(rw io.Writer, req io.Reader) command.Error {
 	}
 
 	command.WriteNillableResponse(rw, &QueryConnectionsResponse{
-		Results: results,
+		Results:    page.Results,
+		NextCursor: page.NextCursor,
 	}, logger)
 
 	logutil.LogDebug(logger, CommandName, QueryConnectionsCommandMethod, successString)
@@ -493,3 +521,172 @@ func (c *Command) RemoveConnection(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// RotateDID replaces my DID on the completed connection, a new peer DID is created if no DID is given.
+func (c *Command) RotateDID(rw io.Writer, req io.Reader) command.Error {
+	var request RotateDIDArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, RotateDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.ID == "" {
+		logutil.LogDebug(logger, CommandName, RotateDIDCommandMethod, errEmptyConnID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyConnID))
+	}
+
+	thID, err := c.client.RotateDID(request.ID, request.ToDID,
+		didexchange.WithRouterConnections(strings.Split(request.RouterConnections, ",")...))
+	if err != nil {
+		logutil.LogError(logger, CommandName, RotateDIDCommandMethod, err.Error(),
+			logutil.CreateKeyValueString(connectionIDString, request.ID))
+
+		return command.NewExecuteError(RotateDIDErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &RotateDIDResponse{
+		ThreadID: thID,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, RotateDIDCommandMethod, successString,
+		logutil.CreateKeyValueString(connectionIDString, request.ID))
+
+	return nil
+}
+
+// SetConnectionMetadata merges the given metadata into the metadata of the connection, the keys with
+// an empty value are removed.
+func (c *Command) SetConnectionMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request ConnectionMetadataArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, SetConnectionMetadataCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	return c.updateConnection(SetConnectionMetadataCommandMethod, request.ID, func() error {
+		return c.client.SetConnectionMetadata(request.ID, request.Metadata)
+	})
+}
+
+// AddConnectionTags adds the tags to the connection.
+func (c *Command) AddConnectionTags(rw io.Writer, req io.Reader) command.Error {
+	var request ConnectionTagsArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, AddConnectionTagsCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	return c.updateConnection(AddConnectionTagsCommandMethod, request.ID, func() error {
+		return c.client.AddConnectionTags(request.ID, request.Tags...)
+	})
+}
+
+// RemoveConnectionTags removes the tags from the connection.
+func (c *Command) RemoveConnectionTags(rw io.Writer, req io.Reader) command.Error {
+	var request ConnectionTagsArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, RemoveConnectionTagsCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	return c.updateConnection(RemoveConnectionTagsCommandMethod, request.ID, func() error {
+		return c.client.RemoveConnectionTags(request.ID, request.Tags...)
+	})
+}
+
+// GetInvitationPolicy returns the policy (max uses, expiry, revocation) and the number of uses of the invitation.
+func (c *Command) GetInvitationPolicy(rw io.Writer, req io.Reader) command.Error {
+	var request InvitationIDArg
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, GetInvitationPolicyCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.ID == "" {
+		logutil.LogDebug(logger, CommandName, GetInvitationPolicyCommandMethod, errEmptyInvID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyInvID))
+	}
+
+	policy, err := c.client.GetInvitationPolicy(request.ID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetInvitationPolicyCommandMethod, err.Error(),
+			logutil.CreateKeyValueString(invitationIDString, request.ID))
+
+		return command.NewExecuteError(InvitationPolicyErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &InvitationPolicyResponse{Result: policy}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetInvitationPolicyCommandMethod, successString,
+		logutil.CreateKeyValueString(invitationIDString, request.ID))
+
+	return nil
+}
+
+// RevokeInvitation revokes the invitation, the exchange requests for the invitation are not accepted anymore.
+func (c *Command) RevokeInvitation(rw io.Writer, req io.Reader) command.Error {
+	var request InvitationIDArg
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, RevokeInvitationCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.ID == "" {
+		logutil.LogDebug(logger, CommandName, RevokeInvitationCommandMethod, errEmptyInvID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyInvID))
+	}
+
+	err = c.client.RevokeInvitation(request.ID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RevokeInvitationCommandMethod, err.Error(),
+			logutil.CreateKeyValueString(invitationIDString, request.ID))
+
+		return command.NewExecuteError(InvitationPolicyErrorCode, err)
+	}
+
+	logutil.LogDebug(logger, CommandName, RevokeInvitationCommandMethod, successString,
+		logutil.CreateKeyValueString(invitationIDString, request.ID))
+
+	return nil
+}
+
+func (c *Command) updateConnection(method, connectionID string, update func() error) command.Error {
+	if connectionID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyConnID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyConnID))
+	}
+
+	if err := update(); err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error(),
+			logutil.CreateKeyValueString(connectionIDString, connectionID))
+
+		return command.NewExecuteError(UpdateConnectionErrorCode, err)
+	}
+
+	logutil.LogDebug(logger, CommandName, method, successString,
+		logutil.CreateKeyValueString(connectionIDString, connectionID))
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -26,12 +27,14 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	didexsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	didrotatesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	mockdidrotate "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didrotate"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
@@ -429,6 +432,55 @@ func TestCommand_QueryConnections(t *testing.T) {
 		require.NotEmpty(t, connID, response.Results[0].ConnectionID)
 	})
 
+	t.Run("test query connections page", func(t *testing.T) {
+		prov := mockProvider()
+		store := mockstore.MockStore{Store: make(map[string][]byte)}
+
+		for _, connID := range []string{"1", "2", "3"} {
+			connBytes, err := json.Marshal(&connection.Record{State: "completed", ConnectionID: connID})
+			require.NoError(t, err)
+			require.NoError(t, store.Put("conn_"+connID, connBytes))
+		}
+
+		prov.StorageProviderValue = &mockstore.MockStoreProvider{Store: &store}
+
+		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.QueryConnections(&b, bytes.NewBufferString(`{"limit":2}`))
+		require.NoError(t, cmdErr)
+
+		response := QueryConnectionsResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Results, 2)
+		require.NotEmpty(t, response.NextCursor)
+
+		b.Reset()
+		cmdErr = cmd.QueryConnections(&b, bytes.NewBufferString(fmt.Sprintf(`{"limit":2,"cursor":"%s"}`,
+			response.NextCursor)))
+		require.NoError(t, cmdErr)
+
+		response = QueryConnectionsResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Results, 1)
+		require.Equal(t, "3", response.Results[0].ConnectionID)
+		require.Empty(t, response.NextCursor)
+	})
+
+	t.Run("test query connections with invalid sort order", func(t *testing.T) {
+		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.QueryConnections(&b, bytes.NewBufferString(`{"sort_by":"label"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, QueryConnectionsErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
 	t.Run("test query connections validation error", func(t *testing.T) {
 		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
 		require.NoError(t, err)
@@ -936,6 +988,218 @@ func TestCommand_RemoveConnection(t *testing.T) {
 	})
 }
 
+func TestCommand_RotateDID(t *testing.T) {
+	t.Run("test rotate DID", func(t *testing.T) {
+		prov := mockProvider()
+		prov.ServiceMap[didrotatesvc.DIDRotate] = &mockdidrotate.MockDIDRotateSvc{
+			RotateDIDFunc: func(connectionID, newDID string, routerConnections []string) (string, error) {
+				require.Equal(t, "1234", connectionID)
+				require.Equal(t, "did:example:new", newDID)
+				require.Equal(t, []string{"router1", "router2"}, routerConnections)
+
+				return "th1234", nil
+			},
+		}
+
+		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.RotateDID(&b,
+			bytes.NewBufferString(`{"id":"1234","to_did":"did:example:new","router_connections":"router1,router2"}`))
+		require.NoError(t, cmdErr)
+
+		response := RotateDIDResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, "th1234", response.ThreadID)
+	})
+
+	t.Run("test rotate DID error", func(t *testing.T) {
+		prov := mockProvider()
+		prov.ServiceMap[didrotatesvc.DIDRotate] = &mockdidrotate.MockDIDRotateSvc{
+			RotateDIDFunc: func(string, string, []string) (string, error) {
+				return "", errors.New("rotate error")
+			},
+		}
+
+		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.RotateDID(&b, bytes.NewBufferString(`{"id":"1234"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, RotateDIDErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), "rotate error")
+	})
+
+	t.Run("test rotate DID validation error", func(t *testing.T) {
+		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.RotateDID(&b, bytes.NewBufferString(`{"id":""}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), errEmptyConnID)
+
+		cmdErr = cmd.RotateDID(&b, bytes.NewBufferString(`--`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_ConnectionMetadataAndTags(t *testing.T) {
+	newCommand := func(t *testing.T) *Command {
+		t.Helper()
+
+		prov := mockProvider()
+
+		r, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+		require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "1234", ThreadID: "th1234", State: "completed",
+		}))
+
+		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		return cmd
+	}
+
+	t.Run("test set metadata and tags", func(t *testing.T) {
+		cmd := newCommand(t)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.SetConnectionMetadata(&b, bytes.NewBufferString(`{"id":"1234","metadata":{"alias":"Alice"}}`))
+		require.NoError(t, cmdErr)
+
+		cmdErr = cmd.AddConnectionTags(&b, bytes.NewBufferString(`{"id":"1234","tags":["customer","gold"]}`))
+		require.NoError(t, cmdErr)
+
+		cmdErr = cmd.RemoveConnectionTags(&b, bytes.NewBufferString(`{"id":"1234","tags":["customer"]}`))
+		require.NoError(t, cmdErr)
+
+		cmdErr = cmd.QueryConnections(&b, bytes.NewBufferString(`{"tags":["gold"]}`))
+		require.NoError(t, cmdErr)
+
+		response := QueryConnectionsResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Results, 1)
+		require.Equal(t, map[string]string{"alias": "Alice"}, response.Results[0].Metadata)
+		require.Equal(t, []string{"gold"}, response.Results[0].Tags)
+	})
+
+	t.Run("test connection not found", func(t *testing.T) {
+		cmd := newCommand(t)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.AddConnectionTags(&b, bytes.NewBufferString(`{"id":"unknown","tags":["gold"]}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateConnectionErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("test validation errors", func(t *testing.T) {
+		cmd := newCommand(t)
+
+		handlers := []command.Exec{cmd.SetConnectionMetadata, cmd.AddConnectionTags, cmd.RemoveConnectionTags}
+
+		for _, handler := range handlers {
+			var b bytes.Buffer
+
+			cmdErr := handler(&b, bytes.NewBufferString(`{"id":""}`))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), errEmptyConnID)
+
+			cmdErr = handler(&b, bytes.NewBufferString(`--`))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+}
+
+func TestCommand_InvitationPolicy(t *testing.T) {
+	t.Run("test create invitation with policy, get policy and revoke", func(t *testing.T) {
+		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.CreateInvitation(&b, bytes.NewBufferString(
+			`{"alias":"myalias","max_uses":5,"expires":"2030-01-01T00:00:00Z"}`))
+		require.NoError(t, cmdErr)
+
+		invResponse := CreateInvitationResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&invResponse))
+
+		request := fmt.Sprintf(`{"id":"%s"}`, invResponse.Invitation.ID)
+
+		b.Reset()
+		cmdErr = cmd.GetInvitationPolicy(&b, bytes.NewBufferString(request))
+		require.NoError(t, cmdErr)
+
+		response := InvitationPolicyResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, invResponse.Invitation.ID, response.Result.InvitationID)
+		require.Equal(t, 5, response.Result.MaxUses)
+		require.Equal(t, 2030, response.Result.Expires.Year())
+		require.False(t, response.Result.Revoked)
+
+		cmdErr = cmd.RevokeInvitation(&b, bytes.NewBufferString(request))
+		require.NoError(t, cmdErr)
+
+		b.Reset()
+		cmdErr = cmd.GetInvitationPolicy(&b, bytes.NewBufferString(request))
+		require.NoError(t, cmdErr)
+
+		response = InvitationPolicyResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.True(t, response.Result.Revoked)
+	})
+
+	t.Run("test unknown invitation", func(t *testing.T) {
+		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetInvitationPolicy(&b, bytes.NewBufferString(`{"id":"unknown"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvitationPolicyErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+
+		cmdErr = cmd.RevokeInvitation(&b, bytes.NewBufferString(`{"id":"unknown"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvitationPolicyErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("test validation errors", func(t *testing.T) {
+		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		for _, method := range []func(io.Writer, io.Reader) command.Error{cmd.GetInvitationPolicy, cmd.RevokeInvitation} {
+			var b bytes.Buffer
+			cmdErr := method(&b, bytes.NewBufferString(`--`))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+
+			cmdErr = method(&b, bytes.NewBufferString(`{}`))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), errEmptyInvID)
+		}
+	})
+}
+
 func mockProvider() *mockprovider.Provider {
 	return &mockprovider.Provider{
 		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 // CreateInvitationArgs model
@@ -27,6 +28,12 @@ type CreateInvitationArgs struct {
 
 	// Optional specifies router connection id
 	RouterConnectionID string `json:"router_connection_id"`
+
+	// Optional maximum number of exchange requests accepted for the invitation
+	MaxUses int `json:"max_uses,omitempty"`
+
+	// Optional time after which the exchange requests for the invitation are not accepted
+	Expires *time.Time `json:"expires,omitempty"`
 }
 
 // CreateInvitationResponse model
@@ -218,6 +225,9 @@ type QueryConnectionResponse struct {
 //
 type QueryConnectionsResponse struct {
 	Results []*didexchange.Connection `json:"results,omitempty"`
+
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // AcceptExchangeRequestArgs model
@@ -312,6 +322,71 @@ type ConnectionIDArg struct {
 	ID string `json:"id"`
 }
 
+// InvitationIDArg model
+//
+// This is used for fetching the policy of the invitation or revoking the invitation
+//
+type InvitationIDArg struct {
+	// Invitation ID
+	ID string `json:"id"`
+}
+
+// InvitationPolicyResponse model
+//
+// This is used for returning the policy and the number of uses of the invitation
+//
+type InvitationPolicyResponse struct {
+	Result *connection.InvitationPolicy `json:"result,omitempty"`
+}
+
+// RotateDIDArgs model
+//
+// This is used for rotating my DID on the connection
+//
+type RotateDIDArgs struct {
+	// Connection ID
+	ID string `json:"id"`
+
+	// The DID replacing my DID, a new peer DID is created if empty
+	ToDID string `json:"to_did,omitempty"`
+
+	// Optional specifies router connections of the new peer DID (comma-separated values)
+	RouterConnections string `json:"router_connections,omitempty"`
+}
+
+// RotateDIDResponse model
+//
+// This is used for returning the result of the DID rotation
+//
+type RotateDIDResponse struct {
+	// The thread ID of the rotation
+	ThreadID string `json:"thread_id"`
+}
+
+// ConnectionMetadataArgs model
+//
+// This is used for setting the metadata of the connection
+//
+type ConnectionMetadataArgs struct {
+	// Connection ID
+	ID string `json:"id"`
+
+	// The metadata merged into the metadata of the connection, the keys with an empty value are removed
+	Metadata map[string]string `json:"metadata"`
+}
+
+// ConnectionTagsArgs model
+//
+// This is used for adding or removing the tags of the connection
+//
+type ConnectionTagsArgs struct {
+	// Connection ID
+	ID string `json:"id"`
+
+	// The tags to add or remove
+	Tags []string `json:"tags"`
+}
+
 // CreateConnectionRequest model
 //
 type CreateConnectionRequest struct {
@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/discoverfeatures"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+)
+
+var logger = log.New("aries-framework/command/discoverfeatures")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.DiscoverFeatures)
+	// QueryErrorCode is for failures in query command.
+	QueryErrorCode
+	// FeaturesErrorCode is for failures in features command.
+	FeaturesErrorCode
+)
+
+// constants for the discover-features controller.
+const (
+	// command name.
+	CommandName = "discoverfeatures"
+
+	// command methods.
+	QueryCommandMethod    = "Query"
+	FeaturesCommandMethod = "Features"
+
+	// log constants.
+	connectionID  = "connectionID"
+	successString = "success"
+
+	errEmptyConnID = "empty connectionID"
+	errEmptyQuery  = "empty query"
+)
+
+// provider contains dependencies for the discover-features command and is typically created by using aries.Context().
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// Command contains command operations provided by discover-features controller.
+type Command struct {
+	client *discoverfeatures.Client
+}
+
+// New returns new discover-features controller command instance.
+func New(ctx provider) (*Command, error) {
+	client, err := discoverfeatures.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create discover-features client : %w", err)
+	}
+
+	return &Command{client: client}, nil
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (c *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, QueryCommandMethod, c.Query),
+		cmdutil.NewCommandHandler(CommandName, FeaturesCommandMethod, c.Features),
+	}
+}
+
+// Query asks the agent on the other end of the connection which protocols it supports.
+func (c *Command) Query(rw io.Writer, req io.Reader) command.Error {
+	var request QueryRequest
+
+	if err := json.NewDecoder(req).Decode(&request); err != nil {
+		logutil.LogInfo(logger, CommandName, QueryCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+	}
+
+	if request.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, QueryCommandMethod, errEmptyConnID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnID))
+	}
+
+	if request.Query == "" {
+		logutil.LogDebug(logger, CommandName, QueryCommandMethod, errEmptyQuery,
+			logutil.CreateKeyValueString(connectionID, request.ConnectionID))
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyQuery))
+	}
+
+	protocols, err := c.client.Query(request.ConnectionID, request.Query)
+	if err != nil {
+		logutil.LogError(logger, CommandName, QueryCommandMethod, err.Error(),
+			logutil.CreateKeyValueString(connectionID, request.ConnectionID))
+		return command.NewExecuteError(QueryErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &FeaturesResponse{Protocols: protocols}, logger)
+
+	logutil.LogDebug(logger, CommandName, QueryCommandMethod, successString,
+		logutil.CreateKeyValueString(connectionID, request.ConnectionID))
+
+	return nil
+}
+
+// Features returns the protocols supported by this agent.
+func (c *Command) Features(rw io.Writer, req io.Reader) command.Error {
+	var request FeaturesRequest
+
+	if err := json.NewDecoder(req).Decode(&request); err != nil {
+		logutil.LogInfo(logger, CommandName, FeaturesCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+	}
+
+	if request.Query == "" {
+		logutil.LogDebug(logger, CommandName, FeaturesCommandMethod, errEmptyQuery)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyQuery))
+	}
+
+	protocols, err := c.client.Features(request.Query)
+	if err != nil {
+		logutil.LogError(logger, CommandName, FeaturesCommandMethod, err.Error())
+		return command.NewExecuteError(FeaturesErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &FeaturesResponse{Protocols: protocols}, logger)
+
+	logutil.LogDebug(logger, CommandName, FeaturesCommandMethod, successString)
+
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	mockdiscover "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/discoverfeatures"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+const (
+	sampleQueryRequest    = `{"connectionID":"123-abc","query":"https://didcomm.org/*"}`
+	sampleFeaturesRequest = `{"query":"https://didcomm.org/*"}`
+	sampleErr             = "sample-error"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new command", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{}))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handlers := cmd.GetHandlers()
+		require.Equal(t, 2, len(handlers))
+	})
+
+	t.Run("test new command - client creation fail", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create discover-features client")
+		require.Nil(t, cmd)
+	})
+}
+
+func TestCommand_Query(t *testing.T) {
+	t.Run("test query - success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			QueryFunc: func(connID, query string, _ ...discoverfeatures.ClientOption) ([]discoverfeatures.ProtocolDescriptor, error) { // nolint: lll
+				require.Equal(t, "123-abc", connID)
+				require.Equal(t, "https://didcomm.org/*", query)
+
+				return []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, nil
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Query(&b, bytes.NewBufferString(sampleQueryRequest))
+		require.NoError(t, cmdErr)
+
+		response := FeaturesResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, response.Protocols)
+	})
+
+	t.Run("test query - validation errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{}))
+		require.NoError(t, err)
+
+		tests := []struct {
+			request string
+			err     string
+		}{
+			{request: "--", err: "request decode"},
+			{request: `{"query":"*"}`, err: errEmptyConnID},
+			{request: `{"connectionID":"123-abc"}`, err: errEmptyQuery},
+		}
+
+		for _, tc := range tests {
+			var b bytes.Buffer
+			cmdErr := cmd.Query(&b, bytes.NewBufferString(tc.request))
+			require.Error(t, cmdErr)
+			require.Contains(t, cmdErr.Error(), tc.err)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+
+	t.Run("test query - error", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			QueryFunc: func(string, string, ...discoverfeatures.ClientOption) ([]discoverfeatures.ProtocolDescriptor, error) {
+				return nil, errors.New(sampleErr)
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Query(&b, bytes.NewBufferString(sampleQueryRequest))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), sampleErr)
+		require.Equal(t, QueryErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_Features(t *testing.T) {
+	t.Run("test features - success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			FeaturesFunc: func(query string) ([]discoverfeatures.ProtocolDescriptor, error) {
+				require.Equal(t, "https://didcomm.org/*", query)
+
+				return []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, nil
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Features(&b, bytes.NewBufferString(sampleFeaturesRequest))
+		require.NoError(t, cmdErr)
+
+		response := FeaturesResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, response.Protocols)
+	})
+
+	t.Run("test features - validation errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{}))
+		require.NoError(t, err)
+
+		for _, request := range []string{"--", `{}`} {
+			var b bytes.Buffer
+			cmdErr := cmd.Features(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+
+	t.Run("test features - error", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			FeaturesFunc: func(string) ([]discoverfeatures.ProtocolDescriptor, error) {
+				return nil, errors.New(sampleErr)
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Features(&b, bytes.NewBufferString(sampleFeaturesRequest))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), sampleErr)
+		require.Equal(t, FeaturesErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func newMockProvider(svc *mockdiscover.MockDiscoverFeaturesSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			discoverfeatures.Name: svc,
+		},
+	}
+}
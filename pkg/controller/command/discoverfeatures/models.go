@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/client/discoverfeatures"
+)
+
+// QueryRequest contains parameters for querying the features of the agent on the other end of the connection.
+type QueryRequest struct {
+	// ConnectionID of the agent to be queried.
+	ConnectionID string `json:"connectionID"`
+	// Query matches protocol identifier URIs, '*' matches any sequence of characters (e.g 'https://didcomm.org/*').
+	Query string `json:"query"`
+}
+
+// FeaturesRequest contains parameters for querying the features of this agent.
+type FeaturesRequest struct {
+	// Query matches protocol identifier URIs, '*' matches any sequence of characters (e.g 'https://didcomm.org/*').
+	Query string `json:"query"`
+}
+
+// FeaturesResponse contains the protocols matching the query.
+type FeaturesResponse struct {
+	Protocols []discoverfeatures.ProtocolDescriptor `json:"protocols"`
+}
@@ -50,6 +50,14 @@ const (
 	ActionsErrorCode
 	// AcceptProblemReportErrorCode is for failures in accept problem report command.
 	AcceptProblemReportErrorCode
+	// ProtocolInstancesErrorCode is for failures in protocol instances command.
+	ProtocolInstancesErrorCode
+	// ProtocolInstanceErrorCode is for failures in protocol instance command.
+	ProtocolInstanceErrorCode
+	// DeleteProtocolInstanceErrorCode is for failures in delete protocol instance command.
+	DeleteProtocolInstanceErrorCode
+	// AcceptRequestWithPublicDIDErrorCode is for failures in accept request with public DID command.
+	AcceptRequestWithPublicDIDErrorCode
 )
 
 // constants for command introduce.
@@ -69,6 +77,11 @@ const (
 	DeclineProposal                   = "DeclineProposal"
 	DeclineRequest                    = "DeclineRequest"
 	AcceptProblemReport               = "AcceptProblemReport"
+	ProtocolInstances                 = "ProtocolInstances"
+	ProtocolInstance                  = "ProtocolInstance"
+	DeleteProtocolInstance            = "DeleteProtocolInstance"
+	AcceptRequestWithPublicDID        = "AcceptRequestWithPublicDID"
+	SetResponsePolicy                 = "SetResponsePolicy"
 	// error messages.
 	errTwoRecipients          = "two recipients must be specified"
 	errEmptyRequest           = "empty request"
@@ -78,6 +91,7 @@ const (
 	errEmptyPleaseIntroduceTo = "empty please_introduce_to"
 	errEmptyPIID              = "empty piid"
 	errEmptyTo                = "empty to"
+	errEmptyPublicDID         = "empty public_did"
 	// log constants.
 	successString = "success"
 
@@ -132,6 +146,11 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, DeclineProposal, c.DeclineProposal),
 		cmdutil.NewCommandHandler(CommandName, DeclineRequest, c.DeclineRequest),
 		cmdutil.NewCommandHandler(CommandName, AcceptProblemReport, c.AcceptProblemReport),
+		cmdutil.NewCommandHandler(CommandName, ProtocolInstances, c.ProtocolInstances),
+		cmdutil.NewCommandHandler(CommandName, ProtocolInstance, c.ProtocolInstance),
+		cmdutil.NewCommandHandler(CommandName, DeleteProtocolInstance, c.DeleteProtocolInstance),
+		cmdutil.NewCommandHandler(CommandName, AcceptRequestWithPublicDID, c.AcceptRequestWithPublicDID),
+		cmdutil.NewCommandHandler(CommandName, SetResponsePolicy, c.SetResponsePolicy),
 	}
 }
 
@@ -343,6 +362,43 @@ func (c *Command) AcceptRequestWithPublicOOBRequest(rw io.Writer, req io.Reader)
 	return nil
 }
 
+// AcceptRequestWithPublicDID is used when the agent the introducer introduces is reachable only by its public DID.
+// nolint: dupl
+func (c *Command) AcceptRequestWithPublicDID(rw io.Writer, req io.Reader) command.Error {
+	var args AcceptRequestWithPublicDIDArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, AcceptRequestWithPublicDID, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, AcceptRequestWithPublicDID, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if args.PublicDID == "" {
+		logutil.LogDebug(logger, CommandName, AcceptRequestWithPublicDID, errEmptyPublicDID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPublicDID))
+	}
+
+	if args.To == nil {
+		logutil.LogDebug(logger, CommandName, AcceptRequestWithPublicDID, errEmptyTo)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyTo))
+	}
+
+	if err := c.client.AcceptRequestWithPublicDID(args.PIID, args.PublicDID, args.To); err != nil {
+		logutil.LogError(logger, CommandName, AcceptRequestWithPublicDID, err.Error())
+		return command.NewExecuteError(AcceptRequestWithPublicDIDErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &AcceptRequestWithPublicDIDResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, AcceptRequestWithPublicDID, successString)
+
+	return nil
+}
+
 // AcceptRequestWithRecipients is used when the introducer does not have a published out-of-band message on hand
 // but he is willing to introduce agents to each other.
 // nolint: dupl
@@ -460,3 +516,94 @@ func (c *Command) AcceptProblemReport(rw io.Writer, req io.Reader) command.Error
 
 	return nil
 }
+
+// ProtocolInstances returns the records of the in-flight and completed protocol instances.
+func (c *Command) ProtocolInstances(rw io.Writer, _ io.Reader) command.Error {
+	result, err := c.client.ProtocolInstances()
+	if err != nil {
+		logutil.LogError(logger, CommandName, ProtocolInstances, err.Error())
+		return command.NewExecuteError(ProtocolInstancesErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ProtocolInstancesResponse{
+		ProtocolInstances: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, ProtocolInstances, successString)
+
+	return nil
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piid.
+func (c *Command) ProtocolInstance(rw io.Writer, req io.Reader) command.Error {
+	var args ProtocolInstanceArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, ProtocolInstance, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, ProtocolInstance, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	result, err := c.client.ProtocolInstance(args.PIID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ProtocolInstance, err.Error())
+		return command.NewExecuteError(ProtocolInstanceErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ProtocolInstanceResponse{
+		ProtocolInstance: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, ProtocolInstance, successString)
+
+	return nil
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piid.
+func (c *Command) DeleteProtocolInstance(rw io.Writer, req io.Reader) command.Error {
+	var args DeleteProtocolInstanceArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, DeleteProtocolInstance, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, DeleteProtocolInstance, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := c.client.DeleteProtocolInstance(args.PIID); err != nil {
+		logutil.LogError(logger, CommandName, DeleteProtocolInstance, err.Error())
+		return command.NewExecuteError(DeleteProtocolInstanceErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &DeleteProtocolInstanceResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, DeleteProtocolInstance, successString)
+
+	return nil
+}
+
+// SetResponsePolicy sets the policy which answers the introduce actions without waiting for the user.
+// An empty policy restores the default behavior which emits all the actions.
+func (c *Command) SetResponsePolicy(rw io.Writer, req io.Reader) command.Error {
+	var args SetResponsePolicyArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, SetResponsePolicy, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	c.client.SetResponsePolicy(args.Policy)
+
+	command.WriteNillableResponse(rw, &SetResponsePolicyResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, SetResponsePolicy, successString)
+
+	return nil
+}
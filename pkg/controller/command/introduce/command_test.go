@@ -697,6 +697,82 @@ func TestCommand_AcceptRequestWithPublicOOBRequest(t *testing.T) {
 	})
 }
 
+func TestCommand_AcceptRequestWithPublicDID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	t.Run("Validation errors", func(t *testing.T) {
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		for payload, errMsg := range map[string]string{
+			"}":                                    "invalid character",
+			"{}":                                   errEmptyPIID,
+			`{"piid":"piid"}`:                      errEmptyPublicDID,
+			`{"piid":"piid","public_did":"did:1"}`: errEmptyTo,
+		} {
+			var b bytes.Buffer
+			cmdErr := cmd.AcceptRequestWithPublicDID(&b, bytes.NewBufferString(payload))
+
+			require.Error(t, cmdErr)
+			require.Contains(t, cmdErr.Error(), errMsg)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+
+	t.Run("AcceptRequestWithPublicDID (error)", func(t *testing.T) {
+		service := mocks.NewMockProtocolService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+		service.EXPECT().ActionContinue(
+			gomock.Any(), gomock.Any(),
+		).Return(errors.New("error message"))
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		const jsonPayload = `{"piid":"piid","public_did":"did:1","to":{}}`
+		cmdErr := cmd.AcceptRequestWithPublicDID(&b, bytes.NewBufferString(jsonPayload))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "error message")
+		require.Equal(t, AcceptRequestWithPublicDIDErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service := mocks.NewMockProtocolService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+		service.EXPECT().ActionContinue("piid", gomock.Any())
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		const jsonPayload = `{"piid":"piid","public_did":"did:1","to":{"name":"Carol"}}`
+		require.NoError(t, cmd.AcceptRequestWithPublicDID(&b, bytes.NewBufferString(jsonPayload)))
+	})
+}
+
 func TestCommand_AcceptRequestWithRecipients(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1059,3 +1135,205 @@ func toProtocolActions(actions []introduce.Action) []protocol.Action {
 
 	return res
 }
+
+func TestCommand_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	t.Run("Success", func(t *testing.T) {
+		service.EXPECT().ProtocolInstances().Return([]*protocol.ProtocolInstance{{PIID: "ID1"}, {PIID: "ID2"}}, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.ProtocolInstances(&b, nil))
+
+		response := ProtocolInstancesResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, ProtocolInstancesResponse{
+			ProtocolInstances: []introduce.ProtocolInstance{{PIID: "ID1"}, {PIID: "ID2"}},
+		}, response)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		service.EXPECT().ProtocolInstances().Return(nil, errors.New("some error message"))
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		cmdErr := cmd.ProtocolInstances(nil, nil)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "some error message")
+		require.Equal(t, ProtocolInstancesErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_ProtocolInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.ProtocolInstance(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty PIID", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.ProtocolInstance(&b, bytes.NewBufferString("{}"))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errEmptyPIID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("ProtocolInstance (error)", func(t *testing.T) {
+		service.EXPECT().ProtocolInstance("id").Return(nil, protocol.ErrProtocolInstanceNotFound)
+
+		var b bytes.Buffer
+		cmdErr := cmd.ProtocolInstance(&b, bytes.NewBufferString(`{"piid":"id"}`))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), protocol.ErrProtocolInstanceNotFound.Error())
+		require.Equal(t, ProtocolInstanceErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service.EXPECT().ProtocolInstance("id").Return(&protocol.ProtocolInstance{PIID: "id"}, nil)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.ProtocolInstance(&b, bytes.NewBufferString(`{"piid":"id"}`)))
+
+		response := ProtocolInstanceResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, &introduce.ProtocolInstance{PIID: "id"}, response.ProtocolInstance)
+	})
+}
+
+func TestCommand_DeleteProtocolInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteProtocolInstance(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty PIID", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteProtocolInstance(&b, bytes.NewBufferString("{}"))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errEmptyPIID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("DeleteProtocolInstance (error)", func(t *testing.T) {
+		service.EXPECT().DeleteProtocolInstance("id").Return(errors.New("some error message"))
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteProtocolInstance(&b, bytes.NewBufferString(`{"piid":"id"}`))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "some error message")
+		require.Equal(t, DeleteProtocolInstanceErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service.EXPECT().DeleteProtocolInstance("id").Return(nil)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.DeleteProtocolInstance(&b, bytes.NewBufferString(`{"piid":"id"}`)))
+	})
+}
+
+func TestCommand_SetResponsePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Decode error", func(t *testing.T) {
+		service := mocks.NewMockProtocolService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.SetResponsePolicy(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service := mocks.NewMockProtocolService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+		service.EXPECT().SetResponsePolicy(gomock.Any()).Do(func(policy *protocol.ResponsePolicy) {
+			require.Equal(t, []string{"did:example:alice"}, policy.Introducers)
+			require.Equal(t, "did:example:carol", policy.Targets["Carol"].PublicDID)
+		})
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		const jsonPayload = `{"policy":{"introducers":["did:example:alice"],` +
+			`"targets":{"Carol":{"to":{"name":"Carol"},"public_did":"did:example:carol"}}}}`
+		require.NoError(t, cmd.SetResponsePolicy(&b, bytes.NewBufferString(jsonPayload)))
+	})
+}
@@ -115,6 +115,25 @@ type AcceptRequestWithPublicOOBRequestArgs struct {
 //
 type AcceptRequestWithPublicOOBRequestResponse struct{}
 
+// AcceptRequestWithPublicDIDArgs model
+//
+// This is used for accepting a request with the public DID of the introduced agent
+//
+type AcceptRequestWithPublicDIDArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+	// PublicDID is the DID by which the introduced agent is reachable
+	PublicDID string `json:"public_did"`
+	// To keeps information about the introduction
+	To *introduce.To `json:"to"`
+}
+
+// AcceptRequestWithPublicDIDResponse model
+//
+// Represents a AcceptRequestWithPublicDID response message
+//
+type AcceptRequestWithPublicDIDResponse struct{}
+
 // AcceptRequestWithRecipientsArgs model
 //
 // This is used for accepting a request with recipients
@@ -197,3 +216,59 @@ type AcceptProblemReportArgs struct {
 // Represents a AcceptProblemReport response message
 //
 type AcceptProblemReportResponse struct{}
+
+// ProtocolInstancesResponse model
+//
+// Represents a ProtocolInstances response message
+//
+type ProtocolInstancesResponse struct {
+	ProtocolInstances []introduce.ProtocolInstance `json:"protocol_instances"`
+}
+
+// ProtocolInstanceArgs model
+//
+// This is used for getting the protocol instance
+//
+type ProtocolInstanceArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+}
+
+// ProtocolInstanceResponse model
+//
+// Represents a ProtocolInstance response message
+//
+type ProtocolInstanceResponse struct {
+	ProtocolInstance *introduce.ProtocolInstance `json:"protocol_instance"`
+}
+
+// DeleteProtocolInstanceArgs model
+//
+// This is used for deleting the protocol instance
+//
+type DeleteProtocolInstanceArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+}
+
+// DeleteProtocolInstanceResponse model
+//
+// Represents a DeleteProtocolInstance response message
+//
+type DeleteProtocolInstanceResponse struct{}
+
+// SetResponsePolicyArgs model
+//
+// This is used for setting the policy which answers the introduce actions
+//
+type SetResponsePolicyArgs struct {
+	// Policy accepts the proposals and forwards the out-of-band messages without waiting for the user,
+	// the default behavior is restored if empty
+	Policy *introduce.ResponsePolicy `json:"policy"`
+}
+
+// SetResponsePolicyResponse model
+//
+// Represents a SetResponsePolicy response message
+//
+type SetResponsePolicyResponse struct{}
@@ -56,6 +56,12 @@ const (
 	SendRequestErrorCode
 	// ActionsErrorCode failures in actions command.
 	ActionsErrorCode
+	// ProtocolInstancesErrorCode is for failures in protocol instances command.
+	ProtocolInstancesErrorCode
+	// ProtocolInstanceErrorCode is for failures in protocol instance command.
+	ProtocolInstanceErrorCode
+	// DeleteProtocolInstanceErrorCode is for failures in delete protocol instance command.
+	DeleteProtocolInstanceErrorCode
 )
 
 // constants for issue credential commands.
@@ -63,20 +69,23 @@ const (
 	// command name.
 	CommandName = "issuecredential"
 
-	Actions             = "Actions"
-	SendOffer           = "SendOffer"
-	SendProposal        = "SendProposal"
-	SendRequest         = "SendRequest"
-	AcceptProposal      = "AcceptProposal"
-	DeclineProposal     = "DeclineProposal"
-	AcceptOffer         = "AcceptOffer"
-	DeclineOffer        = "DeclineOffer"
-	NegotiateProposal   = "NegotiateProposal"
-	AcceptRequest       = "AcceptRequest"
-	DeclineRequest      = "DeclineRequest"
-	AcceptCredential    = "AcceptCredential"
-	DeclineCredential   = "DeclineCredential"
-	AcceptProblemReport = "AcceptProblemReport"
+	Actions                = "Actions"
+	SendOffer              = "SendOffer"
+	SendProposal           = "SendProposal"
+	SendRequest            = "SendRequest"
+	AcceptProposal         = "AcceptProposal"
+	DeclineProposal        = "DeclineProposal"
+	AcceptOffer            = "AcceptOffer"
+	DeclineOffer           = "DeclineOffer"
+	NegotiateProposal      = "NegotiateProposal"
+	AcceptRequest          = "AcceptRequest"
+	DeclineRequest         = "DeclineRequest"
+	AcceptCredential       = "AcceptCredential"
+	DeclineCredential      = "DeclineCredential"
+	AcceptProblemReport    = "AcceptProblemReport"
+	ProtocolInstances      = "ProtocolInstances"
+	ProtocolInstance       = "ProtocolInstance"
+	DeleteProtocolInstance = "DeleteProtocolInstance"
 )
 
 const (
@@ -145,6 +154,9 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, DeclineRequest, c.DeclineRequest),
 		cmdutil.NewCommandHandler(CommandName, AcceptCredential, c.AcceptCredential),
 		cmdutil.NewCommandHandler(CommandName, DeclineCredential, c.DeclineCredential),
+		cmdutil.NewCommandHandler(CommandName, ProtocolInstances, c.ProtocolInstances),
+		cmdutil.NewCommandHandler(CommandName, ProtocolInstance, c.ProtocolInstance),
+		cmdutil.NewCommandHandler(CommandName, DeleteProtocolInstance, c.DeleteProtocolInstance),
 	}
 }
 
@@ -563,3 +575,75 @@ func (c *Command) DeclineCredential(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// ProtocolInstances returns the records of the in-flight and completed protocol instances.
+func (c *Command) ProtocolInstances(rw io.Writer, _ io.Reader) command.Error {
+	result, err := c.client.ProtocolInstances()
+	if err != nil {
+		logutil.LogError(logger, CommandName, ProtocolInstances, err.Error())
+		return command.NewExecuteError(ProtocolInstancesErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ProtocolInstancesResponse{
+		ProtocolInstances: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, ProtocolInstances, successString)
+
+	return nil
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piid.
+func (c *Command) ProtocolInstance(rw io.Writer, req io.Reader) command.Error {
+	var args ProtocolInstanceArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, ProtocolInstance, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, ProtocolInstance, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	result, err := c.client.ProtocolInstance(args.PIID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ProtocolInstance, err.Error())
+		return command.NewExecuteError(ProtocolInstanceErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ProtocolInstanceResponse{
+		ProtocolInstance: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, ProtocolInstance, successString)
+
+	return nil
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piid.
+func (c *Command) DeleteProtocolInstance(rw io.Writer, req io.Reader) command.Error {
+	var args DeleteProtocolInstanceArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, DeleteProtocolInstance, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, DeleteProtocolInstance, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := c.client.DeleteProtocolInstance(args.PIID); err != nil {
+		logutil.LogError(logger, CommandName, DeleteProtocolInstance, err.Error())
+		return command.NewExecuteError(DeleteProtocolInstanceErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &DeleteProtocolInstanceResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, DeleteProtocolInstance, successString)
+
+	return nil
+}
@@ -31,6 +31,12 @@ const (
 	CreateKeySetError
 	// ImportKeyError is for failures while importing key.
 	ImportKeyError
+	// GetKeyMetadataError is for failures while getting key metadata.
+	GetKeyMetadataError
+	// ListKeysError is for failures while listing keys.
+	ListKeysError
+	// DeleteKeyError is for failures while deleting key.
+	DeleteKeyError
 )
 
 // constants for KMS commands.
@@ -39,12 +45,16 @@ const (
 	CommandName = "kms"
 
 	// command methods.
-	CreateKeySetCommandMethod = "CreateKeySet"
-	ImportKeyCommandMethod    = "ImportKey"
+	CreateKeySetCommandMethod   = "CreateKeySet"
+	ImportKeyCommandMethod      = "ImportKey"
+	GetKeyMetadataCommandMethod = "GetKeyMetadata"
+	ListKeysCommandMethod       = "ListKeys"
+	DeleteKeyCommandMethod      = "DeleteKey"
 
 	// error messages.
 	errEmptyKeyType = "key type is mandatory"
 	errEmptyKeyID   = "key id is mandatory"
+	errNoMetadata   = "key manager does not support key metadata"
 )
 
 // provider contains dependencies for the kms command and is typically created by using aries.Context().
@@ -52,6 +62,11 @@ type provider interface {
 	KMS() kms.KeyManager
 }
 
+// keyMetadataProvider is implemented by the key managers keeping the metadata of their keys (eg: localkms).
+type keyMetadataProvider interface {
+	KeyMetadata(keyID string) (*kms.KeyMetadata, error)
+}
+
 // Command contains command operations provided by verifiable credential controller.
 type Command struct {
 	ctx       provider
@@ -75,6 +90,9 @@ func (o *Command) GetHandlers() []command.Handler {
 	return []command.Handler{
 		cmdutil.NewCommandHandler(CommandName, CreateKeySetCommandMethod, o.CreateKeySet),
 		cmdutil.NewCommandHandler(CommandName, ImportKeyCommandMethod, o.ImportKey),
+		cmdutil.NewCommandHandler(CommandName, GetKeyMetadataCommandMethod, o.GetKeyMetadata),
+		cmdutil.NewCommandHandler(CommandName, ListKeysCommandMethod, o.ListKeys),
+		cmdutil.NewCommandHandler(CommandName, DeleteKeyCommandMethod, o.DeleteKey),
 	}
 }
 
@@ -154,3 +172,110 @@ func (o *Command) ImportKey(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// GetKeyMetadata returns the metadata of the key set along with its versions.
+func (o *Command) GetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request GetKeyMetadataRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, GetKeyMetadataCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, CommandName, GetKeyMetadataCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	km, ok := o.ctx.KMS().(keyMetadataProvider)
+	if !ok {
+		logutil.LogError(logger, CommandName, GetKeyMetadataCommandMethod, errNoMetadata)
+		return command.NewExecuteError(GetKeyMetadataError, fmt.Errorf(errNoMetadata))
+	}
+
+	md, err := km.KeyMetadata(request.KeyID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetKeyMetadataCommandMethod, err.Error(),
+			logutil.CreateKeyValueString("keyID", request.KeyID))
+		return command.NewExecuteError(GetKeyMetadataError, err)
+	}
+
+	command.WriteNillableResponse(rw, &GetKeyMetadataResponse{KeyMetadata: *md}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetKeyMetadataCommandMethod, "success",
+		logutil.CreateKeyValueString("keyID", request.KeyID))
+
+	return nil
+}
+
+// ListKeys returns the metadata of the keys managed by the KMS filtered by key type and creation time.
+func (o *Command) ListKeys(rw io.Writer, req io.Reader) command.Error {
+	var request ListKeysRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, ListKeysCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	var opts []kms.ListOpts
+
+	if request.KeyType != "" {
+		opts = append(opts, kms.WithKeyType(kms.KeyType(request.KeyType)))
+	}
+
+	if request.CreatedAfter != nil {
+		opts = append(opts, kms.WithCreatedAfter(*request.CreatedAfter))
+	}
+
+	if request.CreatedBefore != nil {
+		opts = append(opts, kms.WithCreatedBefore(*request.CreatedBefore))
+	}
+
+	keys, err := o.ctx.KMS().List(opts...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ListKeysCommandMethod, err.Error())
+		return command.NewExecuteError(ListKeysError, err)
+	}
+
+	if keys == nil {
+		keys = []kms.KeyMetadata{}
+	}
+
+	command.WriteNillableResponse(rw, &ListKeysResponse{Keys: keys}, logger)
+
+	logutil.LogDebug(logger, CommandName, ListKeysCommandMethod, "success")
+
+	return nil
+}
+
+// DeleteKey deletes the key set along with all its versions.
+func (o *Command) DeleteKey(rw io.Writer, req io.Reader) command.Error {
+	var request DeleteKeyRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, DeleteKeyCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, CommandName, DeleteKeyCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	err = o.ctx.KMS().Delete(request.KeyID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeleteKeyCommandMethod, err.Error(),
+			logutil.CreateKeyValueString("keyID", request.KeyID))
+		return command.NewExecuteError(DeleteKeyError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, DeleteKeyCommandMethod, "success",
+		logutil.CreateKeyValueString("keyID", request.KeyID))
+
+	return nil
+}
@@ -16,10 +16,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/square/go-jose/v3"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	ariesjose "github.com/hyperledger/aries-framework-go/pkg/doc/jose"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
@@ -34,7 +36,7 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, cmd)
 
 		handlers := cmd.GetHandlers()
-		require.Equal(t, 2, len(handlers))
+		require.Equal(t, 5, len(handlers))
 	})
 
 	t.Run("test new command - error from import key", func(t *testing.T) {
@@ -257,3 +259,211 @@ func TestImportKey(t *testing.T) {
 		require.Contains(t, err.Error(), "failed request decode")
 	})
 }
+
+type metadataKeyManager struct {
+	mockkms.KeyManager
+	metadata    *kms.KeyMetadata
+	metadataErr error
+}
+
+func (m *metadataKeyManager) KeyMetadata(keyID string) (*kms.KeyMetadata, error) {
+	if m.metadataErr != nil {
+		return nil, m.metadataErr
+	}
+
+	return m.metadata, nil
+}
+
+func TestGetKeyMetadata(t *testing.T) {
+	t.Run("test get key metadata - success", func(t *testing.T) {
+		created := time.Now().UTC().Truncate(time.Second)
+
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &metadataKeyManager{metadata: &kms.KeyMetadata{
+				KeyID:   "keyID",
+				KeyType: kms.ED25519Type,
+				Created: created,
+				Versions: []kms.KeyVersion{
+					{Version: 1, Status: kms.KeyStatusDisabled, Created: created},
+					{Version: 2, Primary: true, Status: kms.KeyStatusEnabled, Created: created},
+				},
+			}},
+		})
+
+		var getRW bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&getRW, bytes.NewBufferString(`{"keyID":"keyID"}`))
+		require.NoError(t, cmdErr)
+
+		response := GetKeyMetadataResponse{}
+		require.NoError(t, json.NewDecoder(&getRW).Decode(&response))
+
+		require.Equal(t, "keyID", response.KeyID)
+		require.Equal(t, kms.ED25519Type, response.KeyType)
+		require.True(t, created.Equal(response.Created))
+		require.Len(t, response.Versions, 2)
+		require.Equal(t, kms.KeyStatusDisabled, response.Versions[0].Status)
+		require.True(t, response.Versions[1].Primary)
+	})
+
+	t.Run("test get key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &metadataKeyManager{metadataErr: fmt.Errorf("key not found")},
+		})
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"keyID"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, GetKeyMetadataError, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), "key not found")
+	})
+
+	t.Run("test get key metadata - not supported by the key manager", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"keyID"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, GetKeyMetadataError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errNoMetadata)
+	})
+
+	t.Run("test get key metadata - validation errors", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &metadataKeyManager{}})
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&b, bytes.NewBuffer(nil))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed request decode")
+
+		cmdErr = cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyKeyID)
+	})
+}
+
+type listKeyManager struct {
+	mockkms.KeyManager
+}
+
+func (m *listKeyManager) List(opts ...kms.ListOpts) ([]kms.KeyMetadata, error) {
+	lOpts := kms.NewListOpt()
+
+	for _, opt := range opts {
+		opt(lOpts)
+	}
+
+	var keys []kms.KeyMetadata
+
+	for i := range m.ListValue {
+		if lOpts.Matches(&m.ListValue[i]) {
+			keys = append(keys, m.ListValue[i])
+		}
+	}
+
+	return keys, nil
+}
+
+func TestListKeys(t *testing.T) {
+	t.Run("test list keys - success", func(t *testing.T) {
+		created := time.Now().UTC().Truncate(time.Second)
+
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &listKeyManager{KeyManager: mockkms.KeyManager{ListValue: []kms.KeyMetadata{
+				{KeyID: "key1", KeyType: kms.ED25519Type, Created: created},
+				{KeyID: "key2", KeyType: kms.AES128GCMType, Created: created.Add(time.Hour)},
+			}}},
+		})
+
+		var b bytes.Buffer
+		cmdErr := cmd.ListKeys(&b, bytes.NewBufferString(`{}`))
+		require.NoError(t, cmdErr)
+
+		response := ListKeysResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Keys, 2)
+
+		request, err := json.Marshal(&ListKeysRequest{
+			KeyType:      string(kms.AES128GCMType),
+			CreatedAfter: &created,
+		})
+		require.NoError(t, err)
+
+		b.Reset()
+		cmdErr = cmd.ListKeys(&b, bytes.NewBuffer(request))
+		require.NoError(t, cmdErr)
+
+		response = ListKeysResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Keys, 1)
+		require.Equal(t, "key2", response.Keys[0].KeyID)
+
+		before := created.Add(-time.Minute)
+
+		request, err = json.Marshal(&ListKeysRequest{CreatedBefore: &before})
+		require.NoError(t, err)
+
+		b.Reset()
+		cmdErr = cmd.ListKeys(&b, bytes.NewBuffer(request))
+		require.NoError(t, cmdErr)
+		require.JSONEq(t, `{"keys":[]}`, b.String())
+	})
+
+	t.Run("test list keys - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListErr: fmt.Errorf("list error")},
+		})
+
+		var b bytes.Buffer
+		cmdErr := cmd.ListKeys(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, ListKeysError, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), "list error")
+
+		cmdErr = cmd.ListKeys(&b, bytes.NewBuffer(nil))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed request decode")
+	})
+}
+
+func TestDeleteKey(t *testing.T) {
+	t.Run("test delete key - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteKey(&b, bytes.NewBufferString(`{"keyID":"keyID"}`))
+		require.NoError(t, cmdErr)
+	})
+
+	t.Run("test delete key - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{DeleteErr: fmt.Errorf("key not found")},
+		})
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteKey(&b, bytes.NewBufferString(`{"keyID":"keyID"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeleteKeyError, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), "key not found")
+	})
+
+	t.Run("test delete key - validation errors", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteKey(&b, bytes.NewBuffer(nil))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed request decode")
+
+		cmdErr = cmd.DeleteKey(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyKeyID)
+	})
+}
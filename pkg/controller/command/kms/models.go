@@ -6,6 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package kms
 
+import (
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
 // CreateKeySetRequest is model for createKeySey request.
 type CreateKeySetRequest struct {
 	KeyType string `json:"keyType,omitempty"`
@@ -30,3 +36,33 @@ type JSONWebKey struct {
 	Y   string `json:"y,omitempty"`
 	D   string `json:"d,omitempty"`
 }
+
+// GetKeyMetadataRequest is model for getKeyMetadata request.
+type GetKeyMetadataRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// GetKeyMetadataResponse for returning the key metadata (created, type and status of the key versions).
+type GetKeyMetadataResponse struct {
+	kms.KeyMetadata
+}
+
+// ListKeysRequest is model for listKeys request.
+type ListKeysRequest struct {
+	// lists the keys of the given type only
+	KeyType string `json:"keyType,omitempty"`
+	// lists the keys created after the given time only
+	CreatedAfter *time.Time `json:"createdAfter,omitempty"`
+	// lists the keys created before the given time only
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+// ListKeysResponse for returning the metadata of the keys.
+type ListKeysResponse struct {
+	Keys []kms.KeyMetadata `json:"keys"`
+}
+
+// DeleteKeyRequest is model for deleteKey request.
+type DeleteKeyRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
@@ -152,8 +152,8 @@ func (c *Command) CreateRequest(rw io.Writer, req io.Reader) command.Error {
 }
 
 // CreateInvitation creates and saves an out-of-band invitation.
-// Protocols is an optional list of protocol identifier URIs that can be used to form connections. A default
-// will be set if none are provided.
+// HandshakeProtocols is an optional list of protocol identifier URIs that can be used to form connections.
+// A default will be set if none are provided unless attachments are provided.
 func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 	var args CreateInvitationArgs
 	if err := json.NewDecoder(req).Decode(&args); err != nil {
@@ -161,12 +161,20 @@ func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	invitation, err := c.client.CreateInvitation(args.Protocols, []outofband.MessageOption{
+	opts := []outofband.MessageOption{
 		outofband.WithGoal(args.Goal, args.GoalCode),
 		outofband.WithLabel(args.Label),
 		outofband.WithServices(args.Service...),
 		outofband.WithRouterConnections(args.RouterConnectionID),
-	}...)
+		outofband.WithAttachments(args.Attachments...),
+		outofband.WithMaxUses(args.MaxUses),
+	}
+
+	if args.Expires != nil {
+		opts = append(opts, outofband.WithExpiry(*args.Expires))
+	}
+
+	invitation, err := c.client.CreateInvitation(args.HandshakeProtocols, opts...)
 	if err != nil {
 		logutil.LogError(logger, CommandName, CreateInvitation, err.Error())
 		return command.NewExecuteError(CreateInvitationErrorCode, err)
@@ -216,6 +224,8 @@ func (c *Command) AcceptRequest(rw io.Writer, req io.Reader) command.Error {
 }
 
 // AcceptInvitation from another agent and return the ID of the new connection records.
+// The existing connection is reused if requested, no connection is created for the invitation
+// without handshake protocols.
 func (c *Command) AcceptInvitation(rw io.Writer, req io.Reader) command.Error {
 	var args AcceptInvitationArgs
 	if err := json.NewDecoder(req).Decode(&args); err != nil {
@@ -233,8 +243,16 @@ func (c *Command) AcceptInvitation(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyMyLabel))
 	}
 
-	connID, err := c.client.AcceptInvitation(args.Invitation, args.MyLabel,
-		outofband.WithRouterConnections(strings.Split(args.RouterConnections, ",")...))
+	opts := []outofband.MessageOption{
+		outofband.WithRouterConnections(strings.Split(args.RouterConnections, ",")...),
+		outofband.ReuseConnection(args.ReuseConnection),
+	}
+
+	if args.ReuseAnyConnection {
+		opts = append(opts, outofband.ReuseAnyConnection())
+	}
+
+	connID, err := c.client.AcceptInvitation(args.Invitation, args.MyLabel, opts...)
 	if err != nil {
 		logutil.LogError(logger, CommandName, AcceptInvitation, err.Error())
 		return command.NewExecuteError(AcceptInvitationErrorCode, err)
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -21,6 +22,7 @@ import (
 	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/outofband"
 	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -237,11 +239,11 @@ func TestCommand_CreateInvitation(t *testing.T) {
 		var b bytes.Buffer
 
 		expected := CreateInvitationArgs{
-			Label:     "label",
-			Goal:      "goal",
-			GoalCode:  "goal_code",
-			Service:   []interface{}{"s1"},
-			Protocols: []string{"s1"},
+			Label:              "label",
+			Goal:               "goal",
+			GoalCode:           "goal_code",
+			Service:            []interface{}{"s1"},
+			HandshakeProtocols: []string{"s1"},
 		}
 		args, err := json.Marshal(expected)
 		require.NoError(t, err)
@@ -253,7 +255,39 @@ func TestCommand_CreateInvitation(t *testing.T) {
 		require.Equal(t, expected.Goal, res.Invitation.Goal)
 		require.Equal(t, expected.GoalCode, res.Invitation.GoalCode)
 		require.Equal(t, expected.Service, res.Invitation.Service)
-		require.Equal(t, expected.Protocols, res.Invitation.Protocols)
+		require.Equal(t, expected.HandshakeProtocols, res.Invitation.HandshakeProtocols)
+	})
+
+	t.Run("Success with policy", func(t *testing.T) {
+		expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		service := mocks.NewMockOobService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+		service.EXPECT().SaveInvitation(gomock.Any()).Return(nil)
+		service.EXPECT().SaveInvitationPolicy(gomock.Any()).DoAndReturn(
+			func(policy *connection.InvitationPolicy) error {
+				require.Equal(t, 3, policy.MaxUses)
+				require.True(t, expires.Equal(*policy.Expires))
+
+				return nil
+			})
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		args, err := json.Marshal(CreateInvitationArgs{
+			Service: []interface{}{"s1"},
+			MaxUses: 3,
+			Expires: &expires,
+		})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.CreateInvitation(&b, bytes.NewBuffer(args)))
 	})
 }
 
@@ -408,7 +442,7 @@ func TestCommand_AcceptInvitation(t *testing.T) {
 		service := mocks.NewMockOobService(ctrl)
 		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
 		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
-		service.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).
+		service.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any()).
 			Return("", errors.New("error message"))
 
 		provider := mocks.NewMockProvider(ctrl)
@@ -431,7 +465,14 @@ func TestCommand_AcceptInvitation(t *testing.T) {
 		service := mocks.NewMockOobService(ctrl)
 		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
 		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
-		service.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).Return(connID, nil)
+		service.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ *protocol.Invitation, opts protocol.Options) (string, error) {
+				require.Equal(t, "label", opts.MyLabel())
+				require.Equal(t, "reuse-id", opts.ReuseConnection())
+				require.True(t, opts.ReuseAnyConnection())
+
+				return connID, nil
+			})
 
 		provider := mocks.NewMockProvider(ctrl)
 		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
@@ -440,7 +481,8 @@ func TestCommand_AcceptInvitation(t *testing.T) {
 		require.NotNil(t, cmd)
 
 		var b bytes.Buffer
-		require.NoError(t, cmd.AcceptInvitation(&b, bytes.NewBufferString(`{"invitation":{},"my_label":"label"}`)))
+		require.NoError(t, cmd.AcceptInvitation(&b, bytes.NewBufferString(
+			`{"invitation":{},"my_label":"label","reuse_connection":"reuse-id","reuse_any_connection":true}`)))
 		res := AcceptInvitationResponse{}
 		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
 		require.Equal(t, connID, res.ConnectionID)
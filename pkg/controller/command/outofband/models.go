@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package outofband
 
 import (
+	"time"
+
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 )
@@ -43,8 +45,17 @@ type CreateInvitationArgs struct {
 	Goal               string        `json:"goal"`
 	GoalCode           string        `json:"goal_code"`
 	Service            []interface{} `json:"service"`
-	Protocols          []string      `json:"protocols"`
+	HandshakeProtocols []string      `json:"handshake_protocols"`
 	RouterConnectionID string        `json:"router_connection_id"`
+
+	// Attachments are the requests (e.g present proof request) attached to the invitation.
+	Attachments []*decorator.Attachment `json:"attachments"`
+
+	// MaxUses is the maximum number of did-exchange requests accepted for the invitation, unlimited if not set.
+	MaxUses int `json:"max_uses,omitempty"`
+
+	// Expires is the time after which the did-exchange requests for the invitation are not accepted.
+	Expires *time.Time `json:"expires,omitempty"`
 }
 
 // CreateInvitationResponse model
@@ -81,6 +92,11 @@ type AcceptInvitationArgs struct {
 	Invitation        *outofband.Invitation `json:"invitation"`
 	MyLabel           string                `json:"my_label"`
 	RouterConnections string                `json:"router_connections"`
+
+	// ReuseConnection is the ID of the existing connection to reuse instead of the new handshake.
+	ReuseConnection string `json:"reuse_connection"`
+	// ReuseAnyConnection allows reusing any existing connection with the DIDs of the invitation's services.
+	ReuseAnyConnection bool `json:"reuse_any_connection"`
 }
 
 // AcceptInvitationResponse model
@@ -195,3 +195,43 @@ type AcceptProblemReportArgs struct {
 // Represents a AcceptProblemReport response message
 //
 type AcceptProblemReportResponse struct{}
+
+// ProtocolInstancesResponse model
+//
+// Represents a ProtocolInstances response message
+//
+type ProtocolInstancesResponse struct {
+	ProtocolInstances []presentproof.ProtocolInstance `json:"protocol_instances"`
+}
+
+// ProtocolInstanceArgs model
+//
+// This is used for getting the protocol instance
+//
+type ProtocolInstanceArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+}
+
+// ProtocolInstanceResponse model
+//
+// Represents a ProtocolInstance response message
+//
+type ProtocolInstanceResponse struct {
+	ProtocolInstance *presentproof.ProtocolInstance `json:"protocol_instance"`
+}
+
+// DeleteProtocolInstanceArgs model
+//
+// This is used for deleting the protocol instance
+//
+type DeleteProtocolInstanceArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+}
+
+// DeleteProtocolInstanceResponse model
+//
+// Represents a DeleteProtocolInstance response message
+//
+type DeleteProtocolInstanceResponse struct{}
@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+	mocktrustping "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/trustping"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+const (
+	samplePingRequest = `{"connectionID":"123-abc","timeout_ms":1500}`
+	sampleErr         = "sample-error"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new command", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mocktrustping.MockTrustPingSvc{}))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handlers := cmd.GetHandlers()
+		require.Equal(t, 1, len(handlers))
+	})
+
+	t.Run("test new command - client creation fail", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create trust ping client")
+		require.Nil(t, cmd)
+	})
+}
+
+func TestCommand_Ping(t *testing.T) {
+	t.Run("test ping - success", func(t *testing.T) {
+		lastSeen := time.Now().UTC()
+
+		cmd, err := New(newMockProvider(&mocktrustping.MockTrustPingSvc{
+			PingFunc: func(connID string, options ...trustping.ClientOption) (*trustping.Result, error) {
+				require.Equal(t, "123-abc", connID)
+
+				opts := &trustping.ClientOptions{}
+				for _, option := range options {
+					option(opts)
+				}
+
+				require.Equal(t, 1500*time.Millisecond, opts.Timeout)
+
+				return &trustping.Result{RoundTripTime: 42 * time.Millisecond, LastSeen: lastSeen}, nil
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Ping(&b, bytes.NewBufferString(samplePingRequest))
+		require.NoError(t, cmdErr)
+
+		response := PingResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, int64(42), response.RoundTripMs)
+		require.True(t, lastSeen.Equal(response.LastSeen))
+	})
+
+	t.Run("test ping - default timeout", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mocktrustping.MockTrustPingSvc{
+			PingFunc: func(_ string, options ...trustping.ClientOption) (*trustping.Result, error) {
+				require.Empty(t, options)
+
+				return &trustping.Result{}, nil
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Ping(&b, bytes.NewBufferString(`{"connectionID":"123-abc"}`))
+		require.NoError(t, cmdErr)
+	})
+
+	t.Run("test ping - validation errors", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mocktrustping.MockTrustPingSvc{}))
+		require.NoError(t, err)
+
+		tests := []struct {
+			request string
+			err     string
+		}{
+			{request: "--", err: "request decode"},
+			{request: `{"timeout_ms":100}`, err: errEmptyConnID},
+			{request: `{"connectionID":"123-abc","timeout_ms":-1}`, err: errNegativeTimeout},
+		}
+
+		for _, tc := range tests {
+			var b bytes.Buffer
+			cmdErr := cmd.Ping(&b, bytes.NewBufferString(tc.request))
+			require.Error(t, cmdErr)
+			require.Contains(t, cmdErr.Error(), tc.err)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+
+	t.Run("test ping - error", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mocktrustping.MockTrustPingSvc{
+			PingFunc: func(string, ...trustping.ClientOption) (*trustping.Result, error) {
+				return nil, errors.New(sampleErr)
+			},
+		}))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Ping(&b, bytes.NewBufferString(samplePingRequest))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), sampleErr)
+		require.Equal(t, PingErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func newMockProvider(svc *mocktrustping.MockTrustPingSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			trustping.TrustPing: svc,
+		},
+	}
+}
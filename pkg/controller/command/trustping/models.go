@@ -0,0 +1,25 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import "time"
+
+// PingRequest contains parameters for pinging the agent on the other end of the connection.
+type PingRequest struct {
+	// ConnectionID of the connection to be pinged.
+	ConnectionID string `json:"connectionID"`
+	// TimeoutMs is the time in milliseconds to wait for the ping response, the default timeout is used if not set.
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+}
+
+// PingResponse contains the outcome of the ping.
+type PingResponse struct {
+	// RoundTripMs is the round-trip time of the ping in milliseconds.
+	RoundTripMs int64 `json:"round_trip_ms"`
+	// LastSeen is the time the ping response was received.
+	LastSeen time.Time `json:"last_seen"`
+}
@@ -10,7 +10,10 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	actionmenucmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	basicmessagecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
 	didexchangecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
+	discoverfeaturescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/discoverfeatures"
 	introducecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	issuecredentialcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
@@ -18,10 +21,14 @@ import (
 	messagingcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/messaging"
 	outofbandcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
 	presentproofcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
+	trustpingcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
 	vdrcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	actionmenurest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
+	basicmessagerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/basicmessage"
 	didexchangerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
+	discoverfeaturesrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/discoverfeatures"
 	introducerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	issuecredentialrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
 	kmsrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/kms"
@@ -29,6 +36,7 @@ import (
 	messagingrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/messaging"
 	outofbandrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	presentproofrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/presentproof"
+	trustpingrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/trustping"
 	vdrrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdr"
 	verifiablerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
@@ -151,6 +159,30 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 		return nil, fmt.Errorf("create outofband rest command : %w", err)
 	}
 
+	// discover-features REST operation
+	discoverfeaturesOp, err := discoverfeaturesrest.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create discover-features rest command : %w", err)
+	}
+
+	// trust ping REST operation
+	trustpingOp, err := trustpingrest.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create trust ping rest command : %w", err)
+	}
+
+	// action menu REST operation
+	actionmenuOp, err := actionmenurest.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create action menu rest command : %w", err)
+	}
+
+	// basic message REST operation
+	basicmessageOp, err := basicmessagerest.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create basic message rest command : %w", err)
+	}
+
 	// kms command operation
 	kmscmd := kmsrest.New(ctx)
 
@@ -165,6 +197,10 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	allHandlers = append(allHandlers, presentproofOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, introduceOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, outofbandOp.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, discoverfeaturesOp.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, trustpingOp.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, actionmenuOp.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, basicmessageOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, kmscmd.GetRESTHandlers()...)
 
 	nhp, ok := notifier.(handlerProvider)
@@ -247,6 +283,30 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 		return nil, fmt.Errorf("create outofband command : %w", err)
 	}
 
+	// discover-features command operation
+	discoverfeatures, err := discoverfeaturescmd.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create discover-features command : %w", err)
+	}
+
+	// trust ping command operation
+	trustping, err := trustpingcmd.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create trust ping command : %w", err)
+	}
+
+	// action menu command operation
+	actionmenu, err := actionmenucmd.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create action menu command : %w", err)
+	}
+
+	// basic message command operation
+	basicmessage, err := basicmessagecmd.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create basic message command : %w", err)
+	}
+
 	// kms command operation
 	kmscmd := kms.New(ctx)
 
@@ -261,6 +321,10 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	allHandlers = append(allHandlers, presentproof.GetHandlers()...)
 	allHandlers = append(allHandlers, introduce.GetHandlers()...)
 	allHandlers = append(allHandlers, outofband.GetHandlers()...)
+	allHandlers = append(allHandlers, discoverfeatures.GetHandlers()...)
+	allHandlers = append(allHandlers, trustping.GetHandlers()...)
+	allHandlers = append(allHandlers, actionmenu.GetHandlers()...)
+	allHandlers = append(allHandlers, basicmessage.GetHandlers()...)
 
 	return allHandlers, nil
 }
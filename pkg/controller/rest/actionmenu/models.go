@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+)
+
+// actionMenuActionsRequest model
+//
+// Returns pending actions that have not yet to be executed or cancelled.
+//
+// swagger:parameters actionMenuActions
+type actionMenuActionsRequest struct{} // nolint: unused,deadcode
+
+// actionMenuActionsResponse model
+//
+// Represents Actions response message.
+//
+// swagger:response actionMenuActionsResponse
+type actionMenuActionsResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Actions []struct{ *protocol.Action } `json:"actions"`
+	}
+}
+
+// actionMenuSendMenuRequest model
+//
+// This is used for operation to send a menu.
+//
+// swagger:parameters actionMenuSendMenu
+type actionMenuSendMenuRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ConnectionID of the connection the menu is sent over
+		// required: true
+		ConnectionID string `json:"connectionID"`
+		// Menu to be sent
+		// required: true
+		Menu struct{ *protocol.Menu } `json:"menu"`
+	}
+}
+
+// actionMenuRequestMenuRequest model
+//
+// This is used for operation to request a menu.
+//
+// swagger:parameters actionMenuRequestMenu
+type actionMenuRequestMenuRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ConnectionID of the connection the menu is requested over
+		// required: true
+		ConnectionID string `json:"connectionID"`
+	}
+}
+
+// actionMenuMessageResponse model
+//
+// Represents a response message with the ID of the sent message.
+//
+// swagger:response actionMenuMessageResponse
+type actionMenuMessageResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ID of the sent message
+		ID string `json:"id"`
+	}
+}
+
+// actionMenuActiveMenuRequest model
+//
+// This is used for operation to get the active menu.
+//
+// swagger:parameters actionMenuActiveMenu
+type actionMenuActiveMenuRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ConnectionID of the connection the menu was received over
+		// required: true
+		ConnectionID string `json:"connectionID"`
+	}
+}
+
+// actionMenuActiveMenuResponse model
+//
+// Represents an ActiveMenu response message.
+//
+// swagger:response actionMenuActiveMenuResponse
+type actionMenuActiveMenuResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Menu struct{ *protocol.Menu } `json:"menu"`
+	}
+}
+
+// actionMenuPerformRequest model
+//
+// This is used for operation to perform an option of the active menu.
+//
+// swagger:parameters actionMenuPerform
+type actionMenuPerformRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ConnectionID of the connection the menu was received over
+		// required: true
+		ConnectionID string `json:"connectionID"`
+		// Name of the menu option
+		// required: true
+		Name string `json:"name"`
+		// Params are the values of the menu option form parameters
+		Params map[string]string `json:"params"`
+	}
+}
+
+// actionMenuAcceptMenuRequestRequest model
+//
+// This is used for operation to accept a menu request.
+//
+// swagger:parameters actionMenuAcceptMenuRequest
+type actionMenuAcceptMenuRequestRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+
+	// in: body
+	Body struct {
+		// Menu sent in response to the request
+		// required: true
+		Menu struct{ *protocol.Menu } `json:"menu"`
+	}
+}
+
+// actionMenuAcceptMenuRequestResponse model
+//
+// Represents an AcceptMenuRequest response message.
+//
+// swagger:response actionMenuAcceptMenuRequestResponse
+type actionMenuAcceptMenuRequestResponse struct{} // nolint: unused,deadcode
+
+// actionMenuDeclineMenuRequestRequest model
+//
+// This is used for operation to decline a menu request.
+//
+// swagger:parameters actionMenuDeclineMenuRequest
+type actionMenuDeclineMenuRequestRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+
+	// Reason is an explanation of why it was declined
+	Reason string `json:"reason"`
+}
+
+// actionMenuDeclineMenuRequestResponse model
+//
+// Represents a DeclineMenuRequest response message.
+//
+// swagger:response actionMenuDeclineMenuRequestResponse
+type actionMenuDeclineMenuRequestResponse struct{} // nolint: unused,deadcode
+
+// actionMenuAcceptPerformRequest model
+//
+// This is used for operation to accept a perform request.
+//
+// swagger:parameters actionMenuAcceptPerform
+type actionMenuAcceptPerformRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+
+	// in: body
+	Body struct {
+		// Menu optionally sent in response to the perform request
+		Menu struct{ *protocol.Menu } `json:"menu"`
+	}
+}
+
+// actionMenuAcceptPerformResponse model
+//
+// Represents an AcceptPerform response message.
+//
+// swagger:response actionMenuAcceptPerformResponse
+type actionMenuAcceptPerformResponse struct{} // nolint: unused,deadcode
+
+// actionMenuDeclinePerformRequest model
+//
+// This is used for operation to decline a perform request.
+//
+// swagger:parameters actionMenuDeclinePerform
+type actionMenuDeclinePerformRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+
+	// Reason is an explanation of why it was declined
+	Reason string `json:"reason"`
+}
+
+// actionMenuDeclinePerformResponse model
+//
+// Represents a DeclinePerform response message.
+//
+// swagger:response actionMenuDeclinePerformResponse
+type actionMenuDeclinePerformResponse struct{} // nolint: unused,deadcode
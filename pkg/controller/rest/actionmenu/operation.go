@@ -0,0 +1,226 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	client "github.com/hyperledger/aries-framework-go/pkg/client/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+)
+
+// constants for operation action menu.
+const (
+	OperationID        = "/actionmenu"
+	Actions            = OperationID + "/actions"
+	SendMenu           = OperationID + "/send-menu"
+	RequestMenu        = OperationID + "/request-menu"
+	ActiveMenu         = OperationID + "/active-menu"
+	Perform            = OperationID + "/perform"
+	AcceptMenuRequest  = OperationID + "/{piid}/accept-menu-request"
+	DeclineMenuRequest = OperationID + "/{piid}/decline-menu-request"
+	AcceptPerform      = OperationID + "/{piid}/accept-perform"
+	DeclinePerform     = OperationID + "/{piid}/decline-perform"
+)
+
+// Operation is controller REST service controller for the action menu.
+type Operation struct {
+	command  *actionmenu.Command
+	handlers []rest.Handler
+}
+
+// New returns new action menu rest client protocol instance.
+func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
+	cmd, err := actionmenu.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("action menu command : %w", err)
+	}
+
+	o := &Operation{command: cmd}
+	o.registerHandler()
+
+	return o, nil
+}
+
+// GetRESTHandlers get all controller API handler available for this protocol service.
+func (c *Operation) GetRESTHandlers() []rest.Handler {
+	return c.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
+func (c *Operation) registerHandler() {
+	c.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(Actions, http.MethodGet, c.Actions),
+		cmdutil.NewHTTPHandler(SendMenu, http.MethodPost, c.SendMenu),
+		cmdutil.NewHTTPHandler(RequestMenu, http.MethodPost, c.RequestMenu),
+		cmdutil.NewHTTPHandler(ActiveMenu, http.MethodPost, c.ActiveMenu),
+		cmdutil.NewHTTPHandler(Perform, http.MethodPost, c.Perform),
+		cmdutil.NewHTTPHandler(AcceptMenuRequest, http.MethodPost, c.AcceptMenuRequest),
+		cmdutil.NewHTTPHandler(DeclineMenuRequest, http.MethodPost, c.DeclineMenuRequest),
+		cmdutil.NewHTTPHandler(AcceptPerform, http.MethodPost, c.AcceptPerform),
+		cmdutil.NewHTTPHandler(DeclinePerform, http.MethodPost, c.DeclinePerform),
+	}
+}
+
+// Actions swagger:route GET /actionmenu/actions action-menu actionMenuActions
+//
+// Returns pending actions that have not yet to be executed or cancelled.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuActionsResponse
+func (c *Operation) Actions(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(c.command.Actions, rw, nil)
+}
+
+// SendMenu swagger:route POST /actionmenu/send-menu action-menu actionMenuSendMenu
+//
+// Sends a menu.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuMessageResponse
+func (c *Operation) SendMenu(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SendMenu, rw, req.Body)
+}
+
+// RequestMenu swagger:route POST /actionmenu/request-menu action-menu actionMenuRequestMenu
+//
+// Requests a menu.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuMessageResponse
+func (c *Operation) RequestMenu(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RequestMenu, rw, req.Body)
+}
+
+// ActiveMenu swagger:route POST /actionmenu/active-menu action-menu actionMenuActiveMenu
+//
+// Returns the last menu received over the connection.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuActiveMenuResponse
+func (c *Operation) ActiveMenu(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ActiveMenu, rw, req.Body)
+}
+
+// Perform swagger:route POST /actionmenu/perform action-menu actionMenuPerform
+//
+// Performs an option of the active menu.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuMessageResponse
+func (c *Operation) Perform(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.Perform, rw, req.Body)
+}
+
+// AcceptMenuRequest swagger:route POST /actionmenu/{piid}/accept-menu-request action-menu actionMenuAcceptMenuRequest
+//
+// Accepts a menu request.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuAcceptMenuRequestResponse
+func (c *Operation) AcceptMenuRequest(rw http.ResponseWriter, req *http.Request) {
+	if ok, r := toCommandRequest(rw, req); ok {
+		rest.Execute(c.command.AcceptMenuRequest, rw, r)
+	}
+}
+
+// DeclineMenuRequest swagger:route POST /actionmenu/{piid}/decline-menu-request action-menu actionMenuDeclineMenuRequest
+//
+// Declines a menu request.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuDeclineMenuRequestResponse
+func (c *Operation) DeclineMenuRequest(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeclineMenuRequest, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q,
+		"reason":%q
+	}`, mux.Vars(req)["piid"], req.URL.Query().Get("reason"))))
+}
+
+// AcceptPerform swagger:route POST /actionmenu/{piid}/accept-perform action-menu actionMenuAcceptPerform
+//
+// Accepts a perform request.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuAcceptPerformResponse
+func (c *Operation) AcceptPerform(rw http.ResponseWriter, req *http.Request) {
+	if ok, r := toCommandRequest(rw, req); ok {
+		rest.Execute(c.command.AcceptPerform, rw, r)
+	}
+}
+
+// DeclinePerform swagger:route POST /actionmenu/{piid}/decline-perform action-menu actionMenuDeclinePerform
+//
+// Declines a perform request.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuDeclinePerformResponse
+func (c *Operation) DeclinePerform(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeclinePerform, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q,
+		"reason":%q
+	}`, mux.Vars(req)["piid"], req.URL.Query().Get("reason"))))
+}
+
+func toCommandRequest(rw http.ResponseWriter, req *http.Request) (bool, io.Reader) {
+	var buf bytes.Buffer
+
+	if req.Body != nil {
+		// nolint: errcheck
+		_, _ = io.Copy(&buf, req.Body)
+	}
+
+	if !isJSONMap(buf.Bytes()) {
+		rest.SendHTTPStatusError(rw,
+			http.StatusBadRequest,
+			actionmenu.InvalidRequestErrorCode,
+			errors.New("payload was not provided"),
+		)
+
+		return false, nil
+	}
+
+	ending := fmt.Sprintf(`"piid":%q}`, mux.Vars(req)["piid"])
+
+	payload := strings.TrimSpace(buf.String())
+	if payload == "{}" {
+		payload = "{" + ending
+	} else {
+		payload = buf.String()[:buf.Len()-1] + "," + ending
+	}
+
+	return true, bytes.NewBufferString(payload)
+}
+
+func isJSONMap(data []byte) bool {
+	var v struct{}
+	return isJSON(data, &v)
+}
+
+func isJSON(data []byte, v interface{}) bool {
+	return json.Unmarshal(data, &v) == nil
+}
@@ -0,0 +1,277 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockactionmenu "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/actionmenu"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.Equal(t, 9, len(operation.GetRESTHandlers()))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		operation, err := New(&mockprovider.Provider{}, mocknotifier.NewMockNotifier(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "action menu command")
+		require.Nil(t, operation)
+	})
+}
+
+func TestOperation_Actions(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+		ActionsFunc: func() ([]actionmenu.Action, error) {
+			return []actionmenu.Action{{PIID: "1234"}}, nil
+		},
+	}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	buf, code, err := sendRequestToHandler(handlerLookup(t, operation, Actions), nil, Actions)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, buf.String(), "1234")
+}
+
+func TestOperation_SendMenu(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+		SendMenuFunc: func(string, *actionmenu.Menu) (string, error) {
+			return "msg-id", nil
+		},
+	}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	buf, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, SendMenu),
+		bytes.NewBufferString(`{"connectionID":"conn","menu":{"title":"Menu"}}`),
+		SendMenu,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, buf.String(), "msg-id")
+}
+
+func TestOperation_RequestMenu(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, RequestMenu),
+		bytes.NewBufferString(`{"connectionID":"conn"}`),
+		RequestMenu,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestOperation_ActiveMenu(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActiveMenuFunc: func(string) (*actionmenu.Menu, error) {
+				return &actionmenu.Menu{Title: "Menu"}, nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, ActiveMenu),
+			bytes.NewBufferString(`{"connectionID":"conn"}`),
+			ActiveMenu,
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, buf.String(), "Menu")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActiveMenuFunc: func(string) (*actionmenu.Menu, error) {
+				return nil, errors.New("sample-error")
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, ActiveMenu),
+			bytes.NewBufferString(`{"connectionID":"conn"}`),
+			ActiveMenu,
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, buf.String(), "sample-error")
+	})
+}
+
+func TestOperation_Perform(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, Perform),
+		bytes.NewBufferString(`{"connectionID":"conn","name":"opt"}`),
+		Perform,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestOperation_AcceptMenuRequest(t *testing.T) {
+	t.Run("No payload", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, AcceptMenuRequest),
+			nil,
+			strings.Replace(AcceptMenuRequest, `{piid}`, "1234", 1),
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, buf.String(), "payload was not provided")
+	})
+
+	t.Run("Empty menu", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, AcceptMenuRequest),
+			bytes.NewBufferString(`{}`),
+			strings.Replace(AcceptMenuRequest, `{piid}`, "1234", 1),
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, buf.String(), "empty menu")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+			ActionContinueFunc: func(piID string, _ actionmenu.Opt) error {
+				require.Equal(t, "1234", piID)
+
+				return nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		_, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, AcceptMenuRequest),
+			bytes.NewBufferString(`{"menu":{"title":"Menu"}}`),
+			strings.Replace(AcceptMenuRequest, `{piid}`, "1234", 1),
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestOperation_DeclineMenuRequest(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{
+		ActionStopFunc: func(piID string, err error) error {
+			require.Equal(t, "1234", piID)
+			require.EqualError(t, err, "reason")
+
+			return nil
+		},
+	}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, DeclineMenuRequest),
+		nil,
+		strings.Replace(DeclineMenuRequest, `{piid}`, "1234", 1)+"?reason=reason",
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestOperation_AcceptPerform(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, AcceptPerform),
+		bytes.NewBufferString(`{}`),
+		strings.Replace(AcceptPerform, `{piid}`, "1234", 1),
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestOperation_DeclinePerform(t *testing.T) {
+	operation, err := New(newMockProvider(&mockactionmenu.MockActionMenuSvc{}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, DeclinePerform),
+		nil,
+		strings.Replace(DeclinePerform, `{piid}`, "1234", 1),
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func newMockProvider(svc *mockactionmenu.MockActionMenuSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceValue: svc,
+	}
+}
+
+func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
+	t.Helper()
+
+	handlers := op.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		if h.Path() == lookup {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+// sendRequestToHandler reads response from given http handle func.
+func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
+	// prepare request
+	req, err := http.NewRequest(handler.Method(), path, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// prepare router
+	router := mux.NewRouter()
+
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+
+	// serve http on given response and request
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+)
+
+// basicMessageSendMessageRequest model
+//
+// This is used for operation to send a basic message.
+//
+// swagger:parameters basicMessageSendMessage
+type basicMessageSendMessageRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ConnectionID of the connection the message is sent over
+		// required: true
+		ConnectionID string `json:"connectionID"`
+		// Content of the message
+		// required: true
+		Content string `json:"content"`
+		// Locale of the content
+		Locale string `json:"locale,omitempty"`
+	}
+}
+
+// basicMessageMessageResponse model
+//
+// Represents a response message with the ID of the sent message.
+//
+// swagger:response basicMessageMessageResponse
+type basicMessageMessageResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// ID of the sent message
+		ID string `json:"id"`
+	}
+}
+
+// basicMessageHistoryRequest model
+//
+// This is used for operation to query the message history, the messages are sorted by time.
+//
+// swagger:parameters basicMessageHistory
+type basicMessageHistoryRequest struct { // nolint: unused,deadcode
+	// ConnectionID of the connection, the messages of all the connections are returned if empty
+	// in: query
+	ConnectionID string `json:"connectionID"`
+	// Direction of the messages (sent or received)
+	// in: query
+	Direction string `json:"direction"`
+	// Descending returns the newest messages first
+	// in: query
+	Descending bool `json:"descending"`
+	// Limit is the maximum number of messages of the page
+	// in: query
+	Limit int `json:"limit"`
+	// Cursor is the next_cursor of the previous page
+	// in: query
+	Cursor string `json:"cursor"`
+}
+
+// basicMessageHistoryResponse model
+//
+// Represents a page of the message history.
+//
+// swagger:response basicMessageHistoryResponse
+type basicMessageHistoryResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Messages []struct{ *protocol.Record } `json:"messages"`
+		// NextCursor is the cursor of the next page, it is empty on the last page
+		NextCursor string `json:"next_cursor,omitempty"`
+	}
+}
+
+// basicMessageDeleteHistoryRequest model
+//
+// This is used for operation to delete the message history of the connection.
+//
+// swagger:parameters basicMessageDeleteHistory
+type basicMessageDeleteHistoryRequest struct { // nolint: unused,deadcode
+	// ConnectionID of the connection
+	//
+	// in: path
+	// required: true
+	ConnectionID string `json:"connectionID"`
+}
+
+// basicMessageDeleteHistoryResponse model
+//
+// Represents a DeleteHistory response message.
+//
+// swagger:response basicMessageDeleteHistoryResponse
+type basicMessageDeleteHistoryResponse struct{} // nolint: unused,deadcode
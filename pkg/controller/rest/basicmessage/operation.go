@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	client "github.com/hyperledger/aries-framework-go/pkg/client/basicmessage"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/basicmessage"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+)
+
+// constants for operation basic message.
+const (
+	OperationID   = "/basicmessage"
+	SendMessage   = OperationID + "/send-message"
+	History       = OperationID + "/history"
+	DeleteHistory = OperationID + "/history/{connectionID}"
+
+	limitQueryParam      = "limit"
+	descendingQueryParam = "descending"
+)
+
+// Operation is controller REST service controller for the basic message.
+type Operation struct {
+	command  *basicmessage.Command
+	handlers []rest.Handler
+}
+
+// New returns new basic message rest client protocol instance.
+func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
+	cmd, err := basicmessage.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("basic message command : %w", err)
+	}
+
+	o := &Operation{command: cmd}
+	o.registerHandler()
+
+	return o, nil
+}
+
+// GetRESTHandlers get all controller API handler available for this protocol service.
+func (c *Operation) GetRESTHandlers() []rest.Handler {
+	return c.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
+func (c *Operation) registerHandler() {
+	c.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(SendMessage, http.MethodPost, c.SendMessage),
+		cmdutil.NewHTTPHandler(History, http.MethodGet, c.History),
+		cmdutil.NewHTTPHandler(DeleteHistory, http.MethodDelete, c.DeleteHistory),
+	}
+}
+
+// SendMessage swagger:route POST /basicmessage/send-message basic-message basicMessageSendMessage
+//
+// Sends a basic message.
+//
+// Responses:
+//    default: genericError
+//        200: basicMessageMessageResponse
+func (c *Operation) SendMessage(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SendMessage, rw, req.Body)
+}
+
+// History swagger:route GET /basicmessage/history basic-message basicMessageHistory
+//
+// Returns a page of the message history.
+//
+// Responses:
+//    default: genericError
+//        200: basicMessageHistoryResponse
+func (c *Operation) History(rw http.ResponseWriter, req *http.Request) {
+	reqBytes, err := queryArgsAsJSON(req.URL.Query())
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, basicmessage.InvalidRequestErrorCode, err)
+		return
+	}
+
+	rest.Execute(c.command.History, rw, bytes.NewReader(reqBytes))
+}
+
+// DeleteHistory swagger:route DELETE /basicmessage/history/{connectionID} basic-message basicMessageDeleteHistory
+//
+// Deletes the message history of the connection.
+//
+// Responses:
+//    default: genericError
+//        200: basicMessageDeleteHistoryResponse
+func (c *Operation) DeleteHistory(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeleteHistory, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"connectionID":%q
+	}`, mux.Vars(req)["connectionID"])))
+}
+
+func queryArgsAsJSON(vals url.Values) ([]byte, error) {
+	args := make(map[string]interface{})
+
+	for k, v := range vals {
+		if len(v) == 0 {
+			continue
+		}
+
+		switch k {
+		case limitQueryParam:
+			n, err := strconv.Atoi(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid limit %s: %w", v[0], err)
+			}
+
+			args[k] = n
+		case descendingQueryParam:
+			descending, err := strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid descending %s: %w", v[0], err)
+			}
+
+			args[k] = descending
+		default:
+			args[k] = v[0]
+		}
+	}
+
+	return json.Marshal(args)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockbasicmessage "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/basicmessage"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.Equal(t, 3, len(operation.GetRESTHandlers()))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		operation, err := New(&mockprovider.Provider{}, mocknotifier.NewMockNotifier(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "basic message command")
+		require.Nil(t, operation)
+	})
+}
+
+func TestOperation_SendMessage(t *testing.T) {
+	operation, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+		SendMessageFunc: func(string, *basicmessage.Message) (string, error) {
+			return "msg-id", nil
+		},
+	}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	buf, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, SendMessage),
+		bytes.NewBufferString(`{"connectionID":"conn","content":"Hello"}`),
+		SendMessage,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, buf.String(), "msg-id")
+}
+
+func TestOperation_History(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			HistoryFunc: func(params *basicmessage.QueryParams) (*basicmessage.QueryResult, error) {
+				require.Equal(t, &basicmessage.QueryParams{
+					ConnectionID: "conn",
+					Direction:    basicmessage.DirectionSent,
+					Descending:   true,
+					Limit:        5,
+					Cursor:       "abc",
+				}, params)
+
+				return &basicmessage.QueryResult{
+					Records:    []*basicmessage.Record{{MessageID: "msg-id"}},
+					NextCursor: "def",
+				}, nil
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, History), nil,
+			History+"?connectionID=conn&direction=sent&descending=true&limit=5&cursor=abc",
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, buf.String(), "msg-id")
+		require.Contains(t, buf.String(), `"next_cursor":"def"`)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		operation, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+			HistoryFunc: func(*basicmessage.QueryParams) (*basicmessage.QueryResult, error) {
+				return nil, errors.New("sample-error")
+			},
+		}), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		_, code, err := sendRequestToHandler(handlerLookup(t, operation, History), nil, History)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+
+		_, code, err = sendRequestToHandler(handlerLookup(t, operation, History), nil, History+"?limit=five")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		_, code, err = sendRequestToHandler(handlerLookup(t, operation, History), nil, History+"?descending=maybe")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestOperation_DeleteHistory(t *testing.T) {
+	operation, err := New(newMockProvider(&mockbasicmessage.MockBasicMessageSvc{
+		DeleteHistoryFunc: func(connectionID string) error {
+			require.Equal(t, "conn", connectionID)
+
+			return nil
+		},
+	}), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(handlerLookup(t, operation, DeleteHistory), nil, History+"/conn")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestQueryArgsAsJSON(t *testing.T) {
+	raw, err := queryArgsAsJSON(url.Values{"connectionID": {"conn"}, "limit": {"2"}, "cursor": {}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"connectionID":"conn","limit":2}`, string(raw))
+}
+
+func newMockProvider(svc *mockbasicmessage.MockBasicMessageSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceValue: svc,
+	}
+}
+
+func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
+	t.Helper()
+
+	handlers := op.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		if h.Path() == lookup {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+// sendRequestToHandler reads response from given http handle func.
+func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
+	// prepare request
+	req, err := http.NewRequest(handler.Method(), path, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// prepare router
+	router := mux.NewRouter()
+
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+
+	// serve http on given response and request
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code, nil
+}
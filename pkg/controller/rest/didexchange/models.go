@@ -9,6 +9,7 @@ package didexchange
 import (
 	didexchangeSvc "github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 // createInvitationRequest model
@@ -177,6 +178,9 @@ type queryConnectionsResponse struct { // nolint: unused,deadcode
 	// in: body
 	Body struct {
 		Results []*didexchangeSvc.Connection `json:"results,omitempty"`
+
+		// NextCursor is the cursor of the next page, it is empty on the last page
+		NextCursor string `json:"next_cursor,omitempty"`
 	}
 }
 
@@ -234,6 +238,118 @@ type RemoveConnectionResponse struct {
 	Body struct{}
 }
 
+// rotateDIDRequest model
+//
+// This is used for operation to rotate my DID on the connection
+//
+// swagger:parameters rotateDID
+type rotateDIDRequest struct { // nolint: unused,deadcode
+	// Connection ID
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+
+	// Optional DID replacing my DID, a new peer DID is created if empty
+	ToDID string `json:"to_did"`
+
+	// Optional specifies router connections of the new peer DID (comma-separated values)
+	RouterConnections string `json:"router_connections"`
+}
+
+// rotateDIDResponse model
+//
+// This is used for returning the thread ID of the DID rotation
+//
+// swagger:response rotateDIDResponse
+type rotateDIDResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body didexchange.RotateDIDResponse
+}
+
+// setConnectionMetadataRequest model
+//
+// This is used for operation to set the metadata of the connection
+//
+// swagger:parameters setConnectionMetadata
+type setConnectionMetadataRequest struct { // nolint: unused,deadcode
+	// Connection ID
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+
+	// in: body
+	Body struct {
+		// The metadata merged into the metadata of the connection, the keys with an empty value are removed
+		Metadata map[string]string `json:"metadata"`
+	}
+}
+
+// connectionTagsRequest model
+//
+// This is used for operation to add or remove the tags of the connection
+//
+// swagger:parameters addConnectionTags removeConnectionTags
+type connectionTagsRequest struct { // nolint: unused,deadcode
+	// Connection ID
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+
+	// in: body
+	Body struct {
+		// The tags to add or remove
+		Tags []string `json:"tags"`
+	}
+}
+
+// updateConnectionResponse model
+//
+// response of the connection metadata and tags actions
+//
+// swagger:response updateConnectionResponse
+type updateConnectionResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
+
+// invitationIDRequest model
+//
+// This is used for fetching the policy of the invitation or revoking the invitation
+//
+// swagger:parameters getInvitationPolicy revokeInvitation
+type invitationIDRequest struct { // nolint: unused,deadcode
+	// The ID of the invitation
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
+
+// invitationPolicyResponse model
+//
+// This is used for returning the policy and the number of uses of the invitation
+//
+// swagger:response invitationPolicyResponse
+type invitationPolicyResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Result *connection.InvitationPolicy `json:"result,omitempty"`
+	}
+}
+
+// revokeInvitationResponse model
+//
+// response of the revoke invitation action
+//
+// swagger:response revokeInvitationResponse
+type revokeInvitationResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
+
 // createConnectionResp model
 //
 // This is used as the response model for save connection api.
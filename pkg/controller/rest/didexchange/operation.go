@@ -10,8 +10,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -35,6 +38,17 @@ const (
 	AcceptExchangeRequest        = OperationID + "/{id}/accept-request"
 	CreateConnection             = OperationID + "/create"
 	RemoveConnection             = OperationID + "/{id}/remove"
+	RotateDID                    = OperationID + "/{id}/rotate-did"
+	ConnectionMetadata           = OperationID + "/{id}/metadata"
+	AddConnectionTags            = OperationID + "/{id}/add-tags"
+	RemoveConnectionTags         = OperationID + "/{id}/remove-tags"
+	InvitationPolicy             = OperationID + "/invitations/{id}/policy"
+	RevokeInvitation             = OperationID + "/invitations/{id}/revoke"
+
+	tagsQueryParam       = "tags"
+	limitQueryParam      = "limit"
+	maxUsesQueryParam    = "max_uses"
+	descendingQueryParam = "descending"
 )
 
 // provider contains dependencies for the Exchange protocol and is typically created by using aries.Context().
@@ -83,6 +97,12 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(AcceptExchangeRequest, http.MethodPost, c.AcceptExchangeRequest),
 		cmdutil.NewHTTPHandler(CreateConnection, http.MethodPost, c.CreateConnection),
 		cmdutil.NewHTTPHandler(RemoveConnection, http.MethodPost, c.RemoveConnection),
+		cmdutil.NewHTTPHandler(RotateDID, http.MethodPost, c.RotateDID),
+		cmdutil.NewHTTPHandler(ConnectionMetadata, http.MethodPost, c.SetConnectionMetadata),
+		cmdutil.NewHTTPHandler(AddConnectionTags, http.MethodPost, c.AddConnectionTags),
+		cmdutil.NewHTTPHandler(RemoveConnectionTags, http.MethodPost, c.RemoveConnectionTags),
+		cmdutil.NewHTTPHandler(InvitationPolicy, http.MethodGet, c.GetInvitationPolicy),
+		cmdutil.NewHTTPHandler(RevokeInvitation, http.MethodPost, c.RevokeInvitation),
 	}
 }
 
@@ -94,7 +114,7 @@ func (c *Operation) registerHandler() {
 //    default: genericError
 //        200: createInvitationResponse
 func (c *Operation) CreateInvitation(rw http.ResponseWriter, req *http.Request) {
-	reqBytes, err := queryValuesAsJSON(req.URL.Query())
+	reqBytes, err := queryArgsAsJSON(req.URL.Query())
 	if err != nil {
 		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
 		return
@@ -177,7 +197,7 @@ func (c *Operation) AcceptExchangeRequest(rw http.ResponseWriter, req *http.Requ
 //    default: genericError
 //        200: queryConnectionsResponse
 func (c *Operation) QueryConnections(rw http.ResponseWriter, req *http.Request) {
-	reqBytes, err := queryValuesAsJSON(req.URL.Query())
+	reqBytes, err := queryArgsAsJSON(req.URL.Query())
 	if err != nil {
 		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
 		return
@@ -233,6 +253,176 @@ func (c *Operation) RemoveConnection(rw http.ResponseWriter, req *http.Request)
 	rest.Execute(c.command.RemoveConnection, rw, bytes.NewBufferString(request))
 }
 
+// RotateDID swagger:route POST /connections/{id}/rotate-did did-exchange rotateDID
+//
+// Replaces my DID on the completed connection (did-rotate protocol).
+//
+// Responses:
+//    default: genericError
+//    200: rotateDIDResponse
+func (c *Operation) RotateDID(rw http.ResponseWriter, req *http.Request) {
+	id, found := getIDFromRequest(rw, req)
+	if !found {
+		return
+	}
+
+	request := fmt.Sprintf(`{"id":%q, "to_did":%q, "router_connections":%q}`,
+		id, req.URL.Query().Get("to_did"), req.URL.Query().Get("router_connections"))
+
+	rest.Execute(c.command.RotateDID, rw, bytes.NewBufferString(request))
+}
+
+// SetConnectionMetadata swagger:route POST /connections/{id}/metadata did-exchange setConnectionMetadata
+//
+// Merges the given metadata into the metadata of the connection, the keys with an empty value are removed.
+//
+// Responses:
+//    default: genericError
+//    200: updateConnectionResponse
+func (c *Operation) SetConnectionMetadata(rw http.ResponseWriter, req *http.Request) {
+	if request, ok := withConnectionID(rw, req); ok {
+		rest.Execute(c.command.SetConnectionMetadata, rw, request)
+	}
+}
+
+// AddConnectionTags swagger:route POST /connections/{id}/add-tags did-exchange addConnectionTags
+//
+// Adds the tags to the connection.
+//
+// Responses:
+//    default: genericError
+//    200: updateConnectionResponse
+func (c *Operation) AddConnectionTags(rw http.ResponseWriter, req *http.Request) {
+	if request, ok := withConnectionID(rw, req); ok {
+		rest.Execute(c.command.AddConnectionTags, rw, request)
+	}
+}
+
+// RemoveConnectionTags swagger:route POST /connections/{id}/remove-tags did-exchange removeConnectionTags
+//
+// Removes the tags from the connection.
+//
+// Responses:
+//    default: genericError
+//    200: updateConnectionResponse
+func (c *Operation) RemoveConnectionTags(rw http.ResponseWriter, req *http.Request) {
+	if request, ok := withConnectionID(rw, req); ok {
+		rest.Execute(c.command.RemoveConnectionTags, rw, request)
+	}
+}
+
+// GetInvitationPolicy swagger:route GET /connections/invitations/{id}/policy did-exchange getInvitationPolicy
+//
+// Fetch the policy (max uses, expiry, revocation) and the number of uses of the invitation.
+//
+// Responses:
+//    default: genericError
+//    200: invitationPolicyResponse
+func (c *Operation) GetInvitationPolicy(rw http.ResponseWriter, req *http.Request) {
+	id, found := getIDFromRequest(rw, req)
+	if !found {
+		return
+	}
+
+	request := fmt.Sprintf(`{"id":"%s"}`, id)
+
+	rest.Execute(c.command.GetInvitationPolicy, rw, bytes.NewBufferString(request))
+}
+
+// RevokeInvitation swagger:route POST /connections/invitations/{id}/revoke did-exchange revokeInvitation
+//
+// Revokes the invitation, the exchange requests for the invitation are not accepted anymore.
+//
+// Responses:
+//    default: genericError
+//    200: revokeInvitationResponse
+func (c *Operation) RevokeInvitation(rw http.ResponseWriter, req *http.Request) {
+	id, found := getIDFromRequest(rw, req)
+	if !found {
+		return
+	}
+
+	request := fmt.Sprintf(`{"id":"%s"}`, id)
+
+	rest.Execute(c.command.RevokeInvitation, rw, bytes.NewBufferString(request))
+}
+
+// withConnectionID adds the connection ID of the path to the JSON request body.
+func withConnectionID(rw http.ResponseWriter, req *http.Request) (io.Reader, bool) {
+	id, found := getIDFromRequest(rw, req)
+	if !found {
+		return nil, false
+	}
+
+	args := make(map[string]interface{})
+
+	if err := json.NewDecoder(req.Body).Decode(&args); err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
+
+		return nil, false
+	}
+
+	args["id"] = id
+
+	reqBytes, err := json.Marshal(args)
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
+
+		return nil, false
+	}
+
+	return bytes.NewReader(reqBytes), true
+}
+
+// queryArgsAsJSON converts the query strings to JSON bytes, the tags are given as comma-separated values,
+// the limit and the max uses are integers.
+func queryArgsAsJSON(vals url.Values) ([]byte, error) {
+	args := make(map[string]interface{})
+
+	for k, v := range vals {
+		if len(v) == 0 {
+			continue
+		}
+
+		switch k {
+		case tagsQueryParam:
+			args[k] = splitTags(v)
+		case limitQueryParam, maxUsesQueryParam:
+			n, err := strconv.Atoi(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s: %w", k, v[0], err)
+			}
+
+			args[k] = n
+		case descendingQueryParam:
+			descending, err := strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid descending %s: %w", v[0], err)
+			}
+
+			args[k] = descending
+		default:
+			args[k] = v[0]
+		}
+	}
+
+	return json.Marshal(args)
+}
+
+func splitTags(vals []string) []string {
+	var tags []string
+
+	for _, val := range vals {
+		for _, tag := range strings.Split(val, ",") {
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
+
 // queryValuesAsJSON converts query strings to `map[string]string`
 // and marshals them to JSON bytes.
 func queryValuesAsJSON(vals url.Values) ([]byte, error) {
@@ -15,7 +15,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -27,11 +29,13 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	didexsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	didrotatesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	mockdidrotate "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didrotate"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
@@ -117,6 +121,26 @@ func TestOperation_CreateInvitation(t *testing.T) {
 		require.Empty(t, response.Alias)
 	})
 
+	t.Run("Successful CreateInvitation with max uses", func(t *testing.T) {
+		handler := getHandler(t, CreateInvitationPath)
+		buf, err := getSuccessResponseFromHandler(handler, nil, handler.Path()+"?max_uses=2")
+		require.NoError(t, err)
+
+		response := didexchange.CreateInvitationResponse{}
+		err = json.Unmarshal(buf.Bytes(), &response)
+		require.NoError(t, err)
+		require.NotEmpty(t, response.Invitation)
+	})
+
+	t.Run("CreateInvitation with invalid max uses", func(t *testing.T) {
+		handler := getHandler(t, CreateInvitationPath)
+
+		buf, code, err := sendRequestToHandler(handler, nil, handler.Path()+"?max_uses=two")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, didexchange.InvalidRequestErrorCode, buf.Bytes())
+	})
+
 	t.Run("CreateInvitation failure", func(t *testing.T) {
 		const errMsg = "sample-err-01"
 		handler := getHandlerWithError(t, CreateInvitationPath, &fails{storePutErr: fmt.Errorf(errMsg)})
@@ -400,6 +424,116 @@ func TestOperation_RemoveConnection(t *testing.T) {
 	})
 }
 
+func TestOperation_RotateDID(t *testing.T) {
+	t.Run("test rotate DID success", func(t *testing.T) {
+		handler := getHandler(t, RotateDID)
+		buf, err := getSuccessResponseFromHandler(handler, nil, OperationID+"/1234/rotate-did?to_did=did:example:new")
+		require.NoError(t, err)
+
+		response := &didexchange.RotateDIDResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), response))
+		require.Equal(t, "th1234", response.ThreadID)
+	})
+}
+
+func TestOperation_ConnectionMetadataAndTags(t *testing.T) {
+	t.Run("test set metadata success", func(t *testing.T) {
+		handler := getHandler(t, ConnectionMetadata)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBufferString(`{"metadata":{"alias":"Alice"}}`),
+			OperationID+"/1234/metadata")
+		require.NoError(t, err)
+		require.Empty(t, buf.Bytes())
+	})
+	t.Run("test add and remove tags success", func(t *testing.T) {
+		for path, lookup := range map[string]string{"add-tags": AddConnectionTags, "remove-tags": RemoveConnectionTags} {
+			handler := getHandler(t, lookup)
+			buf, err := getSuccessResponseFromHandler(handler, bytes.NewBufferString(`{"tags":["gold"]}`),
+				OperationID+"/1234/"+path)
+			require.NoError(t, err)
+			require.Empty(t, buf.Bytes())
+		}
+	})
+	t.Run("test connection not found", func(t *testing.T) {
+		handler := getHandler(t, AddConnectionTags)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{"tags":["gold"]}`),
+			OperationID+"/unknown/add-tags")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyRESTError(t, didexchange.UpdateConnectionErrorCode, buf.Bytes())
+	})
+	t.Run("test invalid request body", func(t *testing.T) {
+		handler := getHandler(t, ConnectionMetadata)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`--`), OperationID+"/1234/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, didexchange.InvalidRequestErrorCode, buf.Bytes())
+	})
+}
+
+func TestOperation_InvitationPolicy(t *testing.T) {
+	t.Run("test get policy of unknown invitation", func(t *testing.T) {
+		handler := getHandler(t, InvitationPolicy)
+		buf, code, err := sendRequestToHandler(handler, nil, OperationID+"/invitations/unknown/policy")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyRESTError(t, didexchange.InvitationPolicyErrorCode, buf.Bytes())
+	})
+	t.Run("test revoke unknown invitation", func(t *testing.T) {
+		handler := getHandler(t, RevokeInvitation)
+		buf, code, err := sendRequestToHandler(handler, nil, OperationID+"/invitations/unknown/revoke")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyRESTError(t, didexchange.InvitationPolicyErrorCode, buf.Bytes())
+	})
+}
+
+func TestQueryArgsAsJSON(t *testing.T) {
+	vals, err := url.ParseQuery("state=completed&tags=customer,gold&tags=vip")
+	require.NoError(t, err)
+
+	reqBytes, err := queryArgsAsJSON(vals)
+	require.NoError(t, err)
+
+	args := didexchange.QueryConnectionsArgs{}
+	require.NoError(t, json.Unmarshal(reqBytes, &args))
+	require.Equal(t, "completed", args.State)
+	require.Equal(t, []string{"customer", "gold", "vip"}, args.Tags)
+
+	vals, err = url.ParseQuery("label_prefix=bob&sort_by=created&descending=true&limit=10&cursor=abc")
+	require.NoError(t, err)
+
+	reqBytes, err = queryArgsAsJSON(vals)
+	require.NoError(t, err)
+
+	args = didexchange.QueryConnectionsArgs{}
+	require.NoError(t, json.Unmarshal(reqBytes, &args))
+	require.Equal(t, "bob", args.LabelPrefix)
+	require.Equal(t, "created", args.SortBy)
+	require.True(t, args.Descending)
+	require.Equal(t, 10, args.Limit)
+	require.Equal(t, "abc", args.Cursor)
+
+	_, err = queryArgsAsJSON(url.Values{"limit": []string{"ten"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid limit")
+
+	reqBytes, err = queryArgsAsJSON(url.Values{"max_uses": []string{"3"}, "expires": []string{"2030-01-01T00:00:00Z"}})
+	require.NoError(t, err)
+
+	createArgs := didexchange.CreateInvitationArgs{}
+	require.NoError(t, json.Unmarshal(reqBytes, &createArgs))
+	require.Equal(t, 3, createArgs.MaxUses)
+	require.Equal(t, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), *createArgs.Expires)
+
+	_, err = queryArgsAsJSON(url.Values{"max_uses": []string{"three"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid max_uses")
+
+	_, err = queryArgsAsJSON(url.Values{"descending": []string{"maybe"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid descending")
+}
+
 func TestGetIDFromRequest(t *testing.T) {
 	id, found := getIDFromRequest(httptest.NewRecorder(), &http.Request{})
 	require.False(t, found)
@@ -425,7 +559,8 @@ func TestEmptyID(t *testing.T) {
 	require.NotNil(t, op)
 
 	restHandlers := []http.HandlerFunc{
-		op.AcceptInvitation, op.AcceptExchangeRequest, op.QueryConnectionByID, op.RemoveConnection,
+		op.AcceptInvitation, op.AcceptExchangeRequest, op.QueryConnectionByID, op.RemoveConnection, op.RotateDID,
+		op.SetConnectionMetadata, op.AddConnectionTags, op.RemoveConnectionTags,
 	}
 	for _, handler := range restHandlers {
 		rw := httptest.NewRecorder()
@@ -511,6 +646,11 @@ func getHandlerWithError(t *testing.T, lookup string, f *fails) rest.Handler {
 				ImplicitInvitationErr: f.implicitErr,
 			},
 			mediator.Coordination: &mockroute.MockMediatorSvc{},
+			didrotatesvc.DIDRotate: &mockdidrotate.MockDIDRotateSvc{
+				RotateDIDFunc: func(string, string, []string) (string, error) {
+					return "th1234", nil
+				},
+			},
 		},
 		KMSValue:                          &mockkms.KeyManager{CreateKeyValue: ed25519KH},
 		ServiceEndpointValue:              "endpoint",
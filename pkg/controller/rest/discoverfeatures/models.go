@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import "github.com/hyperledger/aries-framework-go/pkg/controller/command/discoverfeatures"
+
+// discoverFeaturesQueryRequest model
+//
+// This is used to query the features of the agent on the other end of the connection.
+//
+// swagger:parameters discoverFeaturesQuery
+type discoverFeaturesQueryRequest struct { // nolint: unused,deadcode
+	// Params for querying the features
+	//
+	// in: body
+	Params discoverfeatures.QueryRequest
+}
+
+// discoverFeaturesFeaturesRequest model
+//
+// This is used to query the features of this agent.
+//
+// swagger:parameters discoverFeaturesFeatures
+type discoverFeaturesFeaturesRequest struct { // nolint: unused,deadcode
+	// Params for querying the features
+	//
+	// in: body
+	Params discoverfeatures.FeaturesRequest
+}
+
+// discoverFeaturesResponse model
+//
+// Represents the protocols matching the query.
+//
+// swagger:response discoverFeaturesResponse
+type discoverFeaturesResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body discoverfeatures.FeaturesResponse
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/discoverfeatures"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+)
+
+// constants for the discover-features operations.
+const (
+	OperationID  = "/discoverfeatures"
+	QueryPath    = OperationID + "/query"
+	FeaturesPath = OperationID + "/features"
+)
+
+// provider contains dependencies for the discover-features protocol and is typically created by using aries.Context().
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// Operation contains basic common operations provided by controller REST API.
+type Operation struct {
+	handlers []rest.Handler
+	command  *discoverfeatures.Command
+}
+
+// New returns new discover-features rest client instance.
+func New(ctx provider) (*Operation, error) {
+	cmd, err := discoverfeatures.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create discover-features command : %w", err)
+	}
+
+	o := &Operation{command: cmd}
+
+	o.registerHandler()
+
+	return o, nil
+}
+
+// GetRESTHandlers get all controller API handler available for this service.
+func (o *Operation) GetRESTHandlers() []rest.Handler {
+	return o.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
+func (o *Operation) registerHandler() {
+	o.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(QueryPath, http.MethodPost, o.Query),
+		cmdutil.NewHTTPHandler(FeaturesPath, http.MethodPost, o.Features),
+	}
+}
+
+// Query swagger:route POST /discoverfeatures/query discoverfeatures discoverFeaturesQuery
+//
+// Asks the agent on the other end of the connection which protocols it supports.
+//
+// Responses:
+//    default: genericError
+//    200: discoverFeaturesResponse
+func (o *Operation) Query(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Query, rw, req.Body)
+}
+
+// Features swagger:route POST /discoverfeatures/features discoverfeatures discoverFeaturesFeatures
+//
+// Returns the protocols supported by this agent.
+//
+// Responses:
+//    default: genericError
+//    200: discoverFeaturesResponse
+func (o *Operation) Features(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Features, rw, req.Body)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	cmddiscover "github.com/hyperledger/aries-framework-go/pkg/controller/command/discoverfeatures"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	mockdiscover "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/discoverfeatures"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+const (
+	queryRequest    = `{"connectionID":"abc-123","query":"*"}`
+	featuresRequest = `{"query":"*"}`
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new command", func(t *testing.T) {
+		cmd, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{}))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+	})
+
+	t.Run("test new command - command creation fail", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create discover-features command")
+		require.Nil(t, cmd)
+	})
+}
+
+func TestOperation_GetRESTHandlers(t *testing.T) {
+	svc, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{}))
+	require.NoError(t, err)
+	require.NotNil(t, svc)
+
+	handlers := svc.GetRESTHandlers()
+	require.Equal(t, len(handlers), 2)
+}
+
+func TestOperation_Query(t *testing.T) {
+	t.Run("test query - success", func(t *testing.T) {
+		svc, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			QueryFunc: func(string, string, ...discoverfeatures.ClientOption) ([]discoverfeatures.ProtocolDescriptor, error) {
+				return []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, nil
+			},
+		}))
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, svc, QueryPath)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBufferString(queryRequest), handler.Path())
+		require.NoError(t, err)
+
+		response := cmddiscover.FeaturesResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, response.Protocols)
+	})
+
+	t.Run("test query - error", func(t *testing.T) {
+		svc, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			QueryFunc: func(string, string, ...discoverfeatures.ClientOption) ([]discoverfeatures.ProtocolDescriptor, error) {
+				return nil, errors.New("query error")
+			},
+		}))
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, svc, QueryPath)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(queryRequest), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, cmddiscover.QueryErrorCode, "query error", buf.Bytes())
+	})
+}
+
+func TestOperation_Features(t *testing.T) {
+	t.Run("test features - success", func(t *testing.T) {
+		svc, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{
+			FeaturesFunc: func(string) ([]discoverfeatures.ProtocolDescriptor, error) {
+				return []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, nil
+			},
+		}))
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, svc, FeaturesPath)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBufferString(featuresRequest), handler.Path())
+		require.NoError(t, err)
+
+		response := cmddiscover.FeaturesResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, []discoverfeatures.ProtocolDescriptor{{PID: discoverfeatures.PIURI}}, response.Protocols)
+	})
+
+	t.Run("test features - validation error", func(t *testing.T) {
+		svc, err := New(newMockProvider(&mockdiscover.MockDiscoverFeaturesSvc{}))
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, svc, FeaturesPath)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{}`), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, cmddiscover.InvalidRequestErrorCode, "empty query", buf.Bytes())
+	})
+}
+
+func newMockProvider(svc *mockdiscover.MockDiscoverFeaturesSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			discoverfeatures.Name: svc,
+		},
+	}
+}
+
+func lookupHandler(t *testing.T, op *Operation, path string) rest.Handler {
+	handlers := op.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		if h.Path() == path {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+// getSuccessResponseFromHandler reads response from given http handle func.
+// expects http status OK.
+func getSuccessResponseFromHandler(handler rest.Handler, requestBody io.Reader,
+	path string) (*bytes.Buffer, error) {
+	response, status, err := sendRequestToHandler(handler, requestBody, path)
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: got %v, want %v",
+			status, http.StatusOK)
+	}
+
+	return response, err
+}
+
+// sendRequestToHandler reads response from given http handle func.
+func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
+	// prepare request
+	req, err := http.NewRequest(handler.Method(), path, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// prepare router
+	router := mux.NewRouter()
+
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+
+	// serve http on given response and request
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code, nil
+}
+
+func verifyError(t *testing.T, expectedCode command.Code, expectedMsg string, data []byte) {
+	// Parser generic error response
+	errResponse := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{}
+	err := json.Unmarshal(data, &errResponse)
+	require.NoError(t, err)
+
+	// verify response
+	require.EqualValues(t, expectedCode, errResponse.Code)
+	require.NotEmpty(t, errResponse.Message)
+
+	if expectedMsg != "" {
+		require.Contains(t, errResponse.Message, expectedMsg)
+	}
+}
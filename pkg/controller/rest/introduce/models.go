@@ -143,6 +143,36 @@ type introduceAcceptRequestWithPublicOOBRequestResponse struct { // nolint: unus
 	Body struct{}
 }
 
+// introduceAcceptRequestWithPublicDID model
+//
+// This is used for operation to accept a request with the public DID of the introduced agent.
+//
+// swagger:parameters introduceAcceptRequestWithPublicDID
+type introduceAcceptRequestWithPublicDID struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+	// in: body
+	Body struct {
+		// PublicDID is the DID by which the introduced agent is reachable
+		PublicDID string `json:"public_did"`
+		// To keeps information about the introduction
+		To struct{ *protocol.To } `json:"to"`
+	}
+}
+
+// introduceAcceptRequestWithPublicDIDResponse model
+//
+// Represents a AcceptRequestWithPublicDID response message.
+//
+// swagger:response introduceAcceptRequestWithPublicDIDResponse
+type introduceAcceptRequestWithPublicDIDResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
+
 // introduceAcceptRequestWithRecipients model
 //
 // This is used for operation to accept a request with recipients.
@@ -303,3 +333,94 @@ type introduceAcceptProblemReportResponse struct { // nolint: unused,deadcode
 	// in: body
 	Body struct{}
 }
+
+// introduceProtocolInstancesRequest model
+//
+// Returns the records of the in-flight and completed protocol instances.
+//
+// swagger:parameters introduceProtocolInstances
+type introduceProtocolInstancesRequest struct{} // nolint: unused,deadcode
+
+// introduceProtocolInstancesResponse model
+//
+// Represents a ProtocolInstances response message.
+//
+// swagger:response introduceProtocolInstancesResponse
+type introduceProtocolInstancesResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		ProtocolInstances []struct{ *protocol.ProtocolInstance } `json:"protocol_instances"`
+	}
+}
+
+// introduceProtocolInstanceRequest model
+//
+// This is used for operation to get the protocol instance.
+//
+// swagger:parameters introduceProtocolInstance
+type introduceProtocolInstanceRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// introduceProtocolInstanceResponse model
+//
+// Represents a ProtocolInstance response message.
+//
+// swagger:response introduceProtocolInstanceResponse
+type introduceProtocolInstanceResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		ProtocolInstance struct{ *protocol.ProtocolInstance } `json:"protocol_instance"`
+	}
+}
+
+// introduceDeleteProtocolInstanceRequest model
+//
+// This is used for operation to delete the protocol instance.
+//
+// swagger:parameters introduceDeleteProtocolInstance
+type introduceDeleteProtocolInstanceRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// introduceDeleteProtocolInstanceResponse model
+//
+// Represents a DeleteProtocolInstance response message.
+//
+// swagger:response introduceDeleteProtocolInstanceResponse
+type introduceDeleteProtocolInstanceResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
+
+// introduceSetResponsePolicyRequest model
+//
+// This is used for operation to set the policy which answers the introduce actions.
+//
+// swagger:parameters introduceSetResponsePolicy
+type introduceSetResponsePolicyRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// Policy accepts the proposals and forwards the out-of-band messages without waiting for the user,
+		// the default behavior is restored if empty
+		Policy struct{ *protocol.ResponsePolicy } `json:"policy"`
+	}
+}
+
+// introduceSetResponsePolicyResponse model
+//
+// Represents a SetResponsePolicy response message.
+//
+// swagger:response introduceSetResponsePolicyResponse
+type introduceSetResponsePolicyResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
@@ -38,6 +38,10 @@ const (
 	DeclineProposal                   = OperationID + "/{piid}/decline-proposal"
 	DeclineRequest                    = OperationID + "/{piid}/decline-request"
 	AcceptProblemReport               = OperationID + "/{piid}/accept-problem-report"
+	ProtocolInstances                 = OperationID + "/instances"
+	ProtocolInstance                  = OperationID + "/instances/{piid}"
+	AcceptRequestWithPublicDID        = OperationID + "/{piid}/accept-request-with-public-did"
+	ResponsePolicy                    = OperationID + "/response-policy"
 )
 
 // Operation is controller REST service controller for the introduce.
@@ -79,6 +83,11 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(DeclineProposal, http.MethodPost, c.DeclineProposal),
 		cmdutil.NewHTTPHandler(DeclineRequest, http.MethodPost, c.DeclineRequest),
 		cmdutil.NewHTTPHandler(AcceptProblemReport, http.MethodPost, c.AcceptProblemReport),
+		cmdutil.NewHTTPHandler(ProtocolInstances, http.MethodGet, c.ProtocolInstances),
+		cmdutil.NewHTTPHandler(ProtocolInstance, http.MethodGet, c.ProtocolInstance),
+		cmdutil.NewHTTPHandler(ProtocolInstance, http.MethodDelete, c.DeleteProtocolInstance),
+		cmdutil.NewHTTPHandler(AcceptRequestWithPublicDID, http.MethodPost, c.AcceptRequestWithPublicDID),
+		cmdutil.NewHTTPHandler(ResponsePolicy, http.MethodPost, c.SetResponsePolicy),
 	}
 }
 
@@ -164,6 +173,19 @@ func (c *Operation) AcceptRequestWithPublicOOBRequest(rw http.ResponseWriter, re
 	}
 }
 
+// AcceptRequestWithPublicDID swagger:route POST /introduce/{piid}/accept-request-with-public-did introduce introduceAcceptRequestWithPublicDID
+//
+// Accept a request with the public DID of the introduced agent.
+//
+// Responses:
+//    default: genericError
+//        200: introduceAcceptRequestWithPublicDIDResponse
+func (c *Operation) AcceptRequestWithPublicDID(rw http.ResponseWriter, req *http.Request) {
+	if ok, r := toCommandRequest(rw, req); ok {
+		rest.Execute(c.command.AcceptRequestWithPublicDID, rw, r)
+	}
+}
+
 // AcceptRequestWithRecipients swagger:route POST /introduce/{piid}/accept-request-with-recipients introduce introduceAcceptRequestWithRecipients
 //
 // Accept a request with recipients.
@@ -218,6 +240,54 @@ func (c *Operation) AcceptProblemReport(rw http.ResponseWriter, req *http.Reques
 	}`, mux.Vars(req)["piid"])))
 }
 
+// ProtocolInstances swagger:route GET /introduce/instances introduce introduceProtocolInstances
+//
+// Returns the records of the in-flight and completed protocol instances.
+//
+// Responses:
+//    default: genericError
+//        200: introduceProtocolInstancesResponse
+func (c *Operation) ProtocolInstances(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(c.command.ProtocolInstances, rw, nil)
+}
+
+// ProtocolInstance swagger:route GET /introduce/instances/{piid} introduce introduceProtocolInstance
+//
+// Returns the record of the protocol instance.
+//
+// Responses:
+//    default: genericError
+//        200: introduceProtocolInstanceResponse
+func (c *Operation) ProtocolInstance(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ProtocolInstance, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
+// DeleteProtocolInstance swagger:route DELETE /introduce/instances/{piid} introduce introduceDeleteProtocolInstance
+//
+// Deletes the record of the protocol instance.
+//
+// Responses:
+//    default: genericError
+//        200: introduceDeleteProtocolInstanceResponse
+func (c *Operation) DeleteProtocolInstance(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeleteProtocolInstance, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
+// SetResponsePolicy swagger:route POST /introduce/response-policy introduce introduceSetResponsePolicy
+//
+// Sets the policy which accepts the proposals and forwards the out-of-band messages without waiting for the user.
+//
+// Responses:
+//    default: genericError
+//        200: introduceSetResponsePolicyResponse
+func (c *Operation) SetResponsePolicy(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SetResponsePolicy, rw, req.Body)
+}
+
 func toCommandRequest(rw http.ResponseWriter, req *http.Request) (bool, io.Reader) {
 	var buf bytes.Buffer
 
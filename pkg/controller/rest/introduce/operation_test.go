@@ -20,6 +20,7 @@ import (
 
 	client "github.com/hyperledger/aries-framework-go/pkg/client/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/introduce"
 	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
 )
@@ -32,6 +33,7 @@ func provider(ctrl *gomock.Controller) client.Provider {
 	service.EXPECT().ActionStop(gomock.Any(), gomock.Any()).AnyTimes()
 	service.EXPECT().HandleOutbound(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	service.EXPECT().Actions().AnyTimes()
+	service.EXPECT().SetResponsePolicy(gomock.Any()).AnyTimes()
 
 	provider := mocks.NewMockProvider(ctrl)
 	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
@@ -215,6 +217,58 @@ func TestOperation_AcceptRequestWithPublicOOBRequest(t *testing.T) {
 	})
 }
 
+func TestOperation_AcceptRequestWithPublicDID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("No payload", func(t *testing.T) {
+		operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, AcceptRequestWithPublicDID), nil,
+			strings.Replace(AcceptRequestWithPublicDID, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, buf.String(), "payload was not provided")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		_, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, AcceptRequestWithPublicDID),
+			bytes.NewBufferString(`{"public_did":"did:example:carol","to":{}}`),
+			strings.Replace(AcceptRequestWithPublicDID, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestOperation_SetResponsePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		_, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, ResponsePolicy),
+			bytes.NewBufferString(`{"policy":{"introducers":["did:example:alice"]}}`),
+			ResponsePolicy,
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
 func TestOperation_AcceptRequestWithRecipients(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -305,6 +359,55 @@ func TestOperation_AcceptProblemReport(t *testing.T) {
 	})
 }
 
+func TestOperation_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+	service.EXPECT().ProtocolInstances().Return([]*protocol.ProtocolInstance{{PIID: "1234"}}, nil)
+	service.EXPECT().ProtocolInstance("1234").Return(&protocol.ProtocolInstance{PIID: "1234"}, nil)
+	service.EXPECT().DeleteProtocolInstance("1234").Return(nil)
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+	operation, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		buf, code, err := sendRequestToHandler(
+			methodHandlerLookup(t, operation, ProtocolInstances, http.MethodGet), nil, ProtocolInstances,
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, buf.String(), `"piid":"1234"`)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		buf, code, err := sendRequestToHandler(
+			methodHandlerLookup(t, operation, ProtocolInstance, http.MethodGet), nil,
+			strings.Replace(ProtocolInstance, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, buf.String(), `"piid":"1234"`)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, code, err := sendRequestToHandler(
+			methodHandlerLookup(t, operation, ProtocolInstance, http.MethodDelete), nil,
+			strings.Replace(ProtocolInstance, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
 func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	t.Helper()
 
@@ -322,6 +425,20 @@ func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	return nil
 }
 
+func methodHandlerLookup(t *testing.T, op *Operation, lookup, method string) rest.Handler {
+	t.Helper()
+
+	for _, h := range op.GetRESTHandlers() {
+		if h.Path() == lookup && h.Method() == method {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
 // sendRequestToHandler reads response from given http handle func.
 func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
 	// prepare request
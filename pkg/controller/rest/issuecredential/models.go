@@ -393,3 +393,70 @@ type issueCredentialAcceptProblemReportResponse struct { // nolint: unused,deadc
 	// in: body
 	Body struct{}
 }
+
+// issueCredentialProtocolInstancesRequest model
+//
+// Returns the records of the in-flight and completed protocol instances.
+//
+// swagger:parameters issueCredentialProtocolInstances
+type issueCredentialProtocolInstancesRequest struct{} // nolint: unused,deadcode
+
+// issueCredentialProtocolInstancesResponse model
+//
+// Represents a ProtocolInstances response message.
+//
+// swagger:response issueCredentialProtocolInstancesResponse
+type issueCredentialProtocolInstancesResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		ProtocolInstances []struct{ *protocol.ProtocolInstance } `json:"protocol_instances"`
+	}
+}
+
+// issueCredentialProtocolInstanceRequest model
+//
+// This is used for operation to get the protocol instance.
+//
+// swagger:parameters issueCredentialProtocolInstance
+type issueCredentialProtocolInstanceRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// issueCredentialProtocolInstanceResponse model
+//
+// Represents a ProtocolInstance response message.
+//
+// swagger:response issueCredentialProtocolInstanceResponse
+type issueCredentialProtocolInstanceResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		ProtocolInstance struct{ *protocol.ProtocolInstance } `json:"protocol_instance"`
+	}
+}
+
+// issueCredentialDeleteProtocolInstanceRequest model
+//
+// This is used for operation to delete the protocol instance.
+//
+// swagger:parameters issueCredentialDeleteProtocolInstance
+type issueCredentialDeleteProtocolInstanceRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// issueCredentialDeleteProtocolInstanceResponse model
+//
+// Represents a DeleteProtocolInstance response message.
+//
+// swagger:response issueCredentialDeleteProtocolInstanceResponse
+type issueCredentialDeleteProtocolInstanceResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
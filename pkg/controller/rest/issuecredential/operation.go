@@ -41,6 +41,8 @@ const (
 	AcceptCredential    = OperationID + "/{piid}/accept-credential"
 	DeclineCredential   = OperationID + "/{piid}/decline-credential"
 	AcceptProblemReport = OperationID + "/{piid}/accept-problem-report"
+	ProtocolInstances   = OperationID + "/instances"
+	ProtocolInstance    = OperationID + "/instances/{piid}"
 )
 
 // Operation is controller REST service controller for issue credential.
@@ -85,6 +87,9 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(AcceptCredential, http.MethodPost, c.AcceptCredential),
 		cmdutil.NewHTTPHandler(DeclineCredential, http.MethodPost, c.DeclineCredential),
 		cmdutil.NewHTTPHandler(AcceptProblemReport, http.MethodPost, c.AcceptProblemReport),
+		cmdutil.NewHTTPHandler(ProtocolInstances, http.MethodGet, c.ProtocolInstances),
+		cmdutil.NewHTTPHandler(ProtocolInstance, http.MethodGet, c.ProtocolInstance),
+		cmdutil.NewHTTPHandler(ProtocolInstance, http.MethodDelete, c.DeleteProtocolInstance),
 	}
 }
 
@@ -185,6 +190,43 @@ func (c *Operation) AcceptProblemReport(rw http.ResponseWriter, req *http.Reques
 	}`, mux.Vars(req)["piid"])))
 }
 
+// ProtocolInstances swagger:route GET /issuecredential/instances issue-credential issueCredentialProtocolInstances
+//
+// Returns the records of the in-flight and completed protocol instances.
+//
+// Responses:
+//    default: genericError
+//        200: issueCredentialProtocolInstancesResponse
+func (c *Operation) ProtocolInstances(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(c.command.ProtocolInstances, rw, nil)
+}
+
+// ProtocolInstance swagger:route GET /issuecredential/instances/{piid} issue-credential issueCredentialProtocolInstance
+//
+// Returns the record of the protocol instance.
+//
+// Responses:
+//    default: genericError
+//        200: issueCredentialProtocolInstanceResponse
+func (c *Operation) ProtocolInstance(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ProtocolInstance, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
+// DeleteProtocolInstance swagger:route DELETE /issuecredential/instances/{piid} issue-credential issueCredentialDeleteProtocolInstance
+//
+// Deletes the record of the protocol instance.
+//
+// Responses:
+//    default: genericError
+//        200: issueCredentialDeleteProtocolInstanceResponse
+func (c *Operation) DeleteProtocolInstance(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeleteProtocolInstance, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
 // DeclineOffer swagger:route POST /issuecredential/{piid}/decline-offer issue-credential issueCredentialDeclineOffer
 //
 // Declines an offer.
@@ -43,3 +43,68 @@ type importKeyReq struct {
 	// in: body
 	kms.JSONWebKey
 }
+
+// getKeyMetadataReq model
+//
+// This is used for getKeyMetadata request.
+//
+// swagger:parameters getKeyMetadataReq
+type getKeyMetadataReq struct { // nolint: unused,deadcode
+	// Key ID of the key set
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+}
+
+// getKeyMetadataRes model
+//
+// This is used for returning the key metadata.
+//
+// swagger:response getKeyMetadataRes
+type getKeyMetadataRes struct { // nolint: unused,deadcode
+
+	// in: body
+	kms.GetKeyMetadataResponse
+}
+
+// listKeysReq model
+//
+// This is used for listKeys request.
+//
+// swagger:parameters listKeysReq
+type listKeysReq struct { // nolint: unused,deadcode
+	// Key type of the listed keys
+	// in: query
+	KeyType string `json:"keyType"`
+	// Lists the keys created after the given time (RFC3339)
+	// in: query
+	CreatedAfter string `json:"createdAfter"`
+	// Lists the keys created before the given time (RFC3339)
+	// in: query
+	CreatedBefore string `json:"createdBefore"`
+}
+
+// listKeysRes model
+//
+// This is used for returning the metadata of the keys.
+//
+// swagger:response listKeysRes
+type listKeysRes struct { // nolint: unused,deadcode
+
+	// in: body
+	kms.ListKeysResponse
+}
+
+// deleteKeyReq model
+//
+// This is used for deleteKey request.
+//
+// swagger:parameters deleteKeyReq
+type deleteKeyReq struct { // nolint: unused,deadcode
+	// Key ID of the key set
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+}
@@ -7,8 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package kms
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
 
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	cmdkms "github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
@@ -19,9 +26,18 @@ import (
 
 // constants for KMS operations.
 const (
-	KmsOperationID   = "/kms"
-	CreateKeySetPath = KmsOperationID + "/keyset"
-	ImportKeyPath    = KmsOperationID + "/import"
+	KmsOperationID     = "/kms"
+	CreateKeySetPath   = KmsOperationID + "/keyset"
+	ImportKeyPath      = KmsOperationID + "/import"
+	GetKeyMetadataPath = KmsOperationID + "/keyset/{keyID}/metadata"
+	ListKeysPath       = KmsOperationID + "/keys"
+	DeleteKeyPath      = KmsOperationID + "/keyset/{keyID}"
+)
+
+// query parameters of the list keys operation.
+const (
+	createdAfterQueryParam  = "createdAfter"
+	createdBeforeQueryParam = "createdBefore"
 )
 
 // provider contains dependencies for the kms command and is typically created by using aries.Context().
@@ -32,6 +48,9 @@ type provider interface {
 type kmsCommand interface {
 	CreateKeySet(rw io.Writer, req io.Reader) command.Error
 	ImportKey(rw io.Writer, req io.Reader) command.Error
+	GetKeyMetadata(rw io.Writer, req io.Reader) command.Error
+	ListKeys(rw io.Writer, req io.Reader) command.Error
+	DeleteKey(rw io.Writer, req io.Reader) command.Error
 }
 
 // Operation contains basic common operations provided by controller REST API.
@@ -60,6 +79,9 @@ func (o *Operation) registerHandler() {
 	o.handlers = []rest.Handler{
 		cmdutil.NewHTTPHandler(CreateKeySetPath, http.MethodPost, o.CreateKeySet),
 		cmdutil.NewHTTPHandler(ImportKeyPath, http.MethodPost, o.ImportKey),
+		cmdutil.NewHTTPHandler(GetKeyMetadataPath, http.MethodGet, o.GetKeyMetadata),
+		cmdutil.NewHTTPHandler(ListKeysPath, http.MethodGet, o.ListKeys),
+		cmdutil.NewHTTPHandler(DeleteKeyPath, http.MethodDelete, o.DeleteKey),
 	}
 }
 
@@ -83,3 +105,69 @@ func (o *Operation) CreateKeySet(rw http.ResponseWriter, req *http.Request) {
 func (o *Operation) ImportKey(rw http.ResponseWriter, req *http.Request) {
 	rest.Execute(o.command.ImportKey, rw, req.Body)
 }
+
+// GetKeyMetadata swagger:route GET /kms/keyset/{keyID}/metadata kms getKeyMetadataReq
+//
+// Gets the key metadata, the created time, type and status of the key versions.
+//
+// Responses:
+//    default: genericError
+//        200: getKeyMetadataRes
+func (o *Operation) GetKeyMetadata(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.GetKeyMetadata, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"keyID":%q
+	}`, mux.Vars(req)["keyID"])))
+}
+
+// ListKeys swagger:route GET /kms/keys kms listKeysReq
+//
+// Lists the metadata of the keys, optionally filtered by key type and creation time (RFC3339).
+//
+// Responses:
+//    default: genericError
+//        200: listKeysRes
+func (o *Operation) ListKeys(rw http.ResponseWriter, req *http.Request) {
+	reqBytes, err := queryArgsAsJSON(req.URL.Query())
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, cmdkms.InvalidRequestErrorCode, err)
+		return
+	}
+
+	rest.Execute(o.command.ListKeys, rw, bytes.NewReader(reqBytes))
+}
+
+// DeleteKey swagger:route DELETE /kms/keyset/{keyID} kms deleteKeyReq
+//
+// Deletes the key set along with all its versions.
+//
+// Responses:
+//    default: genericError
+func (o *Operation) DeleteKey(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.DeleteKey, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"keyID":%q
+	}`, mux.Vars(req)["keyID"])))
+}
+
+func queryArgsAsJSON(vals url.Values) ([]byte, error) {
+	args := make(map[string]interface{})
+
+	for k, v := range vals {
+		if len(v) == 0 {
+			continue
+		}
+
+		switch k {
+		case createdAfterQueryParam, createdBeforeQueryParam:
+			t, err := time.Parse(time.RFC3339, v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s: %w", k, v[0], err)
+			}
+
+			args[k] = t
+		default:
+			args[k] = v[0]
+		}
+	}
+
+	return json.Marshal(args)
+}
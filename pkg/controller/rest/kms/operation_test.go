@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -21,6 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	kmsapi "github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
 )
@@ -31,7 +33,7 @@ func TestNew(t *testing.T) {
 			KMSValue: &mockkms.KeyManager{},
 		})
 		require.NotNil(t, cmd)
-		require.Equal(t, 2, len(cmd.GetRESTHandlers()))
+		require.Equal(t, 5, len(cmd.GetRESTHandlers()))
 	})
 }
 
@@ -42,7 +44,7 @@ func TestCreateKeySet(t *testing.T) {
 		})
 		cmd.command = &mockKMSCommand{}
 
-		handler := lookupHandler(t, cmd, CreateKeySetPath)
+		handler := lookupHandler(t, cmd, CreateKeySetPath, http.MethodPost)
 		err := getSuccessResponseFromHandler(handler, CreateKeySetPath)
 		require.NoError(t, err)
 	})
@@ -53,7 +55,7 @@ func TestCreateKeySet(t *testing.T) {
 		})
 		require.NotNil(t, cmd)
 
-		handler := lookupHandler(t, cmd, CreateKeySetPath)
+		handler := lookupHandler(t, cmd, CreateKeySetPath, http.MethodPost)
 
 		req := createKeySetReq{CreateKeySetRequest: kms.CreateKeySetRequest{
 			KeyType: "ED25519",
@@ -75,7 +77,7 @@ func TestImportKey(t *testing.T) {
 		cmd := New(&mockprovider.Provider{})
 		cmd.command = &mockKMSCommand{}
 
-		handler := lookupHandler(t, cmd, ImportKeyPath)
+		handler := lookupHandler(t, cmd, ImportKeyPath, http.MethodPost)
 		err := getSuccessResponseFromHandler(handler, ImportKeyPath)
 		require.NoError(t, err)
 	})
@@ -87,7 +89,7 @@ func TestImportKey(t *testing.T) {
 		cmd.command = &mockKMSCommand{importKeyError: command.NewExecuteError(kms.ImportKeyError,
 			fmt.Errorf("failed to import key"))}
 
-		handler := lookupHandler(t, cmd, ImportKeyPath)
+		handler := lookupHandler(t, cmd, ImportKeyPath, http.MethodPost)
 
 		req := importKeyReq{JSONWebKey: kms.JSONWebKey{Kid: "k1"}}
 		reqBytes, err := json.Marshal(req)
@@ -102,12 +104,122 @@ func TestImportKey(t *testing.T) {
 	})
 }
 
-func lookupHandler(t *testing.T, op *Operation, path string) rest.Handler {
+func TestGetKeyMetadata(t *testing.T) {
+	t.Run("test get key metadata - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{})
+		cmd.command = &mockKMSCommand{}
+
+		handler := lookupHandler(t, cmd, GetKeyMetadataPath, http.MethodGet)
+		err := getSuccessResponseFromHandler(handler, KmsOperationID+"/keyset/keyID/metadata")
+		require.NoError(t, err)
+	})
+
+	t.Run("test get key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{},
+		})
+
+		handler := lookupHandler(t, cmd, GetKeyMetadataPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KmsOperationID+"/keyset/keyID/metadata")
+		require.NoError(t, err)
+		require.NotEmpty(t, buf)
+
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.GetKeyMetadataError, "key manager does not support key metadata", buf.Bytes())
+	})
+}
+
+func TestListKeys(t *testing.T) {
+	t.Run("test list keys - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListValue: []kmsapi.KeyMetadata{
+				{KeyID: "key1", KeyType: kmsapi.ED25519Type},
+			}},
+		})
+
+		handler := lookupHandler(t, cmd, ListKeysPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil,
+			ListKeysPath+"?keyType=ED25519&createdAfter=2020-11-19T10:00:00Z")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		response := listKeysRes{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Len(t, response.Keys, 1)
+		require.Equal(t, "key1", response.Keys[0].KeyID)
+	})
+
+	t.Run("test list keys - invalid creation time", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		handler := lookupHandler(t, cmd, ListKeysPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, ListKeysPath+"?createdBefore=yesterday")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, kms.InvalidRequestErrorCode, "invalid createdBefore yesterday", buf.Bytes())
+	})
+
+	t.Run("test list keys - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListErr: fmt.Errorf("list error")},
+		})
+
+		handler := lookupHandler(t, cmd, ListKeysPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, ListKeysPath)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.ListKeysError, "list error", buf.Bytes())
+	})
+}
+
+func TestDeleteKey(t *testing.T) {
+	t.Run("test delete key - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{})
+		cmd.command = &mockKMSCommand{}
+
+		handler := lookupHandler(t, cmd, DeleteKeyPath, http.MethodDelete)
+		err := getSuccessResponseFromHandler(handler, KmsOperationID+"/keyset/keyID")
+		require.NoError(t, err)
+	})
+
+	t.Run("test delete key - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{DeleteErr: fmt.Errorf("key not found")},
+		})
+
+		handler := lookupHandler(t, cmd, DeleteKeyPath, http.MethodDelete)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KmsOperationID+"/keyset/keyID")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.DeleteKeyError, "key not found", buf.Bytes())
+	})
+}
+
+func TestQueryArgsAsJSON(t *testing.T) {
+	raw, err := queryArgsAsJSON(url.Values{
+		"keyType":      {"ED25519"},
+		"createdAfter": {"2020-11-19T10:00:00+01:00"},
+		"unused":       {},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"keyType":"ED25519","createdAfter":"2020-11-19T10:00:00+01:00"}`, string(raw))
+
+	_, err = queryArgsAsJSON(url.Values{"createdAfter": {"2020-11-19"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid createdAfter 2020-11-19")
+}
+
+func lookupHandler(t *testing.T, op *Operation, path, method string) rest.Handler {
 	handlers := op.GetRESTHandlers()
 	require.NotEmpty(t, handlers)
 
 	for _, h := range handlers {
-		if h.Path() == path && h.Method() == http.MethodPost {
+		if h.Path() == path && h.Method() == method {
 			return h
 		}
 	}
@@ -181,3 +293,15 @@ func (m *mockKMSCommand) CreateKeySet(rw io.Writer, req io.Reader) command.Error
 func (m *mockKMSCommand) ImportKey(rw io.Writer, req io.Reader) command.Error {
 	return m.importKeyError
 }
+
+func (m *mockKMSCommand) GetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) ListKeys(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) DeleteKey(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
@@ -54,8 +54,11 @@ type outofbandCreateInvitationRequest struct { // nolint: unused,deadcode
 		Goal               string        `json:"goal"`
 		GoalCode           string        `json:"goal_code"`
 		Service            []interface{} `json:"service"`
-		Protocols          []string      `json:"protocols"`
+		HandshakeProtocols []string      `json:"handshake_protocols"`
 		RouterConnectionID string        `json:"router_connection_id"`
+
+		// Attachments are the requests (e.g present proof request) attached to the invitation.
+		Attachments []*decorator.Attachment `json:"attachments"`
 	}
 }
 
@@ -105,9 +108,11 @@ type outofbandAcceptRequestResponse struct { // nolint: unused,deadcode
 type outofbandAcceptInvitationRequest struct { // nolint: unused,deadcode
 	// in: body
 	Body struct {
-		Invitation        struct{ *protocol.Invitation } `json:"invitation"`
-		MyLabel           string                         `json:"my_label"`
-		RouterConnections string                         `json:"router_connections"`
+		Invitation         struct{ *protocol.Invitation } `json:"invitation"`
+		MyLabel            string                         `json:"my_label"`
+		RouterConnections  string                         `json:"router_connections"`
+		ReuseConnection    string                         `json:"reuse_connection"`
+		ReuseAnyConnection bool                           `json:"reuse_any_connection"`
 	}
 }
 
@@ -316,3 +316,70 @@ type presentProofAcceptProblemReportResponse struct { // nolint: unused,deadcode
 	// in: body
 	Body struct{}
 }
+
+// presentProofProtocolInstancesRequest model
+//
+// Returns the records of the in-flight and completed protocol instances.
+//
+// swagger:parameters presentProofProtocolInstances
+type presentProofProtocolInstancesRequest struct{} // nolint: unused,deadcode
+
+// presentProofProtocolInstancesResponse model
+//
+// Represents a ProtocolInstances response message.
+//
+// swagger:response presentProofProtocolInstancesResponse
+type presentProofProtocolInstancesResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		ProtocolInstances []struct{ *protocol.ProtocolInstance } `json:"protocol_instances"`
+	}
+}
+
+// presentProofProtocolInstanceRequest model
+//
+// This is used for operation to get the protocol instance.
+//
+// swagger:parameters presentProofProtocolInstance
+type presentProofProtocolInstanceRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// presentProofProtocolInstanceResponse model
+//
+// Represents a ProtocolInstance response message.
+//
+// swagger:response presentProofProtocolInstanceResponse
+type presentProofProtocolInstanceResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		ProtocolInstance struct{ *protocol.ProtocolInstance } `json:"protocol_instance"`
+	}
+}
+
+// presentProofDeleteProtocolInstanceRequest model
+//
+// This is used for operation to delete the protocol instance.
+//
+// swagger:parameters presentProofDeleteProtocolInstance
+type presentProofDeleteProtocolInstanceRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// presentProofDeleteProtocolInstanceResponse model
+//
+// Represents a DeleteProtocolInstance response message.
+//
+// swagger:response presentProofDeleteProtocolInstanceResponse
+type presentProofDeleteProtocolInstanceResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
@@ -38,6 +38,8 @@ const (
 	AcceptPresentation           = OperationID + "/{piid}/accept-presentation"
 	DeclinePresentation          = OperationID + "/{piid}/decline-presentation"
 	AcceptProblemReport          = OperationID + "/{piid}/accept-problem-report"
+	ProtocolInstances            = OperationID + "/instances"
+	ProtocolInstance             = OperationID + "/instances/{piid}"
 )
 
 // Operation is controller REST service controller for present proof.
@@ -79,6 +81,9 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(AcceptPresentation, http.MethodPost, c.AcceptPresentation),
 		cmdutil.NewHTTPHandler(DeclinePresentation, http.MethodPost, c.DeclinePresentation),
 		cmdutil.NewHTTPHandler(AcceptProblemReport, http.MethodPost, c.AcceptProblemReport),
+		cmdutil.NewHTTPHandler(ProtocolInstances, http.MethodGet, c.ProtocolInstances),
+		cmdutil.NewHTTPHandler(ProtocolInstance, http.MethodGet, c.ProtocolInstance),
+		cmdutil.NewHTTPHandler(ProtocolInstance, http.MethodDelete, c.DeleteProtocolInstance),
 	}
 }
 
@@ -128,6 +133,43 @@ func (c *Operation) AcceptProblemReport(rw http.ResponseWriter, req *http.Reques
 	}`, mux.Vars(req)["piid"])))
 }
 
+// ProtocolInstances swagger:route GET /presentproof/instances present-proof presentProofProtocolInstances
+//
+// Returns the records of the in-flight and completed protocol instances.
+//
+// Responses:
+//    default: genericError
+//        200: presentProofProtocolInstancesResponse
+func (c *Operation) ProtocolInstances(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(c.command.ProtocolInstances, rw, nil)
+}
+
+// ProtocolInstance swagger:route GET /presentproof/instances/{piid} present-proof presentProofProtocolInstance
+//
+// Returns the record of the protocol instance.
+//
+// Responses:
+//    default: genericError
+//        200: presentProofProtocolInstanceResponse
+func (c *Operation) ProtocolInstance(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ProtocolInstance, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
+// DeleteProtocolInstance swagger:route DELETE /presentproof/instances/{piid} present-proof presentProofDeleteProtocolInstance
+//
+// Deletes the record of the protocol instance.
+//
+// Responses:
+//    default: genericError
+//        200: presentProofDeleteProtocolInstanceResponse
+func (c *Operation) DeleteProtocolInstance(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeleteProtocolInstance, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
 // AcceptRequestPresentation swagger:route POST /presentproof/{piid}/accept-request-presentation present-proof presentProofAcceptRequestPresentation
 //
 // Accepts a request presentation.
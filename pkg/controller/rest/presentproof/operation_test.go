@@ -20,6 +20,7 @@ import (
 
 	client "github.com/hyperledger/aries-framework-go/pkg/client/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/presentproof"
 	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
 )
@@ -260,6 +261,55 @@ func TestOperation_NegotiateRequestPresentation(t *testing.T) {
 	})
 }
 
+func TestOperation_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+	service.EXPECT().ProtocolInstances().Return([]*protocol.ProtocolInstance{{PIID: "1234"}}, nil)
+	service.EXPECT().ProtocolInstance("1234").Return(&protocol.ProtocolInstance{PIID: "1234"}, nil)
+	service.EXPECT().DeleteProtocolInstance("1234").Return(nil)
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+	operation, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		buf, code, err := sendRequestToHandler(
+			methodHandlerLookup(t, operation, ProtocolInstances, http.MethodGet), nil, ProtocolInstances,
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, buf.String(), `"piid":"1234"`)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		buf, code, err := sendRequestToHandler(
+			methodHandlerLookup(t, operation, ProtocolInstance, http.MethodGet), nil,
+			strings.Replace(ProtocolInstance, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, buf.String(), `"piid":"1234"`)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, code, err := sendRequestToHandler(
+			methodHandlerLookup(t, operation, ProtocolInstance, http.MethodDelete), nil,
+			strings.Replace(ProtocolInstance, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
 func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	t.Helper()
 
@@ -277,6 +327,20 @@ func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	return nil
 }
 
+func methodHandlerLookup(t *testing.T, op *Operation, lookup, method string) rest.Handler {
+	t.Helper()
+
+	for _, h := range op.GetRESTHandlers() {
+		if h.Path() == lookup && h.Method() == method {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
 // sendRequestToHandler reads response from given http handle func.
 func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
 	// prepare request
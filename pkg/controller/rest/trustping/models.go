@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import "github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
+
+// trustPingRequest model
+//
+// This is used to ping the agent on the other end of the connection.
+//
+// swagger:parameters trustPing
+type trustPingRequest struct { // nolint: unused,deadcode
+	// Params for pinging the connection
+	//
+	// in: body
+	Params trustping.PingRequest
+}
+
+// trustPingResponse model
+//
+// Represents the outcome of the ping.
+//
+// swagger:response trustPingResponse
+type trustPingResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body trustping.PingResponse
+}
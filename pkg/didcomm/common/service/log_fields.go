@@ -0,0 +1,26 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+)
+
+// LogFields returns log fields which allow to correlate the message across agents (thid, pthid, @id, @type).
+func LogFields(msg DIDCommMsg) log.Fields {
+	fields := log.Fields{
+		log.FieldMessageID:      msg.ID(),
+		log.FieldMessageType:    msg.Type(),
+		log.FieldParentThreadID: msg.ParentThreadID(),
+	}
+
+	if thID, err := msg.ThreadID(); err == nil {
+		fields[log.FieldThreadID] = thID
+	}
+
+	return fields
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+)
+
+func TestLogFields(t *testing.T) {
+	t.Run("with thread", func(t *testing.T) {
+		fields := LogFields(DIDCommMsgMap{
+			jsonID:   "ID",
+			jsonType: "type",
+			jsonThread: map[string]interface{}{
+				jsonThreadID:       "thID",
+				jsonParentThreadID: "pthID",
+			},
+		})
+
+		require.Equal(t, log.Fields{
+			log.FieldMessageID:      "ID",
+			log.FieldMessageType:    "type",
+			log.FieldThreadID:       "thID",
+			log.FieldParentThreadID: "pthID",
+		}, fields)
+	})
+
+	t.Run("without ID", func(t *testing.T) {
+		fields := LogFields(DIDCommMsgMap{jsonType: "type"})
+
+		require.Equal(t, log.Fields{
+			log.FieldMessageID:      "",
+			log.FieldMessageType:    "type",
+			log.FieldParentThreadID: "",
+		}, fields)
+	})
+}
@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+// nolint:gochecknoglobals
+var logger = log.New("aries-framework/dispatcher")
+
+const (
+	errAlreadyRegistered = "registration failed, protocol service with name `%s` already registered"
+	errNeverRegistered   = "failed to unregister, unable to find registered protocol service with name `%s`"
+)
+
+// ProtocolEventType is the type of the protocol registration event.
+type ProtocolEventType int
+
+const (
+	// ProtocolRegistered the protocol service was registered, the messages are dispatched to it from now on.
+	ProtocolRegistered ProtocolEventType = iota
+	// ProtocolUnregistered the protocol service was unregistered, the messages are no longer dispatched to it.
+	ProtocolUnregistered
+)
+
+// ProtocolEvent is triggered when the protocol service is registered or unregistered. It lets the consumers
+// register their action and message event channels on the protocol services registered at runtime.
+type ProtocolEvent struct {
+	Type    ProtocolEventType
+	Service ProtocolService
+}
+
+// ProtocolRegistrar maintains the list of the protocol services the messages are dispatched to.
+// The protocol services can be registered and unregistered while the agent is running.
+type ProtocolRegistrar struct {
+	services []ProtocolService
+	events   []chan<- ProtocolEvent
+	lock     sync.RWMutex
+	eventsMu sync.RWMutex
+}
+
+// NewProtocolRegistrar returns the protocol registrar with the given protocol services registered.
+func NewProtocolRegistrar(services ...ProtocolService) *ProtocolRegistrar {
+	svcs := make([]ProtocolService, len(services))
+	copy(svcs, services)
+
+	return &ProtocolRegistrar{services: svcs}
+}
+
+// Services returns the registered protocol services in the order of their registration.
+func (r *ProtocolRegistrar) Services() []ProtocolService {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	svcs := make([]ProtocolService, len(r.services))
+	copy(svcs, r.services)
+
+	return svcs
+}
+
+// Service returns the registered protocol service by its name.
+func (r *ProtocolRegistrar) Service(name string) (ProtocolService, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, svc := range r.services {
+		if svc.Name() == name {
+			return svc, true
+		}
+	}
+
+	return nil, false
+}
+
+// Accepting returns the first registered protocol service which accepts the message type.
+func (r *ProtocolRegistrar) Accepting(msgType string) (ProtocolService, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, svc := range r.services {
+		if svc.Accept(msgType) {
+			return svc, true
+		}
+	}
+
+	return nil, false
+}
+
+// Register registers the protocol services, the messages are dispatched to the first registered service
+// accepting their type. Returns error in case of duplicate registration, none of the services is registered then.
+func (r *ProtocolRegistrar) Register(services ...ProtocolService) error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	r.lock.Lock()
+
+	names := make(map[string]struct{}, len(r.services)+len(services))
+	for _, svc := range r.services {
+		names[svc.Name()] = struct{}{}
+	}
+
+	for _, svc := range services {
+		if _, ok := names[svc.Name()]; ok {
+			r.lock.Unlock()
+
+			return fmt.Errorf(errAlreadyRegistered, svc.Name())
+		}
+
+		names[svc.Name()] = struct{}{}
+	}
+
+	r.services = append(r.services, services...)
+	r.lock.Unlock()
+
+	for _, svc := range services {
+		r.sendEvent(ProtocolEvent{Type: ProtocolRegistered, Service: svc})
+	}
+
+	return nil
+}
+
+// Unregister unregisters the protocol service with the given name,
+// returns error if the protocol service is not registered.
+func (r *ProtocolRegistrar) Unregister(name string) error {
+	r.lock.Lock()
+
+	index := -1
+
+	for i, svc := range r.services {
+		if svc.Name() == name {
+			index = i
+
+			break
+		}
+	}
+
+	if index < 0 {
+		r.lock.Unlock()
+
+		return fmt.Errorf(errNeverRegistered, name)
+	}
+
+	svc := r.services[index]
+
+	r.services = append(r.services[:index:index], r.services[index+1:]...)
+	r.lock.Unlock()
+
+	r.sendEvent(ProtocolEvent{Type: ProtocolUnregistered, Service: svc})
+
+	return nil
+}
+
+// RegisterProtocolEvent registers the channel the protocol registration events are sent to.
+// The events are sent without blocking, the channel should be buffered or the events are dropped
+// while the consumer is not ready to receive them.
+func (r *ProtocolRegistrar) RegisterProtocolEvent(ch chan<- ProtocolEvent) error {
+	if ch == nil {
+		return service.ErrNilChannel
+	}
+
+	r.eventsMu.Lock()
+	r.events = append(r.events, ch)
+	r.eventsMu.Unlock()
+
+	return nil
+}
+
+// UnregisterProtocolEvent unregisters the channel of the protocol registration events.
+func (r *ProtocolRegistrar) UnregisterProtocolEvent(ch chan<- ProtocolEvent) error {
+	if ch == nil {
+		return service.ErrNilChannel
+	}
+
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	for i := range r.events {
+		if r.events[i] == ch {
+			r.events = append(r.events[:i], r.events[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return errors.New("protocol event channel is not registered")
+}
+
+func (r *ProtocolRegistrar) sendEvent(event ProtocolEvent) {
+	r.eventsMu.RLock()
+	events := make([]chan<- ProtocolEvent, len(r.events))
+	copy(events, r.events)
+	r.eventsMu.RUnlock()
+
+	for _, ch := range events {
+		select {
+		case ch <- event:
+		default:
+			logger.Warnf("protocol event of %s is dropped, the consumer is not ready", event.Service.Name())
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+type protocolService struct {
+	name    string
+	msgType string
+}
+
+func (p *protocolService) HandleInbound(service.DIDCommMsg, string, string) (string, error) {
+	return "", nil
+}
+
+func (p *protocolService) HandleOutbound(service.DIDCommMsg, string, string) (string, error) {
+	return "", nil
+}
+
+func (p *protocolService) Accept(msgType string) bool {
+	return p.msgType == msgType
+}
+
+func (p *protocolService) Name() string {
+	return p.name
+}
+
+func TestProtocolRegistrar_Register(t *testing.T) {
+	t.Run("initial services", func(t *testing.T) {
+		svc := &protocolService{name: "svc1", msgType: "type1"}
+		services := []ProtocolService{svc}
+
+		r := NewProtocolRegistrar(services...)
+		services[0] = nil
+
+		require.Equal(t, []ProtocolService{svc}, r.Services())
+
+		found, ok := r.Service("svc1")
+		require.True(t, ok)
+		require.Equal(t, svc, found)
+
+		_, ok = r.Service("svc2")
+		require.False(t, ok)
+	})
+
+	t.Run("register and unregister", func(t *testing.T) {
+		r := NewProtocolRegistrar()
+		require.Empty(t, r.Services())
+		require.NoError(t, r.Register())
+
+		svc1 := &protocolService{name: "svc1", msgType: "type"}
+		svc2 := &protocolService{name: "svc2", msgType: "type"}
+
+		require.NoError(t, r.Register(svc1, svc2))
+		require.Equal(t, []ProtocolService{svc1, svc2}, r.Services())
+
+		// the first registered service accepting the message type handles it
+		accepting, ok := r.Accepting("type")
+		require.True(t, ok)
+		require.Equal(t, svc1, accepting)
+
+		_, ok = r.Accepting("unknown")
+		require.False(t, ok)
+
+		require.NoError(t, r.Unregister("svc1"))
+		require.Equal(t, []ProtocolService{svc2}, r.Services())
+
+		accepting, ok = r.Accepting("type")
+		require.True(t, ok)
+		require.Equal(t, svc2, accepting)
+
+		err := r.Unregister("svc1")
+		require.EqualError(t, err, fmt.Sprintf(errNeverRegistered, "svc1"))
+	})
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		svc1 := &protocolService{name: "svc1"}
+		r := NewProtocolRegistrar(svc1)
+
+		err := r.Register(&protocolService{name: "svc2"}, &protocolService{name: "svc1"})
+		require.EqualError(t, err, fmt.Sprintf(errAlreadyRegistered, "svc1"))
+
+		err = r.Register(&protocolService{name: "svc3"}, &protocolService{name: "svc3"})
+		require.EqualError(t, err, fmt.Sprintf(errAlreadyRegistered, "svc3"))
+
+		// none of the services of the failed batch is registered
+		require.Equal(t, []ProtocolService{svc1}, r.Services())
+	})
+
+	t.Run("concurrent registration", func(t *testing.T) {
+		const count = 100
+
+		r := NewProtocolRegistrar()
+
+		var wg sync.WaitGroup
+
+		wg.Add(count)
+
+		for i := 0; i < count; i++ {
+			go func(i int) {
+				defer wg.Done()
+
+				name := fmt.Sprintf("svc%d", i)
+
+				require.NoError(t, r.Register(&protocolService{name: name, msgType: name}))
+
+				_, ok := r.Accepting(name)
+				require.True(t, ok)
+			}(i)
+		}
+
+		wg.Wait()
+
+		require.Len(t, r.Services(), count)
+	})
+}
+
+func TestProtocolRegistrar_ProtocolEvent(t *testing.T) {
+	r := NewProtocolRegistrar()
+
+	require.EqualError(t, r.RegisterProtocolEvent(nil), service.ErrNilChannel.Error())
+	require.EqualError(t, r.UnregisterProtocolEvent(nil), service.ErrNilChannel.Error())
+
+	events := make(chan ProtocolEvent, 3)
+	require.NoError(t, r.RegisterProtocolEvent(events))
+
+	svc1 := &protocolService{name: "svc1"}
+	svc2 := &protocolService{name: "svc2"}
+
+	require.NoError(t, r.Register(svc1, svc2))
+	require.NoError(t, r.Unregister("svc1"))
+
+	require.Equal(t, ProtocolEvent{Type: ProtocolRegistered, Service: svc1}, <-events)
+	require.Equal(t, ProtocolEvent{Type: ProtocolRegistered, Service: svc2}, <-events)
+	require.Equal(t, ProtocolEvent{Type: ProtocolUnregistered, Service: svc1}, <-events)
+
+	require.NoError(t, r.UnregisterProtocolEvent(events))
+	require.EqualError(t, r.UnregisterProtocolEvent(events), "protocol event channel is not registered")
+
+	// no events are sent to the unregistered channel
+	require.NoError(t, r.Unregister("svc2"))
+	require.Empty(t, events)
+}
+
+func TestProtocolRegistrar_ProtocolEventNotReady(t *testing.T) {
+	r := NewProtocolRegistrar()
+
+	events := make(chan ProtocolEvent)
+	require.NoError(t, r.RegisterProtocolEvent(events))
+
+	// the registration does not block while the consumer is not ready
+	require.NoError(t, r.Register(&protocolService{name: "svc1"}))
+	require.NoError(t, r.Unregister("svc1"))
+
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %v", event)
+	default:
+	}
+}
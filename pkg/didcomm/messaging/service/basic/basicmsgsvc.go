@@ -20,6 +20,7 @@ package basic
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
@@ -90,6 +91,11 @@ func (m *MessageService) Name() string {
 	return m.name
 }
 
+// Protocols returns the protocols handled by the service.
+func (m *MessageService) Protocols() []string {
+	return []string{strings.TrimSuffix(MessageRequestType, "/message")}
+}
+
 // Accept is acceptance criteria for this basic message service.
 func (m *MessageService) Accept(msgType string, purpose []string) bool {
 	return msgType == MessageRequestType
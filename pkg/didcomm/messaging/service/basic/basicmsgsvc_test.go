@@ -49,6 +49,12 @@ func TestMessageService_Name(t *testing.T) {
 	})
 }
 
+func TestMessageService_Protocols(t *testing.T) {
+	svc, err := NewMessageService("sample-name", getMockMessageHandle())
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://didcomm.org/basicmessage/1.0"}, svc.Protocols())
+}
+
 func TestMessageService_Accept(t *testing.T) {
 	t.Run("test MessageService.Accept()", func(t *testing.T) {
 		svc, err := NewMessageService("sample-name", getMockMessageHandle())
@@ -24,6 +24,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
@@ -109,6 +110,11 @@ func (m *OverDIDComm) Name() string {
 	return m.name
 }
 
+// Protocols returns the protocols handled by the service.
+func (m *OverDIDComm) Protocols() []string {
+	return []string{strings.TrimSuffix(OverDIDCommSpec, "/")}
+}
+
 // Accept is acceptance criteria for this HTTP over DIDComm message service,
 // it accepts http-didcomm-over message type [RFC-0335] and follows `A tagging system` purpose field validation
 // from RFC-0351.
@@ -80,6 +80,12 @@ func TestOverDIDComm_Name(t *testing.T) {
 	require.Equal(t, sampleName, svc.Name())
 }
 
+func TestOverDIDComm_Protocols(t *testing.T) {
+	svc, err := NewOverDIDComm("sample-name-01", newMockHandle(), "prp-01", "prp-02")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://didcomm.org/http-over-didcomm/1.0"}, svc.Protocols())
+}
+
 func TestOverDIDComm_Accept(t *testing.T) {
 	tests := []struct {
 		name      string
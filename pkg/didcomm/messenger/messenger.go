@@ -11,11 +11,14 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
@@ -23,13 +26,15 @@ const (
 	// MessengerStore is messenger store name.
 	MessengerStore = "messenger_store"
 
-	metadataKey = "metadata_%s"
+	metadataKey       = "metadata_%s"
+	connectionlessKey = "connectionless_%s"
 
 	jsonID             = "@id"
 	jsonThread         = "~thread"
 	jsonThreadID       = "thid"
 	jsonParentThreadID = "pthid"
 	jsonMetadata       = "_internal_metadata"
+	jsonService        = "~service"
 )
 
 // record is an internal structure and keeps payload about inbound message.
@@ -39,18 +44,26 @@ type record struct {
 	ThreadID       string                 `json:"thread_id,omitempty"`
 	ParentThreadID string                 `json:"parent_thread_id,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	// Service is the ~service decorator of the other agent (connectionless messages).
+	Service *decorator.Service `json:"service,omitempty"`
+	// MyKey is the key the other agent encrypts the messages of the thread for (connectionless messages).
+	MyKey string `json:"my_key,omitempty"`
 }
 
 // Provider contains dependencies for the Messenger.
 type Provider interface {
 	OutboundDispatcher() dispatcher.Outbound
 	StorageProvider() storage.Provider
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
 }
 
 // Messenger describes the messenger structure.
 type Messenger struct {
 	store      storage.Store
 	dispatcher dispatcher.Outbound
+	kms        kms.KeyManager
+	endpoint   string
 }
 
 var logger = log.New("aries-framework/pkg/didcomm/messenger")
@@ -65,6 +78,8 @@ func NewMessenger(ctx Provider) (*Messenger, error) {
 	return &Messenger{
 		store:      store,
 		dispatcher: ctx.OutboundDispatcher(),
+		kms:        ctx.KMS(),
+		endpoint:   ctx.ServiceEndpoint(),
 	}, nil
 }
 
@@ -87,12 +102,28 @@ func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID str
 		return fmt.Errorf("with metadata: %w", err)
 	}
 
+	var svc *decorator.Service
+
+	// there is no connection with the other agent, the replies are sent according to the ~service decorator
+	if theirDID == "" {
+		svc = serviceDecorator(msg)
+	}
+
+	if svc != nil {
+		if err := m.saveService(thID, svc); err != nil {
+			return fmt.Errorf("save service: %w", err)
+		}
+	}
+
+	logger.WithFields(service.LogFields(msg)).Debugf("inbound message")
+
 	// saves message payload
 	return m.saveRecord(msg.ID(), record{
 		ParentThreadID: msg.ParentThreadID(),
 		MyDID:          myDID,
 		TheirDID:       theirDID,
 		ThreadID:       thID,
+		Service:        svc,
 	})
 }
 
@@ -144,6 +175,16 @@ func (m *Messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) erro
 		jsonThreadID: msg.ID(),
 	}
 
+	// the connectionless message is delivered by the caller out-of-band (e.g as the out-of-band request attachment),
+	// the other agent replies according to the ~service decorator of the message.
+	if theirDID == "" {
+		if svc := serviceDecorator(msg); svc != nil {
+			return m.saveMyKey(msg.ID(), svc)
+		}
+	}
+
+	logger.WithFields(service.LogFields(msg)).Debugf("send message")
+
 	return m.dispatcher.SendToDID(msg, myDID, theirDID)
 }
 
@@ -161,6 +202,8 @@ func (m *Messenger) SendToDestination(msg service.DIDCommMsgMap, sender string,
 
 	delete(msg, jsonThread)
 
+	logger.WithFields(service.LogFields(msg)).Debugf("send message to destination")
+
 	return m.dispatcher.Send(msg, sender, destination)
 }
 
@@ -192,6 +235,15 @@ func (m *Messenger) ReplyTo(msgID string, msg service.DIDCommMsgMap) error {
 		return fmt.Errorf("save metadata: %w", err)
 	}
 
+	logger.WithFields(service.LogFields(msg)).Debugf("reply to message %s", msgID)
+
+	if rec.TheirDID == "" && rec.Service != nil {
+		sent, err := m.replyConnectionless(msg, rec.ThreadID)
+		if sent || err != nil {
+			return err
+		}
+	}
+
 	return m.dispatcher.SendToDID(msg, rec.MyDID, rec.TheirDID)
 }
 
@@ -214,9 +266,106 @@ func (m *Messenger) ReplyToNested(msg service.DIDCommMsgMap, opts *service.Neste
 	// sets parent threadID
 	msg[jsonThread] = map[string]interface{}{jsonParentThreadID: opts.ThreadID}
 
+	logger.WithFields(service.LogFields(msg)).Debugf("nested reply")
+
+	if opts.TheirDID == "" {
+		sent, err := m.replyConnectionless(msg, opts.ThreadID)
+		if sent || err != nil {
+			return err
+		}
+	}
+
 	return m.dispatcher.SendToDID(msg, opts.MyDID, opts.TheirDID)
 }
 
+// replyConnectionless sends the message to the other agent according to its ~service decorator.
+// The message gets the ~service decorator with the ephemeral key of the thread, so the other agent can reply.
+// It returns false if there is no ~service decorator of the other agent for the thread.
+func (m *Messenger) replyConnectionless(msg service.DIDCommMsgMap, thID string) (bool, error) {
+	rec, err := m.getRecord(fmt.Sprintf(connectionlessKey, thID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("get connectionless record: %w", err)
+	}
+
+	if rec.Service == nil {
+		return false, nil
+	}
+
+	if rec.MyKey == "" {
+		_, pubKey, err := m.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		if err != nil {
+			return false, fmt.Errorf("create ephemeral key: %w", err)
+		}
+
+		rec.MyKey = base58.Encode(pubKey)
+
+		if err = m.saveRecord(fmt.Sprintf(connectionlessKey, thID), *rec); err != nil {
+			return false, fmt.Errorf("save connectionless record: %w", err)
+		}
+	}
+
+	msg[jsonService] = &decorator.Service{
+		RecipientKeys:   []string{rec.MyKey},
+		ServiceEndpoint: m.endpoint,
+	}
+
+	logger.WithFields(service.LogFields(msg)).Debugf("connectionless reply")
+
+	return true, m.dispatcher.Send(msg, rec.MyKey, &service.Destination{
+		RecipientKeys:   rec.Service.RecipientKeys,
+		RoutingKeys:     rec.Service.RoutingKeys,
+		ServiceEndpoint: rec.Service.ServiceEndpoint,
+	})
+}
+
+// saveService saves the ~service decorator of the other agent for the thread.
+func (m *Messenger) saveService(thID string, svc *decorator.Service) error {
+	rec, err := m.getRecord(fmt.Sprintf(connectionlessKey, thID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		rec, err = &record{}, nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get connectionless record: %w", err)
+	}
+
+	rec.Service = svc
+
+	return m.saveRecord(fmt.Sprintf(connectionlessKey, thID), *rec)
+}
+
+// saveMyKey saves the key of the ~service decorator the thread was started with.
+func (m *Messenger) saveMyKey(thID string, svc *decorator.Service) error {
+	if len(svc.RecipientKeys) == 0 {
+		return errors.New("~service decorator: recipient keys are absent")
+	}
+
+	return m.saveRecord(fmt.Sprintf(connectionlessKey, thID), record{MyKey: svc.RecipientKeys[0]})
+}
+
+// serviceDecorator returns the ~service decorator of the message if any.
+func serviceDecorator(msg service.DIDCommMsgMap) *decorator.Service {
+	if _, ok := msg[jsonService]; !ok {
+		return nil
+	}
+
+	v := struct {
+		Service *decorator.Service `json:"~service"`
+	}{}
+
+	if err := msg.Decode(&v); err != nil {
+		logger.Warnf("invalid ~service decorator: %s", err)
+
+		return nil
+	}
+
+	return v.Service
+}
+
 // fillIfMissing populates message with common fields such as ID.
 func fillIfMissing(msg service.DIDCommMsgMap) {
 	// if ID is empty we will create a new one
@@ -11,15 +11,20 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
 	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
 	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 )
 
 const (
@@ -44,6 +49,8 @@ func TestNewMessenger(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -78,6 +85,8 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -97,6 +106,8 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -112,6 +123,8 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -131,6 +144,8 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -152,6 +167,8 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -176,6 +193,8 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -230,6 +249,8 @@ func TestMessenger_Send(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -249,6 +270,8 @@ func TestMessenger_Send(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -268,6 +291,8 @@ func TestMessenger_Send(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -286,6 +311,8 @@ func TestMessenger_Send(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -321,6 +348,8 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -338,6 +367,8 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -361,6 +392,8 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -380,6 +413,8 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -409,6 +444,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -431,6 +468,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -450,6 +489,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -469,6 +510,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(nil)
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -490,6 +533,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
 		provider.EXPECT().OutboundDispatcher().Return(dispatcherMocks.NewMockOutbound(ctrl))
+		provider.EXPECT().KMS().Return(nil)
+		provider.EXPECT().ServiceEndpoint().Return("")
 
 		msgr, err := NewMessenger(provider)
 		require.NoError(t, err)
@@ -500,3 +545,105 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		require.Contains(t, err.Error(), errMsg)
 	})
 }
+
+func TestMessenger_Connectionless(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const endpoint = "http://example.com"
+
+	newMessenger := func(t *testing.T, outbound dispatcher.Outbound, km kms.KeyManager) *Messenger {
+		t.Helper()
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+		provider.EXPECT().KMS().Return(km)
+		provider.EXPECT().ServiceEndpoint().Return(endpoint)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	theirService := &decorator.Service{
+		RecipientKeys:   []string{"their-key"},
+		RoutingKeys:     []string{"routing-key"},
+		ServiceEndpoint: "http://their.example.com",
+	}
+
+	sendCheck := func(t *testing.T, myKey string) func(msg service.DIDCommMsgMap, sender string,
+		dest *service.Destination) error {
+		return func(msg service.DIDCommMsgMap, sender string, dest *service.Destination) error {
+			require.Equal(t, myKey, sender)
+			require.Equal(t, &service.Destination{
+				RecipientKeys:   theirService.RecipientKeys,
+				RoutingKeys:     theirService.RoutingKeys,
+				ServiceEndpoint: theirService.ServiceEndpoint,
+			}, dest)
+
+			v := struct {
+				Service *decorator.Service `json:"~service"`
+			}{}
+
+			require.NoError(t, msg.Decode(&v))
+			require.Equal(t, &decorator.Service{RecipientKeys: []string{myKey}, ServiceEndpoint: endpoint}, v.Service)
+
+			return nil
+		}
+	}
+
+	t.Run("Replies according to the ~service decorator", func(t *testing.T) {
+		km := &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("my-key")}
+		myKey := base58.Encode(km.CrAndExportPubKeyValue)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Do(sendCheck(t, myKey)).Times(2)
+
+		msgr := newMessenger(t, outbound, km)
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: ID, jsonService: theirService}, "", ""))
+		require.NoError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{}))
+
+		// the ephemeral key of the thread is reused
+		km.CrAndExportPubKeyErr = errors.New(errMsg)
+
+		require.NoError(t, msgr.ReplyToNested(service.DIDCommMsgMap{}, &service.NestedReplyOpts{ThreadID: ID}))
+	})
+
+	t.Run("Replies with the key of the message which started the thread", func(t *testing.T) {
+		const myKey = "my-key"
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Do(sendCheck(t, myKey))
+
+		msgr := newMessenger(t, outbound, &mockkms.KeyManager{CrAndExportPubKeyErr: errors.New(errMsg)})
+
+		// the message is delivered out-of-band
+		require.NoError(t, msgr.Send(service.DIDCommMsgMap{
+			jsonID:      ID,
+			jsonService: &decorator.Service{RecipientKeys: []string{myKey}, ServiceEndpoint: endpoint},
+		}, "", ""))
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{
+			jsonID:      msgID,
+			jsonThread:  map[string]interface{}{jsonThreadID: ID},
+			jsonService: theirService,
+		}, "", ""))
+		require.NoError(t, msgr.ReplyTo(msgID, service.DIDCommMsgMap{}))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		msgr := newMessenger(t, dispatcherMocks.NewMockOutbound(ctrl),
+			&mockkms.KeyManager{CrAndExportPubKeyErr: errors.New(errMsg)})
+
+		err := msgr.Send(service.DIDCommMsgMap{jsonService: &decorator.Service{}}, "", "")
+		require.EqualError(t, err, "~service decorator: recipient keys are absent")
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: ID, jsonService: theirService}, "", ""))
+
+		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{})
+		require.EqualError(t, err, "create ephemeral key: "+errMsg)
+	})
+}
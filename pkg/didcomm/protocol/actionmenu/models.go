@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Menu message presents the actions the requester may take.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0509-action-menu#menu
+type Menu struct {
+	Type        string            `json:"@type,omitempty"`
+	ID          string            `json:"@id,omitempty"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	ErrorMsg    string            `json:"errormsg,omitempty"`
+	Options     []MenuOption      `json:"options"`
+}
+
+// MenuOption is the action the requester may perform.
+type MenuOption struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Form        *Form  `json:"form,omitempty"`
+}
+
+// Form describes the parameters the requester has to provide to perform the action.
+type Form struct {
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Params      []FormParam `json:"params,omitempty"`
+	SubmitLabel string      `json:"submit-label,omitempty"`
+}
+
+// FormParam is the form parameter.
+type FormParam struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MenuRequest message asks the responder to send its current menu.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0509-action-menu#menu-request
+type MenuRequest struct {
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+}
+
+// Perform message requests the responder to perform the menu option.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0509-action-menu#perform
+type Perform struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+}
@@ -0,0 +1,504 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+var logger = log.New("aries-framework/actionmenu/service")
+
+const (
+	// ActionMenu defines the protocol name.
+	ActionMenu = "action-menu"
+	// PIURI is the action menu protocol identifier URI.
+	PIURI = "https://didcomm.org/action-menu/1.0"
+	// MenuMsgType defines the protocol menu message type.
+	MenuMsgType = PIURI + "/menu"
+	// MenuRequestMsgType defines the protocol menu-request message type.
+	MenuRequestMsgType = PIURI + "/menu-request"
+	// PerformMsgType defines the protocol perform message type.
+	PerformMsgType = PIURI + "/perform"
+)
+
+// States of the protocol reported by the message events.
+const (
+	// StateMenuReceived the menu was received and became the active menu of the connection.
+	StateMenuReceived = "menu-received"
+	// StateMenuRequested the menu-request was received, an action event is triggered to provide the menu.
+	StateMenuRequested = "menu-requested"
+	// StatePerformReceived the perform message was received, an action event is triggered to perform the option.
+	StatePerformReceived = "perform-received"
+)
+
+const (
+	activeMenuKey          = "activeMenu_%s"
+	transitionalPayloadKey = "transitionalPayload_%s"
+)
+
+var (
+	// ErrConnectionNotFound connection not found error.
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrNoActiveMenu is returned when no menu was received over the connection.
+	ErrNoActiveMenu = errors.New("no active menu")
+)
+
+// Provider contains dependencies for the action menu protocol and is typically created by using aries.Context().
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// Action contains helpful information about action.
+type Action struct {
+	// Protocol instance ID
+	PIID         string
+	ConnectionID string
+	Msg          service.DIDCommMsgMap
+	MyDID        string
+	TheirDID     string
+}
+
+// Opt describes how the action is continued.
+type Opt func(opts *options)
+
+type options struct {
+	menu *Menu
+}
+
+// WithMenu sends the menu as a response to the action. The menu is required to continue a menu-request,
+// it is optional when continuing a perform (e.g the menu changed as a result of the performed option).
+// USAGE: event.Continue(WithMenu(menu)).
+func WithMenu(menu *Menu) Opt {
+	return func(opts *options) {
+		opts.menu = menu
+	}
+}
+
+type connections interface {
+	GetConnectionIDByDIDs(string, string) (string, error)
+	GetConnectionRecord(string) (*connection.Record, error)
+}
+
+type eventProps struct {
+	piid         string
+	connectionID string
+}
+
+func (e *eventProps) All() map[string]interface{} {
+	return map[string]interface{}{
+		"piid":         e.piid,
+		"connectionID": e.connectionID,
+	}
+}
+
+// Service for the action menu protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0509-action-menu
+type Service struct {
+	service.Action
+	service.Message
+	messenger        service.Messenger
+	store            storage.Store
+	connectionLookup connections
+}
+
+// New returns the action menu service.
+func New(p Provider) (*Service, error) {
+	store, err := p.StorageProvider().OpenStore(ActionMenu)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	connectionLookup, err := connection.NewLookup(p)
+	if err != nil {
+		return nil, fmt.Errorf("new connection lookup: %w", err)
+	}
+
+	return &Service{
+		messenger:        p.Messenger(),
+		store:            store,
+		connectionLookup: connectionLookup,
+	}, nil
+}
+
+// HandleInbound handles inbound action menu messages.
+// The menu becomes the active menu of the connection, menu-request and perform messages trigger action events.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	logger.WithFields(service.LogFields(msg)).Debugf("service.HandleInbound() myDID=%s theirDID=%s", myDID, theirDID)
+
+	if !s.Accept(msg.Type()) {
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	connectionID, err := s.connectionLookup.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("connectionID lookup using DIDs: %w", err)
+	}
+
+	switch msg.Type() {
+	case MenuMsgType:
+		if err := s.handleMenu(msg, connectionID); err != nil {
+			return "", err
+		}
+
+		s.sendMsgEvent(msg, StateMenuReceived, connectionID)
+	case MenuRequestMsgType:
+		if err := s.sendActionEvent(msg, connectionID, myDID, theirDID); err != nil {
+			return "", err
+		}
+
+		s.sendMsgEvent(msg, StateMenuRequested, connectionID)
+	case PerformMsgType:
+		if err := s.sendActionEvent(msg, connectionID, myDID, theirDID); err != nil {
+			return "", err
+		}
+
+		s.sendMsgEvent(msg, StatePerformReceived, connectionID)
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound sends the action menu message.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if !s.Accept(msg.Type()) {
+		return "", fmt.Errorf("invalid or unsupported outbound message type %s", msg.Type())
+	}
+
+	msgMap := msg.Clone()
+
+	if err := s.messenger.Send(msgMap, myDID, theirDID); err != nil {
+		return "", fmt.Errorf("send message: %w", err)
+	}
+
+	return msgMap.ID(), nil
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case MenuMsgType, MenuRequestMsgType, PerformMsgType:
+		return true
+	}
+
+	return false
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return ActionMenu
+}
+
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{PIURI}
+}
+
+// SendMenu sends the menu to the agent on the other end of the connection.
+func (s *Service) SendMenu(connectionID string, menu *Menu) (string, error) {
+	record, err := s.getConnection(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("get connection: %w", err)
+	}
+
+	msg := service.NewDIDCommMsgMap(newMenu(menu))
+
+	if err := s.messenger.Send(msg, record.MyDID, record.TheirDID); err != nil {
+		return "", fmt.Errorf("send menu: %w", err)
+	}
+
+	return msg.ID(), nil
+}
+
+// RequestMenu asks the agent on the other end of the connection to send its menu.
+func (s *Service) RequestMenu(connectionID string) (string, error) {
+	record, err := s.getConnection(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("get connection: %w", err)
+	}
+
+	msg := service.NewDIDCommMsgMap(&MenuRequest{
+		Type: MenuRequestMsgType,
+		ID:   uuid.New().String(),
+	})
+
+	if err := s.messenger.Send(msg, record.MyDID, record.TheirDID); err != nil {
+		return "", fmt.Errorf("send menu request: %w", err)
+	}
+
+	return msg.ID(), nil
+}
+
+// ActiveMenu returns the last menu received over the connection.
+func (s *Service) ActiveMenu(connectionID string) (*Menu, error) {
+	src, err := s.store.Get(fmt.Sprintf(activeMenuKey, connectionID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrNoActiveMenu
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("store get: %w", err)
+	}
+
+	menu := &Menu{}
+	if err := json.Unmarshal(src, menu); err != nil {
+		return nil, fmt.Errorf("unmarshal menu: %w", err)
+	}
+
+	return menu, nil
+}
+
+// Perform asks the agent on the other end of the connection to perform the option of the active menu.
+// The option must be enabled and all the required form parameters must be provided.
+func (s *Service) Perform(connectionID, name string, params map[string]string) (string, error) {
+	menu, err := s.ActiveMenu(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("active menu: %w", err)
+	}
+
+	if err := validatePerform(menu, name, params); err != nil {
+		return "", err
+	}
+
+	msg := service.NewDIDCommMsgMap(&Perform{
+		Type:   PerformMsgType,
+		ID:     uuid.New().String(),
+		Name:   name,
+		Params: params,
+	})
+
+	if err := s.messenger.ReplyTo(menu.ID, msg); err != nil {
+		return "", fmt.Errorf("send perform: %w", err)
+	}
+
+	return msg.ID(), nil
+}
+
+// Actions returns actions for the async usage.
+func (s *Service) Actions() ([]Action, error) {
+	records := s.store.Iterator(
+		fmt.Sprintf(transitionalPayloadKey, ""),
+		fmt.Sprintf(transitionalPayloadKey, storage.EndKeySuffix),
+	)
+	defer records.Release()
+
+	var actions []Action
+
+	for records.Next() {
+		var action Action
+		if err := json.Unmarshal(records.Value(), &action); err != nil {
+			return nil, fmt.Errorf("unmarshal: %w", err)
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, records.Error()
+}
+
+// ActionContinue allows proceeding with the action by the piID.
+func (s *Service) ActionContinue(piID string, opt Opt) error {
+	action, err := s.getTransitionalPayload(piID)
+	if err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	opts := &options{}
+
+	if opt != nil {
+		opt(opts)
+	}
+
+	if opts.menu == nil && action.Msg.Type() == MenuRequestMsgType {
+		return errors.New("menu is required to respond to the menu request")
+	}
+
+	if opts.menu != nil {
+		if err := s.messenger.ReplyTo(action.PIID, service.NewDIDCommMsgMap(newMenu(opts.menu))); err != nil {
+			return fmt.Errorf("send menu: %w", err)
+		}
+	}
+
+	return s.deleteTransitionalPayload(piID)
+}
+
+// ActionStop allows stopping the action by the piID.
+func (s *Service) ActionStop(piID string, cErr error) error {
+	action, err := s.getTransitionalPayload(piID)
+	if err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	logger.WithFields(service.LogFields(action.Msg)).Infof("action stopped: %v", cErr)
+
+	return s.deleteTransitionalPayload(piID)
+}
+
+func (s *Service) handleMenu(msg service.DIDCommMsg, connectionID string) error {
+	menu := &Menu{}
+
+	if err := msg.Decode(menu); err != nil {
+		return fmt.Errorf("menu message unmarshal: %w", err)
+	}
+
+	src, err := json.Marshal(menu)
+	if err != nil {
+		return fmt.Errorf("marshal menu: %w", err)
+	}
+
+	return s.store.Put(fmt.Sprintf(activeMenuKey, connectionID), src)
+}
+
+func (s *Service) sendActionEvent(msg service.DIDCommMsg, connectionID, myDID, theirDID string) error {
+	events := s.ActionEvent()
+	if events == nil {
+		return fmt.Errorf("no clients registered to handle action events for %s protocol", ActionMenu)
+	}
+
+	piID := msg.ID()
+
+	err := s.saveTransitionalPayload(&Action{
+		PIID:         piID,
+		ConnectionID: connectionID,
+		Msg:          msg.Clone(),
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+	})
+	if err != nil {
+		return fmt.Errorf("save transitional payload: %w", err)
+	}
+
+	go func() {
+		events <- service.DIDCommAction{
+			ProtocolName: ActionMenu,
+			Message:      msg,
+			Continue: func(args interface{}) {
+				opt, _ := args.(Opt)
+
+				if err := s.ActionContinue(piID, opt); err != nil {
+					logger.WithFields(service.LogFields(msg)).Errorf("action continue: %s", err)
+				}
+			},
+			Stop: func(err error) {
+				if err := s.ActionStop(piID, err); err != nil {
+					logger.WithFields(service.LogFields(msg)).Errorf("action stop: %s", err)
+				}
+			},
+			Properties: &eventProps{piid: piID, connectionID: connectionID},
+		}
+	}()
+
+	return nil
+}
+
+func (s *Service) sendMsgEvent(msg service.DIDCommMsg, state, connectionID string) {
+	stateMsg := service.StateMsg{
+		ProtocolName: ActionMenu,
+		Type:         service.PostState,
+		StateID:      state,
+		Msg:          msg,
+		Properties:   &eventProps{piid: msg.ID(), connectionID: connectionID},
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
+
+func (s *Service) deleteTransitionalPayload(id string) error {
+	return s.store.Delete(fmt.Sprintf(transitionalPayloadKey, id))
+}
+
+func (s *Service) saveTransitionalPayload(action *Action) error {
+	src, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("marshal transitional payload: %w", err)
+	}
+
+	return s.store.Put(fmt.Sprintf(transitionalPayloadKey, action.PIID), src)
+}
+
+func (s *Service) getTransitionalPayload(id string) (*Action, error) {
+	src, err := s.store.Get(fmt.Sprintf(transitionalPayloadKey, id))
+	if err != nil {
+		return nil, fmt.Errorf("store get: %w", err)
+	}
+
+	action := &Action{}
+
+	if err := json.Unmarshal(src, action); err != nil {
+		return nil, fmt.Errorf("unmarshal transitional payload: %w", err)
+	}
+
+	return action, nil
+}
+
+// newMenu returns a copy of the menu ready to be sent.
+func newMenu(menu *Menu) *Menu {
+	msg := *menu
+	msg.Type = MenuMsgType
+	msg.Thread = nil
+
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+
+	if msg.Options == nil {
+		msg.Options = []MenuOption{}
+	}
+
+	return &msg
+}
+
+func validatePerform(menu *Menu, name string, params map[string]string) error {
+	for _, option := range menu.Options {
+		if option.Name != name {
+			continue
+		}
+
+		if option.Disabled {
+			return fmt.Errorf("menu option %s is disabled", name)
+		}
+
+		if option.Form == nil {
+			return nil
+		}
+
+		for _, param := range option.Form.Params {
+			if _, ok := params[param.Name]; param.Required && !ok {
+				return fmt.Errorf("missing required param %s", param.Name)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("menu option %s not found", name)
+}
@@ -0,0 +1,571 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+	connID   = "conn-id"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+		require.Equal(t, ActionMenu, svc.Name())
+		require.Equal(t, []string{PIURI}, svc.Protocols())
+	})
+
+	t.Run("test new service - open store error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.storageProvider = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open store")
+	})
+
+	t.Run("test new service - connection lookup error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.protocolStateStorage = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	svc, err := New(newProvider(nil))
+	require.NoError(t, err)
+
+	require.True(t, svc.Accept(MenuMsgType))
+	require.True(t, svc.Accept(MenuRequestMsgType))
+	require.True(t, svc.Accept(PerformMsgType))
+	require.False(t, svc.Accept("unsupported"))
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("menu - success", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		_, err = svc.ActiveMenu(connID)
+		require.True(t, errors.Is(err, ErrNoActiveMenu))
+
+		msgID, err := svc.HandleInbound(service.NewDIDCommMsgMap(sampleMenu()), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "menu-id", msgID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, ActionMenu, event.ProtocolName)
+			require.Equal(t, service.PostState, event.Type)
+			require.Equal(t, StateMenuReceived, event.StateID)
+			require.Equal(t, connID, event.Properties.All()["connectionID"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		menu, err := svc.ActiveMenu(connID)
+		require.NoError(t, err)
+		require.Equal(t, sampleMenu().Options, menu.Options)
+		require.Equal(t, "menu-id", menu.ID)
+	})
+
+	t.Run("menu - decode error", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type":   MenuMsgType,
+			"@id":     "menu-id",
+			"options": "invalid",
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "menu message unmarshal")
+	})
+
+	t.Run("menu request - action continue with menu", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		actions := make(chan service.DIDCommAction)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		states := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(states))
+
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyTo("request-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				defer close(done)
+
+				menu := &Menu{}
+				require.NoError(t, msg.Decode(menu))
+				require.Equal(t, MenuMsgType, menu.Type)
+				require.NotEmpty(t, menu.ID)
+				require.Equal(t, "Welcome", menu.Title)
+
+				return nil
+			})
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&MenuRequest{
+			Type: MenuRequestMsgType,
+			ID:   "request-id",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		require.Equal(t, StateMenuRequested, (<-states).StateID)
+
+		pending, err := svc.Actions()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, "request-id", pending[0].PIID)
+		require.Equal(t, connID, pending[0].ConnectionID)
+
+		select {
+		case action := <-actions:
+			require.Equal(t, ActionMenu, action.ProtocolName)
+			require.Equal(t, "request-id", action.Properties.All()["piid"])
+			action.Continue(WithMenu(&Menu{Title: "Welcome"}))
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the action event")
+		}
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the menu")
+		}
+
+		pending, err = svc.Actions()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+
+	t.Run("perform - action stop", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		actions := make(chan service.DIDCommAction)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Perform{
+			Type: PerformMsgType,
+			ID:   "perform-id",
+			Name: "balance",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		select {
+		case action := <-actions:
+			action.Stop(errors.New("not allowed"))
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the action event")
+		}
+
+		require.Eventually(t, func() bool {
+			pending, err := svc.Actions()
+			require.NoError(t, err)
+
+			return len(pending) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("no action event clients", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&MenuRequest{
+			Type: MenuRequestMsgType,
+			ID:   "request-id",
+		}), MYDID, THEIRDID)
+		require.EqualError(t, err, "no clients registered to handle action events for action-menu protocol")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(sampleMenu()), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connectionID lookup using DIDs")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Menu{Type: "unsupported"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unsupported message type unsupported")
+	})
+}
+
+func TestService_ActionContinue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newService := func(t *testing.T, messenger service.Messenger, msg interface{}) *Service {
+		t.Helper()
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(msg), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	t.Run("perform - without menu", func(t *testing.T) {
+		svc := newService(t, nil, &Perform{Type: PerformMsgType, ID: "perform-id", Name: "balance"})
+
+		require.NoError(t, svc.ActionContinue("perform-id", nil))
+
+		pending, err := svc.Actions()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+
+	t.Run("menu request - menu is required", func(t *testing.T) {
+		svc := newService(t, nil, &MenuRequest{Type: MenuRequestMsgType, ID: "request-id"})
+
+		err := svc.ActionContinue("request-id", nil)
+		require.EqualError(t, err, "menu is required to respond to the menu request")
+
+		pending, err := svc.Actions()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+	})
+
+	t.Run("menu request - reply error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("request-id", gomock.Any()).Return(errors.New("reply error"))
+
+		svc := newService(t, messenger, &MenuRequest{Type: MenuRequestMsgType, ID: "request-id"})
+
+		err := svc.ActionContinue("request-id", WithMenu(sampleMenu()))
+		require.EqualError(t, err, "send menu: reply error")
+	})
+
+	t.Run("action not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		err = svc.ActionContinue("unknown", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get transitional payload")
+
+		err = svc.ActionStop("unknown", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get transitional payload")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		msgID, err := svc.HandleOutbound(service.NewDIDCommMsgMap(sampleMenu()), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "menu-id", msgID)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(sampleMenu()), MYDID, THEIRDID)
+		require.EqualError(t, err, "send message: send error")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&Menu{Type: "unsupported"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "invalid or unsupported outbound message type unsupported")
+	})
+}
+
+func TestService_SendMenu(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				require.Equal(t, MenuMsgType, msg.Type())
+
+				return nil
+			})
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		msgID, err := svc.SendMenu(connID, &Menu{Title: "Welcome"})
+		require.NoError(t, err)
+		require.NotEmpty(t, msgID)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.SendMenu(connID, sampleMenu())
+		require.EqualError(t, err, "send menu: send error")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.SendMenu(connID, sampleMenu())
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+}
+
+func TestService_RequestMenu(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				require.Equal(t, MenuRequestMsgType, msg.Type())
+
+				return nil
+			})
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		msgID, err := svc.RequestMenu(connID)
+		require.NoError(t, err)
+		require.NotEmpty(t, msgID)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RequestMenu(connID)
+		require.EqualError(t, err, "send menu request: send error")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.RequestMenu(connID)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+}
+
+func TestService_Perform(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newService := func(t *testing.T, messenger service.Messenger) *Service {
+		t.Helper()
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(sampleMenu()), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("menu-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				perform := &Perform{}
+				require.NoError(t, msg.Decode(perform))
+				require.Equal(t, PerformMsgType, perform.Type)
+				require.Equal(t, "transfer", perform.Name)
+				require.Equal(t, map[string]string{"amount": "10"}, perform.Params)
+
+				return nil
+			})
+
+		svc := newService(t, messenger)
+
+		msgID, err := svc.Perform(connID, "transfer", map[string]string{"amount": "10"})
+		require.NoError(t, err)
+		require.NotEmpty(t, msgID)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		svc := newService(t, nil)
+
+		_, err := svc.Perform(connID, "unknown", nil)
+		require.EqualError(t, err, "menu option unknown not found")
+
+		_, err = svc.Perform(connID, "loan", nil)
+		require.EqualError(t, err, "menu option loan is disabled")
+
+		_, err = svc.Perform(connID, "transfer", nil)
+		require.EqualError(t, err, "missing required param amount")
+	})
+
+	t.Run("reply error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("menu-id", gomock.Any()).Return(errors.New("reply error"))
+
+		svc := newService(t, messenger)
+
+		_, err := svc.Perform(connID, "balance", nil)
+		require.EqualError(t, err, "send perform: reply error")
+	})
+
+	t.Run("no active menu", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.Perform(connID, "balance", nil)
+		require.True(t, errors.Is(err, ErrNoActiveMenu))
+	})
+}
+
+func sampleMenu() *Menu {
+	return &Menu{
+		Type:  MenuMsgType,
+		ID:    "menu-id",
+		Title: "Bank",
+		Options: []MenuOption{
+			{Name: "balance", Title: "Check balance"},
+			{Name: "loan", Title: "Apply for a loan", Disabled: true},
+			{
+				Name:  "transfer",
+				Title: "Transfer funds",
+				Form: &Form{
+					Params:      []FormParam{{Name: "amount", Required: true}, {Name: "memo"}},
+					SubmitLabel: "Transfer",
+				},
+			},
+		},
+	}
+}
+
+func saveConnection(t *testing.T, prov *provider) {
+	t.Helper()
+
+	r, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connID, MyDID: MYDID, TheirDID: THEIRDID, State: "completed",
+	}))
+}
+
+func newProvider(messenger service.Messenger) *provider {
+	return &provider{
+		messenger:            messenger,
+		storageProvider:      mockstore.NewMockStoreProvider(),
+		protocolStateStorage: mockstore.NewMockStoreProvider(),
+	}
+}
+
+type provider struct {
+	messenger            service.Messenger
+	storageProvider      storage.Provider
+	protocolStateStorage storage.Provider
+}
+
+func (p *provider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+func (p *provider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *provider) ProtocolStateStorageProvider() storage.Provider {
+	return p.protocolStateStorage
+}
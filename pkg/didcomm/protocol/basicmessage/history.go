@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Directions of the messages in the history.
+const (
+	// DirectionSent the message was sent by the agent.
+	DirectionSent = "sent"
+	// DirectionReceived the message was received by the agent.
+	DirectionReceived = "received"
+)
+
+const historyKey = "history_%s_%s"
+
+// Record is the message saved in the history of the connection.
+type Record struct {
+	MessageID    string    `json:"message_id"`
+	ConnectionID string    `json:"connection_id"`
+	Direction    string    `json:"direction"`
+	Content      string    `json:"content"`
+	Locale       string    `json:"locale,omitempty"`
+	SentTime     time.Time `json:"sent_time"`
+	// Time is when the message was sent or received by the agent.
+	Time time.Time `json:"time"`
+}
+
+// QueryParams holds the filters and the page of the history query, the records are sorted by time.
+// Empty filters match all the records.
+type QueryParams struct {
+	ConnectionID string
+	// Direction is one of DirectionSent and DirectionReceived.
+	Direction  string
+	Descending bool
+	// Limit is the maximum number of records of the page, all the records are returned if not set.
+	Limit int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+}
+
+// QueryResult is the page of the message history.
+type QueryResult struct {
+	Records []*Record
+	// NextCursor is the cursor of the next page, it is empty on the last page.
+	NextCursor string
+}
+
+// cursor is the position of the last record of the page in the sort order.
+type cursor struct {
+	Time      int64  `json:"t"`
+	MessageID string `json:"id"`
+}
+
+// History returns the page of the message history matching the query parameters.
+func (s *Service) History(params *QueryParams) (*QueryResult, error) {
+	if params.Direction != "" && params.Direction != DirectionSent && params.Direction != DirectionReceived {
+		return nil, fmt.Errorf("unsupported direction %s", params.Direction)
+	}
+
+	if params.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", params.Limit)
+	}
+
+	var after *cursor
+
+	if params.Cursor != "" {
+		var err error
+
+		after, err = decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	records, err := s.queryRecords(params.ConnectionID, func(record *Record) bool {
+		return params.matches(record) && (after == nil || params.less(after, position(record)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return params.less(position(records[i]), position(records[j]))
+	})
+
+	result := &QueryResult{Records: records}
+
+	if params.Limit > 0 && len(records) > params.Limit {
+		result.Records = records[:params.Limit]
+		result.NextCursor = encodeCursor(position(result.Records[params.Limit-1]))
+	}
+
+	return result, nil
+}
+
+// DeleteHistory deletes the messages saved in the history of the connection.
+func (s *Service) DeleteHistory(connectionID string) error {
+	if connectionID == "" {
+		return fmt.Errorf("connectionID is mandatory")
+	}
+
+	records, err := s.queryRecords(connectionID, func(*Record) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err := s.store.Delete(fmt.Sprintf(historyKey, record.ConnectionID, record.MessageID)); err != nil {
+			return fmt.Errorf("delete message %s: %w", record.MessageID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) saveMessage(msg service.DIDCommMsg, connectionID, direction string) error {
+	message := &Message{}
+
+	if err := msg.Decode(message); err != nil {
+		return fmt.Errorf("basic message unmarshal: %w", err)
+	}
+
+	record := &Record{
+		MessageID:    msg.ID(),
+		ConnectionID: connectionID,
+		Direction:    direction,
+		Content:      message.Content,
+		SentTime:     message.SentTime,
+		Time:         time.Now().UTC(),
+	}
+
+	if message.L10n != nil {
+		record.Locale = message.L10n.Locale
+	}
+
+	src, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+
+	if err := s.store.Put(fmt.Sprintf(historyKey, connectionID, record.MessageID), src); err != nil {
+		return fmt.Errorf("save message: %w", err)
+	}
+
+	return nil
+}
+
+// queryRecords returns the records of the connection accepted by the filter,
+// the records of all the connections if the connectionID is empty.
+func (s *Service) queryRecords(connectionID string, filter func(*Record) bool) ([]*Record, error) {
+	prefix := "history_"
+	if connectionID != "" {
+		prefix = fmt.Sprintf(historyKey, connectionID, "")
+	}
+
+	itr := s.store.Iterator(prefix, prefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	var records []*Record
+
+	for itr.Next() {
+		record := &Record{}
+		if err := json.Unmarshal(itr.Value(), record); err != nil {
+			return nil, fmt.Errorf("unmarshal record: %w", err)
+		}
+
+		if filter(record) {
+			records = append(records, record)
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("iterate history: %w", err)
+	}
+
+	return records, nil
+}
+
+func (p *QueryParams) matches(record *Record) bool {
+	return (p.ConnectionID == "" || p.ConnectionID == record.ConnectionID) &&
+		(p.Direction == "" || p.Direction == record.Direction)
+}
+
+// less reports whether the position a is before b in the sort order.
+func (p *QueryParams) less(a, b *cursor) bool {
+	if a.Time == b.Time && a.MessageID == b.MessageID {
+		return false
+	}
+
+	before := a.Time < b.Time || (a.Time == b.Time && a.MessageID < b.MessageID)
+
+	return before != p.Descending
+}
+
+// position returns the position of the record in the sort order.
+func position(record *Record) *cursor {
+	return &cursor{Time: record.Time.UnixNano(), MessageID: record.MessageID}
+}
+
+func encodeCursor(c *cursor) string {
+	// nolint: errcheck
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	c := &cursor{}
+
+	if err = json.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return c, nil
+}
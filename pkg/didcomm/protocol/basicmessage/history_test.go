@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func TestService_History(t *testing.T) {
+	const count = 6
+
+	received := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	newService := func(t *testing.T) *Service {
+		t.Helper()
+
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		for i := 0; i < count; i++ {
+			record := &Record{
+				MessageID:    fmt.Sprint(i),
+				ConnectionID: "conn-1",
+				Direction:    DirectionReceived,
+				Content:      fmt.Sprintf("message %d", i),
+				Time:         received.Add(time.Duration(count-i) * time.Minute),
+			}
+
+			if i%2 == 1 {
+				record.ConnectionID = "conn-2"
+				record.Direction = DirectionSent
+			}
+
+			src, err := json.Marshal(record)
+			require.NoError(t, err)
+
+			require.NoError(t, svc.store.Put(fmt.Sprintf(historyKey, record.ConnectionID, record.MessageID), src))
+		}
+
+		return svc
+	}
+
+	ids := func(records []*Record) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.MessageID)
+		}
+
+		return result
+	}
+
+	t.Run("test history of all connections", func(t *testing.T) {
+		result, err := newService(t).History(&QueryParams{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"5", "4", "3", "2", "1", "0"}, ids(result.Records))
+		require.Empty(t, result.NextCursor)
+	})
+
+	t.Run("test history with filters", func(t *testing.T) {
+		svc := newService(t)
+
+		result, err := svc.History(&QueryParams{ConnectionID: "conn-1"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"4", "2", "0"}, ids(result.Records))
+
+		result, err = svc.History(&QueryParams{Direction: DirectionSent, Descending: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "3", "5"}, ids(result.Records))
+
+		result, err = svc.History(&QueryParams{ConnectionID: "conn-1", Direction: DirectionSent})
+		require.NoError(t, err)
+		require.Empty(t, result.Records)
+	})
+
+	t.Run("test history pages", func(t *testing.T) {
+		svc := newService(t)
+
+		params := &QueryParams{Limit: 4}
+
+		result, err := svc.History(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"5", "4", "3", "2"}, ids(result.Records))
+		require.NotEmpty(t, result.NextCursor)
+
+		params.Cursor = result.NextCursor
+
+		result, err = svc.History(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "0"}, ids(result.Records))
+		require.Empty(t, result.NextCursor)
+
+		params = &QueryParams{ConnectionID: "conn-2", Descending: true, Limit: 2}
+
+		result, err = svc.History(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "3"}, ids(result.Records))
+
+		params.Cursor = result.NextCursor
+
+		result, err = svc.History(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"5"}, ids(result.Records))
+		require.Empty(t, result.NextCursor)
+	})
+
+	t.Run("test history with invalid parameters", func(t *testing.T) {
+		svc := newService(t)
+
+		_, err := svc.History(&QueryParams{Direction: "forwarded"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported direction")
+
+		_, err = svc.History(&QueryParams{Limit: -1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid limit")
+
+		_, err = svc.History(&QueryParams{Cursor: "!!!"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid cursor")
+
+		_, err = svc.History(&QueryParams{Cursor: "bm90LWpzb24"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid cursor")
+	})
+
+	t.Run("test history failure", func(t *testing.T) {
+		svc := newService(t)
+		require.NoError(t, svc.store.Put(fmt.Sprintf(historyKey, "conn-1", "abc"), []byte("-----")))
+
+		result, err := svc.History(&QueryParams{})
+		require.Error(t, err)
+		require.Nil(t, result)
+
+		svc.store = &mockstore.MockStore{ErrItr: errors.New("iterator error")}
+
+		_, err = svc.History(&QueryParams{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator error")
+	})
+
+	t.Run("test delete history", func(t *testing.T) {
+		svc := newService(t)
+
+		require.NoError(t, svc.DeleteHistory("conn-1"))
+
+		result, err := svc.History(&QueryParams{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"5", "3", "1"}, ids(result.Records))
+
+		require.EqualError(t, svc.DeleteHistory(""), "connectionID is mandatory")
+
+		svc.store = &mockstore.MockStore{
+			Store:     svc.store.(*mockstore.MockStore).Store,
+			ErrDelete: errors.New("delete error"),
+		}
+
+		err = svc.DeleteHistory("conn-2")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete error")
+	})
+}
@@ -0,0 +1,26 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"time"
+)
+
+// Message is the basic message, the content is a human readable text.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0095-basic-message#reference
+type Message struct {
+	Type     string    `json:"@type,omitempty"`
+	ID       string    `json:"@id,omitempty"`
+	L10n     *L10n     `json:"~l10n,omitempty"`
+	SentTime time.Time `json:"sent_time"`
+	Content  string    `json:"content"`
+}
+
+// L10n is the localization decorator, it holds the locale of the content.
+type L10n struct {
+	Locale string `json:"locale"`
+}
@@ -0,0 +1,261 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+var logger = log.New("aries-framework/basicmessage/service")
+
+const (
+	// BasicMessage defines the protocol name.
+	BasicMessage = "basicmessage"
+	// PIURI is the basic message protocol identifier URI.
+	PIURI = "https://didcomm.org/basicmessage/1.0"
+	// MessageMsgType defines the protocol message type.
+	MessageMsgType = PIURI + "/message"
+)
+
+// States of the protocol reported by the message events.
+const (
+	// StateMessageReceived the message was received and saved in the history of the connection.
+	StateMessageReceived = "message-received"
+	// StateMessageSent the message was sent and saved in the history of the connection.
+	StateMessageSent = "message-sent"
+)
+
+// ErrConnectionNotFound connection not found error.
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// Provider contains dependencies for the basic message protocol and is typically created by using aries.Context().
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	MessageServiceProvider() api.MessageServiceProvider
+}
+
+type connections interface {
+	GetConnectionIDByDIDs(string, string) (string, error)
+	GetConnectionRecord(string) (*connection.Record, error)
+}
+
+type eventProps struct {
+	piid         string
+	connectionID string
+}
+
+func (e *eventProps) All() map[string]interface{} {
+	return map[string]interface{}{
+		"piid":         e.piid,
+		"connectionID": e.connectionID,
+	}
+}
+
+// Service for the basic message protocol, the messages sent and received are saved in the history
+// of their connection.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0095-basic-message
+type Service struct {
+	service.Action
+	service.Message
+	messenger        service.Messenger
+	store            storage.Store
+	connectionLookup connections
+	msgSvcProvider   api.MessageServiceProvider
+}
+
+// New returns the basic message service.
+func New(p Provider) (*Service, error) {
+	store, err := p.StorageProvider().OpenStore(BasicMessage)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	connectionLookup, err := connection.NewLookup(p)
+	if err != nil {
+		return nil, fmt.Errorf("new connection lookup: %w", err)
+	}
+
+	return &Service{
+		messenger:        p.Messenger(),
+		store:            store,
+		connectionLookup: connectionLookup,
+		msgSvcProvider:   p.MessageServiceProvider(),
+	}, nil
+}
+
+// HandleInbound saves the received message in the history of the connection and triggers a message event.
+// The message is then passed to the message service registered for basic messages (if any),
+// e.g the one registered by the messaging client.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	logger.WithFields(service.LogFields(msg)).Debugf("service.HandleInbound() myDID=%s theirDID=%s", myDID, theirDID)
+
+	if !s.Accept(msg.Type()) {
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	connectionID, err := s.connectionLookup.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("connectionID lookup using DIDs: %w", err)
+	}
+
+	if err := s.saveMessage(msg, connectionID, DirectionReceived); err != nil {
+		return "", err
+	}
+
+	s.sendMsgEvent(msg, StateMessageReceived, connectionID)
+
+	if err := s.forward(msg, myDID, theirDID); err != nil {
+		return "", err
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound sends the basic message and saves it in the history of the connection.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if !s.Accept(msg.Type()) {
+		return "", fmt.Errorf("invalid or unsupported outbound message type %s", msg.Type())
+	}
+
+	connectionID, err := s.connectionLookup.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("connectionID lookup using DIDs: %w", err)
+	}
+
+	msgMap := msg.Clone()
+
+	if err := s.send(msgMap, connectionID, myDID, theirDID); err != nil {
+		return "", err
+	}
+
+	return msgMap.ID(), nil
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == MessageMsgType
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return BasicMessage
+}
+
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{PIURI}
+}
+
+// SendMessage sends the message to the agent on the other end of the connection.
+// The ID and the sent time of the message are set when empty.
+func (s *Service) SendMessage(connectionID string, msg *Message) (string, error) {
+	record, err := s.getConnection(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("get connection: %w", err)
+	}
+
+	msg.Type = MessageMsgType
+
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+
+	if msg.SentTime.IsZero() {
+		msg.SentTime = time.Now().UTC()
+	}
+
+	msgMap := service.NewDIDCommMsgMap(msg)
+
+	if err := s.send(msgMap, connectionID, record.MyDID, record.TheirDID); err != nil {
+		return "", err
+	}
+
+	return msgMap.ID(), nil
+}
+
+func (s *Service) send(msg service.DIDCommMsgMap, connectionID, myDID, theirDID string) error {
+	if err := s.messenger.Send(msg, myDID, theirDID); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+
+	if err := s.saveMessage(msg, connectionID, DirectionSent); err != nil {
+		return err
+	}
+
+	s.sendMsgEvent(msg, StateMessageSent, connectionID)
+
+	return nil
+}
+
+// forward passes the message to the first message service accepting it, as the dispatcher did
+// before the protocol service was registered.
+func (s *Service) forward(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if s.msgSvcProvider == nil {
+		return nil
+	}
+
+	header := struct {
+		Purpose []string `json:"~purpose"`
+	}{}
+
+	if err := msg.Decode(&header); err != nil {
+		return fmt.Errorf("decode purpose: %w", err)
+	}
+
+	for _, svc := range s.msgSvcProvider.Services() {
+		if !svc.Accept(msg.Type(), header.Purpose) {
+			continue
+		}
+
+		if _, err := svc.HandleInbound(msg, myDID, theirDID); err != nil {
+			return fmt.Errorf("message service %s: %w", svc.Name(), err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (s *Service) sendMsgEvent(msg service.DIDCommMsg, stateID, connectionID string) {
+	stateMsg := service.StateMsg{
+		ProtocolName: BasicMessage,
+		Type:         service.PostState,
+		Msg:          msg,
+		StateID:      stateID,
+		Properties:   &eventProps{piid: msg.ID(), connectionID: connectionID},
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	record, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrConnectionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get connection record: %w", err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,358 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/generic"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+	connID   = "conn-id"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+		require.Equal(t, BasicMessage, svc.Name())
+		require.Equal(t, []string{PIURI}, svc.Protocols())
+	})
+
+	t.Run("test new service - open store error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.storageProvider = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open store")
+	})
+
+	t.Run("test new service - connection lookup error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.protocolStateStorage = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	svc, err := New(newProvider(nil))
+	require.NoError(t, err)
+
+	require.True(t, svc.Accept(MessageMsgType))
+	require.False(t, svc.Accept("unsupported"))
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		var forwarded service.DIDCommMsg
+
+		require.NoError(t, prov.msgSvcProvider.Register(&generic.MockMessageSvc{
+			NameVal: "basic",
+			AcceptFunc: func(msgType string, _ []string) bool {
+				return msgType == MessageMsgType
+			},
+			HandleFunc: func(msg *service.DIDCommMsg) (string, error) {
+				forwarded = *msg
+
+				return "", nil
+			},
+		}))
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		msgID, err := svc.HandleInbound(service.NewDIDCommMsgMap(sampleMessage()), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "message-id", msgID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, BasicMessage, event.ProtocolName)
+			require.Equal(t, service.PostState, event.Type)
+			require.Equal(t, StateMessageReceived, event.StateID)
+			require.Equal(t, connID, event.Properties.All()["connectionID"])
+			require.Equal(t, "message-id", event.Properties.All()["piid"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		require.NotNil(t, forwarded)
+		require.Equal(t, "message-id", forwarded.ID())
+
+		result, err := svc.History(&QueryParams{ConnectionID: connID})
+		require.NoError(t, err)
+		require.Len(t, result.Records, 1)
+		require.Equal(t, "message-id", result.Records[0].MessageID)
+		require.Equal(t, DirectionReceived, result.Records[0].Direction)
+		require.Equal(t, "Your hovercraft is full of eels.", result.Records[0].Content)
+		require.Equal(t, "en", result.Records[0].Locale)
+		require.Equal(t, sampleMessage().SentTime, result.Records[0].SentTime)
+		require.False(t, result.Records[0].Time.IsZero())
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Message{Type: "unsupported"}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported message type")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(sampleMessage()), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connectionID lookup using DIDs")
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type":   MessageMsgType,
+			"@id":     "message-id",
+			"content": []string{"invalid"},
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "basic message unmarshal")
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		svc.store = &mockstore.MockStore{Store: make(map[string][]byte), ErrPut: errors.New("put error")}
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(sampleMessage()), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+	})
+
+	t.Run("message service error", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov)
+
+		require.NoError(t, prov.msgSvcProvider.Register(&generic.MockMessageSvc{
+			NameVal: "basic",
+			HandleFunc: func(*service.DIDCommMsg) (string, error) {
+				return "", errors.New("handle error")
+			},
+		}))
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(sampleMessage()), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "message service basic: handle error")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		msgID, err := svc.HandleOutbound(service.NewDIDCommMsgMap(sampleMessage()), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "message-id", msgID)
+
+		result, err := svc.History(&QueryParams{ConnectionID: connID, Direction: DirectionSent})
+		require.NoError(t, err)
+		require.Len(t, result.Records, 1)
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&Message{Type: "unsupported"}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported outbound message type")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(sampleMessage()), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connectionID lookup using DIDs")
+	})
+}
+
+func TestService_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				message := &Message{}
+				require.NoError(t, msg.Decode(message))
+				require.Equal(t, MessageMsgType, message.Type)
+				require.NotEmpty(t, message.ID)
+				require.False(t, message.SentTime.IsZero())
+				require.Equal(t, "fr", message.L10n.Locale)
+
+				return nil
+			})
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		msgID, err := svc.SendMessage(connID, &Message{Content: "Bonjour", L10n: &L10n{Locale: "fr"}})
+		require.NoError(t, err)
+		require.NotEmpty(t, msgID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, StateMessageSent, event.StateID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		result, err := svc.History(&QueryParams{ConnectionID: connID})
+		require.NoError(t, err)
+		require.Len(t, result.Records, 1)
+		require.Equal(t, msgID, result.Records[0].MessageID)
+		require.Equal(t, DirectionSent, result.Records[0].Direction)
+		require.Equal(t, "Bonjour", result.Records[0].Content)
+		require.Equal(t, "fr", result.Records[0].Locale)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.SendMessage(connID, &Message{Content: "Hello"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send error")
+
+		result, err := svc.History(&QueryParams{ConnectionID: connID})
+		require.NoError(t, err)
+		require.Empty(t, result.Records)
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.SendMessage(connID, &Message{Content: "Hello"})
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+}
+
+func sampleMessage() *Message {
+	return &Message{
+		Type:     MessageMsgType,
+		ID:       "message-id",
+		L10n:     &L10n{Locale: "en"},
+		SentTime: time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+		Content:  "Your hovercraft is full of eels.",
+	}
+}
+
+func saveConnection(t *testing.T, prov *provider) {
+	t.Helper()
+
+	r, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connID, MyDID: MYDID, TheirDID: THEIRDID, State: "completed",
+	}))
+}
+
+func newProvider(messenger service.Messenger) *provider {
+	return &provider{
+		messenger:            messenger,
+		storageProvider:      mockstore.NewMockStoreProvider(),
+		protocolStateStorage: mockstore.NewMockStoreProvider(),
+		msgSvcProvider:       msghandler.NewMockMsgServiceProvider(),
+	}
+}
+
+type provider struct {
+	messenger            service.Messenger
+	storageProvider      storage.Provider
+	protocolStateStorage storage.Provider
+	msgSvcProvider       *msghandler.MockMsgSvcProvider
+}
+
+func (p *provider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+func (p *provider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *provider) ProtocolStateStorageProvider() storage.Provider {
+	return p.protocolStateStorage
+}
+
+func (p *provider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.msgSvcProvider
+}
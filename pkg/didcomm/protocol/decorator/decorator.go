@@ -49,6 +49,17 @@ type ReturnRoute struct {
 	Value string `json:"~return_route,omitempty"`
 }
 
+// Service is the ~service decorator which allows the agents to exchange messages without a connection.
+// To find out more please visit https://github.com/hyperledger/aries-rfcs/tree/master/features/0056-service-decorator
+type Service struct {
+	// RecipientKeys are the keys the message for the agent is encrypted for.
+	RecipientKeys []string `json:"recipientKeys"`
+	// RoutingKeys are the keys of the mediators the message for the agent is forwarded through.
+	RoutingKeys []string `json:"routingKeys,omitempty"`
+	// ServiceEndpoint is the endpoint of the agent.
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
 // Attachment is intended to provide the possibility to include files, links or even JSON payload to the message.
 // To find out more please visit https://github.com/hyperledger/aries-rfcs/tree/master/concepts/0017-attachments
 type Attachment struct {
@@ -92,6 +103,18 @@ type AttachmentData struct {
 	// JSON is a directly embedded JSON data, when representing content inline instead of via links,
 	// and when the content is natively conveyable as JSON. Optional.
 	JSON interface{} `json:"json,omitempty"`
+	// JWS is the detached signature of the base64 content. Optional.
+	JWS *AttachmentJWS `json:"jws,omitempty"`
+}
+
+// AttachmentJWS is the detached JWS of the attachment content (the base64url encoded content is the JWS payload).
+type AttachmentJWS struct {
+	// Header is the unprotected JWS header, usually the ID of the signing key.
+	Header map[string]interface{} `json:"header,omitempty"`
+	// Protected is the base64url encoded protected JWS header.
+	Protected string `json:"protected,omitempty"`
+	// Signature is the base64url encoded signature.
+	Signature string `json:"signature"`
 }
 
 // Fetch this attachment's contents.
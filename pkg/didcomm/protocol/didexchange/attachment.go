@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+const (
+	jwsAlgEdDSA  = "EdDSA"
+	jwkKeyType   = "OKP"
+	jwkCurve     = "Ed25519"
+	jsonMimeType = "application/json"
+)
+
+var errUnsignedAttachment = errors.New("the DID doc attachment is not signed")
+
+// jwsHeader is the protected header of the DID doc attachment signature.
+type jwsHeader struct {
+	Alg string  `json:"alg"`
+	KID string  `json:"kid,omitempty"`
+	JWK *jwsJWK `json:"jwk"`
+}
+
+type jwsJWK struct {
+	KTY string `json:"kty"`
+	CRV string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// didDocAttachment returns the DID doc attachment signed with the Ed25519 key (base58) of the agent.
+func (ctx *context) didDocAttachment(doc *did.Doc, signingKey string) (*decorator.Attachment, error) {
+	return SignDIDDocAttachment(ctx.kms, ctx.crypto, doc, signingKey)
+}
+
+// SignDIDDocAttachment returns the DID doc attachment signed with the Ed25519 key (base58) of the agent.
+// The signature is the detached JWS of the base64url encoded DID doc (Aries RFC 0017 signed attachment).
+func SignDIDDocAttachment(km kms.KeyManager, c crypto.Crypto, doc *did.Doc,
+	signingKey string) (*decorator.Attachment, error) {
+	raw, err := doc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal DID doc: %w", err)
+	}
+
+	pubKey := base58.Decode(signingKey)
+
+	kid, err := localkms.CreateKID(pubKey, kms.ED25519Type)
+	if err != nil {
+		return nil, fmt.Errorf("create KID from public key: %w", err)
+	}
+
+	kh, err := km.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("get key handle: %w", err)
+	}
+
+	// the signing key is resolved above, CreateDIDKey has no error and its second value is the did:key key ID
+	didKey, _ := fingerprint.CreateDIDKey(pubKey)
+
+	protected, err := json.Marshal(&jwsHeader{
+		Alg: jwsAlgEdDSA,
+		KID: didKey,
+		JWK: &jwsJWK{KTY: jwkKeyType, CRV: jwkCurve, X: base64.RawURLEncoding.EncodeToString(pubKey)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal JWS header: %w", err)
+	}
+
+	encodedProtected := base64.RawURLEncoding.EncodeToString(protected)
+
+	signature, err := c.Sign(signingInput(encodedProtected, raw), kh)
+	if err != nil {
+		return nil, fmt.Errorf("sign DID doc: %w", err)
+	}
+
+	return &decorator.Attachment{
+		ID:       uuid.New().String(),
+		MimeType: jsonMimeType,
+		Data: decorator.AttachmentData{
+			Base64: base64.StdEncoding.EncodeToString(raw),
+			JWS: &decorator.AttachmentJWS{
+				Header:    map[string]interface{}{"kid": didKey},
+				Protected: encodedProtected,
+				Signature: base64.RawURLEncoding.EncodeToString(signature),
+			},
+		},
+	}, nil
+}
+
+// VerifyDIDDocAttachment verifies the signature of the DID doc attachment and returns the DID doc.
+// The attachment must be signed with one of the given keys (base58), e.g the invitation key. If no keys
+// are given, the attachment must be signed with a key of the attached DID doc.
+func VerifyDIDDocAttachment(attachment *decorator.Attachment, keys ...string) (*did.Doc, error) {
+	raw, err := attachment.Data.Fetch()
+	if err != nil {
+		return nil, fmt.Errorf("fetch DID doc: %w", err)
+	}
+
+	if attachment.Data.JWS == nil {
+		return nil, errUnsignedAttachment
+	}
+
+	protected, err := base64.RawURLEncoding.DecodeString(attachment.Data.JWS.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWS header: %w", err)
+	}
+
+	header := &jwsHeader{}
+	if err = json.Unmarshal(protected, header); err != nil {
+		return nil, fmt.Errorf("unmarshal JWS header: %w", err)
+	}
+
+	if header.Alg != jwsAlgEdDSA || header.JWK == nil {
+		return nil, fmt.Errorf("unsupported JWS algorithm %s", header.Alg)
+	}
+
+	pubKey, err := base64.RawURLEncoding.DecodeString(header.JWK.X)
+	if err != nil || len(pubKey) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid JWS public key")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(attachment.Data.JWS.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWS signature: %w", err)
+	}
+
+	if !ed25519.Verify(pubKey, signingInput(attachment.Data.JWS.Protected, raw), signature) {
+		return nil, errors.New("invalid DID doc signature")
+	}
+
+	doc, err := parseDIDDoc(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 && !docHasKey(doc, pubKey) {
+		return nil, errors.New("the DID doc was not signed with its key")
+	}
+
+	if len(keys) != 0 && !containsKey(keys, pubKey) {
+		return nil, errors.New("the DID doc was not signed with the expected key")
+	}
+
+	return doc, nil
+}
+
+func parseDIDDoc(raw []byte) (*did.Doc, error) {
+	doc, err := did.ParseDocument(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse DID doc: %w", err)
+	}
+
+	return doc, nil
+}
+
+func signingInput(protected string, payload []byte) []byte {
+	return []byte(protected + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
+
+func containsKey(keys []string, pubKey []byte) bool {
+	for _, key := range keys {
+		if bytes.Equal(base58.Decode(key), pubKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func docHasKey(doc *did.Doc, pubKey []byte) bool {
+	for i := range doc.VerificationMethod {
+		if bytes.Equal(doc.VerificationMethod[i].Value, pubKey) {
+			return true
+		}
+	}
+
+	svc, ok := did.LookupService(doc, didCommServiceType)
+
+	return ok && containsKey(svc.RecipientKeys, pubKey)
+}
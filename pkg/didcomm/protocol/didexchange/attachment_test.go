@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+func TestDIDDocAttachment(t *testing.T) {
+	prov := getProvider(t)
+	ctx := getContext(t, &prov)
+	pubKey := newED25519Key(t, ctx.kms)
+	doc := createDIDDocWithKey(pubKey)
+
+	t.Run("verifies the did doc signed with the key of the did doc", func(t *testing.T) {
+		attachment, err := ctx.didDocAttachment(doc, pubKey)
+		require.NoError(t, err)
+		require.Equal(t, jsonMimeType, attachment.MimeType)
+		require.NotNil(t, attachment.Data.JWS)
+
+		result, err := VerifyDIDDocAttachment(attachment)
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, result.ID)
+	})
+	t.Run("verifies the did doc signed with the invitation key", func(t *testing.T) {
+		invitationKey := newED25519Key(t, ctx.kms)
+
+		attachment, err := ctx.didDocAttachment(doc, invitationKey)
+		require.NoError(t, err)
+
+		result, err := VerifyDIDDocAttachment(attachment, invitationKey)
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, result.ID)
+
+		_, err = VerifyDIDDocAttachment(attachment, pubKey)
+		require.EqualError(t, err, "the DID doc was not signed with the expected key")
+
+		_, err = VerifyDIDDocAttachment(attachment)
+		require.EqualError(t, err, "the DID doc was not signed with its key")
+	})
+	t.Run("rejects the unsigned did doc", func(t *testing.T) {
+		attachment, err := ctx.didDocAttachment(doc, pubKey)
+		require.NoError(t, err)
+
+		attachment.Data.JWS = nil
+
+		_, err = VerifyDIDDocAttachment(attachment)
+		require.True(t, errors.Is(err, errUnsignedAttachment))
+
+		_, err = VerifyDIDDocAttachment(attachment, pubKey)
+		require.True(t, errors.Is(err, errUnsignedAttachment))
+	})
+	t.Run("rejects the tampered did doc", func(t *testing.T) {
+		attachment, err := ctx.didDocAttachment(doc, pubKey)
+		require.NoError(t, err)
+
+		raw, err := createDIDDocWithKey(newED25519Key(t, ctx.kms)).JSONBytes()
+		require.NoError(t, err)
+
+		attachment.Data.Base64 = base64.StdEncoding.EncodeToString(raw)
+
+		_, err = VerifyDIDDocAttachment(attachment)
+		require.EqualError(t, err, "invalid DID doc signature")
+	})
+	t.Run("rejects the unsupported signature", func(t *testing.T) {
+		attachment, err := ctx.didDocAttachment(doc, pubKey)
+		require.NoError(t, err)
+
+		attachment.Data.JWS.Protected = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256"}`))
+
+		_, err = VerifyDIDDocAttachment(attachment)
+		require.EqualError(t, err, "unsupported JWS algorithm ES256")
+	})
+	t.Run("rejects the attachment without content", func(t *testing.T) {
+		_, err := VerifyDIDDocAttachment(&decorator.Attachment{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch DID doc")
+	})
+	t.Run("fails to sign with the unknown key", func(t *testing.T) {
+		unknown, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = ctx.didDocAttachment(doc, base58.Encode(unknown))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get key handle")
+	})
+}
@@ -69,9 +69,16 @@ type Invitation struct {
 // Request defines a2a DID exchange request
 // https://github.com/hyperledger/aries-rfcs/tree/master/features/0023-did-exchange#1-exchange-request
 type Request struct {
-	Type       string            `json:"@type,omitempty"`
-	ID         string            `json:"@id,omitempty"`
-	Label      string            `json:"label,omitempty"`
+	Type     string `json:"@type,omitempty"`
+	ID       string `json:"@id,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Goal     string `json:"goal,omitempty"`
+	GoalCode string `json:"goal_code,omitempty"`
+	// DID of the requester, the DID doc is resolved if it is not attached (e.g public DID).
+	DID string `json:"did,omitempty"`
+	// DocAttach is the DID doc of the requester signed with the key of the DID doc.
+	DocAttach *decorator.Attachment `json:"did_doc~attach,omitempty"`
+	// Connection is the RFC 0160 (Connection Protocol) payload.
 	Connection *Connection       `json:"connection,omitempty"`
 	Thread     *decorator.Thread `json:"~thread,omitempty"`
 }
@@ -79,12 +86,35 @@ type Request struct {
 // Response defines a2a DID exchange response
 // https://github.com/hyperledger/aries-rfcs/tree/master/features/0023-did-exchange#2-exchange-response
 type Response struct {
-	Type                string               `json:"@type,omitempty"`
-	ID                  string               `json:"@id,omitempty"`
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+	// DID of the responder, the DID doc is resolved if it is not attached (e.g public DID).
+	DID string `json:"did,omitempty"`
+	// DocAttach is the DID doc of the responder signed with the key of the invitation.
+	DocAttach *decorator.Attachment `json:"did_doc~attach,omitempty"`
+	// ConnectionSignature is the RFC 0160 (Connection Protocol) payload.
 	ConnectionSignature *ConnectionSignature `json:"connection~sig,omitempty"`
 	Thread              *decorator.Thread    `json:"~thread,omitempty"`
 }
 
+// Complete defines a2a DID exchange complete message
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0023-did-exchange#3-exchange-complete
+type Complete struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// ProblemReport defines a2a DID exchange problem report
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0023-did-exchange#problem-reports
+type ProblemReport struct {
+	Type        string            `json:"@type,omitempty"`
+	ID          string            `json:"@id,omitempty"`
+	ProblemCode string            `json:"problem-code,omitempty"`
+	Explain     string            `json:"explain,omitempty"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+}
+
 // ConnectionSignature connection signature.
 type ConnectionSignature struct {
 	Type       string `json:"@type,omitempty"`
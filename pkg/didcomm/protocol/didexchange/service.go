@@ -42,11 +42,23 @@ const (
 	ResponseMsgType = PIURI + "/response"
 	// AckMsgType defines the did-exchange ack message type.
 	AckMsgType = PIURI + "/ack"
+	// CompleteMsgType defines the did-exchange complete message type.
+	CompleteMsgType = PIURI + "/complete"
+	// ProblemReportMsgType defines the did-exchange problem report message type.
+	ProblemReportMsgType = PIURI + "/problem_report"
 	// oobMsgType is the internal message type for the oob invitation that the didexchange service receives.
 	oobMsgType             = "oob-invitation"
 	routerConnsMetadataKey = "routerConnections"
 )
 
+// problem codes of the did-exchange problem report.
+const (
+	codeRequestNotAccepted      = "request_not_accepted"
+	codeRequestProcessingError  = "request_processing_error"
+	codeResponseNotAccepted     = "response_not_accepted"
+	codeResponseProcessingError = "response_processing_error"
+)
+
 // message type to store data for eventing. This is retrieved during callback.
 type message struct {
 	Msg           service.DIDCommMsgMap
@@ -60,6 +72,19 @@ type message struct {
 	err error
 }
 
+// logFields returns fields which allow to correlate log lines of the connection.
+func (m *message) logFields(stateName string) log.Fields {
+	fields := service.LogFields(m.Msg)
+	fields[log.FieldProtocol] = DIDExchange
+	fields[log.FieldState] = stateName
+
+	if m.ConnRecord != nil {
+		fields[log.FieldConnectionID] = m.ConnRecord.ConnectionID
+	}
+
+	return fields
+}
+
 // provider contains dependencies for the DID exchange protocol and is typically created by using aries.Context().
 type provider interface {
 	OutboundDispatcher() dispatcher.Outbound
@@ -94,6 +119,20 @@ type context struct {
 	connectionStore    *connectionStore
 	vdRegistry         vdrapi.Registry
 	routeSvc           mediator.ProtocolService
+	legacyConnections  bool
+}
+
+// ServiceOption configures the service.
+type ServiceOption func(s *Service)
+
+// WithLegacyConnections sends the RFC 0160 (Connection Protocol) payloads: the connection of the request,
+// the connection~sig of the response and the ack instead of the complete message.
+// The responses signed with the connection~sig are only accepted with the option, the response must carry
+// the signed did_doc~attach otherwise.
+func WithLegacyConnections() ServiceOption {
+	return func(s *Service) {
+		s.ctx.legacyConnections = true
+	}
 }
 
 // opts are used to provide client properties to DID Exchange service.
@@ -109,7 +148,7 @@ type opts interface {
 }
 
 // New return didexchange service.
-func New(prov provider) (*Service, error) {
+func New(prov provider, serviceOpts ...ServiceOption) (*Service, error) {
 	connRecorder, err := newConnectionStore(prov)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize connection store : %w", err)
@@ -141,6 +180,10 @@ func New(prov provider) (*Service, error) {
 		connectionStore: connRecorder,
 	}
 
+	for _, opt := range serviceOpts {
+		opt(svc)
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
@@ -162,7 +205,7 @@ func retrievingRouterConnections(msg service.DIDCommMsg) []string {
 }
 
 // HandleInbound handles inbound didexchange messages.
-func (s *Service) HandleInbound(msg service.DIDCommMsg, _, _ string) (string, error) {
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
 	logger.Debugf("receive inbound message : %s", msg)
 
 	// fetch the thread id
@@ -171,6 +214,10 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, _, _ string) (string, er
 		return "", err
 	}
 
+	if msg.Type() == ProblemReportMsgType {
+		return s.handleProblemReport(msg, thID, myDID, theirDID)
+	}
+
 	// valid state transition and get the next state
 	next, err := s.nextState(msg.Type(), thID)
 	if err != nil {
@@ -197,6 +244,14 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, _, _ string) (string, er
 				logutil.CreateKeyValueString("msgType", msg.Msg.Type()),
 				logutil.CreateKeyValueString("msgID", msg.Msg.ID()),
 				logutil.CreateKeyValueString("connectionID", msg.ConnRecord.ConnectionID))
+
+			if code := problemCode(msg.Msg.Type(), false); code != "" {
+				if errAbandon := s.abandon(msg.ThreadID, msg.Msg, code, err); errAbandon != nil {
+					logger.Errorf("process message : %s", errAbandon)
+				}
+			}
+
+			return
 		}
 
 		logutil.LogDebug(logger, DIDExchange, "processMessage", "success",
@@ -218,6 +273,11 @@ func (s *Service) Name() string {
 	return DIDExchange
 }
 
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{PIURI}
+}
+
 func findNamespace(msgType string) string {
 	namespace := theirNSPrefix
 	if msgType == InvitationMsgType || msgType == ResponseMsgType || msgType == oobMsgType {
@@ -232,7 +292,9 @@ func (s *Service) Accept(msgType string) bool {
 	return msgType == InvitationMsgType ||
 		msgType == RequestMsgType ||
 		msgType == ResponseMsgType ||
-		msgType == AckMsgType
+		msgType == AckMsgType ||
+		msgType == CompleteMsgType ||
+		msgType == ProblemReportMsgType
 }
 
 // HandleOutbound handles outbound didexchange messages.
@@ -307,7 +369,7 @@ func (s *Service) handle(msg *message, aEvent chan<- service.DIDCommAction) erro
 		}
 
 		connectionRecord.State = next.Name()
-		logger.Debugf("finished execute state: %s", next.Name())
+		logger.WithFields(msg.logFields(next.Name())).Debugf("finished execute state: %s", next.Name())
 
 		if err = s.update(msg.Msg.Type(), connectionRecord); err != nil {
 			return fmt.Errorf("failed to persist state %s %w", next.Name(), err)
@@ -425,6 +487,9 @@ func (s *Service) sendMsgEvents(msg *service.StateMsg) {
 // startInternalListener listens to messages in gochannel for callback messages from clients.
 func (s *Service) startInternalListener() {
 	for msg := range s.callbackChannel {
+		// the error was passed to the Stop function
+		rejected := msg.err != nil
+
 		// TODO https://github.com/hyperledger/aries-framework-go/issues/242 - retry logic
 		// if no error - do handle
 		if msg.err == nil {
@@ -436,7 +501,7 @@ func (s *Service) startInternalListener() {
 			continue
 		}
 
-		if err := s.abandon(msg.ThreadID, msg.Msg, msg.err); err != nil {
+		if err := s.abandon(msg.ThreadID, msg.Msg, problemCode(msg.Msg.Type(), rejected), msg.err); err != nil {
 			logger.Errorf("process callback : %s", err)
 		}
 	}
@@ -523,7 +588,8 @@ func (s *Service) getEventProtocolStateData(connectionID string) (*message, erro
 }
 
 // abandon updates the state to abandoned and trigger failure event.
-func (s *Service) abandon(thID string, msg service.DIDCommMsg, processErr error) error {
+// The problem report with the given code is sent to the other agent (nothing is sent if the code is empty).
+func (s *Service) abandon(thID string, msg service.DIDCommMsg, code string, processErr error) error {
 	// update the state to abandoned
 	nsThID, err := connection.CreateNamespaceKey(findNamespace(msg.Type()), thID)
 	if err != nil {
@@ -542,6 +608,13 @@ func (s *Service) abandon(thID string, msg service.DIDCommMsg, processErr error)
 		return fmt.Errorf("unable to update the state to abandoned: %w", err)
 	}
 
+	if code != "" {
+		// the problem report is best effort, the connection is abandoned anyway
+		if err = s.sendProblemReport(connRec, msg, code, processErr); err != nil {
+			logger.Warnf("failed to send the problem report: %s", err)
+		}
+	}
+
 	// send the message event
 	s.sendMsgEvents(&service.StateMsg{
 		ProtocolName: DIDExchange,
@@ -609,7 +682,7 @@ func (s *Service) connectionRecord(msg service.DIDCommMsg) (*connection.Record,
 		return s.requestMsgRecord(msg)
 	case ResponseMsgType:
 		return s.responseMsgRecord(msg)
-	case AckMsgType:
+	case AckMsgType, CompleteMsgType:
 		return s.ackMsgRecord(msg)
 	}
 
@@ -704,6 +777,10 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg) (*connection.Record,
 		return nil, fmt.Errorf("unmarshalling failed: %s", err)
 	}
 
+	if request.Connection != nil {
+		request.DID = request.Connection.DID
+	}
+
 	invitationID := msg.ParentThreadID()
 	if invitationID == "" {
 		return nil, fmt.Errorf("missing parent thread ID on didexchange request with @id=%s", request.ID)
@@ -714,7 +791,7 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg) (*connection.Record,
 		ConnectionID: generateRandomID(),
 		ThreadID:     request.ID,
 		State:        stateNameNull,
-		TheirDID:     request.Connection.DID,
+		TheirDID:     request.DID,
 		InvitationID: invitationID,
 		Namespace:    theirNSPrefix,
 	}
@@ -840,3 +917,162 @@ func createDIDCommMsg(invitation *Invitation) (service.DIDCommMsg, error) {
 
 	return service.ParseDIDCommMsgMap(payload)
 }
+
+// problemCode returns the code of the problem report sent when the message is not accepted (rejected)
+// or fails to be processed. The code is empty if no problem report is sent (e.g the invitation).
+func problemCode(msgType string, rejected bool) string {
+	switch {
+	case msgType == RequestMsgType && rejected:
+		return codeRequestNotAccepted
+	case msgType == RequestMsgType:
+		return codeRequestProcessingError
+	case msgType == ResponseMsgType && rejected:
+		return codeResponseNotAccepted
+	case msgType == ResponseMsgType:
+		return codeResponseProcessingError
+	default:
+		return ""
+	}
+}
+
+// sendProblemReport sends the problem report to the agent of the request or the response.
+func (s *Service) sendProblemReport(connRec *connection.Record, msg service.DIDCommMsg, code string,
+	processErr error) error {
+	destination, err := s.problemReportDestination(connRec, msg)
+	if err != nil {
+		return err
+	}
+
+	senderKey, err := s.problemReportSenderKey(connRec)
+	if err != nil {
+		return err
+	}
+
+	report := &ProblemReport{
+		Type:        ProblemReportMsgType,
+		ID:          uuid.New().String(),
+		ProblemCode: code,
+		Explain:     processErr.Error(),
+		Thread: &decorator.Thread{
+			ID:  connRec.ThreadID,
+			PID: parentThreadID(connRec),
+		},
+	}
+
+	return s.ctx.outboundDispatcher.Send(report, senderKey, destination)
+}
+
+// problemReportDestination returns the destination of the requester (the DID doc of the request)
+// or the responder (the service of the invitation).
+func (s *Service) problemReportDestination(connRec *connection.Record,
+	msg service.DIDCommMsg) (*service.Destination, error) {
+	if msg.Type() != RequestMsgType {
+		return &service.Destination{
+			RecipientKeys:   connRec.RecipientKeys,
+			ServiceEndpoint: connRec.ServiceEndPoint,
+		}, nil
+	}
+
+	request := &Request{}
+
+	if err := msg.Decode(request); err != nil {
+		return nil, fmt.Errorf("decode exchange request: %w", err)
+	}
+
+	doc, err := s.ctx.requestDIDDoc(request)
+	if err != nil {
+		return nil, fmt.Errorf("resolve did doc from exchange request: %w", err)
+	}
+
+	return service.CreateDestination(doc)
+}
+
+// problemReportSenderKey returns the key of my DID or the key of the invitation if my DID was not created yet.
+func (s *Service) problemReportSenderKey(connRec *connection.Record) (string, error) {
+	if connRec.MyDID == "" {
+		return s.ctx.getVerKey(connRec.InvitationID)
+	}
+
+	doc, err := s.ctx.vdRegistry.Resolve(connRec.MyDID)
+	if err != nil {
+		return "", fmt.Errorf("resolve my did: %w", err)
+	}
+
+	return recipientKey(doc)
+}
+
+// handleProblemReport abandons the connection the problem report was sent for. Only the other party
+// of the connection is allowed to abandon it, and only while the exchange is in progress.
+func (s *Service) handleProblemReport(msg service.DIDCommMsg, thID, myDID, theirDID string) (string, error) {
+	report := &ProblemReport{}
+
+	if err := msg.Decode(report); err != nil {
+		return "", fmt.Errorf("decode problem report: %w", err)
+	}
+
+	connRec, err := s.problemReportConnection(thID)
+	if err != nil {
+		return "", err
+	}
+
+	switch connRec.State {
+	case StateIDInvited, StateIDRequested, StateIDResponded:
+	default:
+		return "", fmt.Errorf("the connection %s can not be abandoned in the state %s",
+			connRec.ConnectionID, connRec.State)
+	}
+
+	if !sentByPeer(connRec, myDID, theirDID) {
+		return "", fmt.Errorf("the problem report was not sent by the other party of the connection %s",
+			connRec.ConnectionID)
+	}
+
+	connRec.State = StateIDAbandoned
+
+	if err := s.connectionStore.saveConnectionRecord(connRec); err != nil {
+		return "", fmt.Errorf("unable to update the state to abandoned: %w", err)
+	}
+
+	s.sendMsgEvents(&service.StateMsg{
+		ProtocolName: DIDExchange,
+		Type:         service.PostState,
+		Msg:          msg.Clone(),
+		StateID:      StateIDAbandoned,
+		Properties: createErrorEventProperties(connRec.ConnectionID, connRec.InvitationID,
+			fmt.Errorf("problem report %s: %s", report.ProblemCode, report.Explain)),
+	})
+
+	return connRec.ConnectionID, nil
+}
+
+// problemReportConnection returns the connection of the problem report, the problem report is sent
+// either by the inviter or by the invitee.
+func (s *Service) problemReportConnection(thID string) (*connection.Record, error) {
+	for _, nsPrefix := range []string{myNSPrefix, theirNSPrefix} {
+		nsThID, err := connection.CreateNamespaceKey(nsPrefix, thID)
+		if err != nil {
+			return nil, err
+		}
+
+		connRec, err := s.connectionStore.GetConnectionRecordByNSThreadID(nsThID)
+		if err == nil {
+			return connRec, nil
+		}
+
+		if !errors.Is(err, storage.ErrDataNotFound) {
+			return nil, fmt.Errorf("get connection record: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("no connection for the problem report with thread ID %s", thID)
+}
+
+// sentByPeer is true if the DIDs of the message match the DIDs of the connection known so far.
+func sentByPeer(connRec *connection.Record, myDID, theirDID string) bool {
+	if connRec.MyDID == "" && connRec.TheirDID == "" {
+		return false
+	}
+
+	return (connRec.MyDID == "" || connRec.MyDID == myDID) &&
+		(connRec.TheirDID == "" || connRec.TheirDID == theirDID)
+}
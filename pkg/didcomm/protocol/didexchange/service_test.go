@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
@@ -60,6 +61,7 @@ func TestService_Name(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Equal(t, DIDExchange, prov.Name())
+		require.Equal(t, []string{PIURI}, prov.Protocols())
 	})
 }
 
@@ -296,10 +298,11 @@ func TestService_Handle_Invitee(t *testing.T) {
 	newDidDoc, err := ctx.vdRegistry.Create(testMethod)
 	require.NoError(t, err)
 
-	s, err := New(prov)
+	// Bob replies with the connection~sig of the legacy connections
+	s, err := New(prov, WithLegacyConnections())
 	require.NoError(t, err)
 
-	s.ctx.vdRegistry = &mockvdr.MockVDRegistry{ResolveValue: newDidDoc}
+	s.ctx.vdRegistry = &mockvdr.MockVDRegistry{CreateValue: newDidDoc, ResolveValue: newDidDoc}
 	actionCh := make(chan service.DIDCommAction, 10)
 	err = s.RegisterActionEvent(actionCh)
 	require.NoError(t, err)
@@ -518,6 +521,8 @@ func TestService_Accept(t *testing.T) {
 	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/request"))
 	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/response"))
 	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/ack"))
+	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/complete"))
+	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/problem_report"))
 	require.Equal(t, false, s.Accept("unsupported msg type"))
 }
 
@@ -611,10 +616,14 @@ func TestService_Update(t *testing.T) {
 	svc := &Service{connectionStore: connectionStore}
 
 	require.NoError(t, svc.update(RequestMsgType, connRec))
+	require.NotNil(t, connRec.CreatedTime)
+	require.NotNil(t, connRec.UpdatedTime)
 
 	cr := &connection.Record{}
 	err = json.Unmarshal(bytes, cr)
 	require.NoError(t, err)
+
+	connRec.CreatedTime, connRec.UpdatedTime = nil, nil
 	require.Equal(t, cr, connRec)
 }
 
@@ -725,17 +734,25 @@ func (m *mockStore) Put(k string, v []byte) error {
 
 // Get fetches the record based on key.
 func (m *mockStore) Get(k string) ([]byte, error) {
+	if m.get == nil {
+		return nil, storage.ErrDataNotFound
+	}
+
 	return m.get(k)
 }
 
 // Delete the record based on key.
 func (m *mockStore) Delete(k string) error {
+	if m.delete == nil {
+		return nil
+	}
+
 	return m.delete(k)
 }
 
 // Search returns storage iterator.
 func (m *mockStore) Iterator(start, limit string) storage.StoreIterator {
-	return nil
+	return mockstorage.NewMockIterator(nil)
 }
 
 func randomString() string {
@@ -910,6 +927,170 @@ func TestEventsUserError(t *testing.T) {
 	}
 }
 
+func TestEventsUserErrorProblemReport(t *testing.T) {
+	prov := getProvider(t)
+	prov.ServiceMap = map[string]interface{}{
+		mediator.Coordination: &mockroute.MockMediatorSvc{},
+	}
+
+	svc, err := New(&prov)
+	require.NoError(t, err)
+
+	sent := make(chan *ProblemReport, 1)
+	svc.ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+		ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+			report, ok := msg.(*ProblemReport)
+			require.True(t, ok)
+
+			sent <- report
+
+			return nil
+		},
+	}
+
+	actionCh := make(chan service.DIDCommAction, 10)
+	err = svc.RegisterActionEvent(actionCh)
+	require.NoError(t, err)
+
+	go func() {
+		for e := range actionCh {
+			e.Stop(errors.New("unknown requester"))
+		}
+	}()
+
+	invitation, err := createMockInvitation(newED25519Key(t, prov.CustomKMS), svc.ctx)
+	require.NoError(t, err)
+
+	id := randomString()
+	_, err = svc.HandleInbound(generateRequestMsgPayload(t, &protocol.MockProvider{}, id, invitation.ID), "", "")
+	require.NoError(t, err)
+
+	select {
+	case report := <-sent:
+		require.Equal(t, ProblemReportMsgType, report.Type)
+		require.Equal(t, codeRequestNotAccepted, report.ProblemCode)
+		require.Equal(t, "unknown requester", report.Explain)
+		require.Equal(t, id, report.Thread.ID)
+		require.Equal(t, invitation.ID, report.Thread.PID)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "problem report was not sent")
+	}
+
+	validateState(t, svc, id, theirNSPrefix, StateIDAbandoned)
+}
+
+func TestService_HandleProblemReport(t *testing.T) {
+	svc, err := New(testProvider())
+	require.NoError(t, err)
+
+	statusCh := make(chan service.StateMsg, 10)
+	err = svc.RegisterMsgEvent(statusCh)
+	require.NoError(t, err)
+
+	t.Run("abandons the connection", func(t *testing.T) {
+		connRec := &connection.Record{
+			ConnectionID: randomString(),
+			ThreadID:     randomString(),
+			State:        StateIDRequested,
+			Namespace:    myNSPrefix,
+			MyDID:        "did:example:me",
+		}
+		require.NoError(t, svc.connectionStore.saveConnectionRecordWithMapping(connRec))
+
+		connID, err := svc.HandleInbound(toDIDCommMsg(t, &ProblemReport{
+			Type:        ProblemReportMsgType,
+			ID:          randomString(),
+			ProblemCode: codeRequestNotAccepted,
+			Explain:     "unknown requester",
+			Thread:      &decorator.Thread{ID: connRec.ThreadID},
+		}), "did:example:me", "did:example:them")
+		require.NoError(t, err)
+		require.Equal(t, connRec.ConnectionID, connID)
+
+		validateState(t, svc, connRec.ThreadID, myNSPrefix, StateIDAbandoned)
+
+		select {
+		case e := <-statusCh:
+			require.Equal(t, StateIDAbandoned, e.StateID)
+			require.EqualError(t, e.Properties.(error), "problem report request_not_accepted: unknown requester")
+		case <-time.After(time.Second):
+			require.Fail(t, "abandoned event was not sent")
+		}
+	})
+	t.Run("does not abandon the completed connection", func(t *testing.T) {
+		connRec := &connection.Record{
+			ConnectionID: randomString(),
+			ThreadID:     randomString(),
+			State:        StateIDCompleted,
+			Namespace:    myNSPrefix,
+			MyDID:        "did:example:me",
+			TheirDID:     "did:example:them",
+		}
+		require.NoError(t, svc.connectionStore.saveConnectionRecordWithMapping(connRec))
+
+		_, err := svc.HandleInbound(toDIDCommMsg(t, &ProblemReport{
+			Type:   ProblemReportMsgType,
+			ID:     randomString(),
+			Thread: &decorator.Thread{ID: connRec.ThreadID},
+		}), connRec.MyDID, connRec.TheirDID)
+		require.EqualError(t, err, fmt.Sprintf("the connection %s can not be abandoned in the state completed",
+			connRec.ConnectionID))
+
+		validateState(t, svc, connRec.ThreadID, myNSPrefix, StateIDCompleted)
+	})
+	t.Run("rejects the problem report of another sender", func(t *testing.T) {
+		connRec := &connection.Record{
+			ConnectionID: randomString(),
+			ThreadID:     randomString(),
+			State:        StateIDResponded,
+			Namespace:    theirNSPrefix,
+			MyDID:        "did:example:me",
+			TheirDID:     "did:example:them",
+		}
+		require.NoError(t, svc.connectionStore.saveConnectionRecordWithMapping(connRec))
+
+		msg := toDIDCommMsg(t, &ProblemReport{
+			Type:   ProblemReportMsgType,
+			ID:     randomString(),
+			Thread: &decorator.Thread{ID: connRec.ThreadID},
+		})
+		expected := fmt.Sprintf("the problem report was not sent by the other party of the connection %s",
+			connRec.ConnectionID)
+
+		_, err := svc.HandleInbound(msg, connRec.MyDID, "did:example:other")
+		require.EqualError(t, err, expected)
+
+		_, err = svc.HandleInbound(msg, "", "")
+		require.EqualError(t, err, expected)
+
+		connRec.MyDID, connRec.TheirDID = "", ""
+		require.NoError(t, svc.connectionStore.saveConnectionRecord(connRec))
+
+		_, err = svc.HandleInbound(msg, "did:example:me", "did:example:them")
+		require.EqualError(t, err, expected)
+
+		validateState(t, svc, connRec.ThreadID, theirNSPrefix, StateIDResponded)
+	})
+	t.Run("fails if the connection is not found", func(t *testing.T) {
+		_, err := svc.HandleInbound(toDIDCommMsg(t, &ProblemReport{
+			Type:   ProblemReportMsgType,
+			ID:     randomString(),
+			Thread: &decorator.Thread{ID: "unknown"},
+		}), "", "")
+		require.EqualError(t, err, "no connection for the problem report with thread ID unknown")
+	})
+}
+
+func TestWithLegacyConnections(t *testing.T) {
+	svc, err := New(testProvider(), WithLegacyConnections())
+	require.NoError(t, err)
+	require.True(t, svc.ctx.legacyConnections)
+
+	svc, err = New(testProvider())
+	require.NoError(t, err)
+	require.False(t, svc.ctx.legacyConnections)
+}
+
 func TestEventStoreError(t *testing.T) {
 	svc, err := New(&protocol.MockProvider{
 		ServiceMap: map[string]interface{}{
@@ -953,7 +1134,7 @@ func TestEventProcessCallback(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid state name: invalid state name ")
 
-	err = svc.abandon(msg.ThreadID, msg.Msg, nil)
+	err = svc.abandon(msg.ThreadID, msg.Msg, "", nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "unable to update the state to abandoned")
 }
@@ -1770,9 +1951,11 @@ func TestService_CreateImplicitInvitation(t *testing.T) {
 
 		ctx := &context{
 			outboundDispatcher: prov.OutboundDispatcher(),
-			vdRegistry:         &mockvdr.MockVDRegistry{ResolveValue: newDIDDoc},
+			vdRegistry:         &mockvdr.MockVDRegistry{CreateValue: newDIDDoc, ResolveValue: newDIDDoc},
 			connectionStore:    cStore,
 			routeSvc:           routeSvc,
+			crypto:             &tinkcrypto.Crypto{},
+			kms:                k,
 		}
 
 		s, err := New(prov)
@@ -81,7 +81,7 @@ func stateFromMsgType(msgType string) (state, error) {
 		return &requested{}, nil
 	case ResponseMsgType:
 		return &responded{}, nil
-	case AckMsgType:
+	case AckMsgType, CompleteMsgType:
 		return &completed{}, nil
 	default:
 		return nil, fmt.Errorf("unrecognized msgType: %s", msgType)
@@ -201,6 +201,12 @@ func (s *requested) ExecuteInbound(msg *stateMachineMsg, thid string, ctx *conte
 
 		return connRecord, &noOp{}, action, nil
 	case RequestMsgType:
+		// the invitation policy (max uses, expiry and revocation) is checked when the request arrives,
+		// the use is counted when the response is sent
+		if err := ctx.connectionStore.CheckInvitation(msg.connRecord.InvitationID); err != nil {
+			return nil, nil, nil, fmt.Errorf("invitation %s not accepted: %w", msg.connRecord.InvitationID, err)
+		}
+
 		return msg.connRecord, &responded{}, func() error { return nil }, nil
 	default:
 		return nil, nil, nil, fmt.Errorf("illegal msg type %s for state %s", msg.Type(), s.Name())
@@ -235,7 +241,7 @@ func (s *responded) ExecuteInbound(msg *stateMachineMsg, thid string, ctx *conte
 			return nil, nil, nil, fmt.Errorf("handle inbound request: %w", err)
 		}
 
-		return connRecord, &noOp{}, action, nil
+		return connRecord, &noOp{}, ctx.useInvitation(msg.connRecord.InvitationID, action), nil
 	case ResponseMsgType:
 		return msg.connRecord, &completed{}, func() error { return nil }, nil
 	default:
@@ -243,6 +249,25 @@ func (s *responded) ExecuteInbound(msg *stateMachineMsg, thid string, ctx *conte
 	}
 }
 
+// useInvitation counts the use of the invitation before the response is sent by the action,
+// the use is restored if the response is not sent.
+func (ctx *context) useInvitation(invitationID string, action stateAction) stateAction {
+	return func() error {
+		if err := ctx.connectionStore.UseInvitation(invitationID); err != nil {
+			return fmt.Errorf("invitation %s not accepted: %w", invitationID, err)
+		}
+
+		err := action()
+		if err != nil {
+			if e := ctx.connectionStore.ReleaseInvitation(invitationID); e != nil {
+				logger.Warnf("failed to release the use of invitation %s : %s", invitationID, e)
+			}
+		}
+
+		return err
+	}
+}
+
 // completed state.
 type completed struct {
 }
@@ -272,7 +297,7 @@ func (s *completed) ExecuteInbound(msg *stateMachineMsg, thid string, ctx *conte
 		}
 
 		return connRecord, &noOp{}, action, nil
-	case AckMsgType:
+	case AckMsgType, CompleteMsgType:
 		action := func() error { return nil }
 		return msg.connRecord, &noOp{}, action, nil
 	default:
@@ -315,10 +340,9 @@ func (ctx *context) handleInboundOOBInvitation(
 	}
 
 	request := &Request{
-		Type:       RequestMsgType,
-		ID:         thid,
-		Label:      oobInvitation.MyLabel,
-		Connection: conn,
+		Type:  RequestMsgType,
+		ID:    thid,
+		Label: oobInvitation.MyLabel,
 		Thread: &decorator.Thread{
 			ID:  thid,
 			PID: msg.connRecord.ParentThreadID,
@@ -341,6 +365,11 @@ func (ctx *context) handleInboundOOBInvitation(
 		return nil, nil, fmt.Errorf("handle inbound OOBInvitation: %w", err)
 	}
 
+	err = ctx.setRequestDIDDoc(request, myDID, conn, recipientKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("handle inbound OOBInvitation: %w", err)
+	}
+
 	return func() error {
 		logger.Debugf("dispatching outbound request on thread: %+v", request.Thread)
 		return ctx.outboundDispatcher.Send(request, recipientKey, dest)
@@ -367,21 +396,25 @@ func (ctx *context) handleInboundInvitation(invitation *Invitation, thid string,
 	}
 
 	request := &Request{
-		Type:       RequestMsgType,
-		ID:         thid,
-		Label:      getLabel(options),
-		Connection: conn,
+		Type:  RequestMsgType,
+		ID:    thid,
+		Label: getLabel(options),
 		Thread: &decorator.Thread{
 			PID: pid,
 		},
 	}
-	connRec.MyDID = request.Connection.DID
+	connRec.MyDID = conn.DID
 
 	senderKey, err := recipientKey(didDoc)
 	if err != nil {
 		return nil, nil, fmt.Errorf("handle inbound invitation: %w", err)
 	}
 
+	err = ctx.setRequestDIDDoc(request, didDoc, conn, senderKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("handle inbound invitation: %w", err)
+	}
+
 	return func() error {
 		return ctx.outboundDispatcher.Send(request, senderKey, destination)
 	}, connRec, nil
@@ -389,9 +422,16 @@ func (ctx *context) handleInboundInvitation(invitation *Invitation, thid string,
 
 func (ctx *context) handleInboundRequest(request *Request, options *options,
 	connRec *connectionstore.Record) (stateAction, *connectionstore.Record, error) {
-	requestDidDoc, err := ctx.resolveDidDocFromConnection(request.Connection)
+	requestDidDoc, err := ctx.requestDIDDoc(request)
 	if err != nil {
-		return nil, nil, fmt.Errorf("resolve did doc from exchange request connection: %w", err)
+		return nil, nil, fmt.Errorf("resolve did doc from exchange request: %w", err)
+	}
+
+	if storeRequestDIDDoc(request, requestDidDoc) {
+		// store provided did document
+		if err = ctx.vdRegistry.Store(requestDidDoc); err != nil {
+			return nil, nil, fmt.Errorf("failed to store provided did document: %w", err)
+		}
 	}
 
 	// get did document that will be used in exchange response
@@ -401,12 +441,6 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 		return nil, nil, err
 	}
 
-	// prepare connection signature
-	encodedConnectionSignature, err := ctx.prepareConnectionSignature(connection, request.Thread.PID)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	// prepare the response
 	response := &Response{
 		Type: ResponseMsgType,
@@ -414,10 +448,14 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 		Thread: &decorator.Thread{
 			ID: request.ID,
 		},
-		ConnectionSignature: encodedConnectionSignature,
 	}
 
-	connRec.TheirDID = request.Connection.DID
+	err = ctx.setResponseDIDDoc(response, responseDidDoc, connection, request.Thread.PID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connRec.TheirDID = requestDidDoc.ID
 	connRec.MyDID = connection.DID
 	connRec.TheirLabel = request.Label
 
@@ -437,6 +475,97 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 	}, connRec, nil
 }
 
+// setRequestDIDDoc sets the DID of the requester on the request. The DID doc is attached and signed
+// unless the DID is public (the inviter resolves it).
+func (ctx *context) setRequestDIDDoc(request *Request, didDoc *did.Doc, conn *Connection, signingKey string) error {
+	if ctx.legacyConnections {
+		request.Connection = conn
+
+		return nil
+	}
+
+	request.DID = conn.DID
+
+	if conn.DIDDoc == nil {
+		return nil
+	}
+
+	attachment, err := ctx.didDocAttachment(didDoc, signingKey)
+	if err != nil {
+		return fmt.Errorf("attach did doc to exchange request: %w", err)
+	}
+
+	request.DocAttach = attachment
+
+	return nil
+}
+
+// setResponseDIDDoc sets the DID of the responder on the response. The DID doc is attached and signed
+// with the key of the invitation.
+func (ctx *context) setResponseDIDDoc(response *Response, didDoc *did.Doc, conn *Connection,
+	invitationID string) error {
+	if ctx.legacyConnections {
+		// prepare connection signature
+		encodedConnectionSignature, err := ctx.prepareConnectionSignature(conn, invitationID)
+		if err != nil {
+			return err
+		}
+
+		response.ConnectionSignature = encodedConnectionSignature
+
+		return nil
+	}
+
+	verKey, err := ctx.getVerKey(invitationID)
+	if err != nil {
+		return fmt.Errorf("failed to get verkey: %w", err)
+	}
+
+	attachment, err := ctx.didDocAttachment(didDoc, verKey)
+	if err != nil {
+		return fmt.Errorf("attach did doc to exchange response: %w", err)
+	}
+
+	response.DID = conn.DID
+	response.DocAttach = attachment
+
+	return nil
+}
+
+// requestDIDDoc returns the DID doc of the requester, either attached to the request or resolved.
+func (ctx *context) requestDIDDoc(request *Request) (*did.Doc, error) {
+	switch {
+	case request.DocAttach != nil:
+		doc, err := VerifyDIDDocAttachment(request.DocAttach)
+		if err != nil {
+			return nil, err
+		}
+
+		if request.DID != "" && request.DID != doc.ID {
+			return nil, fmt.Errorf("the attached did doc %s does not match the did %s", doc.ID, request.DID)
+		}
+
+		return doc, nil
+	case request.Connection != nil && request.Connection.DIDDoc != nil:
+		return request.Connection.DIDDoc, nil
+	case request.Connection != nil:
+		return ctx.vdRegistry.Resolve(request.Connection.DID)
+	case request.DID != "":
+		return ctx.vdRegistry.Resolve(request.DID)
+	default:
+		return nil, errors.New("the exchange request has neither did nor did doc")
+	}
+}
+
+// storeRequestDIDDoc is true if the DID doc was provided with the request (the public DID doc is resolvable).
+func storeRequestDIDDoc(request *Request, doc *did.Doc) bool {
+	if request.DocAttach != nil {
+		return isPeerDID(doc.ID)
+	}
+
+	return request.Connection != nil && request.Connection.DIDDoc != nil
+}
+
 func getPublicDID(options *options) string {
 	if options == nil {
 		return ""
@@ -474,7 +603,6 @@ func (ctx *context) getDestination(invitation *Invitation) (*service.Destination
 	}, nil
 }
 
-// nolint: funlen,gocyclo
 func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []string) (*did.Doc, *Connection, error) {
 	if pubDID != "" {
 		logger.Debugf("using public did[%s] for connection", pubDID)
@@ -494,13 +622,36 @@ func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []st
 
 	logger.Debugf("creating new '%s' did for connection", didMethod)
 
+	newDidDoc, err := CreatePeerDIDDoc(ctx.vdRegistry, ctx.routeSvc, routerConnections)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = ctx.connectionStore.SaveDIDFromDoc(newDidDoc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connection := &Connection{
+		DID:    newDidDoc.ID,
+		DIDDoc: newDidDoc,
+	}
+
+	return newDidDoc, connection, nil
+}
+
+// CreatePeerDIDDoc creates the peer DID doc of the agent. The DIDComm service is routed through the routers
+// of the given connections and its recipient keys are registered with them, the default service endpoint
+// of the VDR is used if no router connection is given.
+func CreatePeerDIDDoc(vdRegistry vdr.Registry, routeSvc mediator.ProtocolService,
+	routerConnections []string) (*did.Doc, error) {
 	var services []did.Service
 
 	for _, connID := range routerConnections {
 		// get the route configs (pass empty service endpoint, as default service endpoint added in VDR)
-		serviceEndpoint, routingKeys, err := mediator.GetRouterConfig(ctx.routeSvc, connID, "")
+		serviceEndpoint, routingKeys, err := mediator.GetRouterConfig(routeSvc, connID, "")
 		if err != nil {
-			return nil, nil, fmt.Errorf("did doc - fetch router config: %w", err)
+			return nil, fmt.Errorf("did doc - fetch router config: %w", err)
 		}
 
 		services = append(services, did.Service{ServiceEndpoint: serviceEndpoint, RoutingKeys: routingKeys})
@@ -511,12 +662,12 @@ func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []st
 	}
 
 	// by default use peer did
-	newDidDoc, err := ctx.vdRegistry.Create(
+	newDidDoc, err := vdRegistry.Create(
 		didMethod,
 		vdr.WithServices(services...),
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("create %s did: %w", didMethod, err)
+		return nil, fmt.Errorf("create %s did: %w", didMethod, err)
 	}
 
 	if len(routerConnections) != 0 {
@@ -526,25 +677,15 @@ func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []st
 				for _, connID := range routerConnections {
 					// TODO https://github.com/hyperledger/aries-framework-go/issues/1105 Support to Add multiple
 					//  recKeys to the Router
-					if err = mediator.AddKeyToRouter(ctx.routeSvc, connID, recKey); err != nil {
-						return nil, nil, fmt.Errorf("did doc - add key to the router: %w", err)
+					if err = mediator.AddKeyToRouter(routeSvc, connID, recKey); err != nil {
+						return nil, fmt.Errorf("did doc - add key to the router: %w", err)
 					}
 				}
 			}
 		}
 	}
 
-	err = ctx.connectionStore.SaveDIDFromDoc(newDidDoc)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	connection := &Connection{
-		DID:    newDidDoc.ID,
-		DIDDoc: newDidDoc,
-	}
-
-	return newDidDoc, connection, nil
+	return newDidDoc, nil
 }
 
 func (ctx *context) resolveDidDocFromConnection(conn *Connection) (*did.Doc, error) {
@@ -609,16 +750,7 @@ func (ctx *context) prepareConnectionSignature(connection *Connection,
 }
 
 func (ctx *context) handleInboundResponse(response *Response) (stateAction, *connectionstore.Record, error) {
-	ack := &model.Ack{
-		Type:   AckMsgType,
-		ID:     uuid.New().String(),
-		Status: ackStatusOK,
-		Thread: &decorator.Thread{
-			ID: response.Thread.ID,
-		},
-	}
-
-	nsThID, err := connectionstore.CreateNamespaceKey(myNSPrefix, ack.Thread.ID)
+	nsThID, err := connectionstore.CreateNamespaceKey(myNSPrefix, response.Thread.ID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -628,17 +760,12 @@ func (ctx *context) handleInboundResponse(response *Response) (stateAction, *con
 		return nil, nil, fmt.Errorf("get connection record: %w", err)
 	}
 
-	conn, err := verifySignature(response.ConnectionSignature, connRecord.RecipientKeys[0])
+	responseDidDoc, err := ctx.responseDIDDoc(response, connRecord.RecipientKeys[0])
 	if err != nil {
 		return nil, nil, err
 	}
 
-	connRecord.TheirDID = conn.DID
-
-	responseDidDoc, err := ctx.resolveDidDocFromConnection(conn)
-	if err != nil {
-		return nil, nil, fmt.Errorf("resolve did doc from exchange response connection: %w", err)
-	}
+	connRecord.TheirDID = responseDidDoc.ID
 
 	destination, err := service.CreateDestination(responseDidDoc)
 	if err != nil {
@@ -655,11 +782,77 @@ func (ctx *context) handleInboundResponse(response *Response) (stateAction, *con
 		return nil, nil, fmt.Errorf("handle inbound response: %w", err)
 	}
 
+	var reply interface{} = &Complete{
+		Type: CompleteMsgType,
+		ID:   uuid.New().String(),
+		Thread: &decorator.Thread{
+			ID:  response.Thread.ID,
+			PID: parentThreadID(connRecord),
+		},
+	}
+
+	if ctx.legacyConnections {
+		reply = &model.Ack{
+			Type:   AckMsgType,
+			ID:     uuid.New().String(),
+			Status: ackStatusOK,
+			Thread: &decorator.Thread{
+				ID: response.Thread.ID,
+			},
+		}
+	}
+
 	return func() error {
-		return ctx.outboundDispatcher.Send(ack, recKey, destination)
+		return ctx.outboundDispatcher.Send(reply, recKey, destination)
 	}, connRecord, nil
 }
 
+// responseDIDDoc verifies the DID doc of the responder was signed with the key of the invitation
+// and stores it.
+func (ctx *context) responseDIDDoc(response *Response, invitationKey string) (*did.Doc, error) {
+	if response.DocAttach == nil {
+		// the connection~sig of RFC 0160 is only accepted from the agents using the legacy connections
+		if !ctx.legacyConnections || response.ConnectionSignature == nil {
+			return nil, errors.New("the exchange response has no signed did doc")
+		}
+
+		conn, err := verifySignature(response.ConnectionSignature, invitationKey)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := ctx.resolveDidDocFromConnection(conn)
+		if err != nil {
+			return nil, fmt.Errorf("resolve did doc from exchange response connection: %w", err)
+		}
+
+		return doc, nil
+	}
+
+	doc, err := VerifyDIDDocAttachment(response.DocAttach, invitationKey)
+	if err != nil {
+		return nil, fmt.Errorf("verify did doc of exchange response: %w", err)
+	}
+
+	if isPeerDID(doc.ID) {
+		// store provided did document
+		if err = ctx.vdRegistry.Store(doc); err != nil {
+			return nil, fmt.Errorf("failed to store provided did document: %w", err)
+		}
+	}
+
+	return doc, nil
+}
+
+// parentThreadID returns the ID of the invitation the connection originated from.
+func parentThreadID(connRecord *connectionstore.Record) string {
+	if connRecord.ParentThreadID != "" {
+		return connRecord.ParentThreadID
+	}
+
+	return connRecord.InvitationID
+}
+
 // verifySignature verifies connection signature and returns connection.
 func verifySignature(connSignature *ConnectionSignature, recipientKeys string) (*Connection, error) {
 	sigData, err := base64.URLEncoding.DecodeString(connSignature.SignedData)
@@ -842,6 +1035,10 @@ func (ctx *context) resolveVerKey(i *OOBInvitation) (string, error) {
 	return svc.RecipientKeys[0], nil
 }
 
+func isPeerDID(str string) bool {
+	return strings.HasPrefix(str, "did:"+didMethod+":")
+}
+
 func isDID(str string) bool {
 	const didPrefix = "did:"
 	return strings.HasPrefix(str, didPrefix)
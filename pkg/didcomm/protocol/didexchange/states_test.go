@@ -145,6 +145,10 @@ func TestStateFromMsgType(t *testing.T) {
 		actual, err := stateFromMsgType(AckMsgType)
 		require.NoError(t, err)
 		require.Equal(t, expected.Name(), actual.Name())
+
+		actual, err = stateFromMsgType(CompleteMsgType)
+		require.NoError(t, err)
+		require.Equal(t, expected.Name(), actual.Name())
 	})
 	t.Run("invalid", func(t *testing.T) {
 		actual, err := stateFromMsgType("invalid")
@@ -359,7 +363,7 @@ func TestRequestedState_Execute(t *testing.T) {
 		require.True(t, dispatched)
 	})
 	t.Run("handle inbound oob invitations - register recipient keys in router", func(t *testing.T) {
-		expected := "my test key"
+		expected := newED25519Key(t, prov.CustomKMS)
 		registered := false
 		ctx := getContext(t, &prov)
 		doc := createDIDDoc(t, prov.CustomKMS)
@@ -481,6 +485,49 @@ func TestRequestedState_Execute(t *testing.T) {
 		require.Contains(t, err.Error(), "JSON unmarshalling of invitation")
 		require.Nil(t, followup)
 	})
+	t.Run("inbound requests checked against the invitation policy", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		invitationID := uuid.New().String()
+		require.NoError(t, ctx.connectionStore.SaveInvitationPolicy(&connection.InvitationPolicy{
+			InvitationID: invitationID,
+			MaxUses:      1,
+		}))
+
+		msg := &stateMachineMsg{
+			DIDCommMsg: service.NewDIDCommMsgMap(&Request{Type: RequestMsgType, ID: uuid.New().String()}),
+			connRecord: &connection.Record{InvitationID: invitationID},
+		}
+
+		connRec, followup, _, err := (&requested{}).ExecuteInbound(msg, "", ctx)
+		require.NoError(t, err)
+		require.Equal(t, msg.connRecord, connRec)
+		require.Equal(t, &responded{}, followup)
+
+		policy, err := ctx.connectionStore.GetInvitationPolicy(invitationID)
+		require.NoError(t, err)
+		require.Zero(t, policy.Uses)
+
+		require.NoError(t, ctx.connectionStore.UseInvitation(invitationID))
+
+		_, _, _, err = (&requested{}).ExecuteInbound(msg, "", ctx)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, connection.ErrInvitationUsedUp))
+	})
+	t.Run("inbound request for revoked invitation", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		invitationID := uuid.New().String()
+		require.NoError(t, ctx.connectionStore.SaveInvitationPolicy(&connection.InvitationPolicy{
+			InvitationID: invitationID,
+			Revoked:      true,
+		}))
+
+		_, _, _, err := (&requested{}).ExecuteInbound(&stateMachineMsg{
+			DIDCommMsg: service.NewDIDCommMsgMap(&Request{Type: RequestMsgType, ID: uuid.New().String()}),
+			connRecord: &connection.Record{InvitationID: invitationID},
+		}, "", ctx)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, connection.ErrInvitationRevoked))
+	})
 	t.Run("create DID error", func(t *testing.T) {
 		ctx2 := &context{
 			outboundDispatcher: prov.OutboundDispatcher(),
@@ -574,6 +621,48 @@ func TestRespondedState_Execute(t *testing.T) {
 		}, "", ctx)
 		require.Error(t, err)
 	})
+	t.Run("counts the invitation use when the response is sent", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		invitationID := uuid.New().String()
+		require.NoError(t, ctx.connectionStore.SaveInvitationPolicy(&connection.InvitationPolicy{
+			InvitationID: invitationID,
+			MaxUses:      1,
+		}))
+
+		_, _, action, e := (&responded{}).ExecuteInbound(&stateMachineMsg{
+			DIDCommMsg: bytesToDIDCommMsg(t, requestPayloadBytes),
+			connRecord: &connection.Record{InvitationID: invitationID},
+		}, "", ctx)
+		require.NoError(t, e)
+
+		policy, e := ctx.connectionStore.GetInvitationPolicy(invitationID)
+		require.NoError(t, e)
+		require.Zero(t, policy.Uses)
+
+		require.NoError(t, action())
+
+		policy, e = ctx.connectionStore.GetInvitationPolicy(invitationID)
+		require.NoError(t, e)
+		require.Equal(t, 1, policy.Uses)
+
+		e = ctx.useInvitation(invitationID, func() error { return nil })()
+		require.True(t, errors.Is(e, connection.ErrInvitationUsedUp))
+	})
+	t.Run("restores the invitation use if the response is not sent", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		invitationID := uuid.New().String()
+		require.NoError(t, ctx.connectionStore.SaveInvitationPolicy(&connection.InvitationPolicy{
+			InvitationID: invitationID,
+			MaxUses:      1,
+		}))
+
+		e := ctx.useInvitation(invitationID, func() error { return errors.New("send error") })()
+		require.EqualError(t, e, "send error")
+
+		policy, e := ctx.connectionStore.GetInvitationPolicy(invitationID)
+		require.NoError(t, e)
+		require.Zero(t, policy.Uses)
+	})
 }
 
 func TestAbandonedState_Execute(t *testing.T) {
@@ -601,6 +690,8 @@ func TestCompletedState_Execute(t *testing.T) {
 		crypto:          &tinkcrypto.Crypto{},
 		connectionStore: cStore,
 		kms:             customKMS,
+		// the response has the connection~sig of the legacy connections
+		legacyConnections: true,
 	}
 	newDIDDoc := createDIDDocWithKey(pubKey)
 	c := &Connection{
@@ -671,6 +762,18 @@ func TestCompletedState_Execute(t *testing.T) {
 		require.NoError(t, e)
 		require.IsType(t, &noOp{}, followup)
 	})
+	t.Run("no followup for inbound completes", func(t *testing.T) {
+		complete := &Complete{
+			Type:   CompleteMsgType,
+			ID:     randomString(),
+			Thread: &decorator.Thread{ID: response.Thread.ID},
+		}
+		_, followup, _, e := (&completed{}).ExecuteInbound(&stateMachineMsg{
+			DIDCommMsg: toDIDCommMsg(t, complete),
+		}, "", ctx)
+		require.NoError(t, e)
+		require.IsType(t, &noOp{}, followup)
+	})
 	t.Run("rejects messages other than responses and acks", func(t *testing.T) {
 		others := []service.DIDCommMsg{
 			service.NewDIDCommMsgMap(Invitation{Type: InvitationMsgType}),
@@ -964,6 +1067,76 @@ func TestNewRequestFromInvitation(t *testing.T) {
 		require.NotNil(t, connRec.MyDID)
 		require.Equal(t, connRec.MyDID, doc.ID)
 	})
+	t.Run("request has the signed did doc attached", func(t *testing.T) {
+		prov := getProvider(t)
+		ctx := getContext(t, &prov)
+
+		var request *Request
+
+		ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+				request = msg.(*Request)
+
+				return nil
+			},
+		}
+
+		action, connRec, err := ctx.handleInboundInvitation(invitation, invitation.ID, &options{}, &connection.Record{})
+		require.NoError(t, err)
+		require.NoError(t, action())
+		require.Nil(t, request.Connection)
+		require.Equal(t, connRec.MyDID, request.DID)
+
+		doc, err := VerifyDIDDocAttachment(request.DocAttach)
+		require.NoError(t, err)
+		require.Equal(t, request.DID, doc.ID)
+	})
+	t.Run("request has only the public did", func(t *testing.T) {
+		prov := getProvider(t)
+		doc := createDIDDoc(t, prov.CustomKMS)
+		ctx := getContext(t, &prov)
+		ctx.vdRegistry = &mockvdr.MockVDRegistry{ResolveValue: doc}
+
+		var request *Request
+
+		ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+				request = msg.(*Request)
+
+				return nil
+			},
+		}
+
+		action, _, err := ctx.handleInboundInvitation(invitation, invitation.ID, &options{publicDID: doc.ID},
+			&connection.Record{})
+		require.NoError(t, err)
+		require.NoError(t, action())
+		require.Equal(t, doc.ID, request.DID)
+		require.Nil(t, request.DocAttach)
+		require.Nil(t, request.Connection)
+	})
+	t.Run("legacy request has the connection", func(t *testing.T) {
+		prov := getProvider(t)
+		ctx := getContext(t, &prov)
+		ctx.legacyConnections = true
+
+		var request *Request
+
+		ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+				request = msg.(*Request)
+
+				return nil
+			},
+		}
+
+		action, connRec, err := ctx.handleInboundInvitation(invitation, invitation.ID, &options{}, &connection.Record{})
+		require.NoError(t, err)
+		require.NoError(t, action())
+		require.Empty(t, request.DID)
+		require.Nil(t, request.DocAttach)
+		require.Equal(t, connRec.MyDID, request.Connection.DID)
+	})
 	t.Run("unsuccessful new request from invitation ", func(t *testing.T) {
 		prov := protocol.MockProvider{}
 		ctx := &context{
@@ -989,6 +1162,105 @@ func TestNewResponseFromRequest(t *testing.T) {
 		require.NotNil(t, connRec.MyDID)
 		require.NotNil(t, connRec.TheirDID)
 	})
+	t.Run("response has the did doc signed with the invitation key", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		request, err := createRequest(t, ctx)
+		require.NoError(t, err)
+
+		var response *Response
+
+		ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+				response = msg.(*Response)
+
+				return nil
+			},
+		}
+
+		action, connRec, err := ctx.handleInboundRequest(request, &options{}, &connection.Record{})
+		require.NoError(t, err)
+		require.NoError(t, action())
+		require.Nil(t, response.ConnectionSignature)
+		require.Equal(t, connRec.MyDID, response.DID)
+
+		invitationKey, err := ctx.getVerKey(request.Thread.PID)
+		require.NoError(t, err)
+
+		doc, err := VerifyDIDDocAttachment(response.DocAttach, invitationKey)
+		require.NoError(t, err)
+		require.Equal(t, response.DID, doc.ID)
+	})
+	t.Run("legacy response has the connection signature", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		ctx.legacyConnections = true
+		request, err := createRequest(t, ctx)
+		require.NoError(t, err)
+
+		var response *Response
+
+		ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+				response = msg.(*Response)
+
+				return nil
+			},
+		}
+
+		action, _, err := ctx.handleInboundRequest(request, &options{}, &connection.Record{})
+		require.NoError(t, err)
+		require.NoError(t, action())
+		require.NotNil(t, response.ConnectionSignature)
+		require.Nil(t, response.DocAttach)
+	})
+	t.Run("successful new response from request with the attached did doc", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		request, err := createRequest(t, ctx)
+		require.NoError(t, err)
+
+		pubKey := newED25519Key(t, ctx.kms)
+		doc := createDIDDocWithKey(pubKey)
+		request.Connection = nil
+		request.DID = doc.ID
+		request.DocAttach, err = ctx.didDocAttachment(doc, pubKey)
+		require.NoError(t, err)
+
+		_, connRec, err := ctx.handleInboundRequest(request, &options{}, &connection.Record{})
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, connRec.TheirDID)
+
+		request.DID = "did:example:other"
+		_, _, err = ctx.handleInboundRequest(request, &options{}, &connection.Record{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match the did did:example:other")
+
+		request.DID = doc.ID
+		request.DocAttach.Data.JWS = nil
+		_, _, err = ctx.handleInboundRequest(request, &options{}, &connection.Record{})
+		require.True(t, errors.Is(err, errUnsignedAttachment))
+	})
+	t.Run("successful new response from request with the public did", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		request, err := createRequest(t, ctx)
+		require.NoError(t, err)
+
+		doc := createDIDDoc(t, ctx.kms)
+		request.Connection = nil
+		request.DID = doc.ID
+		ctx.vdRegistry = &mockvdr.MockVDRegistry{
+			CreateValue:  createDIDDoc(t, ctx.kms),
+			ResolveValue: doc,
+		}
+
+		_, connRec, err := ctx.handleInboundRequest(request, &options{}, &connection.Record{})
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, connRec.TheirDID)
+	})
+	t.Run("unsuccessful new response from request without did", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		_, _, err := ctx.handleInboundRequest(&Request{}, &options{}, &connection.Record{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the exchange request has neither did nor did doc")
+	})
 	t.Run("unsuccessful new response from request due to create did error", func(t *testing.T) {
 		didDoc := mockdiddoc.GetMockDIDDoc()
 		ctx := &context{
@@ -1057,7 +1329,112 @@ func TestHandleInboundResponse(t *testing.T) {
 		require.Contains(t, e.Error(), "empty bytes")
 		require.Nil(t, connRec)
 	})
+	t.Run("handle inbound responses with the did doc signed with the invitation key", func(t *testing.T) {
+		invitationKey := newED25519Key(t, ctx.kms)
+		myDoc := createDIDDoc(t, ctx.kms)
+		theirDoc := createDIDDoc(t, ctx.kms)
+
+		respond := func(t *testing.T, ctx *context, signingKey string) (*Response, *connection.Record) {
+			t.Helper()
+
+			attachment, e := ctx.didDocAttachment(theirDoc, signingKey)
+			require.NoError(t, e)
+
+			connRec := &connection.Record{
+				State:          (&responded{}).Name(),
+				ThreadID:       randomString(),
+				ConnectionID:   randomString(),
+				ParentThreadID: randomString(),
+				MyDID:          myDoc.ID,
+				RecipientKeys:  []string{invitationKey},
+			}
+
+			require.NoError(t, ctx.connectionStore.saveConnectionRecord(connRec))
+			require.NoError(t, ctx.connectionStore.SaveNamespaceThreadID(connRec.ThreadID, myNSPrefix,
+				connRec.ConnectionID))
+
+			return &Response{
+				Type:      ResponseMsgType,
+				ID:        randomString(),
+				DID:       theirDoc.ID,
+				DocAttach: attachment,
+				Thread:    &decorator.Thread{ID: connRec.ThreadID},
+			}, connRec
+		}
+
+		t.Run("sends complete", func(t *testing.T) {
+			ctx := getContext(t, &prov)
+			ctx.vdRegistry = &mockvdr.MockVDRegistry{ResolveValue: myDoc}
+
+			var sent interface{}
+
+			ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+				ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+					sent = msg
+
+					return nil
+				},
+			}
+
+			response, connRec := respond(t, ctx, invitationKey)
+			action, result, e := ctx.handleInboundResponse(response)
+			require.NoError(t, e)
+			require.Equal(t, theirDoc.ID, result.TheirDID)
+			require.NoError(t, action())
+
+			complete, ok := sent.(*Complete)
+			require.True(t, ok)
+			require.Equal(t, CompleteMsgType, complete.Type)
+			require.Equal(t, connRec.ThreadID, complete.Thread.ID)
+			require.Equal(t, connRec.ParentThreadID, complete.Thread.PID)
+		})
+		t.Run("sends ack with the legacy connections", func(t *testing.T) {
+			ctx := getContext(t, &prov)
+			ctx.legacyConnections = true
+			ctx.vdRegistry = &mockvdr.MockVDRegistry{ResolveValue: myDoc}
+
+			var sent interface{}
+
+			ctx.outboundDispatcher = &mockdispatcher.MockOutbound{
+				ValidateSend: func(msg interface{}, _ string, _ *service.Destination) error {
+					sent = msg
+
+					return nil
+				},
+			}
+
+			response, _ := respond(t, ctx, invitationKey)
+			action, _, e := ctx.handleInboundResponse(response)
+			require.NoError(t, e)
+			require.NoError(t, action())
+			require.IsType(t, &model.Ack{}, sent)
+		})
+		t.Run("rejects the did doc not signed with the invitation key", func(t *testing.T) {
+			ctx := getContext(t, &prov)
+			response, _ := respond(t, ctx, newED25519Key(t, ctx.kms))
+			_, _, e := ctx.handleInboundResponse(response)
+			require.Error(t, e)
+			require.Contains(t, e.Error(), "the DID doc was not signed with the expected key")
+		})
+		t.Run("rejects the unsigned did doc", func(t *testing.T) {
+			ctx := getContext(t, &prov)
+			response, _ := respond(t, ctx, invitationKey)
+			response.DocAttach.Data.JWS = nil
+			_, _, e := ctx.handleInboundResponse(response)
+			require.True(t, errors.Is(e, errUnsignedAttachment))
+		})
+		t.Run("rejects the response without did doc", func(t *testing.T) {
+			ctx := getContext(t, &prov)
+			response, _ := respond(t, ctx, invitationKey)
+			response.DocAttach = nil
+			_, _, e := ctx.handleInboundResponse(response)
+			require.EqualError(t, e, "the exchange response has no signed did doc")
+		})
+	})
 	t.Run("handle inbound responses missing signature data", func(t *testing.T) {
+		ctx.legacyConnections = true
+		defer func() { ctx.legacyConnections = false }()
+
 		resp, err := saveMockConnectionRecord(t, request, ctx)
 		require.NoError(t, err)
 		resp.ConnectionSignature = &ConnectionSignature{}
@@ -1066,6 +1443,13 @@ func TestHandleInboundResponse(t *testing.T) {
 		require.Contains(t, e.Error(), "missing or invalid signature data")
 		require.Nil(t, connRec)
 	})
+	t.Run("rejects the connection signature without the legacy connections", func(t *testing.T) {
+		resp, err := saveMockConnectionRecord(t, request, ctx)
+		require.NoError(t, err)
+		_, connRec, e := ctx.handleInboundResponse(resp)
+		require.EqualError(t, e, "the exchange response has no signed did doc")
+		require.Nil(t, connRec)
+	})
 }
 
 func TestGetInvitationRecipientKey(t *testing.T) {
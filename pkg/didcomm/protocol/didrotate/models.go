@@ -0,0 +1,23 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Rotate message is sent to the other agent to replace the DID of the sender on the connection.
+// The message is sent from the DID being replaced, the envelope authenticates the rotation.
+// https://github.com/hyperledger/aries-rfcs/tree/main/features/0794-did-rotate#rotate
+type Rotate struct {
+	Type  string `json:"@type,omitempty"`
+	ID    string `json:"@id,omitempty"`
+	ToDID string `json:"to_did,omitempty"`
+	// DocAttach is the DID doc of ToDID, it is provided if the DID is not resolvable (e.g peer DID).
+	// The attachment is signed with the key of the DID being replaced.
+	DocAttach *decorator.Attachment `json:"to_did_doc~attach,omitempty"`
+}
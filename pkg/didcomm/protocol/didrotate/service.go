@@ -0,0 +1,754 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+)
+
+// nolint:gochecknoglobals
+var logger = log.New("aries-framework/didrotate/service")
+
+const (
+	// DIDRotate defines the protocol name.
+	DIDRotate = "didrotate"
+	// PIURI is the DID rotate protocol identifier URI.
+	PIURI = "https://didcomm.org/did-rotate/1.0"
+	// RotateMsgType defines the protocol rotate message type.
+	RotateMsgType = PIURI + "/rotate"
+	// AckMsgType defines the protocol ack message type.
+	AckMsgType = PIURI + "/ack"
+	// ProblemReportMsgType defines the protocol problem report message type.
+	ProblemReportMsgType = PIURI + "/problem-report"
+)
+
+// Codes of the problem report sent when the rotation is rejected.
+const (
+	// CodeDIDUnresolvable the DID could not be resolved.
+	CodeDIDUnresolvable = "e.did.unresolvable"
+	// CodeDIDUnusable the DID doc has no DIDComm service.
+	CodeDIDUnusable = "e.did.unusable"
+)
+
+// States of the protocol reported by the message events.
+const (
+	// StateTheirDIDRotated the other agent rotated its DID.
+	StateTheirDIDRotated = "their-did-rotated"
+	// StateMyDIDRotated the other agent acknowledged the rotation of my DID.
+	StateMyDIDRotated = "my-did-rotated"
+	// StateRotationFailed the rotation was rejected.
+	StateRotationFailed = "rotation-failed"
+)
+
+const (
+	pendingKey            = "pending_"
+	didCommServiceType    = "did-communication"
+	peerDIDMethod         = "peer"
+	stateNameCompleted    = "completed"
+	defaultGracePeriod    = 24 * time.Hour
+	defaultReaperInterval = time.Minute
+)
+
+// ErrConnectionNotFound connection not found error.
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// Provider contains dependencies for the DID rotate protocol and is typically created by using aries.Context().
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	VDRegistry() vdrapi.Registry
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+	Service(id string) (interface{}, error)
+}
+
+// ServiceOption configures the service.
+type ServiceOption func(s *Service)
+
+// WithGracePeriod sets how long the connection is still found by the replaced DID (24 hours by default).
+// Messages which were in flight during the rotation are delivered within the grace period.
+func WithGracePeriod(gracePeriod time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.gracePeriod = gracePeriod
+	}
+}
+
+// WithReaperInterval sets how often the service removes the replaced DIDs whose grace period has expired
+// (one minute by default).
+func WithReaperInterval(interval time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.reaperInterval = interval
+	}
+}
+
+type connections interface {
+	GetConnectionIDByDIDs(string, string) (string, error)
+	GetConnectionRecord(string) (*connection.Record, error)
+	SaveConnectionRecord(*connection.Record) error
+	SaveDIDMapping(string, string, string) error
+	RemoveDIDMapping(string, string) error
+	QueryConnectionRecords() ([]*connection.Record, error)
+}
+
+type didConnections interface {
+	SaveDIDFromDoc(*did.Doc) error
+}
+
+// pending is the rotation of my DID waiting for the ack of the other agent.
+type pending struct {
+	ConnectionID string
+	PreviousDID  string
+	NewDID       string
+	TheirDID     string
+}
+
+type eventProps struct {
+	connectionID string
+	previousDID  string
+	newDID       string
+	err          error
+}
+
+func (e *eventProps) All() map[string]interface{} {
+	props := map[string]interface{}{
+		"connectionID": e.connectionID,
+		"previousDID":  e.previousDID,
+		"newDID":       e.newDID,
+	}
+
+	if e.err != nil {
+		props["error"] = e.err.Error()
+	}
+
+	return props
+}
+
+// Service for the DID rotate protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/main/features/0794-did-rotate
+type Service struct {
+	service.Action
+	service.Message
+	messenger       service.Messenger
+	vdRegistry      vdrapi.Registry
+	kms             kms.KeyManager
+	crypto          crypto.Crypto
+	routeSvc        mediator.ProtocolService
+	connectionStore connections
+	didConnections  didConnections
+	store           storage.Store
+	now             func() time.Time
+	gracePeriod     time.Duration
+	reaperInterval  time.Duration
+	lock            sync.Mutex
+	stop            chan struct{}
+	closeOnce       sync.Once
+}
+
+// New returns the DID rotate service.
+func New(p Provider, opts ...ServiceOption) (*Service, error) {
+	connectionStore, err := connection.NewRecorder(p)
+	if err != nil {
+		return nil, fmt.Errorf("new connection recorder: %w", err)
+	}
+
+	didConnections, err := didstore.NewConnectionStore(p)
+	if err != nil {
+		return nil, fmt.Errorf("new did connection store: %w", err)
+	}
+
+	store, err := p.ProtocolStateStorageProvider().OpenStore(DIDRotate)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	s, err := p.Service(mediator.Coordination)
+	if err != nil {
+		return nil, fmt.Errorf("lookup route service: %w", err)
+	}
+
+	routeSvc, ok := s.(mediator.ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to Route Service failed")
+	}
+
+	svc := &Service{
+		messenger:       p.Messenger(),
+		vdRegistry:      p.VDRegistry(),
+		kms:             p.KMS(),
+		crypto:          p.Crypto(),
+		routeSvc:        routeSvc,
+		connectionStore: connectionStore,
+		didConnections:  didConnections,
+		store:           store,
+		now:             time.Now,
+		gracePeriod:     defaultGracePeriod,
+		reaperInterval:  defaultReaperInterval,
+		stop:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	// start removing the replaced DIDs whose grace period has expired, until the service is closed
+	go svc.startReaper()
+
+	return svc, nil
+}
+
+// HandleInbound handles inbound DID rotate messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	logger.WithFields(service.LogFields(msg)).Debugf("service.HandleInbound() myDID=%s theirDID=%s", myDID, theirDID)
+
+	var err error
+
+	switch msg.Type() {
+	case RotateMsgType:
+		err = s.handleRotate(msg, myDID, theirDID)
+	case AckMsgType:
+		err = s.handleAck(msg, myDID, theirDID)
+	case ProblemReportMsgType:
+		err = s.handleProblemReport(msg, myDID, theirDID)
+	default:
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return msg.ID(), nil
+}
+
+// Close stops removing the replaced DIDs whose grace period has expired.
+func (s *Service) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+
+	return nil
+}
+
+// HandleOutbound is not supported, the rotation is started by RotateDID.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, _, _ string) (string, error) {
+	return "", fmt.Errorf("invalid or unsupported outbound message type %s", msg.Type())
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == RotateMsgType || msgType == AckMsgType || msgType == ProblemReportMsgType
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return DIDRotate
+}
+
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{PIURI}
+}
+
+// RotateDID replaces my DID on the completed connection identified by connectionID with newDID.
+// If newDID is empty, a new peer DID is created, its DIDComm service is routed through the given router
+// connections. The connection record is updated once the other agent acknowledges the rotation
+// (StateMyDIDRotated message event), the previous DID remains valid for the grace period.
+// Returns the thread ID of the rotation.
+func (s *Service) RotateDID(connectionID, newDID string, routerConnections []string) (string, error) {
+	record, err := s.getConnection(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("get connection: %w", err)
+	}
+
+	if record.State != stateNameCompleted {
+		return "", fmt.Errorf("connection is not completed: state %s", record.State)
+	}
+
+	doc, err := s.newDIDDoc(newDID, routerConnections)
+	if err != nil {
+		return "", err
+	}
+
+	if doc.ID == record.MyDID {
+		return "", errors.New("the new DID is the current DID of the connection")
+	}
+
+	rotate := &Rotate{
+		Type:  RotateMsgType,
+		ID:    uuid.New().String(),
+		ToDID: doc.ID,
+	}
+
+	if isPeerDID(doc.ID) {
+		if rotate.DocAttach, err = s.docAttachment(doc, record.MyDID); err != nil {
+			return "", err
+		}
+	}
+
+	if err = s.didConnections.SaveDIDFromDoc(doc); err != nil {
+		return "", fmt.Errorf("save DID from doc: %w", err)
+	}
+
+	// the connection is found by the new DID as soon as the other agent uses it
+	if err = s.connectionStore.SaveDIDMapping(doc.ID, record.TheirDID, connectionID); err != nil {
+		return "", err
+	}
+
+	removeMapping := func() error { return s.connectionStore.RemoveDIDMapping(doc.ID, record.TheirDID) }
+
+	if err = s.savePending(rotate.ID, &pending{
+		ConnectionID: connectionID,
+		PreviousDID:  record.MyDID,
+		NewDID:       doc.ID,
+		TheirDID:     record.TheirDID,
+	}); err != nil {
+		return "", rollback(err, removeMapping)
+	}
+
+	if err = s.messenger.Send(service.NewDIDCommMsgMap(rotate), record.MyDID, record.TheirDID); err != nil {
+		return "", rollback(fmt.Errorf("send rotate: %w", err), removeMapping,
+			func() error { return s.store.Delete(pendingKey + rotate.ID) })
+	}
+
+	return rotate.ID, nil
+}
+
+func (s *Service) newDIDDoc(newDID string, routerConnections []string) (*did.Doc, error) {
+	if newDID == "" {
+		// the DID doc is created the way the DID exchange does, the recipient keys are registered with the routers
+		return didexchange.CreatePeerDIDDoc(s.vdRegistry, s.routeSvc, routerConnections)
+	}
+
+	doc, err := s.vdRegistry.Resolve(newDID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID %s: %w", newDID, err)
+	}
+
+	return doc, nil
+}
+
+func (s *Service) handleRotate(msg service.DIDCommMsg, myDID, theirDID string) error {
+	rotate := &Rotate{}
+
+	if err := msg.Decode(rotate); err != nil {
+		return fmt.Errorf("rotate message unmarshal: %w", err)
+	}
+
+	connectionID, err := s.connectionStore.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return fmt.Errorf("connectionID lookup using DIDs: %w", err)
+	}
+
+	doc, code, err := s.rotatedDIDDoc(rotate, theirDID)
+	if err != nil {
+		s.sendMsgEvent(msg, StateRotationFailed, &eventProps{
+			connectionID: connectionID, previousDID: theirDID, newDID: rotate.ToDID, err: err,
+		})
+
+		return s.messenger.ReplyTo(msg.ID(), service.NewDIDCommMsgMap(&model.ProblemReport{
+			Type:        ProblemReportMsgType,
+			ID:          uuid.New().String(),
+			Description: model.Code{Code: code},
+		}))
+	}
+
+	previousDID, err := s.rotateTheirDID(connectionID, doc)
+	if err != nil {
+		return fmt.Errorf("rotate their DID: %w", err)
+	}
+
+	err = s.messenger.ReplyTo(msg.ID(), service.NewDIDCommMsgMap(&model.Ack{
+		Type:   AckMsgType,
+		ID:     uuid.New().String(),
+		Status: "OK",
+	}))
+	if err != nil {
+		return fmt.Errorf("send ack: %w", err)
+	}
+
+	s.sendMsgEvent(msg, StateTheirDIDRotated, &eventProps{
+		connectionID: connectionID, previousDID: previousDID, newDID: doc.ID,
+	})
+
+	return nil
+}
+
+// rotatedDIDDoc returns the DID doc of the rotated DID or the code of the problem report.
+func (s *Service) rotatedDIDDoc(rotate *Rotate, theirDID string) (*did.Doc, string, error) {
+	if rotate.ToDID == "" {
+		return nil, CodeDIDUnresolvable, errors.New("the rotate message has no DID")
+	}
+
+	var (
+		doc *did.Doc
+		err error
+	)
+
+	if rotate.DocAttach != nil {
+		doc, err = s.attachedDIDDoc(rotate, theirDID)
+	} else {
+		doc, err = s.vdRegistry.Resolve(rotate.ToDID)
+	}
+
+	if err != nil {
+		return nil, CodeDIDUnresolvable, err
+	}
+
+	if _, ok := did.LookupService(doc, didCommServiceType); !ok {
+		return nil, CodeDIDUnusable, fmt.Errorf("the DID doc of %s has no %s service", doc.ID, didCommServiceType)
+	}
+
+	if rotate.DocAttach != nil {
+		if err = s.vdRegistry.Store(doc); err != nil {
+			return nil, CodeDIDUnusable, fmt.Errorf("store DID doc: %w", err)
+		}
+	}
+
+	return doc, "", nil
+}
+
+// rotateTheirDID updates the connection record with the new DID of the other agent, the connection is found
+// by the previous DID until the grace period expires. The writes are rolled back if one of them fails.
+// Returns the previous DID.
+func (s *Service) rotateTheirDID(connectionID string, doc *did.Doc) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, err := s.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("get connection record: %w", err)
+	}
+
+	if err = s.didConnections.SaveDIDFromDoc(doc); err != nil {
+		return "", fmt.Errorf("save DID from doc: %w", err)
+	}
+
+	// the mapping of the new DID is saved first so the connection is found by either DID
+	if err = s.connectionStore.SaveDIDMapping(record.MyDID, doc.ID, connectionID); err != nil {
+		return "", err
+	}
+
+	removeMapping := func() error { return s.connectionStore.RemoveDIDMapping(record.MyDID, doc.ID) }
+
+	previous := *record
+
+	record.TheirDIDRotation = &connection.DIDRotation{PreviousDID: previous.TheirDID, Expires: s.now().Add(s.gracePeriod)}
+	record.TheirDID = doc.ID
+
+	if err = s.connectionStore.SaveConnectionRecord(record); err != nil {
+		return "", rollback(fmt.Errorf("save connection record: %w", err), removeMapping)
+	}
+
+	// the DID replaced by the earlier rotation is not found anymore
+	if err = s.expireRotation(previous.TheirDIDRotation, previous.MyDID, ""); err != nil {
+		return "", rollback(err, removeMapping, func() error { return s.connectionStore.SaveConnectionRecord(&previous) })
+	}
+
+	return previous.TheirDID, nil
+}
+
+func (s *Service) handleAck(msg service.DIDCommMsg, myDID, theirDID string) error {
+	thID, p, err := s.getPending(msg, myDID, theirDID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.rotateMyDID(p); err != nil {
+		return fmt.Errorf("rotate my DID: %w", err)
+	}
+
+	if err = s.store.Delete(pendingKey + thID); err != nil {
+		return fmt.Errorf("delete pending rotation: %w", err)
+	}
+
+	s.sendMsgEvent(msg, StateMyDIDRotated, &eventProps{
+		connectionID: p.ConnectionID, previousDID: p.PreviousDID, newDID: p.NewDID,
+	})
+
+	return nil
+}
+
+// rotateMyDID updates the connection record with my new DID, the mapping of the new DID was saved when
+// the rotation was sent. The connection record is restored if the earlier rotation cannot be expired.
+func (s *Service) rotateMyDID(p *pending) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, err := s.connectionStore.GetConnectionRecord(p.ConnectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	previous := *record
+
+	record.MyDIDRotation = &connection.DIDRotation{PreviousDID: p.PreviousDID, Expires: s.now().Add(s.gracePeriod)}
+	record.MyDID = p.NewDID
+
+	if err = s.connectionStore.SaveConnectionRecord(record); err != nil {
+		return fmt.Errorf("save connection record: %w", err)
+	}
+
+	if err = s.expireRotation(previous.MyDIDRotation, "", previous.TheirDID); err != nil {
+		return rollback(err, func() error { return s.connectionStore.SaveConnectionRecord(&previous) })
+	}
+
+	return nil
+}
+
+func (s *Service) handleProblemReport(msg service.DIDCommMsg, myDID, theirDID string) error {
+	report := &model.ProblemReport{}
+
+	if err := msg.Decode(report); err != nil {
+		return fmt.Errorf("problem report unmarshal: %w", err)
+	}
+
+	thID, p, err := s.getPending(msg, myDID, theirDID)
+	if err != nil {
+		return err
+	}
+
+	// the rotation was rejected, the connection keeps the current DID
+	if err = s.connectionStore.RemoveDIDMapping(p.NewDID, p.TheirDID); err != nil {
+		return err
+	}
+
+	if err = s.store.Delete(pendingKey + thID); err != nil {
+		return fmt.Errorf("delete pending rotation: %w", err)
+	}
+
+	s.sendMsgEvent(msg, StateRotationFailed, &eventProps{
+		connectionID: p.ConnectionID,
+		previousDID:  p.PreviousDID,
+		newDID:       p.NewDID,
+		err:          fmt.Errorf("problem report %s", report.Description.Code),
+	})
+
+	return nil
+}
+
+// expireRotation removes the mapping of the previous DID of the earlier rotation. Either myDID or theirDID
+// is given, the other one is the previous DID.
+func (s *Service) expireRotation(rotation *connection.DIDRotation, myDID, theirDID string) error {
+	if rotation == nil {
+		return nil
+	}
+
+	if myDID == "" {
+		return s.connectionStore.RemoveDIDMapping(rotation.PreviousDID, theirDID)
+	}
+
+	return s.connectionStore.RemoveDIDMapping(myDID, rotation.PreviousDID)
+}
+
+// rollback undoes the writes done before err in reverse order, the failures of the undo are logged.
+func rollback(err error, undo ...func() error) error {
+	for i := len(undo) - 1; i >= 0; i-- {
+		if e := undo[i](); e != nil {
+			logger.Errorf("roll back the DID rotation: %s", e)
+		}
+	}
+
+	return err
+}
+
+// startReaper periodically removes the replaced DIDs whose grace period has expired.
+func (s *Service) startReaper() {
+	ticker := time.NewTicker(s.reaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.removeExpired(); err != nil {
+				logger.Errorf("remove expired DIDs: %s", err)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Service) removeExpired() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	records, err := s.connectionStore.QueryConnectionRecords()
+	if err != nil {
+		return fmt.Errorf("query connection records: %w", err)
+	}
+
+	now := s.now()
+
+	for _, record := range records {
+		changed := false
+
+		if record.MyDIDRotation != nil && !record.MyDIDRotation.Expires.After(now) {
+			if err = s.expireRotation(record.MyDIDRotation, "", record.TheirDID); err != nil {
+				return err
+			}
+
+			record.MyDIDRotation, changed = nil, true
+		}
+
+		if record.TheirDIDRotation != nil && !record.TheirDIDRotation.Expires.After(now) {
+			if err = s.expireRotation(record.TheirDIDRotation, record.MyDID, ""); err != nil {
+				return err
+			}
+
+			record.TheirDIDRotation, changed = nil, true
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err = s.connectionStore.SaveConnectionRecord(record); err != nil {
+			return fmt.Errorf("save connection record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) savePending(thID string, p *pending) error {
+	src, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal pending rotation: %w", err)
+	}
+
+	if err = s.store.Put(pendingKey+thID, src); err != nil {
+		return fmt.Errorf("save pending rotation: %w", err)
+	}
+
+	return nil
+}
+
+// getPending returns the pending rotation of the thread of the message, the message must be received
+// on the connection of the rotation by either the previous or the new DID.
+func (s *Service) getPending(msg service.DIDCommMsg, myDID, theirDID string) (string, *pending, error) {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return "", nil, fmt.Errorf("threadID: %w", err)
+	}
+
+	src, err := s.store.Get(pendingKey + thID)
+	if err != nil {
+		return "", nil, fmt.Errorf("get pending rotation: %w", err)
+	}
+
+	p := &pending{}
+	if err = json.Unmarshal(src, p); err != nil {
+		return "", nil, fmt.Errorf("unmarshal pending rotation: %w", err)
+	}
+
+	if theirDID != p.TheirDID || (myDID != p.PreviousDID && myDID != p.NewDID) {
+		return "", nil, fmt.Errorf("the DIDs of the message do not match the pending rotation %s", thID)
+	}
+
+	return thID, p, nil
+}
+
+func (s *Service) sendMsgEvent(msg service.DIDCommMsg, state string, props *eventProps) {
+	stateMsg := service.StateMsg{
+		ProtocolName: DIDRotate,
+		Type:         service.PostState,
+		StateID:      state,
+		Msg:          msg,
+		Properties:   props,
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
+
+// docAttachment returns the DID doc attachment signed with the key of the DID being replaced, the signature
+// binds the new DID to the previous one.
+func (s *Service) docAttachment(doc *did.Doc, previousDID string) (*decorator.Attachment, error) {
+	keys, err := s.recipientKeys(previousDID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := didexchange.SignDIDDocAttachment(s.kms, s.crypto, doc, keys[0])
+	if err != nil {
+		return nil, fmt.Errorf("sign DID doc attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// attachedDIDDoc returns the attached DID doc, the attachment must be signed with a key of the DID being replaced.
+func (s *Service) attachedDIDDoc(rotate *Rotate, previousDID string) (*did.Doc, error) {
+	keys, err := s.recipientKeys(previousDID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := didexchange.VerifyDIDDocAttachment(rotate.DocAttach, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("verify DID doc attachment: %w", err)
+	}
+
+	if doc.ID != rotate.ToDID {
+		return nil, fmt.Errorf("the attached DID doc %s does not match the DID %s", doc.ID, rotate.ToDID)
+	}
+
+	return doc, nil
+}
+
+// recipientKeys returns the recipient keys of the DIDComm service of the DID.
+func (s *Service) recipientKeys(didID string) ([]string, error) {
+	doc, err := s.vdRegistry.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+	}
+
+	svc, ok := did.LookupService(doc, didCommServiceType)
+	if !ok || len(svc.RecipientKeys) == 0 {
+		return nil, fmt.Errorf("the DID doc of %s has no recipient keys", didID)
+	}
+
+	return svc.RecipientKeys, nil
+}
+
+func isPeerDID(str string) bool {
+	return strings.HasPrefix(str, "did:"+peerDIDMethod+":")
+}
@@ -0,0 +1,998 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockprotocol "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "did:peer:mydid"
+	THEIRDID = "did:peer:theirdid"
+	NEWDID   = "did:peer:newdid"
+	connID   = "conn-id"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(newProvider(t, nil))
+		require.NoError(t, err)
+		require.Equal(t, DIDRotate, svc.Name())
+		require.Equal(t, []string{PIURI}, svc.Protocols())
+	})
+
+	t.Run("test new service - connection recorder error", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.storageProvider = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection recorder")
+	})
+
+	t.Run("test new service - open store error", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.protocolStateStorage = &mockstore.MockStoreProvider{
+			Store: &mockstore.MockStore{Store: make(map[string][]byte)}, FailNamespace: DIDRotate,
+		}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open store")
+	})
+
+	t.Run("test new service - route service error", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.serviceErr = errors.New("service error")
+
+		_, err := New(prov)
+		require.EqualError(t, err, "lookup route service: service error")
+	})
+
+	t.Run("test new service - options", func(t *testing.T) {
+		svc, err := New(newProvider(t, nil), WithGracePeriod(time.Hour), WithReaperInterval(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, svc.gracePeriod)
+		require.Equal(t, time.Second, svc.reaperInterval)
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	svc, err := New(newProvider(t, nil))
+	require.NoError(t, err)
+
+	require.True(t, svc.Accept(RotateMsgType))
+	require.True(t, svc.Accept(AckMsgType))
+	require.True(t, svc.Accept(ProblemReportMsgType))
+	require.False(t, svc.Accept("unsupported"))
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	svc, err := New(newProvider(t, nil))
+	require.NoError(t, err)
+
+	_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&Rotate{Type: RotateMsgType}), MYDID, THEIRDID)
+	require.EqualError(t, err, "invalid or unsupported outbound message type "+RotateMsgType)
+}
+
+func TestService_RotateDID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("rotate to the new peer DID - success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(t, messenger)
+		prov.vdr.CreateValue = newDIDDoc(NEWDID)
+		saveConnection(t, prov)
+
+		svc, err := New(prov, WithGracePeriod(time.Hour))
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		var threadID string
+
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				rotate := &Rotate{}
+				require.NoError(t, msg.Decode(rotate))
+				require.Equal(t, RotateMsgType, rotate.Type)
+				require.Equal(t, NEWDID, rotate.ToDID)
+
+				// the new DID doc is signed with the key of the previous DID
+				_, err := didexchange.VerifyDIDDocAttachment(rotate.DocAttach, recipientKey(prov, MYDID))
+				require.NoError(t, err)
+
+				threadID = rotate.ID
+
+				return nil
+			})
+
+		thID, err := svc.RotateDID(connID, "", nil)
+		require.NoError(t, err)
+		require.Equal(t, threadID, thID)
+
+		// the connection is found by the new DID before the ack is received
+		id, err := svc.connectionStore.GetConnectionIDByDIDs(NEWDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, connID, id)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&model.Ack{
+			Type:   AckMsgType,
+			ID:     "ack-id",
+			Thread: &decorator.Thread{ID: thID},
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		select {
+		case event := <-events:
+			require.Equal(t, DIDRotate, event.ProtocolName)
+			require.Equal(t, StateMyDIDRotated, event.StateID)
+			require.Equal(t, connID, event.Properties.All()["connectionID"])
+			require.Equal(t, MYDID, event.Properties.All()["previousDID"])
+			require.Equal(t, NEWDID, event.Properties.All()["newDID"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		record, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, NEWDID, record.MyDID)
+		require.Equal(t, MYDID, record.MyDIDRotation.PreviousDID)
+
+		// the previous DID remains valid for the grace period
+		id, err = svc.connectionStore.GetConnectionIDByDIDs(MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, connID, id)
+
+		_, err = svc.store.Get(pendingKey + thID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("rotate to the new peer DID routed through the router", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		var routerKeys []string
+
+		prov := newProvider(t, messenger)
+		prov.routeSvc.RouterEndpoint = "http://router.example.com"
+		prov.routeSvc.RoutingKeys = []string{"routing-key"}
+		prov.routeSvc.AddKeyFunc = func(recKey string) error {
+			routerKeys = append(routerKeys, recKey)
+
+			return nil
+		}
+		prov.vdr.CreateFunc = func(method string, opts ...vdrapi.DocOpts) (*did.Doc, error) {
+			docOpts := &vdrapi.CreateDIDOpts{}
+			for _, opt := range opts {
+				opt(docOpts)
+			}
+
+			require.Equal(t, peerDIDMethod, method)
+			require.Len(t, docOpts.Services, 1)
+			require.Equal(t, "http://router.example.com", docOpts.Services[0].ServiceEndpoint)
+			require.Equal(t, []string{"routing-key"}, docOpts.Services[0].RoutingKeys)
+
+			return newDIDDoc(NEWDID), nil
+		}
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", []string{"router-conn"})
+		require.NoError(t, err)
+		require.Equal(t, newDIDDoc(NEWDID).Service[0].RecipientKeys, routerKeys)
+	})
+
+	t.Run("router error", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.routeSvc.ConfigErr = errors.New("config error")
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", []string{"router-conn"})
+		require.EqualError(t, err, "did doc - fetch router config: fetch router config: config error")
+	})
+
+	t.Run("rotate to the public DID - success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(t, messenger)
+		prov.vdr.ResolveValue = newDIDDoc("did:example:public")
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				rotate := &Rotate{}
+				require.NoError(t, msg.Decode(rotate))
+				require.Equal(t, "did:example:public", rotate.ToDID)
+				require.Nil(t, rotate.DocAttach)
+
+				return nil
+			})
+
+		_, err = svc.RotateDID(connID, "did:example:public", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(t, nil))
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", nil)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("connection not completed", func(t *testing.T) {
+		prov := newProvider(t, nil)
+
+		r, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+		require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+			ConnectionID: connID, MyDID: MYDID, TheirDID: THEIRDID, State: "requested",
+		}))
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", nil)
+		require.EqualError(t, err, "connection is not completed: state requested")
+	})
+
+	t.Run("create DID error", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.vdr.CreateErr = errors.New("create error")
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", nil)
+		require.EqualError(t, err, "create peer did: create error")
+	})
+
+	t.Run("resolve DID error", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.vdr.ResolveErr = errors.New("resolve error")
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "did:example:public", nil)
+		require.EqualError(t, err, "resolve DID did:example:public: resolve error")
+	})
+
+	t.Run("the new DID is the current DID", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.vdr.ResolveValue = newDIDDoc(MYDID)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, MYDID, nil)
+		require.EqualError(t, err, "the new DID is the current DID of the connection")
+	})
+
+	t.Run("previous DID without recipient keys", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		prov.vdr.CreateValue = newDIDDoc(NEWDID)
+		prov.vdr.MemStore[MYDID].Service = nil
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", nil)
+		require.EqualError(t, err, "the DID doc of "+MYDID+" has no recipient keys")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("send error"))
+
+		prov := newProvider(t, messenger)
+		prov.vdr.CreateValue = newDIDDoc(NEWDID)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.RotateDID(connID, "", nil)
+		require.EqualError(t, err, "send rotate: send error")
+
+		// the rotation is rolled back
+		_, err = svc.connectionStore.GetConnectionIDByDIDs(NEWDID, THEIRDID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		iter := svc.store.Iterator(pendingKey, pendingKey+storage.EndKeySuffix)
+		require.False(t, iter.Next())
+		iter.Release()
+	})
+}
+
+func TestService_HandleRotate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("rotate with the attached DID doc - success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(t, messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov, WithGracePeriod(time.Hour))
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		messenger.EXPECT().ReplyTo("rotate-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				require.Equal(t, AckMsgType, msg.Type())
+
+				return nil
+			})
+
+		// the other agent signs the new DID doc with the key of its previous DID
+		attachment, err := svc.docAttachment(newDIDDoc(NEWDID), THEIRDID)
+		require.NoError(t, err)
+
+		msgID, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:      RotateMsgType,
+			ID:        "rotate-id",
+			ToDID:     NEWDID,
+			DocAttach: attachment,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "rotate-id", msgID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, StateTheirDIDRotated, event.StateID)
+			require.Equal(t, connID, event.Properties.All()["connectionID"])
+			require.Equal(t, THEIRDID, event.Properties.All()["previousDID"])
+			require.Equal(t, NEWDID, event.Properties.All()["newDID"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		record, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, NEWDID, record.TheirDID)
+		require.Equal(t, THEIRDID, record.TheirDIDRotation.PreviousDID)
+		require.WithinDuration(t, svc.now().Add(time.Hour), record.TheirDIDRotation.Expires, time.Minute)
+
+		for _, theirDID := range []string{THEIRDID, NEWDID} {
+			id, err := svc.connectionStore.GetConnectionIDByDIDs(MYDID, theirDID)
+			require.NoError(t, err)
+			require.Equal(t, connID, id)
+		}
+
+		require.NotNil(t, prov.vdr.MemStore[NEWDID])
+	})
+
+	t.Run("rotate to the public DID - success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("rotate-id", gomock.Any()).Return(nil)
+
+		prov := newProvider(t, messenger)
+		prov.vdr.ResolveValue = newDIDDoc("did:example:public")
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:  RotateMsgType,
+			ID:    "rotate-id",
+			ToDID: "did:example:public",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		record, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, "did:example:public", record.TheirDID)
+	})
+
+	t.Run("unresolvable DID - problem report", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("rotate-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				report := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(report))
+				require.Equal(t, ProblemReportMsgType, report.Type)
+				require.Equal(t, CodeDIDUnresolvable, report.Description.Code)
+
+				return nil
+			})
+
+		prov := newProvider(t, messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:  RotateMsgType,
+			ID:    "rotate-id",
+			ToDID: "did:example:unknown",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		select {
+		case event := <-events:
+			require.Equal(t, StateRotationFailed, event.StateID)
+			require.Contains(t, event.Properties.All()["error"], "DID not found")
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		record, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, THEIRDID, record.TheirDID)
+		require.Nil(t, record.TheirDIDRotation)
+	})
+
+	t.Run("DID doc without DIDComm service - problem report", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("rotate-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				report := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(report))
+				require.Equal(t, CodeDIDUnusable, report.Description.Code)
+
+				return nil
+			})
+
+		doc := newDIDDoc("did:example:public")
+		doc.Service = nil
+
+		prov := newProvider(t, messenger)
+		prov.vdr.ResolveValue = doc
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:  RotateMsgType,
+			ID:    "rotate-id",
+			ToDID: "did:example:public",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("attached DID doc does not match the DID - problem report", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("rotate-id", gomock.Any()).Return(nil)
+
+		prov := newProvider(t, messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		attachment, err := svc.docAttachment(newDIDDoc("did:peer:other"), THEIRDID)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:      RotateMsgType,
+			ID:        "rotate-id",
+			ToDID:     NEWDID,
+			DocAttach: attachment,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		event := <-events
+		require.Equal(t, "the attached DID doc did:peer:other does not match the DID "+NEWDID,
+			event.Properties.All()["error"])
+	})
+
+	t.Run("attached DID doc not signed by the previous DID - problem report", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("rotate-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				report := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(report))
+				require.Equal(t, CodeDIDUnresolvable, report.Description.Code)
+
+				return nil
+			})
+
+		prov := newProvider(t, messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		// signed with the key of my DID instead of the DID being replaced
+		attachment, err := svc.docAttachment(newDIDDoc(NEWDID), MYDID)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:      RotateMsgType,
+			ID:        "rotate-id",
+			ToDID:     NEWDID,
+			DocAttach: attachment,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		event := <-events
+		require.Equal(t, "verify DID doc attachment: the DID doc was not signed with the expected key",
+			event.Properties.All()["error"])
+
+		record, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, THEIRDID, record.TheirDID)
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(t, nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:  RotateMsgType,
+			ID:    "rotate-id",
+			ToDID: NEWDID,
+		}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connectionID lookup using DIDs")
+	})
+
+	t.Run("ack error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).Return(errors.New("reply error"))
+
+		prov := newProvider(t, messenger)
+		prov.vdr.ResolveValue = newDIDDoc("did:example:public")
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:  RotateMsgType,
+			ID:    "rotate-id",
+			ToDID: "did:example:public",
+		}), MYDID, THEIRDID)
+		require.EqualError(t, err, "send ack: reply error")
+	})
+}
+
+func TestService_HandleProblemReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+	messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+	prov := newProvider(t, messenger)
+	prov.vdr.CreateValue = newDIDDoc(NEWDID)
+	saveConnection(t, prov)
+
+	svc, err := New(prov)
+	require.NoError(t, err)
+
+	events := make(chan service.StateMsg, 1)
+	require.NoError(t, svc.RegisterMsgEvent(events))
+
+	thID, err := svc.RotateDID(connID, "", nil)
+	require.NoError(t, err)
+
+	_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&struct {
+		model.ProblemReport
+		Thread *decorator.Thread `json:"~thread"`
+	}{
+		ProblemReport: model.ProblemReport{
+			Type:        ProblemReportMsgType,
+			ID:          "report-id",
+			Description: model.Code{Code: CodeDIDUnusable},
+		},
+		Thread: &decorator.Thread{ID: thID},
+	}), MYDID, THEIRDID)
+	require.NoError(t, err)
+
+	select {
+	case event := <-events:
+		require.Equal(t, StateRotationFailed, event.StateID)
+		require.Equal(t, "problem report "+CodeDIDUnusable, event.Properties.All()["error"])
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the message event")
+	}
+
+	_, err = svc.connectionStore.GetConnectionIDByDIDs(NEWDID, THEIRDID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	record, err := svc.connectionStore.GetConnectionRecord(connID)
+	require.NoError(t, err)
+	require.Equal(t, MYDID, record.MyDID)
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	svc, err := New(newProvider(t, nil))
+	require.NoError(t, err)
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&model.Ack{Type: "unsupported"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unsupported message type unsupported")
+	})
+
+	t.Run("ack for the unknown rotation", func(t *testing.T) {
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&model.Ack{
+			Type:   AckMsgType,
+			ID:     "ack-id",
+			Thread: &decorator.Thread{ID: "unknown"},
+		}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get pending rotation")
+	})
+
+	t.Run("ack from another connection", func(t *testing.T) {
+		require.NoError(t, svc.savePending("thread-id", &pending{
+			ConnectionID: connID, PreviousDID: MYDID, NewDID: NEWDID, TheirDID: THEIRDID,
+		}))
+
+		for _, dids := range [][2]string{{"did:peer:other", THEIRDID}, {MYDID, "did:peer:other"}} {
+			for _, msgType := range []string{AckMsgType, ProblemReportMsgType} {
+				_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&model.Ack{
+					Type:   msgType,
+					ID:     "msg-id",
+					Thread: &decorator.Thread{ID: "thread-id"},
+				}), dids[0], dids[1])
+				require.EqualError(t, err, "the DIDs of the message do not match the pending rotation thread-id")
+			}
+		}
+
+		_, err = svc.store.Get(pendingKey + "thread-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("ack without thread", func(t *testing.T) {
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&model.Ack{Type: AckMsgType}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "threadID")
+	})
+}
+
+func TestService_RotateRollback(t *testing.T) {
+	t.Run("their DID", func(t *testing.T) {
+		prov := newProvider(t, nil)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		connections := svc.connectionStore
+		svc.connectionStore = &failingConnections{connections: connections, saveErr: errors.New("save error")}
+
+		_, err = svc.rotateTheirDID(connID, newDIDDoc(NEWDID))
+		require.EqualError(t, err, "save connection record: save error")
+
+		_, err = connections.GetConnectionIDByDIDs(MYDID, NEWDID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		record, err := connections.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, THEIRDID, record.TheirDID)
+	})
+
+	t.Run("their DID with the earlier rotation", func(t *testing.T) {
+		prov := newProvider(t, nil)
+
+		r, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+		require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+			ConnectionID:     connID,
+			MyDID:            MYDID,
+			TheirDID:         THEIRDID,
+			State:            stateNameCompleted,
+			TheirDIDRotation: &connection.DIDRotation{PreviousDID: "did:peer:oldtheirdid", Expires: time.Now()},
+		}))
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		connections := svc.connectionStore
+		svc.connectionStore = &failingConnections{connections: connections, removeErr: errors.New("remove error")}
+
+		_, err = svc.rotateTheirDID(connID, newDIDDoc(NEWDID))
+		require.EqualError(t, err, "remove error")
+
+		record, err := connections.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, THEIRDID, record.TheirDID)
+		require.Equal(t, "did:peer:oldtheirdid", record.TheirDIDRotation.PreviousDID)
+	})
+
+	t.Run("my DID", func(t *testing.T) {
+		prov := newProvider(t, nil)
+
+		r, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+		require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+			ConnectionID:  connID,
+			MyDID:         MYDID,
+			TheirDID:      THEIRDID,
+			State:         stateNameCompleted,
+			MyDIDRotation: &connection.DIDRotation{PreviousDID: "did:peer:oldmydid", Expires: time.Now()},
+		}))
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		connections := svc.connectionStore
+		svc.connectionStore = &failingConnections{connections: connections, removeErr: errors.New("remove error")}
+
+		err = svc.rotateMyDID(&pending{ConnectionID: connID, PreviousDID: MYDID, NewDID: NEWDID, TheirDID: THEIRDID})
+		require.EqualError(t, err, "remove error")
+
+		record, err := connections.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, MYDID, record.MyDID)
+		require.Equal(t, "did:peer:oldmydid", record.MyDIDRotation.PreviousDID)
+	})
+}
+
+func TestService_Close(t *testing.T) {
+	svc, err := New(newProvider(t, nil), WithReaperInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Close())
+	require.NoError(t, svc.Close())
+
+	select {
+	case <-svc.stop:
+	default:
+		t.Fatal("the reaper is not stopped")
+	}
+}
+
+func TestService_RemoveExpired(t *testing.T) {
+	prov := newProvider(t, nil)
+
+	r, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	require.NoError(t, r.SaveDIDMapping("did:peer:oldmydid", THEIRDID, connID))
+	require.NoError(t, r.SaveDIDMapping(MYDID, "did:peer:oldtheirdid", connID))
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID:     connID,
+		MyDID:            MYDID,
+		TheirDID:         THEIRDID,
+		State:            stateNameCompleted,
+		MyDIDRotation:    &connection.DIDRotation{PreviousDID: "did:peer:oldmydid", Expires: now.Add(-time.Minute)},
+		TheirDIDRotation: &connection.DIDRotation{PreviousDID: "did:peer:oldtheirdid", Expires: now.Add(time.Hour)},
+	}))
+
+	svc, err := New(prov)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now }
+
+	require.NoError(t, svc.removeExpired())
+
+	record, err := r.GetConnectionRecord(connID)
+	require.NoError(t, err)
+	require.Nil(t, record.MyDIDRotation)
+	require.NotNil(t, record.TheirDIDRotation)
+
+	_, err = r.GetConnectionIDByDIDs("did:peer:oldmydid", THEIRDID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	_, err = r.GetConnectionIDByDIDs(MYDID, "did:peer:oldtheirdid")
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now.Add(2 * time.Hour) }
+
+	require.NoError(t, svc.removeExpired())
+
+	record, err = r.GetConnectionRecord(connID)
+	require.NoError(t, err)
+	require.Nil(t, record.TheirDIDRotation)
+
+	_, err = r.GetConnectionIDByDIDs(MYDID, "did:peer:oldtheirdid")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+}
+
+func newDIDDoc(id string) *did.Doc {
+	doc := mockdiddoc.GetMockDIDDoc()
+	doc.ID = id
+
+	return doc
+}
+
+func saveConnection(t *testing.T, prov *provider) {
+	t.Helper()
+
+	r, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connID, MyDID: MYDID, TheirDID: THEIRDID, State: stateNameCompleted,
+	}))
+}
+
+func newProvider(t *testing.T, messenger service.Messenger) *provider {
+	t.Helper()
+
+	storageProvider := mockstore.NewMockStoreProvider()
+
+	km, err := localkms.New("local-lock://primary/test/", &mockprotocol.MockProvider{
+		StoreProvider: storageProvider,
+		CustomLock:    &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	p := &provider{
+		messenger:            messenger,
+		storageProvider:      storageProvider,
+		protocolStateStorage: mockstore.NewMockStoreProvider(),
+		vdr:                  &mockvdr.MockVDRegistry{},
+		kms:                  km,
+		routeSvc:             &mockroute.MockMediatorSvc{},
+	}
+
+	// the DID docs of the connection are resolved from the store, the keys are in the KMS
+	for _, didID := range []string{MYDID, THEIRDID} {
+		_, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519)
+		require.NoError(t, err)
+
+		doc := newDIDDoc(didID)
+		doc.Service = []did.Service{{
+			Type:            didCommServiceType,
+			ServiceEndpoint: "http://localhost:8090",
+			RecipientKeys:   []string{base58.Encode(pubKey)},
+		}}
+
+		require.NoError(t, p.vdr.Store(doc))
+	}
+
+	p.vdr.ResolveFunc = func(didID string, _ ...vdrapi.ResolveOpts) (*did.Doc, error) {
+		if doc, ok := p.vdr.MemStore[didID]; ok {
+			return doc, nil
+		}
+
+		if p.vdr.ResolveErr != nil {
+			return nil, p.vdr.ResolveErr
+		}
+
+		if p.vdr.ResolveValue == nil {
+			return nil, vdrapi.ErrNotFound
+		}
+
+		return p.vdr.ResolveValue, nil
+	}
+
+	return p
+}
+
+func recipientKey(p *provider, didID string) string {
+	return p.vdr.MemStore[didID].Service[0].RecipientKeys[0]
+}
+
+type provider struct {
+	messenger            service.Messenger
+	storageProvider      storage.Provider
+	protocolStateStorage storage.Provider
+	vdr                  *mockvdr.MockVDRegistry
+	kms                  kms.KeyManager
+	routeSvc             *mockroute.MockMediatorSvc
+	serviceErr           error
+}
+
+func (p *provider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+func (p *provider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *provider) ProtocolStateStorageProvider() storage.Provider {
+	return p.protocolStateStorage
+}
+
+func (p *provider) VDRegistry() vdrapi.Registry {
+	return p.vdr
+}
+
+func (p *provider) KMS() kms.KeyManager {
+	return p.kms
+}
+
+func (p *provider) Crypto() crypto.Crypto {
+	return &tinkcrypto.Crypto{}
+}
+
+func (p *provider) Service(id string) (interface{}, error) {
+	if p.serviceErr != nil {
+		return nil, p.serviceErr
+	}
+
+	if id != mediator.Coordination {
+		return nil, fmt.Errorf("service %s not found", id)
+	}
+
+	return p.routeSvc, nil
+}
+
+// failingConnections fails the first connection record save or DID mapping removal.
+type failingConnections struct {
+	connections
+	saveErr   error
+	removeErr error
+}
+
+func (c *failingConnections) SaveConnectionRecord(record *connection.Record) error {
+	if err := c.saveErr; err != nil {
+		c.saveErr = nil
+
+		return err
+	}
+
+	return c.connections.SaveConnectionRecord(record)
+}
+
+func (c *failingConnections) RemoveDIDMapping(myDID, theirDID string) error {
+	if err := c.removeErr; err != nil {
+		c.removeErr = nil
+
+		return err
+	}
+
+	return c.connections.RemoveDIDMapping(myDID, theirDID)
+}
@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Query message asks the other agent which protocols it supports.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features#query-message-type
+type Query struct {
+	Type    string `json:"@type,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Query   string `json:"query,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Disclose message lists the protocols matching the query.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features#disclose-message-type
+type Disclose struct {
+	Type      string               `json:"@type,omitempty"`
+	ID        string               `json:"@id,omitempty"`
+	Thread    *decorator.Thread    `json:"~thread,omitempty"`
+	Protocols []ProtocolDescriptor `json:"protocols"`
+}
+
+// ProtocolDescriptor describes the supported protocol.
+type ProtocolDescriptor struct {
+	PID   string   `json:"pid"`
+	Roles []string `json:"roles,omitempty"`
+}
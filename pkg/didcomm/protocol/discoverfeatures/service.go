@@ -0,0 +1,423 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+var logger = log.New("aries-framework/discoverfeatures/service")
+
+const (
+	// Name defines the protocol name.
+	Name = "discover-features"
+	// PIURI is the discover-features protocol identifier URI.
+	PIURI = "https://didcomm.org/discover-features/1.0"
+	// QueryMsgType defines the protocol query message type.
+	QueryMsgType = PIURI + "/query"
+	// DiscloseMsgType defines the protocol disclose message type.
+	DiscloseMsgType = PIURI + "/disclose"
+)
+
+// States of the protocol reported by the message events.
+const (
+	// StateQueryReceived the query was received and the disclose message was sent back.
+	StateQueryReceived = "query-received"
+	// StateDiscloseReceived the disclose message was received.
+	StateDiscloseReceived = "disclose-received"
+)
+
+const (
+	// Wildcard matches any sequence of characters in the query.
+	Wildcard = "*"
+
+	queryTimeout = 10 * time.Second
+)
+
+// ErrConnectionNotFound connection not found error.
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// Provider contains dependencies for the discover-features protocol and is typically created by using aries.Context().
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	ProtocolServices() []dispatcher.ProtocolService
+	MessageServiceProvider() api.MessageServiceProvider
+}
+
+// ProtocolDescriber is implemented by protocol and message services which disclose the protocols they
+// support in response to the queries. Services which do not implement it are never disclosed.
+type ProtocolDescriber interface {
+	// Protocols returns the protocol identifier URIs handled by the service.
+	Protocols() []string
+}
+
+// DisclosurePolicy decides which of the protocols matching the query are disclosed to the requester.
+// The default policy discloses all of them.
+type DisclosurePolicy func(query *Query, myDID, theirDID string, protocols []ProtocolDescriptor) []ProtocolDescriptor
+
+// ClientOption configures the discover-features client.
+type ClientOption func(opts *ClientOptions)
+
+// ClientOptions holds options for the discover-features client.
+type ClientOptions struct {
+	Timeout time.Duration
+}
+
+type connections interface {
+	GetConnectionRecord(string) (*connection.Record, error)
+}
+
+type eventProps struct {
+	myDID    string
+	theirDID string
+}
+
+func (e *eventProps) All() map[string]interface{} {
+	return map[string]interface{}{
+		"myDID":    e.myDID,
+		"theirDID": e.theirDID,
+	}
+}
+
+// Service for the discover-features protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features
+type Service struct {
+	service.Action
+	service.Message
+	messenger        service.Messenger
+	connectionLookup connections
+	provider         Provider
+	policy           DisclosurePolicy
+	policyLock       sync.RWMutex
+	discloseMap      map[string]chan *Disclose
+	discloseMapLock  sync.RWMutex
+}
+
+// New returns the discover-features service.
+func New(p Provider) (*Service, error) {
+	connectionLookup, err := connection.NewLookup(p)
+	if err != nil {
+		return nil, fmt.Errorf("new connection lookup: %w", err)
+	}
+
+	return &Service{
+		messenger:        p.Messenger(),
+		connectionLookup: connectionLookup,
+		provider:         p,
+		discloseMap:      make(map[string]chan *Disclose),
+	}, nil
+}
+
+// SetDisclosurePolicy sets the policy which filters the protocols disclosed to other agents.
+// Passing nil restores the default policy which discloses all the protocols matching the query.
+func (s *Service) SetDisclosurePolicy(policy DisclosurePolicy) {
+	s.policyLock.Lock()
+	defer s.policyLock.Unlock()
+
+	s.policy = policy
+}
+
+// HandleInbound handles inbound discover-features messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	logger.WithFields(service.LogFields(msg)).Debugf("service.HandleInbound() myDID=%s theirDID=%s", myDID, theirDID)
+
+	var (
+		state string
+		err   error
+	)
+
+	switch msg.Type() {
+	case QueryMsgType:
+		state, err = StateQueryReceived, s.handleQuery(msg, myDID, theirDID)
+	case DiscloseMsgType:
+		state, err = StateDiscloseReceived, s.handleDisclose(msg)
+	default:
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	s.sendMsgEvent(msg, state, myDID, theirDID)
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound sends the query message. Responses are delivered as message events.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if msg.Type() != QueryMsgType {
+		return "", fmt.Errorf("invalid or unsupported outbound message type %s", msg.Type())
+	}
+
+	msgMap := msg.Clone()
+
+	if err := s.messenger.Send(msgMap, myDID, theirDID); err != nil {
+		return "", fmt.Errorf("send query: %w", err)
+	}
+
+	return msgMap.ID(), nil
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == QueryMsgType || msgType == DiscloseMsgType
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return Name
+}
+
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{PIURI}
+}
+
+// Query asks the agent on the other end of the connection identified by connectionID which protocols
+// matching the query it supports. The query may contain wildcards (e.g 'https://didcomm.org/*').
+// This method blocks until the disclose message is received or it times out.
+func (s *Service) Query(connectionID, query string, options ...ClientOption) ([]ProtocolDescriptor, error) {
+	record, err := s.getConnection(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection: %w", err)
+	}
+
+	opts := parseClientOpts(options...)
+
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	discloseCh := make(chan *Disclose, 1)
+	s.setDiscloseCh(msgID, discloseCh)
+
+	// remove the channel once its been processed
+	defer s.setDiscloseCh(msgID, nil)
+
+	err = s.messenger.Send(service.NewDIDCommMsgMap(&Query{
+		Type:  QueryMsgType,
+		ID:    msgID,
+		Query: query,
+	}), record.MyDID, record.TheirDID)
+	if err != nil {
+		return nil, fmt.Errorf("send query: %w", err)
+	}
+
+	select {
+	case disclose := <-discloseCh:
+		return disclose.Protocols, nil
+	case <-time.After(opts.Timeout):
+		return nil, errors.New("timeout waiting for disclose message")
+	}
+}
+
+// Features returns the protocols supported by the agent which match the query (wildcards are allowed).
+func (s *Service) Features(query string) ([]ProtocolDescriptor, error) {
+	matcher, err := compileQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptors []ProtocolDescriptor
+
+	for _, pid := range s.protocols() {
+		if matcher.MatchString(pid) {
+			descriptors = append(descriptors, ProtocolDescriptor{PID: pid})
+		}
+	}
+
+	return descriptors, nil
+}
+
+func (s *Service) handleQuery(msg service.DIDCommMsg, myDID, theirDID string) error {
+	query := &Query{}
+
+	if err := msg.Decode(query); err != nil {
+		return fmt.Errorf("query message unmarshal: %w", err)
+	}
+
+	descriptors, err := s.Features(query.Query)
+	if err != nil {
+		return fmt.Errorf("features: %w", err)
+	}
+
+	s.policyLock.RLock()
+	policy := s.policy
+	s.policyLock.RUnlock()
+
+	if policy != nil {
+		descriptors = policy(query, myDID, theirDID, descriptors)
+	}
+
+	if descriptors == nil {
+		descriptors = []ProtocolDescriptor{}
+	}
+
+	return s.messenger.ReplyTo(msg.ID(), service.NewDIDCommMsgMap(&Disclose{
+		Type:      DiscloseMsgType,
+		ID:        uuid.New().String(),
+		Protocols: descriptors,
+	}))
+}
+
+func (s *Service) handleDisclose(msg service.DIDCommMsg) error {
+	disclose := &Disclose{}
+
+	if err := msg.Decode(disclose); err != nil {
+		return fmt.Errorf("disclose message unmarshal: %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("threadID: %w", err)
+	}
+
+	// check if there are any channels registered for the thread ID
+	if discloseCh := s.getDiscloseCh(thID); discloseCh != nil {
+		select {
+		case discloseCh <- disclose:
+		default:
+			logger.WithFields(service.LogFields(msg)).Warnf("ignoring duplicate disclose message")
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) sendMsgEvent(msg service.DIDCommMsg, state, myDID, theirDID string) {
+	stateMsg := service.StateMsg{
+		ProtocolName: Name,
+		Type:         service.PostState,
+		StateID:      state,
+		Msg:          msg,
+		Properties:   &eventProps{myDID: myDID, theirDID: theirDID},
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+}
+
+// protocols returns the sorted and deduplicated protocols of the registered protocol and message services.
+func (s *Service) protocols() []string {
+	var describers []interface{}
+
+	for _, svc := range s.provider.ProtocolServices() {
+		describers = append(describers, svc)
+	}
+
+	if msgSvcProvider := s.provider.MessageServiceProvider(); msgSvcProvider != nil {
+		for _, svc := range msgSvcProvider.Services() {
+			describers = append(describers, svc)
+		}
+	}
+
+	unique := make(map[string]struct{})
+
+	for _, svc := range describers {
+		describer, ok := svc.(ProtocolDescriber)
+		if !ok {
+			continue
+		}
+
+		for _, pid := range describer.Protocols() {
+			unique[pid] = struct{}{}
+		}
+	}
+
+	protocols := make([]string, 0, len(unique))
+
+	for pid := range unique {
+		protocols = append(protocols, pid)
+	}
+
+	sort.Strings(protocols)
+
+	return protocols
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
+
+func (s *Service) getDiscloseCh(thID string) chan *Disclose {
+	s.discloseMapLock.RLock()
+	defer s.discloseMapLock.RUnlock()
+
+	return s.discloseMap[thID]
+}
+
+func (s *Service) setDiscloseCh(thID string, discloseCh chan *Disclose) {
+	s.discloseMapLock.Lock()
+	defer s.discloseMapLock.Unlock()
+
+	if discloseCh == nil {
+		delete(s.discloseMap, thID)
+	} else {
+		s.discloseMap[thID] = discloseCh
+	}
+}
+
+// compileQuery converts the query into a regular expression, the wildcard matches any sequence of characters.
+func compileQuery(query string) (*regexp.Regexp, error) {
+	if query == "" {
+		return nil, errors.New("query is empty")
+	}
+
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(query), regexp.QuoteMeta(Wildcard), ".*")
+
+	return regexp.Compile("^" + pattern + "$")
+}
+
+// PIURIFromMsgType returns the protocol identifier URI of the message type,
+// e.g 'https://didcomm.org/basicmessage/1.0' for 'https://didcomm.org/basicmessage/1.0/message'.
+func PIURIFromMsgType(msgType string) string {
+	i := strings.LastIndex(msgType, "/")
+	if i < 0 {
+		return ""
+	}
+
+	return msgType[:i]
+}
+
+func parseClientOpts(options ...ClientOption) *ClientOptions {
+	opts := &ClientOptions{
+		Timeout: queryTimeout,
+	}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
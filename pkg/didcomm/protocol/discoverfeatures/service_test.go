@@ -0,0 +1,453 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/generic"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+		require.Equal(t, Name, svc.Name())
+		require.Equal(t, []string{PIURI}, svc.Protocols())
+	})
+
+	t.Run("test new service - connection lookup error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.storageProvider = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	svc, err := New(newProvider(nil))
+	require.NoError(t, err)
+
+	require.True(t, svc.Accept(QueryMsgType))
+	require.True(t, svc.Accept(DiscloseMsgType))
+	require.False(t, svc.Accept("unsupported"))
+}
+
+func TestService_Features(t *testing.T) {
+	prov := newProvider(nil)
+	prov.services = []dispatcher.ProtocolService{
+		&protocolSvc{protocols: []string{"https://didcomm.org/introduce/1.0"}},
+		&protocolSvc{protocols: []string{"https://didcomm.org/issue-credential/2.0", "https://didcomm.org/introduce/1.0"}},
+		&protocolSvc{},
+	}
+
+	require.NoError(t, prov.msgSvcProvider.Register(&messageSvc{
+		MockMessageSvc: generic.MockMessageSvc{NameVal: "basic"},
+		protocols:      []string{"https://didcomm.org/basicmessage/1.0"},
+	}))
+	require.NoError(t, prov.msgSvcProvider.Register(&generic.MockMessageSvc{NameVal: "not-described"}))
+
+	svc, err := New(prov)
+	require.NoError(t, err)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "*", expected: []string{
+			"https://didcomm.org/basicmessage/1.0",
+			"https://didcomm.org/introduce/1.0",
+			"https://didcomm.org/issue-credential/2.0",
+		}},
+		{query: "https://didcomm.org/i*", expected: []string{
+			"https://didcomm.org/introduce/1.0",
+			"https://didcomm.org/issue-credential/2.0",
+		}},
+		{query: "https://didcomm.org/*/1.0", expected: []string{
+			"https://didcomm.org/basicmessage/1.0",
+			"https://didcomm.org/introduce/1.0",
+		}},
+		{query: "https://didcomm.org/introduce/1.0", expected: []string{"https://didcomm.org/introduce/1.0"}},
+		{query: "https://didcomm.org/introduce/1.?", expected: nil},
+		{query: "https://didcomm.org/introduce", expected: nil},
+	}
+
+	for _, tc := range tests {
+		descriptors, err := svc.Features(tc.query)
+		require.NoError(t, err, tc.query)
+
+		var pids []string
+		for _, d := range descriptors {
+			pids = append(pids, d.PID)
+		}
+
+		require.Equal(t, tc.expected, pids, tc.query)
+	}
+
+	_, err = svc.Features("")
+	require.EqualError(t, err, "query is empty")
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("query - success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(messenger)
+		prov.services = []dispatcher.ProtocolService{
+			&protocolSvc{protocols: []string{"https://didcomm.org/introduce/1.0", "https://didcomm.org/routing/1.0"}},
+		}
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		messenger.EXPECT().ReplyTo("query-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				require.Equal(t, DiscloseMsgType, msg.Type())
+
+				disclose := &Disclose{}
+				require.NoError(t, msg.Decode(disclose))
+				require.Equal(t, []ProtocolDescriptor{{PID: "https://didcomm.org/introduce/1.0"}}, disclose.Protocols)
+
+				return nil
+			})
+
+		msgID, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type:  QueryMsgType,
+			ID:    "query-id",
+			Query: "https://didcomm.org/introduce/*",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "query-id", msgID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, Name, event.ProtocolName)
+			require.Equal(t, service.PostState, event.Type)
+			require.Equal(t, StateQueryReceived, event.StateID)
+			require.Equal(t, MYDID, event.Properties.All()["myDID"])
+			require.Equal(t, THEIRDID, event.Properties.All()["theirDID"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+	})
+
+	t.Run("query - disclosure policy", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(messenger)
+		prov.services = []dispatcher.ProtocolService{
+			&protocolSvc{protocols: []string{"https://didcomm.org/introduce/1.0", "https://didcomm.org/routing/1.0"}},
+		}
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		svc.SetDisclosurePolicy(func(query *Query, myDID, theirDID string, _ []ProtocolDescriptor) []ProtocolDescriptor {
+			require.Equal(t, "*", query.Query)
+			require.Equal(t, MYDID, myDID)
+			require.Equal(t, THEIRDID, theirDID)
+
+			return nil
+		})
+
+		messenger.EXPECT().ReplyTo("query-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				disclose := &Disclose{}
+				require.NoError(t, msg.Decode(disclose))
+				require.Empty(t, disclose.Protocols)
+				require.NotNil(t, msg["protocols"])
+
+				return nil
+			})
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type:  QueryMsgType,
+			ID:    "query-id",
+			Query: "*",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("query - empty query", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type: QueryMsgType,
+			ID:   "query-id",
+		}), MYDID, THEIRDID)
+		require.EqualError(t, err, "features: query is empty")
+	})
+
+	t.Run("query - reply error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).Return(errors.New("reply error"))
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type:  QueryMsgType,
+			ID:    "query-id",
+			Query: "*",
+		}), MYDID, THEIRDID)
+		require.EqualError(t, err, "reply error")
+	})
+
+	t.Run("disclose - no waiting query", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Disclose{
+			Type: DiscloseMsgType,
+			ID:   "disclose-id",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		event := <-events
+		require.Equal(t, StateDiscloseReceived, event.StateID)
+	})
+
+	t.Run("disclose - decode error", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type":     DiscloseMsgType,
+			"@id":       "disclose-id",
+			"protocols": "invalid",
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disclose message unmarshal")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Query{Type: "unsupported"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unsupported message type unsupported")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		msgID, err := svc.HandleOutbound(service.NewDIDCommMsgMap(&Query{
+			Type:  QueryMsgType,
+			ID:    "query-id",
+			Query: "*",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "query-id", msgID)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&Query{Type: QueryMsgType}), MYDID, THEIRDID)
+		require.EqualError(t, err, "send query: send error")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&Disclose{Type: DiscloseMsgType}), MYDID, THEIRDID)
+		require.EqualError(t, err, "invalid or unsupported outbound message type "+DiscloseMsgType)
+	})
+}
+
+func TestService_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				query := &Query{}
+				require.NoError(t, msg.Decode(query))
+				require.Equal(t, QueryMsgType, query.Type)
+				require.Equal(t, "https://didcomm.org/*", query.Query)
+
+				go func() {
+					_, err := svc.HandleInbound(service.DIDCommMsgMap{
+						"@type":     DiscloseMsgType,
+						"@id":       "disclose-id",
+						"~thread":   map[string]interface{}{"thid": query.ID},
+						"protocols": []interface{}{map[string]interface{}{"pid": "https://didcomm.org/introduce/1.0"}},
+					}, MYDID, THEIRDID)
+					require.NoError(t, err)
+				}()
+
+				return nil
+			})
+
+		protocols, err := svc.Query("conn-id", "https://didcomm.org/*")
+		require.NoError(t, err)
+		require.Equal(t, []ProtocolDescriptor{{PID: "https://didcomm.org/introduce/1.0"}}, protocols)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.Query("conn-id", "*", func(opts *ClientOptions) {
+			opts.Timeout = 10 * time.Millisecond
+		})
+		require.EqualError(t, err, "timeout waiting for disclose message")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.Query("conn-id", "*")
+		require.EqualError(t, err, "send query: send error")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.Query("conn-id", "*")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+}
+
+func TestPIURIFromMsgType(t *testing.T) {
+	require.Equal(t, "https://didcomm.org/basicmessage/1.0",
+		PIURIFromMsgType("https://didcomm.org/basicmessage/1.0/message"))
+	require.Empty(t, PIURIFromMsgType("message"))
+}
+
+func saveConnection(t *testing.T, prov *provider) {
+	t.Helper()
+
+	r, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "conn-id", MyDID: MYDID, TheirDID: THEIRDID, State: "completed",
+	}))
+}
+
+func newProvider(messenger service.Messenger) *provider {
+	return &provider{
+		messenger:            messenger,
+		storageProvider:      mockstore.NewMockStoreProvider(),
+		protocolStateStorage: mockstore.NewMockStoreProvider(),
+		msgSvcProvider:       msghandler.NewMockMsgServiceProvider(),
+	}
+}
+
+type provider struct {
+	messenger            service.Messenger
+	storageProvider      storage.Provider
+	protocolStateStorage storage.Provider
+	services             []dispatcher.ProtocolService
+	msgSvcProvider       *msghandler.MockMsgSvcProvider
+}
+
+func (p *provider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+func (p *provider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *provider) ProtocolStateStorageProvider() storage.Provider {
+	return p.protocolStateStorage
+}
+
+func (p *provider) ProtocolServices() []dispatcher.ProtocolService {
+	return p.services
+}
+
+func (p *provider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.msgSvcProvider
+}
+
+type protocolSvc struct {
+	dispatcher.ProtocolService
+	protocols []string
+}
+
+func (s *protocolSvc) Protocols() []string {
+	return s.protocols
+}
+
+type messageSvc struct {
+	generic.MockMessageSvc
+	protocols []string
+}
+
+func (s *messageSvc) Protocols() []string {
+	return s.protocols
+}
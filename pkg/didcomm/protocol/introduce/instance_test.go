@@ -0,0 +1,20 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package introduce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_roleFromState(t *testing.T) {
+	require.Equal(t, RoleIntroducer, roleFromState(stateNameArranging))
+	require.Equal(t, RoleIntroducee, roleFromState(stateNameRequesting))
+	require.Equal(t, RoleIntroducee, roleFromState(stateNameDeciding))
+	require.Empty(t, roleFromState(stateNameAbandoning))
+}
@@ -49,6 +49,27 @@ func WithPublicOOBRequest(req *outofband.Request, to *To) Opt {
 	}
 }
 
+// WithPublicOOBInvitation is used when introducer wants to provide a public out-of-band invitation.
+// NOTE: Introducer can provide this invitation only after receiving RequestMsgType
+// USAGE: event.Continue(WithPublicOOBInvitation(inv, to)).
+func WithPublicOOBInvitation(inv *outofband.Invitation, to *To) Opt {
+	return func(m map[string]interface{}) {
+		m[metaOOBMessage] = service.NewDIDCommMsgMap(inv)
+		m[metaSkipProposal] = true
+		m[metaRecipients] = []interface{}{&Recipient{
+			To: to,
+		}}
+	}
+}
+
+// WithPublicDID is used when the agent the introducer introduces is reachable only by its public DID.
+// The introducee receives an out-of-band invitation to connect to the public DID.
+// NOTE: Introducer can provide the public DID only after receiving RequestMsgType
+// USAGE: event.Continue(WithPublicDID(publicDID, to)).
+func WithPublicDID(publicDID string, to *To) Opt {
+	return WithPublicOOBInvitation(CreatePublicDIDInvitation(publicDID, to), to)
+}
+
 // WithRecipients is used when the introducer does not have a public invitation
 // but he is willing to introduce agents to each other.
 // NOTE: Introducer can provide recipients only after receiving RequestMsgType.
@@ -80,6 +101,14 @@ func WrapWithMetadataPublicOOBRequest(msg service.DIDCommMsgMap, req *outofband.
 	msg.Metadata()[metaSkipProposal] = true
 }
 
+// WrapWithMetadataPublicOOBInvitation wraps message with metadata.
+// The function is used by the introduce client to define skip proposal.
+// It also saves the invitation and will provide it later to the introducee.
+func WrapWithMetadataPublicOOBInvitation(msg service.DIDCommMsgMap, inv *outofband.Invitation) {
+	msg.Metadata()[metaOOBMessage] = service.NewDIDCommMsgMap(inv)
+	msg.Metadata()[metaSkipProposal] = true
+}
+
 func copyMetadata(from, to service.DIDCommMsg) {
 	for k, v := range from.Metadata() {
 		to.Metadata()[k] = v
@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package introduce
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+)
+
+// Target describes the agent the introducer introduces on request without asking the user.
+// The agent is reachable by exactly one of an out-of-band request, an out-of-band invitation or a public DID.
+type Target struct {
+	To            *To                   `json:"to"`
+	OOBRequest    *outofband.Request    `json:"oob_request,omitempty"`
+	OOBInvitation *outofband.Invitation `json:"oob_invitation,omitempty"`
+	PublicDID     string                `json:"public_did,omitempty"`
+}
+
+// ResponsePolicy answers the introduce actions without waiting for the user, the actions the policy
+// does not answer are emitted as action events.
+type ResponsePolicy struct {
+	// Introducers are the DIDs of the introducers whose proposals are accepted by the introducee.
+	Introducers []string `json:"introducers,omitempty"`
+	// OOBRequest is shared by the introducee when it accepts a proposal (optional).
+	OOBRequest *outofband.Request `json:"oob_request,omitempty"`
+	// Targets are the agents the introducer introduces on request, by the name asked for
+	// in the please_introduce_to field of the request.
+	Targets map[string]*Target `json:"targets,omitempty"`
+	// AcceptProblemReports accepts the problem reports received by both roles.
+	AcceptProblemReports bool `json:"accept_problem_reports,omitempty"`
+}
+
+// SetResponsePolicy sets the policy which answers the introduce actions on behalf of the user.
+// Passing nil restores the default behavior which emits all the actions as action events.
+func (s *Service) SetResponsePolicy(policy *ResponsePolicy) {
+	s.policyLock.Lock()
+	defer s.policyLock.Unlock()
+
+	s.policy = policy
+}
+
+// respond returns the option answering the action by the response policy,
+// false is returned when the action is left to the user.
+func (s *Service) respond(md *metaData) (Opt, bool) {
+	s.policyLock.RLock()
+	defer s.policyLock.RUnlock()
+
+	if s.policy == nil {
+		return nil, false
+	}
+
+	switch md.Msg.Type() {
+	case ProposalMsgType:
+		for _, introducer := range s.policy.Introducers {
+			if introducer != md.TheirDID {
+				continue
+			}
+
+			if s.policy.OOBRequest != nil {
+				return WithOOBRequest(s.policy.OOBRequest), true
+			}
+
+			return nil, true
+		}
+	case RequestMsgType:
+		request := Request{}
+		if err := md.Msg.Decode(&request); err != nil || request.PleaseIntroduceTo == nil {
+			return nil, false
+		}
+
+		if target, ok := s.policy.Targets[request.PleaseIntroduceTo.Name]; ok {
+			return target.opt()
+		}
+	case ProblemReportMsgType:
+		return nil, s.policy.AcceptProblemReports
+	}
+
+	return nil, false
+}
+
+func (t *Target) opt() (Opt, bool) {
+	switch {
+	case t.OOBRequest != nil:
+		return WithPublicOOBRequest(t.OOBRequest, t.To), true
+	case t.OOBInvitation != nil:
+		return WithPublicOOBInvitation(t.OOBInvitation, t.To), true
+	case t.PublicDID != "":
+		return WithPublicDID(t.PublicDID, t.To), true
+	default:
+		return nil, false
+	}
+}
+
+// CreatePublicDIDInvitation creates an out-of-band invitation to connect to the agent
+// which is reachable only by its public DID.
+func CreatePublicDIDInvitation(publicDID string, to *To) *outofband.Invitation {
+	inv := &outofband.Invitation{
+		ID:                 uuid.New().String(),
+		Type:               outofband.InvitationMsgType,
+		HandshakeProtocols: []string{didexchange.PIURI},
+		Service:            []interface{}{publicDID},
+	}
+
+	if to != nil {
+		inv.Label = to.Name
+	}
+
+	return inv
+}
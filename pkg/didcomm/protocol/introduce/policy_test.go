@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package introduce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+)
+
+func TestService_Respond(t *testing.T) {
+	newMetaData := func(msg interface{}, theirDID string) *metaData {
+		md := &metaData{}
+		md.Msg = service.NewDIDCommMsgMap(msg)
+		md.TheirDID = theirDID
+
+		return md
+	}
+
+	request := func(name string) *metaData {
+		return newMetaData(&Request{
+			Type:              RequestMsgType,
+			PleaseIntroduceTo: &PleaseIntroduceTo{To: To{Name: name}},
+		}, "Bob")
+	}
+
+	t.Run("no policy", func(t *testing.T) {
+		opt, ok := (&Service{}).respond(newMetaData(&Proposal{Type: ProposalMsgType}, "Alice"))
+		require.False(t, ok)
+		require.Nil(t, opt)
+	})
+
+	t.Run("proposals", func(t *testing.T) {
+		svc := &Service{}
+		svc.SetResponsePolicy(&ResponsePolicy{Introducers: []string{"Alice"}})
+
+		opt, ok := svc.respond(newMetaData(&Proposal{Type: ProposalMsgType}, "Alice"))
+		require.True(t, ok)
+		require.Nil(t, opt)
+
+		_, ok = svc.respond(newMetaData(&Proposal{Type: ProposalMsgType}, "Mallory"))
+		require.False(t, ok)
+
+		svc.SetResponsePolicy(&ResponsePolicy{
+			Introducers: []string{"Alice"},
+			OOBRequest:  &outofband.Request{Type: outofband.RequestMsgType},
+		})
+
+		md := newMetaData(&Proposal{Type: ProposalMsgType}, "Alice")
+
+		opt, ok = svc.respond(md)
+		require.True(t, ok)
+		require.NotNil(t, opt)
+
+		opt(md.Msg.Metadata())
+		require.Equal(t, outofband.RequestMsgType, contextOOBMessage(md.Msg)["@type"])
+	})
+
+	t.Run("requests", func(t *testing.T) {
+		svc := &Service{}
+		svc.SetResponsePolicy(&ResponsePolicy{Targets: map[string]*Target{
+			"Carol": {To: &To{Name: "Carol"}, PublicDID: "did:example:carol"},
+			"Dave":  {To: &To{Name: "Dave"}, OOBRequest: &outofband.Request{Type: outofband.RequestMsgType}},
+			"Eve":   {To: &To{Name: "Eve"}, OOBInvitation: &outofband.Invitation{Type: outofband.InvitationMsgType}},
+			"Frank": {To: &To{Name: "Frank"}},
+		}})
+
+		for name, msgType := range map[string]string{
+			"Carol": outofband.InvitationMsgType,
+			"Dave":  outofband.RequestMsgType,
+			"Eve":   outofband.InvitationMsgType,
+		} {
+			md := request(name)
+
+			opt, ok := svc.respond(md)
+			require.True(t, ok)
+
+			opt(md.Msg.Metadata())
+			require.True(t, isSkipProposal(md))
+			require.Equal(t, msgType, contextOOBMessage(md.Msg)["@type"])
+			require.Len(t, getMetaRecipients(md), 1)
+			require.Equal(t, name, getMetaRecipients(md)[0].To.Name)
+		}
+
+		_, ok := svc.respond(request("Frank"))
+		require.False(t, ok)
+
+		_, ok = svc.respond(request("Grace"))
+		require.False(t, ok)
+
+		_, ok = svc.respond(newMetaData(&Request{Type: RequestMsgType}, "Bob"))
+		require.False(t, ok)
+	})
+
+	t.Run("problem reports", func(t *testing.T) {
+		svc := &Service{}
+		svc.SetResponsePolicy(&ResponsePolicy{})
+
+		_, ok := svc.respond(newMetaData(&Proposal{Type: ProblemReportMsgType}, "Alice"))
+		require.False(t, ok)
+
+		svc.SetResponsePolicy(&ResponsePolicy{AcceptProblemReports: true})
+
+		_, ok = svc.respond(newMetaData(&Proposal{Type: ProblemReportMsgType}, "Alice"))
+		require.True(t, ok)
+
+		svc.SetResponsePolicy(nil)
+
+		_, ok = svc.respond(newMetaData(&Proposal{Type: ProblemReportMsgType}, "Alice"))
+		require.False(t, ok)
+	})
+}
+
+func TestCreatePublicDIDInvitation(t *testing.T) {
+	inv := CreatePublicDIDInvitation("did:example:carol", &To{Name: "Carol"})
+	require.NotEmpty(t, inv.ID)
+	require.Equal(t, outofband.InvitationMsgType, inv.Type)
+	require.Equal(t, "Carol", inv.Label)
+	require.Equal(t, []string{didexchange.PIURI}, inv.HandshakeProtocols)
+	require.Equal(t, []interface{}{"did:example:carol"}, inv.Service)
+
+	inv = CreatePublicDIDInvitation("did:example:carol", nil)
+	require.Empty(t, inv.Label)
+}
@@ -11,6 +11,8 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,7 +22,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -96,16 +100,37 @@ type metaData struct {
 	// e.g the user received an action event and executes Stop(err) function
 	// in that case `err` is equal to `err` which was passing to Stop function
 	err error
+	// connectionID is the connection the protocol instance belongs to, it is used in the log fields only.
+	connectionID string
+}
+
+// logFields returns fields which allow to correlate log lines of the protocol instance.
+func (md *metaData) logFields() log.Fields {
+	fields := service.LogFields(md.Msg)
+	fields[log.FieldProtocol] = Introduce
+	fields[log.FieldConnectionID] = md.connectionID
+
+	if md.state != nil {
+		fields[log.FieldState] = md.state.Name()
+	}
+
+	return fields
 }
 
 // Service for introduce protocol.
 type Service struct {
 	service.Action
 	service.Message
-	store     storage.Store
-	callbacks chan *metaData
-	oobEvent  chan service.StateMsg
-	messenger service.Messenger
+	store      storage.Store
+	callbacks  chan *metaData
+	oobEvent   chan service.StateMsg
+	messenger  service.Messenger
+	now        func() time.Time
+	history    *statemachine.History
+	policy     *ResponsePolicy
+	policyLock sync.RWMutex
+	// connections is used to add the connection ID to the log fields.
+	connections *connection.Lookup
 }
 
 // Provider contains dependencies for the DID exchange protocol and is typically created by using aries.Context().
@@ -115,6 +140,13 @@ type Provider interface {
 	Service(id string) (interface{}, error)
 }
 
+// connectionStoreProvider is implemented by the providers giving access to the connection store
+// (e.g. the framework context), the connection ID is added to the log fields then.
+type connectionStoreProvider interface {
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
 // New returns introduce service.
 func New(p Provider) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Introduce)
@@ -137,6 +169,16 @@ func New(p Provider) (*Service, error) {
 		store:     store,
 		callbacks: make(chan *metaData),
 		oobEvent:  make(chan service.StateMsg),
+		now:       time.Now,
+	}
+
+	svc.history = svc.newHistory()
+
+	if cp, ok := p.(connectionStoreProvider); ok {
+		svc.connections, err = connection.NewLookup(cp)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err = oobService.RegisterMsgEvent(svc.oobEvent); err != nil {
@@ -166,7 +208,7 @@ func (s *Service) startInternalListener() {
 
 			msg.state = &abandoning{Code: codeInternalError}
 
-			logInternalError(msg.err)
+			s.logInternalError(msg)
 
 			if err := s.handle(msg); err != nil {
 				logger.Errorf("listener handle: %s", err)
@@ -179,9 +221,9 @@ func (s *Service) startInternalListener() {
 	}
 }
 
-func logInternalError(err error) {
-	if _, ok := err.(customError); !ok {
-		logger.Errorf("go to abandoning: %v", err)
+func (s *Service) logInternalError(md *metaData) {
+	if _, ok := md.err.(customError); !ok {
+		logger.WithFields(s.logFields(md)).Errorf("go to abandoning: %v", md.err)
 	}
 }
 
@@ -284,6 +326,15 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 
 	// trigger action event based on message type for inbound messages
 	if canTriggerActionEvents(msg) {
+		// the action answered by the response policy does not wait for the user
+		if opt, ok := s.respond(md); ok {
+			if opt != nil {
+				opt(md.Msg.Metadata())
+			}
+
+			return md.PIID, s.handle(md)
+		}
+
 		err = s.saveTransitionalPayload(md.PIID, md.transitionalPayload)
 		if err != nil {
 			return "", fmt.Errorf("save transitional payload: %w", err)
@@ -595,13 +646,15 @@ func (s *Service) handle(md *metaData) error {
 	}
 
 	var (
-		current   = md.state
-		actions   []stateAction
-		stateName string
+		current    = md.state
+		actions    []stateAction
+		stateName  string
+		stateNames []string
 	)
 
 	for !isNoOp(current) {
 		stateName = current.Name()
+		stateNames = append(stateNames, stateName)
 
 		next, action, err := s.execute(current, md)
 		if err != nil {
@@ -621,6 +674,12 @@ func (s *Service) handle(md *metaData) error {
 		return fmt.Errorf("failed to persist state %s: %w", stateName, err)
 	}
 
+	if len(stateNames) > 0 {
+		if err := s.saveHistoryEntry(md, stateNames); err != nil {
+			return fmt.Errorf("save history entry: %w", err)
+		}
+	}
+
 	for _, action := range actions {
 		if err := action(); err != nil {
 			return err
@@ -734,8 +793,19 @@ func (s *Service) getParticipants(piID string) ([]*participant, error) {
 	return participants, nil
 }
 
+// logFields returns the log fields of the protocol instance, its connection is looked up once.
+func (s *Service) logFields(md *metaData) log.Fields {
+	if md.connectionID == "" && s.connections != nil && md.MyDID != "" && md.TheirDID != "" {
+		md.connectionID, _ = s.connections.GetConnectionIDByDIDs(md.MyDID, md.TheirDID) // nolint: errcheck
+	}
+
+	return md.logFields()
+}
+
 func (s *Service) execute(next state, md *metaData) (state, stateAction, error) {
 	md.state = next
+	logger.WithFields(s.logFields(md)).Debugf("execute state")
+
 	s.sendMsgEvents(md, next.Name(), service.PreState)
 
 	defer s.sendMsgEvents(md, next.Name(), service.PostState)
@@ -764,6 +834,11 @@ func (s *Service) Name() string {
 	return Introduce
 }
 
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{strings.TrimSuffix(IntroduceSpec, "/")}
+}
+
 // Accept msg checks the msg type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
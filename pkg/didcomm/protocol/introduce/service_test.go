@@ -80,6 +80,8 @@ func agentSetup(agent string, t *testing.T, ctrl *gomock.Controller, tr map[stri
 	mProvider := messengerMocks.NewMockProvider(ctrl)
 	mProvider.EXPECT().StorageProvider().Return(storageProvider)
 	mProvider.EXPECT().OutboundDispatcher().Return(outbound)
+	mProvider.EXPECT().KMS().Return(nil)
+	mProvider.EXPECT().ServiceEndpoint().Return("")
 
 	provider := introduceMocks.NewMockProvider(ctrl)
 	provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -100,7 +102,7 @@ func agentSetup(agent string, t *testing.T, ctrl *gomock.Controller, tr map[stri
 				didMap, err := service.ParseDIDCommMsgMap(msg.msg)
 				require.NoError(t, err)
 
-				if didMap.Type() == outofband.RequestMsgType {
+				if didMap.Type() == outofband.RequestMsgType || didMap.Type() == outofband.InvitationMsgType {
 					require.NoError(t, svc.OOBMessageReceived(service.StateMsg{
 						Type:    service.PostState,
 						StateID: "requested",
@@ -1358,6 +1360,55 @@ func TestService_SkipProposalWithRequest(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// Alice received request from Bob and forwarded the invitation to Carol's public DID by the response policy.
+// Bob received proposal from Alice and accepted it by the response policy.
+// Alice received response from Bob.
+// Bob received invitation from Alice.
+func TestService_SkipProposalWithResponsePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transport := map[string]chan payload{
+		Alice: make(chan payload),
+		Bob:   make(chan payload),
+	}
+
+	done := make(chan struct{}, len(transport)*2)
+	defer wait(t, done)
+
+	alice := agentSetup(Alice, t, ctrl, transport)
+	alice.SetResponsePolicy(&introduce.ResponsePolicy{
+		Targets: map[string]*introduce.Target{
+			Carol: {To: &introduce.To{Name: Carol}, PublicDID: "did:example:carol"},
+		},
+	})
+
+	handle(t, Alice, done, alice, checkStateMsg(t, Alice,
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"delivering", "delivering",
+		"done", "done",
+	), nil)
+
+	bob := agentSetup(Bob, t, ctrl, transport)
+	bob.SetResponsePolicy(&introduce.ResponsePolicy{Introducers: []string{Alice}})
+
+	handle(t, Bob, done, bob, checkStateMsg(t, Bob,
+		"requesting", "requesting",
+		"deciding", "deciding",
+		"waiting", "waiting",
+		"done", "done",
+	), nil)
+
+	_, err := bob.HandleOutbound(service.NewDIDCommMsgMap(&introduce.Request{
+		Type: introduce.RequestMsgType,
+		PleaseIntroduceTo: &introduce.PleaseIntroduceTo{To: introduce.To{
+			Name: Carol,
+		}},
+	}), Bob, Alice)
+	require.NoError(t, err)
+}
+
 // Alice received request from Bob.
 // Bob received proposal from Alice.
 // Alice received response from Bob.
@@ -1469,6 +1520,10 @@ func TestService_Name(t *testing.T) {
 	require.Equal(t, introduce.Introduce, (&introduce.Service{}).Name())
 }
 
+func TestService_Protocols(t *testing.T) {
+	require.Equal(t, []string{"https://didcomm.org/introduce/1.0"}, (&introduce.Service{}).Protocols())
+}
+
 func TestService_New(t *testing.T) {
 	const errMsg = "test err"
 
@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+const (
+	// FormatLDProofVC is the name of the JSON-LD verifiable credential format family.
+	FormatLDProofVC = "ldp_vc"
+	// FormatJWTVC is the name of the JWT verifiable credential format family.
+	FormatJWTVC = "jwt_vc"
+	// LDProofVCDetailFormat is the format of the attachment which describes the credential to be issued
+	// (Aries RFC 0593).
+	LDProofVCDetailFormat = "aries/ld-proof-vc-detail@v1.0"
+	// LDProofVCFormat is the format of the issued credential attachment (Aries RFC 0593).
+	LDProofVCFormat = "aries/ld-proof-vc@v1.0"
+
+	// the error code of the problem report which is sent when none of the formats is supported.
+	codeUnsupportedFormat = "unsupported-format"
+	// the error code of the problem report which is sent when the attachment does not match its format.
+	codeInvalidAttachment = "invalid-attachment"
+
+	jwtParts = 3
+)
+
+// errUnsupportedFormat is returned when none of the formats of the message is supported.
+var errUnsupportedFormat = errors.New("unsupported format")
+
+// FormatHandler handles the attachments of the verifiable credential format family (e.g ldp_vc, jwt_vc).
+type FormatHandler interface {
+	// Name returns the name of the format family.
+	Name() string
+	// Supports checks whether the attachment format belongs to the format family.
+	Supports(format string) bool
+	// Validate checks the attachment payload of the given format.
+	Validate(format string, payload []byte) error
+}
+
+// WithFormatHandlers registers the handlers of the credential formats the agent supports.
+// Once at least one handler is registered, the formats of every message are negotiated: the first format
+// supported by the agent is selected and the replies carry only the attachments of the selected format family.
+// Messages with formats the agent does not support are rejected with the problem report.
+func WithFormatHandlers(handlers ...FormatHandler) ServiceOption {
+	return func(s *Service) {
+		s.formats = append(s.formats, handlers...)
+	}
+}
+
+// LDProofVCFormatHandler handles the JSON-LD verifiable credential formats (ldp_vc and Aries RFC 0593).
+type LDProofVCFormatHandler struct{}
+
+// Name returns the name of the format family.
+func (h *LDProofVCFormatHandler) Name() string {
+	return FormatLDProofVC
+}
+
+// Supports checks whether the attachment format belongs to the format family.
+func (h *LDProofVCFormatHandler) Supports(format string) bool {
+	return format == FormatLDProofVC || format == LDProofVCDetailFormat || format == LDProofVCFormat
+}
+
+// Validate checks the attachment payload of the given format.
+func (h *LDProofVCFormatHandler) Validate(format string, payload []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return fmt.Errorf("%s: unmarshal: %w", format, err)
+	}
+
+	if format == LDProofVCDetailFormat && len(fields["credential"]) == 0 {
+		return fmt.Errorf("%s: credential was not provided", format)
+	}
+
+	return nil
+}
+
+// JWTVCFormatHandler handles the JWT verifiable credential format (jwt_vc).
+type JWTVCFormatHandler struct{}
+
+// Name returns the name of the format family.
+func (h *JWTVCFormatHandler) Name() string {
+	return FormatJWTVC
+}
+
+// Supports checks whether the attachment format belongs to the format family.
+func (h *JWTVCFormatHandler) Supports(format string) bool {
+	return format == FormatJWTVC
+}
+
+// Validate checks the attachment payload of the given format.
+func (h *JWTVCFormatHandler) Validate(format string, payload []byte) error {
+	jwt := strings.TrimSpace(string(payload))
+
+	// the JWT might be attached as the JSON string
+	var unquoted string
+	if err := json.Unmarshal([]byte(jwt), &unquoted); err == nil {
+		jwt = unquoted
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != jwtParts {
+		return fmt.Errorf("%s: compact JWT was expected", format)
+	}
+
+	// the header and the claims must be base64url encoded JSON objects
+	for _, part := range parts[:2] {
+		raw, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil || !json.Valid(raw) {
+			return fmt.Errorf("%s: compact JWT was expected", format)
+		}
+	}
+
+	return nil
+}
+
+// formatHandler returns the registered handler which supports the format.
+func (s *Service) formatHandler(format string) FormatHandler {
+	for _, handler := range s.formats {
+		if handler.Supports(format) {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+// formatHandlerByName returns the registered handler of the format family.
+func (s *Service) formatHandlerByName(name string) FormatHandler {
+	for _, handler := range s.formats {
+		if handler.Name() == name {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+// negotiateFormat selects the first format of the message supported by the agent and validates its attachment.
+// It returns the name of the selected format family, the empty name means the message is not negotiated.
+func (s *Service) negotiateFormat(msg service.DIDCommMsg) (string, error) {
+	if len(s.formats) == 0 {
+		return "", nil
+	}
+
+	payload := struct {
+		Formats           []Format               `json:"formats,omitempty"`
+		FilterAttach      []decorator.Attachment `json:"filter~attach,omitempty"`
+		OffersAttach      []decorator.Attachment `json:"offers~attach,omitempty"`
+		RequestsAttach    []decorator.Attachment `json:"requests~attach,omitempty"`
+		CredentialsAttach []decorator.Attachment `json:"credentials~attach,omitempty"`
+	}{}
+
+	if err := msg.Decode(&payload); err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+
+	if len(payload.Formats) == 0 {
+		return "", nil
+	}
+
+	var attachments []decorator.Attachment
+
+	attachments = append(attachments, payload.FilterAttach...)
+	attachments = append(attachments, payload.OffersAttach...)
+	attachments = append(attachments, payload.RequestsAttach...)
+	attachments = append(attachments, payload.CredentialsAttach...)
+
+	for _, format := range payload.Formats {
+		handler := s.formatHandler(format.Format)
+		if handler == nil {
+			continue
+		}
+
+		attachment := findAttachment(attachments, format.AttachID)
+		if attachment == nil {
+			return "", fmt.Errorf("attachment %s not found", format.AttachID)
+		}
+
+		raw, err := attachment.Data.Fetch()
+		if err != nil {
+			return "", fmt.Errorf("fetch: %w", err)
+		}
+
+		if err = handler.Validate(format.Format, raw); err != nil {
+			return "", fmt.Errorf("validate: %w", err)
+		}
+
+		return handler.Name(), nil
+	}
+
+	return "", errUnsupportedFormat
+}
+
+// filterFormats keeps only the formats of the negotiated format family along with their attachments.
+// Attachments which are not referenced by any format are kept as is.
+func filterFormats(handler FormatHandler, formats []Format,
+	attachments []decorator.Attachment) ([]Format, []decorator.Attachment, error) {
+	if handler == nil || len(formats) == 0 {
+		return formats, attachments, nil
+	}
+
+	var (
+		filtered   []Format
+		referenced = map[string]bool{}
+	)
+
+	for _, format := range formats {
+		referenced[format.AttachID] = handler.Supports(format.Format)
+
+		if handler.Supports(format.Format) {
+			filtered = append(filtered, format)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return nil, nil, fmt.Errorf("no attachments of the negotiated format %s", handler.Name())
+	}
+
+	var kept []decorator.Attachment
+
+	for _, attachment := range attachments {
+		if supported, ok := referenced[attachment.ID]; !ok || supported {
+			kept = append(kept, attachment)
+		}
+	}
+
+	return filtered, kept, nil
+}
+
+// applyFormat narrows the reply messages down to the negotiated format family.
+func (md *metaData) applyFormat() error {
+	var err error
+
+	if md.proposeCredential != nil {
+		md.proposeCredential.Formats, md.proposeCredential.FilterAttach, err = filterFormats(
+			md.formatHandler, md.proposeCredential.Formats, md.proposeCredential.FilterAttach)
+		if err != nil {
+			return fmt.Errorf("propose credential: %w", err)
+		}
+	}
+
+	if md.offerCredential != nil {
+		md.offerCredential.Formats, md.offerCredential.OffersAttach, err = filterFormats(
+			md.formatHandler, md.offerCredential.Formats, md.offerCredential.OffersAttach)
+		if err != nil {
+			return fmt.Errorf("offer credential: %w", err)
+		}
+	}
+
+	if md.requestCredential != nil {
+		md.requestCredential.Formats, md.requestCredential.RequestsAttach, err = filterFormats(
+			md.formatHandler, md.requestCredential.Formats, md.requestCredential.RequestsAttach)
+		if err != nil {
+			return fmt.Errorf("request credential: %w", err)
+		}
+	}
+
+	if md.issueCredential != nil {
+		md.issueCredential.Formats, md.issueCredential.CredentialsAttach, err = filterFormats(
+			md.formatHandler, md.issueCredential.Formats, md.issueCredential.CredentialsAttach)
+		if err != nil {
+			return fmt.Errorf("issue credential: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func findAttachment(attachments []decorator.Attachment, id string) *decorator.Attachment {
+	for i := range attachments {
+		if attachments[i].ID == id {
+			return &attachments[i]
+		}
+	}
+
+	return nil
+}
+
+func formatErrorCode(err error) string {
+	if errors.Is(err, errUnsupportedFormat) {
+		return codeUnsupportedFormat
+	}
+
+	return codeInvalidAttachment
+}
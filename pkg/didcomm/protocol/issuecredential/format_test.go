@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	issuecredentialMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const (
+	testJWT       = "eyJhbGciOiJub25lIn0.eyJ2YyI6e319."
+	testLDPayload = `{"credential":{"@context":["https://www.w3.org/2018/credentials/v1"]}}`
+)
+
+func testOffer() *OfferCredential {
+	return &OfferCredential{
+		Type: OfferCredentialMsgType,
+		Formats: []Format{
+			{AttachID: "jwt", Format: FormatJWTVC},
+			{AttachID: "ld", Format: LDProofVCDetailFormat},
+		},
+		OffersAttach: []decorator.Attachment{
+			{ID: "jwt", Data: decorator.AttachmentData{JSON: testJWT}},
+			{ID: "ld", Data: decorator.AttachmentData{JSON: map[string]interface{}{
+				"credential": map[string]interface{}{"type": "VerifiableCredential"},
+			}}},
+		},
+	}
+}
+
+func TestService_FormatNegotiation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newService := func(t *testing.T, messenger service.Messenger) *Service {
+		t.Helper()
+
+		provider := issuecredentialMocks.NewMockProvider(ctrl)
+		provider.EXPECT().Messenger().Return(messenger)
+		provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+
+		svc, err := New(provider, WithFormatHandlers(&LDProofVCFormatHandler{}))
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	t.Run("Requests the credential in the negotiated format", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		svc := newService(t, messenger)
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				defer close(done)
+
+				request := &RequestCredential{}
+				require.NoError(t, msg.Decode(request))
+				require.Equal(t, RequestCredentialMsgType, request.Type)
+				require.Equal(t, []Format{{AttachID: "ld", Format: LDProofVCDetailFormat}}, request.Formats)
+				require.Len(t, request.RequestsAttach, 1)
+				require.Equal(t, "ld", request.RequestsAttach[0].ID)
+
+				return nil
+			})
+
+		msg := service.NewDIDCommMsgMap(testOffer())
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		_, err := svc.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		action := <-actions
+		require.Equal(t, FormatLDProofVC, action.Properties.All()[formatPropKey])
+
+		action.Continue(nil)
+
+		<-done
+	})
+
+	t.Run("Rejects the message with unsupported formats", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		svc := newService(t, messenger)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		msg := service.NewDIDCommMsgMap(OfferCredential{
+			Type:         OfferCredentialMsgType,
+			Formats:      []Format{{AttachID: "jwt", Format: FormatJWTVC}},
+			OffersAttach: []decorator.Attachment{{ID: "jwt", Data: decorator.AttachmentData{JSON: testJWT}}},
+		})
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, ProblemReportMsgType, r.Type)
+				require.Equal(t, codeUnsupportedFormat, r.Description.Code)
+				require.Equal(t, Alice, opts.MyDID)
+				require.Equal(t, Bob, opts.TheirDID)
+
+				return nil
+			})
+
+		_, err := svc.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		threads, err := svc.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Equal(t, stateNameDone, threads[0].StateName)
+	})
+
+	t.Run("Rejects the outbound message with unsupported formats", func(t *testing.T) {
+		svc := newService(t, serviceMocks.NewMockMessenger(ctrl))
+
+		msg := service.NewDIDCommMsgMap(OfferCredential{
+			Type:    OfferCredentialMsgType,
+			Formats: []Format{{AttachID: "jwt", Format: FormatJWTVC}},
+		})
+
+		_, err := svc.HandleOutbound(msg, Alice, Bob)
+		require.EqualError(t, err, "negotiate format: unsupported format")
+	})
+}
+
+func TestService_negotiateFormat(t *testing.T) {
+	svc := &Service{}
+
+	format, err := svc.negotiateFormat(service.NewDIDCommMsgMap(testOffer()))
+	require.NoError(t, err)
+	require.Empty(t, format)
+
+	svc.formats = []FormatHandler{&LDProofVCFormatHandler{}, &JWTVCFormatHandler{}}
+
+	format, err = svc.negotiateFormat(service.NewDIDCommMsgMap(testOffer()))
+	require.NoError(t, err)
+	require.Equal(t, FormatJWTVC, format)
+
+	format, err = svc.negotiateFormat(service.NewDIDCommMsgMap(OfferCredential{Type: OfferCredentialMsgType}))
+	require.NoError(t, err)
+	require.Empty(t, format)
+
+	offer := testOffer()
+	offer.OffersAttach = offer.OffersAttach[1:]
+
+	_, err = svc.negotiateFormat(service.NewDIDCommMsgMap(offer))
+	require.EqualError(t, err, "attachment jwt not found")
+	require.Equal(t, codeInvalidAttachment, formatErrorCode(err))
+
+	offer = testOffer()
+	offer.OffersAttach[0].Data = decorator.AttachmentData{JSON: "invalid"}
+
+	_, err = svc.negotiateFormat(service.NewDIDCommMsgMap(offer))
+	require.EqualError(t, err, "validate: jwt_vc: compact JWT was expected")
+
+	offer = testOffer()
+	offer.Formats[0].Format = "hlindy/cred@v2.0"
+	offer.Formats[1].Format = "unknown"
+
+	_, err = svc.negotiateFormat(service.NewDIDCommMsgMap(offer))
+	require.EqualError(t, err, "unsupported format")
+	require.Equal(t, codeUnsupportedFormat, formatErrorCode(err))
+}
+
+func Test_filterFormats(t *testing.T) {
+	offer := testOffer()
+	offer.OffersAttach = append(offer.OffersAttach, decorator.Attachment{ID: "preview"})
+
+	formats, attachments, err := filterFormats(nil, offer.Formats, offer.OffersAttach)
+	require.NoError(t, err)
+	require.Equal(t, offer.Formats, formats)
+	require.Equal(t, offer.OffersAttach, attachments)
+
+	formats, attachments, err = filterFormats(&JWTVCFormatHandler{}, offer.Formats, offer.OffersAttach)
+	require.NoError(t, err)
+	require.Equal(t, []Format{{AttachID: "jwt", Format: FormatJWTVC}}, formats)
+	require.Len(t, attachments, 2)
+	require.Equal(t, "jwt", attachments[0].ID)
+	require.Equal(t, "preview", attachments[1].ID)
+
+	_, _, err = filterFormats(&JWTVCFormatHandler{}, offer.Formats[1:], offer.OffersAttach)
+	require.EqualError(t, err, "no attachments of the negotiated format jwt_vc")
+
+	md := &metaData{formatHandler: &JWTVCFormatHandler{}, issueCredential: &IssueCredential{
+		Formats: []Format{{AttachID: "ld", Format: LDProofVCFormat}},
+	}}
+	require.EqualError(t, md.applyFormat(), "issue credential: no attachments of the negotiated format jwt_vc")
+
+	md = &metaData{formatHandler: &LDProofVCFormatHandler{}, offerCredential: testOffer()}
+	require.NoError(t, md.applyFormat())
+	require.Len(t, md.offerCredential.Formats, 1)
+	require.Len(t, md.offerCredential.OffersAttach, 1)
+}
+
+func TestLDProofVCFormatHandler(t *testing.T) {
+	h := &LDProofVCFormatHandler{}
+
+	require.Equal(t, FormatLDProofVC, h.Name())
+	require.True(t, h.Supports(FormatLDProofVC))
+	require.True(t, h.Supports(LDProofVCDetailFormat))
+	require.True(t, h.Supports(LDProofVCFormat))
+	require.False(t, h.Supports(FormatJWTVC))
+
+	require.NoError(t, h.Validate(LDProofVCDetailFormat, []byte(testLDPayload)))
+	require.NoError(t, h.Validate(LDProofVCFormat, []byte(`{"@context":[]}`)))
+	require.EqualError(t, h.Validate(LDProofVCDetailFormat, []byte(`{}`)),
+		"aries/ld-proof-vc-detail@v1.0: credential was not provided")
+	require.Contains(t, h.Validate(FormatLDProofVC, []byte(testJWT)).Error(), "ldp_vc: unmarshal")
+}
+
+func TestJWTVCFormatHandler(t *testing.T) {
+	h := &JWTVCFormatHandler{}
+
+	require.Equal(t, FormatJWTVC, h.Name())
+	require.True(t, h.Supports(FormatJWTVC))
+	require.False(t, h.Supports(FormatLDProofVC))
+
+	require.NoError(t, h.Validate(FormatJWTVC, []byte(testJWT)))
+	require.NoError(t, h.Validate(FormatJWTVC, []byte(`"`+testJWT+`"`)))
+	require.EqualError(t, h.Validate(FormatJWTVC, []byte(testLDPayload)), "jwt_vc: compact JWT was expected")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
+)
+
+const (
+	// RoleIssuer the role of the agent which issues credentials.
+	RoleIssuer = "issuer"
+	// RoleHolder the role of the agent which receives credentials.
+	RoleHolder = "holder"
+)
+
+// ErrProtocolInstanceNotFound is returned when the protocol instance record does not exist.
+var ErrProtocolInstanceNotFound = statemachine.ErrProtocolInstanceNotFound
+
+// ProtocolInstance is the record of the protocol instance (thread) along with its history.
+type ProtocolInstance = statemachine.ProtocolInstance
+
+// HistoryEntry describes the handled message of the protocol instance.
+type HistoryEntry = statemachine.HistoryEntry
+
+// newHistory returns the history of the protocol instances.
+func (s *Service) newHistory() *statemachine.History {
+	return statemachine.NewHistory(&statemachine.HistoryDefinition{
+		Role: roleFromState,
+		Now: func() time.Time {
+			return s.now()
+		},
+	}, s.store)
+}
+
+// saveHistoryEntry appends the entry to the history of the protocol instance.
+func (s *Service) saveHistoryEntry(md *metaData, stateNames []string) error {
+	return s.history.Save(&HistoryEntry{
+		PIID:       md.PIID,
+		MyDID:      md.MyDID,
+		TheirDID:   md.TheirDID,
+		StateNames: stateNames,
+		Msg:        md.Msg,
+	})
+}
+
+// ProtocolInstances returns the records of the protocol instances.
+func (s *Service) ProtocolInstances() ([]*ProtocolInstance, error) {
+	return s.history.ProtocolInstances()
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piID.
+func (s *Service) ProtocolInstance(piID string) (*ProtocolInstance, error) {
+	return s.history.ProtocolInstance(piID)
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piID.
+func (s *Service) DeleteProtocolInstance(piID string) error {
+	return s.history.DeleteProtocolInstance(piID)
+}
+
+func roleFromState(name string) string {
+	switch name {
+	case stateNameProposalReceived, stateNameOfferSent, stateNameRequestReceived, stateNameCredentialIssued:
+		return RoleIssuer
+	case stateNameProposalSent, stateNameOfferReceived, stateNameRequestSent, stateNameCredentialReceived:
+		return RoleHolder
+	default:
+		return ""
+	}
+}
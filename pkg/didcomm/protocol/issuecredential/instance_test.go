@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	issuecredentialMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestService_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+
+	svc, err := New(provider)
+	require.NoError(t, err)
+
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	instances, err := svc.ProtocolInstances()
+	require.NoError(t, err)
+	require.Empty(t, instances)
+
+	_, err = svc.ProtocolInstance("piid")
+	require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+
+	actions := make(chan service.DIDCommAction, 1)
+	require.NoError(t, svc.RegisterActionEvent(actions))
+
+	messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+	messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).Return(nil)
+
+	offer := service.NewDIDCommMsgMap(OfferCredential{Type: OfferCredentialMsgType})
+	require.NoError(t, offer.SetID(uuid.New().String()))
+
+	piid, err := svc.HandleOutbound(offer, Alice, Bob)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now.Add(time.Minute) }
+
+	msg := service.NewDIDCommMsgMap(RequestCredential{Type: RequestCredentialMsgType})
+	require.NoError(t, msg.SetID(uuid.New().String()))
+	msg["~thread"] = map[string]interface{}{"thid": piid}
+
+	_, err = svc.HandleInbound(msg, Alice, Bob)
+	require.NoError(t, err)
+
+	(<-actions).Continue(WithIssueCredential(&IssueCredential{}))
+
+	instance, err := svc.ProtocolInstance(piid)
+	require.NoError(t, err)
+	require.Equal(t, piid, instance.PIID)
+	require.Equal(t, RoleIssuer, instance.Role)
+	require.Equal(t, Alice, instance.MyDID)
+	require.Equal(t, Bob, instance.TheirDID)
+	require.Equal(t, stateNameCredentialIssued, instance.StateName)
+	require.True(t, now.Equal(instance.CreatedAt))
+	require.True(t, now.Add(time.Minute).Equal(instance.UpdatedAt))
+	require.Len(t, instance.History, 2)
+	require.Equal(t, []string{stateNameOfferSent}, instance.History[0].StateNames)
+	require.Equal(t, OfferCredentialMsgType, instance.History[0].Msg.Type())
+	require.Equal(t, []string{stateNameRequestReceived, stateNameCredentialIssued}, instance.History[1].StateNames)
+	require.Equal(t, RequestCredentialMsgType, instance.History[1].Msg.Type())
+
+	instances, err = svc.ProtocolInstances()
+	require.NoError(t, err)
+	require.Equal(t, []*ProtocolInstance{instance}, instances)
+
+	require.NoError(t, svc.DeleteProtocolInstance(piid))
+	require.True(t, errors.Is(svc.DeleteProtocolInstance(piid), ErrProtocolInstanceNotFound))
+
+	_, err = svc.ProtocolInstance(piid)
+	require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+}
+
+func Test_roleFromState(t *testing.T) {
+	require.Equal(t, RoleIssuer, roleFromState(stateNameOfferSent))
+	require.Equal(t, RoleIssuer, roleFromState(stateNameProposalReceived))
+	require.Equal(t, RoleHolder, roleFromState(stateNameRequestSent))
+	require.Equal(t, RoleHolder, roleFromState(stateNameProposalSent))
+	require.Empty(t, roleFromState(stateNameAbandoning))
+}
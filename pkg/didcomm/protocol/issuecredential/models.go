@@ -51,6 +51,9 @@ type OfferCredential struct {
 	// OffersAttach is a slice of attachments that further define the credential being offered.
 	// This might be used to clarify which formats or format versions will be issued.
 	OffersAttach []decorator.Attachment `json:"offers~attach,omitempty"`
+	// Service is the ~service decorator which allows the holder to reply without a connection,
+	// e.g when the offer is delivered as the attachment of the out-of-band request.
+	Service *decorator.Service `json:"~service,omitempty"`
 }
 
 // RequestCredential is a message sent by the potential Holder to the Issuer,
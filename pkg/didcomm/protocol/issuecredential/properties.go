@@ -13,6 +13,7 @@ const (
 	theirDIDPropKey = "theirDID"
 	piidPropKey     = "piid"
 	errorPropKey    = "error"
+	formatPropKey   = "format"
 )
 
 type eventProps struct {
@@ -20,6 +21,7 @@ type eventProps struct {
 	myDID      string
 	theirDID   string
 	piid       string
+	format     string
 	err        error
 }
 
@@ -34,6 +36,7 @@ func newEventProps(md *metaData) *eventProps {
 		myDID:      md.MyDID,
 		theirDID:   md.TheirDID,
 		piid:       md.PIID,
+		format:     md.Format,
 		err:        md.err,
 	}
 }
@@ -50,6 +53,11 @@ func (e *eventProps) PIID() string {
 	return e.piid
 }
 
+// Format returns the name of the negotiated credential format family.
+func (e *eventProps) Format() string {
+	return e.format
+}
+
 func (e eventProps) Err() error {
 	if errors.As(e.err, &customError{}) {
 		return nil
@@ -72,6 +80,10 @@ func (e eventProps) All() map[string]interface{} {
 		e.properties[piidPropKey] = e.piid
 	}
 
+	if e.format != "" {
+		e.properties[formatPropKey] = e.format
+	}
+
 	if e.Err() != nil {
 		e.properties[errorPropKey] = e.Err()
 	}
@@ -18,6 +18,7 @@ func TestEventProps_All(t *testing.T) {
 	md.MyDID = "MyDID"
 	md.TheirDID = "TheirDID"
 	md.PIID = "PIID"
+	md.Format = FormatLDProofVC
 	md.err = errors.New("error")
 
 	props := newEventProps(md)
@@ -25,11 +26,13 @@ func TestEventProps_All(t *testing.T) {
 	require.Equal(t, md.MyDID, props.MyDID())
 	require.Equal(t, md.TheirDID, props.TheirDID())
 	require.Equal(t, md.PIID, props.PIID())
+	require.Equal(t, md.Format, props.Format())
 	require.Equal(t, md.err, props.Err())
-	require.Equal(t, 4, len(props.All()))
+	require.Equal(t, 5, len(props.All()))
 
 	md.err = customError{errors.New("error")}
 	md.MyDID = ""
+	md.Format = ""
 
 	props = newEventProps(md)
 
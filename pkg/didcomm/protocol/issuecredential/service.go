@@ -10,12 +10,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -60,6 +64,8 @@ type customError struct{ error }
 type transitionalPayload struct {
 	Action
 	StateName string
+	// Format is the name of the negotiated credential format family.
+	Format string
 }
 
 // metaData type to store data for internal usage.
@@ -76,10 +82,27 @@ type metaData struct {
 	proposeCredential *ProposeCredential
 	requestCredential *RequestCredential
 	issueCredential   *IssueCredential
+	// formatHandler handles the negotiated credential format family.
+	formatHandler FormatHandler
 	// err is used to determine whether callback was stopped
 	// e.g the user received an action event and executes Stop(err) function
 	// in that case `err` is equal to `err` which was passing to Stop function.
 	err error
+	// connectionID is the connection the protocol instance belongs to, it is used in the log fields only.
+	connectionID string
+}
+
+// logFields returns fields which allow to correlate log lines of the protocol instance.
+func (md *metaData) logFields() log.Fields {
+	fields := service.LogFields(md.Msg)
+	fields[log.FieldProtocol] = Name
+	fields[log.FieldConnectionID] = md.connectionID
+
+	if md.state != nil {
+		fields[log.FieldState] = md.state.Name()
+	}
+
+	return fields
 }
 
 func (md *metaData) Message() service.DIDCommMsg {
@@ -172,18 +195,30 @@ type Provider interface {
 	StorageProvider() storage.Provider
 }
 
+// connectionStoreProvider is implemented by the providers giving access to the connection store
+// (e.g. the framework context), the connection ID is added to the log fields then.
+type connectionStoreProvider interface {
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
 // Service for the issuecredential protocol.
 type Service struct {
 	service.Action
 	service.Message
-	store      storage.Store
-	callbacks  chan *metaData
-	messenger  service.Messenger
-	middleware Handler
+	store       storage.Store
+	callbacks   chan *metaData
+	messenger   service.Messenger
+	middleware  Handler
+	timeouts    *statemachine.Timeouts
+	history     *statemachine.History
+	now         func() time.Time
+	formats     []FormatHandler
+	connections *connection.Lookup
 }
 
 // New returns the issuecredential service.
-func New(p Provider) (*Service, error) {
+func New(p Provider, opts ...ServiceOption) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Name)
 	if err != nil {
 		return nil, err
@@ -194,10 +229,27 @@ func New(p Provider) (*Service, error) {
 		store:      store,
 		callbacks:  make(chan *metaData),
 		middleware: initialHandler,
+		now:        time.Now,
+	}
+
+	svc.timeouts = svc.newTimeouts()
+	svc.history = svc.newHistory()
+
+	if cp, ok := p.(connectionStoreProvider); ok {
+		svc.connections, err = connection.NewLookup(cp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, opt := range opts {
+		opt(svc)
 	}
 
 	// start the listener
 	go svc.startInternalListener()
+	// start abandoning the expired protocol instances
+	svc.timeouts.Start()
 
 	return svc, nil
 }
@@ -231,6 +283,19 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 	md.MyDID = myDID
 	md.TheirDID = theirDID
 
+	md.Format, err = s.negotiateFormat(msg)
+	if err != nil {
+		logger.WithFields(s.logFields(md)).Errorf("negotiate format: %s", err)
+		// rejects the message with the problem report
+		md.state = &abandoning{Code: formatErrorCode(err)}
+
+		if err = s.handle(md); err != nil {
+			return "", fmt.Errorf("handle inbound: %w", err)
+		}
+
+		return msg.ThreadID()
+	}
+
 	// trigger action event based on message type for inbound messages
 	if canTriggerActionEvents(msg) {
 		err = s.saveTransitionalPayload(md.PIID, md.transitionalPayload)
@@ -262,6 +327,11 @@ func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string)
 	md.MyDID = myDID
 	md.TheirDID = theirDID
 
+	md.Format, err = s.negotiateFormat(msg)
+	if err != nil {
+		return "", fmt.Errorf("negotiate format: %w", err)
+	}
+
 	if err = s.handle(md); err != nil {
 		return "", fmt.Errorf("handle outbound: %w", err)
 	}
@@ -333,7 +403,7 @@ func (s *Service) startInternalListener() {
 			continue
 		}
 
-		logger.Errorf("abandoning: %s", msg.err)
+		logger.WithFields(s.logFields(msg)).Errorf("abandoning: %s", msg.err)
 		msg.state = &abandoning{Code: codeInternalError}
 
 		if err := s.handle(msg); err != nil {
@@ -349,13 +419,15 @@ func isNoOp(s state) bool {
 
 func (s *Service) handle(md *metaData) error {
 	var (
-		current   = md.state
-		actions   []stateAction
-		stateName string
+		current    = md.state
+		actions    []stateAction
+		stateName  string
+		stateNames []string
 	)
 
 	for !isNoOp(current) {
 		stateName = current.Name()
+		stateNames = append(stateNames, stateName)
 
 		next, action, err := s.execute(current, md)
 		if err != nil {
@@ -375,12 +447,22 @@ func (s *Service) handle(md *metaData) error {
 		return fmt.Errorf("failed to persist state %s: %w", stateName, err)
 	}
 
+	if len(stateNames) > 0 {
+		if err := s.saveHistoryEntry(md, stateNames); err != nil {
+			return fmt.Errorf("save history entry: %w", err)
+		}
+	}
+
 	for _, action := range actions {
 		if err := action(s.messenger); err != nil {
 			return fmt.Errorf("action %s: %w", stateName, err)
 		}
 	}
 
+	if err := s.timeouts.Save((*statemachine.Action)(&md.Action), stateName); err != nil {
+		return fmt.Errorf("save expiry: %w", err)
+	}
+
 	return nil
 }
 
@@ -629,8 +711,20 @@ func (s *Service) newDIDCommActionMsg(md *metaData) service.DIDCommAction {
 	}
 }
 
+// logFields returns the log fields of the protocol instance, its connection is looked up once.
+func (s *Service) logFields(md *metaData) log.Fields {
+	if md.connectionID == "" && s.connections != nil && md.MyDID != "" && md.TheirDID != "" {
+		// the connection is unknown for the connectionless exchanges, the ID stays empty then
+		md.connectionID, _ = s.connections.GetConnectionIDByDIDs(md.MyDID, md.TheirDID) // nolint: errcheck
+	}
+
+	return md.logFields()
+}
+
 func (s *Service) execute(next state, md *metaData) (state, stateAction, error) {
 	md.state = next
+	logger.WithFields(s.logFields(md)).Debugf("execute state")
+
 	s.sendMsgEvents(md, next.Name(), service.PreState)
 
 	defer s.sendMsgEvents(md, next.Name(), service.PostState)
@@ -641,6 +735,14 @@ func (s *Service) execute(next state, md *metaData) (state, stateAction, error)
 		return nil, nil, fmt.Errorf("middleware: %w", err)
 	}
 
+	md.formatHandler = s.formatHandlerByName(md.Format)
+
+	if md.inbound && !isTerminal(next.Name()) {
+		if err := md.applyFormat(); err != nil {
+			return nil, nil, fmt.Errorf("apply format: %w", err)
+		}
+	}
+
 	exec := next.ExecuteOutbound
 	if md.inbound {
 		exec = next.ExecuteInbound
@@ -668,6 +770,11 @@ func (s *Service) Name() string {
 	return Name
 }
 
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{strings.TrimSuffix(Spec, "/")}
+}
+
 // Accept msg checks the msg type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
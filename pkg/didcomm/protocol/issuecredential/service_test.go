@@ -9,6 +9,7 @@ package issuecredential
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -178,6 +179,7 @@ func TestService_HandleInbound(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	store.EXPECT().Put(historyEntryKey{}, gomock.Any()).Return(nil).AnyTimes()
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).AnyTimes()
@@ -1086,6 +1088,7 @@ func TestService_HandleOutbound(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	store.EXPECT().Put(historyEntryKey{}, gomock.Any()).Return(nil).AnyTimes()
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).AnyTimes()
@@ -1463,6 +1466,10 @@ func TestService_Name(t *testing.T) {
 	require.Equal(t, (*Service).Name(nil), Name)
 }
 
+func TestService_Protocols(t *testing.T) {
+	require.Equal(t, []string{"https://didcomm.org/issue-credential/2.0"}, (*Service).Protocols(nil))
+}
+
 func TestService_Accept(t *testing.T) {
 	require.True(t, (*Service).Accept(nil, ProposeCredentialMsgType))
 	require.True(t, (*Service).Accept(nil, OfferCredentialMsgType))
@@ -1492,3 +1499,16 @@ func TestService_canTriggerActionEvents(t *testing.T) {
 
 	require.False(t, canTriggerActionEvents(service.NewDIDCommMsgMap(struct{}{})))
 }
+
+// historyEntryKey matches the keys of the protocol instance history entries.
+type historyEntryKey struct{}
+
+func (historyEntryKey) Matches(x interface{}) bool {
+	key, ok := x.(string)
+
+	return ok && strings.HasPrefix(key, "protocol_instance_")
+}
+
+func (historyEntryKey) String() string {
+	return "has prefix protocol_instance_"
+}
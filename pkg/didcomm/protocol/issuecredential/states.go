@@ -331,11 +331,18 @@ func (s *offerReceived) ExecuteInbound(md *metaData) (state, stateAction, error)
 		return nil, nil, fmt.Errorf("decode: %w", err)
 	}
 
+	// requests the credential in the negotiated format
+	formats, attachments, err := filterFormats(md.formatHandler, offer.Formats, offer.OffersAttach)
+	if err != nil {
+		return nil, nil, fmt.Errorf("filter formats: %w", err)
+	}
+
 	// creates the state's action
 	action := func(messenger service.Messenger) error {
 		return messenger.ReplyTo(md.Msg.ID(), service.NewDIDCommMsgMap(RequestCredential{
 			Type:           RequestCredentialMsgType,
-			RequestsAttach: offer.OffersAttach,
+			Formats:        formats,
+			RequestsAttach: attachments,
 		}))
 	}
 
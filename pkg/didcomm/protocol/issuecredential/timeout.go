@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// ServiceOption configures the service.
+type ServiceOption func(s *Service)
+
+// WithTimeout sets the default time the protocol instance waits for the next message from the other agent.
+// Once the time elapses the protocol instance is abandoned and the problem report is sent to the other agent.
+// The ~timing.expires_time decorator of the message takes precedence over the default.
+// By default, only protocol instances with the ~timing.expires_time decorator expire.
+func WithTimeout(timeout time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.timeouts.SetTimeout(timeout)
+	}
+}
+
+// WithReaperInterval sets how often the service looks for expired protocol instances (one minute by default).
+func WithReaperInterval(interval time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.timeouts.SetReaperInterval(interval)
+	}
+}
+
+// ThreadState describes the current state of the protocol instance.
+type ThreadState = statemachine.ThreadState
+
+func isTerminal(stateName string) bool {
+	return stateName == stateNameDone || stateName == stateNameAbandoning
+}
+
+// newTimeouts returns the timeouts of the issue credential protocol instances.
+func (s *Service) newTimeouts() *statemachine.Timeouts {
+	return statemachine.NewTimeouts(&statemachine.TimeoutDefinition{
+		ProblemReportMsgType: ProblemReportMsgType,
+		Terminal:             isTerminal,
+		CurrentState:         s.currentStateName,
+		ActionPending: func(piID string) (bool, error) {
+			_, err := s.getTransitionalPayload(piID)
+			if errors.Is(err, storage.ErrDataNotFound) {
+				return false, nil
+			}
+
+			return err == nil, err
+		},
+		States:  s.states,
+		Abandon: s.abandon,
+		Now: func() time.Time {
+			return s.now()
+		},
+	}, s.store, s.messenger)
+}
+
+// abandon persists and reports the abandoning state of the expired protocol instance.
+func (s *Service) abandon(e *statemachine.Expiry, problemReport service.DIDCommMsgMap) error {
+	return s.handle(&metaData{
+		transitionalPayload: transitionalPayload{
+			StateName: stateNameAbandoning,
+			Action: Action{
+				PIID:     e.PIID,
+				Msg:      problemReport,
+				MyDID:    e.MyDID,
+				TheirDID: e.TheirDID,
+			},
+		},
+		state:      &abandoning{},
+		msgClone:   problemReport.Clone(),
+		inbound:    true,
+		properties: map[string]interface{}{},
+	})
+}
+
+// states returns the current states of the protocol instances.
+func (s *Service) states() ([]ThreadState, error) {
+	records := s.store.Iterator(stateNameKey, stateNameKey+storage.EndKeySuffix)
+	defer records.Release()
+
+	var states []ThreadState
+
+	for records.Next() {
+		states = append(states, ThreadState{
+			PIID:      strings.TrimPrefix(string(records.Key()), stateNameKey),
+			StateName: string(records.Value()),
+		})
+	}
+
+	if records.Error() != nil {
+		return nil, records.Error()
+	}
+
+	return states, nil
+}
+
+// ThreadStates returns the current states of the protocol instances.
+func (s *Service) ThreadStates() ([]ThreadState, error) {
+	return s.timeouts.ThreadStates()
+}
@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	issuecredentialMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestService_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newService := func(t *testing.T, messenger service.Messenger, opts ...ServiceOption) *Service {
+		t.Helper()
+
+		provider := issuecredentialMocks.NewMockProvider(ctrl)
+		provider.EXPECT().Messenger().Return(messenger)
+		provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+
+		svc, err := New(provider, opts...)
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	sendOffer := func(t *testing.T, svc *Service, messenger *serviceMocks.MockMessenger,
+		timing *decorator.Timing) string {
+		t.Helper()
+
+		messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		msg := service.NewDIDCommMsgMap(OfferCredential{Type: OfferCredentialMsgType})
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		if timing != nil {
+			msg["~timing"] = timing
+		}
+
+		_, err := svc.HandleOutbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		return msg.ID()
+	}
+
+	expectProblemReport := func(messenger *serviceMocks.MockMessenger, piid string, done chan struct{}) {
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				defer close(done)
+
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, ProblemReportMsgType, r.Type)
+				require.Equal(t, statemachine.CodeExpiredError, r.Description.Code)
+				require.Equal(t, &service.NestedReplyOpts{ThreadID: piid, MyDID: Alice, TheirDID: Bob}, opts)
+
+				return nil
+			})
+	}
+
+	t.Run("Abandons the expired protocol instance (default timeout)", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		svc := newService(t, messenger, WithTimeout(time.Hour))
+
+		states := make(chan service.StateMsg, 4)
+		require.NoError(t, svc.RegisterMsgEvent(states))
+
+		piid := sendOffer(t, svc, messenger, nil)
+
+		// offer-sent events
+		<-states
+		<-states
+
+		threads, err := svc.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Equal(t, piid, threads[0].PIID)
+		require.Equal(t, stateNameOfferSent, threads[0].StateName)
+		require.NotNil(t, threads[0].ExpiresTime)
+
+		// the protocol instance did not expire yet
+		require.NoError(t, svc.timeouts.AbandonExpired())
+
+		done := make(chan struct{})
+		expectProblemReport(messenger, piid, done)
+
+		svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		require.NoError(t, svc.timeouts.AbandonExpired())
+
+		<-done
+
+		event := <-states
+		require.Equal(t, stateNameAbandoning, event.StateID)
+		require.Equal(t, ProblemReportMsgType, event.Msg.Type())
+
+		threads, err = svc.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Equal(t, stateNameDone, threads[0].StateName)
+		require.Nil(t, threads[0].ExpiresTime)
+	})
+
+	t.Run("Abandons the expired protocol instance (~timing decorator)", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		svc := newService(t, messenger, WithReaperInterval(time.Millisecond))
+
+		piid := uuid.New().String()
+
+		done := make(chan struct{})
+		expectProblemReport(messenger, piid, done)
+		messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		msg := service.NewDIDCommMsgMap(OfferCredential{Type: OfferCredentialMsgType})
+		msg["~timing"] = &decorator.Timing{ExpiresTime: time.Now().Add(-time.Minute)}
+		require.NoError(t, msg.SetID(piid))
+
+		_, err := svc.HandleOutbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
+	t.Run("Does not abandon the protocol instance waiting for the action", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		svc := newService(t, messenger, WithTimeout(time.Hour))
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		piid := sendOffer(t, svc, messenger, nil)
+
+		msg := service.NewDIDCommMsgMap(RequestCredential{Type: RequestCredentialMsgType})
+		msg["~thread"] = map[string]interface{}{"thid": piid}
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		_, err := svc.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		<-actions
+
+		svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		require.NoError(t, svc.timeouts.AbandonExpired())
+
+		threads, err := svc.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Equal(t, stateNameOfferSent, threads[0].StateName)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -336,6 +337,14 @@ func (s *Service) Name() string {
 	return Coordination
 }
 
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{
+		strings.TrimSuffix(CoordinationSpec, "/"),
+		strings.TrimSuffix(service.ForwardMsgType, "/forward"),
+	}
+}
+
 func (s *Service) handleInboundRequest(c *callback) error {
 	// unmarshal the payload
 	request := &Request{}
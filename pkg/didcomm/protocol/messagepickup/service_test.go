@@ -36,6 +36,7 @@ func TestServiceNew(t *testing.T) {
 		svc, err := getService()
 		require.NoError(t, err)
 		require.Equal(t, MessagePickup, svc.Name())
+		require.Equal(t, []string{"https://didcomm.org/messagepickup/1.0"}, svc.Protocols())
 	})
 
 	t.Run("test new service name - store error", func(t *testing.T) {
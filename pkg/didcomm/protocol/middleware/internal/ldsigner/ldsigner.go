@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ldsigner contains the helpers shared by the middlewares which sign credentials and presentations
+// with linked data proofs using the keys of the KMS.
+package ldsigner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	// Ed25519Signature2018 ed25519 signature suite.
+	Ed25519Signature2018 = "Ed25519Signature2018"
+	// JSONWebSignature2020 json web signature suite.
+	JSONWebSignature2020 = "JsonWebSignature2020"
+
+	creatorParts = 2
+)
+
+// KMSSigner signs the data with the key of the verification method kept in the KMS.
+type KMSSigner struct {
+	// DID is the DID of the verification method.
+	DID       string
+	keyHandle interface{}
+	crypto    crypto.Crypto
+}
+
+// NewKMSSigner returns the signer of the verification method (didID#keyID),
+// the keyID part is used to get the key from the KMS.
+func NewKMSSigner(keyManager kms.KeyManager, c crypto.Crypto, verificationMethod string) (*KMSSigner, error) {
+	idSplit := strings.Split(verificationMethod, "#")
+	if len(idSplit) != creatorParts {
+		return nil, fmt.Errorf("wrong verification method %q", verificationMethod)
+	}
+
+	keyHandle, err := keyManager.Get(idSplit[1])
+	if err != nil {
+		return nil, fmt.Errorf("get key: %w", err)
+	}
+
+	return &KMSSigner{DID: idSplit[0], keyHandle: keyHandle, crypto: c}, nil
+}
+
+// Sign signs the data.
+func (s *KMSSigner) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.keyHandle)
+}
+
+// SignatureSuite returns the signature suite of the given type (Ed25519Signature2018 by default) along with
+// the name of the type, false is returned if the type is not supported.
+func (s *KMSSigner) SignatureSuite(signatureType string) (signer.SignatureSuite, string, bool) {
+	switch signatureType {
+	case Ed25519Signature2018, "":
+		return ed25519signature2018.New(suite.WithSigner(s)), Ed25519Signature2018, true
+	case JSONWebSignature2020:
+		return jsonwebsignature2020.New(suite.WithSigner(s)), JSONWebSignature2020, true
+	default:
+		return nil, "", false
+	}
+}
+
+// FindAttachment returns the attachment with the given ID or nil if there is no such attachment.
+func FindAttachment(attachments []decorator.Attachment, id string) *decorator.Attachment {
+	for i := range attachments {
+		if attachments[i].ID == id {
+			return &attachments[i]
+		}
+	}
+
+	return nil
+}
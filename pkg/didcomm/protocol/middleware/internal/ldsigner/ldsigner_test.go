@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldsigner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+)
+
+func TestNewKMSSigner(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewKMSSigner(&mockkms.KeyManager{}, &mockcrypto.Crypto{SignValue: []byte("signature")},
+			"did:example:123#key-1")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", s.DID)
+
+		signature, err := s.Sign([]byte("data"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("signature"), signature)
+	})
+
+	t.Run("Wrong verification method", func(t *testing.T) {
+		_, err := NewKMSSigner(&mockkms.KeyManager{}, &mockcrypto.Crypto{}, "did:example:123")
+		require.EqualError(t, err, `wrong verification method "did:example:123"`)
+	})
+
+	t.Run("KMS error", func(t *testing.T) {
+		_, err := NewKMSSigner(&mockkms.KeyManager{GetKeyErr: errors.New("no key")}, &mockcrypto.Crypto{},
+			"did:example:123#key-1")
+		require.EqualError(t, err, "get key: no key")
+	})
+}
+
+func TestKMSSigner_SignatureSuite(t *testing.T) {
+	s := &KMSSigner{}
+
+	for signatureType, expected := range map[string]string{
+		"":                   Ed25519Signature2018,
+		Ed25519Signature2018: Ed25519Signature2018,
+		JSONWebSignature2020: JSONWebSignature2020,
+	} {
+		signatureSuite, name, ok := s.SignatureSuite(signatureType)
+		require.True(t, ok)
+		require.NotNil(t, signatureSuite)
+		require.Equal(t, expected, name)
+	}
+
+	_, _, ok := s.SignatureSuite("BbsBlsSignature2020")
+	require.False(t, ok)
+}
+
+func TestFindAttachment(t *testing.T) {
+	attachments := []decorator.Attachment{{ID: "1"}, {ID: "2"}}
+
+	require.Equal(t, &attachments[1], FindAttachment(attachments, "2"))
+	require.Nil(t, FindAttachment(attachments, "3"))
+}
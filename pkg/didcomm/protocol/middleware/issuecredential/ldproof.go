@@ -0,0 +1,275 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/piprate/json-gold/ld"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/internal/ldsigner"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	stateNameRequestReceived = "request-received"
+
+	// LDProofVCDetailFormat is the format of the request attachment which describes the credential to be issued
+	// (Aries RFC 0593).
+	LDProofVCDetailFormat = issuecredential.LDProofVCDetailFormat
+	// LDProofVCFormat is the format of the issued credential attachment (Aries RFC 0593).
+	LDProofVCFormat = issuecredential.LDProofVCFormat
+
+	// Ed25519Signature2018 ed25519 signature suite.
+	Ed25519Signature2018 = ldsigner.Ed25519Signature2018
+	// JSONWebSignature2020 json web signature suite.
+	JSONWebSignature2020 = ldsigner.JSONWebSignature2020
+
+	defaultProofPurpose = "assertionMethod"
+	ldJSONMimeType      = "application/ld+json"
+)
+
+// LDProofVCDetail is the payload of the aries/ld-proof-vc-detail attachment.
+type LDProofVCDetail struct {
+	// Credential is the credential to be issued without the proof.
+	Credential json.RawMessage `json:"credential"`
+	// Options describes the proof the Holder wants to receive.
+	Options *LDProofVCDetailOptions `json:"options"`
+}
+
+// LDProofVCDetailOptions describes the proof the Holder wants to receive.
+type LDProofVCDetailOptions struct {
+	ProofPurpose string     `json:"proofPurpose,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+	Domain       string     `json:"domain,omitempty"`
+	Challenge    string     `json:"challenge,omitempty"`
+	ProofType    string     `json:"proofType"`
+}
+
+// IssuancePolicy decides whether the requested credential can be issued.
+// The credential may be amended by the policy (e.g issuer name, issuance date) before it is signed,
+// returning an error rejects the request. The issuer ID is set to the DID of the verification method,
+// the policy must not change it.
+type IssuancePolicy func(metadata Metadata, vc *verifiable.Credential, options *LDProofVCDetailOptions) error
+
+// SignerProvider contains dependencies for the IssueLDProofCredentials middleware function.
+type SignerProvider interface {
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+}
+
+type ldProofOptions struct {
+	verificationMethod string
+	policy             IssuancePolicy
+	documentLoader     ld.DocumentLoader
+}
+
+// LDProofOpt describes option signature for the IssueLDProofCredentials middleware function.
+type LDProofOpt func(opts *ldProofOptions)
+
+// WithVerificationMethod sets the verification method (didID#keyID) which is used to sign credentials.
+// The keyID part is used to get the key from the KMS.
+func WithVerificationMethod(verificationMethod string) LDProofOpt {
+	return func(opts *ldProofOptions) {
+		opts.verificationMethod = verificationMethod
+	}
+}
+
+// WithIssuancePolicy sets the policy which is applied to every requested credential before it is signed.
+// The requests are rejected if no policy is set.
+func WithIssuancePolicy(policy IssuancePolicy) LDProofOpt {
+	return func(opts *ldProofOptions) {
+		opts.policy = policy
+	}
+}
+
+// WithJSONLDDocumentLoader sets the JSON-LD document loader which is used to parse and sign credentials.
+func WithJSONLDDocumentLoader(loader ld.DocumentLoader) LDProofOpt {
+	return func(opts *ldProofOptions) {
+		opts.documentLoader = loader
+	}
+}
+
+// IssueLDProofCredentials the helper function for the issue credential protocol which issues credentials
+// requested with the aries/ld-proof-vc-detail format. The Issuer accepts the request with an IssueCredential
+// message which has no credentials attached, the middleware builds the credentials from the request,
+// applies the issuance policy, signs them and attaches them to the message.
+// The credentials are issued by the DID of the verification method which key must be in the KMS.
+func IssueLDProofCredentials(p SignerProvider, opts ...LDProofOpt) (issuecredential.Middleware, error) {
+	options := &ldProofOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	s, err := ldsigner.NewKMSSigner(p.KMS(), p.Crypto(), options.verificationMethod)
+	if err != nil {
+		return nil, fmt.Errorf("kms signer: %w", err)
+	}
+
+	issuer := &ldProofIssuer{signer: s, options: options}
+
+	return func(next issuecredential.Handler) issuecredential.Handler {
+		return issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+			if metadata.StateName() != stateNameRequestReceived {
+				return next.Handle(metadata)
+			}
+
+			msg := metadata.IssueCredential()
+			// the Issuer did not accept the request or has already attached credentials
+			if msg == nil || len(msg.CredentialsAttach) > 0 {
+				return next.Handle(metadata)
+			}
+
+			request := issuecredential.RequestCredential{}
+
+			err := metadata.Message().Decode(&request)
+			if err != nil {
+				return fmt.Errorf("decode: %w", err)
+			}
+
+			details, err := ldProofVCDetails(&request)
+			if err != nil {
+				return fmt.Errorf("ld proof vc details: %w", err)
+			}
+
+			for _, detail := range details {
+				vc, err := issuer.issue(metadata, detail)
+				if err != nil {
+					return fmt.Errorf("issue credential: %w", err)
+				}
+
+				attachID := uuid.New().String()
+
+				msg.Formats = append(msg.Formats, issuecredential.Format{
+					AttachID: attachID,
+					Format:   LDProofVCFormat,
+				})
+
+				msg.CredentialsAttach = append(msg.CredentialsAttach, decorator.Attachment{
+					ID:       attachID,
+					MimeType: ldJSONMimeType,
+					Data:     decorator.AttachmentData{JSON: vc},
+				})
+			}
+
+			return next.Handle(metadata)
+		})
+	}, nil
+}
+
+func ldProofVCDetails(request *issuecredential.RequestCredential) ([]*LDProofVCDetail, error) {
+	var details []*LDProofVCDetail
+
+	for _, format := range request.Formats {
+		if format.Format != LDProofVCDetailFormat {
+			continue
+		}
+
+		attachment := ldsigner.FindAttachment(request.RequestsAttach, format.AttachID)
+		if attachment == nil {
+			return nil, fmt.Errorf("attachment %s not found", format.AttachID)
+		}
+
+		raw, err := attachment.Data.Fetch()
+		if err != nil {
+			return nil, fmt.Errorf("fetch: %w", err)
+		}
+
+		detail := &LDProofVCDetail{}
+
+		if err = json.Unmarshal(raw, detail); err != nil {
+			return nil, fmt.Errorf("unmarshal: %w", err)
+		}
+
+		if len(detail.Credential) == 0 {
+			return nil, errors.New("credential was not provided")
+		}
+
+		if detail.Options == nil {
+			detail.Options = &LDProofVCDetailOptions{}
+		}
+
+		details = append(details, detail)
+	}
+
+	return details, nil
+}
+
+type ldProofIssuer struct {
+	signer  *ldsigner.KMSSigner
+	options *ldProofOptions
+}
+
+func (i *ldProofIssuer) issue(metadata Metadata, detail *LDProofVCDetail) (*verifiable.Credential, error) {
+	opts := i.options
+
+	if opts.policy == nil {
+		return nil, errors.New("no issuance policy is configured")
+	}
+
+	credOpts := []verifiable.CredentialOpt{verifiable.WithDisabledProofCheck()}
+	if opts.documentLoader != nil {
+		credOpts = append(credOpts, verifiable.WithJSONLDDocumentLoader(opts.documentLoader))
+	}
+
+	vc, err := verifiable.ParseCredential(detail.Credential, credOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("parse credential: %w", err)
+	}
+
+	// the credential is issued by the owner of the key, whatever issuer was requested
+	vc.Issuer = verifiable.Issuer{ID: i.signer.DID}
+
+	if err = opts.policy(metadata, vc, detail.Options); err != nil {
+		return nil, fmt.Errorf("issuance policy: %w", err)
+	}
+
+	if vc.Issuer.ID != i.signer.DID {
+		return nil, fmt.Errorf("issuance policy: the issuer %s is not the DID of the verification method", vc.Issuer.ID)
+	}
+
+	signatureSuite, signatureType, ok := i.signer.SignatureSuite(detail.Options.ProofType)
+	if !ok {
+		return nil, fmt.Errorf("proof type %s is not supported", detail.Options.ProofType)
+	}
+
+	purpose := detail.Options.ProofPurpose
+	if purpose == "" {
+		purpose = defaultProofPurpose
+	}
+
+	var jsonldOpts []jsonld.ProcessorOpts
+	if opts.documentLoader != nil {
+		jsonldOpts = append(jsonldOpts, jsonld.WithDocumentLoader(opts.documentLoader))
+	}
+
+	err = vc.AddLinkedDataProof(&verifiable.LinkedDataProofContext{
+		VerificationMethod:      opts.verificationMethod,
+		SignatureRepresentation: verifiable.SignatureJWS,
+		SignatureType:           signatureType,
+		Suite:                   signatureSuite,
+		Created:                 detail.Options.Created,
+		Domain:                  detail.Options.Domain,
+		Challenge:               detail.Options.Challenge,
+		Purpose:                 purpose,
+	}, jsonldOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("add linked data proof: %w", err)
+	}
+
+	return vc, nil
+}
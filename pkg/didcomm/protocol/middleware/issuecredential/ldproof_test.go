@@ -0,0 +1,289 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+)
+
+const (
+	verificationMethod = "did:example:76e12ec712ebc6f1c221ebfeb1f#key-1"
+	unsignedVC         = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1", "https://example.org/context/v1"],
+  "id": "http://example.edu/credentials/1872",
+  "type": ["VerifiableCredential", "UniversityDegreeCredential"],
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "2010-01-01T19:23:24Z",
+  "credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"}
+}`
+	exampleContext = `{
+  "@context": {
+    "@version": 1.1,
+    "@protected": true,
+    "UniversityDegreeCredential": "https://example.org/examples#UniversityDegreeCredential",
+    "referenceNumber": "https://example.org/examples#referenceNumber"
+  }
+}`
+)
+
+type signerProvider struct {
+	kms    kms.KeyManager
+	crypto crypto.Crypto
+}
+
+func (p *signerProvider) KMS() kms.KeyManager {
+	return p.kms
+}
+
+func (p *signerProvider) Crypto() crypto.Crypto {
+	return p.crypto
+}
+
+func newSignerProvider() *signerProvider {
+	return &signerProvider{
+		kms:    &mockkms.KeyManager{},
+		crypto: &mockcrypto.Crypto{SignValue: []byte("signature")},
+	}
+}
+
+func requestMsg(t *testing.T, detail string) service.DIDCommMsg {
+	t.Helper()
+
+	return service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+		Type: issuecredential.RequestCredentialMsgType,
+		Formats: []issuecredential.Format{{
+			AttachID: "detail",
+			Format:   LDProofVCDetailFormat,
+		}},
+		RequestsAttach: []decorator.Attachment{{
+			ID:   "detail",
+			Data: decorator.AttachmentData{JSON: json.RawMessage(detail)},
+		}},
+	})
+}
+
+func newMetadata(ctrl *gomock.Controller, msg service.DIDCommMsg,
+	issue *issuecredential.IssueCredential) *mocks.MockMetadata {
+	metadata := mocks.NewMockMetadata(ctrl)
+	metadata.EXPECT().StateName().Return(stateNameRequestReceived).AnyTimes()
+	metadata.EXPECT().IssueCredential().Return(issue).AnyTimes()
+	metadata.EXPECT().Message().Return(msg).AnyTimes()
+
+	return metadata
+}
+
+func issueLDProofCredentials(t *testing.T, p SignerProvider, opts ...LDProofOpt) issuecredential.Middleware {
+	t.Helper()
+
+	mw, err := IssueLDProofCredentials(p, append([]LDProofOpt{WithVerificationMethod(verificationMethod)}, opts...)...)
+	require.NoError(t, err)
+
+	return mw
+}
+
+func allowAll(Metadata, *verifiable.Credential, *LDProofVCDetailOptions) error {
+	return nil
+}
+
+func TestIssueLDProofCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loader := jsonldLoader(t)
+	detail := `{"credential":` + unsignedVC + `,"options":{"proofPurpose":"assertionMethod","domain":"example.com"}}`
+
+	t.Run("Ignores other states", func(t *testing.T) {
+		next := issuecredential.HandlerFunc(func(issuecredential.Metadata) error { return nil })
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return("offer-sent")
+
+		require.NoError(t, issueLDProofCredentials(t, newSignerProvider())(next).Handle(metadata))
+	})
+
+	t.Run("Credentials already attached", func(t *testing.T) {
+		next := issuecredential.HandlerFunc(func(issuecredential.Metadata) error { return nil })
+
+		issue := &issuecredential.IssueCredential{CredentialsAttach: []decorator.Attachment{{ID: "vc"}}}
+
+		require.NoError(t, issueLDProofCredentials(t, newSignerProvider())(next).Handle(
+			newMetadata(ctrl, requestMsg(t, detail), issue)))
+		require.Len(t, issue.CredentialsAttach, 1)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		next := issuecredential.HandlerFunc(func(issuecredential.Metadata) error { return nil })
+
+		issue := &issuecredential.IssueCredential{}
+		policy := func(_ Metadata, vc *verifiable.Credential, options *LDProofVCDetailOptions) error {
+			require.Equal(t, "example.com", options.Domain)
+			vc.CustomFields = verifiable.CustomFields{"referenceNumber": 83294847}
+
+			return nil
+		}
+
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithIssuancePolicy(policy),
+			WithJSONLDDocumentLoader(loader),
+		)(next).Handle(newMetadata(ctrl, requestMsg(t, detail), issue))
+		require.NoError(t, err)
+
+		require.Len(t, issue.Formats, 1)
+		require.Equal(t, LDProofVCFormat, issue.Formats[0].Format)
+		require.Len(t, issue.CredentialsAttach, 1)
+		require.Equal(t, issue.Formats[0].AttachID, issue.CredentialsAttach[0].ID)
+
+		raw, err := issue.CredentialsAttach[0].Data.Fetch()
+		require.NoError(t, err)
+
+		vc, err := verifiable.ParseCredential(raw, verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+		require.Len(t, vc.Proofs, 1)
+		require.Equal(t, Ed25519Signature2018, vc.Proofs[0]["type"])
+		require.Equal(t, verificationMethod, vc.Proofs[0]["verificationMethod"])
+		require.Equal(t, "example.com", vc.Proofs[0]["domain"])
+		require.EqualValues(t, 83294847, vc.CustomFields["referenceNumber"])
+	})
+
+	t.Run("Issued by the DID of the verification method", func(t *testing.T) {
+		next := issuecredential.HandlerFunc(func(issuecredential.Metadata) error { return nil })
+
+		issue := &issuecredential.IssueCredential{}
+		d := `{"credential":` + strings.Replace(unsignedVC, "did:example:76e12ec712ebc6f1c221ebfeb1f",
+			"did:example:holder", 1) + `,"options":{}}`
+
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithIssuancePolicy(func(_ Metadata, vc *verifiable.Credential, _ *LDProofVCDetailOptions) error {
+				require.Equal(t, "did:example:76e12ec712ebc6f1c221ebfeb1f", vc.Issuer.ID)
+
+				return nil
+			}),
+			WithJSONLDDocumentLoader(loader),
+		)(next).Handle(newMetadata(ctrl, requestMsg(t, d), issue))
+		require.NoError(t, err)
+
+		raw, err := issue.CredentialsAttach[0].Data.Fetch()
+		require.NoError(t, err)
+
+		vc, err := verifiable.ParseCredential(raw, verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+		require.Equal(t, "did:example:76e12ec712ebc6f1c221ebfeb1f", vc.Issuer.ID)
+	})
+
+	t.Run("No issuance policy", func(t *testing.T) {
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithJSONLDDocumentLoader(loader),
+		)(nil).Handle(newMetadata(ctrl, requestMsg(t, detail), &issuecredential.IssueCredential{}))
+		require.EqualError(t, err, "issue credential: no issuance policy is configured")
+	})
+
+	t.Run("Policy rejects the credential", func(t *testing.T) {
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithIssuancePolicy(func(Metadata, *verifiable.Credential, *LDProofVCDetailOptions) error {
+				return errors.New("not allowed")
+			}),
+			WithJSONLDDocumentLoader(loader),
+		)(nil).Handle(newMetadata(ctrl, requestMsg(t, detail), &issuecredential.IssueCredential{}))
+		require.EqualError(t, err, "issue credential: issuance policy: not allowed")
+	})
+
+	t.Run("Policy changes the issuer", func(t *testing.T) {
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithIssuancePolicy(func(_ Metadata, vc *verifiable.Credential, _ *LDProofVCDetailOptions) error {
+				vc.Issuer = verifiable.Issuer{ID: "did:example:other"}
+
+				return nil
+			}),
+			WithJSONLDDocumentLoader(loader),
+		)(nil).Handle(newMetadata(ctrl, requestMsg(t, detail), &issuecredential.IssueCredential{}))
+		require.EqualError(t, err, "issue credential: issuance policy: "+
+			"the issuer did:example:other is not the DID of the verification method")
+	})
+
+	t.Run("Unsupported proof type", func(t *testing.T) {
+		d := `{"credential":` + unsignedVC + `,"options":{"proofType":"BbsBlsSignature2020"}}`
+
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithIssuancePolicy(allowAll),
+			WithJSONLDDocumentLoader(loader),
+		)(nil).Handle(newMetadata(ctrl, requestMsg(t, d), &issuecredential.IssueCredential{}))
+		require.EqualError(t, err, "issue credential: proof type BbsBlsSignature2020 is not supported")
+	})
+
+	t.Run("Wrong verification method", func(t *testing.T) {
+		_, err := IssueLDProofCredentials(newSignerProvider(), WithVerificationMethod("did:example:123"))
+		require.EqualError(t, err, `kms signer: wrong verification method "did:example:123"`)
+	})
+
+	t.Run("No verification method", func(t *testing.T) {
+		_, err := IssueLDProofCredentials(newSignerProvider())
+		require.EqualError(t, err, `kms signer: wrong verification method ""`)
+	})
+
+	t.Run("KMS error", func(t *testing.T) {
+		p := newSignerProvider()
+		p.kms = &mockkms.KeyManager{GetKeyErr: errors.New("no key")}
+
+		_, err := IssueLDProofCredentials(p, WithVerificationMethod(verificationMethod))
+		require.EqualError(t, err, "kms signer: get key: no key")
+	})
+
+	t.Run("Invalid credential", func(t *testing.T) {
+		err := issueLDProofCredentials(t, newSignerProvider(),
+			WithIssuancePolicy(allowAll),
+		)(nil).Handle(newMetadata(ctrl, requestMsg(t, `{"credential":{}}`), &issuecredential.IssueCredential{}))
+		require.Contains(t, err.Error(), "issue credential: parse credential")
+	})
+
+	t.Run("Credential was not provided", func(t *testing.T) {
+		err := issueLDProofCredentials(t, newSignerProvider())(nil).Handle(
+			newMetadata(ctrl, requestMsg(t, `{"options":{}}`), &issuecredential.IssueCredential{}))
+		require.EqualError(t, err, "ld proof vc details: credential was not provided")
+	})
+
+	t.Run("Attachment not found", func(t *testing.T) {
+		msg := service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+			Type:    issuecredential.RequestCredentialMsgType,
+			Formats: []issuecredential.Format{{AttachID: "unknown", Format: LDProofVCDetailFormat}},
+		})
+
+		err := issueLDProofCredentials(t, newSignerProvider())(nil).Handle(
+			newMetadata(ctrl, msg, &issuecredential.IssueCredential{}))
+		require.EqualError(t, err, "ld proof vc details: attachment unknown not found")
+	})
+}
+
+func jsonldLoader(t *testing.T) *ld.CachingDocumentLoader {
+	t.Helper()
+
+	reader, err := ld.DocumentFromReader(strings.NewReader(exampleContext))
+	require.NoError(t, err)
+
+	loader := verifiable.CachingJSONLDLoader()
+	loader.AddDocument("https://example.org/context/v1", reader)
+
+	return loader
+}
@@ -69,7 +69,8 @@ func SaveCredentials(p Provider) issuecredential.Middleware {
 			myDID, _ := properties[myDIDKey].(string)
 			// nolint: errcheck
 			theirDID, _ := properties[theirDIDKey].(string)
-			if myDID == "" || theirDID == "" {
+			// both DIDs are absent if the protocol runs connectionless (~service decorator)
+			if (myDID == "") != (theirDID == "") {
 				return errors.New("myDID or theirDID is absent")
 			}
 
@@ -152,10 +152,10 @@ func TestSaveCredentials(t *testing.T) {
 		require.EqualError(t, SaveCredentials(provider)(next).Handle(metadata), "save credential: "+errMsg)
 	})
 
-	t.Run("No DIDs", func(t *testing.T) {
+	t.Run("No their DID", func(t *testing.T) {
 		metadata := mocks.NewMockMetadata(ctrl)
 		metadata.EXPECT().StateName().Return(stateNameCredentialReceived)
-		metadata.EXPECT().Properties().Return(map[string]interface{}{})
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: myDIDKey})
 		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(issuecredential.IssueCredential{
 			Type: issuecredential.IssueCredentialMsgType,
 			CredentialsAttach: []decorator.Attachment{
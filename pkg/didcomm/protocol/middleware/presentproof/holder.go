@@ -0,0 +1,318 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/piprate/json-gold/ld"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/internal/ldsigner"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	storeverifiable "github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
+)
+
+const (
+	stateNameRequestReceived = "request-received"
+
+	// Ed25519Signature2018 ed25519 signature suite.
+	Ed25519Signature2018 = ldsigner.Ed25519Signature2018
+	// JSONWebSignature2020 json web signature suite.
+	JSONWebSignature2020 = ldsigner.JSONWebSignature2020
+
+	authenticationProofPurpose = "authentication"
+	ldJSONMimeType             = "application/ld+json"
+)
+
+type options struct {
+	documentLoader     ld.DocumentLoader
+	verificationMethod string
+	signatureType      string
+}
+
+// Opt describes option signature for the presentation definition helpers.
+type Opt func(opts *options)
+
+// VerifierOpt is the option of the VerifyPresentationDefinitions middleware function.
+// Deprecated: use Opt, the options are shared by the holder and the verifier.
+type VerifierOpt = Opt
+
+// WithJSONLDDocumentLoader sets the JSON-LD document loader which is used to parse and sign presentations
+// and credentials.
+func WithJSONLDDocumentLoader(loader ld.DocumentLoader) Opt {
+	return func(opts *options) {
+		opts.documentLoader = loader
+	}
+}
+
+// WithVerificationMethod sets the verification method (didID#keyID) which is used by the holder to sign
+// presentations. The keyID part is used to get the key from the KMS.
+func WithVerificationMethod(verificationMethod string) Opt {
+	return func(opts *options) {
+		opts.verificationMethod = verificationMethod
+	}
+}
+
+// WithSignatureType sets the signature suite which is used by the holder to sign presentations
+// (Ed25519Signature2018 by default).
+func WithSignatureType(signatureType string) Opt {
+	return func(opts *options) {
+		opts.signatureType = signatureType
+	}
+}
+
+// HolderProvider contains dependencies for the Holder.
+type HolderProvider interface {
+	VerifiableStore() storeverifiable.Store
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+}
+
+// Holder builds presentations which satisfy presentation definitions from the credentials
+// of the verifiable store.
+type Holder struct {
+	store   storeverifiable.Store
+	kms     kms.KeyManager
+	crypto  crypto.Crypto
+	options *options
+}
+
+// NewHolder returns a new Holder instance.
+func NewHolder(p HolderProvider, opts ...Opt) *Holder {
+	h := &Holder{
+		store:   p.VerifiableStore(),
+		kms:     p.KMS(),
+		crypto:  p.Crypto(),
+		options: &options{},
+	}
+
+	for _, opt := range opts {
+		opt(h.options)
+	}
+
+	return h
+}
+
+// Candidates returns the stored credentials which can be submitted for the input descriptors of the definition.
+// The credentials are keyed by the input descriptor ID, a descriptor without candidates is not present in the result.
+func (h *Holder) Candidates(definition *presexch.PresentationDefinitions) (map[string][]*verifiable.Credential, error) {
+	records, err := h.store.GetCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("get credentials: %w", err)
+	}
+
+	candidates := make(map[string][]*verifiable.Credential)
+
+	for _, record := range records {
+		vc, err := h.store.GetCredential(record.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get credential: %w", err)
+		}
+
+		for _, descriptor := range definition.InputDescriptors {
+			// the schema and the constraints of the input descriptor are checked against the credential itself
+			if definition.MatchCredential(descriptor.ID, vc) != nil {
+				continue
+			}
+
+			candidates[descriptor.ID] = append(candidates[descriptor.ID], vc)
+		}
+	}
+
+	return candidates, nil
+}
+
+// Present builds and signs the presentation which satisfies the definition, the credentials are selected
+// from the candidates to meet the submission requirements of the definition.
+func (h *Holder) Present(definition *presexch.PresentationDefinitions,
+	opts *PresentationDefinitionOptions) (*verifiable.Presentation, error) {
+	candidates, err := h.Candidates(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := definition.SelectCredentials(candidates)
+	if err != nil {
+		return nil, fmt.Errorf("select credentials: %w", err)
+	}
+
+	return h.BuildPresentation(definition, selected, opts)
+}
+
+// BuildPresentation builds the presentation with the presentation submission from the selected credentials
+// and signs it. The credentials are keyed by the input descriptor ID.
+func (h *Holder) BuildPresentation(definition *presexch.PresentationDefinitions,
+	selected map[string]*verifiable.Credential, opts *PresentationDefinitionOptions) (*verifiable.Presentation, error) {
+	vp, err := definition.CreateVP(selected)
+	if err != nil {
+		return nil, fmt.Errorf("create presentation: %w", err)
+	}
+
+	if opts == nil {
+		opts = &PresentationDefinitionOptions{}
+	}
+
+	// the verifier checks the presentation is signed by its holder
+	vp.Holder = strings.Split(h.options.verificationMethod, "#")[0]
+
+	signatureSuite, signatureType, err := h.signatureSuite()
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonldOpts []jsonld.ProcessorOpts
+	if h.options.documentLoader != nil {
+		jsonldOpts = append(jsonldOpts, jsonld.WithDocumentLoader(h.options.documentLoader))
+	}
+
+	err = vp.AddLinkedDataProof(&verifiable.LinkedDataProofContext{
+		VerificationMethod:      h.options.verificationMethod,
+		SignatureRepresentation: verifiable.SignatureJWS,
+		SignatureType:           signatureType,
+		Suite:                   signatureSuite,
+		Domain:                  opts.Domain,
+		Challenge:               opts.Challenge,
+		Purpose:                 authenticationProofPurpose,
+	}, jsonldOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("add linked data proof: %w", err)
+	}
+
+	return vp, nil
+}
+
+func (h *Holder) signatureSuite() (signer.SignatureSuite, string, error) {
+	s, err := ldsigner.NewKMSSigner(h.kms, h.crypto, h.options.verificationMethod)
+	if err != nil {
+		return nil, "", fmt.Errorf("kms signer: %w", err)
+	}
+
+	signatureSuite, signatureType, ok := s.SignatureSuite(h.options.signatureType)
+	if !ok {
+		return nil, "", fmt.Errorf("signature type %s is not supported", h.options.signatureType)
+	}
+
+	return signatureSuite, signatureType, nil
+}
+
+// BuildPresentations the helper function for the present proof protocol which builds presentations requested
+// with the dif/presentation-exchange/definitions format. The Prover accepts the request with a Presentation
+// message which has no presentations attached, the middleware selects the stored credentials which satisfy
+// the definition, builds and signs the presentation and attaches it to the message.
+func BuildPresentations(p HolderProvider, opts ...Opt) presentproof.Middleware {
+	holder := NewHolder(p, opts...)
+
+	return func(next presentproof.Handler) presentproof.Handler {
+		return presentproof.HandlerFunc(func(metadata presentproof.Metadata) error {
+			if metadata.StateName() != stateNameRequestReceived {
+				return next.Handle(metadata)
+			}
+
+			msg := metadata.Presentation()
+			// the Prover did not accept the request or has already attached presentations
+			if msg == nil || len(msg.PresentationsAttach) > 0 {
+				return next.Handle(metadata)
+			}
+
+			request := &presentproof.RequestPresentation{}
+
+			if err := metadata.Message().Decode(request); err != nil {
+				return fmt.Errorf("decode: %w", err)
+			}
+
+			raw, err := presentationDefinition(request)
+			if err != nil {
+				return fmt.Errorf("presentation definition: %w", err)
+			}
+
+			if raw == nil {
+				return next.Handle(metadata)
+			}
+
+			definition := &PresentationDefinitionAttachment{}
+
+			if err = json.Unmarshal(raw, definition); err != nil {
+				return fmt.Errorf("unmarshal presentation definition: %w", err)
+			}
+
+			if definition.PresentationDefinition == nil {
+				return errors.New("presentation definition is absent")
+			}
+
+			vp, err := holder.Present(definition.PresentationDefinition, definition.Options)
+			if err != nil {
+				return fmt.Errorf("build presentation: %w", err)
+			}
+
+			attachID := uuid.New().String()
+
+			msg.Formats = append(msg.Formats, presentproof.Format{
+				AttachID: attachID,
+				Format:   PresentationSubmissionFormat,
+			})
+
+			msg.PresentationsAttach = append(msg.PresentationsAttach, decorator.Attachment{
+				ID:       attachID,
+				MimeType: ldJSONMimeType,
+				Data:     decorator.AttachmentData{JSON: vp},
+			})
+
+			return next.Handle(metadata)
+		})
+	}
+}
+
+// AutoRespondPresentationRequests is a utility function to respond to request-presentation messages which contain
+// the presentation definition automatically. The request is accepted with an empty Presentation message which is
+// built by the BuildPresentations middleware, other action events are passed to the given channel.
+// This is a blocking function and use this function with a goroutine.
+//
+// Usage:
+//
+//	actions := make(chan service.DIDCommAction)
+//	others := make(chan service.DIDCommAction)
+//	err = presentProofClient.RegisterActionEvent(actions)
+//	go presentproof.AutoRespondPresentationRequests(actions, others)
+func AutoRespondPresentationRequests(actions <-chan service.DIDCommAction, others chan<- service.DIDCommAction) {
+	for action := range actions {
+		if action.Message.Type() == presentproof.RequestPresentationMsgType && hasPresentationDefinition(action) {
+			action.Continue(presentproof.WithPresentation(&presentproof.Presentation{}))
+
+			continue
+		}
+
+		others <- action
+	}
+}
+
+func hasPresentationDefinition(action service.DIDCommAction) bool {
+	request := &presentproof.RequestPresentation{}
+
+	if err := action.Message.Decode(request); err != nil {
+		return false
+	}
+
+	for _, format := range request.Formats {
+		if format.Format == PresentationDefinitionFormat {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,368 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/presentproof"
+	mocksstore "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/store/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	storeverifiable "github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
+)
+
+const verificationMethod = "did:example:ebfeb1f712ebc6f1c276e12ec21#key-1"
+
+type holderProvider struct {
+	store  storeverifiable.Store
+	kms    kms.KeyManager
+	crypto crypto.Crypto
+}
+
+func (p *holderProvider) VerifiableStore() storeverifiable.Store {
+	return p.store
+}
+
+func (p *holderProvider) KMS() kms.KeyManager {
+	return p.kms
+}
+
+func (p *holderProvider) Crypto() crypto.Crypto {
+	return p.crypto
+}
+
+func newHolderProvider(store storeverifiable.Store) *holderProvider {
+	return &holderProvider{
+		store:  store,
+		kms:    &mockkms.KeyManager{},
+		crypto: &mockcrypto.Crypto{SignValue: []byte("signature")},
+	}
+}
+
+func degreeCredential() *verifiable.Credential {
+	return &verifiable.Credential{
+		ID:      "http://example.edu/credentials/1872",
+		Context: []string{"https://www.w3.org/2018/credentials/v1", contextURI},
+		Types:   []string{"VerifiableCredential", degreeType},
+		Issuer:  verifiable.Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"},
+		Issued:  util.NewTime(time.Date(2010, 1, 1, 19, 23, 24, 0, time.UTC)),
+		Subject: "did:example:ebfeb1f712ebc6f1c276e12ec21",
+	}
+}
+
+func otherCredential() *verifiable.Credential {
+	vc := degreeCredential()
+	vc.ID = "http://example.edu/credentials/other"
+	vc.Context = []string{"https://www.w3.org/2018/credentials/v1"}
+	vc.Types = []string{"VerifiableCredential"}
+
+	return vc
+}
+
+func newCredentialStore(ctrl *gomock.Controller, vcs ...*verifiable.Credential) *mocksstore.MockStore {
+	vcs = append([]*verifiable.Credential{otherCredential()}, vcs...)
+	records := make([]*storeverifiable.Record, len(vcs))
+
+	store := mocksstore.NewMockStore(ctrl)
+
+	for i, vc := range vcs {
+		records[i] = &storeverifiable.Record{ID: vc.ID, Context: vc.Context}
+		store.EXPECT().GetCredential(vc.ID).Return(vc, nil).AnyTimes()
+	}
+
+	store.EXPECT().GetCredentials().Return(records, nil).AnyTimes()
+
+	return store
+}
+
+func degreeDefinition() *presexch.PresentationDefinitions {
+	return &presexch.PresentationDefinitions{
+		InputDescriptors: []*presexch.InputDescriptor{{
+			ID:     "degree",
+			Schema: []*presexch.Schema{{URI: degreeType}},
+		}},
+	}
+}
+
+func TestHolder_Candidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		vc := degreeCredential()
+		definition := degreeDefinition()
+		definition.InputDescriptors = append(definition.InputDescriptors, &presexch.InputDescriptor{
+			ID:     "passport",
+			Schema: []*presexch.Schema{{URI: "https://example.org/passport/v1"}},
+		})
+
+		candidates, err := NewHolder(newHolderProvider(newCredentialStore(ctrl, vc))).Candidates(definition)
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		require.Equal(t, []*verifiable.Credential{vc}, candidates["degree"])
+	})
+
+	t.Run("Get credentials error", func(t *testing.T) {
+		store := mocksstore.NewMockStore(ctrl)
+		store.EXPECT().GetCredentials().Return(nil, errors.New("test error"))
+
+		_, err := NewHolder(newHolderProvider(store)).Candidates(degreeDefinition())
+		require.EqualError(t, err, "get credentials: test error")
+	})
+
+	t.Run("Get credential error", func(t *testing.T) {
+		store := mocksstore.NewMockStore(ctrl)
+		store.EXPECT().GetCredentials().Return([]*storeverifiable.Record{{ID: "vc", Context: []string{contextURI}}}, nil)
+		store.EXPECT().GetCredential("vc").Return(nil, errors.New("test error"))
+
+		_, err := NewHolder(newHolderProvider(store)).Candidates(degreeDefinition())
+		require.EqualError(t, err, "get credential: test error")
+	})
+}
+
+func TestHolder_Present(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loader := jsonldLoader(t)
+
+	t.Run("Success", func(t *testing.T) {
+		vc := degreeCredential()
+		holder := NewHolder(newHolderProvider(newCredentialStore(ctrl, vc)),
+			WithVerificationMethod(verificationMethod),
+			WithJSONLDDocumentLoader(loader),
+		)
+
+		vp, err := holder.Present(degreeDefinition(), &PresentationDefinitionOptions{
+			Challenge: "challenge",
+			Domain:    "example.com",
+		})
+		require.NoError(t, err)
+		require.Len(t, vp.Proofs, 1)
+		require.Equal(t, Ed25519Signature2018, vp.Proofs[0]["type"])
+		require.Equal(t, authenticationProofPurpose, vp.Proofs[0]["proofPurpose"])
+		require.Equal(t, "challenge", vp.Proofs[0]["challenge"])
+		require.Equal(t, "example.com", vp.Proofs[0]["domain"])
+		require.Equal(t, "did:example:ebfeb1f712ebc6f1c276e12ec21", vp.Holder)
+
+		raw, err := json.Marshal(vp)
+		require.NoError(t, err)
+
+		received, err := verifiable.ParseUnverifiedPresentation(raw)
+		require.NoError(t, err)
+
+		matched, err := degreeDefinition().Match(received, presexch.WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+		require.Equal(t, vc.ID, matched["degree"].ID)
+	})
+
+	t.Run("No credentials", func(t *testing.T) {
+		definition := degreeDefinition()
+		definition.InputDescriptors[0].Schema[0].URI = "https://example.org/passport/v1"
+
+		_, err := NewHolder(newHolderProvider(newCredentialStore(ctrl, degreeCredential()))).Present(definition, nil)
+		require.EqualError(t, err, "select credentials: no credential provided for input descriptor degree")
+	})
+
+	t.Run("Picks the credentials for the submission requirements", func(t *testing.T) {
+		vc := degreeCredential()
+		second := degreeCredential()
+		second.ID = "http://example.edu/credentials/1873"
+
+		definition := &presexch.PresentationDefinitions{
+			SubmissionRequirements: []*presexch.SubmissionRequirement{{Rule: presexch.Pick, Count: 1, From: "A"}},
+			InputDescriptors: []*presexch.InputDescriptor{
+				{ID: "degree", Group: []string{"A"}, Schema: []*presexch.Schema{{URI: degreeType}}},
+				{ID: "diploma", Group: []string{"A"}, Schema: []*presexch.Schema{{URI: degreeType}}},
+				{ID: "passport", Group: []string{"A"}, Schema: []*presexch.Schema{{URI: "https://example.org/passport/v1"}}},
+			},
+		}
+
+		holder := NewHolder(newHolderProvider(newCredentialStore(ctrl, vc, second)),
+			WithVerificationMethod(verificationMethod),
+			WithJSONLDDocumentLoader(loader),
+		)
+
+		vp, err := holder.Present(definition, nil)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 1)
+
+		raw, err := json.Marshal(vp)
+		require.NoError(t, err)
+
+		received, err := verifiable.ParseUnverifiedPresentation(raw)
+		require.NoError(t, err)
+
+		matched, err := definition.Match(received, presexch.WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		require.Equal(t, vc.ID, matched["degree"].ID)
+	})
+
+	t.Run("Signature type is not supported", func(t *testing.T) {
+		holder := NewHolder(newHolderProvider(newCredentialStore(ctrl, degreeCredential())),
+			WithVerificationMethod(verificationMethod),
+			WithSignatureType("BbsBlsSignature2020"),
+		)
+
+		_, err := holder.Present(degreeDefinition(), nil)
+		require.EqualError(t, err, "signature type BbsBlsSignature2020 is not supported")
+	})
+
+	t.Run("KMS error", func(t *testing.T) {
+		p := newHolderProvider(newCredentialStore(ctrl, degreeCredential()))
+		p.kms = &mockkms.KeyManager{GetKeyErr: errors.New("no key")}
+
+		_, err := NewHolder(p, WithVerificationMethod(verificationMethod)).Present(degreeDefinition(), nil)
+		require.EqualError(t, err, "kms signer: get key: no key")
+	})
+
+	t.Run("Wrong verification method", func(t *testing.T) {
+		_, err := NewHolder(newHolderProvider(newCredentialStore(ctrl, degreeCredential())),
+			WithVerificationMethod("did:example:123"),
+		).Present(degreeDefinition(), nil)
+		require.Contains(t, err.Error(), "wrong verification method")
+	})
+}
+
+func TestBuildPresentations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loader := jsonldLoader(t)
+	next := presentproof.HandlerFunc(func(presentproof.Metadata) error { return nil })
+
+	newMetadata := func(request *presentproof.RequestPresentation,
+		presentation *presentproof.Presentation) *mocks.MockMetadata {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived).AnyTimes()
+		metadata.EXPECT().Presentation().Return(presentation).AnyTimes()
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(request)).AnyTimes()
+
+		return metadata
+	}
+
+	t.Run("Ignores other states", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNamePresentationReceived)
+
+		require.NoError(t, BuildPresentations(newHolderProvider(nil))(next).Handle(metadata))
+	})
+
+	t.Run("Request was not accepted", func(t *testing.T) {
+		require.NoError(t, BuildPresentations(newHolderProvider(nil))(next).Handle(
+			newMetadata(requestPresentation(definitionReq), nil)))
+	})
+
+	t.Run("Presentations already attached", func(t *testing.T) {
+		presentation := &presentproof.Presentation{PresentationsAttach: []decorator.Attachment{{ID: "vp"}}}
+
+		require.NoError(t, BuildPresentations(newHolderProvider(nil))(next).Handle(
+			newMetadata(requestPresentation(definitionReq), presentation)))
+		require.Len(t, presentation.PresentationsAttach, 1)
+	})
+
+	t.Run("No presentation definition", func(t *testing.T) {
+		presentation := &presentproof.Presentation{}
+
+		require.NoError(t, BuildPresentations(newHolderProvider(nil))(next).Handle(newMetadata(
+			&presentproof.RequestPresentation{Type: presentproof.RequestPresentationMsgType}, presentation)))
+		require.Empty(t, presentation.PresentationsAttach)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		presentation := &presentproof.Presentation{}
+
+		err := BuildPresentations(newHolderProvider(newCredentialStore(ctrl, degreeCredential())),
+			WithVerificationMethod(verificationMethod),
+			WithJSONLDDocumentLoader(loader),
+		)(next).Handle(newMetadata(requestPresentation(definitionReq), presentation))
+		require.NoError(t, err)
+
+		require.Len(t, presentation.Formats, 1)
+		require.Equal(t, PresentationSubmissionFormat, presentation.Formats[0].Format)
+		require.Len(t, presentation.PresentationsAttach, 1)
+		require.Equal(t, presentation.Formats[0].AttachID, presentation.PresentationsAttach[0].ID)
+
+		raw, err := presentation.PresentationsAttach[0].Data.Fetch()
+		require.NoError(t, err)
+
+		vp, err := verifiable.ParseUnverifiedPresentation(raw)
+		require.NoError(t, err)
+		require.Len(t, vp.Proofs, 1)
+		require.Equal(t, "23516943-1d79-4ebd-8981-623f036365ef", vp.Proofs[0]["challenge"])
+	})
+
+	t.Run("Build presentation error", func(t *testing.T) {
+		err := BuildPresentations(newHolderProvider(newCredentialStore(ctrl, degreeCredential())))(next).Handle(
+			newMetadata(requestPresentation(definitionReq), &presentproof.Presentation{}))
+		require.Contains(t, err.Error(), "build presentation: kms signer")
+	})
+
+	t.Run("Presentation definition is absent", func(t *testing.T) {
+		err := BuildPresentations(newHolderProvider(nil))(next).Handle(
+			newMetadata(requestPresentation(`{"options":{}}`), &presentproof.Presentation{}))
+		require.EqualError(t, err, "presentation definition is absent")
+	})
+}
+
+func TestAutoRespondPresentationRequests(t *testing.T) {
+	actions := make(chan service.DIDCommAction)
+	others := make(chan service.DIDCommAction)
+
+	go AutoRespondPresentationRequests(actions, others)
+
+	done := make(chan interface{})
+
+	actions <- service.DIDCommAction{
+		Message:  service.NewDIDCommMsgMap(requestPresentation(definitionReq)),
+		Continue: func(args interface{}) { done <- args },
+	}
+
+	select {
+	case args := <-done:
+		_, ok := args.(presentproof.Opt)
+		require.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	proposal := service.NewDIDCommMsgMap(presentproof.ProposePresentation{
+		Type: presentproof.ProposePresentationMsgType,
+	})
+
+	for _, msg := range []service.DIDCommMsg{
+		proposal,
+		service.NewDIDCommMsgMap(presentproof.RequestPresentation{Type: presentproof.RequestPresentationMsgType}),
+	} {
+		actions <- service.DIDCommAction{Message: msg}
+
+		select {
+		case action := <-others:
+			require.Equal(t, msg.Type(), action.Message.Type())
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	close(actions)
+}
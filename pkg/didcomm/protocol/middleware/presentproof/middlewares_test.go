@@ -140,10 +140,10 @@ func TestSavePresentation(t *testing.T) {
 		require.EqualError(t, SavePresentation(provider)(next).Handle(metadata), "save presentation: "+errMsg)
 	})
 
-	t.Run("No DIDs", func(t *testing.T) {
+	t.Run("No their DID", func(t *testing.T) {
 		metadata := mocks.NewMockMetadata(ctrl)
 		metadata.EXPECT().StateName().Return(stateNamePresentationReceived)
-		metadata.EXPECT().Properties().Return(map[string]interface{}{})
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: myDIDKey})
 		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(presentproof.Presentation{
 			Type: presentproof.PresentationMsgType,
 			PresentationsAttach: []decorator.Attachment{
@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/internal/ldsigner"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	stateNameRequestSent = "request-sent"
+	piidKey              = "piid"
+
+	// PresentationDefinitionFormat is the format of the request attachment which contains
+	// the presentation definition (Aries RFC 0510).
+	PresentationDefinitionFormat = "dif/presentation-exchange/definitions@v1.0"
+	// PresentationSubmissionFormat is the format of the presentation attachment which contains
+	// the presentation submission (Aries RFC 0510).
+	PresentationSubmissionFormat = "dif/presentation-exchange/submission@v1.0"
+
+	verifierStoreName = "presentproof-verifier"
+)
+
+// PresentationDefinitionAttachment is the payload of the dif/presentation-exchange/definitions attachment.
+type PresentationDefinitionAttachment struct {
+	Options                *PresentationDefinitionOptions    `json:"options,omitempty"`
+	PresentationDefinition *presexch.PresentationDefinitions `json:"presentation_definition"`
+}
+
+// PresentationDefinitionOptions are the options of the presentation definition.
+type PresentationDefinitionOptions struct {
+	Challenge string `json:"challenge,omitempty"`
+	Domain    string `json:"domain,omitempty"`
+}
+
+// VerifierProvider contains dependencies for the VerifyPresentationDefinitions middleware function.
+type VerifierProvider interface {
+	StorageProvider() storage.Provider
+	VDRegistry() vdrapi.Registry
+}
+
+// VerifyPresentationDefinitions the helper function for the present proof protocol which verifies the received
+// presentations against the presentation definition which was sent with the request-presentation message.
+// The definition is kept when the request is sent, when the presentation is received the proofs of the presentation
+// and its credentials are checked and the presentation submission is matched against the definition.
+// The presentation must be signed by its holder, either with the linked data proof or as the JWT,
+// for the challenge (nonce) and the domain (aud) of the request.
+// If the presentation does not satisfy the definition the protocol is declined with the problem report.
+func VerifyPresentationDefinitions(p VerifierProvider, opts ...Opt) (presentproof.Middleware, error) {
+	options := &options{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	store, err := p.StorageProvider().OpenStore(verifierStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	v := &verifier{
+		store:   store,
+		fetcher: verifiable.NewDIDKeyResolver(p.VDRegistry()).PublicKeyFetcher(),
+		options: options,
+	}
+
+	return func(next presentproof.Handler) presentproof.Handler {
+		return presentproof.HandlerFunc(func(metadata presentproof.Metadata) error {
+			switch metadata.StateName() {
+			case stateNameRequestSent:
+				if err := v.saveDefinition(metadata); err != nil {
+					return fmt.Errorf("save presentation definition: %w", err)
+				}
+			case stateNamePresentationReceived:
+				if err := v.verify(metadata); err != nil {
+					return err
+				}
+			}
+
+			return next.Handle(metadata)
+		})
+	}, nil
+}
+
+type verifier struct {
+	store   storage.Store
+	fetcher verifiable.PublicKeyFetcher
+	options *options
+}
+
+func (v *verifier) saveDefinition(metadata presentproof.Metadata) error {
+	request := metadata.RequestPresentation()
+	if request == nil {
+		if metadata.Message().Type() != presentproof.RequestPresentationMsgType {
+			return nil
+		}
+
+		request = &presentproof.RequestPresentation{}
+
+		if err := metadata.Message().Decode(request); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+	}
+
+	raw, err := presentationDefinition(request)
+	if err != nil || raw == nil {
+		return err
+	}
+
+	// nolint: errcheck
+	piid, _ := metadata.Properties()[piidKey].(string)
+	if piid == "" {
+		return errors.New("piid is absent")
+	}
+
+	return v.store.Put(piid, raw)
+}
+
+func presentationDefinition(request *presentproof.RequestPresentation) ([]byte, error) {
+	for _, format := range request.Formats {
+		if format.Format != PresentationDefinitionFormat {
+			continue
+		}
+
+		attachment := ldsigner.FindAttachment(request.RequestPresentationsAttach, format.AttachID)
+		if attachment == nil {
+			return nil, fmt.Errorf("attachment %s not found", format.AttachID)
+		}
+
+		raw, err := attachment.Data.Fetch()
+		if err != nil {
+			return nil, fmt.Errorf("fetch: %w", err)
+		}
+
+		return raw, nil
+	}
+
+	return nil, nil
+}
+
+func (v *verifier) verify(metadata presentproof.Metadata) error {
+	// nolint: errcheck
+	piid, _ := metadata.Properties()[piidKey].(string)
+
+	raw, err := v.store.Get(piid)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		// the request was sent without the presentation definition
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get presentation definition: %w", err)
+	}
+
+	// the definition is no longer needed, the presentation is either accepted or the protocol is abandoned
+	if err = v.store.Delete(piid); err != nil {
+		return fmt.Errorf("delete presentation definition: %w", err)
+	}
+
+	definition := &PresentationDefinitionAttachment{}
+
+	if err = json.Unmarshal(raw, definition); err != nil {
+		return fmt.Errorf("unmarshal presentation definition: %w", err)
+	}
+
+	if definition.PresentationDefinition == nil {
+		return errors.New("presentation definition is absent")
+	}
+
+	presentation := presentproof.Presentation{}
+	if err = metadata.Message().Decode(&presentation); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	opts := definition.Options
+	if opts == nil {
+		opts = &PresentationDefinitionOptions{}
+	}
+
+	if err = v.match(definition.PresentationDefinition, opts, presentation.PresentationsAttach); err != nil {
+		return presentproof.RejectedError(fmt.Errorf("verify presentation: %w", err))
+	}
+
+	return nil
+}
+
+func (v *verifier) match(definition *presexch.PresentationDefinitions, opts *PresentationDefinitionOptions,
+	data []decorator.Attachment) error {
+	if len(data) == 0 {
+		return errors.New("presentations were not provided")
+	}
+
+	vpOpts := []verifiable.PresentationOpt{verifiable.WithPresPublicKeyFetcher(v.fetcher)}
+	vcOpts := []verifiable.CredentialOpt{verifiable.WithPublicKeyFetcher(v.fetcher)}
+	matchOpts := []presexch.MatchOption{presexch.WithCredentialOptions(vcOpts...)}
+
+	if v.options.documentLoader != nil {
+		vpOpts = append(vpOpts, verifiable.WithPresJSONLDDocumentLoader(v.options.documentLoader))
+		matchOpts = append(matchOpts, presexch.WithJSONLDDocumentLoader(v.options.documentLoader))
+	}
+
+	for i := range data {
+		raw, err := data[i].Data.Fetch()
+		if err != nil {
+			return fmt.Errorf("fetch: %w", err)
+		}
+
+		// the JWT presentation may be attached as the JSON string
+		var vpJWT string
+		if json.Unmarshal(raw, &vpJWT) == nil {
+			raw = []byte(vpJWT)
+		}
+
+		vp, err := verifiable.ParsePresentation(raw, vpOpts...)
+		if err != nil {
+			return fmt.Errorf("parse presentation: %w", err)
+		}
+
+		if jwt.IsJWS(string(raw)) {
+			err = v.checkJWT(string(raw), vp, opts)
+		} else {
+			err = checkProofs(vp, opts)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err = definition.Match(vp, matchOpts...); err != nil {
+			return fmt.Errorf("match: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkProofs checks the presentation is signed by its holder for the challenge and the domain of the request,
+// the signatures are verified when the presentation is parsed.
+func checkProofs(vp *verifiable.Presentation, opts *PresentationDefinitionOptions) error {
+	if len(vp.Proofs) == 0 {
+		return errors.New("the presentation is not signed")
+	}
+
+	if vp.Holder == "" {
+		return errors.New("the presentation has no holder")
+	}
+
+	for _, proof := range vp.Proofs {
+		// nolint: errcheck
+		challenge, _ := proof["challenge"].(string)
+		if challenge != opts.Challenge {
+			return fmt.Errorf("the proof challenge %q does not match the request", challenge)
+		}
+
+		// nolint: errcheck
+		domain, _ := proof["domain"].(string)
+		if domain != opts.Domain {
+			return fmt.Errorf("the proof domain %q does not match the request", domain)
+		}
+
+		// nolint: errcheck
+		verificationMethod, _ := proof["verificationMethod"].(string)
+		if strings.Split(verificationMethod, "#")[0] != vp.Holder {
+			return fmt.Errorf("the verification method %q does not belong to the holder %q",
+				verificationMethod, vp.Holder)
+		}
+	}
+
+	return nil
+}
+
+// checkJWT checks the JWT presentation is signed by its holder (iss) for the challenge (nonce) and the domain (aud)
+// of the request. The signature is verified with the key of the holder when the presentation is parsed.
+func (v *verifier) checkJWT(vpJWT string, vp *verifiable.Presentation, opts *PresentationDefinitionOptions) error {
+	if vp.Holder == "" {
+		return errors.New("the presentation has no holder")
+	}
+
+	token, err := jwt.Parse(vpJWT, jwt.WithSignatureVerifier(jwt.NewVerifier(jwt.KeyResolverFunc(v.fetcher))))
+	if err != nil {
+		return fmt.Errorf("parse presentation jwt: %w", err)
+	}
+
+	// the key ID may be the DID URL, the key is resolved from the DID of the holder though
+	kid, _ := token.Headers.KeyID()
+	if did := strings.Split(kid, "#")[0]; strings.Contains(kid, "#") && did != "" && did != vp.Holder {
+		return fmt.Errorf("the key %q does not belong to the holder %q", kid, vp.Holder)
+	}
+
+	claims := struct {
+		jwt.Claims
+		Nonce string `json:"nonce,omitempty"`
+	}{}
+
+	if err = token.DecodeClaims(&claims); err != nil {
+		return fmt.Errorf("decode presentation jwt claims: %w", err)
+	}
+
+	if claims.Nonce != opts.Challenge {
+		return fmt.Errorf("the presentation nonce %q does not match the request", claims.Nonce)
+	}
+
+	if opts.Domain != "" && !claims.Audience.Contains(opts.Domain) {
+		return fmt.Errorf("the presentation audience does not contain the domain %q of the request", opts.Domain)
+	}
+
+	return nil
+}
@@ -0,0 +1,442 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/presentproof"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	piid          = "piid-1"
+	contextURI    = "https://example.org/context/v1"
+	degreeType    = "UniversityDegreeCredential"
+	definitionReq = `{
+  "options": {"challenge": "23516943-1d79-4ebd-8981-623f036365ef", "domain": "example.com"},
+  "presentation_definition": {
+    "input_descriptors": [{"id": "degree", "schema": [{"uri": "UniversityDegreeCredential"}]}]
+  }
+}`
+	exampleContext = `{
+  "@context": {
+    "@version": 1.1,
+    "@protected": true,
+    "UniversityDegreeCredential": "https://example.org/examples#UniversityDegreeCredential"
+  }
+}`
+	submissionContext = `{
+  "@context": {
+    "@version": 1.1,
+    "PresentationSubmission": "https://identity.foundation/presentation-exchange/#presentation-submission",
+    "presentation_submission": {
+      "@id": "https://identity.foundation/presentation-exchange/#presentation-submission",
+      "@type": "@json"
+    }
+  }
+}`
+	vcTemplate = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1", "https://example.org/context/v1"],
+  "id": "http://example.edu/credentials/1872",
+  "type": ["VerifiableCredential", "UniversityDegreeCredential"],
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "2010-01-01T19:23:24Z",
+  "credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"}%s
+}`
+	vpTemplate = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1",
+    "https://identity.foundation/presentation-exchange/submission/v1"],
+  "type": ["VerifiablePresentation", "PresentationSubmission"],
+  "presentation_submission": {"descriptor_map": [{"id": "%s", "path": "$.verifiableCredential[0]"}]},
+  "verifiableCredential": [%s]
+}`
+	vcProof = `,
+  "proof": {
+    "type": "Ed25519Signature2018",
+    "created": "2020-01-01T19:23:24Z",
+    "proofPurpose": "assertionMethod",
+    "verificationMethod": "did:example:76e12ec712ebc6f1c221ebfeb1f#key-1",
+    "jws": "eyJhbGciOiJFZERTQSIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..c2lnbmF0dXJl"
+  }`
+)
+
+const challenge = "23516943-1d79-4ebd-8981-623f036365ef"
+
+type verifierProvider struct {
+	storage storage.Provider
+	vdr     vdrapi.Registry
+	signer  signature.Signer
+}
+
+func (p *verifierProvider) StorageProvider() storage.Provider {
+	return p.storage
+}
+
+func (p *verifierProvider) VDRegistry() vdrapi.Registry {
+	return p.vdr
+}
+
+// newVerifierProvider returns the provider which resolves the DID of the holder (verificationMethod).
+func newVerifierProvider(t *testing.T) *verifierProvider {
+	t.Helper()
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	holderDID := strings.Split(verificationMethod, "#")[0]
+	vm := did.NewVerificationMethodFromBytes(verificationMethod, "Ed25519VerificationKey2018", holderDID, pubKey)
+
+	return &verifierProvider{
+		storage: mockstorage.NewMockStoreProvider(),
+		vdr: &mockvdr.MockVDRegistry{ResolveValue: &did.Doc{
+			ID:                 holderDID,
+			VerificationMethod: []did.VerificationMethod{*vm},
+			Authentication:     []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)},
+		}},
+		signer: signature.GetEd25519Signer(privKey, pubKey),
+	}
+}
+
+// sign signs the presentation with the key of the holder, the holder is set unless the presentation has one.
+func (p *verifierProvider) sign(t *testing.T, vp string, proofChallenge, proofDomain string) string {
+	t.Helper()
+
+	presentation, err := verifiable.ParseUnverifiedPresentation([]byte(vp))
+	require.NoError(t, err)
+
+	if presentation.Holder == "" {
+		presentation.Holder = strings.Split(verificationMethod, "#")[0]
+	}
+
+	require.NoError(t, presentation.AddLinkedDataProof(&verifiable.LinkedDataProofContext{
+		VerificationMethod:      verificationMethod,
+		SignatureRepresentation: verifiable.SignatureJWS,
+		SignatureType:           Ed25519Signature2018,
+		Suite:                   ed25519signature2018.New(suite.WithSigner(p.signer)),
+		Challenge:               proofChallenge,
+		Domain:                  proofDomain,
+		Purpose:                 authenticationProofPurpose,
+	}, jsonld.WithDocumentLoader(jsonldLoader(t))))
+
+	raw, err := json.Marshal(presentation)
+	require.NoError(t, err)
+
+	return string(raw)
+}
+
+// signJWT signs the JWT presentation of the holder with the given key ID, nonce and audience.
+func (p *verifierProvider) signJWT(t *testing.T, vp, holder, kid, nonce, aud string) string {
+	t.Helper()
+
+	vpClaim := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(vp), &vpClaim))
+
+	header, err := json.Marshal(map[string]interface{}{"alg": "EdDSA", "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(map[string]interface{}{"iss": holder, "nonce": nonce, "aud": aud, "vp": vpClaim})
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	sig, err := p.signer.Sign([]byte(signingInput))
+	require.NoError(t, err)
+
+	// the JWT presentation is attached as the JSON string
+	raw, err := json.Marshal(signingInput + "." + base64.RawURLEncoding.EncodeToString(sig))
+	require.NoError(t, err)
+
+	return string(raw)
+}
+
+func requestPresentation(definition string) *presentproof.RequestPresentation {
+	return &presentproof.RequestPresentation{
+		Type: presentproof.RequestPresentationMsgType,
+		Formats: []presentproof.Format{{
+			AttachID: "definition",
+			Format:   PresentationDefinitionFormat,
+		}},
+		RequestPresentationsAttach: []decorator.Attachment{{
+			ID:   "definition",
+			Data: decorator.AttachmentData{JSON: json.RawMessage(definition)},
+		}},
+	}
+}
+
+func presentationMsg(vp string) service.DIDCommMsg {
+	return service.NewDIDCommMsgMap(presentproof.Presentation{
+		Type: presentproof.PresentationMsgType,
+		Formats: []presentproof.Format{{
+			AttachID: "submission",
+			Format:   PresentationSubmissionFormat,
+		}},
+		PresentationsAttach: []decorator.Attachment{{
+			ID:   "submission",
+			Data: decorator.AttachmentData{JSON: json.RawMessage(vp)},
+		}},
+	})
+}
+
+func newVerifierMetadata(ctrl *gomock.Controller, stateName string, msg service.DIDCommMsg,
+	request *presentproof.RequestPresentation) *mocks.MockMetadata {
+	metadata := mocks.NewMockMetadata(ctrl)
+	metadata.EXPECT().StateName().Return(stateName).AnyTimes()
+	metadata.EXPECT().Message().Return(msg).AnyTimes()
+	metadata.EXPECT().RequestPresentation().Return(request).AnyTimes()
+	metadata.EXPECT().Properties().Return(map[string]interface{}{piidKey: piid}).AnyTimes()
+
+	return metadata
+}
+
+func TestVerifyPresentationDefinitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loader := jsonldLoader(t)
+	vc := strings.Replace(vcTemplate, "%s", "", 1)
+
+	verify := func(t *testing.T, p VerifierProvider, request *presentproof.RequestPresentation,
+		vp string) error {
+		t.Helper()
+
+		var called bool
+
+		next := presentproof.HandlerFunc(func(presentproof.Metadata) error {
+			called = true
+
+			return nil
+		})
+
+		mw, err := VerifyPresentationDefinitions(p, WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		require.NoError(t, mw(next).Handle(newVerifierMetadata(ctrl, stateNameRequestSent,
+			service.NewDIDCommMsgMap(request), nil)))
+		require.True(t, called)
+
+		called = false
+
+		err = mw(next).Handle(newVerifierMetadata(ctrl, stateNamePresentationReceived, presentationMsg(vp), nil))
+		if err == nil {
+			require.True(t, called)
+		}
+
+		return err
+	}
+
+	t.Run("Ignores other states", func(t *testing.T) {
+		next := presentproof.HandlerFunc(func(presentproof.Metadata) error { return nil })
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return("request-received")
+
+		mw, err := VerifyPresentationDefinitions(newVerifierProvider(t))
+		require.NoError(t, err)
+		require.NoError(t, mw(next).Handle(metadata))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		p := newVerifierProvider(t)
+
+		require.NoError(t, verify(t, p, requestPresentation(definitionReq),
+			p.sign(t, fmtVP("degree", vc), challenge, "example.com")))
+
+		store, err := p.storage.OpenStore(verifierStoreName)
+		require.NoError(t, err)
+
+		_, err = store.Get(piid)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("Success (request provided through the Continue function)", func(t *testing.T) {
+		next := presentproof.HandlerFunc(func(presentproof.Metadata) error { return nil })
+		p := newVerifierProvider(t)
+
+		mw, err := VerifyPresentationDefinitions(p, WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		require.NoError(t, mw(next).Handle(newVerifierMetadata(ctrl, stateNameRequestSent,
+			service.NewDIDCommMsgMap(presentproof.ProposePresentation{
+				Type: presentproof.ProposePresentationMsgType,
+			}), requestPresentation(definitionReq))))
+
+		require.NoError(t, mw(next).Handle(newVerifierMetadata(ctrl, stateNamePresentationReceived,
+			presentationMsg(p.sign(t, fmtVP("degree", vc), challenge, "example.com")), nil)))
+	})
+
+	t.Run("No presentation definition", func(t *testing.T) {
+		request := &presentproof.RequestPresentation{Type: presentproof.RequestPresentationMsgType}
+
+		require.NoError(t, verify(t, newVerifierProvider(t), request, fmtVP("unknown", vc)))
+	})
+
+	t.Run("Presentation does not satisfy the definition", func(t *testing.T) {
+		p := newVerifierProvider(t)
+
+		err := verify(t, p, requestPresentation(definitionReq), p.sign(t, fmtVP("unknown", vc), challenge, "example.com"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify presentation: match")
+		require.Contains(t, err.Error(), "did not match the `id` property of any input descriptor")
+	})
+
+	t.Run("Invalid credential proof", func(t *testing.T) {
+		signed := strings.Replace(vcTemplate, "%s", vcProof, 1)
+
+		p := newVerifierProvider(t)
+
+		err := verify(t, p, requestPresentation(definitionReq), p.sign(t, fmtVP("degree", signed), challenge, "example.com"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check embedded proof")
+	})
+
+	t.Run("Presentation is not signed", func(t *testing.T) {
+		err := verify(t, newVerifierProvider(t), requestPresentation(definitionReq), fmtVP("degree", vc))
+		require.EqualError(t, err, "verify presentation: the presentation is not signed")
+	})
+
+	t.Run("Proof does not match the request", func(t *testing.T) {
+		p := newVerifierProvider(t)
+
+		err := verify(t, p, requestPresentation(definitionReq), p.sign(t, fmtVP("degree", vc), "other", "example.com"))
+		require.EqualError(t, err, `verify presentation: the proof challenge "other" does not match the request`)
+
+		err = verify(t, p, requestPresentation(definitionReq), p.sign(t, fmtVP("degree", vc), challenge, ""))
+		require.EqualError(t, err, `verify presentation: the proof domain "" does not match the request`)
+	})
+
+	t.Run("Proof is not signed by the holder", func(t *testing.T) {
+		p := newVerifierProvider(t)
+		vp := strings.Replace(fmtVP("degree", vc), "{", `{"holder": "did:example:other",`, 1)
+
+		err := verify(t, p, requestPresentation(definitionReq), p.sign(t, vp, challenge, "example.com"))
+		require.EqualError(t, err, `verify presentation: the verification method "`+verificationMethod+
+			`" does not belong to the holder "did:example:other"`)
+	})
+
+	t.Run("Success (JWT presentation)", func(t *testing.T) {
+		p := newVerifierProvider(t)
+		holder := strings.Split(verificationMethod, "#")[0]
+
+		require.NoError(t, verify(t, p, requestPresentation(definitionReq),
+			p.signJWT(t, fmtVP("degree", vc), holder, verificationMethod, challenge, "example.com")))
+		require.NoError(t, verify(t, p, requestPresentation(definitionReq),
+			p.signJWT(t, fmtVP("degree", vc), holder, "key-1", challenge, "example.com")))
+	})
+
+	t.Run("JWT presentation does not match the request", func(t *testing.T) {
+		p := newVerifierProvider(t)
+		holder := strings.Split(verificationMethod, "#")[0]
+
+		err := verify(t, p, requestPresentation(definitionReq),
+			p.signJWT(t, fmtVP("degree", vc), holder, verificationMethod, "other", "example.com"))
+		require.EqualError(t, err, `verify presentation: the presentation nonce "other" does not match the request`)
+
+		err = verify(t, p, requestPresentation(definitionReq),
+			p.signJWT(t, fmtVP("degree", vc), holder, verificationMethod, challenge, "other.com"))
+		require.EqualError(t, err,
+			`verify presentation: the presentation audience does not contain the domain "example.com" of the request`)
+	})
+
+	t.Run("JWT presentation is not signed by the holder", func(t *testing.T) {
+		p := newVerifierProvider(t)
+
+		err := verify(t, p, requestPresentation(definitionReq),
+			p.signJWT(t, fmtVP("degree", vc), "did:example:other", verificationMethod, challenge, "example.com"))
+		require.EqualError(t, err, `verify presentation: the key "`+verificationMethod+
+			`" does not belong to the holder "did:example:other"`)
+	})
+
+	t.Run("Presentations not provided", func(t *testing.T) {
+		mw, err := VerifyPresentationDefinitions(newVerifierProvider(t))
+		require.NoError(t, err)
+
+		require.NoError(t, mw(presentproof.HandlerFunc(func(presentproof.Metadata) error { return nil })).Handle(
+			newVerifierMetadata(ctrl, stateNameRequestSent,
+				service.NewDIDCommMsgMap(requestPresentation(definitionReq)), nil)))
+
+		err = mw(nil).Handle(newVerifierMetadata(ctrl, stateNamePresentationReceived,
+			service.NewDIDCommMsgMap(presentproof.Presentation{Type: presentproof.PresentationMsgType}), nil))
+		require.EqualError(t, err, "verify presentation: presentations were not provided")
+	})
+
+	t.Run("Attachment not found", func(t *testing.T) {
+		request := requestPresentation(definitionReq)
+		request.Formats[0].AttachID = "unknown"
+
+		mw, err := VerifyPresentationDefinitions(newVerifierProvider(t))
+		require.NoError(t, err)
+
+		err = mw(nil).Handle(newVerifierMetadata(ctrl, stateNameRequestSent, service.NewDIDCommMsgMap(request), nil))
+		require.EqualError(t, err, "save presentation definition: attachment unknown not found")
+	})
+
+	t.Run("Presentation definition is absent", func(t *testing.T) {
+		err := verify(t, newVerifierProvider(t), requestPresentation(`{"options":{}}`), fmtVP("degree", vc))
+		require.EqualError(t, err, "presentation definition is absent")
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := newVerifierProvider(t)
+		p.storage = &mockstorage.MockStoreProvider{FailNamespace: verifierStoreName}
+
+		_, err := VerifyPresentationDefinitions(p)
+		require.Contains(t, err.Error(), "open store")
+	})
+}
+
+func fmtVP(descriptorID, vc string) string {
+	return strings.Replace(strings.Replace(vpTemplate, "%s", descriptorID, 1), "%s", vc, 1)
+}
+
+func jsonldLoader(t *testing.T) *ld.CachingDocumentLoader {
+	t.Helper()
+
+	loader := verifiable.CachingJSONLDLoader()
+
+	for url, context := range map[string]string{
+		contextURI: exampleContext,
+		presexch.PresentationSubmissionJSONLDContext: submissionContext,
+	} {
+		reader, err := ld.DocumentFromReader(strings.NewReader(context))
+		require.NoError(t, err)
+
+		loader.AddDocument(url, reader)
+	}
+
+	return loader
+}
+
+func Test_checkProofs(t *testing.T) {
+	err := checkProofs(&verifiable.Presentation{Proofs: []verifiable.Proof{{"challenge": challenge}}},
+		&PresentationDefinitionOptions{Challenge: challenge})
+	require.EqualError(t, err, "the presentation has no holder")
+}
@@ -6,9 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 
 package outofband
 
-import "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+import (
+	"encoding/json"
 
-// Request is this protocol's 'request' message.
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Request is the pre-release out-of-band 'request' message.
+// Deprecated: the out-of-band/1.0 invitation carries the requests in the `requests~attach` field.
 type Request struct {
 	ID       string                  `json:"@id"`
 	Type     string                  `json:"@type"`
@@ -21,11 +26,63 @@ type Request struct {
 
 // Invitation is this protocol's `invitation` message.
 type Invitation struct {
-	ID        string        `json:"@id"`
-	Type      string        `json:"@type"`
-	Label     string        `json:"label,omitempty"`
-	Goal      string        `json:"goal,omitempty"`
-	GoalCode  string        `json:"goal-code,omitempty"`
-	Service   []interface{} `json:"service"` // Service is an array of either DIDs or 'service' block entries.
-	Protocols []string      `json:"protocols"`
+	ID                 string                  `json:"@id"`
+	Type               string                  `json:"@type"`
+	Label              string                  `json:"label,omitempty"`
+	Goal               string                  `json:"goal,omitempty"`
+	GoalCode           string                  `json:"goal_code,omitempty"`
+	HandshakeProtocols []string                `json:"handshake_protocols,omitempty"`
+	Requests           []*decorator.Attachment `json:"requests~attach,omitempty"`
+	// Service is an array of either DIDs or 'service' block entries.
+	Service []interface{} `json:"service"`
+}
+
+// UnmarshalJSON reads the pre-release `oob-invitation/1.0` invitation as well,
+// its `protocols` become the handshake protocols.
+func (i *Invitation) UnmarshalJSON(data []byte) error {
+	type invitation Invitation
+
+	raw := struct {
+		*invitation
+		legacyInvitation
+	}{invitation: (*invitation)(i)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	i.fromLegacy(&raw.legacyInvitation)
+
+	return nil
+}
+
+// legacyInvitation has the fields of the pre-release invitation which were renamed in out-of-band/1.0.
+type legacyInvitation struct {
+	Protocols []string `json:"protocols,omitempty"`
+	GoalCode  string   `json:"goal-code,omitempty"`
+}
+
+func (i *Invitation) fromLegacy(legacy *legacyInvitation) {
+	if len(i.HandshakeProtocols) == 0 {
+		i.HandshakeProtocols = legacy.Protocols
+	}
+
+	if i.GoalCode == "" {
+		i.GoalCode = legacy.GoalCode
+	}
+}
+
+// HandshakeReuse is this protocol's `handshake-reuse` message.
+// It is sent over the existing connection instead of the new handshake.
+type HandshakeReuse struct {
+	ID     string            `json:"@id"`
+	Type   string            `json:"@type"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// HandshakeReuseAccepted is this protocol's `handshake-reuse-accepted` message.
+type HandshakeReuseAccepted struct {
+	ID     string            `json:"@id"`
+	Type   string            `json:"@type"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
 }
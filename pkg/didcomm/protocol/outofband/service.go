@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/mitchellh/mapstructure"
@@ -18,6 +19,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
@@ -27,31 +32,52 @@ import (
 const (
 	// Name of this protocol service.
 	Name = "out-of-band"
-	// RequestMsgType is the '@type' for the request message.
+	// PIURI is the out-of-band protocol's protocol instance URI.
+	PIURI = "https://didcomm.org/out-of-band/1.0"
+	// RequestMsgType is the '@type' for the pre-release request message.
 	RequestMsgType = "https://didcomm.org/oob-request/1.0/request"
 	// InvitationMsgType is the '@type' for the invitation message.
-	InvitationMsgType = "https://didcomm.org/oob-invitation/1.0/invitation"
+	InvitationMsgType = PIURI + "/invitation"
+	// LegacyInvitationMsgType is the '@type' for the pre-release invitation message.
+	// Deprecated: the invitation of this type is accepted as the out-of-band/1.0 invitation.
+	LegacyInvitationMsgType = "https://didcomm.org/oob-invitation/1.0/invitation"
+	// HandshakeReuseMsgType is the '@type' for the handshake-reuse message.
+	HandshakeReuseMsgType = PIURI + "/handshake-reuse"
+	// HandshakeReuseAcceptedMsgType is the '@type' for the handshake-reuse-accepted message.
+	HandshakeReuseAcceptedMsgType = PIURI + "/handshake-reuse-accepted"
 
 	// StateRequested is one of the possible states of this protocol.
 	StateRequested = "requested"
 	// StateInvited is this protocol's state after accepting an invitation.
 	StateInvited = "invited"
+	// StateReused is this protocol's state after reusing the existing connection.
+	StateReused = "reused"
 
 	// TODO channel size - https://github.com/hyperledger/aries-framework-go/issues/246
 	callbackChannelSize = 10
 
 	transitionalPayloadKey = "transitional_payload_%s"
+	// invitationKeyPrefix is the prefix of the keys of the requests and invitations created by this agent
+	// in the connection store, it keeps them apart from the invitations of the didexchange protocol.
+	invitationKeyPrefix = "oob_"
 )
 
 var logger = log.New(fmt.Sprintf("aries-framework/%s/service", Name))
 
-var errIgnoredDidEvent = errors.New("ignored")
+var (
+	errIgnoredDidEvent = errors.New("ignored")
+	errNoRequests      = errors.New("no requests found")
+)
 
 // Options is a container for optional values provided by the user.
 type Options interface {
 	// MyLabel is the label to share with the other agent in the subsequent did-exchange.
 	MyLabel() string
 	RouterConnections() []string
+	// ReuseConnection is the ID of the existing connection to reuse instead of the new handshake.
+	ReuseConnection() string
+	// ReuseAnyConnection allows reusing any existing connection with the DIDs of the invitation's services.
+	ReuseAnyConnection() bool
 }
 
 type didExchSvc interface {
@@ -69,6 +95,8 @@ type Service struct {
 	store                      storage.Store
 	connections                *connection.Recorder
 	outboundHandler            service.OutboundHandler
+	messenger                  service.Messenger
+	inboundHandler             transport.InboundMessageHandler
 	chooseRequestFunc          func(*myState) (*decorator.Attachment, bool)
 	extractDIDCommMsgBytesFunc func(*decorator.Attachment) ([]byte, error)
 	listenerFunc               func()
@@ -88,6 +116,8 @@ type myState struct {
 	Request      *Request
 	Invitation   *Invitation
 	Done         bool
+	// Reused is true if the existing connection was reused instead of the new handshake
+	Reused bool
 }
 
 // Action contains helpful information about action.
@@ -111,6 +141,8 @@ type Provider interface {
 	StorageProvider() storage.Provider
 	ProtocolStateStorageProvider() storage.Provider
 	OutboundMessageHandler() service.OutboundHandler
+	InboundMessageHandler() transport.InboundMessageHandler
+	Messenger() service.Messenger
 }
 
 // New creates a new instance of the out-of-band service.
@@ -142,6 +174,8 @@ func New(p Provider) (*Service, error) {
 		store:                      store,
 		connections:                connectionRecorder,
 		outboundHandler:            p.OutboundMessageHandler(),
+		messenger:                  p.Messenger(),
+		inboundHandler:             p.InboundMessageHandler(),
 		chooseRequestFunc:          chooseRequest,
 		extractDIDCommMsgBytesFunc: extractDIDCommMsgBytes,
 	}
@@ -167,9 +201,24 @@ func (s *Service) Name() string {
 	return Name
 }
 
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{
+		strings.TrimSuffix(RequestMsgType, "/request"),
+		strings.TrimSuffix(LegacyInvitationMsgType, "/invitation"),
+		PIURI,
+	}
+}
+
 // Accept determines whether this service can handle the given type of message.
 func (s *Service) Accept(msgType string) bool {
-	return msgType == RequestMsgType || msgType == InvitationMsgType
+	switch msgType {
+	case RequestMsgType, InvitationMsgType, LegacyInvitationMsgType,
+		HandshakeReuseMsgType, HandshakeReuseAcceptedMsgType:
+		return true
+	}
+
+	return false
 }
 
 // HandleInbound handles inbound messages.
@@ -180,6 +229,13 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 		return "", fmt.Errorf("unsupported message type %s", msg.Type())
 	}
 
+	switch msg.Type() {
+	case HandshakeReuseMsgType:
+		return "", s.handleHandshakeReuse(msg, myDID, theirDID)
+	case HandshakeReuseAcceptedMsgType:
+		return "", s.handleHandshakeReuseAccepted(msg, myDID, theirDID)
+	}
+
 	events := s.ActionEvent()
 	if events == nil {
 		return "", fmt.Errorf("no clients registered to handle action events for %s protocol", Name)
@@ -358,6 +414,8 @@ func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, err
 }
 
 // AcceptRequest from another agent and return the connection ID.
+// The connection ID is empty if the attached message has the ~service decorator,
+// such message is handled without a connection.
 func (s *Service) AcceptRequest(r *Request, myLabel string, routerConnections []string) (string, error) {
 	connID, err := s.handleCallback(&callback{
 		msg:     service.NewDIDCommMsgMap(r),
@@ -371,10 +429,17 @@ func (s *Service) AcceptRequest(r *Request, myLabel string, routerConnections []
 }
 
 // AcceptInvitation from another agent and return the connection ID.
-func (s *Service) AcceptInvitation(i *Invitation, myLabel string, routerConnections []string) (string, error) {
+// The existing connection is returned if it is reused according to the options.
+// The connection ID is empty if the invitation has no handshake protocols, the attached request
+// is handled without a connection in that case.
+func (s *Service) AcceptInvitation(i *Invitation, opts Options) (string, error) {
+	if opts == nil {
+		opts = &userOptions{}
+	}
+
 	connID, err := s.handleCallback(&callback{
 		msg:     service.NewDIDCommMsgMap(i),
-		options: &userOptions{myLabel: myLabel, routerConnections: routerConnections},
+		options: opts,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to accept invitation : %w", err)
@@ -386,7 +451,7 @@ func (s *Service) AcceptInvitation(i *Invitation, myLabel string, routerConnecti
 // SaveRequest created by the outofband client.
 func (s *Service) SaveRequest(r *Request) error {
 	// TODO where should we save this request? - https://github.com/hyperledger/aries-framework-go/issues/1547
-	err := s.connections.SaveInvitation(r.ID+"-TODO", r)
+	err := s.connections.SaveInvitation(invitationKey(r.ID), r)
 	if err != nil {
 		return fmt.Errorf("failed to save oob request : %w", err)
 	}
@@ -417,7 +482,7 @@ func (s *Service) SaveInvitation(i *Invitation) error {
 	}
 
 	// TODO where should we save this invitation? - https://github.com/hyperledger/aries-framework-go/issues/1547
-	err = s.connections.SaveInvitation(i.ID+"-TODO", i)
+	err = s.connections.SaveInvitation(invitationKey(i.ID), i)
 	if err != nil {
 		return fmt.Errorf("failed to save oob invitation : %w", err)
 	}
@@ -435,6 +500,31 @@ func (s *Service) SaveInvitation(i *Invitation) error {
 	return nil
 }
 
+// invitationKey returns the key of the request or invitation created by this agent in the connection store.
+func invitationKey(invitationID string) string {
+	return invitationKeyPrefix + invitationID
+}
+
+// SaveInvitationPolicy saves the policy (max uses, expiry) of the invitation created by the outofband client.
+func (s *Service) SaveInvitationPolicy(policy *connection.InvitationPolicy) error {
+	err := s.connections.SaveInvitationPolicy(policy)
+	if err != nil {
+		return fmt.Errorf("failed to save oob invitation policy : %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitationPolicy returns the policy and the number of uses of the invitation.
+func (s *Service) GetInvitationPolicy(invitationID string) (*connection.InvitationPolicy, error) {
+	return s.connections.GetInvitationPolicy(invitationID)
+}
+
+// RevokeInvitation revokes the invitation, the did-exchange requests for the invitation are not accepted anymore.
+func (s *Service) RevokeInvitation(invitationID string) error {
+	return s.connections.RevokeInvitation(invitationID)
+}
+
 func listener(
 	callbacks chan *callback,
 	didEvents chan service.StateMsg,
@@ -446,7 +536,7 @@ func listener(
 			select {
 			case c := <-callbacks:
 				switch c.msg.Type() {
-				case RequestMsgType, InvitationMsgType:
+				case RequestMsgType, InvitationMsgType, LegacyInvitationMsgType:
 					connID, err := handleCallbackFunc(c)
 					if err != nil {
 						logutil.LogError(logger, Name, "handleCallback", err.Error(),
@@ -478,7 +568,7 @@ func (s *Service) handleCallback(c *callback) (string, error) {
 	switch c.msg.Type() {
 	case RequestMsgType:
 		return s.handleRequestCallback(c)
-	case InvitationMsgType:
+	case InvitationMsgType, LegacyInvitationMsgType:
 		return s.handleInvitationCallback(c)
 	default:
 		return "", fmt.Errorf("unsupported message type: %s", c.msg.Type())
@@ -501,6 +591,11 @@ func (s *Service) handleRequestCallback(c *callback) (string, error) {
 		Request: req,
 	}
 
+	// the attached message with the ~service decorator is handled without a connection
+	if msg, err := s.extractDIDCommMsg(state); err == nil && hasServiceDecorator(msg) {
+		return "", s.handleConnectionless(state, msg)
+	}
+
 	err = s.save(state)
 
 	if err != nil {
@@ -525,6 +620,66 @@ func (s *Service) handleRequestCallback(c *callback) (string, error) {
 	return connID, nil
 }
 
+// handleConnectionless dispatches the attached message to the protocol service as an inbound message
+// without a connection, the protocol service replies according to the ~service decorator of the message.
+// Only the messages which start the protocols supporting connectionless exchanges are dispatched.
+func (s *Service) handleConnectionless(state *myState, msg service.DIDCommMsg) error {
+	if !connectionlessMsgType(msg.Type()) {
+		return fmt.Errorf("the attached message type %s is not supported without a connection", msg.Type())
+	}
+
+	// the message starts a new thread, a reply has no pending request of this agent to match
+	if thID, err := msg.ThreadID(); err != nil || thID != msg.ID() {
+		return fmt.Errorf("the attached message %s has no matching pending request", msg.ID())
+	}
+
+	// the same request or invitation is not handled twice
+	if previous, err := s.fetchMyState(state.ID); err == nil && previous.Done {
+		return fmt.Errorf("the attached message of %s was already handled", state.ID)
+	}
+
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the attached message : %w", err)
+	}
+
+	// the out-of-band protocol has done its job, the other protocol maintains its own state.
+	state.Done = true
+
+	if err = s.save(state); err != nil {
+		return fmt.Errorf("failed to save new state : %w", err)
+	}
+
+	if err = s.inboundHandler(raw, "", ""); err != nil {
+		return fmt.Errorf("failed to dispatch the connectionless message : %w", err)
+	}
+
+	return nil
+}
+
+// connectionlessMsgType reports whether the message of the type starts the protocol which can run
+// without a connection.
+func connectionlessMsgType(msgType string) bool {
+	switch msgType {
+	case presentproof.RequestPresentationMsgType, issuecredential.OfferCredentialMsgType,
+		issuecredential.ProposeCredentialMsgType, trustping.PingMsgType:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasServiceDecorator(msg service.DIDCommMsg) bool {
+	m, ok := msg.(service.DIDCommMsgMap)
+	if !ok {
+		return false
+	}
+
+	_, ok = m["~service"]
+
+	return ok
+}
+
 func (s *Service) handleInvitationCallback(c *callback) (string, error) {
 	logger.Debugf("input: %+v", c)
 
@@ -533,16 +688,35 @@ func (s *Service) handleInvitationCallback(c *callback) (string, error) {
 		return "", fmt.Errorf("handleInvitationCallback: failed to decode callback message : %w", err)
 	}
 
+	state := &myState{
+		// the pthid of the didexchange thread will equal this invitation's ID as per the RFC
+		ID:         oobInv.ID,
+		Invitation: oobInv,
+	}
+
+	record, err := s.findReusableConnection(oobInv, c.options)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the connection to reuse : %w", err)
+	}
+
+	if record != nil {
+		return record.ConnectionID, s.reuseConnection(state, record)
+	}
+
+	if len(oobInv.HandshakeProtocols) == 0 {
+		return "", s.handleConnectionlessInvitation(state)
+	}
+
+	if !supportsHandshake(oobInv.HandshakeProtocols) {
+		return "", fmt.Errorf("none of the handshake protocols is supported : %v", oobInv.HandshakeProtocols)
+	}
+
 	connID, err := s.didSvc.RespondTo(didInv, c.options.RouterConnections())
 	if err != nil {
 		return "", fmt.Errorf("didexchange service failed to handle inbound invitation : %w", err)
 	}
 
-	state := &myState{
-		ID:           didInv.ID,
-		ConnectionID: connID,
-		Invitation:   oobInv,
-	}
+	state.ConnectionID = connID
 
 	err = s.save(state)
 	if err != nil {
@@ -552,6 +726,138 @@ func (s *Service) handleInvitationCallback(c *callback) (string, error) {
 	return connID, nil
 }
 
+// handleConnectionlessInvitation handles the attached request of the invitation without handshake protocols.
+// The request gets the ~service decorator from the invitation's inline service block unless it has one already.
+func (s *Service) handleConnectionlessInvitation(state *myState) error {
+	msg, err := s.extractDIDCommMsg(state)
+	if err != nil {
+		return fmt.Errorf("failed to extract the request of the invitation without handshake protocols : %w", err)
+	}
+
+	if !hasServiceDecorator(msg) {
+		svc, err := serviceDecorator(state.Invitation.Service)
+		if err != nil {
+			return fmt.Errorf("invitation without handshake protocols : %w", err)
+		}
+
+		msg.(service.DIDCommMsgMap)["~service"] = svc
+	}
+
+	return s.handleConnectionless(state, msg)
+}
+
+// findReusableConnection returns the completed connection to reuse according to the options.
+// It returns nil if no connection should be reused.
+func (s *Service) findReusableConnection(inv *Invitation, opts Options) (*connection.Record, error) {
+	if opts.ReuseConnection() != "" {
+		record, err := s.connections.GetConnectionRecord(opts.ReuseConnection())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get connection record %s : %w", opts.ReuseConnection(), err)
+		}
+
+		if record.State != didexchange.StateIDCompleted {
+			return nil, fmt.Errorf("connection %s is not completed", record.ConnectionID)
+		}
+
+		return record, nil
+	}
+
+	if !opts.ReuseAnyConnection() {
+		return nil, nil
+	}
+
+	records, err := s.connections.QueryConnectionRecords()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection records : %w", err)
+	}
+
+	for i := range inv.Service {
+		theirDID, ok := inv.Service[i].(string)
+		if !ok {
+			continue
+		}
+
+		for _, record := range records {
+			if record.TheirDID == theirDID && record.State == didexchange.StateIDCompleted {
+				return record, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// reuseConnection sends the handshake-reuse message over the existing connection.
+// The attached request is dispatched once the other agent accepts the reuse.
+func (s *Service) reuseConnection(state *myState, record *connection.Record) error {
+	state.ConnectionID = record.ConnectionID
+	state.Reused = true
+
+	if err := s.save(state); err != nil {
+		return fmt.Errorf("failed to save my state : %w", err)
+	}
+
+	msg := service.NewDIDCommMsgMap(&HandshakeReuse{
+		ID:   uuid.New().String(),
+		Type: HandshakeReuseMsgType,
+	})
+
+	err := s.messenger.ReplyToNested(msg, &service.NestedReplyOpts{
+		ThreadID: state.ID,
+		MyDID:    record.MyDID,
+		TheirDID: record.TheirDID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send the handshake-reuse message : %w", err)
+	}
+
+	return nil
+}
+
+// handleHandshakeReuse accepts the reuse of the connection with the sender for the invitation created by this agent.
+func (s *Service) handleHandshakeReuse(msg service.DIDCommMsg, myDID, theirDID string) error {
+	invitationID := msg.ParentThreadID()
+	if invitationID == "" {
+		return errors.New("handshake-reuse: the parent thread ID is empty")
+	}
+
+	if err := s.connections.GetInvitation(invitationKey(invitationID), &Invitation{}); err != nil {
+		return fmt.Errorf("handshake-reuse: failed to get the invitation %s : %w", invitationID, err)
+	}
+
+	if _, err := s.connections.GetConnectionIDByDIDs(myDID, theirDID); err != nil {
+		return fmt.Errorf("handshake-reuse: failed to get the connection with the sender : %w", err)
+	}
+
+	err := s.messenger.ReplyTo(msg.ID(), service.NewDIDCommMsgMap(&HandshakeReuseAccepted{
+		ID:   uuid.New().String(),
+		Type: HandshakeReuseAcceptedMsgType,
+	}))
+	if err != nil {
+		return fmt.Errorf("handshake-reuse: failed to reply : %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) handleHandshakeReuseAccepted(msg service.DIDCommMsg, myDID, theirDID string) error {
+	state, err := s.fetchMyState(msg.ParentThreadID())
+	if err != nil {
+		return fmt.Errorf("handshake-reuse-accepted: failed to load state : %w", err)
+	}
+
+	if !state.Reused {
+		return fmt.Errorf("handshake-reuse-accepted: the connection was not reused for %s", state.ID)
+	}
+
+	err = s.dispatchRequest(state, myDID, theirDID)
+	if err != nil && !errors.Is(err, errNoRequests) {
+		return fmt.Errorf("handshake-reuse-accepted: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) handleDIDEvent(e service.StateMsg) error {
 	logger.Debugf("input: %+v", e)
 
@@ -580,9 +886,20 @@ func (s *Service) handleDIDEvent(e service.StateMsg) error {
 		return fmt.Errorf("service.handleDIDEvent: failed to load state : %w", err)
 	}
 
+	// the invitation without requests has done its job once the connection is completed
+	err = s.dispatchRequest(state, record.MyDID, record.TheirDID)
+	if err != nil && !errors.Is(err, errNoRequests) {
+		return fmt.Errorf("service.handleDIDEvent: %w", err)
+	}
+
+	return nil
+}
+
+// dispatchRequest dispatches the attached request over the connection.
+func (s *Service) dispatchRequest(state *myState, myDID, theirDID string) error {
 	msg, err := s.extractDIDCommMsg(state)
 	if err != nil {
-		return fmt.Errorf("service.handleDIDEvent: failed to extract DIDComm msg : %w", err)
+		return fmt.Errorf("failed to extract DIDComm msg : %w", err)
 	}
 
 	state.Done = true
@@ -591,12 +908,12 @@ func (s *Service) handleDIDEvent(e service.StateMsg) error {
 	// has done its job in getting this far. The other protocol maintains its own state.
 	err = s.save(state)
 	if err != nil {
-		return fmt.Errorf("service.handleDIDEvent: failed to update state : %w", err)
+		return fmt.Errorf("failed to update state : %w", err)
 	}
 
-	_, err = s.outboundHandler.HandleOutbound(msg, record.MyDID, record.TheirDID)
+	_, err = s.outboundHandler.HandleOutbound(msg, myDID, theirDID)
 	if err != nil {
-		return fmt.Errorf("service.handleDIDEvent: failed to dispatch message : %w", err)
+		return fmt.Errorf("failed to dispatch message : %w", err)
 	}
 
 	return nil
@@ -637,11 +954,24 @@ func (s *Service) fetchMyState(id string) (*myState, error) {
 //  - https://github.com/hyperledger/aries-rfcs/issues/451
 //  This logic should be injected into the service.
 func chooseRequest(state *myState) (*decorator.Attachment, bool) {
-	if !state.Done {
-		return state.Request.Requests[0], true
+	if state.Done {
+		return nil, false
+	}
+
+	var requests []*decorator.Attachment
+
+	switch {
+	case state.Request != nil:
+		requests = state.Request.Requests
+	case state.Invitation != nil:
+		requests = state.Invitation.Requests
 	}
 
-	return nil, false
+	if len(requests) == 0 {
+		return nil, false
+	}
+
+	return requests[0], true
 }
 
 func extractDIDCommMsgBytes(a *decorator.Attachment) ([]byte, error) {
@@ -656,7 +986,7 @@ func extractDIDCommMsgBytes(a *decorator.Attachment) ([]byte, error) {
 func (s *Service) extractDIDCommMsg(state *myState) (service.DIDCommMsg, error) {
 	req, found := s.chooseRequestFunc(state)
 	if !found {
-		return nil, fmt.Errorf("no requests found to extract for msgId=%s", state.Request.ID)
+		return nil, fmt.Errorf("%w to extract for msgId=%s", errNoRequests, state.ID)
 	}
 
 	bytes, err := s.extractDIDCommMsgBytesFunc(req)
@@ -699,9 +1029,7 @@ func decodeInvitationAndRequest(c *callback) (*didexchange.OOBInvitation, *Reque
 }
 
 func decodeDIDInvitationAndOOBInvitation(c *callback) (*didexchange.OOBInvitation, *Invitation, error) {
-	oobInv := &Invitation{}
-
-	err := c.msg.Decode(oobInv)
+	oobInv, err := decodeInvitation(c.msg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to decode out-of-band invitation mesesage : %w", err)
 	}
@@ -747,6 +1075,35 @@ func chooseTarget(svcs []interface{}) (interface{}, error) {
 	return nil, fmt.Errorf("invalid or no targets to choose from")
 }
 
+// serviceDecorator returns the ~service decorator built from the first inline service block.
+func serviceDecorator(svcs []interface{}) (*decorator.Service, error) {
+	target, err := chooseTarget(svcs)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, ok := target.(*did.Service)
+	if !ok {
+		return nil, errors.New("the inline service block was expected")
+	}
+
+	return &decorator.Service{
+		RecipientKeys:   svc.RecipientKeys,
+		RoutingKeys:     svc.RoutingKeys,
+		ServiceEndpoint: svc.ServiceEndpoint,
+	}, nil
+}
+
+func supportsHandshake(protocols []string) bool {
+	for _, protocol := range protocols {
+		if protocol == didexchange.PIURI {
+			return true
+		}
+	}
+
+	return false
+}
+
 type eventProps struct {
 	ConnID string `json:"conn_id"`
 	Err    error  `json:"err"`
@@ -761,8 +1118,10 @@ func (e *eventProps) Error() error {
 }
 
 type userOptions struct {
-	myLabel           string
-	routerConnections []string
+	myLabel            string
+	routerConnections  []string
+	reuseConnection    string
+	reuseAnyConnection bool
 }
 
 func (e *userOptions) MyLabel() string {
@@ -773,6 +1132,14 @@ func (e *userOptions) RouterConnections() []string {
 	return e.routerConnections
 }
 
+func (e *userOptions) ReuseConnection() string {
+	return e.reuseConnection
+}
+
+func (e *userOptions) ReuseAnyConnection() bool {
+	return e.reuseAnyConnection
+}
+
 // All implements EventProperties interface.
 func (e *eventProps) All() map[string]interface{} {
 	return map[string]interface{}{
@@ -780,3 +1147,24 @@ func (e *eventProps) All() map[string]interface{} {
 		"error":        e.Error(),
 	}
 }
+
+// decodeInvitation decodes the invitation, the fields of the pre-release invitation are read as well.
+func decodeInvitation(msg service.DIDCommMsg) (*Invitation, error) {
+	inv := &Invitation{}
+
+	if err := msg.Decode(inv); err != nil {
+		return nil, err
+	}
+
+	if msg.Type() == LegacyInvitationMsgType {
+		legacy := &legacyInvitation{}
+
+		if err := msg.Decode(legacy); err != nil {
+			return nil, err
+		}
+
+		inv.fromLegacy(legacy)
+	}
+
+	return inv, nil
+}
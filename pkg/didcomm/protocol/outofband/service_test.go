@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
@@ -20,8 +21,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
@@ -98,6 +101,16 @@ func TestName(t *testing.T) {
 	require.Equal(t, s.Name(), "out-of-band")
 }
 
+func TestProtocols(t *testing.T) {
+	s, err := New(testProvider())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"https://didcomm.org/oob-request/1.0",
+		"https://didcomm.org/oob-invitation/1.0",
+		"https://didcomm.org/out-of-band/1.0",
+	}, s.Protocols())
+}
+
 func TestAccept(t *testing.T) {
 	t.Run("accepts out-of-band request messages", func(t *testing.T) {
 		s, err := New(testProvider())
@@ -107,8 +120,15 @@ func TestAccept(t *testing.T) {
 	t.Run("accepts out-of-band invitation messages", func(t *testing.T) {
 		s, err := New(testProvider())
 		require.NoError(t, err)
+		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/invitation"))
 		require.True(t, s.Accept("https://didcomm.org/oob-invitation/1.0/invitation"))
 	})
+	t.Run("accepts out-of-band handshake-reuse messages", func(t *testing.T) {
+		s, err := New(testProvider())
+		require.NoError(t, err)
+		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/handshake-reuse"))
+		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/handshake-reuse-accepted"))
+	})
 	t.Run("rejects unsupported messages", func(t *testing.T) {
 		s, err := New(testProvider())
 		require.NoError(t, err)
@@ -482,18 +502,15 @@ func TestHandleDIDEvent(t *testing.T) {
 		expected := errors.New("test")
 		const connID = "123"
 		provider := testProvider()
-		provider.ProtocolStateStoreProvider = &mockstore.MockStoreProvider{
-			Store: &mockstore.MockStore{
-				Store:  make(map[string][]byte),
-				ErrGet: expected,
-			},
-		}
+		protocolStateStore := &mockstore.MockStore{Store: make(map[string][]byte)}
+		provider.ProtocolStateStoreProvider = &mockstore.MockStoreProvider{Store: protocolStateStore}
 		r, err := connection.NewRecorder(provider)
 		require.NoError(t, err)
 		err = r.SaveConnectionRecord(&connection.Record{
 			ConnectionID: connID,
 		})
 		require.NoError(t, err)
+		protocolStateStore.ErrGet = expected
 		s := newAutoService(t, provider)
 		err = s.handleDIDEvent(service.StateMsg{
 			ProtocolName: didexchange.DIDExchange,
@@ -902,6 +919,88 @@ func TestAcceptRequest(t *testing.T) {
 		require.Error(t, err)
 		require.True(t, errors.Is(err, expected))
 	})
+	t.Run("handles the attached message with ~service decorator connectionless", func(t *testing.T) {
+		req := newRequest()
+		req.Requests[0].Data.JSON = map[string]interface{}{
+			"@id":      "123",
+			"@type":    presentproof.RequestPresentationMsgType,
+			"~service": map[string]interface{}{"recipientKeys": []string{"key"}, "serviceEndpoint": "http://example.com"},
+		}
+
+		provider := testProvider()
+		provider.ServiceMap = map[string]interface{}{
+			didexchange.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(_ *didexchange.OOBInvitation, _ []string) (string, error) {
+					return "", errors.New("unexpected call")
+				},
+			},
+		}
+
+		var handled service.DIDCommMsgMap
+
+		provider.InboundMsgHandler = func(message []byte, myDID, theirDID string) error {
+			require.Empty(t, myDID)
+			require.Empty(t, theirDID)
+
+			var err error
+			handled, err = service.ParseDIDCommMsgMap(message)
+
+			return err
+		}
+
+		s := newAutoService(t, provider)
+		connID, err := s.AcceptRequest(req, "", nil)
+		require.NoError(t, err)
+		require.Empty(t, connID)
+		require.Equal(t, "123", handled.ID())
+
+		state, err := s.fetchMyState(req.ID)
+		require.NoError(t, err)
+		require.True(t, state.Done)
+
+		// the request is handled once
+		_, err = s.AcceptRequest(req, "", nil)
+		require.EqualError(t, err, fmt.Sprintf("failed to accept request : the attached message of %s "+
+			"was already handled", req.ID))
+
+		expected := errors.New("test")
+		provider.InboundMsgHandler = func([]byte, string, string) error { return expected }
+
+		req.ID = uuid.New().String()
+		s = newAutoService(t, provider)
+		_, err = s.AcceptRequest(req, "", nil)
+		require.True(t, errors.Is(err, expected))
+	})
+	t.Run("rejects the connectionless message the request cannot start", func(t *testing.T) {
+		provider := testProvider()
+		provider.InboundMsgHandler = func([]byte, string, string) error {
+			return errors.New("unexpected call")
+		}
+
+		s := newAutoService(t, provider)
+
+		req := newRequest()
+		req.Requests[0].Data.JSON = map[string]interface{}{
+			"@id":      "123",
+			"@type":    presentproof.PresentationMsgType,
+			"~service": map[string]interface{}{"recipientKeys": []string{"key"}, "serviceEndpoint": "http://example.com"},
+		}
+
+		_, err := s.AcceptRequest(req, "", nil)
+		require.EqualError(t, err, "failed to accept request : the attached message type "+
+			presentproof.PresentationMsgType+" is not supported without a connection")
+
+		req = newRequest()
+		req.Requests[0].Data.JSON = map[string]interface{}{
+			"@id":      "123",
+			"@type":    presentproof.RequestPresentationMsgType,
+			"~thread":  map[string]interface{}{"thid": "456"},
+			"~service": map[string]interface{}{"recipientKeys": []string{"key"}, "serviceEndpoint": "http://example.com"},
+		}
+
+		_, err = s.AcceptRequest(req, "", nil)
+		require.EqualError(t, err, "failed to accept request : the attached message 123 has no matching pending request")
+	})
 }
 
 func TestAcceptInvitation(t *testing.T) {
@@ -916,7 +1015,7 @@ func TestAcceptInvitation(t *testing.T) {
 			},
 		}
 		s := newAutoService(t, provider)
-		result, err := s.AcceptInvitation(newInvitation(), "", nil)
+		result, err := s.AcceptInvitation(newInvitation(), &userOptions{})
 		require.NoError(t, err)
 		require.Equal(t, expected, result)
 	})
@@ -931,10 +1030,287 @@ func TestAcceptInvitation(t *testing.T) {
 			},
 		}
 		s := newAutoService(t, provider)
-		_, err := s.AcceptInvitation(newInvitation(), "", nil)
+		_, err := s.AcceptInvitation(newInvitation(), &userOptions{})
 		require.Error(t, err)
 		require.True(t, errors.Is(err, expected))
 	})
+	t.Run("accepts the pre-release invitation", func(t *testing.T) {
+		raw := []byte(`{
+			"@id": "legacy-invitation",
+			"@type": "https://didcomm.org/oob-invitation/1.0/invitation",
+			"label": "test",
+			"goal-code": "test",
+			"service": ["did:example:1235"],
+			"protocols": ["https://didcomm.org/didexchange/1.0"]
+		}`)
+
+		inv := &Invitation{}
+		require.NoError(t, json.Unmarshal(raw, inv))
+		require.Equal(t, []string{didexchange.PIURI}, inv.HandshakeProtocols)
+		require.Equal(t, "test", inv.GoalCode)
+
+		msg, err := service.ParseDIDCommMsgMap(raw)
+		require.NoError(t, err)
+
+		provider := testProvider()
+		provider.ServiceMap = map[string]interface{}{
+			didexchange.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(i *didexchange.OOBInvitation, _ []string) (string, error) {
+					require.Equal(t, "legacy-invitation", i.ThreadID)
+
+					return "123456", nil
+				},
+			},
+		}
+
+		s := newAutoService(t, provider)
+		connID, err := s.handleCallback(&callback{msg: msg, options: &userOptions{}})
+		require.NoError(t, err)
+		require.Equal(t, "123456", connID)
+	})
+	t.Run("fails if none of the handshake protocols is supported", func(t *testing.T) {
+		inv := newInvitation()
+		inv.HandshakeProtocols = []string{"https://didcomm.org/unsupported/1.0"}
+
+		s := newAutoService(t, testProvider())
+		_, err := s.AcceptInvitation(inv, nil)
+		require.EqualError(t, err, "failed to accept invitation : none of the handshake protocols is supported : "+
+			"[https://didcomm.org/unsupported/1.0]")
+	})
+	t.Run("handles the request of the invitation without handshake protocols connectionless", func(t *testing.T) {
+		inv := newInvitation()
+		inv.HandshakeProtocols = nil
+		inv.Requests = newRequest().Requests
+		inv.Service = []interface{}{&did.Service{
+			RecipientKeys:   []string{"key"},
+			ServiceEndpoint: "http://example.com",
+		}}
+
+		var handled service.DIDCommMsgMap
+
+		provider := testProvider()
+		provider.InboundMsgHandler = func(message []byte, myDID, theirDID string) error {
+			require.Empty(t, myDID)
+			require.Empty(t, theirDID)
+
+			var err error
+			handled, err = service.ParseDIDCommMsgMap(message)
+
+			return err
+		}
+
+		s := newAutoService(t, provider)
+		connID, err := s.AcceptInvitation(inv, nil)
+		require.NoError(t, err)
+		require.Empty(t, connID)
+		require.Equal(t, "123", handled.ID())
+
+		svc := &decorator.Service{}
+		require.NoError(t, handled.Decode(&struct {
+			Service *decorator.Service `json:"~service"`
+		}{Service: svc}))
+		require.Equal(t, []string{"key"}, svc.RecipientKeys)
+		require.Equal(t, "http://example.com", svc.ServiceEndpoint)
+
+		state, err := s.fetchMyState(inv.ID)
+		require.NoError(t, err)
+		require.True(t, state.Done)
+	})
+	t.Run("fails to handle the invitation without handshake protocols and inline service", func(t *testing.T) {
+		inv := newInvitation()
+		inv.HandshakeProtocols = nil
+		inv.Requests = newRequest().Requests
+
+		s := newAutoService(t, testProvider())
+		_, err := s.AcceptInvitation(inv, nil)
+		require.EqualError(t, err, "failed to accept invitation : invitation without handshake protocols : "+
+			"the inline service block was expected")
+
+		inv.Requests = nil
+
+		_, err = s.AcceptInvitation(inv, nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errNoRequests))
+	})
+}
+
+func TestHandshakeReuse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const connID = "conn-id"
+
+	newProvider := func(t *testing.T, state string) *protocol.MockProvider {
+		t.Helper()
+
+		provider := testProvider()
+		provider.ServiceMap = map[string]interface{}{
+			didexchange.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(_ *didexchange.OOBInvitation, _ []string) (string, error) {
+					return "", errors.New("unexpected call")
+				},
+			},
+		}
+
+		r, err := connection.NewRecorder(provider)
+		require.NoError(t, err)
+		require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+			ConnectionID: connID,
+			State:        state,
+			MyDID:        myDID,
+			TheirDID:     theirDID,
+		}))
+
+		return provider
+	}
+
+	newInv := func() *Invitation {
+		inv := newInvitation()
+		inv.Service = []interface{}{theirDID}
+		inv.Requests = newRequest().Requests
+
+		return inv
+	}
+
+	t.Run("reuses the connection with the DID of the invitation", func(t *testing.T) {
+		inv := newInv()
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				require.Equal(t, HandshakeReuseMsgType, msg.Type())
+				require.Equal(t, inv.ID, opts.ThreadID)
+				require.Equal(t, myDID, opts.MyDID)
+				require.Equal(t, theirDID, opts.TheirDID)
+
+				return nil
+			})
+
+		dispatched := make(chan service.DIDCommMsg, 1)
+
+		provider := newProvider(t, didexchange.StateIDCompleted)
+		provider.OutboundMsgHandler = &outboundMsgHandlerStub{
+			handleFunc: func(msg service.DIDCommMsg, my, their string) (string, error) {
+				require.Equal(t, myDID, my)
+				require.Equal(t, theirDID, their)
+				dispatched <- msg
+
+				return "", nil
+			},
+		}
+
+		s := newAutoService(t, provider)
+		s.messenger = messenger
+
+		result, err := s.AcceptInvitation(inv, &userOptions{reuseAnyConnection: true})
+		require.NoError(t, err)
+		require.Equal(t, connID, result)
+
+		accepted := service.NewDIDCommMsgMap(&HandshakeReuseAccepted{
+			ID:     uuid.New().String(),
+			Type:   HandshakeReuseAcceptedMsgType,
+			Thread: &decorator.Thread{ID: uuid.New().String(), PID: inv.ID},
+		})
+
+		_, err = s.HandleInbound(accepted, myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, "123", (<-dispatched).ID())
+
+		state, err := s.fetchMyState(inv.ID)
+		require.NoError(t, err)
+		require.True(t, state.Done)
+		require.True(t, state.Reused)
+	})
+	t.Run("reuses the connection by ID", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).Return(nil)
+
+		s := newAutoService(t, newProvider(t, didexchange.StateIDCompleted))
+		s.messenger = messenger
+
+		inv := newInv()
+		inv.Service = []interface{}{"did:example:other"}
+
+		result, err := s.AcceptInvitation(inv, &userOptions{reuseConnection: connID})
+		require.NoError(t, err)
+		require.Equal(t, connID, result)
+	})
+	t.Run("fails to reuse the connection which is not completed", func(t *testing.T) {
+		s := newAutoService(t, newProvider(t, didexchange.StateIDRequested))
+
+		_, err := s.AcceptInvitation(newInv(), &userOptions{reuseConnection: connID})
+		require.EqualError(t, err, "failed to accept invitation : failed to find the connection to reuse : "+
+			"connection conn-id is not completed")
+	})
+	t.Run("fails to send the handshake-reuse message", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).Return(errors.New("test"))
+
+		s := newAutoService(t, newProvider(t, didexchange.StateIDCompleted))
+		s.messenger = messenger
+
+		_, err := s.AcceptInvitation(newInv(), &userOptions{reuseAnyConnection: true})
+		require.EqualError(t, err, "failed to accept invitation : failed to send the handshake-reuse message : test")
+	})
+	t.Run("replies to the handshake-reuse message", func(t *testing.T) {
+		inv := newInv()
+		msg := service.NewDIDCommMsgMap(&HandshakeReuse{
+			ID:     uuid.New().String(),
+			Type:   HandshakeReuseMsgType,
+			Thread: &decorator.Thread{PID: inv.ID},
+		})
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(msg.ID(), gomock.Any()).
+			Do(func(_ string, reply service.DIDCommMsgMap) error {
+				require.Equal(t, HandshakeReuseAcceptedMsgType, reply.Type())
+
+				return nil
+			})
+
+		s := newAutoService(t, newProvider(t, didexchange.StateIDCompleted))
+		s.messenger = messenger
+		require.NoError(t, s.connections.SaveInvitation(invitationKey(inv.ID), inv))
+
+		_, err := s.HandleInbound(msg, myDID, theirDID)
+		require.NoError(t, err)
+	})
+	t.Run("rejects the handshake-reuse message", func(t *testing.T) {
+		inv := newInv()
+		msg := service.NewDIDCommMsgMap(&HandshakeReuse{
+			ID:     uuid.New().String(),
+			Type:   HandshakeReuseMsgType,
+			Thread: &decorator.Thread{PID: uuid.New().String()},
+		})
+
+		s := newAutoService(t, newProvider(t, didexchange.StateIDCompleted))
+		require.NoError(t, s.connections.SaveInvitation(invitationKey(inv.ID), inv))
+
+		_, err := s.HandleInbound(msg, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handshake-reuse: failed to get the invitation")
+
+		msg["~thread"] = map[string]interface{}{"pthid": inv.ID}
+
+		_, err = s.HandleInbound(msg, myDID, "did:example:other")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handshake-reuse: failed to get the connection with the sender")
+
+		delete(msg, "~thread")
+
+		_, err = s.HandleInbound(msg, myDID, theirDID)
+		require.EqualError(t, err, "handshake-reuse: the parent thread ID is empty")
+	})
+	t.Run("fails to handle the handshake-reuse-accepted message of the unknown invitation", func(t *testing.T) {
+		s := newAutoService(t, testProvider())
+
+		_, err := s.HandleInbound(service.NewDIDCommMsgMap(&HandshakeReuseAccepted{
+			ID:     uuid.New().String(),
+			Type:   HandshakeReuseAcceptedMsgType,
+			Thread: &decorator.Thread{PID: uuid.New().String()},
+		}), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handshake-reuse-accepted: failed to load state")
+	})
 }
 
 func TestSaveRequest(t *testing.T) {
@@ -1067,6 +1443,38 @@ func TestSaveInvitation(t *testing.T) {
 	})
 }
 
+func TestInvitationPolicy(t *testing.T) {
+	t.Run("saves, returns and revokes the invitation policy", func(t *testing.T) {
+		s := newAutoService(t, testProvider())
+
+		err := s.SaveInvitationPolicy(&connection.InvitationPolicy{InvitationID: "inv-1", MaxUses: 3})
+		require.NoError(t, err)
+
+		policy, err := s.GetInvitationPolicy("inv-1")
+		require.NoError(t, err)
+		require.Equal(t, 3, policy.MaxUses)
+
+		require.NoError(t, s.RevokeInvitation("inv-1"))
+
+		policy, err = s.GetInvitationPolicy("inv-1")
+		require.NoError(t, err)
+		require.True(t, policy.Revoked)
+	})
+	t.Run("wraps error from store", func(t *testing.T) {
+		expected := errors.New("test")
+		provider := testProvider()
+		provider.StoreProvider = mockstore.NewCustomMockStoreProvider(&stubStore{
+			putFunc: func(string, []byte) error {
+				return expected
+			},
+		})
+		s := newAutoService(t, provider)
+
+		err := s.SaveInvitationPolicy(&connection.InvitationPolicy{InvitationID: "inv-1", MaxUses: 3})
+		require.True(t, errors.Is(err, expected))
+	})
+}
+
 func TestChooseTarget(t *testing.T) {
 	t.Run("chooses a string", func(t *testing.T) {
 		expected := "abc123"
@@ -1139,7 +1547,7 @@ func newRequest() *Request {
 				Data: decorator.AttachmentData{
 					JSON: map[string]interface{}{
 						"@id":   "123",
-						"@type": "test-type",
+						"@type": presentproof.RequestPresentationMsgType,
 					},
 				},
 			},
@@ -1150,13 +1558,13 @@ func newRequest() *Request {
 
 func newInvitation() *Invitation {
 	return &Invitation{
-		ID:        uuid.New().String(),
-		Type:      InvitationMsgType,
-		Label:     "test",
-		Goal:      "test",
-		GoalCode:  "test",
-		Service:   []interface{}{"did:example:1235"},
-		Protocols: []string{didexchange.PIURI},
+		ID:                 uuid.New().String(),
+		Type:               InvitationMsgType,
+		Label:              "test",
+		Goal:               "test",
+		GoalCode:           "test",
+		Service:            []interface{}{"did:example:1235"},
+		HandshakeProtocols: []string{didexchange.PIURI},
 	}
 }
 
@@ -1262,11 +1670,11 @@ func (s *stubStore) Put(k string, v []byte) error {
 }
 
 func (s *stubStore) Get(k string) ([]byte, error) {
-	panic("implement me")
+	return nil, storage.ErrDataNotFound
 }
 
 func (s *stubStore) Iterator(start, limit string) storage.StoreIterator {
-	panic("implement me")
+	return mockstore.NewMockIterator(nil)
 }
 
 func (s *stubStore) Delete(k string) error {
@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	presentproofMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestService_ProtocolInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+
+	provider := presentproofMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+
+	svc, err := New(provider)
+	require.NoError(t, err)
+
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	instances, err := svc.ProtocolInstances()
+	require.NoError(t, err)
+	require.Empty(t, instances)
+
+	_, err = svc.ProtocolInstance("piid")
+	require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+
+	actions := make(chan service.DIDCommAction, 1)
+	require.NoError(t, svc.RegisterActionEvent(actions))
+
+	messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+	request := service.NewDIDCommMsgMap(RequestPresentation{Type: RequestPresentationMsgType})
+	require.NoError(t, request.SetID(uuid.New().String()))
+
+	_, err = svc.HandleInbound(request, Alice, Bob)
+	require.NoError(t, err)
+
+	piid := request.ID()
+
+	svc.now = func() time.Time { return now.Add(time.Minute) }
+
+	msg := randomInboundMessage(PresentationMsgType)
+	msg["~thread"] = map[string]interface{}{"thid": piid}
+
+	_, err = svc.HandleInbound(msg, Alice, Bob)
+	require.NoError(t, err)
+
+	(<-actions).Continue(nil)
+
+	instance, err := svc.ProtocolInstance(piid)
+	require.NoError(t, err)
+	require.Equal(t, piid, instance.PIID)
+	require.Equal(t, RoleVerifier, instance.Role)
+	require.Equal(t, Alice, instance.MyDID)
+	require.Equal(t, Bob, instance.TheirDID)
+	require.Equal(t, stateNameDone, instance.StateName)
+	require.True(t, now.Equal(instance.CreatedAt))
+	require.True(t, now.Add(time.Minute).Equal(instance.UpdatedAt))
+	require.Len(t, instance.History, 2)
+	require.Equal(t, []string{stateNameRequestSent}, instance.History[0].StateNames)
+	require.Equal(t, RequestPresentationMsgType, instance.History[0].Msg.Type())
+	require.Equal(t, []string{stateNamePresentationReceived, stateNameDone}, instance.History[1].StateNames)
+	require.Equal(t, PresentationMsgType, instance.History[1].Msg.Type())
+
+	instances, err = svc.ProtocolInstances()
+	require.NoError(t, err)
+	require.Equal(t, []*ProtocolInstance{instance}, instances)
+
+	require.NoError(t, svc.DeleteProtocolInstance(piid))
+	require.True(t, errors.Is(svc.DeleteProtocolInstance(piid), ErrProtocolInstanceNotFound))
+
+	_, err = svc.ProtocolInstance(piid)
+	require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+}
+
+func Test_roleFromState(t *testing.T) {
+	require.Equal(t, RoleVerifier, roleFromState(stateNameRequestSent))
+	require.Equal(t, RoleVerifier, roleFromState(stateNameProposalReceived))
+	require.Equal(t, RoleProver, roleFromState(stateNameRequestReceived))
+	require.Equal(t, RoleProver, roleFromState(stateNameProposalSent))
+	require.Empty(t, roleFromState(stateNameAbandoned))
+}
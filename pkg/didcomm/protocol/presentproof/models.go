@@ -38,6 +38,9 @@ type RequestPresentation struct {
 	Formats []Format `json:"formats,omitempty"`
 	// RequestPresentationsAttach is an array of attachments containing the acceptable verifiable presentation requests.
 	RequestPresentationsAttach []decorator.Attachment `json:"request_presentations~attach,omitempty"`
+	// Service is the ~service decorator which allows the prover to reply without a connection,
+	// e.g when the request is delivered as the attachment of the out-of-band request.
+	Service *decorator.Service `json:"~service,omitempty"`
 }
 
 // Presentation is a response to a RequestPresentation message and contains signed presentations.
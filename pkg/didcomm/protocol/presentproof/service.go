@@ -7,15 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package presentproof
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-
-	"github.com/google/uuid"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -37,48 +38,52 @@ const (
 	PresentationPreviewMsgType = Spec + "presentation-preview"
 )
 
-const (
-	internalDataKey        = "internal_data_"
-	transitionalPayloadKey = "transitionalPayload_%s"
-)
-
 // nolint:gochecknoglobals
 var (
 	logger         = log.New("aries-framework/presentproof/service")
 	initialHandler = HandlerFunc(func(_ Metadata) error {
 		return nil
 	})
-	errProtocolStopped = errors.New("protocol was stopped")
 )
 
-// customError is a wrapper to determine custom error against internal error.
-type customError struct{ error }
+// RejectedError wraps the given error to let the middleware decline the protocol.
+// The protocol is abandoned and the problem report is sent with the rejected code instead of the internal one.
+func RejectedError(err error) error {
+	return statemachine.RejectedError(err)
+}
 
-// transitionalPayload keeps payload needed for Continue function to proceed with the action.
-type transitionalPayload struct {
-	Action
-	StateName   string
+// internalData is persisted as the state of the protocol instance.
+type internalData struct {
 	AckRequired bool
+	StateName   string
+}
+
+func (d *internalData) CurrentState() string {
+	return d.StateName
+}
+
+func (d *internalData) SetCurrentState(name string) {
+	d.StateName = name
 }
 
 // metaData type to store data for internal usage.
 type metaData struct {
-	transitionalPayload
-	state               state
+	statemachine.Instance
 	presentationNames   []string
-	properties          map[string]interface{}
-	msgClone            service.DIDCommMsg
 	presentation        *Presentation
 	proposePresentation *ProposePresentation
 	request             *RequestPresentation
-	// err is used to determine whether callback was stopped
-	// e.g the user received an action event and executes Stop(err) function
-	// in that case `err` is equal to `err` which was passing to Stop function
-	err error
 }
 
-func (md *metaData) Message() service.DIDCommMsg {
-	return md.msgClone
+// data returns the data persisted with the state of the protocol instance.
+func (md *metaData) data() *internalData {
+	data, ok := md.Data.(*internalData)
+	if !ok {
+		data = &internalData{}
+		md.Data = data
+	}
+
+	return data
 }
 
 func (md *metaData) Presentation() *Presentation {
@@ -97,22 +102,12 @@ func (md *metaData) PresentationNames() []string {
 	return md.presentationNames
 }
 
-func (md *metaData) StateName() string {
-	return md.state.Name()
-}
-
-func (md *metaData) Properties() map[string]interface{} {
-	return md.properties
-}
-
 // Action contains helpful information about action.
-type Action struct {
-	// Protocol instance ID
-	PIID     string
-	Msg      service.DIDCommMsgMap
-	MyDID    string
-	TheirDID string
-}
+type Action = statemachine.Action
+
+// EventProps are the properties of the action and state events (service.DIDCommAction.Properties and
+// service.StateMsg.Properties).
+type EventProps = statemachine.EventProps
 
 // Opt describes option signature for the Continue function.
 type Opt func(md *metaData)
@@ -154,32 +149,88 @@ type Provider interface {
 	StorageProvider() storage.Provider
 }
 
+// connectionStoreProvider is implemented by the providers giving access to the connection store
+// (e.g. the framework context), the connection ID is added to the log fields then.
+type connectionStoreProvider interface {
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
 // Service for the presentproof protocol.
 type Service struct {
 	service.Action
 	service.Message
-	store      storage.Store
-	callbacks  chan *metaData
-	messenger  service.Messenger
-	middleware Handler
+	store     storage.Store
+	messenger service.Messenger
+	machine   *statemachine.Machine
+	timeouts  *statemachine.Timeouts
+	history   *statemachine.History
+	now       func() time.Time
 }
 
 // New returns the presentproof service.
-func New(p Provider) (*Service, error) {
+func New(p Provider, opts ...ServiceOption) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Name)
 	if err != nil {
 		return nil, err
 	}
 
 	svc := &Service{
-		messenger:  p.Messenger(),
-		store:      store,
-		callbacks:  make(chan *metaData),
-		middleware: initialHandler,
+		messenger: p.Messenger(),
+		store:     store,
+		now:       time.Now,
 	}
 
-	// start the listener
-	go svc.startInternalListener()
+	svc.timeouts = svc.newTimeouts()
+	svc.history = svc.newHistory()
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	svc.machine = statemachine.New(&statemachine.Definition{
+		Name:        Name,
+		Initial:     stateNameStart,
+		Transitions: transitions,
+		StateFromName: func(name string) statemachine.State {
+			return stateFromName(name)
+		},
+		NextState: func(msg service.DIDCommMsgMap) (statemachine.State, error) {
+			return nextState(msg)
+		},
+		ActionRequired: func(msg service.DIDCommMsgMap) bool {
+			return canReplyTo(msg) && canTriggerActionEvents(msg)
+		},
+		ClientRequired: canReplyTo,
+		Execute: execute,
+		Abandoned: func() statemachine.State {
+			return &abandoned{Code: codeInternalError}
+		},
+		NewMetadata: func() statemachine.Metadata {
+			return &metaData{}
+		},
+		NewData: func() statemachine.Data {
+			return &internalData{}
+		},
+		ApplyOption: func(md statemachine.Metadata, opt interface{}) {
+			if fn, ok := opt.(Opt); ok && fn != nil {
+				fn(md.(*metaData))
+			}
+		},
+		Handled: svc.handled,
+	}, store, svc.messenger, svc)
+
+	if cp, ok := p.(connectionStoreProvider); ok {
+		connections, e := connection.NewLookup(cp)
+		if e != nil {
+			return nil, e
+		}
+
+		svc.machine.UseConnectionLookup(connections)
+	}
+
+	// start abandoning the expired protocol instances
+	svc.timeouts.Start()
 
 	return svc, nil
 }
@@ -191,7 +242,15 @@ func (s *Service) Use(items ...Middleware) {
 		handler = items[i](handler)
 	}
 
-	s.middleware = handler
+	s.machine.Use(func(next statemachine.Handler) statemachine.Handler {
+		return statemachine.HandlerFunc(func(md statemachine.Metadata) error {
+			if err := handler.Handle(md.(*metaData)); err != nil {
+				return err
+			}
+
+			return next.Handle(md)
+		})
+	})
 }
 
 // HandleInbound handles inbound message (presentproof protocol).
@@ -200,41 +259,12 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 
 	msgMap := msg.Clone()
 
-	aEvent := s.ActionEvent()
-
-	canReply := canReplyTo(msgMap)
-
-	if canReply && aEvent == nil {
+	if canReplyTo(msgMap) && s.ActionEvent() == nil {
 		// throw error if there is no action event registered for inbound messages
 		return "", errors.New("no clients are registered to handle the message")
 	}
 
-	md, err := s.doHandle(msgMap)
-	if err != nil {
-		return "", fmt.Errorf("doHandle: %w", err)
-	}
-
-	md.MyDID = myDID
-	md.TheirDID = theirDID
-
-	// trigger action event based on message type for inbound messages
-	if canReply && canTriggerActionEvents(msgMap) {
-		err = s.saveTransitionalPayload(md.PIID, md.transitionalPayload)
-		if err != nil {
-			return "", fmt.Errorf("save transitional payload: %w", err)
-		}
-		aEvent <- s.newDIDCommActionMsg(md)
-
-		return "", nil
-	}
-
-	thid, err := msgMap.ThreadID()
-	if err != nil {
-		return "", fmt.Errorf("failed to obtain the message's threadID : %w", err)
-	}
-
-	// if no action event is triggered, continue the execution
-	return thid, s.handle(md)
+	return s.machine.HandleInbound(msgMap, myDID, theirDID)
 }
 
 // HandleOutbound handles outbound message (presentproof protocol).
@@ -242,153 +272,41 @@ func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, err
 	return "", errors.New("not implemented")
 }
 
-func (s *Service) getCurrentInternalDataAndPIID(msg service.DIDCommMsg) (string, *internalData, error) {
-	piID, err := getPIID(msg)
-	if errors.Is(err, service.ErrThreadIDNotFound) {
-		piID = uuid.New().String()
-
-		return piID, &internalData{StateName: stateNameStart}, msg.SetID(piID)
-	}
-
-	if err != nil {
-		return "", nil, fmt.Errorf("piID: %w", err)
-	}
-
-	data, err := s.currentInternalData(piID)
-	if err != nil {
-		return "", nil, fmt.Errorf("current internal data: %w", err)
-	}
-
-	return piID, data, nil
+// execute executes the state of the present proof protocol.
+func execute(st statemachine.State, md statemachine.Metadata) (statemachine.State, statemachine.StateAction, error) {
+	return st.(state).Execute(md.(*metaData))
 }
 
-func (s *Service) doHandle(msg service.DIDCommMsgMap) (*metaData, error) {
-	piID, data, err := s.getCurrentInternalDataAndPIID(msg)
-	if err != nil {
-		return nil, fmt.Errorf("current internal data and PIID: %w", err)
-	}
-
-	current := stateFromName(data.StateName)
-
-	next, err := nextState(msg)
-	if err != nil {
-		return nil, fmt.Errorf("nextState: %w", err)
+// handled persists the expiry and the history entry of the protocol instance once the message is handled.
+func (s *Service) handled(md statemachine.Metadata, stateNames []string) error {
+	var stateName string
+	if len(stateNames) > 0 {
+		stateName = stateNames[len(stateNames)-1]
 	}
 
-	if !current.CanTransitionTo(next) {
-		return nil, fmt.Errorf("invalid state transition: %s -> %s", current.Name(), next.Name())
+	if err := s.timeouts.Save(&md.(*metaData).Action, stateName); err != nil {
+		return fmt.Errorf("save expiry: %w", err)
 	}
 
-	return &metaData{
-		transitionalPayload: transitionalPayload{
-			StateName:   next.Name(),
-			AckRequired: data.AckRequired,
-			Action: Action{
-				Msg:  msg,
-				PIID: piID,
-			},
-		},
-		properties: map[string]interface{}{},
-		state:      next,
-		msgClone:   msg.Clone(),
-	}, nil
-}
-
-// startInternalListener listens to messages in go channel for callback messages from clients.
-func (s *Service) startInternalListener() {
-	for msg := range s.callbacks {
-		// if no error do handle
-		if msg.err == nil {
-			msg.err = s.handle(msg)
-		}
-
-		// no error - continue
-		if msg.err == nil {
-			continue
-		}
-
-		logger.Errorf("failed to handle msgID=%s : %s", msg.Msg.ID(), msg.err)
-
-		msg.state = &abandoned{Code: codeInternalError}
-
-		if err := s.handle(msg); err != nil {
-			logger.Errorf("listener handle: %s", err)
-		}
+	if len(stateNames) == 0 {
+		return nil
 	}
-}
-
-func isNoOp(s state) bool {
-	_, ok := s.(*noOp)
-	return ok
-}
-
-func (s *Service) handle(md *metaData) error {
-	current := md.state
-
-	for !isNoOp(current) {
-		next, action, err := s.execute(current, md)
-		if err != nil {
-			return fmt.Errorf("execute: %w", err)
-		}
-
-		if !isNoOp(next) && !current.CanTransitionTo(next) {
-			return fmt.Errorf("invalid state transition: %s --> %s", current.Name(), next.Name())
-		}
 
-		// WARN: md.ackRequired is being modified by requestSent state
-		data := &internalData{StateName: current.Name(), AckRequired: md.AckRequired}
-		if err := s.saveInternalData(md.PIID, data); err != nil {
-			return fmt.Errorf("failed to persist state %s: %w", current.Name(), err)
-		}
-
-		if err := action(s.messenger); err != nil {
-			return fmt.Errorf("action %s: %w", md.state.Name(), err)
-		}
-
-		current = next
+	if err := s.saveHistoryEntry(md.(*metaData), stateNames); err != nil {
+		return fmt.Errorf("save history entry: %w", err)
 	}
 
 	return nil
 }
 
-func getPIID(msg service.DIDCommMsg) (string, error) {
-	if pthID := msg.ParentThreadID(); pthID != "" {
-		return pthID, nil
-	}
-
-	return msg.ThreadID()
-}
-
-type internalData struct {
-	AckRequired bool
-	StateName   string
-}
-
-func (s *Service) saveInternalData(piID string, data *internalData) error {
-	src, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-
-	return s.store.Put(internalDataKey+piID, src)
-}
-
+// currentInternalData returns the data persisted as the current state of the protocol instance.
 func (s *Service) currentInternalData(piID string) (*internalData, error) {
-	src, err := s.store.Get(internalDataKey + piID)
-	if errors.Is(err, storage.ErrDataNotFound) {
-		return &internalData{StateName: stateNameStart}, nil
-	}
-
+	data, err := s.machine.CurrentData(piID)
 	if err != nil {
 		return nil, err
 	}
 
-	var data *internalData
-	if err := json.Unmarshal(src, &data); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return data.(*internalData), nil
 }
 
 // nolint: gocyclo
@@ -445,15 +363,6 @@ func nextState(msg service.DIDCommMsgMap) (state, error) {
 	}
 }
 
-func (s *Service) saveTransitionalPayload(id string, data transitionalPayload) error {
-	src, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("marshal transitional payload: %w", err)
-	}
-
-	return s.store.Put(fmt.Sprintf(transitionalPayloadKey, id), src)
-}
-
 // canTriggerActionEvents checks if the incoming message can trigger an action event.
 func canTriggerActionEvents(msg service.DIDCommMsg) bool {
 	return msg.Type() == PresentationMsgType ||
@@ -462,174 +371,19 @@ func canTriggerActionEvents(msg service.DIDCommMsg) bool {
 		msg.Type() == ProblemReportMsgType
 }
 
-func (s *Service) getTransitionalPayload(id string) (*transitionalPayload, error) {
-	src, err := s.store.Get(fmt.Sprintf(transitionalPayloadKey, id))
-	if err != nil {
-		return nil, fmt.Errorf("store get: %w", err)
-	}
-
-	t := &transitionalPayload{}
-
-	err = json.Unmarshal(src, t)
-	if err != nil {
-		return nil, fmt.Errorf("unmarshal transitional payload: %w", err)
-	}
-
-	return t, err
-}
-
-func (s *Service) deleteTransitionalPayload(id string) error {
-	return s.store.Delete(fmt.Sprintf(transitionalPayloadKey, id))
-}
-
 // Actions returns actions for the async usage.
 func (s *Service) Actions() ([]Action, error) {
-	records := s.store.Iterator(
-		fmt.Sprintf(transitionalPayloadKey, ""),
-		fmt.Sprintf(transitionalPayloadKey, storage.EndKeySuffix),
-	)
-	defer records.Release()
-
-	var actions []Action
-
-	for records.Next() {
-		var action Action
-		if err := json.Unmarshal(records.Value(), &action); err != nil {
-			return nil, fmt.Errorf("unmarshal: %w", err)
-		}
-
-		actions = append(actions, action)
-	}
-
-	if records.Error() != nil {
-		return nil, records.Error()
-	}
-
-	return actions, nil
+	return s.machine.Actions()
 }
 
 // ActionContinue allows proceeding with the action by the piID.
 func (s *Service) ActionContinue(piID string, opt Opt) error {
-	tPayload, err := s.getTransitionalPayload(piID)
-	if err != nil {
-		return fmt.Errorf("get transitional payload: %w", err)
-	}
-
-	md := &metaData{
-		transitionalPayload: *tPayload,
-		state:               stateFromName(tPayload.StateName),
-		msgClone:            tPayload.Msg.Clone(),
-		properties:          map[string]interface{}{},
-	}
-
-	if opt != nil {
-		opt(md)
-	}
-
-	if err := s.deleteTransitionalPayload(md.PIID); err != nil {
-		return fmt.Errorf("delete transitional payload: %w", err)
-	}
-
-	s.processCallback(md)
-
-	return nil
+	return s.machine.ActionContinue(piID, opt)
 }
 
 // ActionStop allows stopping the action by the piID.
 func (s *Service) ActionStop(piID string, cErr error) error {
-	tPayload, err := s.getTransitionalPayload(piID)
-	if err != nil {
-		return fmt.Errorf("get transitional payload: %w", err)
-	}
-
-	md := &metaData{
-		transitionalPayload: *tPayload,
-		state:               stateFromName(tPayload.StateName),
-		msgClone:            tPayload.Msg.Clone(),
-		properties:          map[string]interface{}{},
-	}
-
-	if err := s.deleteTransitionalPayload(md.PIID); err != nil {
-		return fmt.Errorf("delete transitional payload: %w", err)
-	}
-
-	if cErr == nil {
-		cErr = errProtocolStopped
-	}
-
-	md.err = customError{error: cErr}
-	s.processCallback(md)
-
-	return nil
-}
-
-func (s *Service) processCallback(msg *metaData) {
-	// pass the callback data to internal channel. This is created to unblock consumer go routine and wrap the callback
-	// channel internally.
-	s.callbacks <- msg
-}
-
-// newDIDCommActionMsg creates new DIDCommAction message.
-func (s *Service) newDIDCommActionMsg(md *metaData) service.DIDCommAction {
-	// create the message for the channel
-	// trigger the registered action event
-	return service.DIDCommAction{
-		ProtocolName: Name,
-		Message:      md.msgClone,
-		Continue: func(opt interface{}) {
-			if fn, ok := opt.(Opt); ok {
-				fn(md)
-			}
-
-			if err := s.deleteTransitionalPayload(md.PIID); err != nil {
-				logger.Errorf("continue: delete transitional payload: %v", err)
-			}
-
-			s.processCallback(md)
-		},
-		Stop: func(cErr error) {
-			if err := s.deleteTransitionalPayload(md.PIID); err != nil {
-				logger.Errorf("stop: delete transitional payload: %v", err)
-			}
-
-			if cErr == nil {
-				cErr = errProtocolStopped
-			}
-
-			md.err = customError{error: cErr}
-			s.processCallback(md)
-		},
-		Properties: newEventProps(md),
-	}
-}
-
-func (s *Service) execute(next state, md *metaData) (state, stateAction, error) {
-	md.state = next
-	s.sendMsgEvents(md, next.Name(), service.PreState)
-
-	defer s.sendMsgEvents(md, next.Name(), service.PostState)
-
-	md.properties = newEventProps(md).All()
-
-	if err := s.middleware.Handle(md); err != nil {
-		return nil, nil, fmt.Errorf("middleware: %w", err)
-	}
-
-	return next.Execute(md)
-}
-
-// sendMsgEvents triggers the message events.
-func (s *Service) sendMsgEvents(md *metaData, stateID string, stateType service.StateMsgType) {
-	// trigger the message events
-	for _, handler := range s.MsgEvents() {
-		handler <- service.StateMsg{
-			ProtocolName: Name,
-			Type:         stateType,
-			Msg:          md.msgClone,
-			StateID:      stateID,
-			Properties:   newEventProps(md),
-		}
-	}
+	return s.machine.ActionStop(piID, cErr)
 }
 
 // Name returns service name.
@@ -637,6 +391,11 @@ func (s *Service) Name() string {
 	return Name
 }
 
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{strings.TrimSuffix(Spec, "/")}
+}
+
 // Accept msg checks the msg type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	presentproofMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/presentproof"
 	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
@@ -82,12 +84,13 @@ func TestService_Use(t *testing.T) {
 		require.NotNil(t, svc)
 
 		meta := &metaData{
-			state: &done{},
-			msgClone: service.NewDIDCommMsgMap(struct {
-				Type string `json:"@type"`
-			}{
-				Type: PresentationMsgType,
-			}),
+			Instance: statemachine.NewInstance(Action{
+				Msg: service.NewDIDCommMsgMap(struct {
+					Type string `json:"@type"`
+				}{
+					Type: PresentationMsgType,
+				}),
+			}, nil, &done{}),
 			presentation:        &Presentation{Type: PresentationMsgType},
 			proposePresentation: &ProposePresentation{Type: ProposePresentationMsgType},
 			request:             &RequestPresentation{Type: RequestPresentationMsgType},
@@ -96,20 +99,20 @@ func TestService_Use(t *testing.T) {
 		var executed bool
 		svc.Use(func(next Handler) Handler {
 			return HandlerFunc(func(metadata Metadata) error {
-				require.Equal(t, meta.msgClone, metadata.Message())
+				require.Equal(t, meta.Message(), metadata.Message())
 				require.Equal(t, metadata.Message().Type(), PresentationMsgType)
 				require.Equal(t, meta.presentation, metadata.Presentation())
 				require.Equal(t, meta.proposePresentation, metadata.ProposePresentation())
 				require.Equal(t, meta.request, metadata.RequestPresentation())
 				require.Equal(t, meta.presentationNames, metadata.PresentationNames())
-				require.Equal(t, meta.state.Name(), metadata.StateName())
+				require.Equal(t, meta.State.Name(), metadata.StateName())
 
 				executed = true
 				return next.Handle(metadata)
 			})
 		})
 
-		_, _, err = svc.execute(meta.state, meta)
+		_, _, err = svc.machine.Execute(meta.State, meta)
 		require.NoError(t, err)
 		require.True(t, executed)
 	})
@@ -139,7 +142,7 @@ func TestService_Use(t *testing.T) {
 			})
 		})
 
-		_, _, err = svc.execute(&done{}, &metaData{})
+		_, _, err = svc.machine.Execute(&done{}, &metaData{})
 		require.NoError(t, err)
 	})
 
@@ -162,7 +165,7 @@ func TestService_Use(t *testing.T) {
 			})
 		})
 
-		_, _, err = svc.execute(&done{}, &metaData{})
+		_, _, err = svc.machine.Execute(&done{}, &metaData{})
 		require.EqualError(t, err, "middleware: "+msgErr)
 	})
 }
@@ -201,6 +204,65 @@ func TestService_ActionContinue(t *testing.T) {
 		err = svc.ActionContinue("piID", nil)
 		require.Contains(t, fmt.Sprintf("%v", err), "delete transitional payload: "+errMsg)
 	})
+
+	t.Run("Continue the action persisted in the previous layout", func(t *testing.T) {
+		memStore, err := mem.NewProvider().OpenStore(Name)
+		require.NoError(t, err)
+
+		memProvider := storageMocks.NewMockProvider(ctrl)
+		memProvider.EXPECT().OpenStore(Name).Return(memStore, nil)
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				require.Equal(t, AckMsgType, msg.Type())
+
+				return nil
+			})
+
+		p := presentproofMocks.NewMockProvider(ctrl)
+		p.EXPECT().Messenger().Return(messenger)
+		p.EXPECT().StorageProvider().Return(memProvider)
+
+		svc, err := New(p)
+		require.NoError(t, err)
+
+		states := make(chan service.StateMsg, 10)
+		require.NoError(t, svc.RegisterMsgEvent(states))
+
+		msg := service.NewDIDCommMsgMap(Presentation{Type: PresentationMsgType})
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		// the state and the ack flag were kept next to the action
+		src, err := json.Marshal(map[string]interface{}{
+			"PIID":        "piID",
+			"Msg":         msg,
+			"MyDID":       Alice,
+			"TheirDID":    Bob,
+			"StateName":   stateNamePresentationReceived,
+			"AckRequired": true,
+		})
+		require.NoError(t, err)
+		require.NoError(t, memStore.Put("transitionalPayload_piID", src))
+
+		actions, err := svc.Actions()
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		require.Equal(t, "piID", actions[0].PIID)
+
+		require.NoError(t, svc.ActionContinue("piID", nil))
+
+		for {
+			select {
+			case st := <-states:
+				if st.StateID == stateNameDone && st.Type == service.PostState {
+					return
+				}
+			case <-time.After(time.Second):
+				t.Fatal("timeout")
+			}
+		}
+	})
 }
 
 func TestService_ActionStop(t *testing.T) {
@@ -260,6 +322,7 @@ func TestService_HandleInbound(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	store.EXPECT().Put(historyEntryKey{}, gomock.Any()).Return(nil).AnyTimes()
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(Name).Return(store, nil).AnyTimes()
@@ -379,7 +442,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -443,7 +506,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -593,7 +656,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -653,7 +716,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -699,7 +762,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -745,7 +808,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -807,7 +870,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -881,7 +944,7 @@ func TestService_HandleInbound(t *testing.T) {
 
 		action := <-ch
 
-		properties, ok := action.Properties.(*eventProps)
+		properties, ok := action.Properties.(*EventProps)
 		require.True(t, ok)
 		require.NotEmpty(t, properties.PIID())
 		require.Equal(t, properties.MyDID(), Alice)
@@ -1059,6 +1122,10 @@ func TestService_Name(t *testing.T) {
 	require.Equal(t, (*Service).Name(nil), Name)
 }
 
+func TestService_Protocols(t *testing.T) {
+	require.Equal(t, []string{"https://didcomm.org/present-proof/2.0"}, (*Service).Protocols(nil))
+}
+
 func TestService_Accept(t *testing.T) {
 	require.True(t, (*Service).Accept(nil, ProposePresentationMsgType))
 	require.True(t, (*Service).Accept(nil, RequestPresentationMsgType))
@@ -1084,29 +1151,6 @@ func TestService_canTriggerActionEvents(t *testing.T) {
 	require.False(t, canTriggerActionEvents(service.NewDIDCommMsgMap(struct{}{})))
 }
 
-func Test_getTransitionalPayload(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	store := storageMocks.NewMockStore(ctrl)
-
-	storeProvider := storageMocks.NewMockProvider(ctrl)
-	storeProvider.EXPECT().OpenStore(Name).Return(store, nil).AnyTimes()
-
-	provider := presentproofMocks.NewMockProvider(ctrl)
-	provider.EXPECT().Messenger().Return(nil).AnyTimes()
-	provider.EXPECT().StorageProvider().Return(storeProvider).AnyTimes()
-
-	store.EXPECT().Get(fmt.Sprintf(transitionalPayloadKey, "ID")).Return([]byte(`[]`), nil)
-
-	svc, err := New(provider)
-	require.NoError(t, err)
-
-	res, err := svc.getTransitionalPayload("ID")
-	require.Nil(t, res)
-	require.Contains(t, fmt.Sprintf("%v", err), "unmarshal transitional payload")
-}
-
 func Test_nextState(t *testing.T) {
 	next, err := nextState(service.NewDIDCommMsgMap(RequestPresentation{
 		Type: RequestPresentationMsgType,
@@ -1144,3 +1188,16 @@ func Test_nextState(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, next)
 }
+
+// historyEntryKey matches the keys of the protocol instance history entries.
+type historyEntryKey struct{}
+
+func (historyEntryKey) Matches(x interface{}) bool {
+	key, ok := x.(string)
+
+	return ok && strings.HasPrefix(key, "protocol_instance_")
+}
+
+func (historyEntryKey) String() string {
+	return "has prefix protocol_instance_"
+}
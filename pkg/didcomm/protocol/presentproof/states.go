@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
 )
 
 const (
@@ -19,7 +20,7 @@ const (
 	stateNameStart     = "start"
 	stateNameAbandoned = "abandoned"
 	stateNameDone      = "done"
-	stateNameNoop      = "noop"
+	stateNameNoop      = statemachine.StateNameNoOp
 
 	// states for Verifier.
 	stateNameRequestSent          = "request-sent"
@@ -41,19 +42,34 @@ const (
 )
 
 // state action for network call.
-type stateAction func(messenger service.Messenger) error
+type stateAction = statemachine.StateAction
 
 // the protocol's state.
 type state interface {
-	// Name of this state.
-	Name() string
-	// Whether this state allows transitioning into the next state.
-	CanTransitionTo(next state) bool
+	statemachine.State
 	// Executes this state, returning a followup state to be immediately executed as well.
 	// The 'noOp' state should be returned if the state has no followup.
 	Execute(msg *metaData) (state, stateAction, error)
 }
 
+// transitions declares the states each state is allowed to transition into.
+// nolint:gochecknoglobals
+var transitions = statemachine.Transitions{
+	// Verifier and Prover.
+	stateNameStart: {
+		stateNameRequestSent, stateNameProposalReceived,
+		stateNameProposalSent, stateNameRequestReceived,
+	},
+	// Prover.
+	stateNameRequestReceived:  {stateNamePresentationSent, stateNameProposalSent, stateNameAbandoned},
+	stateNamePresentationSent: {stateNameAbandoned, stateNameDone},
+	stateNameProposalSent:     {stateNameRequestReceived, stateNameAbandoned},
+	// Verifier.
+	stateNameRequestSent:          {stateNamePresentationReceived, stateNameProposalReceived, stateNameAbandoned},
+	stateNamePresentationReceived: {stateNameAbandoned, stateNameDone},
+	stateNameProposalReceived:     {stateNameRequestSent, stateNameAbandoned},
+}
+
 // represents zero state's action.
 func zeroAction(service.Messenger) error { return nil }
 
@@ -64,19 +80,6 @@ func (s *start) Name() string {
 	return stateNameStart
 }
 
-func (s *start) CanTransitionTo(st state) bool {
-	switch st.Name() {
-	// Verifier.
-	case stateNameRequestSent, stateNameProposalReceived:
-		return true
-	// Prover.
-	case stateNameProposalSent, stateNameRequestReceived:
-		return true
-	}
-
-	return false
-}
-
 func (s *start) Execute(_ *metaData) (state, stateAction, error) {
 	return nil, nil, fmt.Errorf("%s: is not implemented yet", s.Name())
 }
@@ -90,10 +93,6 @@ func (s *abandoned) Name() string {
 	return stateNameAbandoned
 }
 
-func (s *abandoned) CanTransitionTo(st state) bool {
-	return false
-}
-
 func (s *abandoned) Execute(md *metaData) (state, stateAction, error) {
 	// if code is not provided it means we do not need to notify the another agent.
 	// if we received ProblemReport message no need to answer.
@@ -103,8 +102,8 @@ func (s *abandoned) Execute(md *metaData) (state, stateAction, error) {
 
 	code := model.Code{Code: s.Code}
 
-	// if the protocol was stopped by the user we will set the rejected error code
-	if errors.As(md.err, &customError{}) {
+	// if the protocol was stopped by the user or declined by the middleware we will set the rejected error code
+	if statemachine.IsRejected(md.Err) {
 		code = model.Code{Code: codeRejectedError}
 	}
 
@@ -128,10 +127,6 @@ func (s *done) Name() string {
 	return stateNameDone
 }
 
-func (s *done) CanTransitionTo(_ state) bool {
-	return false
-}
-
 func (s *done) Execute(_ *metaData) (state, stateAction, error) {
 	return &noOp{}, zeroAction, nil
 }
@@ -143,10 +138,6 @@ func (s *noOp) Name() string {
 	return stateNameNoop
 }
 
-func (s *noOp) CanTransitionTo(_ state) bool {
-	return false
-}
-
 func (s *noOp) Execute(_ *metaData) (state, stateAction, error) {
 	return nil, nil, errors.New("cannot execute no-op")
 }
@@ -158,12 +149,6 @@ func (s *requestReceived) Name() string {
 	return stateNameRequestReceived
 }
 
-func (s *requestReceived) CanTransitionTo(st state) bool {
-	return st.Name() == stateNamePresentationSent ||
-		st.Name() == stateNameProposalSent ||
-		st.Name() == stateNameAbandoned
-}
-
 func (s *requestReceived) Execute(md *metaData) (state, stateAction, error) {
 	if md.presentation == nil {
 		return &proposalSent{}, zeroAction, nil
@@ -185,12 +170,6 @@ func (s *requestSent) Name() string {
 	return stateNameRequestSent
 }
 
-func (s *requestSent) CanTransitionTo(st state) bool {
-	return st.Name() == stateNamePresentationReceived ||
-		st.Name() == stateNameProposalReceived ||
-		st.Name() == stateNameAbandoned
-}
-
 func forwardInitial(md *metaData) stateAction {
 	return func(messenger service.Messenger) error {
 		return messenger.Send(md.Msg, md.MyDID, md.TheirDID)
@@ -205,7 +184,7 @@ func (s *requestSent) Execute(md *metaData) (state, stateAction, error) {
 			return nil, nil, err
 		}
 
-		md.AckRequired = req.WillConfirm
+		md.data().AckRequired = req.WillConfirm
 
 		return &noOp{}, forwardInitial(md), nil
 	}
@@ -214,7 +193,7 @@ func (s *requestSent) Execute(md *metaData) (state, stateAction, error) {
 		return nil, nil, errors.New("request was not provided")
 	}
 
-	md.AckRequired = md.request.WillConfirm
+	md.data().AckRequired = md.request.WillConfirm
 
 	return &noOp{}, func(messenger service.Messenger) error {
 		md.request.Type = RequestPresentationMsgType
@@ -231,11 +210,6 @@ func (s *presentationSent) Name() string {
 	return stateNamePresentationSent
 }
 
-func (s *presentationSent) CanTransitionTo(st state) bool {
-	return st.Name() == stateNameAbandoned ||
-		st.Name() == stateNameDone
-}
-
 func (s *presentationSent) Execute(md *metaData) (state, stateAction, error) {
 	if md.presentation == nil {
 		return nil, nil, errors.New("presentation was not provided")
@@ -262,13 +236,8 @@ func (s *presentationReceived) Name() string {
 	return stateNamePresentationReceived
 }
 
-func (s *presentationReceived) CanTransitionTo(st state) bool {
-	return st.Name() == stateNameAbandoned ||
-		st.Name() == stateNameDone
-}
-
 func (s *presentationReceived) Execute(md *metaData) (state, stateAction, error) {
-	if !md.AckRequired {
+	if !md.data().AckRequired {
 		return &done{}, zeroAction, nil
 	}
 
@@ -289,11 +258,6 @@ func (s *proposalSent) Name() string {
 	return stateNameProposalSent
 }
 
-func (s *proposalSent) CanTransitionTo(st state) bool {
-	return st.Name() == stateNameRequestReceived ||
-		st.Name() == stateNameAbandoned
-}
-
 func canReplyTo(msg service.DIDCommMsgMap) bool {
 	_, ok := msg[jsonThread]
 	return ok
@@ -321,11 +285,6 @@ func (s *proposalReceived) Name() string {
 	return stateNameProposalReceived
 }
 
-func (s *proposalReceived) CanTransitionTo(st state) bool {
-	return st.Name() == stateNameRequestSent ||
-		st.Name() == stateNameAbandoned
-}
-
 func (s *proposalReceived) Execute(_ *metaData) (state, stateAction, error) {
 	return &requestSent{}, zeroAction, nil
 }
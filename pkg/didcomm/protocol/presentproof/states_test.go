@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 )
 
@@ -25,18 +26,18 @@ func TestStart_CanTransitionTo(t *testing.T) {
 	st := &start{}
 	require.Equal(t, stateNameStart, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.False(t, st.CanTransitionTo(&abandoned{}))
-	require.False(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.False(t, canTransitionTo(st, &abandoned{}))
+	require.False(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.True(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.True(t, st.CanTransitionTo(&proposalReceived{}))
+	require.True(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.True(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.True(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.True(t, st.CanTransitionTo(&proposalSent{}))
+	require.True(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.True(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestStart_Execute(t *testing.T) {
@@ -50,18 +51,18 @@ func TestAbandoning_CanTransitionTo(t *testing.T) {
 	st := &abandoned{}
 	require.Equal(t, stateNameAbandoned, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.False(t, st.CanTransitionTo(&abandoned{}))
-	require.False(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.False(t, canTransitionTo(st, &abandoned{}))
+	require.False(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.False(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.False(t, st.CanTransitionTo(&proposalReceived{}))
+	require.False(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.False(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.False(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.False(t, st.CanTransitionTo(&proposalSent{}))
+	require.False(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.False(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestAbandoning_Execute(t *testing.T) {
@@ -103,7 +104,7 @@ func TestAbandoning_Execute(t *testing.T) {
 	})
 
 	t.Run("Custom Error", func(t *testing.T) {
-		md := &metaData{err: customError{error: errors.New("error")}}
+		md := &metaData{Instance: statemachine.Instance{Err: statemachine.StoppedError(errors.New("error"))}}
 		md.Msg = service.NewDIDCommMsgMap(struct{}{})
 
 		thID := uuid.New().String()
@@ -132,6 +133,37 @@ func TestAbandoning_Execute(t *testing.T) {
 		require.NoError(t, action(messenger))
 	})
 
+	t.Run("Rejected Error", func(t *testing.T) {
+		md := &metaData{Instance: statemachine.Instance{
+			Err: fmt.Errorf("middleware: %w", RejectedError(errors.New("error"))),
+		}}
+		md.Msg = service.NewDIDCommMsgMap(struct{}{})
+
+		require.NoError(t, md.Msg.SetID(uuid.New().String()))
+
+		followup, action, err := (&abandoned{Code: codeInternalError}).Execute(md)
+		require.NoError(t, err)
+		require.Equal(t, &noOp{}, followup)
+		require.NotNil(t, action)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().
+			ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, codeRejectedError, r.Description.Code)
+
+				return nil
+			})
+
+		require.NoError(t, action(messenger))
+		require.EqualError(t, errors.Unwrap(errors.Unwrap(md.Err)), "error")
+	})
+
 	t.Run("No error code", func(t *testing.T) {
 		md := &metaData{}
 		md.Msg = service.NewDIDCommMsgMap(struct{}{})
@@ -177,18 +209,18 @@ func TestRequestReceived_CanTransitionTo(t *testing.T) {
 	st := &requestReceived{}
 	require.Equal(t, stateNameRequestReceived, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.True(t, st.CanTransitionTo(&abandoned{}))
-	require.False(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.True(t, canTransitionTo(st, &abandoned{}))
+	require.False(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.False(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.False(t, st.CanTransitionTo(&proposalReceived{}))
+	require.False(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.False(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.False(t, st.CanTransitionTo(&requestReceived{}))
-	require.True(t, st.CanTransitionTo(&presentationSent{}))
-	require.True(t, st.CanTransitionTo(&proposalSent{}))
+	require.False(t, canTransitionTo(st, &requestReceived{}))
+	require.True(t, canTransitionTo(st, &presentationSent{}))
+	require.True(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestRequestReceived_Execute(t *testing.T) {
@@ -197,8 +229,8 @@ func TestRequestReceived_Execute(t *testing.T) {
 		msg["will_confirm"] = true
 
 		followup, action, err := (&requestReceived{}).Execute(&metaData{
-			presentation:        &Presentation{},
-			transitionalPayload: transitionalPayload{Action: Action{Msg: msg}},
+			presentation: &Presentation{},
+			Instance:     statemachine.Instance{Action: Action{Msg: msg}},
 		})
 		require.NoError(t, err)
 		require.Equal(t, &presentationSent{WillConfirm: true}, followup)
@@ -208,7 +240,7 @@ func TestRequestReceived_Execute(t *testing.T) {
 	t.Run("With presentation - Ack is not required", func(t *testing.T) {
 		followup, action, err := (&requestReceived{}).Execute(&metaData{
 			presentation: &Presentation{},
-			transitionalPayload: transitionalPayload{Action: Action{
+			Instance: statemachine.Instance{Action: Action{
 				Msg: randomInboundMessage(RequestPresentationMsgType),
 			}},
 		})
@@ -227,7 +259,7 @@ func TestRequestReceived_Execute(t *testing.T) {
 	t.Run("Message decode error", func(t *testing.T) {
 		followup, action, err := (&requestReceived{}).Execute(&metaData{
 			presentation: &Presentation{},
-			transitionalPayload: transitionalPayload{Action: Action{
+			Instance: statemachine.Instance{Action: Action{
 				Msg: service.DIDCommMsgMap{"@type": []int{1}},
 			}},
 		})
@@ -241,18 +273,18 @@ func TestRequestSent_CanTransitionTo(t *testing.T) {
 	st := &requestSent{}
 	require.Equal(t, stateNameRequestSent, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.True(t, st.CanTransitionTo(&abandoned{}))
-	require.False(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.True(t, canTransitionTo(st, &abandoned{}))
+	require.False(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.False(t, st.CanTransitionTo(&requestSent{}))
-	require.True(t, st.CanTransitionTo(&presentationReceived{}))
-	require.True(t, st.CanTransitionTo(&proposalReceived{}))
+	require.False(t, canTransitionTo(st, &requestSent{}))
+	require.True(t, canTransitionTo(st, &presentationReceived{}))
+	require.True(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.False(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.False(t, st.CanTransitionTo(&proposalSent{}))
+	require.False(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.False(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func randomInboundMessage(t string) service.DIDCommMsgMap {
@@ -270,8 +302,8 @@ func randomInboundMessage(t string) service.DIDCommMsgMap {
 func TestRequestSent_Execute(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		followup, action, err := (&requestSent{}).Execute(&metaData{
-			transitionalPayload: transitionalPayload{Action: Action{Msg: randomInboundMessage("")}},
-			request:             &RequestPresentation{},
+			Instance: statemachine.Instance{Action: Action{Msg: randomInboundMessage("")}},
+			request:  &RequestPresentation{},
 		})
 		require.NoError(t, err)
 		require.Equal(t, &noOp{}, followup)
@@ -288,7 +320,7 @@ func TestRequestSent_Execute(t *testing.T) {
 
 	t.Run("Request presentation is absent", func(t *testing.T) {
 		followup, action, err := (&requestSent{}).Execute(&metaData{
-			transitionalPayload: transitionalPayload{Action: Action{Msg: randomInboundMessage("")}},
+			Instance: statemachine.Instance{Action: Action{Msg: randomInboundMessage("")}},
 		})
 		require.EqualError(t, err, "request was not provided")
 		require.Nil(t, followup)
@@ -296,7 +328,7 @@ func TestRequestSent_Execute(t *testing.T) {
 	})
 
 	t.Run("Success (outbound)", func(t *testing.T) {
-		followup, action, err := (&requestSent{}).Execute(&metaData{transitionalPayload: transitionalPayload{
+		followup, action, err := (&requestSent{}).Execute(&metaData{Instance: statemachine.Instance{
 			Action: Action{Msg: service.NewDIDCommMsgMap(struct {
 				WillConfirm bool `json:"will_confirm"`
 			}{WillConfirm: true})},
@@ -315,7 +347,7 @@ func TestRequestSent_Execute(t *testing.T) {
 	})
 
 	t.Run("Message decode error", func(t *testing.T) {
-		followup, action, err := (&requestSent{}).Execute(&metaData{transitionalPayload: transitionalPayload{
+		followup, action, err := (&requestSent{}).Execute(&metaData{Instance: statemachine.Instance{
 			Action: Action{Msg: service.DIDCommMsgMap{"@type": []int{1}}},
 		}})
 		require.Error(t, err)
@@ -328,18 +360,18 @@ func TestPresentationSent_CanTransitionTo(t *testing.T) {
 	st := &presentationSent{}
 	require.Equal(t, stateNamePresentationSent, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.True(t, st.CanTransitionTo(&abandoned{}))
-	require.True(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.True(t, canTransitionTo(st, &abandoned{}))
+	require.True(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.False(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.False(t, st.CanTransitionTo(&proposalReceived{}))
+	require.False(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.False(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.False(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.False(t, st.CanTransitionTo(&proposalSent{}))
+	require.False(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.False(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestPresentationSent_Execute(t *testing.T) {
@@ -387,25 +419,25 @@ func TestPresentationReceived_CanTransitionTo(t *testing.T) {
 	st := &presentationReceived{}
 	require.Equal(t, stateNamePresentationReceived, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.True(t, st.CanTransitionTo(&abandoned{}))
-	require.True(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.True(t, canTransitionTo(st, &abandoned{}))
+	require.True(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.False(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.False(t, st.CanTransitionTo(&proposalReceived{}))
+	require.False(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.False(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.False(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.False(t, st.CanTransitionTo(&proposalSent{}))
+	require.False(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.False(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestPresentationReceived_Execute(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		followup, action, err := (&presentationReceived{}).Execute(&metaData{
-			transitionalPayload: transitionalPayload{AckRequired: true},
-			presentation:        &Presentation{},
+			Instance:     statemachine.Instance{Data: &internalData{AckRequired: true}},
+			presentation: &Presentation{},
 		})
 		require.NoError(t, err)
 		require.Equal(t, &done{}, followup)
@@ -436,24 +468,24 @@ func TestProposePresentationSent_CanTransitionTo(t *testing.T) {
 	st := &proposalSent{}
 	require.Equal(t, stateNameProposalSent, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.True(t, st.CanTransitionTo(&abandoned{}))
-	require.False(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.True(t, canTransitionTo(st, &abandoned{}))
+	require.False(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.False(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.False(t, st.CanTransitionTo(&proposalReceived{}))
+	require.False(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.False(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.True(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.False(t, st.CanTransitionTo(&proposalSent{}))
+	require.True(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.False(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestProposePresentationSent_Execute(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		followup, action, err := (&proposalSent{}).Execute(&metaData{
-			transitionalPayload: transitionalPayload{Action: Action{Msg: randomInboundMessage("")}},
+			Instance:            statemachine.Instance{Action: Action{Msg: randomInboundMessage("")}},
 			proposePresentation: &ProposePresentation{},
 		})
 		require.NoError(t, err)
@@ -471,7 +503,7 @@ func TestProposePresentationSent_Execute(t *testing.T) {
 
 	t.Run("Propose presentation is absent", func(t *testing.T) {
 		followup, action, err := (&proposalSent{}).Execute(&metaData{
-			transitionalPayload: transitionalPayload{Action: Action{Msg: randomInboundMessage("")}},
+			Instance: statemachine.Instance{Action: Action{Msg: randomInboundMessage("")}},
 		})
 		require.EqualError(t, err, "propose-presentation was not provided")
 		require.Nil(t, followup)
@@ -498,18 +530,18 @@ func TestProposePresentationReceived_CanTransitionTo(t *testing.T) {
 	st := &proposalReceived{}
 	require.Equal(t, stateNameProposalReceived, st.Name())
 	// common states
-	require.False(t, st.CanTransitionTo(&start{}))
-	require.True(t, st.CanTransitionTo(&abandoned{}))
-	require.False(t, st.CanTransitionTo(&done{}))
-	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, canTransitionTo(st, &start{}))
+	require.True(t, canTransitionTo(st, &abandoned{}))
+	require.False(t, canTransitionTo(st, &done{}))
+	require.False(t, canTransitionTo(st, &noOp{}))
 	// states for Verifier
-	require.True(t, st.CanTransitionTo(&requestSent{}))
-	require.False(t, st.CanTransitionTo(&presentationReceived{}))
-	require.False(t, st.CanTransitionTo(&proposalReceived{}))
+	require.True(t, canTransitionTo(st, &requestSent{}))
+	require.False(t, canTransitionTo(st, &presentationReceived{}))
+	require.False(t, canTransitionTo(st, &proposalReceived{}))
 	// states for Prover
-	require.False(t, st.CanTransitionTo(&requestReceived{}))
-	require.False(t, st.CanTransitionTo(&presentationSent{}))
-	require.False(t, st.CanTransitionTo(&proposalSent{}))
+	require.False(t, canTransitionTo(st, &requestReceived{}))
+	require.False(t, canTransitionTo(st, &presentationSent{}))
+	require.False(t, canTransitionTo(st, &proposalSent{}))
 }
 
 func TestProposePresentationReceived_Execute(t *testing.T) {
@@ -532,6 +564,10 @@ func notTransition(t *testing.T, st state) {
 	}
 
 	for _, s := range allState {
-		require.False(t, st.CanTransitionTo(s))
+		require.False(t, canTransitionTo(st, s))
 	}
 }
+
+func canTransitionTo(st, next state) bool {
+	return transitions.Allowed(st.Name(), next.Name())
+}
@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/statemachine"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// ServiceOption configures the service.
+type ServiceOption func(s *Service)
+
+// WithTimeout sets the default time the protocol instance waits for the next message from the other agent.
+// Once the time elapses the protocol instance is abandoned and the problem report is sent to the other agent.
+// The ~timing.expires_time decorator of the message takes precedence over the default.
+// By default, only protocol instances with the ~timing.expires_time decorator expire.
+func WithTimeout(timeout time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.timeouts.SetTimeout(timeout)
+	}
+}
+
+// WithReaperInterval sets how often the service looks for expired protocol instances (one minute by default).
+func WithReaperInterval(interval time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.timeouts.SetReaperInterval(interval)
+	}
+}
+
+// ThreadState describes the current state of the protocol instance.
+type ThreadState = statemachine.ThreadState
+
+func isTerminal(stateName string) bool {
+	return stateName == stateNameDone || stateName == stateNameAbandoned
+}
+
+// newTimeouts returns the timeouts of the present proof protocol instances.
+func (s *Service) newTimeouts() *statemachine.Timeouts {
+	return statemachine.NewTimeouts(&statemachine.TimeoutDefinition{
+		ProblemReportMsgType: ProblemReportMsgType,
+		Terminal:             isTerminal,
+		CurrentState: func(piID string) (string, error) {
+			data, err := s.currentInternalData(piID)
+			if err != nil {
+				return "", err
+			}
+
+			return data.StateName, nil
+		},
+		ActionPending: func(piID string) (bool, error) {
+			_, err := s.machine.Action(piID)
+			if errors.Is(err, storage.ErrDataNotFound) {
+				return false, nil
+			}
+
+			return err == nil, err
+		},
+		States: func() ([]ThreadState, error) {
+			instances, err := s.machine.States()
+			if err != nil {
+				return nil, err
+			}
+
+			states := make([]ThreadState, len(instances))
+			for i, instance := range instances {
+				states[i] = ThreadState{PIID: instance.PIID, StateName: instance.Data.CurrentState()}
+			}
+
+			return states, nil
+		},
+		Abandon: s.abandon,
+		Now: func() time.Time {
+			return s.now()
+		},
+	}, s.store, s.messenger)
+}
+
+// abandon persists and reports the abandoned state of the expired protocol instance.
+func (s *Service) abandon(e *statemachine.Expiry, problemReport service.DIDCommMsgMap) error {
+	data, err := s.currentInternalData(e.PIID)
+	if err != nil {
+		return err
+	}
+
+	return s.machine.Handle(&metaData{
+		Instance: statemachine.NewInstance(Action{
+			PIID:     e.PIID,
+			Msg:      problemReport,
+			MyDID:    e.MyDID,
+			TheirDID: e.TheirDID,
+		}, data, &abandoned{}),
+	})
+}
+
+// ThreadStates returns the current states of the protocol instances.
+func (s *Service) ThreadStates() ([]ThreadState, error) {
+	return s.timeouts.ThreadStates()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+// StateNameNoOp is the name of the state which ends the execution of the protocol instance,
+// it is returned as the followup state once the protocol instance waits for the next message.
+const StateNameNoOp = "noop"
+
+// StateAction is the network call of the state, it is executed once the state is persisted.
+type StateAction func(messenger service.Messenger) error
+
+// ZeroAction is the action of the states which do not send messages.
+func ZeroAction(service.Messenger) error { return nil }
+
+// State of the protocol instance.
+type State interface {
+	// Name of this state.
+	Name() string
+}
+
+// Transitions declares the states each state is allowed to transition into (by name).
+type Transitions map[string][]string
+
+// Allowed reports whether the state is allowed to transition into the next state.
+func (t Transitions) Allowed(from, to string) bool {
+	for _, name := range t[from] {
+		if name == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Data is persisted as the state of the protocol instance, it holds the name of the current state along with
+// the data the protocol needs to keep between the messages (e.g whether the other agent requested an ack).
+type Data interface {
+	// CurrentState returns the name of the current state.
+	CurrentState() string
+	// SetCurrentState sets the name of the current state.
+	SetCurrentState(name string)
+}
+
+// StateData is the Data of the protocols which keep the name of the current state only.
+type StateData struct {
+	StateName string
+}
+
+// CurrentState returns the name of the current state.
+func (d *StateData) CurrentState() string {
+	return d.StateName
+}
+
+// SetCurrentState sets the name of the current state.
+func (d *StateData) SetCurrentState(name string) {
+	d.StateName = name
+}
+
+// Definition declares the protocol handled by the Machine: its states, the transitions between them and
+// the messages which require an action from the user.
+type Definition struct {
+	// Name of the protocol, it is used as the protocol name of the events.
+	Name string
+	// Initial is the name of the state the new protocol instances are in.
+	Initial string
+	// Transitions declares the states each state is allowed to transition into.
+	Transitions Transitions
+	// StateFromName returns the state by given name.
+	StateFromName func(name string) State
+	// NextState returns the state the message moves the protocol instance into.
+	NextState func(msg service.DIDCommMsgMap) (State, error)
+	// ActionRequired reports whether the inbound message triggers an action event, the protocol instance
+	// is handled once the action is continued or stopped by the user.
+	ActionRequired func(msg service.DIDCommMsgMap) bool
+	// ClientRequired reports whether the inbound message is rejected when no client is registered to the action
+	// events, it is checked before the message is handled. ActionRequired is used if not provided.
+	ClientRequired func(msg service.DIDCommMsgMap) bool
+	// Execute executes the state, returning a followup state to be immediately executed as well.
+	// The state named StateNameNoOp should be returned if the state has no followup.
+	Execute func(st State, md Metadata) (State, StateAction, error)
+	// Abandoned returns the state the protocol instance moves into when the continued action fails.
+	Abandoned func() State
+	// NewMetadata returns the empty metadata of the protocol, the Instance is used if not provided.
+	NewMetadata func() Metadata
+	// NewData returns the empty data of the protocol, the StateData is used if not provided.
+	NewData func() Data
+	// ApplyOption applies the option provided by the user through the Continue function.
+	ApplyOption func(md Metadata, opt interface{})
+	// Handled is invoked with the names of the executed states once the message is handled (optional).
+	Handled func(md Metadata, stateNames []string) error
+}
+
+func (d *Definition) actionRequired(msg service.DIDCommMsgMap) bool {
+	return d.ActionRequired != nil && d.ActionRequired(msg)
+}
+
+func (d *Definition) clientRequired(msg service.DIDCommMsgMap) bool {
+	if d.ClientRequired == nil {
+		return d.actionRequired(msg)
+	}
+
+	return d.ClientRequired(msg)
+}
+
+func (d *Definition) newMetadata() Metadata {
+	if d.NewMetadata == nil {
+		return &Instance{}
+	}
+
+	return d.NewMetadata()
+}
+
+func (d *Definition) newData(stateName string) Data {
+	var data Data = &StateData{}
+	if d.NewData != nil {
+		data = d.NewData()
+	}
+
+	data.SetCurrentState(stateName)
+
+	return data
+}
@@ -0,0 +1,201 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const protocolInstanceKey = "protocol_instance_"
+
+// ErrProtocolInstanceNotFound is returned when the protocol instance record does not exist.
+var ErrProtocolInstanceNotFound = errors.New("protocol instance not found")
+
+// ProtocolInstance is the record of the protocol instance (thread) along with its history.
+type ProtocolInstance struct {
+	PIID      string    `json:"piid"`
+	Role      string    `json:"role"`
+	MyDID     string    `json:"my_did"`
+	TheirDID  string    `json:"their_did"`
+	StateName string    `json:"state_name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// History contains the handled messages along with the states they moved the protocol instance through.
+	History []*HistoryEntry `json:"history"`
+}
+
+// HistoryEntry describes the handled message of the protocol instance.
+type HistoryEntry struct {
+	PIID       string                `json:"piid"`
+	MyDID      string                `json:"my_did"`
+	TheirDID   string                `json:"their_did"`
+	StateNames []string              `json:"state_names"`
+	Time       time.Time             `json:"time"`
+	Msg        service.DIDCommMsgMap `json:"message"`
+}
+
+// HistoryDefinition declares how the records of the protocol instances are built.
+type HistoryDefinition struct {
+	// Role returns the role of the agent in the protocol instance which started in the given state.
+	Role func(stateName string) string
+	// Now returns the current time, time.Now is used if not provided.
+	Now func() time.Time
+}
+
+func (d *HistoryDefinition) now() time.Time {
+	if d.Now == nil {
+		return time.Now()
+	}
+
+	return d.Now()
+}
+
+// History keeps the handled messages of the protocol instances.
+type History struct {
+	def   *HistoryDefinition
+	store storage.Store
+}
+
+// NewHistory returns the history of the protocol instances, the entries are kept in the given store.
+func NewHistory(def *HistoryDefinition, store storage.Store) *History {
+	return &History{def: def, store: store}
+}
+
+// Save appends the entry to the history of the protocol instance, the entry time is set to the current time.
+func (h *History) Save(entry *HistoryEntry) error {
+	entry.Time = h.def.now()
+
+	src, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal history entry: %w", err)
+	}
+
+	return h.store.Put(fmt.Sprintf("%s%s_%020d", protocolInstanceKey, entry.PIID, entry.Time.UnixNano()), src)
+}
+
+func (h *History) entries(prefix string) ([]*HistoryEntry, error) {
+	records := h.store.Iterator(prefix, prefix+storage.EndKeySuffix)
+	defer records.Release()
+
+	var entries []*HistoryEntry
+
+	for records.Next() {
+		var entry *HistoryEntry
+		if err := json.Unmarshal(records.Value(), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshal history entry: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if records.Error() != nil {
+		return nil, records.Error()
+	}
+
+	return entries, nil
+}
+
+// ProtocolInstances returns the records of the protocol instances.
+func (h *History) ProtocolInstances() ([]*ProtocolInstance, error) {
+	entries, err := h.entries(protocolInstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("history entries: %w", err)
+	}
+
+	var (
+		instances []*ProtocolInstance
+		byPIID    = make(map[string][]*HistoryEntry)
+	)
+
+	for _, entry := range entries {
+		if _, ok := byPIID[entry.PIID]; !ok {
+			instances = append(instances, &ProtocolInstance{PIID: entry.PIID})
+		}
+
+		byPIID[entry.PIID] = append(byPIID[entry.PIID], entry)
+	}
+
+	for _, instance := range instances {
+		h.fill(instance, byPIID[instance.PIID])
+	}
+
+	return instances, nil
+}
+
+// ProtocolInstance returns the record of the protocol instance by the piID.
+func (h *History) ProtocolInstance(piID string) (*ProtocolInstance, error) {
+	entries, err := h.entries(protocolInstanceKey + piID + "_")
+	if err != nil {
+		return nil, fmt.Errorf("history entries: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrProtocolInstanceNotFound
+	}
+
+	instance := &ProtocolInstance{PIID: piID}
+	h.fill(instance, entries)
+
+	return instance, nil
+}
+
+// DeleteProtocolInstance deletes the record of the protocol instance by the piID.
+func (h *History) DeleteProtocolInstance(piID string) error {
+	prefix := protocolInstanceKey + piID + "_"
+
+	records := h.store.Iterator(prefix, prefix+storage.EndKeySuffix)
+	defer records.Release()
+
+	var keys []string
+
+	for records.Next() {
+		keys = append(keys, string(records.Key()))
+	}
+
+	if records.Error() != nil {
+		return records.Error()
+	}
+
+	if len(keys) == 0 {
+		return ErrProtocolInstanceNotFound
+	}
+
+	for _, key := range keys {
+		if err := h.store.Delete(key); err != nil {
+			return fmt.Errorf("delete history entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (h *History) fill(p *ProtocolInstance, entries []*HistoryEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+
+	first, last := entries[0], entries[len(entries)-1]
+
+	p.MyDID, p.TheirDID = last.MyDID, last.TheirDID
+	p.CreatedAt, p.UpdatedAt = first.Time, last.Time
+	p.History = entries
+
+	if len(first.StateNames) > 0 && h.def.Role != nil {
+		p.Role = h.def.Role(first.StateNames[0])
+	}
+
+	if len(last.StateNames) > 0 {
+		p.StateName = last.StateNames[len(last.StateNames)-1]
+	}
+}
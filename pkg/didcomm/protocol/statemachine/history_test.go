@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func pingRole(stateName string) string {
+	switch stateName {
+	case statePingSent:
+		return "sender"
+	case statePingReceived:
+		return "receiver"
+	default:
+		return ""
+	}
+}
+
+func TestHistory(t *testing.T) {
+	store, err := mem.NewProvider().OpenStore("ping")
+	require.NoError(t, err)
+
+	now := time.Now()
+	history := NewHistory(&HistoryDefinition{
+		Role: pingRole,
+		Now:  func() time.Time { return now },
+	}, store)
+
+	instances, err := history.ProtocolInstances()
+	require.NoError(t, err)
+	require.Empty(t, instances)
+
+	_, err = history.ProtocolInstance("piid")
+	require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+
+	ping := service.NewDIDCommMsgMap(struct {
+		Type string `json:"@type"`
+	}{Type: pingMsgType})
+
+	require.NoError(t, history.Save(&HistoryEntry{
+		PIID:       "piid",
+		MyDID:      Alice,
+		TheirDID:   Bob,
+		StateNames: []string{statePingSent},
+		Msg:        ping,
+	}))
+
+	now = now.Add(time.Minute)
+
+	pong := service.NewDIDCommMsgMap(struct {
+		Type string `json:"@type"`
+	}{Type: pongMsgType})
+
+	require.NoError(t, history.Save(&HistoryEntry{
+		PIID:       "piid",
+		MyDID:      Alice,
+		TheirDID:   Bob,
+		StateNames: []string{stateDone},
+		Msg:        pong,
+	}))
+
+	instance, err := history.ProtocolInstance("piid")
+	require.NoError(t, err)
+	require.Equal(t, "piid", instance.PIID)
+	require.Equal(t, "sender", instance.Role)
+	require.Equal(t, Alice, instance.MyDID)
+	require.Equal(t, Bob, instance.TheirDID)
+	require.Equal(t, stateDone, instance.StateName)
+	require.True(t, now.Add(-time.Minute).Equal(instance.CreatedAt))
+	require.True(t, now.Equal(instance.UpdatedAt))
+	require.Len(t, instance.History, 2)
+	require.Equal(t, []string{statePingSent}, instance.History[0].StateNames)
+	require.Equal(t, pingMsgType, instance.History[0].Msg.Type())
+	require.Equal(t, pongMsgType, instance.History[1].Msg.Type())
+
+	instances, err = history.ProtocolInstances()
+	require.NoError(t, err)
+	require.Equal(t, []*ProtocolInstance{instance}, instances)
+
+	require.NoError(t, history.DeleteProtocolInstance("piid"))
+	require.True(t, errors.Is(history.DeleteProtocolInstance("piid"), ErrProtocolInstanceNotFound))
+
+	_, err = history.ProtocolInstance("piid")
+	require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+}
+
+func TestHistory_Save(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storageMocks.NewMockStore(ctrl)
+	store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+	err := NewHistory(&HistoryDefinition{}, store).Save(&HistoryEntry{PIID: "piid"})
+	require.EqualError(t, err, "error")
+}
@@ -0,0 +1,563 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	internalDataKey        = "internal_data_"
+	transitionalPayloadKey = "transitionalPayload_%s"
+)
+
+var errNoClients = errors.New("no clients are registered to handle the message")
+
+// nolint:gochecknoglobals
+var (
+	logger         = log.New("aries-framework/statemachine")
+	initialHandler = HandlerFunc(func(_ Metadata) error {
+		return nil
+	})
+)
+
+// Events provides the channels the events of the protocol are triggered on,
+// it is typically implemented by the protocol service embedding service.Action and service.Message.
+type Events interface {
+	ActionEvent() chan<- service.DIDCommAction
+	MsgEvents() []chan<- service.StateMsg
+}
+
+// ConnectionLookup resolves the connection between the DIDs of the protocol instance,
+// the connection ID is added to the log fields.
+type ConnectionLookup interface {
+	GetConnectionIDByDIDs(myDID, theirDID string) (string, error)
+}
+
+// InstanceState is the current state of the protocol instance.
+type InstanceState struct {
+	PIID string
+	Data Data
+}
+
+// transitionalPayload keeps payload needed for Continue function to proceed with the action.
+type transitionalPayload struct {
+	Action
+	Data Data
+}
+
+// UnmarshalJSON reads the payloads persisted before the protocols were moved onto the Machine as well,
+// those kept the data of the protocol instance (e.g StateName, AckRequired) next to the action.
+func (t *transitionalPayload) UnmarshalJSON(b []byte) error {
+	payload := struct {
+		Action
+		Data json.RawMessage
+	}{}
+
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return err
+	}
+
+	t.Action = payload.Action
+
+	if t.Data == nil {
+		t.Data = &StateData{}
+	}
+
+	data := []byte(payload.Data)
+	if len(data) == 0 || string(data) == "null" {
+		data = b
+	}
+
+	return json.Unmarshal(data, t.Data)
+}
+
+// Machine moves the protocol instances through the states of the declared protocol. It persists their state,
+// triggers the action and state events, handles the continued and stopped actions and runs the middleware.
+type Machine struct {
+	def        *Definition
+	store      storage.Store
+	messenger  service.Messenger
+	events     Events
+	middleware Handler
+	callbacks  chan Metadata
+	// connections is used to add the connection ID to the log fields.
+	connections ConnectionLookup
+}
+
+// New returns the state machine of the protocol, the state of the protocol instances is kept in the given store.
+func New(def *Definition, store storage.Store, messenger service.Messenger, events Events) *Machine {
+	m := &Machine{
+		def:        def,
+		store:      store,
+		messenger:  messenger,
+		events:     events,
+		middleware: initialHandler,
+		callbacks:  make(chan Metadata),
+	}
+
+	// start the listener
+	go m.startInternalListener()
+
+	return m
+}
+
+// Use allows providing middlewares.
+func (m *Machine) Use(items ...Middleware) {
+	var handler Handler = initialHandler
+	for i := len(items) - 1; i >= 0; i-- {
+		handler = items[i](handler)
+	}
+
+	m.middleware = handler
+}
+
+// UseConnectionLookup allows adding the ID of the connection the protocol instances belong to to the log fields.
+func (m *Machine) UseConnectionLookup(connections ConnectionLookup) {
+	m.connections = connections
+}
+
+// HandleInbound moves the protocol instance the message belongs to into the next state.
+// If the message requires an action the action event is triggered and the protocol instance is handled
+// once the action is continued or stopped.
+func (m *Machine) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID string) (string, error) {
+	aEvent := m.events.ActionEvent()
+
+	if aEvent == nil && m.def.clientRequired(msg) {
+		// throw error if there is no action event registered for inbound messages
+		return "", errNoClients
+	}
+
+	md, err := m.doHandle(msg)
+	if err != nil {
+		return "", fmt.Errorf("doHandle: %w", err)
+	}
+
+	instance := md.instance()
+	instance.MyDID = myDID
+	instance.TheirDID = theirDID
+
+	// trigger action event based on message type for inbound messages
+	if m.def.actionRequired(msg) {
+		if aEvent == nil {
+			return "", errNoClients
+		}
+
+		err = m.saveTransitionalPayload(instance.PIID, &transitionalPayload{
+			Action: instance.Action,
+			Data:   instance.Data,
+		})
+		if err != nil {
+			return "", fmt.Errorf("save transitional payload: %w", err)
+		}
+
+		aEvent <- m.newDIDCommActionMsg(md)
+
+		return "", nil
+	}
+
+	thid, err := msg.ThreadID()
+	if err != nil {
+		return "", fmt.Errorf("failed to obtain the message's threadID : %w", err)
+	}
+
+	// if no action event is triggered, continue the execution
+	return thid, m.Handle(md)
+}
+
+func (m *Machine) doHandle(msg service.DIDCommMsgMap) (Metadata, error) {
+	piID, data, err := m.getCurrentDataAndPIID(msg)
+	if err != nil {
+		return nil, fmt.Errorf("current internal data and PIID: %w", err)
+	}
+
+	current := m.def.StateFromName(data.CurrentState())
+
+	next, err := m.def.NextState(msg)
+	if err != nil {
+		return nil, fmt.Errorf("nextState: %w", err)
+	}
+
+	if !m.def.Transitions.Allowed(current.Name(), next.Name()) {
+		return nil, fmt.Errorf("invalid state transition: %s -> %s", current.Name(), next.Name())
+	}
+
+	data.SetCurrentState(next.Name())
+
+	return m.newMetadata(Action{PIID: piID, Msg: msg}, data, next), nil
+}
+
+func (m *Machine) getCurrentDataAndPIID(msg service.DIDCommMsg) (string, Data, error) {
+	piID, err := getPIID(msg)
+	if errors.Is(err, service.ErrThreadIDNotFound) {
+		piID = uuid.New().String()
+
+		return piID, m.def.newData(m.def.Initial), msg.SetID(piID)
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("piID: %w", err)
+	}
+
+	data, err := m.CurrentData(piID)
+	if err != nil {
+		return "", nil, fmt.Errorf("current internal data: %w", err)
+	}
+
+	return piID, data, nil
+}
+
+func getPIID(msg service.DIDCommMsg) (string, error) {
+	if pthID := msg.ParentThreadID(); pthID != "" {
+		return pthID, nil
+	}
+
+	return msg.ThreadID()
+}
+
+func (m *Machine) newMetadata(action Action, data Data, st State) Metadata {
+	md := m.def.newMetadata()
+	*md.instance() = NewInstance(action, data, st)
+
+	return md
+}
+
+// startInternalListener listens to messages in go channel for callback messages from clients.
+func (m *Machine) startInternalListener() {
+	for md := range m.callbacks {
+		instance := md.instance()
+
+		// if no error do handle
+		if instance.Err == nil {
+			instance.Err = m.Handle(md)
+		}
+
+		// no error - continue
+		if instance.Err == nil {
+			continue
+		}
+
+		logger.WithFields(m.logFields(instance)).
+			Errorf("failed to handle msgID=%s : %s", instance.Msg.ID(), instance.Err)
+
+		instance.State = m.def.Abandoned()
+
+		if err := m.Handle(md); err != nil {
+			logger.Errorf("listener handle: %s", err)
+		}
+	}
+}
+
+func isNoOp(s State) bool {
+	return s.Name() == StateNameNoOp
+}
+
+// Handle executes the current state of the protocol instance along with its followup states.
+// Each state is persisted before its action is executed.
+func (m *Machine) Handle(md Metadata) error {
+	instance := md.instance()
+	if instance.Data == nil {
+		instance.Data = m.def.newData("")
+	}
+
+	var (
+		current    = instance.State
+		stateNames []string
+	)
+
+	for !isNoOp(current) {
+		stateNames = append(stateNames, current.Name())
+
+		next, action, err := m.Execute(current, md)
+		if err != nil {
+			return fmt.Errorf("execute: %w", err)
+		}
+
+		if !isNoOp(next) && !m.def.Transitions.Allowed(current.Name(), next.Name()) {
+			return fmt.Errorf("invalid state transition: %s --> %s", current.Name(), next.Name())
+		}
+
+		instance.Data.SetCurrentState(current.Name())
+
+		if err := m.saveData(instance.PIID, instance.Data); err != nil {
+			return fmt.Errorf("failed to persist state %s: %w", current.Name(), err)
+		}
+
+		if err := action(m.messenger); err != nil {
+			return fmt.Errorf("action %s: %w", instance.State.Name(), err)
+		}
+
+		current = next
+	}
+
+	if m.def.Handled == nil {
+		return nil
+	}
+
+	return m.def.Handled(md, stateNames)
+}
+
+// logFields returns the log fields of the protocol instance, its connection is looked up once.
+func (m *Machine) logFields(instance *Instance) log.Fields {
+	if instance.connectionID == "" && m.connections != nil && instance.MyDID != "" && instance.TheirDID != "" {
+		// the connection is unknown for the connectionless exchanges, the ID stays empty then
+		instance.connectionID, _ = m.connections.GetConnectionIDByDIDs(instance.MyDID, instance.TheirDID) // nolint: errcheck
+	}
+
+	return instance.logFields(m.def.Name)
+}
+
+// Execute executes the state with the middleware, the state events are triggered before and after the execution.
+func (m *Machine) Execute(next State, md Metadata) (State, StateAction, error) {
+	instance := md.instance()
+	instance.State = next
+	logger.WithFields(m.logFields(instance)).Debugf("execute state")
+
+	m.sendMsgEvents(instance, next.Name(), service.PreState)
+
+	defer m.sendMsgEvents(instance, next.Name(), service.PostState)
+
+	instance.properties = newEventProps(instance).All()
+
+	if err := m.middleware.Handle(md); err != nil {
+		return nil, nil, fmt.Errorf("middleware: %w", err)
+	}
+
+	return m.def.Execute(next, md)
+}
+
+// sendMsgEvents triggers the message events.
+func (m *Machine) sendMsgEvents(instance *Instance, stateID string, stateType service.StateMsgType) {
+	// trigger the message events
+	for _, handler := range m.events.MsgEvents() {
+		handler <- service.StateMsg{
+			ProtocolName: m.def.Name,
+			Type:         stateType,
+			Msg:          instance.msgClone,
+			StateID:      stateID,
+			Properties:   newEventProps(instance),
+		}
+	}
+}
+
+// CurrentData returns the data persisted as the current state of the protocol instance,
+// the new protocol instances are in the initial state.
+func (m *Machine) CurrentData(piID string) (Data, error) {
+	src, err := m.store.Get(internalDataKey + piID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return m.def.newData(m.def.Initial), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := m.def.newData("")
+	if err := json.Unmarshal(src, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (m *Machine) saveData(piID string, data Data) error {
+	src, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return m.store.Put(internalDataKey+piID, src)
+}
+
+// States returns the current states of the protocol instances.
+func (m *Machine) States() ([]InstanceState, error) {
+	records := m.store.Iterator(internalDataKey, internalDataKey+storage.EndKeySuffix)
+	defer records.Release()
+
+	var states []InstanceState
+
+	for records.Next() {
+		data := m.def.newData("")
+		if err := json.Unmarshal(records.Value(), data); err != nil {
+			return nil, fmt.Errorf("unmarshal internal data: %w", err)
+		}
+
+		states = append(states, InstanceState{
+			PIID: strings.TrimPrefix(string(records.Key()), internalDataKey),
+			Data: data,
+		})
+	}
+
+	if records.Error() != nil {
+		return nil, records.Error()
+	}
+
+	return states, nil
+}
+
+func (m *Machine) saveTransitionalPayload(id string, data *transitionalPayload) error {
+	src, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal transitional payload: %w", err)
+	}
+
+	return m.store.Put(fmt.Sprintf(transitionalPayloadKey, id), src)
+}
+
+func (m *Machine) getTransitionalPayload(id string) (*transitionalPayload, error) {
+	src, err := m.store.Get(fmt.Sprintf(transitionalPayloadKey, id))
+	if err != nil {
+		return nil, fmt.Errorf("store get: %w", err)
+	}
+
+	t := &transitionalPayload{Data: m.def.newData("")}
+
+	err = json.Unmarshal(src, t)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal transitional payload: %w", err)
+	}
+
+	return t, err
+}
+
+func (m *Machine) deleteTransitionalPayload(id string) error {
+	return m.store.Delete(fmt.Sprintf(transitionalPayloadKey, id))
+}
+
+// Action returns the action of the protocol instance which waits to be continued or stopped by the user.
+func (m *Machine) Action(piID string) (*Action, error) {
+	tPayload, err := m.getTransitionalPayload(piID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tPayload.Action, nil
+}
+
+// Actions returns actions for the async usage.
+func (m *Machine) Actions() ([]Action, error) {
+	records := m.store.Iterator(
+		fmt.Sprintf(transitionalPayloadKey, ""),
+		fmt.Sprintf(transitionalPayloadKey, storage.EndKeySuffix),
+	)
+	defer records.Release()
+
+	var actions []Action
+
+	for records.Next() {
+		var action Action
+		if err := json.Unmarshal(records.Value(), &action); err != nil {
+			return nil, fmt.Errorf("unmarshal: %w", err)
+		}
+
+		actions = append(actions, action)
+	}
+
+	if records.Error() != nil {
+		return nil, records.Error()
+	}
+
+	return actions, nil
+}
+
+// ActionContinue allows proceeding with the action by the piID.
+func (m *Machine) ActionContinue(piID string, opt interface{}) error {
+	tPayload, err := m.getTransitionalPayload(piID)
+	if err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	md := m.newMetadata(tPayload.Action, tPayload.Data, m.def.StateFromName(tPayload.Data.CurrentState()))
+
+	m.applyOption(md, opt)
+
+	if err := m.deleteTransitionalPayload(piID); err != nil {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	m.processCallback(md)
+
+	return nil
+}
+
+// ActionStop allows stopping the action by the piID.
+func (m *Machine) ActionStop(piID string, cErr error) error {
+	tPayload, err := m.getTransitionalPayload(piID)
+	if err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	md := m.newMetadata(tPayload.Action, tPayload.Data, m.def.StateFromName(tPayload.Data.CurrentState()))
+
+	if err := m.deleteTransitionalPayload(piID); err != nil {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	if cErr == nil {
+		cErr = errProtocolStopped
+	}
+
+	md.instance().Err = StoppedError(cErr)
+	m.processCallback(md)
+
+	return nil
+}
+
+func (m *Machine) applyOption(md Metadata, opt interface{}) {
+	if opt != nil && m.def.ApplyOption != nil {
+		m.def.ApplyOption(md, opt)
+	}
+}
+
+func (m *Machine) processCallback(md Metadata) {
+	// pass the callback data to internal channel. This is created to unblock consumer go routine and wrap the callback
+	// channel internally.
+	m.callbacks <- md
+}
+
+// newDIDCommActionMsg creates new DIDCommAction message.
+func (m *Machine) newDIDCommActionMsg(md Metadata) service.DIDCommAction {
+	instance := md.instance()
+
+	// create the message for the channel
+	// trigger the registered action event
+	return service.DIDCommAction{
+		ProtocolName: m.def.Name,
+		Message:      instance.msgClone,
+		Continue: func(opt interface{}) {
+			m.applyOption(md, opt)
+
+			if err := m.deleteTransitionalPayload(instance.PIID); err != nil {
+				logger.Errorf("continue: delete transitional payload: %v", err)
+			}
+
+			m.processCallback(md)
+		},
+		Stop: func(cErr error) {
+			if err := m.deleteTransitionalPayload(instance.PIID); err != nil {
+				logger.Errorf("stop: delete transitional payload: %v", err)
+			}
+
+			if cErr == nil {
+				cErr = errProtocolStopped
+			}
+
+			instance.Err = StoppedError(cErr)
+			m.processCallback(md)
+		},
+		Properties: newEventProps(instance),
+	}
+}
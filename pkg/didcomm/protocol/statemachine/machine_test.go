@@ -0,0 +1,674 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const (
+	Alice = "Alice"
+	Bob   = "Bob"
+
+	pingMsgType = "https://example.org/ping/1.0/ping"
+	pongMsgType = "https://example.org/ping/1.0/pong"
+
+	stateStart        = "start"
+	stateAbandoned    = "abandoned"
+	stateDone         = "done"
+	statePingSent     = "ping-sent"
+	statePingReceived = "ping-received"
+	statePongSent     = "pong-sent"
+)
+
+// pingState is the state of the ping protocol declared by the tests.
+type pingState string
+
+func (s pingState) Name() string {
+	return string(s)
+}
+
+// pingMetadata keeps the comment of the pong provided by the user along with the protocol instance.
+type pingMetadata struct {
+	Instance
+	comment string
+}
+
+// pingData is persisted with the state of the protocol instance.
+type pingData struct {
+	StateName string
+	Pings     int
+}
+
+func (d *pingData) CurrentState() string {
+	return d.StateName
+}
+
+func (d *pingData) SetCurrentState(name string) {
+	d.StateName = name
+}
+
+func executePing(st State, md Metadata) (State, StateAction, error) {
+	ping := md.(*pingMetadata)
+
+	switch st.Name() {
+	case statePingSent:
+		return pingState(StateNameNoOp), func(messenger service.Messenger) error {
+			return messenger.Send(ping.Msg, ping.MyDID, ping.TheirDID)
+		}, nil
+	case statePingReceived:
+		ping.Data.(*pingData).Pings++
+
+		return pingState(statePongSent), ZeroAction, nil
+	case statePongSent:
+		if ping.comment == "" {
+			return nil, nil, errors.New("comment was not provided")
+		}
+
+		return pingState(stateDone), func(messenger service.Messenger) error {
+			return messenger.ReplyTo(ping.Msg.ID(), service.NewDIDCommMsgMap(struct {
+				Type    string `json:"@type"`
+				Comment string `json:"comment"`
+			}{Type: pongMsgType, Comment: ping.comment}))
+		}, nil
+	case stateDone, stateAbandoned:
+		return pingState(StateNameNoOp), ZeroAction, nil
+	}
+
+	return nil, nil, fmt.Errorf("%s: is not implemented", st.Name())
+}
+
+func canReply(msg service.DIDCommMsgMap) bool {
+	_, ok := msg["~thread"]
+	return ok
+}
+
+func pingDefinition(handled func(Metadata, []string) error) *Definition {
+	return &Definition{
+		Name:    "ping",
+		Initial: stateStart,
+		Transitions: Transitions{
+			stateStart:        {statePingSent, statePingReceived},
+			statePingSent:     {stateDone, stateAbandoned},
+			statePingReceived: {statePongSent, stateAbandoned},
+			statePongSent:     {stateDone, stateAbandoned},
+		},
+		StateFromName: func(name string) State {
+			return pingState(name)
+		},
+		NextState: func(msg service.DIDCommMsgMap) (State, error) {
+			switch msg.Type() {
+			case pingMsgType:
+				if canReply(msg) {
+					return pingState(statePingReceived), nil
+				}
+
+				return pingState(statePingSent), nil
+			case pongMsgType:
+				return pingState(stateDone), nil
+			}
+
+			return nil, fmt.Errorf("unrecognized msgType: %s", msg.Type())
+		},
+		ActionRequired: func(msg service.DIDCommMsgMap) bool {
+			return msg.Type() == pingMsgType && canReply(msg)
+		},
+		Execute: executePing,
+		Abandoned: func() State {
+			return pingState(stateAbandoned)
+		},
+		NewMetadata: func() Metadata {
+			return &pingMetadata{}
+		},
+		NewData: func() Data {
+			return &pingData{}
+		},
+		ApplyOption: func(md Metadata, opt interface{}) {
+			if comment, ok := opt.(string); ok {
+				md.(*pingMetadata).comment = comment
+			}
+		},
+		Handled: handled,
+	}
+}
+
+// events implements Events the way the protocol services do.
+type events struct {
+	service.Action
+	service.Message
+}
+
+func newMachine(t *testing.T, messenger service.Messenger, handled func(Metadata, []string) error) (*Machine, *events) {
+	t.Helper()
+
+	store, err := mem.NewProvider().OpenStore("ping")
+	require.NoError(t, err)
+
+	e := &events{}
+
+	return New(pingDefinition(handled), store, messenger, e), e
+}
+
+func inboundPing() service.DIDCommMsgMap {
+	msg := service.NewDIDCommMsgMap(struct {
+		Type   string           `json:"@type"`
+		Thread decorator.Thread `json:"~thread"`
+	}{Type: pingMsgType, Thread: decorator.Thread{ID: uuid.New().String()}})
+
+	// nolint: errcheck
+	msg.SetID(uuid.New().String())
+
+	return msg
+}
+
+func TestMachine_HandleInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Send ping", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		var handled []string
+
+		machine, e := newMachine(t, messenger, func(md Metadata, stateNames []string) error {
+			handled = stateNames
+
+			return nil
+		})
+
+		states := make(chan service.StateMsg, 2)
+		require.NoError(t, e.RegisterMsgEvent(states))
+
+		thID, err := machine.HandleInbound(service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: pingMsgType}), Alice, Bob)
+		require.NoError(t, err)
+		require.NotEmpty(t, thID)
+		require.Equal(t, []string{statePingSent}, handled)
+
+		for _, stateType := range []service.StateMsgType{service.PreState, service.PostState} {
+			event := <-states
+			require.Equal(t, "ping", event.ProtocolName)
+			require.Equal(t, stateType, event.Type)
+			require.Equal(t, statePingSent, event.StateID)
+			require.Equal(t, thID, event.Properties.All()[piidPropKey])
+		}
+
+		instances, err := machine.States()
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		require.Equal(t, thID, instances[0].PIID)
+		require.Equal(t, statePingSent, instances[0].Data.CurrentState())
+
+		data, err := machine.CurrentData(thID)
+		require.NoError(t, err)
+		require.Equal(t, &pingData{StateName: statePingSent}, data)
+	})
+
+	t.Run("Receive ping (continue)", func(t *testing.T) {
+		done := make(chan struct{})
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				require.Equal(t, pongMsgType, msg.Type())
+				require.Equal(t, "pong", msg["comment"])
+
+				return nil
+			})
+
+		machine, e := newMachine(t, messenger, func(md Metadata, stateNames []string) error {
+			defer close(done)
+
+			require.Equal(t, []string{statePingReceived, statePongSent, stateDone}, stateNames)
+
+			return nil
+		})
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, e.RegisterActionEvent(actions))
+
+		msg := inboundPing()
+
+		thID, err := machine.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+		require.Empty(t, thID)
+
+		action := <-actions
+		require.Equal(t, "ping", action.ProtocolName)
+		require.Equal(t, pingMsgType, action.Message.Type())
+
+		properties, ok := action.Properties.(*EventProps)
+		require.True(t, ok)
+		require.Equal(t, Alice, properties.MyDID())
+		require.Equal(t, Bob, properties.TheirDID())
+
+		pending, err := machine.Actions()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, properties.PIID(), pending[0].PIID)
+
+		action.Continue("pong")
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+
+		data, err := machine.CurrentData(properties.PIID())
+		require.NoError(t, err)
+		require.Equal(t, &pingData{StateName: stateDone, Pings: 1}, data)
+
+		pending, err = machine.Actions()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+
+	t.Run("Receive ping (stop)", func(t *testing.T) {
+		done := make(chan struct{})
+
+		machine, e := newMachine(t, nil, func(md Metadata, stateNames []string) error {
+			defer close(done)
+
+			require.Equal(t, []string{stateAbandoned}, stateNames)
+			require.True(t, IsRejected(md.instance().Err))
+
+			return nil
+		})
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, e.RegisterActionEvent(actions))
+
+		_, err := machine.HandleInbound(inboundPing(), Alice, Bob)
+		require.NoError(t, err)
+
+		(<-actions).Stop(nil)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
+	t.Run("No clients", func(t *testing.T) {
+		machine, _ := newMachine(t, nil, nil)
+
+		_, err := machine.HandleInbound(inboundPing(), Alice, Bob)
+		require.EqualError(t, err, "no clients are registered to handle the message")
+	})
+
+	t.Run("No clients - checked before the message is handled", func(t *testing.T) {
+		store, err := mem.NewProvider().OpenStore("ping")
+		require.NoError(t, err)
+
+		def := pingDefinition(nil)
+		def.ClientRequired = canReply
+
+		msg := inboundPing()
+		msg["@type"] = "unknown"
+
+		// the message is rejected before its state transition is evaluated
+		_, err = New(def, store, nil, &events{}).HandleInbound(msg, Alice, Bob)
+		require.EqualError(t, err, "no clients are registered to handle the message")
+
+		def.ClientRequired = nil
+
+		_, err = New(def, store, nil, &events{}).HandleInbound(msg, Alice, Bob)
+		require.EqualError(t, err, "doHandle: nextState: unrecognized msgType: unknown")
+	})
+
+	t.Run("Unrecognized msgType", func(t *testing.T) {
+		machine, _ := newMachine(t, nil, nil)
+
+		_, err := machine.HandleInbound(service.NewDIDCommMsgMap(struct{}{}), Alice, Bob)
+		require.Contains(t, fmt.Sprintf("%v", err), "doHandle: nextState: unrecognized msgType: ")
+	})
+
+	t.Run("Invalid state transition", func(t *testing.T) {
+		machine, _ := newMachine(t, nil, nil)
+
+		_, err := machine.HandleInbound(service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: pongMsgType}), Alice, Bob)
+		require.Contains(t, fmt.Sprintf("%v", err), "doHandle: invalid state transition: start -> done")
+	})
+
+	t.Run("DB error", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{}, ErrGet: errors.New("get error")}
+
+		e := &events{}
+		require.NoError(t, e.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		machine := New(pingDefinition(nil), store, nil, e)
+
+		_, err := machine.HandleInbound(inboundPing(), Alice, Bob)
+		require.Contains(t, fmt.Sprintf("%v", err),
+			"doHandle: current internal data and PIID: current internal data: get error")
+	})
+
+	t.Run("Action error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(errors.New("send error"))
+
+		machine, _ := newMachine(t, messenger, nil)
+
+		_, err := machine.HandleInbound(service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: pingMsgType}), Alice, Bob)
+		require.Contains(t, fmt.Sprintf("%v", err), "action ping-sent: send error")
+	})
+}
+
+func TestMachine_ActionContinue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Continue by piID", func(t *testing.T) {
+		done := make(chan struct{})
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).Return(nil)
+
+		machine, e := newMachine(t, messenger, func(md Metadata, stateNames []string) error {
+			close(done)
+
+			return nil
+		})
+
+		require.NoError(t, e.RegisterActionEvent(make(chan service.DIDCommAction, 1)))
+
+		msg := inboundPing()
+
+		_, err := machine.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		thID, err := msg.ThreadID()
+		require.NoError(t, err)
+
+		action, err := machine.Action(thID)
+		require.NoError(t, err)
+		require.Equal(t, Alice, action.MyDID)
+
+		require.NoError(t, machine.ActionContinue(thID, "pong"))
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+
+		require.Error(t, machine.ActionContinue(thID, "pong"))
+	})
+
+	t.Run("Abandons the protocol instance on failure", func(t *testing.T) {
+		done := make(chan struct{})
+
+		machine, e := newMachine(t, nil, func(md Metadata, stateNames []string) error {
+			defer close(done)
+
+			require.Equal(t, []string{stateAbandoned}, stateNames)
+			require.EqualError(t, md.instance().Err, "execute: comment was not provided")
+
+			return nil
+		})
+
+		require.NoError(t, e.RegisterActionEvent(make(chan service.DIDCommAction, 1)))
+
+		msg := inboundPing()
+
+		_, err := machine.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		thID, err := msg.ThreadID()
+		require.NoError(t, err)
+
+		require.NoError(t, machine.ActionContinue(thID, nil))
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
+	t.Run("Continue the payload of the previous layout", func(t *testing.T) {
+		done := make(chan struct{})
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).Return(nil)
+
+		machine, _ := newMachine(t, messenger, func(md Metadata, stateNames []string) error {
+			defer close(done)
+
+			require.Equal(t, []string{statePingReceived, statePongSent, stateDone}, stateNames)
+			require.Equal(t, Alice, md.instance().MyDID)
+
+			return nil
+		})
+
+		msg := inboundPing()
+
+		thID, err := msg.ThreadID()
+		require.NoError(t, err)
+
+		// the data of the protocol instance was kept next to the action
+		src, err := json.Marshal(map[string]interface{}{
+			"PIID":      thID,
+			"Msg":       msg,
+			"MyDID":     Alice,
+			"TheirDID":  Bob,
+			"StateName": statePingReceived,
+			"Pings":     2,
+		})
+		require.NoError(t, err)
+		require.NoError(t, machine.store.Put(fmt.Sprintf(transitionalPayloadKey, thID), src))
+
+		action, err := machine.Action(thID)
+		require.NoError(t, err)
+		require.Equal(t, Bob, action.TheirDID)
+
+		require.NoError(t, machine.ActionContinue(thID, "pong"))
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+
+		data, err := machine.CurrentData(thID)
+		require.NoError(t, err)
+		require.Equal(t, &pingData{StateName: stateDone, Pings: 3}, data)
+	})
+
+	t.Run("Error transitional payload (get)", func(t *testing.T) {
+		machine, _ := newMachine(t, nil, nil)
+
+		err := machine.ActionContinue("piID", nil)
+		require.Contains(t, fmt.Sprintf("%v", err), "get transitional payload: store get: ")
+	})
+
+	t.Run("Error transitional payload (unmarshal)", func(t *testing.T) {
+		machine, _ := newMachine(t, nil, nil)
+		require.NoError(t, machine.store.Put(fmt.Sprintf(transitionalPayloadKey, "ID"), []byte(`[]`)))
+
+		res, err := machine.getTransitionalPayload("ID")
+		require.Nil(t, res)
+		require.Contains(t, fmt.Sprintf("%v", err), "unmarshal transitional payload")
+	})
+
+	t.Run("Error transitional payload (delete)", func(t *testing.T) {
+		store := &mockstore.MockStore{
+			Store: map[string][]byte{
+				fmt.Sprintf(transitionalPayloadKey, "ID"): []byte(`{}`),
+			},
+			ErrDelete: errors.New("delete error"),
+		}
+
+		machine := New(pingDefinition(nil), store, nil, &events{})
+
+		err := machine.ActionContinue("ID", nil)
+		require.Contains(t, fmt.Sprintf("%v", err), "delete transitional payload: delete error")
+
+		store.Store[fmt.Sprintf(transitionalPayloadKey, "ID")] = []byte(`{}`)
+
+		err = machine.ActionStop("ID", nil)
+		require.Contains(t, fmt.Sprintf("%v", err), "delete transitional payload: delete error")
+	})
+}
+
+func TestMachine_ActionStop(t *testing.T) {
+	done := make(chan struct{})
+
+	machine, e := newMachine(t, nil, func(md Metadata, stateNames []string) error {
+		defer close(done)
+
+		require.Equal(t, []string{stateAbandoned}, stateNames)
+		require.EqualError(t, errors.Unwrap(md.instance().Err), "declined")
+		require.Nil(t, newEventProps(md.instance()).Err())
+
+		return nil
+	})
+
+	require.NoError(t, e.RegisterActionEvent(make(chan service.DIDCommAction, 1)))
+
+	msg := inboundPing()
+
+	_, err := machine.HandleInbound(msg, Alice, Bob)
+	require.NoError(t, err)
+
+	thID, err := msg.ThreadID()
+	require.NoError(t, err)
+
+	require.NoError(t, machine.ActionStop(thID, errors.New("declined")))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("timeout")
+	}
+
+	require.Error(t, machine.ActionStop(thID, nil))
+}
+
+func TestMachine_Use(t *testing.T) {
+	machine, _ := newMachine(t, nil, nil)
+
+	msg := inboundPing()
+	md := &pingMetadata{Instance: NewInstance(Action{Msg: msg}, &pingData{}, nil)}
+
+	var executed []string
+
+	machine.Use(func(next Handler) Handler {
+		return HandlerFunc(func(metadata Metadata) error {
+			require.Equal(t, msg, metadata.Message())
+			require.Equal(t, statePingReceived, metadata.StateName())
+
+			executed = append(executed, "first")
+
+			return next.Handle(metadata)
+		})
+	}, func(next Handler) Handler {
+		return HandlerFunc(func(metadata Metadata) error {
+			executed = append(executed, "second")
+
+			metadata.Properties()["custom"] = "value"
+
+			return next.Handle(metadata)
+		})
+	})
+
+	next, action, err := machine.Execute(pingState(statePingReceived), md)
+	require.NoError(t, err)
+	require.Equal(t, pingState(statePongSent), next)
+	require.NotNil(t, action)
+	require.Equal(t, []string{"first", "second"}, executed)
+	require.Equal(t, "value", md.Properties()["custom"])
+
+	machine.Use(func(next Handler) Handler {
+		return HandlerFunc(func(metadata Metadata) error {
+			return RejectedError(errors.New("rejected"))
+		})
+	})
+
+	_, _, err = machine.Execute(pingState(statePingReceived), md)
+	require.EqualError(t, err, "middleware: rejected")
+	require.True(t, IsRejected(err))
+}
+
+type connectionLookup struct {
+	lookups int
+}
+
+func (c *connectionLookup) GetConnectionIDByDIDs(myDID, theirDID string) (string, error) {
+	c.lookups++
+
+	if myDID == Alice && theirDID == Bob {
+		return "connID", nil
+	}
+
+	return "", errors.New("connection not found")
+}
+
+func TestMachine_LogFields(t *testing.T) {
+	machine, _ := newMachine(t, nil, nil)
+
+	instance := NewInstance(Action{Msg: inboundPing(), MyDID: Alice, TheirDID: Bob}, &pingData{}, pingState(stateStart))
+	require.Empty(t, machine.logFields(&instance)[log.FieldConnectionID])
+
+	lookup := &connectionLookup{}
+	machine.UseConnectionLookup(lookup)
+
+	fields := machine.logFields(&instance)
+	require.Equal(t, "connID", fields[log.FieldConnectionID])
+	require.Equal(t, "ping", fields[log.FieldProtocol])
+	require.Equal(t, stateStart, fields[log.FieldState])
+
+	// the connection is looked up once per protocol instance
+	machine.logFields(&instance)
+	require.Equal(t, 1, lookup.lookups)
+
+	// connectionless exchanges have no connection
+	connectionless := NewInstance(Action{Msg: inboundPing(), MyDID: Alice}, &pingData{}, nil)
+	require.Empty(t, machine.logFields(&connectionless)[log.FieldConnectionID])
+	require.Equal(t, 1, lookup.lookups)
+}
+
+func TestTransitions_Allowed(t *testing.T) {
+	transitions := pingDefinition(nil).Transitions
+
+	require.True(t, transitions.Allowed(stateStart, statePingSent))
+	require.True(t, transitions.Allowed(statePingReceived, stateAbandoned))
+	require.False(t, transitions.Allowed(stateStart, stateDone))
+	require.False(t, transitions.Allowed(stateDone, stateStart))
+	require.False(t, transitions.Allowed("unknown", stateStart))
+}
+
+func TestDefinition_Defaults(t *testing.T) {
+	def := &Definition{}
+
+	require.Equal(t, &Instance{}, def.newMetadata())
+	require.Equal(t, &StateData{StateName: stateStart}, def.newData(stateStart))
+	require.Equal(t, stateStart, def.newData(stateStart).CurrentState())
+}
@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+// Action contains helpful information about action.
+type Action struct {
+	// Protocol instance ID
+	PIID     string
+	Msg      service.DIDCommMsgMap
+	MyDID    string
+	TheirDID string
+}
+
+// Metadata provides helpful information for the processing, it is passed to the states and the middleware.
+// Protocols embed the Instance in their own metadata to keep the data provided by the user along with it.
+type Metadata interface {
+	// Message contains the original inbound/outbound message
+	Message() service.DIDCommMsg
+	// StateName provides the state name
+	StateName() string
+	// Properties provides the possibility to set properties
+	Properties() map[string]interface{}
+
+	instance() *Instance
+}
+
+// Instance is the protocol instance handling the message.
+type Instance struct {
+	Action
+	// Data is persisted as the state of the protocol instance.
+	Data Data
+	// State is the state being executed.
+	State State
+	// Err is used to determine whether callback was stopped
+	// e.g the user received an action event and executes Stop(err) function
+	// in that case `Err` wraps the `err` which was passing to Stop function
+	Err        error
+	properties map[string]interface{}
+	msgClone   service.DIDCommMsg
+	// connectionID is the connection the protocol instance belongs to, it is used in the log fields only.
+	connectionID string
+}
+
+// NewInstance returns the protocol instance in the given state.
+func NewInstance(action Action, data Data, st State) Instance {
+	instance := Instance{
+		Action:     action,
+		Data:       data,
+		State:      st,
+		properties: map[string]interface{}{},
+	}
+
+	if action.Msg != nil {
+		instance.msgClone = action.Msg.Clone()
+	}
+
+	return instance
+}
+
+// Message returns the copy of the message taken before it was handled.
+func (i *Instance) Message() service.DIDCommMsg {
+	return i.msgClone
+}
+
+// StateName returns the name of the state being executed.
+func (i *Instance) StateName() string {
+	return i.State.Name()
+}
+
+// Properties returns the properties of the events.
+func (i *Instance) Properties() map[string]interface{} {
+	return i.properties
+}
+
+func (i *Instance) instance() *Instance {
+	return i
+}
+
+// logFields returns fields which allow to correlate log lines of the protocol instance.
+func (i *Instance) logFields(protocol string) log.Fields {
+	fields := service.LogFields(i.Msg)
+	fields[log.FieldProtocol] = protocol
+	fields[log.FieldConnectionID] = i.connectionID
+
+	if i.State != nil {
+		fields[log.FieldState] = i.State.Name()
+	}
+
+	return fields
+}
+
+// Handler describes middleware interface.
+type Handler interface {
+	Handle(metadata Metadata) error
+}
+
+// Middleware function receives next handler and returns handler that needs to be executed.
+type Middleware func(next Handler) Handler
+
+// HandlerFunc is a helper type which implements the middleware Handler interface.
+type HandlerFunc func(metadata Metadata) error
+
+// Handle implements function to satisfy the Handler interface.
+func (hf HandlerFunc) Handle(metadata Metadata) error {
+	return hf(metadata)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import "errors"
+
+const (
+	myDIDPropKey    = "myDID"
+	theirDIDPropKey = "theirDID"
+	piidPropKey     = "piid"
+	errorPropKey    = "error"
+)
+
+var errProtocolStopped = errors.New("protocol was stopped")
+
+// stoppedError is a wrapper to determine the error the action was stopped with against internal error.
+type stoppedError struct{ error }
+
+func (e stoppedError) Unwrap() error {
+	return e.error
+}
+
+// StoppedError wraps the error the user stopped the action with.
+func StoppedError(err error) error {
+	return stoppedError{error: err}
+}
+
+// rejectedError is a wrapper to determine an error returned by a middleware which declines the protocol.
+type rejectedError struct{ error }
+
+func (e rejectedError) Unwrap() error {
+	return e.error
+}
+
+// RejectedError wraps the given error to let the middleware decline the protocol.
+func RejectedError(err error) error {
+	return rejectedError{error: err}
+}
+
+// IsRejected reports whether the protocol instance was stopped by the user or declined by the middleware.
+func IsRejected(err error) bool {
+	return errors.As(err, &stoppedError{}) || errors.As(err, &rejectedError{})
+}
+
+// EventProps are the properties of the action and state events of the protocol instance.
+type EventProps struct {
+	properties map[string]interface{}
+	myDID      string
+	theirDID   string
+	piid       string
+	err        error
+}
+
+func newEventProps(instance *Instance) *EventProps {
+	properties := instance.properties
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	return &EventProps{
+		properties: properties,
+		myDID:      instance.MyDID,
+		theirDID:   instance.TheirDID,
+		piid:       instance.PIID,
+		err:        instance.Err,
+	}
+}
+
+// MyDID returns the DID of the agent.
+func (e *EventProps) MyDID() string {
+	return e.myDID
+}
+
+// TheirDID returns the DID of the other agent.
+func (e *EventProps) TheirDID() string {
+	return e.theirDID
+}
+
+// PIID returns the protocol instance ID.
+func (e *EventProps) PIID() string {
+	return e.piid
+}
+
+// Err returns the error the protocol instance failed with, the error the action was stopped with is not reported.
+func (e EventProps) Err() error {
+	if errors.As(e.err, &stoppedError{}) {
+		return nil
+	}
+
+	return e.err
+}
+
+// All implements EventProperties interface.
+func (e EventProps) All() map[string]interface{} {
+	if e.myDID != "" {
+		e.properties[myDIDPropKey] = e.myDID
+	}
+
+	if e.theirDID != "" {
+		e.properties[theirDIDPropKey] = e.theirDID
+	}
+
+	if e.piid != "" {
+		e.properties[piidPropKey] = e.piid
+	}
+
+	if e.Err() != nil {
+		e.properties[errorPropKey] = e.Err()
+	}
+
+	return e.properties
+}
@@ -0,0 +1,52 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventProps_All(t *testing.T) {
+	instance := &Instance{}
+	instance.MyDID = "MyDID"
+	instance.TheirDID = "TheirDID"
+	instance.PIID = "PIID"
+	instance.Err = errors.New("error")
+
+	props := newEventProps(instance)
+
+	require.Equal(t, instance.MyDID, props.MyDID())
+	require.Equal(t, instance.TheirDID, props.TheirDID())
+	require.Equal(t, instance.PIID, props.PIID())
+	require.Equal(t, instance.Err, props.Err())
+	require.Equal(t, 4, len(props.All()))
+
+	instance.Err = StoppedError(errors.New("error"))
+	instance.MyDID = ""
+
+	props = newEventProps(instance)
+
+	require.Equal(t, instance.MyDID, props.MyDID())
+	require.Equal(t, instance.TheirDID, props.TheirDID())
+	require.Equal(t, instance.PIID, props.PIID())
+	require.Equal(t, nil, props.Err())
+	require.Equal(t, 2, len(props.All()))
+}
+
+func TestIsRejected(t *testing.T) {
+	require.True(t, IsRejected(StoppedError(errors.New("error"))))
+	require.True(t, IsRejected(fmt.Errorf("middleware: %w", RejectedError(errors.New("error")))))
+	require.False(t, IsRejected(errors.New("error")))
+	require.False(t, IsRejected(nil))
+
+	require.EqualError(t, errors.Unwrap(RejectedError(errors.New("error"))), "error")
+	require.EqualError(t, errors.Unwrap(StoppedError(errors.New("error"))), "error")
+}
@@ -0,0 +1,284 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	expiryKey = "expiry_"
+
+	// CodeExpiredError is the error code of the problem report which is sent when the protocol instance expires.
+	CodeExpiredError = "expired"
+
+	defaultReaperInterval = time.Minute
+)
+
+// ThreadState describes the current state of the protocol instance.
+type ThreadState struct {
+	PIID      string `json:"piid"`
+	StateName string `json:"state_name"`
+	// ExpiresTime is the time the protocol instance is abandoned at if the other agent does not reply.
+	ExpiresTime *time.Time `json:"expires_time,omitempty"`
+}
+
+// Expiry keeps data needed to abandon the protocol instance once it expires.
+type Expiry struct {
+	PIID        string
+	StateName   string
+	ThreadID    string
+	MyDID       string
+	TheirDID    string
+	ExpiresTime time.Time
+}
+
+// TimeoutDefinition declares how the protocol instances of the protocol expire.
+type TimeoutDefinition struct {
+	// ProblemReportMsgType is the type of the problem report sent to the other agent once the instance expires.
+	ProblemReportMsgType string
+	// Terminal reports whether the protocol instance ends in the state, such instances do not expire.
+	Terminal func(stateName string) bool
+	// CurrentState returns the name of the current state of the protocol instance.
+	CurrentState func(piID string) (string, error)
+	// ActionPending reports whether the action of the protocol instance waits for the user,
+	// such instances do not expire since the other agent has replied.
+	ActionPending func(piID string) (bool, error)
+	// States returns the current states of the protocol instances.
+	States func() ([]ThreadState, error)
+	// Abandon persists and reports the abandoned state of the expired protocol instance,
+	// the problem report is already sent to the other agent.
+	Abandon func(e *Expiry, problemReport service.DIDCommMsgMap) error
+	// Now returns the current time, time.Now is used if not provided.
+	Now func() time.Time
+}
+
+func (d *TimeoutDefinition) now() time.Time {
+	if d.Now == nil {
+		return time.Now()
+	}
+
+	return d.Now()
+}
+
+// Timeouts abandons the protocol instances whose other agent did not reply in time
+// and sends the problem report to the other agent.
+type Timeouts struct {
+	def            *TimeoutDefinition
+	store          storage.Store
+	messenger      service.Messenger
+	timeout        time.Duration
+	reaperInterval time.Duration
+}
+
+// NewTimeouts returns the timeouts of the protocol, the expiries are kept in the given store.
+// By default, only the protocol instances with the ~timing.expires_time decorator expire.
+func NewTimeouts(def *TimeoutDefinition, store storage.Store, messenger service.Messenger) *Timeouts {
+	return &Timeouts{
+		def:            def,
+		store:          store,
+		messenger:      messenger,
+		reaperInterval: defaultReaperInterval,
+	}
+}
+
+// SetTimeout sets the default time the protocol instance waits for the next message from the other agent.
+// The ~timing.expires_time decorator of the message takes precedence over the default.
+func (t *Timeouts) SetTimeout(timeout time.Duration) {
+	t.timeout = timeout
+}
+
+// SetReaperInterval sets how often the expired protocol instances are looked for (one minute by default).
+func (t *Timeouts) SetReaperInterval(interval time.Duration) {
+	t.reaperInterval = interval
+}
+
+// Start starts abandoning the expired protocol instances periodically.
+func (t *Timeouts) Start() {
+	go t.startReaper()
+}
+
+// expiresTime returns the time the protocol instance expires at, the ~timing.expires_time decorator
+// of the message takes precedence over the default timeout.
+func (t *Timeouts) expiresTime(msg service.DIDCommMsgMap) (time.Time, bool) {
+	timing := struct {
+		Timing *decorator.Timing `json:"~timing,omitempty"`
+	}{}
+
+	if err := msg.Decode(&timing); err == nil && timing.Timing != nil && !timing.Timing.ExpiresTime.IsZero() {
+		return timing.Timing.ExpiresTime, true
+	}
+
+	if t.timeout > 0 {
+		return t.def.now().Add(t.timeout), true
+	}
+
+	return time.Time{}, false
+}
+
+// Save persists the expiry of the protocol instance which waits for the other agent in the given state,
+// the action holds the last message of the protocol instance.
+func (t *Timeouts) Save(action *Action, stateName string) error {
+	if t.def.Terminal(stateName) {
+		return nil
+	}
+
+	expiresTime, ok := t.expiresTime(action.Msg)
+	if !ok {
+		return nil
+	}
+
+	thID, err := action.Msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("threadID: %w", err)
+	}
+
+	src, err := json.Marshal(&Expiry{
+		PIID:        action.PIID,
+		StateName:   stateName,
+		ThreadID:    thID,
+		MyDID:       action.MyDID,
+		TheirDID:    action.TheirDID,
+		ExpiresTime: expiresTime,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal expiry: %w", err)
+	}
+
+	return t.store.Put(expiryKey+action.PIID, src)
+}
+
+// Expiries returns the expiries of the protocol instances by PIID.
+func (t *Timeouts) Expiries() (map[string]*Expiry, error) {
+	records := t.store.Iterator(expiryKey, expiryKey+storage.EndKeySuffix)
+	defer records.Release()
+
+	result := make(map[string]*Expiry)
+
+	for records.Next() {
+		var e *Expiry
+		if err := json.Unmarshal(records.Value(), &e); err != nil {
+			return nil, fmt.Errorf("unmarshal expiry: %w", err)
+		}
+
+		result[e.PIID] = e
+	}
+
+	if records.Error() != nil {
+		return nil, records.Error()
+	}
+
+	return result, nil
+}
+
+// startReaper periodically abandons the expired protocol instances.
+func (t *Timeouts) startReaper() {
+	ticker := time.NewTicker(t.reaperInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := t.AbandonExpired(); err != nil {
+			logger.Errorf("abandon expired protocol instances: %s", err)
+		}
+	}
+}
+
+// AbandonExpired abandons the protocol instances which expired.
+func (t *Timeouts) AbandonExpired() error {
+	expiries, err := t.Expiries()
+	if err != nil {
+		return fmt.Errorf("expiries: %w", err)
+	}
+
+	now := t.def.now()
+
+	for _, e := range expiries {
+		if e.ExpiresTime.After(now) {
+			continue
+		}
+
+		if err = t.Abandon(e); err != nil {
+			logger.Errorf("abandon expired protocol instance piid=%s: %s", e.PIID, err)
+		}
+	}
+
+	return nil
+}
+
+// Abandon sends the problem report to the other agent and abandons the expired protocol instance,
+// unless the protocol instance has moved on since the expiry was saved.
+func (t *Timeouts) Abandon(e *Expiry) error {
+	stateName, err := t.def.CurrentState(e.PIID)
+	if err != nil {
+		return fmt.Errorf("current state name: %w", err)
+	}
+
+	// the protocol instance has moved on since the expiry was saved
+	if stateName != e.StateName {
+		return t.store.Delete(expiryKey + e.PIID)
+	}
+
+	// the other agent has replied, the action is pending on our side
+	pending, err := t.def.ActionPending(e.PIID)
+	if err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	if pending {
+		return nil
+	}
+
+	msg := service.NewDIDCommMsgMap(&model.ProblemReport{
+		Type:        t.def.ProblemReportMsgType,
+		Description: model.Code{Code: CodeExpiredError},
+	})
+
+	err = t.messenger.ReplyToNested(msg, &service.NestedReplyOpts{
+		ThreadID: e.ThreadID,
+		MyDID:    e.MyDID,
+		TheirDID: e.TheirDID,
+	})
+	if err != nil {
+		return fmt.Errorf("send problem report: %w", err)
+	}
+
+	if err = t.store.Delete(expiryKey + e.PIID); err != nil {
+		return fmt.Errorf("delete expiry: %w", err)
+	}
+
+	// the problem report was sent, the abandoned state only needs to be persisted and reported
+	return t.def.Abandon(e, msg)
+}
+
+// ThreadStates returns the current states of the protocol instances along with the time they expire at.
+func (t *Timeouts) ThreadStates() ([]ThreadState, error) {
+	expiries, err := t.Expiries()
+	if err != nil {
+		return nil, fmt.Errorf("expiries: %w", err)
+	}
+
+	states, err := t.def.States()
+	if err != nil {
+		return nil, fmt.Errorf("protocol instance states: %w", err)
+	}
+
+	for i := range states {
+		if e, ok := expiries[states[i].PIID]; ok && e.StateName == states[i].StateName {
+			expiresTime := e.ExpiresTime
+			states[i].ExpiresTime = &expiresTime
+		}
+	}
+
+	return states, nil
+}
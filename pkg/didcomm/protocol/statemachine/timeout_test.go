@@ -0,0 +1,278 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const problemReportMsgType = "https://example.org/ping/1.0/problem-report"
+
+// pingTimeouts is the ping protocol whose instances expire, it keeps the current states in memory.
+type pingTimeouts struct {
+	*Timeouts
+	states    map[string]string
+	pending   map[string]bool
+	abandoned []*Expiry
+	now       time.Time
+}
+
+func newPingTimeouts(t *testing.T, messenger service.Messenger) *pingTimeouts {
+	t.Helper()
+
+	store, err := mem.NewProvider().OpenStore("ping")
+	require.NoError(t, err)
+
+	p := &pingTimeouts{
+		states:  map[string]string{},
+		pending: map[string]bool{},
+		now:     time.Now(),
+	}
+
+	p.Timeouts = NewTimeouts(&TimeoutDefinition{
+		ProblemReportMsgType: problemReportMsgType,
+		Terminal: func(stateName string) bool {
+			return stateName == stateDone || stateName == stateAbandoned
+		},
+		CurrentState: func(piID string) (string, error) {
+			return p.states[piID], nil
+		},
+		ActionPending: func(piID string) (bool, error) {
+			return p.pending[piID], nil
+		},
+		States: func() ([]ThreadState, error) {
+			var states []ThreadState
+			for piID, stateName := range p.states {
+				states = append(states, ThreadState{PIID: piID, StateName: stateName})
+			}
+
+			return states, nil
+		},
+		Abandon: func(e *Expiry, problemReport service.DIDCommMsgMap) error {
+			require.Equal(t, problemReportMsgType, problemReport.Type())
+			p.states[e.PIID] = stateAbandoned
+			p.abandoned = append(p.abandoned, e)
+
+			return nil
+		},
+		Now: func() time.Time {
+			return p.now
+		},
+	}, store, messenger)
+
+	return p
+}
+
+// sendPing saves the expiry of the protocol instance which waits for the pong.
+func (p *pingTimeouts) sendPing(t *testing.T, timing *decorator.Timing) string {
+	t.Helper()
+
+	msg := service.NewDIDCommMsgMap(struct {
+		Type string `json:"@type"`
+	}{Type: pingMsgType})
+	require.NoError(t, msg.SetID(uuid.New().String()))
+
+	if timing != nil {
+		msg["~timing"] = timing
+	}
+
+	p.states[msg.ID()] = statePingSent
+
+	require.NoError(t, p.Save(&Action{PIID: msg.ID(), Msg: msg, MyDID: Alice, TheirDID: Bob}, statePingSent))
+
+	return msg.ID()
+}
+
+func TestTimeouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectProblemReport := func(messenger *serviceMocks.MockMessenger, piid string) {
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, problemReportMsgType, r.Type)
+				require.Equal(t, CodeExpiredError, r.Description.Code)
+				require.Equal(t, &service.NestedReplyOpts{ThreadID: piid, MyDID: Alice, TheirDID: Bob}, opts)
+
+				return nil
+			})
+	}
+
+	t.Run("abandons the expired protocol instance (default timeout)", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		p := newPingTimeouts(t, messenger)
+		p.SetTimeout(time.Hour)
+
+		piid := p.sendPing(t, nil)
+
+		threads, err := p.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Equal(t, piid, threads[0].PIID)
+		require.True(t, p.now.Add(time.Hour).Equal(*threads[0].ExpiresTime))
+
+		// the protocol instance did not expire yet
+		require.NoError(t, p.AbandonExpired())
+		require.Empty(t, p.abandoned)
+
+		expectProblemReport(messenger, piid)
+
+		p.now = p.now.Add(2 * time.Hour)
+		require.NoError(t, p.AbandonExpired())
+		require.Len(t, p.abandoned, 1)
+		require.Equal(t, piid, p.abandoned[0].PIID)
+
+		threads, err = p.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Equal(t, stateAbandoned, threads[0].StateName)
+		require.Nil(t, threads[0].ExpiresTime)
+	})
+
+	t.Run("abandons the expired protocol instance (~timing decorator)", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		p := newPingTimeouts(t, messenger)
+		p.SetTimeout(time.Hour)
+
+		piid := p.sendPing(t, &decorator.Timing{ExpiresTime: p.now.Add(-time.Minute)})
+
+		expectProblemReport(messenger, piid)
+
+		require.NoError(t, p.AbandonExpired())
+		require.Len(t, p.abandoned, 1)
+	})
+
+	t.Run("the reaper abandons the expired protocol instance", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		store, err := mem.NewProvider().OpenStore("ping")
+		require.NoError(t, err)
+
+		done := make(chan struct{})
+
+		timeouts := NewTimeouts(&TimeoutDefinition{
+			ProblemReportMsgType: problemReportMsgType,
+			Terminal:             func(string) bool { return false },
+			CurrentState:         func(string) (string, error) { return statePingSent, nil },
+			ActionPending:        func(string) (bool, error) { return false, nil },
+			Abandon: func(*Expiry, service.DIDCommMsgMap) error {
+				close(done)
+
+				return nil
+			},
+		}, store, messenger)
+		timeouts.SetReaperInterval(time.Millisecond)
+
+		msg := service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: pingMsgType})
+		msg["~timing"] = &decorator.Timing{ExpiresTime: time.Now().Add(-time.Minute)}
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		require.NoError(t, timeouts.Save(&Action{PIID: msg.ID(), Msg: msg, MyDID: Alice, TheirDID: Bob}, statePingSent))
+
+		expectProblemReport(messenger, msg.ID())
+
+		timeouts.Start()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
+	t.Run("protocol instance does not expire by default", func(t *testing.T) {
+		p := newPingTimeouts(t, nil)
+
+		p.sendPing(t, nil)
+
+		p.now = p.now.Add(24 * time.Hour)
+		require.NoError(t, p.AbandonExpired())
+		require.Empty(t, p.abandoned)
+
+		threads, err := p.ThreadStates()
+		require.NoError(t, err)
+		require.Len(t, threads, 1)
+		require.Nil(t, threads[0].ExpiresTime)
+	})
+
+	t.Run("terminal states do not expire", func(t *testing.T) {
+		p := newPingTimeouts(t, nil)
+		p.SetTimeout(time.Hour)
+
+		msg := service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: pingMsgType})
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		require.NoError(t, p.Save(&Action{PIID: msg.ID(), Msg: msg}, stateDone))
+
+		expiries, err := p.Expiries()
+		require.NoError(t, err)
+		require.Empty(t, expiries)
+	})
+
+	t.Run("does not abandon the protocol instance waiting for the action", func(t *testing.T) {
+		p := newPingTimeouts(t, nil)
+		p.SetTimeout(time.Hour)
+
+		piid := p.sendPing(t, nil)
+		p.pending[piid] = true
+
+		p.now = p.now.Add(2 * time.Hour)
+		require.NoError(t, p.AbandonExpired())
+		require.Empty(t, p.abandoned)
+	})
+
+	t.Run("forgets the expiry of the protocol instance which moved on", func(t *testing.T) {
+		p := newPingTimeouts(t, nil)
+		p.SetTimeout(time.Hour)
+
+		piid := p.sendPing(t, nil)
+		p.states[piid] = statePongSent
+
+		p.now = p.now.Add(2 * time.Hour)
+		require.NoError(t, p.AbandonExpired())
+		require.Empty(t, p.abandoned)
+
+		expiries, err := p.Expiries()
+		require.NoError(t, err)
+		require.Empty(t, expiries)
+	})
+
+	t.Run("send problem report error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		p := newPingTimeouts(t, messenger)
+		p.SetTimeout(time.Hour)
+
+		piid := p.sendPing(t, nil)
+
+		expiries, err := p.Expiries()
+		require.NoError(t, err)
+
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+		err = p.Abandon(expiries[piid])
+		require.EqualError(t, err, "send problem report: error")
+		require.Empty(t, p.abandoned)
+	})
+}
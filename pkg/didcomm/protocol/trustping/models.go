@@ -0,0 +1,29 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Ping message is sent to test the connection.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0048-trust-ping#messages
+type Ping struct {
+	Type    string `json:"@type,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// ResponseRequested defaults to true when not set.
+	ResponseRequested *bool `json:"response_requested,omitempty"`
+}
+
+// PingResponse message is sent back when the ping requests a response.
+type PingResponse struct {
+	Type    string            `json:"@type,omitempty"`
+	ID      string            `json:"@id,omitempty"`
+	Thread  *decorator.Thread `json:"~thread,omitempty"`
+	Comment string            `json:"comment,omitempty"`
+}
@@ -0,0 +1,344 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+var logger = log.New("aries-framework/trustping/service")
+
+const (
+	// TrustPing defines the protocol name.
+	TrustPing = "trustping"
+	// PIURI is the trust ping protocol identifier URI.
+	PIURI = "https://didcomm.org/trust_ping/1.0"
+	// PingMsgType defines the protocol ping message type.
+	PingMsgType = PIURI + "/ping"
+	// PingResponseMsgType defines the protocol ping response message type.
+	PingResponseMsgType = PIURI + "/ping_response"
+)
+
+// States of the protocol reported by the message events.
+const (
+	// StatePingReceived the ping was received (and answered if requested).
+	StatePingReceived = "ping-received"
+	// StatePingResponseReceived the ping response was received.
+	StatePingResponseReceived = "ping-response-received"
+)
+
+const pingTimeout = 10 * time.Second
+
+// ErrConnectionNotFound connection not found error.
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// Provider contains dependencies for the trust ping protocol and is typically created by using aries.Context().
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// ClientOption configures the trust ping client.
+type ClientOption func(opts *ClientOptions)
+
+// ClientOptions holds options for the trust ping client.
+type ClientOptions struct {
+	Timeout time.Duration
+}
+
+// Result contains the outcome of the ping.
+type Result struct {
+	// RoundTripTime is the time elapsed between sending the ping and receiving the response.
+	RoundTripTime time.Duration
+	// LastSeen is the time the response was received.
+	LastSeen time.Time
+}
+
+type connections interface {
+	GetConnectionIDByDIDs(string, string) (string, error)
+	GetConnectionRecord(string) (*connection.Record, error)
+	GetConnectionHealth(string) (*connection.Health, error)
+	SaveConnectionHealth(*connection.Health) error
+}
+
+type eventProps struct {
+	connectionID string
+}
+
+func (e *eventProps) All() map[string]interface{} {
+	return map[string]interface{}{
+		"connectionID": e.connectionID,
+	}
+}
+
+// Service for the trust ping protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0048-trust-ping
+type Service struct {
+	service.Action
+	service.Message
+	messenger       service.Messenger
+	connectionStore connections
+	now             func() time.Time
+	responseMap     map[string]chan time.Time
+	responseMapLock sync.RWMutex
+	healthLock      sync.Mutex
+}
+
+// New returns the trust ping service.
+func New(p Provider) (*Service, error) {
+	connectionStore, err := connection.NewRecorder(p)
+	if err != nil {
+		return nil, fmt.Errorf("new connection recorder: %w", err)
+	}
+
+	return &Service{
+		messenger:       p.Messenger(),
+		connectionStore: connectionStore,
+		now:             time.Now,
+		responseMap:     make(map[string]chan time.Time),
+	}, nil
+}
+
+// HandleInbound handles inbound trust ping messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	logger.WithFields(service.LogFields(msg)).Debugf("service.HandleInbound() myDID=%s theirDID=%s", myDID, theirDID)
+
+	received := s.now()
+
+	var (
+		state string
+		err   error
+	)
+
+	switch msg.Type() {
+	case PingMsgType:
+		state, err = StatePingReceived, s.handlePing(msg)
+	case PingResponseMsgType:
+		state, err = StatePingResponseReceived, s.handlePingResponse(msg, received)
+	default:
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	connectionID, err := s.connectionStore.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		// the message is answered even though the connection is unknown (e.g connectionless ping)
+		logger.WithFields(service.LogFields(msg)).Warnf("connectionID lookup using DIDs: %s", err)
+	} else if err = s.updateHealth(connectionID, received, nil); err != nil {
+		logger.WithFields(service.LogFields(msg)).Errorf("update connection health: %s", err)
+	}
+
+	s.sendMsgEvent(msg, state, connectionID)
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound sends the ping message. Responses are delivered as message events.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if msg.Type() != PingMsgType {
+		return "", fmt.Errorf("invalid or unsupported outbound message type %s", msg.Type())
+	}
+
+	msgMap := msg.Clone()
+
+	if err := s.messenger.Send(msgMap, myDID, theirDID); err != nil {
+		return "", fmt.Errorf("send ping: %w", err)
+	}
+
+	return msgMap.ID(), nil
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == PingMsgType || msgType == PingResponseMsgType
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return TrustPing
+}
+
+// Protocols returns the protocols handled by the service.
+func (s *Service) Protocols() []string {
+	return []string{PIURI}
+}
+
+// Ping sends the ping to the agent on the other end of the connection identified by connectionID and
+// waits for the response. The round-trip time and the time the response was received are recorded
+// as the health of the connection. This method blocks until the response is received or it times out.
+func (s *Service) Ping(connectionID string, options ...ClientOption) (*Result, error) {
+	record, err := s.getConnection(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection: %w", err)
+	}
+
+	opts := parseClientOpts(options...)
+
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	responseCh := make(chan time.Time, 1)
+	s.setResponseCh(msgID, responseCh)
+
+	// remove the channel once its been processed
+	defer s.setResponseCh(msgID, nil)
+
+	responseRequested := true
+	sent := s.now()
+
+	err = s.messenger.Send(service.NewDIDCommMsgMap(&Ping{
+		Type:              PingMsgType,
+		ID:                msgID,
+		ResponseRequested: &responseRequested,
+	}), record.MyDID, record.TheirDID)
+	if err != nil {
+		return nil, fmt.Errorf("send ping: %w", err)
+	}
+
+	select {
+	case received := <-responseCh:
+		result := &Result{RoundTripTime: received.Sub(sent), LastSeen: received}
+
+		if err := s.updateHealth(connectionID, received, &result.RoundTripTime); err != nil {
+			return nil, fmt.Errorf("update connection health: %w", err)
+		}
+
+		return result, nil
+	case <-time.After(opts.Timeout):
+		return nil, errors.New("timeout waiting for ping response")
+	}
+}
+
+func (s *Service) handlePing(msg service.DIDCommMsg) error {
+	ping := &Ping{}
+
+	if err := msg.Decode(ping); err != nil {
+		return fmt.Errorf("ping message unmarshal: %w", err)
+	}
+
+	if ping.ResponseRequested != nil && !*ping.ResponseRequested {
+		return nil
+	}
+
+	return s.messenger.ReplyTo(msg.ID(), service.NewDIDCommMsgMap(&PingResponse{
+		Type: PingResponseMsgType,
+		ID:   uuid.New().String(),
+	}))
+}
+
+func (s *Service) handlePingResponse(msg service.DIDCommMsg, received time.Time) error {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("threadID: %w", err)
+	}
+
+	// check if there are any channels registered for the thread ID
+	if responseCh := s.getResponseCh(thID); responseCh != nil {
+		select {
+		case responseCh <- received:
+		default:
+			logger.WithFields(service.LogFields(msg)).Warnf("ignoring duplicate ping response")
+		}
+	}
+
+	return nil
+}
+
+// updateHealth records the health of the connection, the round-trip time is kept if rtt is nil.
+// The health is the only data written by the service, the lock keeps the concurrent pings consistent.
+func (s *Service) updateHealth(connectionID string, lastSeen time.Time, rtt *time.Duration) error {
+	s.healthLock.Lock()
+	defer s.healthLock.Unlock()
+
+	health, err := s.connectionStore.GetConnectionHealth(connectionID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		health = &connection.Health{ConnectionID: connectionID}
+	} else if err != nil {
+		return err
+	}
+
+	if health.LastSeen == nil || health.LastSeen.Before(lastSeen) {
+		health.LastSeen = &lastSeen
+	}
+
+	if rtt != nil {
+		health.RoundTripTime = *rtt
+	}
+
+	return s.connectionStore.SaveConnectionHealth(health)
+}
+
+func (s *Service) sendMsgEvent(msg service.DIDCommMsg, state, connectionID string) {
+	stateMsg := service.StateMsg{
+		ProtocolName: TrustPing,
+		Type:         service.PostState,
+		StateID:      state,
+		Msg:          msg,
+		Properties:   &eventProps{connectionID: connectionID},
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
+
+func (s *Service) getResponseCh(thID string) chan time.Time {
+	s.responseMapLock.RLock()
+	defer s.responseMapLock.RUnlock()
+
+	return s.responseMap[thID]
+}
+
+func (s *Service) setResponseCh(thID string, responseCh chan time.Time) {
+	s.responseMapLock.Lock()
+	defer s.responseMapLock.Unlock()
+
+	if responseCh == nil {
+		delete(s.responseMap, thID)
+	} else {
+		s.responseMap[thID] = responseCh
+	}
+}
+
+func parseClientOpts(options ...ClientOption) *ClientOptions {
+	opts := &ClientOptions{
+		Timeout: pingTimeout,
+	}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
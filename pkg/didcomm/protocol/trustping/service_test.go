@@ -0,0 +1,336 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+		require.Equal(t, TrustPing, svc.Name())
+		require.Equal(t, []string{PIURI}, svc.Protocols())
+	})
+
+	t.Run("test new service - connection recorder error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.storageProvider = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection recorder")
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	svc, err := New(newProvider(nil))
+	require.NoError(t, err)
+
+	require.True(t, svc.Accept(PingMsgType))
+	require.True(t, svc.Accept(PingResponseMsgType))
+	require.False(t, svc.Accept("unsupported"))
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("ping - success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		messenger.EXPECT().ReplyTo("ping-id", gomock.Any()).
+			Do(func(_ string, msg service.DIDCommMsgMap) error {
+				require.Equal(t, PingResponseMsgType, msg.Type())
+
+				return nil
+			})
+
+		msgID, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{
+			Type: PingMsgType,
+			ID:   "ping-id",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "ping-id", msgID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, TrustPing, event.ProtocolName)
+			require.Equal(t, service.PostState, event.Type)
+			require.Equal(t, StatePingReceived, event.StateID)
+			require.Equal(t, "conn-id", event.Properties.All()["connectionID"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the message event")
+		}
+
+		health, err := svc.connectionStore.GetConnectionHealth("conn-id")
+		require.NoError(t, err)
+		require.NotNil(t, health.LastSeen)
+
+		// the pings don't touch the connection record
+		record, err := svc.connectionStore.GetConnectionRecord("conn-id")
+		require.NoError(t, err)
+		require.Equal(t, record.CreatedTime, record.UpdatedTime)
+	})
+
+	t.Run("ping - response not requested", func(t *testing.T) {
+		svc, err := New(newProvider(serviceMocks.NewMockMessenger(ctrl)))
+		require.NoError(t, err)
+
+		responseRequested := false
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{
+			Type:              PingMsgType,
+			ID:                "ping-id",
+			ResponseRequested: &responseRequested,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("ping - reply error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo(gomock.Any(), gomock.Any()).Return(errors.New("reply error"))
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{
+			Type: PingMsgType,
+			ID:   "ping-id",
+		}), MYDID, THEIRDID)
+		require.EqualError(t, err, "reply error")
+	})
+
+	t.Run("ping - decode error", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type":              PingMsgType,
+			"@id":                "ping-id",
+			"response_requested": "invalid",
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ping message unmarshal")
+	})
+
+	t.Run("ping response - no waiting ping", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type":   PingResponseMsgType,
+			"@id":     "response-id",
+			"~thread": map[string]interface{}{"thid": "ping-id"},
+		}, MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		event := <-events
+		require.Equal(t, StatePingResponseReceived, event.StateID)
+		require.Empty(t, event.Properties.All()["connectionID"])
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{Type: "unsupported"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unsupported message type unsupported")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		msgID, err := svc.HandleOutbound(service.NewDIDCommMsgMap(&Ping{
+			Type: PingMsgType,
+			ID:   "ping-id",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "ping-id", msgID)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		svc, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&Ping{Type: PingMsgType}), MYDID, THEIRDID)
+		require.EqualError(t, err, "send ping: send error")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(&PingResponse{Type: PingResponseMsgType}), MYDID, THEIRDID)
+		require.EqualError(t, err, "invalid or unsupported outbound message type "+PingResponseMsgType)
+	})
+}
+
+func TestService_Ping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		sent := time.Now()
+		svc.now = func() time.Time { return sent }
+
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) error {
+				ping := &Ping{}
+				require.NoError(t, msg.Decode(ping))
+				require.Equal(t, PingMsgType, ping.Type)
+				require.True(t, *ping.ResponseRequested)
+
+				svc.now = func() time.Time { return sent.Add(time.Second) }
+
+				go func() {
+					_, err := svc.HandleInbound(service.DIDCommMsgMap{
+						"@type":   PingResponseMsgType,
+						"@id":     "response-id",
+						"~thread": map[string]interface{}{"thid": ping.ID},
+					}, MYDID, THEIRDID)
+					require.NoError(t, err)
+				}()
+
+				return nil
+			})
+
+		result, err := svc.Ping("conn-id")
+		require.NoError(t, err)
+		require.Equal(t, time.Second, result.RoundTripTime)
+		require.True(t, sent.Add(time.Second).Equal(result.LastSeen))
+
+		health, err := svc.connectionStore.GetConnectionHealth("conn-id")
+		require.NoError(t, err)
+		require.Equal(t, time.Second, health.RoundTripTime)
+		require.True(t, sent.Add(time.Second).Equal(*health.LastSeen))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(nil)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.Ping("conn-id", func(opts *ClientOptions) {
+			opts.Timeout = 10 * time.Millisecond
+		})
+		require.EqualError(t, err, "timeout waiting for ping response")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), MYDID, THEIRDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov)
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = svc.Ping("conn-id")
+		require.EqualError(t, err, "send ping: send error")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = svc.Ping("conn-id")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+}
+
+func saveConnection(t *testing.T, prov *provider) {
+	t.Helper()
+
+	r, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "conn-id", MyDID: MYDID, TheirDID: THEIRDID, State: "completed",
+	}))
+}
+
+func newProvider(messenger service.Messenger) *provider {
+	return &provider{
+		messenger:            messenger,
+		storageProvider:      mockstore.NewMockStoreProvider(),
+		protocolStateStorage: mockstore.NewMockStoreProvider(),
+	}
+}
+
+type provider struct {
+	messenger            service.Messenger
+	storageProvider      storage.Provider
+	protocolStateStorage storage.Provider
+}
+
+func (p *provider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+func (p *provider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *provider) ProtocolStateStorageProvider() storage.Provider {
+	return p.protocolStateStorage
+}
@@ -28,24 +28,165 @@ const (
 	descriptorMapProperty = "descriptor_map"
 )
 
+// Selection can be "all" or "pick".
+type Selection string
+
+const (
+	// All rule's value.
+	All Selection = "all"
+	// Pick rule's value.
+	Pick Selection = "pick"
+)
+
+// Preference can be "required" or "preferred".
+type Preference string
+
+const (
+	// Required predicate's value.
+	Required Preference = "required"
+	// Preferred predicate's value.
+	Preferred Preference = "preferred"
+)
+
 // PresentationDefinitions presentation definitions (https://identity.foundation/presentation-exchange/).
 type PresentationDefinitions struct {
-	Name             string             `json:"name"`
-	Purpose          string             `json:"purpose"`
-	InputDescriptors []*InputDescriptor `json:"input_descriptors,omitempty"`
+	ID                     string                   `json:"id,omitempty"`
+	Name                   string                   `json:"name,omitempty"`
+	Purpose                string                   `json:"purpose,omitempty"`
+	Locale                 string                   `json:"locale,omitempty"`
+	Format                 *Format                  `json:"format,omitempty"`
+	SubmissionRequirements []*SubmissionRequirement `json:"submission_requirements,omitempty"`
+	InputDescriptors       []*InputDescriptor       `json:"input_descriptors,omitempty"`
+}
+
+// SubmissionRequirement describes input that must be submitted via a Presentation Submission
+// to satisfy Verifier demands.
+type SubmissionRequirement struct {
+	Name       string                   `json:"name,omitempty"`
+	Purpose    string                   `json:"purpose,omitempty"`
+	Rule       Selection                `json:"rule,omitempty"`
+	Count      int                      `json:"count,omitempty"`
+	Min        int                      `json:"min,omitempty"`
+	Max        int                      `json:"max,omitempty"`
+	From       string                   `json:"from,omitempty"`
+	FromNested []*SubmissionRequirement `json:"from_nested,omitempty"`
 }
 
 // InputDescriptor input descriptors.
 type InputDescriptor struct {
-	ID     string  `json:"id,omitempty"`
-	Schema *Schema `json:"schema,omitempty"`
+	ID          string                 `json:"id,omitempty"`
+	Group       []string               `json:"group,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Purpose     string                 `json:"purpose,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Schema      []*Schema              `json:"schema,omitempty"`
+	Constraints *Constraints           `json:"constraints,omitempty"`
+}
+
+// UnmarshalJSON unmarshals the input descriptor from JSON, the schema is accepted as an object or an array.
+func (d *InputDescriptor) UnmarshalJSON(data []byte) error {
+	type Alias InputDescriptor
+
+	raw := struct {
+		*Alias
+		Schema json.RawMessage `json:"schema,omitempty"`
+	}{Alias: (*Alias)(d)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	d.Schema = nil
+
+	if len(raw.Schema) == 0 {
+		return nil
+	}
+
+	var schema *Schema
+
+	if err := json.Unmarshal(raw.Schema, &schema); err == nil {
+		// as object
+		if schema != nil {
+			d.Schema = []*Schema{schema}
+		}
+
+		return nil
+	}
+
+	// as array
+	if err := json.Unmarshal(raw.Schema, &d.Schema); err != nil {
+		return fmt.Errorf("unmarshal input descriptor schema: %w", err)
+	}
+
+	return nil
 }
 
 // Schema input descriptor schema.
 type Schema struct {
-	URI     string `json:"uri,omitempty"`
-	Name    string `json:"name,omitempty"`
-	Purpose string `json:"purpose,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Purpose  string `json:"purpose,omitempty"`
+}
+
+// Constraints describes InputDescriptor's Constraints field.
+type Constraints struct {
+	LimitDisclosure bool          `json:"limit_disclosure,omitempty"`
+	SubjectIsIssuer *Preference   `json:"subject_is_issuer,omitempty"`
+	IsHolder        []*HolderRule `json:"is_holder,omitempty"`
+	SameSubject     []*HolderRule `json:"same_subject,omitempty"`
+	Fields          []*Field      `json:"fields,omitempty"`
+}
+
+// HolderRule describes the is_holder and same_subject constraints.
+type HolderRule struct {
+	FieldID   []string    `json:"field_id,omitempty"`
+	Directive *Preference `json:"directive,omitempty"`
+}
+
+// Field describes Constraints's Fields field.
+type Field struct {
+	ID        string      `json:"id,omitempty"`
+	Path      []string    `json:"path,omitempty"`
+	Purpose   string      `json:"purpose,omitempty"`
+	Filter    *Filter     `json:"filter,omitempty"`
+	Predicate *Preference `json:"predicate,omitempty"`
+}
+
+// Filter is the JSON Schema the field value is evaluated against.
+type Filter struct {
+	Type             string        `json:"type,omitempty"`
+	Format           string        `json:"format,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	Minimum          interface{}   `json:"minimum,omitempty"`
+	Maximum          interface{}   `json:"maximum,omitempty"`
+	ExclusiveMinimum interface{}   `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum interface{}   `json:"exclusiveMaximum,omitempty"`
+	MinLength        int           `json:"minLength,omitempty"`
+	MaxLength        int           `json:"maxLength,omitempty"`
+	Const            interface{}   `json:"const,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty"`
+	Not              *Filter       `json:"not,omitempty"`
+}
+
+// Format describes PresentationDefinitions's Format field.
+type Format struct {
+	Jwt   *JwtType `json:"jwt,omitempty"`
+	JwtVC *JwtType `json:"jwt_vc,omitempty"`
+	JwtVP *JwtType `json:"jwt_vp,omitempty"`
+	Ldp   *LdpType `json:"ldp,omitempty"`
+	LdpVC *LdpType `json:"ldp_vc,omitempty"`
+	LdpVP *LdpType `json:"ldp_vp,omitempty"`
+}
+
+// JwtType contains alg.
+type JwtType struct {
+	Alg []string `json:"alg,omitempty"`
+}
+
+// LdpType contains proof_type.
+type LdpType struct {
+	ProofType []string `json:"proof_type,omitempty"`
 }
 
 // PresentationSubmission is the container for the descriptor_map:
@@ -63,6 +204,7 @@ type InputDescriptorMapping struct {
 // MatchOptions is a holder of options that can set when matching a submission against definitions.
 type MatchOptions struct {
 	JSONLDDocumentLoader ld.DocumentLoader
+	CredentialOptions    []verifiable.CredentialOpt
 }
 
 // MatchOption is an option that sets an option for when matching.
@@ -75,6 +217,14 @@ func WithJSONLDDocumentLoader(l ld.DocumentLoader) MatchOption {
 	}
 }
 
+// WithCredentialOptions sets the options to use when parsing the embedded verifiable credentials
+// (e.g public key fetcher to verify their proofs).
+func WithCredentialOptions(options ...verifiable.CredentialOpt) MatchOption {
+	return func(m *MatchOptions) {
+		m.CredentialOptions = append(m.CredentialOptions, options...)
+	}
+}
+
 // Match returns the credentials matched against the InputDescriptors ids.
 func (p *PresentationDefinitions) Match(vp *verifiable.Presentation, // nolint:gocyclo,funlen
 	options ...MatchOption) (map[string]*verifiable.Credential, error) {
@@ -110,6 +260,8 @@ func (p *PresentationDefinitions) Match(vp *verifiable.Presentation, // nolint:g
 
 	builder := gval.Full(jsonpath.PlaceholderExtension())
 	result := make(map[string]*verifiable.Credential)
+	// subject IDs of the credentials keyed by the field ID
+	subjects := make(map[string][]string)
 
 	for i := range descriptorMap {
 		mapping := descriptorMap[i]
@@ -121,7 +273,7 @@ func (p *PresentationDefinitions) Match(vp *verifiable.Presentation, // nolint:g
 				descriptorMapProperty, mapping.ID)
 		}
 
-		vc, selectErr := selectByPath(builder, typelessVP, mapping.Path, opts.JSONLDDocumentLoader)
+		raw, vc, selectErr := selectByPath(builder, typelessVP, mapping.Path, opts)
 		if selectErr != nil {
 			return nil, fmt.Errorf("failed to select vc from submission: %w", selectErr)
 		}
@@ -129,18 +281,36 @@ func (p *PresentationDefinitions) Match(vp *verifiable.Presentation, // nolint:g
 		inputDescriptor := p.inputDescriptor(mapping.ID)
 
 		// The schema of the candidate input must match one of the Input Descriptor schema object uri values exactly.
-		if !stringsContain(vc.Context, inputDescriptor.Schema.URI) {
+		if !matchSchema(inputDescriptor.Schema, vc) {
 			return nil, fmt.Errorf(
-				"input descriptor id [%s] requires schema uri [%s] which is not in vc context [%+v]",
-				inputDescriptor.ID, inputDescriptor.Schema.URI, vc.Types,
+				"input descriptor id [%s] requires schema uri %v which is not in vc types %v or schemas %v",
+				inputDescriptor.ID, schemaURIs(inputDescriptor.Schema), vc.Types, credentialSchemaIDs(vc),
 			)
 		}
 
-		// TODO add support for constraints: https://github.com/hyperledger/aries-framework-go/issues/2108
+		err = p.Format.check(raw, vc)
+		if err != nil {
+			return nil, fmt.Errorf("input descriptor id [%s]: format: %w", inputDescriptor.ID, err)
+		}
+
+		err = inputDescriptor.Constraints.check(builder, vc, subjects)
+		if err != nil {
+			return nil, fmt.Errorf("input descriptor id [%s]: constraints: %w", inputDescriptor.ID, err)
+		}
+
+		err = inputDescriptor.Constraints.checkHolder(vc, vp.Holder)
+		if err != nil {
+			return nil, fmt.Errorf("input descriptor id [%s]: constraints: %w", inputDescriptor.ID, err)
+		}
 
 		result[mapping.ID] = vc
 	}
 
+	err = p.checkSameSubject(result, subjects)
+	if err != nil {
+		return nil, fmt.Errorf("failed same subject: %w", err)
+	}
+
 	err = p.evalSubmissionRequirements(result)
 	if err != nil {
 		return nil, fmt.Errorf("failed submission requirements: %w", err)
@@ -149,15 +319,112 @@ func (p *PresentationDefinitions) Match(vp *verifiable.Presentation, // nolint:g
 	return result, nil
 }
 
+// MatchCredential checks whether the credential can be submitted for the input descriptor.
+// The schema and the constraints of the input descriptor are evaluated, except the constraints which depend on
+// the presentation (is_holder, same_subject) and the format of the submission.
+func (p *PresentationDefinitions) MatchCredential(descriptorID string, vc *verifiable.Credential) error {
+	inputDescriptor := p.inputDescriptor(descriptorID)
+	if inputDescriptor == nil {
+		return fmt.Errorf("input descriptor %s not found", descriptorID)
+	}
+
+	if !matchSchema(inputDescriptor.Schema, vc) {
+		return fmt.Errorf("schema uri %v is not in vc types %v or schemas %v",
+			schemaURIs(inputDescriptor.Schema), vc.Types, credentialSchemaIDs(vc))
+	}
+
+	builder := gval.Full(jsonpath.PlaceholderExtension())
+
+	return inputDescriptor.Constraints.check(builder, vc, make(map[string][]string))
+}
+
+// CreateVP creates the verifiable presentation which contains the given credentials along with
+// the presentation submission. The credentials are keyed by the input descriptor ID and must meet
+// the submission requirements (see SelectCredentials).
+func (p *PresentationDefinitions) CreateVP(credentials map[string]*verifiable.Credential) (
+	*verifiable.Presentation, error) {
+	for id, vc := range credentials {
+		if p.inputDescriptor(id) == nil {
+			return nil, fmt.Errorf("input descriptor %s not found", id)
+		}
+
+		if vc == nil {
+			return nil, fmt.Errorf("no credential for input descriptor %s", id)
+		}
+	}
+
+	if err := p.evalSubmissionRequirements(credentials); err != nil {
+		return nil, err
+	}
+
+	submission := &PresentationSubmission{}
+
+	var vcs []interface{}
+
+	// the credentials are submitted in the order of the input descriptors
+	for _, descriptor := range p.InputDescriptors {
+		vc, ok := credentials[descriptor.ID]
+		if !ok {
+			continue
+		}
+
+		submission.DescriptorMap = append(submission.DescriptorMap, &InputDescriptorMapping{
+			ID:   descriptor.ID,
+			Path: fmt.Sprintf("$.verifiableCredential[%d]", len(vcs)),
+		})
+		vcs = append(vcs, vc)
+	}
+
+	vp := &verifiable.Presentation{
+		Context: []string{
+			"https://www.w3.org/2018/credentials/v1",
+			PresentationSubmissionJSONLDContext,
+		},
+		Type: []string{
+			"VerifiablePresentation",
+			PresentationSubmissionJSONLDType,
+		},
+	}
+
+	if err := vp.SetCredentials(vcs...); err != nil {
+		return nil, fmt.Errorf("set credentials: %w", err)
+	}
+
+	raw, err := json.Marshal(submission)
+	if err != nil {
+		return nil, fmt.Errorf("marshal submission: %w", err)
+	}
+
+	var submissionMap map[string]interface{}
+
+	if err = json.Unmarshal(raw, &submissionMap); err != nil {
+		return nil, fmt.Errorf("unmarshal submission: %w", err)
+	}
+
+	vp.CustomFields = verifiable.CustomFields{submissionProperty: submissionMap}
+
+	return vp, nil
+}
+
 // Ensures the matched credentials meet the submission requirements.
+// If there are no submission requirements every input descriptor must be satisfied.
 func (p *PresentationDefinitions) evalSubmissionRequirements(matched map[string]*verifiable.Credential) error {
-	// TODO support submission requirement rules: https://github.com/hyperledger/aries-framework-go/issues/2109
-	descriptorIDs := descriptorIDs(p.InputDescriptors)
+	if len(p.SubmissionRequirements) == 0 {
+		descriptorIDs := descriptorIDs(p.InputDescriptors)
+
+		for i := range descriptorIDs {
+			_, found := matched[descriptorIDs[i]]
+			if !found {
+				return fmt.Errorf("no credential provided for input descriptor %s", descriptorIDs[i])
+			}
+		}
 
-	for i := range descriptorIDs {
-		_, found := matched[descriptorIDs[i]]
-		if !found {
-			return fmt.Errorf("no credential provided for input descriptor %s", descriptorIDs[i])
+		return nil
+	}
+
+	for _, requirement := range p.SubmissionRequirements {
+		if err := p.evalSubmissionRequirement(requirement, matched); err != nil {
+			return fmt.Errorf("submission requirement %q: %w", requirement.Name, err)
 		}
 	}
 
@@ -226,34 +493,41 @@ func descriptorIDs(input []*InputDescriptor) []string {
 // string expression that selects the credential to be submit in relation to the identified Input Descriptor
 // identified, when executed against the top-level of the object the Presentation Submission is embedded within.
 func selectByPath(builder gval.Language, vp interface{}, jsonPath string,
-	loader ld.DocumentLoader) (*verifiable.Credential, error) {
+	opts *MatchOptions) (interface{}, *verifiable.Credential, error) {
 	path, err := builder.NewEvaluable(jsonPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build new json path evaluator: %w", err)
+		return nil, nil, fmt.Errorf("failed to build new json path evaluator: %w", err)
 	}
 
 	cred, err := path(context.TODO(), vp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to evaluate json path [%s]: %w", jsonPath, err)
+		return nil, nil, fmt.Errorf("failed to evaluate json path [%s]: %w", jsonPath, err)
 	}
 
 	credBits, err := json.Marshal(cred)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal credential: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal credential: %w", err)
 	}
 
-	vcOpts := make([]verifiable.CredentialOpt, 0)
+	// JWT credential
+	if jwt, ok := cred.(string); ok {
+		credBits = []byte(jwt)
+	}
 
-	if loader != nil {
-		vcOpts = append(vcOpts, verifiable.WithJSONLDDocumentLoader(loader))
+	vcOpts := make([]verifiable.CredentialOpt, 0, len(opts.CredentialOptions)+1)
+
+	if opts.JSONLDDocumentLoader != nil {
+		vcOpts = append(vcOpts, verifiable.WithJSONLDDocumentLoader(opts.JSONLDDocumentLoader))
 	}
 
+	vcOpts = append(vcOpts, opts.CredentialOptions...)
+
 	vc, err := verifiable.ParseCredential(credBits, vcOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse credential: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse credential: %w", err)
 	}
 
-	return vc, nil
+	return cred, vc, nil
 }
 
 func stringsContain(s []string, val string) bool {
@@ -28,9 +28,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -48,14 +48,50 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		require.Equal(t, expected.ID, result.ID)
 	})
 
+	t.Run("match credential with credential options", func(t *testing.T) {
+		uri := randomURI()
+		expected := newVC([]string{uri})
+		expected.Proofs = []verifiable.Proof{{
+			"type":               "Ed25519Signature2018",
+			"created":            "2020-01-01T19:23:24Z",
+			"proofPurpose":       "assertionMethod",
+			"verificationMethod": "did:example:123#key-1",
+			"jws":                "eyJhbGciOiJFZERTQSJ9..c2lnbmF0dXJl",
+		}}
+		defs := &PresentationDefinitions{
+			InputDescriptors: []*InputDescriptor{{
+				ID: uuid.New().String(),
+				Schema: []*Schema{{
+					URI: uri,
+				}},
+			}},
+		}
+		vp := newVP(t,
+			&PresentationSubmission{DescriptorMap: []*InputDescriptorMapping{{
+				ID:   defs.InputDescriptors[0].ID,
+				Path: "$.verifiableCredential[0]",
+			}}},
+			expected,
+		)
+
+		_, err := defs.Match(vp, WithJSONLDDocumentLoader(jsonldContextLoader(t, uri)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key fetcher is not defined")
+
+		matched, err := defs.Match(vp, WithJSONLDDocumentLoader(jsonldContextLoader(t, uri)),
+			WithCredentialOptions(verifiable.WithDisabledProofCheck()))
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+	})
+
 	t.Run("error if vp does not have the right context", func(t *testing.T) {
 		uri := randomURI()
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -78,9 +114,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -103,9 +139,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -124,9 +160,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -144,9 +180,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -164,9 +200,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -190,15 +226,15 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 			InputDescriptors: []*InputDescriptor{
 				{
 					ID: uuid.New().String(),
-					Schema: &Schema{
+					Schema: []*Schema{{
 						URI: uriOne,
-					},
+					}},
 				},
 				{
 					ID: uuid.New().String(),
-					Schema: &Schema{
+					Schema: []*Schema{{
 						URI: uriTwo,
-					},
+					}},
 				},
 			},
 		}
@@ -218,9 +254,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -236,9 +272,9 @@ func TestPresentationDefinitions_Match(t *testing.T) {
 		defs := &PresentationDefinitions{
 			InputDescriptors: []*InputDescriptor{{
 				ID: uuid.New().String(),
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: uri,
-				},
+				}},
 			}},
 		}
 
@@ -255,14 +291,14 @@ func TestE2E(t *testing.T) {
 	verifierDefinitions := &PresentationDefinitions{
 		InputDescriptors: []*InputDescriptor{{
 			ID: uuid.New().String(),
-			Schema: &Schema{
+			Schema: []*Schema{{
 				URI: randomURI(),
-			},
+			}},
 		}},
 	}
 
 	// holder builds their presentation submission against the verifier's definitions
-	holderCredential := newVC([]string{verifierDefinitions.InputDescriptors[0].Schema.URI})
+	holderCredential := newVC([]string{verifierDefinitions.InputDescriptors[0].Schema[0].URI})
 	vp := newVP(t,
 		&PresentationSubmission{DescriptorMap: []*InputDescriptorMapping{{
 			ID:   verifierDefinitions.InputDescriptors[0].ID,
@@ -282,7 +318,7 @@ func TestE2E(t *testing.T) {
 	matched, err := verifierDefinitions.Match(
 		receivedVP,
 		WithJSONLDDocumentLoader(
-			jsonldContextLoader(t, verifierDefinitions.InputDescriptors[0].Schema.URI)))
+			jsonldContextLoader(t, verifierDefinitions.InputDescriptors[0].Schema[0].URI)))
 	require.NoError(t, err)
 	require.Len(t, matched, 1)
 	result, ok := matched[verifierDefinitions.InputDescriptors[0].ID]
@@ -290,6 +326,45 @@ func TestE2E(t *testing.T) {
 	require.Equal(t, holderCredential.ID, result.ID)
 }
 
+func TestInputDescriptor_UnmarshalJSON(t *testing.T) {
+	t.Run("schema object", func(t *testing.T) {
+		var descriptor InputDescriptor
+		require.NoError(t, json.Unmarshal([]byte(`{"id": "degree", "schema": {"uri": "https://example.org/degree"}}`),
+			&descriptor))
+		require.Equal(t, "degree", descriptor.ID)
+		require.Equal(t, []*Schema{{URI: "https://example.org/degree"}}, descriptor.Schema)
+	})
+
+	t.Run("schema array", func(t *testing.T) {
+		var descriptor InputDescriptor
+		require.NoError(t, json.Unmarshal([]byte(`{"id": "degree", "schema": [
+			{"uri": "https://example.org/degree"}, {"uri": "https://example.org/diploma", "required": true}
+		]}`), &descriptor))
+		require.Equal(t, []*Schema{
+			{URI: "https://example.org/degree"},
+			{URI: "https://example.org/diploma", Required: true},
+		}, descriptor.Schema)
+	})
+
+	t.Run("no schema", func(t *testing.T) {
+		var descriptor InputDescriptor
+		require.NoError(t, json.Unmarshal([]byte(`{"id": "degree", "schema": null}`), &descriptor))
+		require.Empty(t, descriptor.Schema)
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		var descriptor InputDescriptor
+		err := json.Unmarshal([]byte(`{"id": "degree", "schema": "https://example.org/degree"}`), &descriptor)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal input descriptor schema")
+	})
+
+	t.Run("invalid descriptor", func(t *testing.T) {
+		var descriptor InputDescriptor
+		require.Error(t, json.Unmarshal([]byte(`{"id": 1}`), &descriptor))
+	})
+}
+
 func newVC(context []string) *verifiable.Credential {
 	vc := &verifiable.Credential{
 		ID:      "http://test.credential.com/123",
@@ -304,8 +379,10 @@ func newVC(context []string) *verifiable.Credential {
 		},
 	}
 
+	// the test credentials are typed by the context which defines them
 	if context != nil {
 		vc.Context = append(vc.Context, context...)
+		vc.Types = append(vc.Types, context...)
 	}
 
 	return vc
@@ -385,3 +462,99 @@ func jsonldContextLoader(t *testing.T, contextURL string) *ld.CachingDocumentLoa
 
 	return loader
 }
+
+func TestPresentationDefinitions_CreateVP(t *testing.T) {
+	t.Run("create vp which matches the definitions", func(t *testing.T) {
+		uriOne := randomURI()
+		uriTwo := randomURI()
+		defs := &PresentationDefinitions{
+			InputDescriptors: []*InputDescriptor{
+				{ID: uuid.New().String(), Schema: []*Schema{{URI: uriOne}}},
+				{ID: uuid.New().String(), Schema: []*Schema{{URI: uriTwo}}},
+			},
+		}
+
+		vcOne := newVC([]string{uriOne})
+		vcTwo := newVC([]string{uriTwo})
+
+		vp, err := defs.CreateVP(map[string]*verifiable.Credential{
+			defs.InputDescriptors[0].ID: vcOne,
+			defs.InputDescriptors[1].ID: vcTwo,
+		})
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 2)
+
+		receivedVP, err := verifiable.ParseUnverifiedPresentation(marshal(t, vp))
+		require.NoError(t, err)
+
+		loader := jsonldContextLoader(t, uriOne)
+		addContext(t, loader, uriTwo)
+
+		matched, err := defs.Match(receivedVP, WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+		require.Len(t, matched, 2)
+		require.Equal(t, vcOne.ID, matched[defs.InputDescriptors[0].ID].ID)
+		require.Equal(t, vcTwo.ID, matched[defs.InputDescriptors[1].ID].ID)
+	})
+
+	t.Run("error if input descriptor has no credential", func(t *testing.T) {
+		defs := &PresentationDefinitions{
+			InputDescriptors: []*InputDescriptor{{ID: "degree", Schema: []*Schema{{URI: randomURI()}}}},
+		}
+
+		_, err := defs.CreateVP(map[string]*verifiable.Credential{})
+		require.EqualError(t, err, "no credential provided for input descriptor degree")
+
+		_, err = defs.CreateVP(map[string]*verifiable.Credential{"degree": nil})
+		require.EqualError(t, err, "no credential for input descriptor degree")
+	})
+
+	t.Run("error if input descriptor is unknown", func(t *testing.T) {
+		defs := &PresentationDefinitions{
+			InputDescriptors: []*InputDescriptor{{ID: "degree", Schema: []*Schema{{URI: randomURI()}}}},
+		}
+
+		_, err := defs.CreateVP(map[string]*verifiable.Credential{"license": newVC(nil)})
+		require.EqualError(t, err, "input descriptor license not found")
+	})
+
+	t.Run("submits the credentials picked for the submission requirements", func(t *testing.T) {
+		defs := &PresentationDefinitions{}
+		require.NoError(t, json.Unmarshal([]byte(multiGroupDefinition), defs))
+
+		vp, err := defs.CreateVP(map[string]*verifiable.Credential{
+			"banking_input_2":     newVC(nil),
+			"employment_input":    newVC(nil),
+			"citizenship_input_1": newVC(nil),
+		})
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 3)
+
+		submission, ok := vp.CustomFields[submissionProperty].(map[string]interface{})
+		require.True(t, ok)
+
+		descriptors, ok := submission["descriptor_map"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, descriptors, 3)
+		require.Equal(t, map[string]interface{}{
+			"id":   "banking_input_2",
+			"path": "$.verifiableCredential[0]",
+		}, descriptors[0])
+
+		_, err = defs.CreateVP(map[string]*verifiable.Credential{
+			"banking_input_1":     newVC(nil),
+			"banking_input_2":     newVC(nil),
+			"employment_input":    newVC(nil),
+			"citizenship_input_1": newVC(nil),
+		})
+		require.EqualError(t, err, `submission requirement "Banking Information": `+
+			"rule pick: count 1 is expected but 2 inputs are satisfied")
+	})
+}
+
+func addContext(t *testing.T, loader *ld.CachingDocumentLoader, contextURL string) {
+	reader, err := ld.DocumentFromReader(strings.NewReader(`{"@context":{"@version":1.1}}`))
+	require.NoError(t, err)
+
+	loader.AddDocument(contextURL, reader)
+}
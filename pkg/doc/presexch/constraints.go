@@ -0,0 +1,386 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presexch
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+const (
+	credentialSubjectProperty = "credentialSubject"
+	jwtParts                  = 3
+)
+
+var errFieldNotSatisfied = errors.New("no value satisfies the field")
+
+// matchSchema checks whether one of the schema uris is the type or the credential schema of the credential.
+func matchSchema(schemas []*Schema, vc *verifiable.Credential) bool {
+	if len(schemas) == 0 {
+		return true
+	}
+
+	ids := credentialSchemaIDs(vc)
+
+	for _, schema := range schemas {
+		if stringsContain(vc.Types, schema.URI) || stringsContain(ids, schema.URI) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func credentialSchemaIDs(vc *verifiable.Credential) []string {
+	ids := make([]string, len(vc.Schemas))
+
+	for i := range vc.Schemas {
+		ids[i] = vc.Schemas[i].ID
+	}
+
+	return ids
+}
+
+func schemaURIs(schemas []*Schema) []string {
+	uris := make([]string, len(schemas))
+
+	for i := range schemas {
+		uris[i] = schemas[i].URI
+	}
+
+	return uris
+}
+
+// check ensures the credential is submitted in one of the allowed formats.
+// The JWT credential is submitted as a string, otherwise the linked data proof format is expected.
+func (f *Format) check(raw interface{}, vc *verifiable.Credential) error {
+	if f == nil {
+		return nil
+	}
+
+	if jwt, ok := raw.(string); ok {
+		allowed := f.JwtVC
+		if allowed == nil {
+			allowed = f.Jwt
+		}
+
+		if allowed == nil {
+			return errors.New("jwt_vc format is not allowed")
+		}
+
+		if len(allowed.Alg) == 0 {
+			return nil
+		}
+
+		alg, err := jwtAlg(jwt)
+		if err != nil {
+			return err
+		}
+
+		if !stringsContain(allowed.Alg, alg) {
+			return fmt.Errorf("jwt alg %s is not allowed", alg)
+		}
+
+		return nil
+	}
+
+	allowed := f.LdpVC
+	if allowed == nil {
+		allowed = f.Ldp
+	}
+
+	if allowed == nil {
+		return errors.New("ldp_vc format is not allowed")
+	}
+
+	if len(allowed.ProofType) == 0 {
+		return nil
+	}
+
+	if len(vc.Proofs) == 0 {
+		return errors.New("credential has no proof")
+	}
+
+	for _, proof := range vc.Proofs {
+		// nolint: errcheck
+		proofType, _ := proof["type"].(string)
+		if !stringsContain(allowed.ProofType, proofType) {
+			return fmt.Errorf("proof type %s is not allowed", proofType)
+		}
+	}
+
+	return nil
+}
+
+func jwtAlg(jwt string) (string, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != jwtParts {
+		return "", errors.New("invalid jwt")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("decode jwt header: %w", err)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+
+	if err = json.Unmarshal(rawHeader, &header); err != nil {
+		return "", fmt.Errorf("unmarshal jwt header: %w", err)
+	}
+
+	return header.Alg, nil
+}
+
+// check ensures the credential satisfies the constraints. Subject IDs of the credential are collected
+// for every field to evaluate the same_subject constraints later.
+func (c *Constraints) check(builder gval.Language, vc *verifiable.Credential, subjects map[string][]string) error {
+	if c == nil {
+		return nil
+	}
+
+	credential, err := credentialToMap(vc)
+	if err != nil {
+		return err
+	}
+
+	ids := subjectIDs(credential)
+
+	for _, field := range c.Fields {
+		if err = field.evaluate(builder, credential); err != nil {
+			return fmt.Errorf("field %s: %w", fieldName(field), err)
+		}
+
+		if field.ID != "" {
+			subjects[field.ID] = append(subjects[field.ID], ids...)
+		}
+	}
+
+	if isRequired(c.SubjectIsIssuer) {
+		for _, subjectID := range ids {
+			if subjectID != vc.Issuer.ID {
+				return fmt.Errorf("subject %s is not the issuer", subjectID)
+			}
+		}
+	}
+
+	if c.LimitDisclosure {
+		return limitDisclosure(credential, c.Fields)
+	}
+
+	return nil
+}
+
+// checkHolder ensures the subject of the credential is the holder of the presentation (is_holder constraints).
+func (c *Constraints) checkHolder(vc *verifiable.Credential, holder string) error {
+	if c == nil {
+		return nil
+	}
+
+	for _, rule := range c.IsHolder {
+		if !isRequired(rule.Directive) {
+			continue
+		}
+
+		credential, err := credentialToMap(vc)
+		if err != nil {
+			return err
+		}
+
+		for _, subjectID := range subjectIDs(credential) {
+			if subjectID != holder {
+				return fmt.Errorf("subject %s is not the holder", subjectID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func fieldName(field *Field) string {
+	if field.ID != "" {
+		return field.ID
+	}
+
+	return fmt.Sprintf("%v", field.Path)
+}
+
+func isRequired(p *Preference) bool {
+	return p != nil && *p == Required
+}
+
+// evaluate applies the paths in order, the first value which satisfies the filter satisfies the field.
+func (f *Field) evaluate(builder gval.Language, credential interface{}) error {
+	for _, path := range f.Path {
+		eval, err := builder.NewEvaluable(path)
+		if err != nil {
+			return fmt.Errorf("failed to build new json path evaluator: %w", err)
+		}
+
+		value, err := eval(context.TODO(), credential)
+		if err != nil {
+			// the path does not match, the next one should be tried
+			continue
+		}
+
+		// the predicate feature allows the Holder to submit the result of the filter instead of the value
+		if b, ok := value.(bool); ok && f.Predicate != nil {
+			if b {
+				return nil
+			}
+
+			continue
+		}
+
+		if f.Filter == nil {
+			return nil
+		}
+
+		valid, err := f.Filter.validate(value)
+		if err != nil {
+			return err
+		}
+
+		if valid {
+			return nil
+		}
+	}
+
+	return errFieldNotSatisfied
+}
+
+func (f *Filter) validate(value interface{}) (bool, error) {
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(f), gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return false, fmt.Errorf("validate filter: %w", err)
+	}
+
+	return result.Valid(), nil
+}
+
+// limitDisclosure ensures the credential subject does not disclose claims which are not requested by the fields.
+func limitDisclosure(credential map[string]interface{}, fields []*Field) error {
+	for _, subject := range credentialSubjects(credential) {
+		for claim := range subject {
+			if claim == "id" || claimRequested(claim, fields) {
+				continue
+			}
+
+			return fmt.Errorf("limit disclosure: claim %s is not requested", claim)
+		}
+	}
+
+	return nil
+}
+
+func claimRequested(claim string, fields []*Field) bool {
+	for _, field := range fields {
+		for _, path := range field.Path {
+			if strings.Contains(path, credentialSubjectProperty+"."+claim) ||
+				strings.Contains(path, credentialSubjectProperty+"['"+claim+"']") ||
+				strings.Contains(path, credentialSubjectProperty+`["`+claim+`"]`) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func credentialSubjects(credential map[string]interface{}) []map[string]interface{} {
+	switch subject := credential[credentialSubjectProperty].(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{subject}
+	case []interface{}:
+		var result []map[string]interface{}
+
+		for i := range subject {
+			if s, ok := subject[i].(map[string]interface{}); ok {
+				result = append(result, s)
+			}
+		}
+
+		return result
+	default:
+		return nil
+	}
+}
+
+func subjectIDs(credential map[string]interface{}) []string {
+	if id, ok := credential[credentialSubjectProperty].(string); ok {
+		return []string{id}
+	}
+
+	var ids []string
+
+	for _, subject := range credentialSubjects(credential) {
+		if id, ok := subject["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// checkSameSubject ensures the credentials which satisfy the fields of the same_subject constraints
+// are about the same subject.
+func (p *PresentationDefinitions) checkSameSubject(matched map[string]*verifiable.Credential,
+	subjects map[string][]string) error {
+	for _, descriptor := range p.InputDescriptors {
+		if _, ok := matched[descriptor.ID]; !ok || descriptor.Constraints == nil {
+			continue
+		}
+
+		for _, rule := range descriptor.Constraints.SameSubject {
+			if !isRequired(rule.Directive) {
+				continue
+			}
+
+			var subjectID string
+
+			for _, fieldID := range rule.FieldID {
+				for _, id := range subjects[fieldID] {
+					if subjectID == "" {
+						subjectID = id
+					}
+
+					if id != subjectID {
+						return fmt.Errorf("fields %v do not have the same subject", rule.FieldID)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func credentialToMap(vc *verifiable.Credential) (map[string]interface{}, error) {
+	raw, err := vc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var credential map[string]interface{}
+
+	if err = json.Unmarshal(raw, &credential); err != nil {
+		return nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	return credential, nil
+}
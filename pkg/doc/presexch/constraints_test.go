@@ -0,0 +1,372 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presexch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+const (
+	driversLicenseURI = "https://eu.com/claims/DriversLicense.json"
+	passportURI       = "https://us.gov/claims/Passport.json"
+
+	// Single Group example of the DIF Presentation Exchange specification.
+	singleGroupDefinition = `{
+  "submission_requirements": [{
+    "name": "Citizenship Information",
+    "rule": "pick",
+    "count": 1,
+    "from": "A"
+  }],
+  "input_descriptors": [
+    {
+      "id": "citizenship_input_1",
+      "name": "EU Driver's License",
+      "group": ["A"],
+      "schema": [{"uri": "https://eu.com/claims/DriversLicense.json"}],
+      "constraints": {
+        "fields": [
+          {
+            "path": ["$.issuer", "$.vc.issuer", "$.iss"],
+            "purpose": "The credential must be from one of the specified issuers",
+            "filter": {"type": "string", "pattern": "did:example:gov1|did:example:gov2"}
+          },
+          {
+            "path": ["$.credentialSubject.dob", "$.vc.credentialSubject.dob", "$.dob"],
+            "filter": {"type": "string", "format": "date"}
+          }
+        ]
+      }
+    },
+    {
+      "id": "citizenship_input_2",
+      "name": "US Passport",
+      "group": ["A"],
+      "schema": [{"uri": "https://us.gov/claims/Passport.json"}],
+      "constraints": {
+        "fields": [{
+          "path": ["$.credentialSubject.birth_date", "$.vc.credentialSubject.birth_date", "$.birth_date"],
+          "filter": {"type": "string", "format": "date"}
+        }]
+      }
+    }
+  ]
+}`
+)
+
+func TestPresentationDefinitions_Match_Constraints(t *testing.T) {
+	loader := contextLoader(t, driversLicenseURI, passportURI)
+
+	t.Run("single group example", func(t *testing.T) {
+		defs := &PresentationDefinitions{}
+		require.NoError(t, json.Unmarshal([]byte(singleGroupDefinition), defs))
+
+		license := newSubjectVC(driversLicenseURI, "did:example:gov1", map[string]interface{}{
+			"id":  "did:example:holder",
+			"dob": "1990-07-17",
+		})
+
+		matched, err := defs.Match(submit(t, "citizenship_input_1", license), WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+
+		license.Issuer.ID = "did:example:gov3"
+
+		_, err = defs.Match(submit(t, "citizenship_input_1", license), WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no value satisfies the field")
+	})
+
+	t.Run("field filter", func(t *testing.T) {
+		defs := &PresentationDefinitions{}
+		require.NoError(t, json.Unmarshal([]byte(singleGroupDefinition), defs))
+
+		passport := newSubjectVC(passportURI, "did:example:gov1", map[string]interface{}{
+			"id":         "did:example:holder",
+			"birth_date": "not a date",
+		})
+
+		_, err := defs.Match(submit(t, "citizenship_input_2", passport), WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field [$.credentialSubject.birth_date")
+	})
+
+	t.Run("field predicate", func(t *testing.T) {
+		required := Required
+		defs := newDefinitions(passportURI, &Constraints{Fields: []*Field{{
+			Path:      []string{"$.credentialSubject.adult"},
+			Filter:    &Filter{Type: "number", Minimum: 18},
+			Predicate: &required,
+		}}})
+
+		vc := newSubjectVC(passportURI, "did:example:gov1", map[string]interface{}{"adult": true})
+
+		_, err := defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		vc.Subject = map[string]interface{}{"adult": false}
+
+		_, err = defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+	})
+
+	t.Run("limit disclosure", func(t *testing.T) {
+		defs := newDefinitions(passportURI, &Constraints{
+			LimitDisclosure: true,
+			Fields:          []*Field{{Path: []string{"$.credentialSubject.birth_date"}}},
+		})
+
+		vc := newSubjectVC(passportURI, "did:example:gov1", map[string]interface{}{
+			"id":         "did:example:holder",
+			"birth_date": "1990-07-17",
+		})
+
+		_, err := defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		vc.Subject = map[string]interface{}{
+			"id":         "did:example:holder",
+			"birth_date": "1990-07-17",
+			"name":       "John Doe",
+		}
+
+		_, err = defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "limit disclosure: claim name is not requested")
+	})
+
+	t.Run("subject is issuer", func(t *testing.T) {
+		required := Required
+		defs := newDefinitions(passportURI, &Constraints{SubjectIsIssuer: &required})
+
+		vc := newSubjectVC(passportURI, "did:example:holder", map[string]interface{}{"id": "did:example:holder"})
+
+		_, err := defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		vc.Issuer.ID = "did:example:gov1"
+
+		_, err = defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "subject did:example:holder is not the issuer")
+	})
+
+	t.Run("is holder", func(t *testing.T) {
+		required := Required
+		defs := newDefinitions(passportURI, &Constraints{
+			Fields:   []*Field{{ID: "birth_date", Path: []string{"$.credentialSubject.birth_date"}}},
+			IsHolder: []*HolderRule{{FieldID: []string{"birth_date"}, Directive: &required}},
+		})
+
+		vc := newSubjectVC(passportURI, "did:example:gov1", map[string]interface{}{
+			"id":         "did:example:holder",
+			"birth_date": "1990-07-17",
+		})
+
+		vp := submit(t, "input", vc)
+		vp.Holder = "did:example:holder"
+
+		_, err := defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		vp.Holder = "did:example:other"
+
+		_, err = defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "subject did:example:holder is not the holder")
+	})
+
+	t.Run("same subject", func(t *testing.T) {
+		required := Required
+		defs := &PresentationDefinitions{
+			InputDescriptors: []*InputDescriptor{
+				{
+					ID:     "license",
+					Schema: []*Schema{{URI: driversLicenseURI}},
+					Constraints: &Constraints{
+						Fields: []*Field{{ID: "dob", Path: []string{"$.credentialSubject.dob"}}},
+						SameSubject: []*HolderRule{{
+							FieldID:   []string{"dob", "birth_date"},
+							Directive: &required,
+						}},
+					},
+				},
+				{
+					ID:     "passport",
+					Schema: []*Schema{{URI: passportURI}},
+					Constraints: &Constraints{
+						Fields: []*Field{{ID: "birth_date", Path: []string{"$.credentialSubject.birth_date"}}},
+					},
+				},
+			},
+		}
+
+		license := newSubjectVC(driversLicenseURI, "did:example:gov1", map[string]interface{}{
+			"id":  "did:example:holder",
+			"dob": "1990-07-17",
+		})
+		passport := newSubjectVC(passportURI, "did:example:gov2", map[string]interface{}{
+			"id":         "did:example:holder",
+			"birth_date": "1990-07-17",
+		})
+
+		vp := newVP(t, &PresentationSubmission{DescriptorMap: []*InputDescriptorMapping{
+			{ID: "license", Path: "$.verifiableCredential[0]"},
+			{ID: "passport", Path: "$.verifiableCredential[1]"},
+		}}, license, passport)
+
+		_, err := defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		passport.Subject = map[string]interface{}{"id": "did:example:other", "birth_date": "1990-07-17"}
+
+		vp = newVP(t, &PresentationSubmission{DescriptorMap: []*InputDescriptorMapping{
+			{ID: "license", Path: "$.verifiableCredential[0]"},
+			{ID: "passport", Path: "$.verifiableCredential[1]"},
+		}}, license, passport)
+
+		_, err = defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "do not have the same subject")
+	})
+}
+
+func TestPresentationDefinitions_Match_Format(t *testing.T) {
+	loader := contextLoader(t, passportURI)
+
+	t.Run("ldp_vc proof type", func(t *testing.T) {
+		defs := newDefinitions(passportURI, nil)
+		defs.Format = &Format{LdpVC: &LdpType{ProofType: []string{"Ed25519Signature2018"}}}
+
+		vc := newSubjectVC(passportURI, "did:example:gov1", map[string]interface{}{"id": "did:example:holder"})
+
+		_, err := defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "credential has no proof")
+
+		vc.Proofs = []verifiable.Proof{{"type": "Ed25519Signature2018"}}
+
+		_, err = defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader),
+			WithCredentialOptions(verifiable.WithDisabledProofCheck()))
+		require.NoError(t, err)
+
+		vc.Proofs = []verifiable.Proof{{"type": "JsonWebSignature2020"}}
+
+		_, err = defs.Match(submit(t, "input", vc), WithJSONLDDocumentLoader(loader),
+			WithCredentialOptions(verifiable.WithDisabledProofCheck()))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "proof type JsonWebSignature2020 is not allowed")
+	})
+
+	t.Run("jwt_vc alg", func(t *testing.T) {
+		vc := newSubjectVC(passportURI, "did:example:gov1", map[string]interface{}{"id": "did:example:holder"})
+
+		claims, err := vc.JWTClaims(false)
+		require.NoError(t, err)
+
+		jwt, err := claims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		vp := newVP(t, &PresentationSubmission{DescriptorMap: []*InputDescriptorMapping{{
+			ID:   "input",
+			Path: "$.verifiableCredential[0]",
+		}}})
+		require.NoError(t, vp.SetCredentials(jwt))
+
+		defs := newDefinitions(passportURI, nil)
+		defs.Format = &Format{JwtVC: &JwtType{Alg: []string{"none"}}}
+
+		_, err = defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.NoError(t, err)
+
+		defs.Format = &Format{JwtVC: &JwtType{Alg: []string{"EdDSA"}}}
+
+		_, err = defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "jwt alg none is not allowed")
+
+		defs.Format = &Format{LdpVC: &LdpType{}}
+
+		_, err = defs.Match(vp, WithJSONLDDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "jwt_vc format is not allowed")
+	})
+}
+
+func TestPresentationDefinitions_MatchCredential(t *testing.T) {
+	defs := &PresentationDefinitions{}
+	require.NoError(t, json.Unmarshal([]byte(singleGroupDefinition), defs))
+
+	license := newSubjectVC(driversLicenseURI, "did:example:gov2", map[string]interface{}{
+		"id":  "did:example:holder",
+		"dob": "1990-07-17",
+	})
+
+	require.NoError(t, defs.MatchCredential("citizenship_input_1", license))
+	require.Error(t, defs.MatchCredential("citizenship_input_2", license))
+	require.EqualError(t, defs.MatchCredential("unknown", license), "input descriptor unknown not found")
+
+	// the schema uri matches the credential schema
+	passport := newVC(nil)
+	passport.Subject = map[string]interface{}{"id": "did:example:holder", "birth_date": "1990-07-17"}
+	passport.Schemas = []verifiable.TypedID{{ID: passportURI, Type: "JsonSchemaValidator2018"}}
+	require.NoError(t, defs.MatchCredential("citizenship_input_2", passport))
+
+	// the context of the credential is not its schema
+	passport = newVC(nil)
+	passport.Context = append(passport.Context, passportURI)
+	require.EqualError(t, defs.MatchCredential("citizenship_input_2", passport),
+		"schema uri [https://us.gov/claims/Passport.json] is not in vc types [VerifiableCredential] or schemas []")
+}
+
+func newDefinitions(uri string, constraints *Constraints) *PresentationDefinitions {
+	return &PresentationDefinitions{
+		InputDescriptors: []*InputDescriptor{{
+			ID:          "input",
+			Schema:      []*Schema{{URI: uri}},
+			Constraints: constraints,
+		}},
+	}
+}
+
+func newSubjectVC(uri, issuer string, subject map[string]interface{}) *verifiable.Credential {
+	vc := newVC([]string{uri})
+	vc.Issuer = verifiable.Issuer{ID: issuer}
+	vc.Subject = subject
+
+	return vc
+}
+
+func submit(t *testing.T, descriptorID string, vcs ...*verifiable.Credential) *verifiable.Presentation {
+	t.Helper()
+
+	descriptorMap := make([]*InputDescriptorMapping, len(vcs))
+
+	for i := range vcs {
+		descriptorMap[i] = &InputDescriptorMapping{ID: descriptorID, Path: "$.verifiableCredential[0]"}
+	}
+
+	return newVP(t, &PresentationSubmission{DescriptorMap: descriptorMap}, vcs...)
+}
+
+func contextLoader(t *testing.T, uris ...string) *ld.CachingDocumentLoader {
+	t.Helper()
+
+	loader := verifiable.CachingJSONLDLoader()
+
+	for _, uri := range uris {
+		addContext(t, loader, uri)
+	}
+
+	return loader
+}
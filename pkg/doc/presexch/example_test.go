@@ -23,15 +23,15 @@ func ExamplePresentationDefinitions_Match() {
 		InputDescriptors: []*InputDescriptor{
 			{
 				ID: "banking",
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: "https://example.context.jsonld/account",
-				},
+				}},
 			},
 			{
 				ID: "residence",
-				Schema: &Schema{
+				Schema: []*Schema{{
 					URI: "https://example.context.jsonld/address",
-				},
+				}},
 			},
 		},
 	}
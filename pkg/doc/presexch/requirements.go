@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presexch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+// evalSubmissionRequirement evaluates the rule against the input descriptors of the group (from)
+// or against the nested submission requirements (from_nested).
+func (p *PresentationDefinitions) evalSubmissionRequirement(requirement *SubmissionRequirement,
+	matched map[string]*verifiable.Credential) error {
+	if (requirement.From == "") == (len(requirement.FromNested) == 0) {
+		return errors.New("either from or from_nested must be provided")
+	}
+
+	var total, satisfied int
+
+	if requirement.From != "" {
+		for _, descriptor := range p.InputDescriptors {
+			if !stringsContain(descriptor.Group, requirement.From) {
+				continue
+			}
+
+			total++
+
+			if _, ok := matched[descriptor.ID]; ok {
+				satisfied++
+			}
+		}
+	}
+
+	for _, nested := range requirement.FromNested {
+		total++
+
+		if p.evalSubmissionRequirement(nested, matched) == nil {
+			satisfied++
+		}
+	}
+
+	switch requirement.Rule {
+	case All:
+		if satisfied != total {
+			return fmt.Errorf("rule all: %d of %d inputs are satisfied", satisfied, total)
+		}
+	case Pick:
+		return evalPick(requirement, satisfied)
+	default:
+		return fmt.Errorf("rule %q is not supported", requirement.Rule)
+	}
+
+	return nil
+}
+
+func evalPick(requirement *SubmissionRequirement, satisfied int) error {
+	if requirement.Count > 0 && satisfied != requirement.Count {
+		return fmt.Errorf("rule pick: count %d is expected but %d inputs are satisfied", requirement.Count, satisfied)
+	}
+
+	if requirement.Min > 0 && satisfied < requirement.Min {
+		return fmt.Errorf("rule pick: min %d is expected but %d inputs are satisfied", requirement.Min, satisfied)
+	}
+
+	if requirement.Max > 0 && satisfied > requirement.Max {
+		return fmt.Errorf("rule pick: max %d is expected but %d inputs are satisfied", requirement.Max, satisfied)
+	}
+
+	return nil
+}
+
+// SelectCredentials selects the credentials to be submitted from the candidates so that the submission
+// requirements are met, the first candidate of the input descriptor is selected. The candidates and the result
+// are keyed by the input descriptor ID. If there are no submission requirements every input descriptor
+// must have a candidate.
+func (p *PresentationDefinitions) SelectCredentials(
+	candidates map[string][]*verifiable.Credential) (map[string]*verifiable.Credential, error) {
+	available := make(map[string]*verifiable.Credential)
+
+	for _, descriptor := range p.InputDescriptors {
+		if len(candidates[descriptor.ID]) > 0 {
+			available[descriptor.ID] = candidates[descriptor.ID][0]
+		}
+	}
+
+	if len(p.SubmissionRequirements) == 0 {
+		if err := p.evalSubmissionRequirements(available); err != nil {
+			return nil, err
+		}
+
+		return available, nil
+	}
+
+	selected := make(map[string]*verifiable.Credential)
+
+	for _, requirement := range p.SubmissionRequirements {
+		ids, err := p.selectForRequirement(requirement, available)
+		if err != nil {
+			return nil, fmt.Errorf("submission requirement %q: %w", requirement.Name, err)
+		}
+
+		for _, id := range ids {
+			selected[id] = available[id]
+		}
+	}
+
+	// the requirements may share input descriptors, the selection is checked as a whole
+	if err := p.evalSubmissionRequirements(selected); err != nil {
+		return nil, err
+	}
+
+	return selected, nil
+}
+
+// selectForRequirement returns the IDs of the input descriptors which are submitted to meet the requirement,
+// the pick rule submits as few inputs as the count or max allows.
+func (p *PresentationDefinitions) selectForRequirement(requirement *SubmissionRequirement,
+	available map[string]*verifiable.Credential) ([]string, error) {
+	inputs, total, err := p.requirementInputs(requirement, available)
+	if err != nil {
+		return nil, err
+	}
+
+	switch requirement.Rule {
+	case All:
+		if len(inputs) != total {
+			return nil, fmt.Errorf("rule all: %d of %d inputs are satisfied", len(inputs), total)
+		}
+	case Pick:
+		if requirement.Count > 0 && len(inputs) > requirement.Count {
+			inputs = inputs[:requirement.Count]
+		}
+
+		if requirement.Max > 0 && len(inputs) > requirement.Max {
+			inputs = inputs[:requirement.Max]
+		}
+
+		if err = evalPick(requirement, len(inputs)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("rule %q is not supported", requirement.Rule)
+	}
+
+	var ids []string
+
+	for _, input := range inputs {
+		ids = append(ids, input...)
+	}
+
+	return ids, nil
+}
+
+// requirementInputs returns the inputs of the requirement which can be satisfied along with the total number
+// of its inputs. The input is either the input descriptor of the group or the nested requirement.
+func (p *PresentationDefinitions) requirementInputs(requirement *SubmissionRequirement,
+	available map[string]*verifiable.Credential) ([][]string, int, error) {
+	if (requirement.From == "") == (len(requirement.FromNested) == 0) {
+		return nil, 0, errors.New("either from or from_nested must be provided")
+	}
+
+	var (
+		inputs [][]string
+		total  int
+	)
+
+	if requirement.From != "" {
+		for _, descriptor := range p.InputDescriptors {
+			if !stringsContain(descriptor.Group, requirement.From) {
+				continue
+			}
+
+			total++
+
+			if _, ok := available[descriptor.ID]; ok {
+				inputs = append(inputs, []string{descriptor.ID})
+			}
+		}
+	}
+
+	for _, nested := range requirement.FromNested {
+		total++
+
+		if ids, err := p.selectForRequirement(nested, available); err == nil {
+			inputs = append(inputs, ids)
+		}
+	}
+
+	return inputs, total, nil
+}
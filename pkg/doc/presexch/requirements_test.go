@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presexch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+// Multi-Group example of the DIF Presentation Exchange specification.
+const multiGroupDefinition = `{
+  "submission_requirements": [
+    {
+      "name": "Banking Information",
+      "purpose": "We need you to prove you currently hold a bank account older than 12months.",
+      "rule": "pick",
+      "count": 1,
+      "from": "A"
+    },
+    {
+      "name": "Employment Information",
+      "purpose": "We are only verifying one current employment relationship.",
+      "rule": "all",
+      "from": "B"
+    },
+    {
+      "name": "Citizenship Information",
+      "rule": "pick",
+      "count": 1,
+      "from_nested": [
+        {
+          "name": "United States Citizenship Proofs",
+          "purpose": "We need you to prove your US citizenship.",
+          "rule": "all",
+          "from": "C"
+        },
+        {
+          "name": "European Union Citizenship Proofs",
+          "purpose": "We need you to prove you are a citizen of an EU member state.",
+          "rule": "all",
+          "from": "D"
+        }
+      ]
+    }
+  ],
+  "input_descriptors": [
+    {"id": "banking_input_1", "group": ["A"], "schema": [{"uri": "https://bank-standards.com/customer.json"}]},
+    {"id": "banking_input_2", "group": ["A"], "schema": [{"uri": "https://bank-schemas.org/1.0.0/accounts.json"}]},
+    {
+      "id": "employment_input",
+      "group": ["B"],
+      "schema": [{"uri": "https://business-standards.org/schemas/employment-history.json"}]
+    },
+    {"id": "citizenship_input_1", "group": ["C"], "schema": [{"uri": "https://eu.com/claims/DriversLicense.json"}]},
+    {"id": "citizenship_input_2", "group": ["D"], "schema": [{"uri": "https://us.gov/claims/Passport.json"}]}
+  ]
+}`
+
+func TestPresentationDefinitions_evalSubmissionRequirements(t *testing.T) {
+	defs := &PresentationDefinitions{}
+	require.NoError(t, json.Unmarshal([]byte(multiGroupDefinition), defs))
+
+	matched := func(ids ...string) map[string]*verifiable.Credential {
+		result := make(map[string]*verifiable.Credential)
+
+		for _, id := range ids {
+			result[id] = &verifiable.Credential{}
+		}
+
+		return result
+	}
+
+	t.Run("multi group example", func(t *testing.T) {
+		require.NoError(t, defs.evalSubmissionRequirements(
+			matched("banking_input_1", "employment_input", "citizenship_input_2")))
+	})
+
+	t.Run("rule pick count", func(t *testing.T) {
+		err := defs.evalSubmissionRequirements(
+			matched("banking_input_1", "banking_input_2", "employment_input", "citizenship_input_1"))
+		require.EqualError(t, err, `submission requirement "Banking Information": `+
+			"rule pick: count 1 is expected but 2 inputs are satisfied")
+	})
+
+	t.Run("rule all", func(t *testing.T) {
+		err := defs.evalSubmissionRequirements(matched("banking_input_1", "citizenship_input_1"))
+		require.EqualError(t, err, `submission requirement "Employment Information": `+
+			"rule all: 0 of 1 inputs are satisfied")
+	})
+
+	t.Run("from nested", func(t *testing.T) {
+		err := defs.evalSubmissionRequirements(
+			matched("banking_input_1", "employment_input", "citizenship_input_1", "citizenship_input_2"))
+		require.EqualError(t, err, `submission requirement "Citizenship Information": `+
+			"rule pick: count 1 is expected but 2 inputs are satisfied")
+	})
+
+	t.Run("rule pick min and max", func(t *testing.T) {
+		d := &PresentationDefinitions{
+			SubmissionRequirements: []*SubmissionRequirement{{Rule: Pick, Min: 1, Max: 1, From: "A"}},
+			InputDescriptors:       defs.InputDescriptors,
+		}
+
+		require.NoError(t, d.evalSubmissionRequirements(matched("banking_input_2")))
+		require.EqualError(t, d.evalSubmissionRequirements(matched()),
+			`submission requirement "": rule pick: min 1 is expected but 0 inputs are satisfied`)
+		require.EqualError(t, d.evalSubmissionRequirements(matched("banking_input_1", "banking_input_2")),
+			`submission requirement "": rule pick: max 1 is expected but 2 inputs are satisfied`)
+	})
+
+	t.Run("no submission requirements", func(t *testing.T) {
+		d := &PresentationDefinitions{InputDescriptors: defs.InputDescriptors[:2]}
+
+		require.NoError(t, d.evalSubmissionRequirements(matched("banking_input_1", "banking_input_2")))
+		require.Error(t, d.evalSubmissionRequirements(matched("banking_input_1")))
+	})
+
+	t.Run("invalid submission requirements", func(t *testing.T) {
+		d := &PresentationDefinitions{
+			SubmissionRequirements: []*SubmissionRequirement{{Name: "invalid", Rule: All}},
+			InputDescriptors:       defs.InputDescriptors,
+		}
+
+		require.EqualError(t, d.evalSubmissionRequirements(matched()),
+			`submission requirement "invalid": either from or from_nested must be provided`)
+
+		d.SubmissionRequirements[0].From = "A"
+		d.SubmissionRequirements[0].Rule = "any"
+
+		require.EqualError(t, d.evalSubmissionRequirements(matched()),
+			`submission requirement "invalid": rule "any" is not supported`)
+	})
+}
+
+func TestPresentationDefinitions_SelectCredentials(t *testing.T) {
+	defs := &PresentationDefinitions{}
+	require.NoError(t, json.Unmarshal([]byte(multiGroupDefinition), defs))
+
+	candidates := func(ids ...string) map[string][]*verifiable.Credential {
+		result := make(map[string][]*verifiable.Credential)
+
+		for _, id := range ids {
+			result[id] = []*verifiable.Credential{{ID: id + "_1"}, {ID: id + "_2"}}
+		}
+
+		return result
+	}
+
+	selectedIDs := func(selected map[string]*verifiable.Credential) map[string]string {
+		result := make(map[string]string)
+
+		for id, vc := range selected {
+			result[id] = vc.ID
+		}
+
+		return result
+	}
+
+	t.Run("multi group example", func(t *testing.T) {
+		selected, err := defs.SelectCredentials(candidates(
+			"banking_input_1", "banking_input_2", "employment_input", "citizenship_input_1", "citizenship_input_2"))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"banking_input_1":     "banking_input_1_1",
+			"employment_input":    "employment_input_1",
+			"citizenship_input_1": "citizenship_input_1_1",
+		}, selectedIDs(selected))
+	})
+
+	t.Run("picks the satisfied input", func(t *testing.T) {
+		selected, err := defs.SelectCredentials(candidates("banking_input_2", "employment_input", "citizenship_input_2"))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"banking_input_2":     "banking_input_2_1",
+			"employment_input":    "employment_input_1",
+			"citizenship_input_2": "citizenship_input_2_1",
+		}, selectedIDs(selected))
+	})
+
+	t.Run("rule all is not satisfied", func(t *testing.T) {
+		_, err := defs.SelectCredentials(candidates("banking_input_1", "citizenship_input_1"))
+		require.EqualError(t, err, `submission requirement "Employment Information": `+
+			"rule all: 0 of 1 inputs are satisfied")
+	})
+
+	t.Run("rule pick is not satisfied", func(t *testing.T) {
+		_, err := defs.SelectCredentials(candidates("employment_input", "citizenship_input_1"))
+		require.EqualError(t, err, `submission requirement "Banking Information": `+
+			"rule pick: count 1 is expected but 0 inputs are satisfied")
+	})
+
+	t.Run("rule pick max", func(t *testing.T) {
+		d := &PresentationDefinitions{
+			SubmissionRequirements: []*SubmissionRequirement{{Rule: Pick, Min: 1, Max: 1, From: "A"}},
+			InputDescriptors:       defs.InputDescriptors,
+		}
+
+		selected, err := d.SelectCredentials(candidates("banking_input_1", "banking_input_2"))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"banking_input_1": "banking_input_1_1"}, selectedIDs(selected))
+	})
+
+	t.Run("no submission requirements", func(t *testing.T) {
+		d := &PresentationDefinitions{InputDescriptors: defs.InputDescriptors[:2]}
+
+		selected, err := d.SelectCredentials(candidates("banking_input_1", "banking_input_2", "employment_input"))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"banking_input_1": "banking_input_1_1",
+			"banking_input_2": "banking_input_2_1",
+		}, selectedIDs(selected))
+
+		_, err = d.SelectCredentials(candidates("banking_input_1"))
+		require.EqualError(t, err, "no credential provided for input descriptor banking_input_2")
+	})
+
+	t.Run("invalid submission requirements", func(t *testing.T) {
+		d := &PresentationDefinitions{
+			SubmissionRequirements: []*SubmissionRequirement{{Name: "invalid", Rule: All}},
+			InputDescriptors:       defs.InputDescriptors,
+		}
+
+		_, err := d.SelectCredentials(candidates())
+		require.EqualError(t, err, `submission requirement "invalid": either from or from_nested must be provided`)
+
+		d.SubmissionRequirements[0].From = "A"
+		d.SubmissionRequirements[0].Rule = "any"
+
+		_, err = d.SelectCredentials(candidates())
+		require.EqualError(t, err, `submission requirement "invalid": rule "any" is not supported`)
+	})
+}
@@ -19,7 +19,11 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/anoncrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/authcrypt"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
@@ -28,6 +32,7 @@ import (
 	mdpresentproof "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
 	didcommtransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
@@ -67,8 +72,9 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	// - OutOfBand depends on DIDExchange
 	// - Introduce depends on OutOfBand
 	frameworkOpts.protocolSvcCreators = append(frameworkOpts.protocolSvcCreators,
-		newMessagePickupSvc(), newRouteSvc(), newExchangeSvc(), newOutOfBandSvc(),
-		newIntroduceSvc(), newIssueCredentialSvc(), newPresentProofSvc())
+		newMessagePickupSvc(), newRouteSvc(), newExchangeSvc(frameworkOpts.legacyConnections), newOutOfBandSvc(),
+		newIntroduceSvc(), newIssueCredentialSvc(), newPresentProofSvc(), newTrustPingSvc(),
+		newDIDRotateSvc(), newActionMenuSvc(), newBasicMessageSvc(), newDiscoverFeaturesSvc())
 
 	if frameworkOpts.secretLock == nil && frameworkOpts.kmsCreator == nil {
 		err = createDefSecretLock(frameworkOpts)
@@ -86,9 +92,15 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	return setAdditionalDefaultOpts(frameworkOpts)
 }
 
-func newExchangeSvc() api.ProtocolSvcCreator {
+func newExchangeSvc(legacyConnections bool) api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		return didexchange.New(prv)
+		var opts []didexchange.ServiceOption
+
+		if legacyConnections {
+			opts = append(opts, didexchange.WithLegacyConnections())
+		}
+
+		return didexchange.New(prv, opts...)
 	}
 }
 
@@ -100,7 +112,10 @@ func newIntroduceSvc() api.ProtocolSvcCreator {
 
 func newIssueCredentialSvc() api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		service, err := issuecredential.New(prv)
+		service, err := issuecredential.New(prv, issuecredential.WithFormatHandlers(
+			&issuecredential.LDProofVCFormatHandler{},
+			&issuecredential.JWTVCFormatHandler{},
+		))
 		if err != nil {
 			return nil, err
 		}
@@ -119,8 +134,13 @@ func newPresentProofSvc() api.ProtocolSvcCreator {
 			return nil, err
 		}
 
+		verifier, err := mdpresentproof.VerifyPresentationDefinitions(prv)
+		if err != nil {
+			return nil, err
+		}
+
 		// sets default middleware to the service
-		service.Use(mdpresentproof.SavePresentation(prv))
+		service.Use(verifier, mdpresentproof.SavePresentation(prv))
 
 		return service, nil
 	}
@@ -149,6 +169,46 @@ func newOutOfBandSvc() api.ProtocolSvcCreator {
 	}
 }
 
+func newTrustPingSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return trustping.New(prv)
+	}
+}
+
+func newDIDRotateSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return didrotate.New(prv)
+	}
+}
+
+func newActionMenuSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return actionmenu.New(prv)
+	}
+}
+
+func newBasicMessageSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		bp, ok := prv.(basicmessage.Provider)
+		if !ok {
+			return nil, errors.New("failed to cast basic message provider")
+		}
+
+		return basicmessage.New(bp)
+	}
+}
+
+func newDiscoverFeaturesSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		dp, ok := prv.(discoverfeatures.Provider)
+		if !ok {
+			return nil, errors.New("failed to cast discover-features provider")
+		}
+
+		return discoverfeatures.New(dp)
+	}
+}
+
 func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 	if frameworkOpts.kmsCreator == nil {
 		frameworkOpts.kmsCreator = func(provider kms.Provider) (kms.KeyManager, error) {
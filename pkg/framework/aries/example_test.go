@@ -11,6 +11,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 )
 
 func Example() {
@@ -67,7 +68,7 @@ func newMockDBProvider() *mockDBProvider {
 }
 
 func (c *mockDBProvider) OpenStore(name string) (storage.Store, error) {
-	return nil, nil
+	return mem.NewProvider().OpenStore(name)
 }
 
 func (c *mockDBProvider) CloseStore(name string) error {
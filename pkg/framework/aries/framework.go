@@ -8,10 +8,12 @@ package aries
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
@@ -38,12 +40,15 @@ const (
 	defaultMasterKeyURI = "local-lock://default/master/key/"
 )
 
+// nolint:gochecknoglobals
+var logger = log.New("aries-framework/framework")
+
 // Aries provides access to the context being managed by the framework. The context can be used to create aries clients.
 type Aries struct {
 	storeProvider              storage.Provider
 	protocolStateStoreProvider storage.Provider
 	protocolSvcCreators        []api.ProtocolSvcCreator
-	services                   []dispatcher.ProtocolService
+	protocols                  *dispatcher.ProtocolRegistrar
 	msgSvcProvider             api.MessageServiceProvider
 	outboundDispatcher         dispatcher.Outbound
 	messenger                  service.MessengerHandler
@@ -63,6 +68,7 @@ type Aries struct {
 	vdr                        []vdrapi.VDR
 	verifiableStore            verifiable.Store
 	transportReturnRoute       string
+	legacyConnections          bool
 	id                         string
 }
 
@@ -72,7 +78,7 @@ type Option func(opts *Aries) error
 // New initializes the Aries framework based on the set of options provided. This function returns a framework
 // which can be used to manage Aries clients by getting the framework context.
 func New(opts ...Option) (*Aries, error) {
-	frameworkOpts := &Aries{}
+	frameworkOpts := &Aries{protocols: dispatcher.NewProtocolRegistrar()}
 
 	// generate framework configs from options
 	for _, option := range opts {
@@ -182,6 +188,16 @@ func WithTransportReturnRoute(transportReturnRoute string) Option {
 	}
 }
 
+// WithLegacyConnections makes the did-exchange service send the RFC 0160 (Connection Protocol) payloads
+// instead of the signed DID doc attachments, for agents which do not support DID Exchange 1.0 yet.
+func WithLegacyConnections() Option {
+	return func(opts *Aries) error {
+		opts.legacyConnections = true
+
+		return nil
+	}
+}
+
 // WithStoreProvider injects a storage provider to the Aries framework.
 func WithStoreProvider(prov storage.Provider) Option {
 	return func(opts *Aries) error {
@@ -274,7 +290,7 @@ func (a *Aries) Context() (*context.Provider, error) {
 		context.WithOutboundDispatcher(a.outboundDispatcher),
 		context.WithMessengerHandler(a.messenger),
 		context.WithOutboundTransports(a.outboundTransports...),
-		context.WithProtocolServices(a.services...),
+		context.WithProtocolRegistrar(a.protocols),
 		context.WithKMS(a.kms),
 		context.WithSecretLock(a.secretLock),
 		context.WithCrypto(a.crypto),
@@ -320,9 +336,33 @@ func (a *Aries) Close() error {
 		}
 	}
 
+	if err := a.closeServices(); err != nil {
+		return err
+	}
+
 	return a.closeVDR()
 }
 
+// closeServices stops the background work of the protocol services which can be closed.
+func (a *Aries) closeServices() error {
+	if a.protocols == nil {
+		return nil
+	}
+
+	for _, svc := range a.protocols.Services() {
+		closer, ok := svc.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("close protocol service %s: %w", svc.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 func (a *Aries) closeVDR() error {
 	if a.vdrRegistry != nil {
 		if err := a.vdrRegistry.Close(); err != nil {
@@ -393,6 +433,8 @@ func createMessengerHandler(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithOutboundDispatcher(frameworkOpts.outboundDispatcher),
 		context.WithStorageProvider(frameworkOpts.storeProvider),
+		context.WithKMS(frameworkOpts.kms),
+		context.WithServiceEndpoint(serviceEndpoint(frameworkOpts)),
 	)
 	if err != nil {
 		return fmt.Errorf("context creation failed: %w", err)
@@ -425,7 +467,7 @@ func startTransports(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithPackager(frameworkOpts.packager),
-		context.WithProtocolServices(frameworkOpts.services...),
+		context.WithProtocolRegistrar(frameworkOpts.protocols),
 		context.WithAriesFrameworkID(frameworkOpts.id),
 		context.WithMessageServiceProvider(frameworkOpts.msgSvcProvider),
 		context.WithMessengerHandler(frameworkOpts.messenger),
@@ -465,6 +507,7 @@ func loadServices(frameworkOpts *Aries) error {
 		context.WithVDRegistry(frameworkOpts.vdrRegistry),
 		context.WithVerifiableStore(frameworkOpts.verifiableStore),
 		context.WithMessageServiceProvider(frameworkOpts.msgSvcProvider),
+		context.WithProtocolRegistrar(frameworkOpts.protocols),
 	)
 	if err != nil {
 		return fmt.Errorf("create context failed: %w", err)
@@ -476,11 +519,17 @@ func loadServices(frameworkOpts *Aries) error {
 			return fmt.Errorf("new protocol service failed: %w", svcErr)
 		}
 
-		frameworkOpts.services = append(frameworkOpts.services, svc)
+		// the services injected with WithProtocols are created first, they override the default ones
+		if _, ok := frameworkOpts.protocols.Service(svc.Name()); ok {
+			logger.Warnf("protocol service %s is already registered, skipping the later one", svc.Name())
+
+			continue
+		}
+
 		// after service was successfully created we need to add it to the context
 		// since the introduce protocol depends on did-exchange
-		if err := context.WithProtocolServices(frameworkOpts.services...)(ctx); err != nil {
-			return err
+		if err := frameworkOpts.protocols.Register(svc); err != nil {
+			return fmt.Errorf("register protocol service: %w", err)
 		}
 	}
 
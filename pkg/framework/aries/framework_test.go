@@ -20,13 +20,19 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
@@ -192,6 +198,19 @@ func TestFramework(t *testing.T) {
 
 		_, err = ctx.Service(didexchange.DIDExchange)
 		require.NoError(t, err)
+
+		svc, err := ctx.Service(discoverfeatures.Name)
+		require.NoError(t, err)
+
+		features, err := svc.(*discoverfeatures.Service).Features("https://didcomm.org/*")
+		require.NoError(t, err)
+		require.Contains(t, features, discoverfeatures.ProtocolDescriptor{PID: didexchange.PIURI})
+		require.Contains(t, features, discoverfeatures.ProtocolDescriptor{PID: discoverfeatures.PIURI})
+		require.Contains(t, features, discoverfeatures.ProtocolDescriptor{PID: trustping.PIURI})
+		require.Contains(t, features, discoverfeatures.ProtocolDescriptor{PID: didrotate.PIURI})
+		require.Contains(t, features, discoverfeatures.ProtocolDescriptor{PID: actionmenu.PIURI})
+		require.Contains(t, features, discoverfeatures.ProtocolDescriptor{PID: basicmessage.PIURI})
+
 		err = aries.Close()
 		require.NoError(t, err)
 	})
@@ -220,6 +239,38 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test protocol svc - overrides the default one", func(t *testing.T) {
+		svc := &mockdidexchange.MockDIDExchangeSvc{ProtocolName: trustping.TrustPing}
+
+		aries, err := New(WithProtocols(func(api.Provider) (dispatcher.ProtocolService, error) {
+			return svc, nil
+		}), WithInboundTransport(&mockInboundTransport{}))
+		require.NoError(t, err)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		registered, err := ctx.Service(trustping.TrustPing)
+		require.NoError(t, err)
+		require.Equal(t, svc, registered)
+
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test close protocol services", func(t *testing.T) {
+		svc := &closableProtocolSvc{MockDIDExchangeSvc: mockdidexchange.MockDIDExchangeSvc{ProtocolName: "closable"}}
+		aries, err := New(WithProtocols(func(api.Provider) (dispatcher.ProtocolService, error) {
+			return svc, nil
+		}), WithInboundTransport(&mockInboundTransport{}))
+		require.NoError(t, err)
+
+		require.NoError(t, aries.Close())
+		require.True(t, svc.closed)
+
+		svc.closeErr = errors.New("close error")
+		require.EqualError(t, aries.Close(), "close protocol service closable: close error")
+	})
+
 	t.Run("test new with protocol service", func(t *testing.T) {
 		mockSvcCreator := func(prv api.Provider) (dispatcher.ProtocolService, error) {
 			return &mockdidexchange.MockDIDExchangeSvc{
@@ -239,6 +290,52 @@ func TestFramework(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("test protocol svc - registered at runtime", func(t *testing.T) {
+		inbound := &mockInboundTransport{}
+
+		aries, err := New(WithInboundTransport(inbound))
+		require.NoError(t, err)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		err = ctx.RegisterProtocolServices(&mockdidexchange.MockDIDExchangeSvc{ProtocolName: didexchange.DIDExchange})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already registered")
+
+		handled := make(chan struct{}, 1)
+
+		require.NoError(t, ctx.RegisterProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			ProtocolName: "mockProtocolSvc",
+			AcceptFunc: func(msgType string) bool {
+				return msgType == "mock-message-type"
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				handled <- struct{}{}
+
+				return "", nil
+			},
+		}))
+
+		// the contexts created afterwards and the inbound transports see the registered service
+		prov, err := aries.Context()
+		require.NoError(t, err)
+
+		_, err = prov.Service("mockProtocolSvc")
+		require.NoError(t, err)
+
+		msg := []byte(`{"@id":"` + uuid.New().String() + `","@type":"mock-message-type"}`)
+		require.NoError(t, inbound.provider.InboundMessageHandler()(msg, "myDID", "theirDID"))
+		require.Len(t, handled, 1)
+
+		require.NoError(t, prov.UnregisterProtocolService("mockProtocolSvc"))
+
+		_, err = ctx.Service("mockProtocolSvc")
+		require.Error(t, err)
+
+		require.NoError(t, aries.Close())
+	})
+
 	t.Run("test error from protocol service", func(t *testing.T) {
 		newMockSvc := func(prv api.Provider) (dispatcher.ProtocolService, error) {
 			return nil, errors.New("error creating the protocol")
@@ -570,6 +667,19 @@ func TestFramework(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid transport return route option : "+transportReturnRoute)
 	})
 
+	t.Run("test new with legacy connections", func(t *testing.T) {
+		aries, err := New(WithLegacyConnections())
+		require.NoError(t, err)
+		require.True(t, aries.legacyConnections)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		_, err = ctx.Service(didexchange.DIDExchange)
+		require.NoError(t, err)
+		require.NoError(t, aries.Close())
+	})
+
 	t.Run("test message service provider option", func(t *testing.T) {
 		// custom message service provider
 		handler := msghandler.NewMockMsgServiceProvider()
@@ -668,6 +778,7 @@ func getServerPort(server net.Listener) int {
 type mockInboundTransport struct {
 	startError error
 	stopError  error
+	provider   transport.Provider
 }
 
 func (m *mockInboundTransport) Start(prov transport.Provider) error {
@@ -675,6 +786,8 @@ func (m *mockInboundTransport) Start(prov transport.Provider) error {
 		return m.startError
 	}
 
+	m.provider = prov
+
 	return nil
 }
 
@@ -689,3 +802,15 @@ func (m *mockInboundTransport) Stop() error {
 func (m *mockInboundTransport) Endpoint() string {
 	return ""
 }
+
+type closableProtocolSvc struct {
+	mockdidexchange.MockDIDExchangeSvc
+	closed   bool
+	closeErr error
+}
+
+func (s *closableProtocolSvc) Close() error {
+	s.closed = true
+
+	return s.closeErr
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package context
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
@@ -26,9 +27,11 @@ import (
 // package context creates a framework Provider context to add optional (non default) framework services and provides
 // simple accessor methods to those same services.
 
+var errNoProtocolRegistrar = errors.New("protocol registrar is not initialized")
+
 // Provider supplies the framework configuration to client objects.
 type Provider struct {
-	services                   []dispatcher.ProtocolService
+	protocols                  *dispatcher.ProtocolRegistrar
 	msgSvcProvider             api.MessageServiceProvider
 	storeProvider              storage.Provider
 	protocolStateStoreProvider storage.Provider
@@ -50,12 +53,12 @@ type Provider struct {
 }
 
 type outboundHandler struct {
-	services []dispatcher.ProtocolService
+	protocols *dispatcher.ProtocolRegistrar
 }
 
 func (o *outboundHandler) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
-	for _, s := range o.services {
-		if s.Accept(msg.Type()) {
+	if o.protocols != nil {
+		if s, ok := o.protocols.Accepting(msg.Type()); ok {
 			return s.HandleOutbound(msg, myDID, theirDID)
 		}
 	}
@@ -74,6 +77,10 @@ func New(opts ...ProviderOption) (*Provider, error) {
 		}
 	}
 
+	if ctxProvider.protocols == nil {
+		ctxProvider.protocols = dispatcher.NewProtocolRegistrar()
+	}
+
 	return &ctxProvider, nil
 }
 
@@ -89,15 +96,56 @@ func (p *Provider) OutboundTransports() []transport.OutboundTransport {
 
 // Service return protocol service.
 func (p *Provider) Service(id string) (interface{}, error) {
-	for _, v := range p.services {
-		if v.Name() == id {
-			return v, nil
-		}
+	if p.protocols == nil {
+		return nil, api.ErrSvcNotFound
+	}
+
+	if svc, ok := p.protocols.Service(id); ok {
+		return svc, nil
 	}
 
 	return nil, api.ErrSvcNotFound
 }
 
+// ProtocolServices returns the registered protocol services.
+func (p *Provider) ProtocolServices() []dispatcher.ProtocolService {
+	if p.protocols == nil {
+		return nil
+	}
+
+	return p.protocols.Services()
+}
+
+// ProtocolRegistrar returns the registrar of the protocol services the messages are dispatched to.
+func (p *Provider) ProtocolRegistrar() *dispatcher.ProtocolRegistrar {
+	return p.protocols
+}
+
+// RegisterProtocolServices registers the protocol services at runtime, the inbound and outbound messages
+// are dispatched to them right away. Returns error if a service with the same name is already registered.
+func (p *Provider) RegisterProtocolServices(services ...dispatcher.ProtocolService) error {
+	if p.protocols == nil {
+		return errNoProtocolRegistrar
+	}
+
+	return p.protocols.Register(services...)
+}
+
+// UnregisterProtocolService unregisters the protocol service with the given name at runtime,
+// the messages are no longer dispatched to it.
+func (p *Provider) UnregisterProtocolService(name string) error {
+	if p.protocols == nil {
+		return errNoProtocolRegistrar
+	}
+
+	return p.protocols.Unregister(name)
+}
+
+// MessageServiceProvider returns the provider of message services.
+func (p *Provider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.msgSvcProvider
+}
+
 // KMS returns a Key Management Service.
 func (p *Provider) KMS() kms.KeyManager {
 	return p.kms
@@ -166,8 +214,8 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 		}
 
 		// find the service which accepts the message type
-		for _, svc := range p.services {
-			if svc.Accept(msg.Type()) {
+		if p.protocols != nil {
+			if svc, ok := p.protocols.Accepting(msg.Type()); ok {
 				return p.tryToHandle(svc, msg, myDID, theirDID)
 			}
 		}
@@ -194,11 +242,9 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 }
 
 // OutboundMessageHandler returns a handler composed of all registered protocol services.
+// The protocol services registered or unregistered later on are taken into account.
 func (p *Provider) OutboundMessageHandler() service.OutboundHandler {
-	tmp := make([]dispatcher.ProtocolService, len(p.services))
-	copy(tmp, p.services)
-
-	return &outboundHandler{services: tmp}
+	return &outboundHandler{protocols: p.protocols}
 }
 
 // StorageProvider return a storage provider.
@@ -269,7 +315,16 @@ func WithTransportReturnRoute(transportReturnRoute string) ProviderOption {
 // WithProtocolServices injects a protocol services into the context.
 func WithProtocolServices(services ...dispatcher.ProtocolService) ProviderOption {
 	return func(opts *Provider) error {
-		opts.services = services
+		opts.protocols = dispatcher.NewProtocolRegistrar(services...)
+		return nil
+	}
+}
+
+// WithProtocolRegistrar injects the registrar of the protocol services into the context.
+// The contexts sharing the registrar see the protocol services registered at runtime.
+func WithProtocolRegistrar(r *dispatcher.ProtocolRegistrar) ProviderOption {
+	return func(opts *Provider) error {
+		opts.protocols = r
 		return nil
 	}
 }
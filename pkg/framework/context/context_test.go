@@ -18,7 +18,9 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	verifiableStoreMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/store/verifiable"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
@@ -71,6 +73,8 @@ func TestNewProvider(t *testing.T) {
 
 		_, err = prov.Service("mockProtocolSvc1")
 		require.Error(t, err)
+
+		require.Len(t, prov.ProtocolServices(), 1)
 	})
 
 	t.Run("test inbound message handlers/dispatchers", func(t *testing.T) {
@@ -217,6 +221,102 @@ func TestNewProvider(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("register and unregister protocol service at runtime", func(t *testing.T) {
+		messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
+		messengerHandler.EXPECT().HandleInbound(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+		ctx, err := New(
+			WithMessageServiceProvider(msghandler.NewMockMsgServiceProvider()),
+			WithMessengerHandler(messengerHandler),
+		)
+		require.NoError(t, err)
+
+		inboundHandler := ctx.InboundMessageHandler()
+		outboundHandler := ctx.OutboundMessageHandler()
+		msg := []byte(`{"@type": "runtime-message-type"}`)
+		outboundMsg := service.NewDIDCommMsgMap(&didexchange.Request{Type: "runtime-message-type"})
+
+		err = inboundHandler(msg, "", "")
+		require.EqualError(t, err, "no message handlers found for the message type: runtime-message-type")
+
+		_, err = outboundHandler.HandleOutbound(outboundMsg, "myDID", "theirDID")
+		require.EqualError(t, err, "no handlers for msg type runtime-message-type")
+
+		handled := make(chan struct{}, 2)
+
+		require.NoError(t, ctx.RegisterProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			ProtocolName: "runtimeProtocolSvc",
+			AcceptFunc: func(msgType string) bool {
+				return msgType == "runtime-message-type"
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				handled <- struct{}{}
+
+				return "", nil
+			},
+			HandleOutboundFunc: func(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+				handled <- struct{}{}
+
+				return "", nil
+			},
+		}))
+
+		_, err = ctx.Service("runtimeProtocolSvc")
+		require.NoError(t, err)
+		require.Len(t, ctx.ProtocolServices(), 1)
+
+		err = ctx.RegisterProtocolServices(&mockdidexchange.MockDIDExchangeSvc{ProtocolName: "runtimeProtocolSvc"})
+		require.EqualError(t, err,
+			"registration failed, protocol service with name `runtimeProtocolSvc` already registered")
+
+		// handlers obtained before the registration dispatch to the new service
+		require.NoError(t, inboundHandler(msg, "", ""))
+		_, err = outboundHandler.HandleOutbound(outboundMsg, "myDID", "theirDID")
+		require.NoError(t, err)
+		require.Len(t, handled, 2)
+
+		require.NoError(t, ctx.UnregisterProtocolService("runtimeProtocolSvc"))
+		require.EqualError(t, ctx.UnregisterProtocolService("runtimeProtocolSvc"),
+			"failed to unregister, unable to find registered protocol service with name `runtimeProtocolSvc`")
+
+		_, err = ctx.Service("runtimeProtocolSvc")
+		require.True(t, errors.Is(err, api.ErrSvcNotFound))
+
+		err = inboundHandler(msg, "", "")
+		require.EqualError(t, err, "no message handlers found for the message type: runtime-message-type")
+	})
+
+	t.Run("contexts sharing the protocol registrar", func(t *testing.T) {
+		registrar := dispatcher.NewProtocolRegistrar()
+
+		ctx1, err := New(WithProtocolRegistrar(registrar))
+		require.NoError(t, err)
+
+		ctx2, err := New(WithProtocolRegistrar(registrar))
+		require.NoError(t, err)
+		require.Equal(t, registrar, ctx2.ProtocolRegistrar())
+
+		require.NoError(t, ctx1.RegisterProtocolServices(&mockdidexchange.MockDIDExchangeSvc{ProtocolName: "shared"}))
+
+		_, err = ctx2.Service("shared")
+		require.NoError(t, err)
+	})
+
+	t.Run("protocol services of the zero provider", func(t *testing.T) {
+		prov := &Provider{}
+
+		_, err := prov.Service("mockProtocolSvc")
+		require.True(t, errors.Is(err, api.ErrSvcNotFound))
+		require.Empty(t, prov.ProtocolServices())
+		require.True(t, errors.Is(prov.RegisterProtocolServices(), errNoProtocolRegistrar))
+		require.True(t, errors.Is(prov.UnregisterProtocolService("mockProtocolSvc"), errNoProtocolRegistrar))
+
+		_, err = prov.OutboundMessageHandler().HandleOutbound(service.NewDIDCommMsgMap(&didexchange.Request{
+			Type: "test",
+		}), "myDID", "theirDID")
+		require.EqualError(t, err, "no handlers for msg type test")
+	})
+
 	t.Run("test new with message service", func(t *testing.T) {
 		const sampleMsgType = "generic-msg-type-2.0"
 
@@ -231,6 +331,7 @@ func TestNewProvider(t *testing.T) {
 		mockMsgHandler := msghandler.NewMockMsgServiceProvider()
 		prov, err := New(WithMessageServiceProvider(mockMsgHandler), WithMessengerHandler(messenger))
 		require.NoError(t, err)
+		require.Equal(t, mockMsgHandler, prov.MessageServiceProvider())
 
 		err = mockMsgHandler.Register(&generic.MockMessageSvc{
 			HandleFunc: func(*service.DIDCommMsg) (string, error) {
@@ -33,10 +33,11 @@ func NewDefLog(module string) *DefLog {
 // DefLog is a logger implementation built on top of standard go log.
 // There is a  configurable caller info feature which displays caller function information name in logged lines.
 // caller info can be configured by log levels and modules. By default it is enabled.
-// Log Format : [<MODULE NAME>] <TIME IN UTC> - <CALLER INFO> -> <LOG LEVEL> <LOG TEXT>.
+// Log Format : [<MODULE NAME>] <TIME IN UTC> - <CALLER INFO> -> <LOG LEVEL> <LOG TEXT> <FIELDS AS JSON>.
 type DefLog struct {
 	logger *log.Logger
 	module string
+	fields map[string]interface{}
 }
 
 // Fatalf is CRITICAL log formatted followed by a call to os.Exit(1).
@@ -76,6 +77,22 @@ func (l *DefLog) Errorf(format string, args ...interface{}) {
 	l.logf(metadata.ERROR, format, args...)
 }
 
+// WithFields returns a copy of the logger which renders given fields (merged with the existing ones)
+// as JSON object at the end of every line. The copy shares output destination with the original logger.
+func (l *DefLog) WithFields(fields map[string]interface{}) Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &DefLog{logger: l.logger, module: l.module, fields: merged}
+}
+
 // SetOutput sets the output destination for the logger.
 func (l *DefLog) SetOutput(output io.Writer) {
 	l.logger.SetOutput(output)
@@ -87,7 +104,12 @@ func (l *DefLog) logf(level metadata.Level, format string, args ...interface{})
 	// Format prefix to show function name and log level and to indicate that timezone used is UTC
 	customPrefix := fmt.Sprintf(logLevelFormatter, l.getCallerInfo(level), metadata.ParseString(level))
 
-	err := l.logger.Output(callDepth, customPrefix+fmt.Sprintf(format, args...))
+	msg := customPrefix + fmt.Sprintf(format, args...)
+	if len(l.fields) > 0 {
+		msg += " " + FormatFields(l.fields)
+	}
+
+	err := l.logger.Output(callDepth, msg)
 	if err != nil {
 		fmt.Printf("error from logger.Output %v\n", err)
 	}
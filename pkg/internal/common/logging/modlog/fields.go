@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package modlog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FieldsLogger is implemented by loggers which are able to attach structured fields to logged lines.
+// Loggers which do not implement it get the fields appended to the message text instead.
+type FieldsLogger interface {
+	// WithFields returns a logger which attaches given fields to every logged line.
+	WithFields(fields map[string]interface{}) Logger
+}
+
+// WithFields returns a logger which attaches given fields to every logged line.
+// If the underlying logger supports structured fields they are passed as they are,
+// otherwise fields are rendered as JSON object at the end of the message.
+func WithFields(logger Logger, fields map[string]interface{}) Logger {
+	if len(fields) == 0 {
+		return logger
+	}
+
+	if modLog, ok := logger.(*ModLog); ok {
+		return NewModLog(WithFields(modLog.logger, fields), modLog.module)
+	}
+
+	if fieldsLogger, ok := logger.(FieldsLogger); ok {
+		return fieldsLogger.WithFields(fields)
+	}
+
+	return &fieldsLog{logger: logger, suffix: " " + FormatFields(fields)}
+}
+
+// FormatFields renders given fields as JSON object, keys are sorted.
+func FormatFields(fields map[string]interface{}) string {
+	src, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Sprintf("%v", fields)
+	}
+
+	return string(src)
+}
+
+// fieldsLog appends rendered fields to every message of the underlying logger.
+type fieldsLog struct {
+	logger Logger
+	suffix string
+}
+
+// Fatalf calls underlying logger.Fatalf.
+func (l *fieldsLog) Fatalf(format string, args ...interface{}) {
+	l.logger.Fatalf("%s", l.format(format, args...))
+}
+
+// Panicf calls underlying logger.Panicf.
+func (l *fieldsLog) Panicf(format string, args ...interface{}) {
+	l.logger.Panicf("%s", l.format(format, args...))
+}
+
+// Debugf calls underlying logger.Debugf.
+func (l *fieldsLog) Debugf(format string, args ...interface{}) {
+	l.logger.Debugf("%s", l.format(format, args...))
+}
+
+// Infof calls underlying logger.Infof.
+func (l *fieldsLog) Infof(format string, args ...interface{}) {
+	l.logger.Infof("%s", l.format(format, args...))
+}
+
+// Warnf calls underlying logger.Warnf.
+func (l *fieldsLog) Warnf(format string, args ...interface{}) {
+	l.logger.Warnf("%s", l.format(format, args...))
+}
+
+// Errorf calls underlying logger.Errorf.
+func (l *fieldsLog) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf("%s", l.format(format, args...))
+}
+
+func (l *fieldsLog) format(format string, args ...interface{}) string {
+	return fmt.Sprintf(format, args...) + l.suffix
+}
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package modlog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/logging/metadata"
+)
+
+func TestWithFields(t *testing.T) {
+	t.Run("default logger", func(t *testing.T) {
+		const module = "sample-module-fields"
+
+		logger := WithFields(NewModLog(NewDefLog(module), module), map[string]interface{}{"thid": "123"})
+		SwitchLogOutputToBuffer(logger)
+
+		defer buf.Reset()
+
+		logger = WithFields(logger, map[string]interface{}{"state": "done"})
+
+		metadata.SetLevel(module, metadata.INFO)
+		logger.Infof(msgFormat, msgArg1, msgArg2)
+		require.Contains(t, buf.String(), `INFO brown fox jumps over the lazy dog {"state":"done","thid":"123"}`)
+	})
+
+	t.Run("custom logger", func(t *testing.T) {
+		const module = "sample-module-fields-custom"
+
+		custom := &recordLog{}
+		logger := WithFields(NewModLog(custom, module), map[string]interface{}{"thid": "123"})
+
+		metadata.SetLevel(module, metadata.DEBUG)
+
+		logger.Debugf(msgFormat, msgArg1, msgArg2)
+		logger.Infof(msgFormat, msgArg1, msgArg2)
+		logger.Warnf(msgFormat, msgArg1, msgArg2)
+		logger.Errorf(msgFormat, msgArg1, msgArg2)
+		logger.Panicf(msgFormat, msgArg1, msgArg2)
+		logger.Fatalf(msgFormat, msgArg1, msgArg2)
+
+		require.Len(t, custom.lines, 6)
+
+		for _, line := range custom.lines {
+			require.Equal(t, `brown fox jumps over the lazy dog {"thid":"123"}`, line)
+		}
+	})
+
+	t.Run("no fields", func(t *testing.T) {
+		custom := &recordLog{}
+		require.Equal(t, custom, WithFields(custom, nil))
+	})
+
+	t.Run("unsupported value", func(t *testing.T) {
+		require.Equal(t, "map[ch:<nil>]", FormatFields(map[string]interface{}{"ch": (chan int)(nil)}))
+	})
+}
+
+type recordLog struct {
+	lines []string
+}
+
+func (l *recordLog) record(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordLog) Fatalf(format string, args ...interface{}) { l.record(format, args...) }
+
+func (l *recordLog) Panicf(format string, args ...interface{}) { l.record(format, args...) }
+
+func (l *recordLog) Debugf(format string, args ...interface{}) { l.record(format, args...) }
+
+func (l *recordLog) Infof(format string, args ...interface{}) { l.record(format, args...) }
+
+func (l *recordLog) Warnf(format string, args ...interface{}) { l.record(format, args...) }
+
+func (l *recordLog) Errorf(format string, args ...interface{}) { l.record(format, args...) }
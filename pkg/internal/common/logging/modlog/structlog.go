@@ -0,0 +1,319 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package modlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/logging/metadata"
+)
+
+// Keys of the entry values which are always present in structured log lines.
+const (
+	TimestampKey = "ts"
+	LevelKey     = "level"
+	ModuleKey    = "module"
+	CallerKey    = "caller"
+	MessageKey   = "msg"
+)
+
+// Entry is a single structured log line.
+type Entry struct {
+	Time    time.Time
+	Level   metadata.Level
+	Module  string
+	Caller  string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Encoder converts an entry into a log line (without the trailing new line).
+type Encoder func(e *Entry) []byte
+
+// EncodeJSON encodes the entry as a JSON object.
+// Entry values come first followed by the fields sorted by key.
+func EncodeJSON(e *Entry) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, kv := range e.keyValues() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.Write(marshalJSON(kv.key))
+		buf.WriteByte(':')
+		buf.Write(marshalJSON(kv.value))
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes()
+}
+
+// EncodeLogfmt encodes the entry as logfmt key=value pairs.
+// Entry values come first followed by the fields sorted by key.
+func EncodeLogfmt(e *Entry) []byte {
+	var buf bytes.Buffer
+
+	for i, kv := range e.keyValues() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(kv.key)
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(kv.value))
+	}
+
+	return buf.Bytes()
+}
+
+type keyValue struct {
+	key   string
+	value interface{}
+}
+
+func (e *Entry) keyValues() []keyValue {
+	kvs := []keyValue{
+		{key: TimestampKey, value: e.Time.UTC().Format(time.RFC3339Nano)},
+		{key: LevelKey, value: metadata.ParseString(e.Level)},
+		{key: ModuleKey, value: e.Module},
+	}
+
+	if e.Caller != "" {
+		kvs = append(kvs, keyValue{key: CallerKey, value: e.Caller})
+	}
+
+	kvs = append(kvs, keyValue{key: MessageKey, value: e.Message})
+
+	keys := make([]string, 0, len(e.Fields))
+
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		kvs = append(kvs, keyValue{key: k, value: e.Fields[k]})
+	}
+
+	return kvs
+}
+
+func marshalJSON(v interface{}) []byte {
+	src, err := json.Marshal(v)
+	if err != nil {
+		src, _ = json.Marshal(fmt.Sprintf("%v", v)) // nolint: errcheck
+	}
+
+	return src
+}
+
+func logfmtValue(v interface{}) string {
+	var s string
+
+	switch val := v.(type) {
+	case string:
+		s = val
+	case fmt.Stringer:
+		s = val.String()
+	default:
+		s = fmt.Sprintf("%v", val)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return string(marshalJSON(s))
+	}
+
+	return s
+}
+
+// Sampler limits the number of logged lines. Within every tick interval the first lines are logged,
+// after that only every thereafter-th line is logged.
+type Sampler struct {
+	first      int
+	thereafter int
+	tick       time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	resetAt time.Time
+	count   int
+}
+
+// NewSampler returns new sampler.
+// If thereafter is less than one no lines are logged after the first lines within the interval.
+func NewSampler(first, thereafter int, tick time.Duration) *Sampler {
+	return &Sampler{first: first, thereafter: thereafter, tick: tick, now: time.Now}
+}
+
+// Allow checks whether the next line should be logged.
+func (s *Sampler) Allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !now.Before(s.resetAt) {
+		s.resetAt = now.Add(s.tick)
+		s.count = 0
+	}
+
+	s.count++
+
+	if s.count <= s.first {
+		return true
+	}
+
+	return s.thereafter > 0 && (s.count-s.first)%s.thereafter == 0
+}
+
+// NewStructLog returns new StructLog instance based on given module.
+// Every line is written to the output with a single Write call.
+// The sampler is applied to DEBUG lines only and can be nil.
+func NewStructLog(module string, encode Encoder, output io.Writer, sampler *Sampler) *StructLog {
+	return &StructLog{
+		module:  module,
+		encode:  encode,
+		output:  output,
+		sampler: sampler,
+	}
+}
+
+// StructLog is a logger implementation which writes structured lines (e.g JSON or logfmt).
+// Each line contains timestamp, level, module, caller info (if enabled for the module and level),
+// message and the fields attached by WithFields.
+type StructLog struct {
+	module  string
+	encode  Encoder
+	output  io.Writer
+	sampler *Sampler
+	fields  map[string]interface{}
+}
+
+// Fatalf is CRITICAL log formatted followed by a call to os.Exit(1).
+func (l *StructLog) Fatalf(format string, args ...interface{}) {
+	l.logf(metadata.CRITICAL, format, args...)
+	os.Exit(1)
+}
+
+// Panicf is CRITICAL log formatted followed by a call to panic().
+func (l *StructLog) Panicf(format string, args ...interface{}) {
+	l.logf(metadata.CRITICAL, format, args...)
+	panic(fmt.Sprintf(format, args...))
+}
+
+// Debugf logs verbose messages, lines might be sampled.
+func (l *StructLog) Debugf(format string, args ...interface{}) {
+	if l.sampler != nil && !l.sampler.Allow() {
+		return
+	}
+
+	l.logf(metadata.DEBUG, format, args...)
+}
+
+// Infof logs general information messages.
+func (l *StructLog) Infof(format string, args ...interface{}) {
+	l.logf(metadata.INFO, format, args...)
+}
+
+// Warnf logs possible errors.
+func (l *StructLog) Warnf(format string, args ...interface{}) {
+	l.logf(metadata.WARNING, format, args...)
+}
+
+// Errorf logs errors.
+func (l *StructLog) Errorf(format string, args ...interface{}) {
+	l.logf(metadata.ERROR, format, args...)
+}
+
+// WithFields returns a copy of the logger which attaches given fields (merged with the existing ones)
+// to every line. The copy shares output and sampler with the original logger.
+func (l *StructLog) WithFields(fields map[string]interface{}) Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &StructLog{
+		module:  l.module,
+		encode:  l.encode,
+		output:  l.output,
+		sampler: l.sampler,
+		fields:  merged,
+	}
+}
+
+func (l *StructLog) logf(level metadata.Level, format string, args ...interface{}) {
+	entry := &Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Module:  l.module,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  l.fields,
+	}
+
+	if metadata.IsCallerInfoEnabled(l.module, level) {
+		entry.Caller = callerInfo()
+	}
+
+	if _, err := l.output.Write(append(l.encode(entry), '\n')); err != nil {
+		fmt.Printf("error from logger output %v\n", err)
+	}
+}
+
+// callerInfo returns 'package/file.go:line' of the first frame outside of the logging library.
+func callerInfo() string {
+	const (
+		maxCallers = 10
+		skip       = 3
+		notFound   = "n/a"
+	)
+
+	pcs := make([]uintptr, maxCallers)
+
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip, pcs)])
+
+	for {
+		f, more := frames.Next()
+
+		if !isLoggerFrame(f.Function) {
+			if f.File == "" {
+				return notFound
+			}
+
+			return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(f.File)), filepath.Base(f.File), f.Line)
+		}
+
+		if !more {
+			return notFound
+		}
+	}
+}
+
+func isLoggerFrame(fn string) bool {
+	_, name := filepath.Split(fn)
+
+	return strings.HasPrefix(name, "modlog.(*") || strings.HasPrefix(name, "log.(*Log)")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package modlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/logging/metadata"
+)
+
+func TestStructLog(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		const module = "sample-module-json"
+
+		var out bytes.Buffer
+
+		var logger Logger = NewModLog(NewStructLog(module, EncodeJSON, &out, nil), module)
+		logger = WithFields(logger, map[string]interface{}{"thid": "123", "count": 1})
+
+		metadata.SetLevel(module, metadata.INFO)
+		metadata.ShowCallerInfo(module, metadata.INFO)
+
+		logger.Infof(msgFormat, msgArg1, msgArg2)
+		logger.Debugf(msgFormat, msgArg1, msgArg2)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 1)
+
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+
+		require.Equal(t, "INFO", line[LevelKey])
+		require.Equal(t, module, line[ModuleKey])
+		require.Equal(t, "brown fox jumps over the lazy dog", line[MessageKey])
+		require.Equal(t, "123", line["thid"])
+		require.EqualValues(t, 1, line["count"])
+		require.Contains(t, line[CallerKey], "modlog/structlog_test.go:")
+		require.NotEmpty(t, line[TimestampKey])
+
+		require.True(t, strings.HasPrefix(lines[0], `{"ts":`))
+	})
+
+	t.Run("logfmt", func(t *testing.T) {
+		const module = "sample-module-logfmt"
+
+		var out bytes.Buffer
+
+		logger := NewStructLog(module, EncodeLogfmt, &out, nil).
+			WithFields(map[string]interface{}{"thid": "123", "state": "request sent", "empty": ""})
+
+		metadata.HideCallerInfo(module, metadata.WARNING)
+
+		logger.Warnf("a=%s", "b")
+
+		require.Regexp(t, `^ts=\S+ level=WARNING module=sample-module-logfmt msg="a=b" empty="" `+
+			`state="request sent" thid=123\n$`, out.String())
+	})
+
+	t.Run("all levels", func(t *testing.T) {
+		const module = "sample-module-all-levels"
+
+		var out bytes.Buffer
+
+		logger := NewStructLog(module, EncodeLogfmt, &out, nil)
+
+		logger.Debugf("debug")
+		logger.Infof("info")
+		logger.Warnf("warn")
+		logger.Errorf("error")
+		require.Panics(t, func() {
+			logger.Panicf("panic")
+		})
+
+		for _, level := range []string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"} {
+			require.Contains(t, out.String(), "level="+level)
+		}
+	})
+}
+
+func TestSampler(t *testing.T) {
+	now := time.Now()
+
+	sampler := NewSampler(2, 3, time.Second)
+	sampler.now = func() time.Time { return now }
+
+	var allowed []bool
+	for i := 0; i < 8; i++ {
+		allowed = append(allowed, sampler.Allow())
+	}
+
+	require.Equal(t, []bool{true, true, false, false, true, false, false, true}, allowed)
+
+	// next interval
+	now = now.Add(time.Second)
+
+	require.True(t, sampler.Allow())
+
+	t.Run("drop all after first", func(t *testing.T) {
+		sampler := NewSampler(1, 0, time.Minute)
+
+		require.True(t, sampler.Allow())
+		require.False(t, sampler.Allow())
+		require.False(t, sampler.Allow())
+	})
+
+	t.Run("sampled debug lines", func(t *testing.T) {
+		const module = "sample-module-sampled"
+
+		var out bytes.Buffer
+
+		metadata.SetLevel(module, metadata.DEBUG)
+
+		logger := NewModLog(NewStructLog(module, EncodeLogfmt, &out, NewSampler(1, 0, time.Minute)), module)
+		logger.Debugf("first")
+		logger.Debugf("second")
+		logger.Infof("info")
+
+		require.Contains(t, out.String(), "msg=first")
+		require.NotContains(t, out.String(), "msg=second")
+		require.Contains(t, out.String(), "msg=info")
+	})
+}
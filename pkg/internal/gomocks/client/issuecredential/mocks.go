@@ -115,6 +115,20 @@ func (mr *MockProtocolServiceMockRecorder) Actions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Actions", reflect.TypeOf((*MockProtocolService)(nil).Actions))
 }
 
+// DeleteProtocolInstance mocks base method
+func (m *MockProtocolService) DeleteProtocolInstance(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProtocolInstance", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProtocolInstance indicates an expected call of DeleteProtocolInstance
+func (mr *MockProtocolServiceMockRecorder) DeleteProtocolInstance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProtocolInstance", reflect.TypeOf((*MockProtocolService)(nil).DeleteProtocolInstance), arg0)
+}
+
 // HandleInbound mocks base method
 func (m *MockProtocolService) HandleInbound(arg0 service.DIDCommMsg, arg1, arg2 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +159,36 @@ func (mr *MockProtocolServiceMockRecorder) HandleOutbound(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleOutbound", reflect.TypeOf((*MockProtocolService)(nil).HandleOutbound), arg0, arg1, arg2)
 }
 
+// ProtocolInstance mocks base method
+func (m *MockProtocolService) ProtocolInstance(arg0 string) (*issuecredential.ProtocolInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtocolInstance", arg0)
+	ret0, _ := ret[0].(*issuecredential.ProtocolInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProtocolInstance indicates an expected call of ProtocolInstance
+func (mr *MockProtocolServiceMockRecorder) ProtocolInstance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtocolInstance", reflect.TypeOf((*MockProtocolService)(nil).ProtocolInstance), arg0)
+}
+
+// ProtocolInstances mocks base method
+func (m *MockProtocolService) ProtocolInstances() ([]*issuecredential.ProtocolInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtocolInstances")
+	ret0, _ := ret[0].([]*issuecredential.ProtocolInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProtocolInstances indicates an expected call of ProtocolInstances
+func (mr *MockProtocolServiceMockRecorder) ProtocolInstances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtocolInstances", reflect.TypeOf((*MockProtocolService)(nil).ProtocolInstances))
+}
+
 // RegisterActionEvent mocks base method
 func (m *MockProtocolService) RegisterActionEvent(arg0 chan<- service.DIDCommAction) error {
 	m.ctrl.T.Helper()
@@ -9,6 +9,7 @@ import (
 	service "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	outofband "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	kms "github.com/hyperledger/aries-framework-go/pkg/kms"
+	connection "github.com/hyperledger/aries-framework-go/pkg/store/connection"
 	reflect "reflect"
 )
 
@@ -102,18 +103,18 @@ func (m *MockOobService) EXPECT() *MockOobServiceMockRecorder {
 }
 
 // AcceptInvitation mocks base method
-func (m *MockOobService) AcceptInvitation(arg0 *outofband.Invitation, arg1 string, arg2 []string) (string, error) {
+func (m *MockOobService) AcceptInvitation(arg0 *outofband.Invitation, arg1 outofband.Options) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AcceptInvitation", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AcceptInvitation", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AcceptInvitation indicates an expected call of AcceptInvitation
-func (mr *MockOobServiceMockRecorder) AcceptInvitation(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockOobServiceMockRecorder) AcceptInvitation(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockOobService)(nil).AcceptInvitation), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockOobService)(nil).AcceptInvitation), arg0, arg1)
 }
 
 // AcceptRequest mocks base method
@@ -174,6 +175,21 @@ func (mr *MockOobServiceMockRecorder) Actions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Actions", reflect.TypeOf((*MockOobService)(nil).Actions))
 }
 
+// GetInvitationPolicy mocks base method
+func (m *MockOobService) GetInvitationPolicy(arg0 string) (*connection.InvitationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationPolicy", arg0)
+	ret0, _ := ret[0].(*connection.InvitationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationPolicy indicates an expected call of GetInvitationPolicy
+func (mr *MockOobServiceMockRecorder) GetInvitationPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationPolicy", reflect.TypeOf((*MockOobService)(nil).GetInvitationPolicy), arg0)
+}
+
 // RegisterActionEvent mocks base method
 func (m *MockOobService) RegisterActionEvent(arg0 chan<- service.DIDCommAction) error {
 	m.ctrl.T.Helper()
@@ -202,6 +218,20 @@ func (mr *MockOobServiceMockRecorder) RegisterMsgEvent(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMsgEvent", reflect.TypeOf((*MockOobService)(nil).RegisterMsgEvent), arg0)
 }
 
+// RevokeInvitation mocks base method
+func (m *MockOobService) RevokeInvitation(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeInvitation indicates an expected call of RevokeInvitation
+func (mr *MockOobServiceMockRecorder) RevokeInvitation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeInvitation", reflect.TypeOf((*MockOobService)(nil).RevokeInvitation), arg0)
+}
+
 // SaveInvitation mocks base method
 func (m *MockOobService) SaveInvitation(arg0 *outofband.Invitation) error {
 	m.ctrl.T.Helper()
@@ -216,6 +246,20 @@ func (mr *MockOobServiceMockRecorder) SaveInvitation(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveInvitation", reflect.TypeOf((*MockOobService)(nil).SaveInvitation), arg0)
 }
 
+// SaveInvitationPolicy mocks base method
+func (m *MockOobService) SaveInvitationPolicy(arg0 *connection.InvitationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveInvitationPolicy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveInvitationPolicy indicates an expected call of SaveInvitationPolicy
+func (mr *MockOobServiceMockRecorder) SaveInvitationPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveInvitationPolicy", reflect.TypeOf((*MockOobService)(nil).SaveInvitationPolicy), arg0)
+}
+
 // SaveRequest mocks base method
 func (m *MockOobService) SaveRequest(arg0 *outofband.Request) error {
 	m.ctrl.T.Helper()
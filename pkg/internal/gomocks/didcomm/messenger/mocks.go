@@ -7,6 +7,7 @@ package mocks
 import (
 	gomock "github.com/golang/mock/gomock"
 	dispatcher "github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	kms "github.com/hyperledger/aries-framework-go/pkg/kms"
 	storage "github.com/hyperledger/aries-framework-go/pkg/storage"
 	reflect "reflect"
 )
@@ -34,6 +35,20 @@ func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
 	return m.recorder
 }
 
+// KMS mocks base method
+func (m *MockProvider) KMS() kms.KeyManager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KMS")
+	ret0, _ := ret[0].(kms.KeyManager)
+	return ret0
+}
+
+// KMS indicates an expected call of KMS
+func (mr *MockProviderMockRecorder) KMS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KMS", reflect.TypeOf((*MockProvider)(nil).KMS))
+}
+
 // OutboundDispatcher mocks base method
 func (m *MockProvider) OutboundDispatcher() dispatcher.Outbound {
 	m.ctrl.T.Helper()
@@ -48,6 +63,20 @@ func (mr *MockProviderMockRecorder) OutboundDispatcher() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundDispatcher", reflect.TypeOf((*MockProvider)(nil).OutboundDispatcher))
 }
 
+// ServiceEndpoint mocks base method
+func (m *MockProvider) ServiceEndpoint() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServiceEndpoint")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ServiceEndpoint indicates an expected call of ServiceEndpoint
+func (mr *MockProviderMockRecorder) ServiceEndpoint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceEndpoint", reflect.TypeOf((*MockProvider)(nil).ServiceEndpoint))
+}
+
 // StorageProvider mocks base method
 func (m *MockProvider) StorageProvider() storage.Provider {
 	m.ctrl.T.Helper()
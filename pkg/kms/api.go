@@ -64,6 +64,17 @@ type KeyManager interface {
 	//  - handle instance (to private key)
 	//  - error if import failure (key empty, invalid, doesn't match keyType, unsupported keyType or storing key failed)
 	ImportPrivateKey(privKey interface{}, kt KeyType, opts ...PrivateKeyOpts) (string, interface{}, error)
+	// List returns the metadata of the keys managed by the KMS.
+	// 'opts' allows filtering the keys by type using WithKeyType() and by creation time using WithCreatedAfter()
+	// and WithCreatedBefore() options.
+	// Returns:
+	//  - metadata of the keys matching the filters
+	//  - error if failure
+	List(opts ...ListOpts) ([]KeyMetadata, error)
+	// Delete removes the key referenced by keyID from the KMS storage along with all its versions.
+	// Returns:
+	//  - error if the key is not found or deleting it failed
+	Delete(keyID string) error
 }
 
 // Provider for KeyManager builder/constructor.
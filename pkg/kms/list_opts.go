@@ -0,0 +1,84 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import "time"
+
+// listOpts holds the filters of List.
+type listOpts struct {
+	keyType       KeyType
+	createdAfter  *time.Time
+	createdBefore *time.Time
+}
+
+// NewListOpt creates a new empty list option.
+// Not to be used directly. It's intended for implementations of KeyManager interface
+// Use WithKeyType(), WithCreatedAfter() and WithCreatedBefore() option functions below instead.
+func NewListOpt() *listOpts { // nolint
+	return &listOpts{}
+}
+
+// KeyType gets the key type the listed keys are filtered by.
+// Not to be used directly. It's intended for implementations of KeyManager interface.
+func (lo *listOpts) KeyType() KeyType {
+	return lo.keyType
+}
+
+// CreatedAfter gets the time the listed keys must be created after.
+// Not to be used directly. It's intended for implementations of KeyManager interface.
+func (lo *listOpts) CreatedAfter() *time.Time {
+	return lo.createdAfter
+}
+
+// CreatedBefore gets the time the listed keys must be created before.
+// Not to be used directly. It's intended for implementations of KeyManager interface.
+func (lo *listOpts) CreatedBefore() *time.Time {
+	return lo.createdBefore
+}
+
+// Matches reports whether the key matches the filters, the keys of unknown type or creation time
+// don't match the corresponding filter.
+// Not to be used directly. It's intended for implementations of KeyManager interface.
+func (lo *listOpts) Matches(md *KeyMetadata) bool {
+	if lo.keyType != "" && md.KeyType != lo.keyType {
+		return false
+	}
+
+	if lo.createdAfter != nil && (md.Created.IsZero() || !md.Created.After(*lo.createdAfter)) {
+		return false
+	}
+
+	if lo.createdBefore != nil && (md.Created.IsZero() || !md.Created.Before(*lo.createdBefore)) {
+		return false
+	}
+
+	return true
+}
+
+// ListOpts are the List options.
+type ListOpts func(opts *listOpts)
+
+// WithKeyType option is for listing the keys of the given type only.
+func WithKeyType(kt KeyType) ListOpts {
+	return func(opts *listOpts) {
+		opts.keyType = kt
+	}
+}
+
+// WithCreatedAfter option is for listing the keys created after the given time only.
+func WithCreatedAfter(t time.Time) ListOpts {
+	return func(opts *listOpts) {
+		opts.createdAfter = &t
+	}
+}
+
+// WithCreatedBefore option is for listing the keys created before the given time only.
+func WithCreatedBefore(t time.Time) ListOpts {
+	return func(opts *listOpts) {
+		opts.createdBefore = &t
+	}
+}
@@ -0,0 +1,532 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// keyMetadataPrefix is the storage prefix of the key metadata records, it differs from the keysets' prefix.
+const keyMetadataPrefix = "m"
+
+// rotateVersionOpts holds options for RotateVersion.
+type rotateVersionOpts struct {
+	retireAfter *time.Duration
+}
+
+// RotateVersionOpts are the RotateVersion options.
+type RotateVersionOpts func(opts *rotateVersionOpts)
+
+// WithRetireAfter option schedules the retirement of the previous primary version, the version is disabled
+// once the duration has elapsed.
+func WithRetireAfter(d time.Duration) RotateVersionOpts {
+	return func(opts *rotateVersionOpts) {
+		opts.retireAfter = &d
+	}
+}
+
+// RotateVersion adds a new primary version to the key set referenced by keyID. Unlike Rotate, the key set keeps its
+// keyID and the previous versions remain available to verify and decrypt until they are disabled, destroyed or
+// retired. The new version has the key type the key set was created with.
+// Note: the keyID of an asymmetric key set remains the thumbprint of its first primary version.
+// Returns:
+//  - the new primary version
+//  - error if failure
+func (l *LocalKMS) RotateVersion(keyID string, opts ...RotateVersionOpts) (uint32, error) {
+	rOpts := &rotateVersionOpts{}
+
+	for _, opt := range opts {
+		opt(rOpts)
+	}
+
+	md, err := l.updateKeySet(keyID, func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error {
+		if md.KeyType == "" {
+			return fmt.Errorf("key type of key '%s' is unknown", keyID)
+		}
+
+		if rOpts.retireAfter != nil {
+			retireAt := time.Now().Add(*rOpts.retireAfter)
+
+			for i := range md.Versions {
+				if md.Versions[i].Version == ks.PrimaryKeyId {
+					md.Versions[i].RetireAt = &retireAt
+				}
+			}
+		}
+
+		return rotateKeyset(ks, md.KeyType)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rotateVersion: %w", err)
+	}
+
+	primary, _ := md.PrimaryVersion()
+
+	return primary.Version, nil
+}
+
+// KeyMetadata returns the metadata of the key set referenced by keyID along with its versions.
+func (l *LocalKMS) KeyMetadata(keyID string) (*kms.KeyMetadata, error) {
+	err := l.retireKeyVersions(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("keyMetadata: %w", err)
+	}
+
+	ks, err := l.readKeyset(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("keyMetadata: %w", err)
+	}
+
+	md, err := l.loadKeyMetadata(keyID, ks)
+	if err != nil {
+		return nil, fmt.Errorf("keyMetadata: %w", err)
+	}
+
+	return md, nil
+}
+
+// KeyVersions returns the versions of the key set referenced by keyID including the destroyed ones.
+func (l *LocalKMS) KeyVersions(keyID string) ([]kms.KeyVersion, error) {
+	md, err := l.KeyMetadata(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return md.Versions, nil
+}
+
+// SetPrimaryKeyVersion makes the enabled version of the key set referenced by keyID its primary version.
+func (l *LocalKMS) SetPrimaryKeyVersion(keyID string, version uint32) error {
+	_, err := l.updateKeySet(keyID, func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error {
+		key, err := findKeyVersion(ks, md, version)
+		if err != nil {
+			return err
+		}
+
+		if key.Status != tinkpb.KeyStatusType_ENABLED {
+			return fmt.Errorf("key version %d of key '%s' is not enabled", version, keyID)
+		}
+
+		ks.PrimaryKeyId = version
+		clearRetirement(md, version)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("setPrimaryKeyVersion: %w", err)
+	}
+
+	return nil
+}
+
+// EnableKeyVersion enables the disabled version of the key set referenced by keyID.
+func (l *LocalKMS) EnableKeyVersion(keyID string, version uint32) error {
+	_, err := l.updateKeySet(keyID, func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error {
+		key, err := findKeyVersion(ks, md, version)
+		if err != nil {
+			return err
+		}
+
+		key.Status = tinkpb.KeyStatusType_ENABLED
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("enableKeyVersion: %w", err)
+	}
+
+	return nil
+}
+
+// DisableKeyVersion disables the version of the key set referenced by keyID, the primary version can't be disabled.
+func (l *LocalKMS) DisableKeyVersion(keyID string, version uint32) error {
+	_, err := l.updateKeySet(keyID, func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error {
+		key, err := findKeyVersion(ks, md, version)
+		if err != nil {
+			return err
+		}
+
+		if version == ks.PrimaryKeyId {
+			return fmt.Errorf("key version %d of key '%s' is primary", version, keyID)
+		}
+
+		key.Status = tinkpb.KeyStatusType_DISABLED
+		clearRetirement(md, version)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("disableKeyVersion: %w", err)
+	}
+
+	return nil
+}
+
+// DestroyKeyVersion removes the key material of the version of the key set referenced by keyID, the version
+// remains listed as destroyed. The primary version can't be destroyed.
+func (l *LocalKMS) DestroyKeyVersion(keyID string, version uint32) error {
+	_, err := l.updateKeySet(keyID, func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error {
+		if _, err := findKeyVersion(ks, md, version); err != nil {
+			return err
+		}
+
+		if version == ks.PrimaryKeyId {
+			return fmt.Errorf("key version %d of key '%s' is primary", version, keyID)
+		}
+
+		for i, key := range ks.Key {
+			if key.KeyId == version {
+				ks.Key = append(ks.Key[:i], ks.Key[i+1:]...)
+
+				break
+			}
+		}
+
+		clearRetirement(md, version)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("destroyKeyVersion: %w", err)
+	}
+
+	return nil
+}
+
+// retireKeyVersions disables the versions of the key set referenced by keyID which are due to retire.
+func (l *LocalKMS) retireKeyVersions(keyID string) error {
+	md, err := l.getKeyMetadata(keyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if !retirementDue(md, now) {
+		return nil
+	}
+
+	_, err = l.updateKeySet(keyID, func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error {
+		for i := range md.Versions {
+			v := &md.Versions[i]
+			if v.RetireAt == nil || v.RetireAt.After(now) {
+				continue
+			}
+
+			v.RetireAt = nil
+
+			for _, key := range ks.Key {
+				if key.KeyId == v.Version && key.KeyId != ks.PrimaryKeyId {
+					key.Status = tinkpb.KeyStatusType_DISABLED
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retire key versions: %w", err)
+	}
+
+	return nil
+}
+
+func retirementDue(md *kms.KeyMetadata, now time.Time) bool {
+	for _, v := range md.Versions {
+		if v.RetireAt != nil && !v.RetireAt.After(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func clearRetirement(md *kms.KeyMetadata, version uint32) {
+	for i := range md.Versions {
+		if md.Versions[i].Version == version {
+			md.Versions[i].RetireAt = nil
+		}
+	}
+}
+
+// updateKeySet reads the keyset referenced by keyID and its metadata, applies the update and stores both back.
+func (l *LocalKMS) updateKeySet(keyID string,
+	update func(ks *tinkpb.Keyset, md *kms.KeyMetadata) error) (*kms.KeyMetadata, error) {
+	l.versionsLock.Lock()
+	defer l.versionsLock.Unlock()
+
+	ks, err := l.readKeyset(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := l.loadKeyMetadata(keyID, ks)
+	if err != nil {
+		return nil, err
+	}
+
+	err = update(ks, md)
+	if err != nil {
+		return nil, err
+	}
+
+	err = keyset.Validate(ks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyset: %w", err)
+	}
+
+	buf, err := l.encryptKeyset(ks)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.store.Put(keyID, buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store keyset: %w", err)
+	}
+
+	info, err := getKeysetInfo(ks)
+	if err != nil {
+		return nil, err
+	}
+
+	syncKeyVersions(md, info, time.Now())
+
+	err = l.putKeyMetadata(md)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store key metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+// readKeyset reads the keyset referenced by keyID and decrypts it with the primary key.
+func (l *LocalKMS) readKeyset(keyID string) (*tinkpb.Keyset, error) {
+	encrypted, err := keyset.NewJSONReader(newReader(l.store, keyID)).ReadEncrypted()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read json keyset from reader: %w", err)
+	}
+
+	serialized, err := l.primaryKeyEnvAEAD.Decrypt(encrypted.EncryptedKeyset, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keyset: %w", err)
+	}
+
+	ks := &tinkpb.Keyset{}
+
+	err = proto.Unmarshal(serialized, ks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal keyset: %w", err)
+	}
+
+	return ks, nil
+}
+
+// rotateKeyset adds a new primary key of type kt to the keyset.
+func rotateKeyset(ks *tinkpb.Keyset, kt kms.KeyType) error {
+	keyTemplate, err := getKeyTemplate(kt)
+	if err != nil {
+		return err
+	}
+
+	kh, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+	if err != nil {
+		return fmt.Errorf("failed to read keyset: %w", err)
+	}
+
+	km := keyset.NewManagerFromHandle(kh)
+
+	err = km.Rotate(keyTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to call Tink's keyManager rotate: %w", err)
+	}
+
+	rotatedKH, err := km.Handle()
+	if err != nil {
+		return fmt.Errorf("failed to get kms keyset handle: %w", err)
+	}
+
+	rotated := &keyset.MemReaderWriter{}
+
+	err = insecurecleartextkeyset.Write(rotatedKH, rotated)
+	if err != nil {
+		return fmt.Errorf("failed to write keyset: %w", err)
+	}
+
+	ks.Key = rotated.Keyset.Key
+	ks.PrimaryKeyId = rotated.Keyset.PrimaryKeyId
+
+	return nil
+}
+
+func findKeyVersion(ks *tinkpb.Keyset, md *kms.KeyMetadata, version uint32) (*tinkpb.Keyset_Key, error) {
+	for _, key := range ks.Key {
+		if key.KeyId == version {
+			return key, nil
+		}
+	}
+
+	for _, v := range md.Versions {
+		if v.Version == version && v.Status == kms.KeyStatusDestroyed {
+			return nil, fmt.Errorf("key version %d of key '%s' is destroyed", version, md.KeyID)
+		}
+	}
+
+	return nil, fmt.Errorf("key version %d of key '%s' not found", version, md.KeyID)
+}
+
+func keyMetadataKey(keyID string) string {
+	return keyMetadataPrefix + keyID
+}
+
+func (l *LocalKMS) getKeyMetadata(keyID string) (*kms.KeyMetadata, error) {
+	data, err := l.kmsStore.Get(keyMetadataKey(keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	md := &kms.KeyMetadata{}
+
+	err = json.Unmarshal(data, md)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+// loadKeyMetadata returns the stored metadata of the keyset, the keys stored before the metadata was introduced
+// get the metadata built from the keyset with unknown key type and creation time.
+func (l *LocalKMS) loadKeyMetadata(keyID string, ks *tinkpb.Keyset) (*kms.KeyMetadata, error) {
+	md, err := l.getKeyMetadata(keyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		info, e := getKeysetInfo(ks)
+		if e != nil {
+			return nil, e
+		}
+
+		return newKeyMetadata(keyID, "", info, time.Time{}), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+func (l *LocalKMS) putKeyMetadata(md *kms.KeyMetadata) error {
+	data, err := json.Marshal(md)
+	if err != nil {
+		return fmt.Errorf("failed to marshal key metadata: %w", err)
+	}
+
+	return l.kmsStore.Put(keyMetadataKey(md.KeyID), data)
+}
+
+// moveKeyMetadata stores the metadata of the keyset rotated by Rotate under its new keyID.
+func (l *LocalKMS) moveKeyMetadata(oldKeyID, newKeyID string, kt kms.KeyType, info *tinkpb.KeysetInfo) error {
+	now := time.Now()
+
+	md, err := l.getKeyMetadata(oldKeyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		md = &kms.KeyMetadata{Created: now}
+	} else if err != nil {
+		return err
+	}
+
+	md.KeyID = newKeyID
+	md.KeyType = kt
+
+	syncKeyVersions(md, info, now)
+
+	err = l.putKeyMetadata(md)
+	if err != nil || oldKeyID == newKeyID {
+		return err
+	}
+
+	return l.kmsStore.Delete(keyMetadataKey(oldKeyID))
+}
+
+func newKeyMetadata(keyID string, kt kms.KeyType, info *tinkpb.KeysetInfo, created time.Time) *kms.KeyMetadata {
+	md := &kms.KeyMetadata{
+		KeyID:   keyID,
+		KeyType: kt,
+		Created: created,
+	}
+
+	syncKeyVersions(md, info, created)
+
+	return md
+}
+
+// syncKeyVersions updates the status of the versions from the keyset info, the versions missing from the keyset
+// are destroyed and the new ones are created at the given time.
+func syncKeyVersions(md *kms.KeyMetadata, info *tinkpb.KeysetInfo, created time.Time) {
+	keys := make(map[uint32]*tinkpb.KeysetInfo_KeyInfo, len(info.KeyInfo))
+	for _, key := range info.KeyInfo {
+		keys[key.KeyId] = key
+	}
+
+	for i := range md.Versions {
+		v := &md.Versions[i]
+
+		key, ok := keys[v.Version]
+		if !ok {
+			v.Primary = false
+			v.Status = kms.KeyStatusDestroyed
+			v.RetireAt = nil
+
+			continue
+		}
+
+		delete(keys, v.Version)
+
+		v.Primary = key.KeyId == info.PrimaryKeyId
+		v.Status = keyStatus(key.Status)
+	}
+
+	// new versions are appended in the order of the keyset
+	for _, key := range info.KeyInfo {
+		if _, ok := keys[key.KeyId]; !ok {
+			continue
+		}
+
+		md.Versions = append(md.Versions, kms.KeyVersion{
+			Version: key.KeyId,
+			Primary: key.KeyId == info.PrimaryKeyId,
+			Status:  keyStatus(key.Status),
+			Created: created,
+		})
+	}
+}
+
+func keyStatus(status tinkpb.KeyStatusType) kms.KeyStatus {
+	switch status {
+	case tinkpb.KeyStatusType_ENABLED:
+		return kms.KeyStatusEnabled
+	case tinkpb.KeyStatusType_DESTROYED:
+		return kms.KeyStatusDestroyed
+	default:
+		return kms.KeyStatusDisabled
+	}
+}
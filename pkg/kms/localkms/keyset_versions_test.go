@@ -0,0 +1,321 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+)
+
+func newVersionsKMS(t *testing.T) (*LocalKMS, *mockstorage.MockStore) {
+	t.Helper()
+
+	store := &mockstorage.MockStore{Store: map[string][]byte{}}
+
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    &mockstorage.MockStoreProvider{Store: store},
+		secretLock: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	return kmsService, store
+}
+
+func sign(t *testing.T, l *LocalKMS, keyID string, msg []byte) []byte {
+	t.Helper()
+
+	kh, err := l.Get(keyID)
+	require.NoError(t, err)
+
+	signer, err := signature.NewSigner(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	sig, err := signer.Sign(msg)
+	require.NoError(t, err)
+
+	return sig
+}
+
+func verify(t *testing.T, l *LocalKMS, keyID string, msg, sig []byte) error {
+	t.Helper()
+
+	kh, err := l.Get(keyID)
+	require.NoError(t, err)
+
+	pubKH, err := kh.(*keyset.Handle).Public()
+	require.NoError(t, err)
+
+	verifier, err := signature.NewVerifier(pubKH)
+	require.NoError(t, err)
+
+	return verifier.Verify(sig, msg)
+}
+
+func TestLocalKMS_RotateVersion(t *testing.T) {
+	msg := []byte("message")
+
+	t.Run("old versions remain available to verify", func(t *testing.T) {
+		kmsService, _ := newVersionsKMS(t)
+
+		keyID, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		md, err := kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+		require.Equal(t, keyID, md.KeyID)
+		require.Equal(t, kms.ED25519Type, md.KeyType)
+		require.False(t, md.Created.IsZero())
+		require.Len(t, md.Versions, 1)
+
+		first, ok := md.PrimaryVersion()
+		require.True(t, ok)
+		require.Equal(t, kms.KeyStatusEnabled, first.Status)
+
+		oldSig := sign(t, kmsService, keyID, msg)
+
+		version, err := kmsService.RotateVersion(keyID)
+		require.NoError(t, err)
+		require.NotEqual(t, first.Version, version)
+
+		versions, err := kmsService.KeyVersions(keyID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, first.Version, versions[0].Version)
+		require.False(t, versions[0].Primary)
+		require.Equal(t, kms.KeyStatusEnabled, versions[0].Status)
+		require.Equal(t, version, versions[1].Version)
+		require.True(t, versions[1].Primary)
+
+		// the key set keeps its ID, the new primary signs and the old version still verifies
+		newSig := sign(t, kmsService, keyID, msg)
+		require.NoError(t, verify(t, kmsService, keyID, msg, newSig))
+		require.NoError(t, verify(t, kmsService, keyID, msg, oldSig))
+
+		require.NoError(t, kmsService.DisableKeyVersion(keyID, first.Version))
+		require.Error(t, verify(t, kmsService, keyID, msg, oldSig))
+
+		require.NoError(t, kmsService.EnableKeyVersion(keyID, first.Version))
+		require.NoError(t, verify(t, kmsService, keyID, msg, oldSig))
+
+		require.NoError(t, kmsService.DestroyKeyVersion(keyID, first.Version))
+		require.Error(t, verify(t, kmsService, keyID, msg, oldSig))
+		require.NoError(t, verify(t, kmsService, keyID, msg, newSig))
+
+		versions, err = kmsService.KeyVersions(keyID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, kms.KeyStatusDestroyed, versions[0].Status)
+
+		err = kmsService.EnableKeyVersion(keyID, first.Version)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is destroyed")
+	})
+
+	t.Run("scheduled retirement of the previous primary", func(t *testing.T) {
+		kmsService, _ := newVersionsKMS(t)
+
+		keyID, _, err := kmsService.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		first, err := kmsService.RotateVersion(keyID, WithRetireAfter(time.Hour))
+		require.NoError(t, err)
+
+		md, err := kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+		require.NotNil(t, md.Versions[0].RetireAt)
+		require.Equal(t, kms.KeyStatusEnabled, md.Versions[0].Status)
+
+		_, err = kmsService.RotateVersion(keyID, WithRetireAfter(0))
+		require.NoError(t, err)
+
+		_, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		md, err = kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+		require.Len(t, md.Versions, 3)
+		require.NotNil(t, md.Versions[0].RetireAt)
+		require.Equal(t, kms.KeyStatusEnabled, md.Versions[0].Status)
+		require.Equal(t, first, md.Versions[1].Version)
+		require.Nil(t, md.Versions[1].RetireAt)
+		require.Equal(t, kms.KeyStatusDisabled, md.Versions[1].Status)
+		require.True(t, md.Versions[2].Primary)
+
+		// the primary selection cancels the retirement
+		require.NoError(t, kmsService.SetPrimaryKeyVersion(keyID, md.Versions[0].Version))
+
+		md, err = kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+		require.True(t, md.Versions[0].Primary)
+		require.Nil(t, md.Versions[0].RetireAt)
+	})
+
+	t.Run("primary version selection and its restrictions", func(t *testing.T) {
+		kmsService, _ := newVersionsKMS(t)
+
+		keyID, _, err := kmsService.Create(kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		versions, err := kmsService.KeyVersions(keyID)
+		require.NoError(t, err)
+
+		first := versions[0].Version
+
+		second, err := kmsService.RotateVersion(keyID)
+		require.NoError(t, err)
+
+		err = kmsService.DisableKeyVersion(keyID, second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is primary")
+
+		err = kmsService.DestroyKeyVersion(keyID, second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is primary")
+
+		err = kmsService.SetPrimaryKeyVersion(keyID, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key version 1 of key '"+keyID+"' not found")
+
+		require.NoError(t, kmsService.DisableKeyVersion(keyID, first))
+
+		err = kmsService.SetPrimaryKeyVersion(keyID, first)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not enabled")
+
+		require.NoError(t, kmsService.EnableKeyVersion(keyID, first))
+		require.NoError(t, kmsService.SetPrimaryKeyVersion(keyID, first))
+
+		pubKey, err := kmsService.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.NotEmpty(t, pubKey)
+	})
+
+	t.Run("Rotate moves the metadata to the new key ID", func(t *testing.T) {
+		kmsService, store := newVersionsKMS(t)
+
+		keyID, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		created, err := kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+
+		newKeyID, _, err := kmsService.Rotate(kms.ED25519Type, keyID)
+		require.NoError(t, err)
+
+		md, err := kmsService.KeyMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Equal(t, newKeyID, md.KeyID)
+		require.Equal(t, created.Created.Unix(), md.Created.Unix())
+		require.Len(t, md.Versions, 2)
+
+		require.NotContains(t, store.Store, keyMetadataKey(keyID))
+	})
+
+	t.Run("keys without metadata", func(t *testing.T) {
+		kmsService, store := newVersionsKMS(t)
+
+		keyID, _, err := kmsService.Create(kms.AES128GCMType)
+		require.NoError(t, err)
+
+		delete(store.Store, keyMetadataKey(keyID))
+
+		md, err := kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+		require.Empty(t, md.KeyType)
+		require.True(t, md.Created.IsZero())
+		require.Len(t, md.Versions, 1)
+
+		_, err = kmsService.RotateVersion(keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type of key '"+keyID+"' is unknown")
+
+		// the key set versions can be managed nevertheless
+		second, _, err := kmsService.Rotate(kms.AES128GCMType, keyID)
+		require.NoError(t, err)
+
+		versions, err := kmsService.KeyVersions(second)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.NoError(t, kmsService.DisableKeyVersion(second, versions[0].Version))
+	})
+
+	t.Run("imported keys get metadata", func(t *testing.T) {
+		kmsService, _ := newVersionsKMS(t)
+
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keyID, _, err := kmsService.ImportPrivateKey(privKey, kms.ED25519Type, kms.WithKeyID("imported"))
+		require.NoError(t, err)
+
+		md, err := kmsService.KeyMetadata(keyID)
+		require.NoError(t, err)
+		require.Equal(t, "imported", md.KeyID)
+		require.Equal(t, kms.ED25519Type, md.KeyType)
+		require.Len(t, md.Versions, 1)
+
+		_, err = kmsService.RotateVersion(keyID)
+		require.NoError(t, err)
+	})
+}
+
+func TestLocalKMS_KeyVersions_Failure(t *testing.T) {
+	t.Run("unknown key", func(t *testing.T) {
+		kmsService, _ := newVersionsKMS(t)
+
+		_, err := kmsService.KeyMetadata("unknown")
+		require.Error(t, err)
+
+		_, err = kmsService.KeyVersions("unknown")
+		require.Error(t, err)
+
+		_, err = kmsService.RotateVersion("unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rotateVersion")
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		kmsService, store := newVersionsKMS(t)
+
+		keyID, _, err := kmsService.Create(kms.AES128GCMType)
+		require.NoError(t, err)
+
+		store.Store[keyMetadataKey(keyID)] = []byte("{")
+
+		_, err = kmsService.Get(keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal key metadata")
+
+		_, err = kmsService.KeyMetadata(keyID)
+		require.Error(t, err)
+
+		delete(store.Store, keyMetadataKey(keyID))
+
+		errPut := errors.New("put error")
+		store.ErrPut = errPut
+
+		err = kmsService.DisableKeyVersion(keyID, 1)
+		require.Error(t, err)
+
+		_, err = kmsService.RotateVersion(keyID)
+		require.Error(t, err)
+
+		_, _, err = kmsService.Create(kms.AES128GCMType)
+		require.True(t, errors.Is(err, errPut))
+	})
+}
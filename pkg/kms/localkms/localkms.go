@@ -13,6 +13,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/aead"
@@ -52,21 +56,23 @@ type LocalKMS struct {
 	secretLock        secretlock.Service
 	primaryKeyURI     string
 	store             storage.Store
+	kmsStore          storage.Store // unprefixed store holding both the key sets and their metadata
 	primaryKeyEnvAEAD *aead.KMSEnvelopeAEAD
+	versionsLock      sync.Mutex
 }
 
-func newKeyIDWrapperStore(provider storage.Provider) (storage.Store, error) {
-	s, err := provider.OpenStore(Namespace)
-	if err != nil {
-		return nil, err
-	}
-
+func newKeyIDWrapperStore(s storage.Store) (storage.Store, error) {
 	return prefix.NewPrefixStoreWrapper(s, prefix.StorageKIDPrefix)
 }
 
 // New will create a new (local) KMS service.
 func New(primaryKeyURI string, p kms.Provider) (*LocalKMS, error) {
-	store, err := newKeyIDWrapperStore(p.StorageProvider())
+	s, err := p.StorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("new: failed to ceate local kms: %w", err)
+	}
+
+	store, err := newKeyIDWrapperStore(s)
 	if err != nil {
 		return nil, fmt.Errorf("new: failed to ceate local kms: %w", err)
 	}
@@ -83,6 +89,7 @@ func New(primaryKeyURI string, p kms.Provider) (*LocalKMS, error) {
 
 	return &LocalKMS{
 			store:             store,
+			kmsStore:          s,
 			secretLock:        secretLock,
 			primaryKeyURI:     primaryKeyURI,
 			primaryKeyEnvAEAD: keyEnvelopeAEAD,
@@ -115,14 +122,24 @@ func (l *LocalKMS) Create(kt kms.KeyType) (string, interface{}, error) {
 		return "", nil, fmt.Errorf("create: failed to store keyset: %w", err)
 	}
 
+	err = l.putKeyMetadata(newKeyMetadata(kID, kt, kh.KeysetInfo(), time.Now()))
+	if err != nil {
+		return "", nil, fmt.Errorf("create: failed to store key metadata: %w", err)
+	}
+
 	return kID, kh, nil
 }
 
-// Get key handle for the given keyID
+// Get key handle for the given keyID. The versions of the key set which are due to retire are disabled first.
 // Returns:
 //  - handle instance (to private key)
 //  - error if failure
 func (l *LocalKMS) Get(keyID string) (interface{}, error) {
+	err := l.retireKeyVersions(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
 	return l.getKeySet(keyID)
 }
 
@@ -165,6 +182,11 @@ func (l *LocalKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, er
 		return "", nil, fmt.Errorf("rotate: failed to store keySet: %w", err)
 	}
 
+	err = l.moveKeyMetadata(keyID, newID, kt, updatedKH.KeysetInfo())
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: failed to store key metadata: %w", err)
+	}
+
 	return newID, updatedKH, nil
 }
 
@@ -379,14 +401,109 @@ func (l *LocalKMS) PubKeyBytesToHandle(pubKey []byte, kt kms.KeyType) (interface
 //  - error if import failure (key empty, invalid, doesn't match keyType, unsupported keyType or storing key failed)
 func (l *LocalKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
 	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	var (
+		ksID string
+		kh   *keyset.Handle
+		err  error
+	)
+
 	switch pk := privKey.(type) {
 	case *ecdsa.PrivateKey:
-		return l.importECDSAKey(pk, kt, opts...)
+		ksID, kh, err = l.importECDSAKey(pk, kt, opts...)
 	case ed25519.PrivateKey:
-		return l.importEd25519Key(pk, kt, opts...)
+		ksID, kh, err = l.importEd25519Key(pk, kt, opts...)
 	default:
 		return "", nil, fmt.Errorf("import private key does not support this key type or key is public")
 	}
+
+	if err != nil {
+		return ksID, kh, err
+	}
+
+	err = l.putKeyMetadata(newKeyMetadata(ksID, kt, kh.KeysetInfo(), time.Now()))
+	if err != nil {
+		return ksID, kh, fmt.Errorf("import private key successful but failed to store key metadata: %w", err)
+	}
+
+	return ksID, kh, nil
+}
+
+// List returns the metadata of the keys stored in the KMS sorted by keyID. The keys stored before the metadata was
+// introduced have an unknown key type and creation time, they are listed only if no filter applies to them.
+// Returns:
+//  - metadata of the keys matching the filters set in opts
+//  - error if failure
+func (l *LocalKMS) List(opts ...kms.ListOpts) ([]kms.KeyMetadata, error) {
+	lOpts := kms.NewListOpt()
+
+	for _, opt := range opts {
+		opt(lOpts)
+	}
+
+	keyIDs, err := l.listKeyIDs()
+	if err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
+
+	var keys []kms.KeyMetadata
+
+	for _, keyID := range keyIDs {
+		md, err := l.KeyMetadata(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("list: %w", err)
+		}
+
+		if lOpts.Matches(md) {
+			keys = append(keys, *md)
+		}
+	}
+
+	return keys, nil
+}
+
+// listKeyIDs returns the sorted IDs of the key sets stored in the KMS.
+func (l *LocalKMS) listKeyIDs() ([]string, error) {
+	itr := l.kmsStore.Iterator(prefix.StorageKIDPrefix, prefix.StorageKIDPrefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	var keyIDs []string
+
+	for itr.Next() {
+		keyIDs = append(keyIDs, strings.TrimPrefix(string(itr.Key()), prefix.StorageKIDPrefix))
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over key sets: %w", err)
+	}
+
+	sort.Strings(keyIDs)
+
+	return keyIDs, nil
+}
+
+// Delete removes the key set referenced by keyID along with all its versions and its metadata.
+// Returns:
+//  - error if the key is not found or failure
+func (l *LocalKMS) Delete(keyID string) error {
+	l.versionsLock.Lock()
+	defer l.versionsLock.Unlock()
+
+	_, err := l.store.Get(keyID)
+	if err != nil {
+		return fmt.Errorf("delete: failed to get key set '%s': %w", keyID, err)
+	}
+
+	err = l.store.Delete(keyID)
+	if err != nil {
+		return fmt.Errorf("delete: failed to delete key set '%s': %w", keyID, err)
+	}
+
+	err = l.kmsStore.Delete(keyMetadataKey(keyID))
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delete: failed to delete metadata of key '%s': %w", keyID, err)
+	}
+
+	return nil
 }
 
 func (l *LocalKMS) generateKID(kh *keyset.Handle, kt kms.KeyType) (string, error) {
@@ -20,6 +20,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/subtle/random"
@@ -545,3 +546,106 @@ func (m *mockProvider) StorageProvider() storage.Provider {
 func (m *mockProvider) SecretLock() secretlock.Service {
 	return m.secretLock
 }
+
+func TestLocalKMS_List(t *testing.T) {
+	kmsService, store := newVersionsKMS(t)
+
+	keys, err := kmsService.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	edKeyID, _, err := kmsService.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	between := time.Now()
+
+	time.Sleep(time.Millisecond)
+
+	aesKeyID, _, err := kmsService.Create(kms.AES128GCMType)
+	require.NoError(t, err)
+
+	legacyKeyID, _, err := kmsService.Create(kms.AES256GCMType)
+	require.NoError(t, err)
+
+	delete(store.Store, keyMetadataKey(legacyKeyID))
+
+	keys, err = kmsService.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	for i := 1; i < len(keys); i++ {
+		require.Less(t, keys[i-1].KeyID, keys[i].KeyID)
+	}
+
+	keys, err = kmsService.List(kms.WithKeyType(kms.ED25519Type))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, edKeyID, keys[0].KeyID)
+	require.Len(t, keys[0].Versions, 1)
+
+	keys, err = kmsService.List(kms.WithCreatedAfter(between))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, aesKeyID, keys[0].KeyID)
+
+	keys, err = kmsService.List(kms.WithCreatedBefore(between), kms.WithKeyType(kms.AES128GCMType))
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	t.Run("iterator error", func(t *testing.T) {
+		store.ErrItr = errors.New("iterator error")
+		defer func() { store.ErrItr = nil }()
+
+		_, err = kmsService.List()
+		require.EqualError(t, err, "list: failed to iterate over key sets: iterator error")
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		store.Store[keyMetadataKey(edKeyID)] = []byte("{")
+		defer delete(store.Store, keyMetadataKey(edKeyID))
+
+		_, err = kmsService.List()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal key metadata")
+	})
+}
+
+func TestLocalKMS_Delete(t *testing.T) {
+	kmsService, store := newVersionsKMS(t)
+
+	keyID, _, err := kmsService.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = kmsService.RotateVersion(keyID)
+	require.NoError(t, err)
+
+	require.NoError(t, kmsService.Delete(keyID))
+	require.Empty(t, store.Store)
+
+	_, err = kmsService.Get(keyID)
+	require.Error(t, err)
+
+	err = kmsService.Delete(keyID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	t.Run("keys without metadata", func(t *testing.T) {
+		keyID, _, err = kmsService.Create(kms.AES128GCMType)
+		require.NoError(t, err)
+
+		delete(store.Store, keyMetadataKey(keyID))
+
+		require.NoError(t, kmsService.Delete(keyID))
+		require.Empty(t, store.Store)
+	})
+
+	t.Run("store delete error", func(t *testing.T) {
+		keyID, _, err = kmsService.Create(kms.AES128GCMType)
+		require.NoError(t, err)
+
+		store.ErrDelete = errors.New("delete error")
+		defer func() { store.ErrDelete = nil }()
+
+		err = kmsService.Delete(keyID)
+		require.EqualError(t, err, "delete: failed to delete key set '"+keyID+"': delete error")
+	})
+}
@@ -179,19 +179,29 @@ func newProtoEd25519PrivateKey(privateKey ed25519.PrivateKey) (*ed25519pb.Ed2551
 }
 
 func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpts) (string, error) {
+	buf, err := l.encryptKeyset(ks)
+	if err != nil {
+		return "", err
+	}
+
+	return writeToStore(l.store, buf, opts...)
+}
+
+// encryptKeyset encrypts the keyset with the primary key and returns it marshalled as JSON.
+func (l *LocalKMS) encryptKeyset(ks *tinkpb.Keyset) (*bytes.Buffer, error) {
 	serializedKeyset, err := proto.Marshal(ks)
 	if err != nil {
-		return "", fmt.Errorf("invalid keyset data")
+		return nil, fmt.Errorf("invalid keyset data")
 	}
 
 	encrypted, err := l.primaryKeyEnvAEAD.Encrypt(serializedKeyset, []byte{})
 	if err != nil {
-		return "", fmt.Errorf("encrypted failed: %w", err)
+		return nil, fmt.Errorf("encrypted failed: %w", err)
 	}
 
 	ksInfo, err := getKeysetInfo(ks)
 	if err != nil {
-		return "", fmt.Errorf("cannot get keyset info: %w", err)
+		return nil, fmt.Errorf("cannot get keyset info: %w", err)
 	}
 
 	encryptedKeyset := &tinkpb.EncryptedKeyset{
@@ -204,10 +214,10 @@ func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpt
 
 	err = jsonKeysetWriter.WriteEncrypted(encryptedKeyset)
 	if err != nil {
-		return "", fmt.Errorf("failed to write keyset as json: %w", err)
+		return nil, fmt.Errorf("failed to write keyset as json: %w", err)
 	}
 
-	return writeToStore(l.store, buf, opts...)
+	return buf, nil
 }
 
 func getKeysetInfo(ks *tinkpb.Keyset) (*tinkpb.KeysetInfo, error) {
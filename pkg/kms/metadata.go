@@ -0,0 +1,50 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import "time"
+
+// KeyStatus is the status of a version of a key set.
+type KeyStatus string
+
+const (
+	// KeyStatusEnabled the key version can be used by the crypto primitives.
+	KeyStatusEnabled = KeyStatus("enabled")
+	// KeyStatusDisabled the key version is kept in the key set but can't be used until it is enabled again.
+	KeyStatusDisabled = KeyStatus("disabled")
+	// KeyStatusDestroyed the key material of the version was removed from the key set.
+	KeyStatusDestroyed = KeyStatus("destroyed")
+)
+
+// KeyVersion describes a version of a key set. The primary version is used to sign and encrypt,
+// the other enabled versions remain available to verify and decrypt.
+type KeyVersion struct {
+	Version  uint32     `json:"version"`
+	Primary  bool       `json:"primary,omitempty"`
+	Status   KeyStatus  `json:"status"`
+	Created  time.Time  `json:"created"`
+	RetireAt *time.Time `json:"retireAt,omitempty"`
+}
+
+// KeyMetadata describes a key set managed by the KMS.
+type KeyMetadata struct {
+	KeyID    string       `json:"keyID"`
+	KeyType  KeyType      `json:"keyType,omitempty"`
+	Created  time.Time    `json:"created"`
+	Versions []KeyVersion `json:"versions"`
+}
+
+// PrimaryVersion returns the primary version of the key set.
+func (m *KeyMetadata) PrimaryVersion() (KeyVersion, bool) {
+	for _, v := range m.Versions {
+		if v.Primary {
+			return v, true
+		}
+	}
+
+	return KeyVersion{}, false
+}
@@ -46,6 +46,10 @@ type exportKeyResp struct {
 	KeyBytes string `json:"keyid,omitempty"`
 }
 
+type listKeysResp struct {
+	Keys []kms.KeyMetadata `json:"keys"`
+}
+
 type marshalFunc func(interface{}) ([]byte, error)
 
 type unmarshalFunc func([]byte, interface{}) error
@@ -129,6 +133,10 @@ func (r *RemoteKMS) getHTTPRequest(destination string) (*http.Response, error) {
 	return r.doHTTPRequest(http.MethodGet, destination, nil)
 }
 
+func (r *RemoteKMS) deleteHTTPRequest(destination string) (*http.Response, error) {
+	return r.doHTTPRequest(http.MethodDelete, destination, nil)
+}
+
 func (r *RemoteKMS) doHTTPRequest(method, destination string, mReq []byte) (*http.Response, error) {
 	httpReq, err := http.NewRequest(method, destination, bytes.NewBuffer(mReq))
 	if err != nil {
@@ -281,6 +289,81 @@ func (r *RemoteKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
 	return "", nil, errors.New("function ImportPrivateKey is not implemented in remoteKMS")
 }
 
+// List remotely fetches the metadata of the keys of the keystore. The filters set in opts are applied to the
+// fetched keys.
+// Returns:
+//  - metadata of the keys matching the filters
+//  - error if failure
+func (r *RemoteKMS) List(opts ...kms.ListOpts) ([]kms.KeyMetadata, error) {
+	lOpts := kms.NewListOpt()
+
+	for _, opt := range opts {
+		opt(lOpts)
+	}
+
+	destination := r.keystoreURL + "/keys"
+
+	resp, err := r.getHTTPRequest(destination)
+	if err != nil {
+		return nil, fmt.Errorf("get List keys failed [%s, %w]", destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, logger, "List")
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read keys response for List failed [%s, %w]", destination, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get List keys failed [%s, status: %d, %s]", destination, resp.StatusCode, respBody)
+	}
+
+	httpResp := &listKeysResp{}
+
+	err = r.unmarshalFunc(respBody, httpResp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal keys for List failed [%s, %w]", destination, err)
+	}
+
+	var keys []kms.KeyMetadata
+
+	for i := range httpResp.Keys {
+		if lOpts.Matches(&httpResp.Keys[i]) {
+			keys = append(keys, httpResp.Keys[i])
+		}
+	}
+
+	return keys, nil
+}
+
+// Delete remotely removes the key referenced by keyID from the keystore.
+// Returns:
+//  - error if the key is not found or failure
+func (r *RemoteKMS) Delete(keyID string) error {
+	destination := r.buildKIDURL(keyID)
+
+	resp, err := r.deleteHTTPRequest(destination)
+	if err != nil {
+		return fmt.Errorf("delete key failed [%s, %w]", destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, logger, "Delete")
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read response for Delete failed [%s, %w]", destination, err)
+		}
+
+		return fmt.Errorf("delete key failed [%s, status: %d, %s]", destination, resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
 // closeResponseBody closes the response body.
 //nolint: interfacer // don't want to add test stretcher logger here
 func closeResponseBody(respBody io.Closer, logger log.Logger, action string) {
@@ -211,6 +211,97 @@ func TestRemoteKeyStoreWithHeadersFunc(t *testing.T) {
 	})
 }
 
+func TestRemoteKeyStore_ListAndDelete(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	keys := []kms.KeyMetadata{
+		{KeyID: "key1", KeyType: kms.ED25519Type, Created: created},
+		{KeyID: "key2", KeyType: kms.AES128GCMType, Created: created.Add(time.Minute)},
+	}
+
+	var deleted []string
+
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/12345/keys"):
+			mResp, err := json.Marshal(&listKeysResp{Keys: keys})
+			require.NoError(t, err)
+
+			_, err = w.Write(mResp)
+			require.NoError(t, err)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/invalid/keys"):
+			_, err := w.Write([]byte("{"))
+			require.NoError(t, err)
+		case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/keys/key1"):
+			deleted = append(deleted, "key1")
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "key not found", http.StatusNotFound)
+		}
+	})
+
+	server, url, client := CreateMockHTTPServerAndClient(t, hf)
+
+	defer func() {
+		e := server.Close()
+		require.NoError(t, e)
+	}()
+
+	keystoreURL := url + "/kms/keystores/12345"
+	remoteKMS := New(keystoreURL, client)
+
+	t.Run("List success", func(t *testing.T) {
+		list, err := remoteKMS.List()
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "key1", list[0].KeyID)
+		require.Equal(t, "key2", list[1].KeyID)
+
+		list, err = remoteKMS.List(kms.WithKeyType(kms.AES128GCMType))
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "key2", list[0].KeyID)
+
+		list, err = remoteKMS.List(kms.WithCreatedBefore(created.Add(time.Second)))
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "key1", list[0].KeyID)
+
+		list, err = remoteKMS.List(kms.WithCreatedAfter(time.Now()))
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+
+	t.Run("List failures", func(t *testing.T) {
+		_, err := New(keystoreURL, &http.Client{}).List()
+		require.Contains(t, err.Error(), "get List keys failed")
+
+		_, err = New(url+"/kms/keystores/invalid", client).List()
+		require.Contains(t, err.Error(), "unmarshal keys for List failed")
+
+		_, err = New(url+"/kms/keystores/unknown", client).List()
+		require.Contains(t, err.Error(), "status: 404")
+
+		remoteKMS2 := New(keystoreURL, client)
+		remoteKMS2.unmarshalFunc = failingUnmarshal
+
+		_, err = remoteKMS2.List()
+		require.Contains(t, err.Error(), "failingUnmarshal always fails")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, remoteKMS.Delete("key1"))
+		require.Equal(t, []string{"key1"}, deleted)
+
+		err := remoteKMS.Delete("key3")
+		require.Contains(t, err.Error(), "delete key failed")
+		require.Contains(t, err.Error(), "status: 404")
+
+		err = New(keystoreURL, &http.Client{}).Delete("key1")
+		require.Contains(t, err.Error(), "delete key failed")
+	})
+}
+
 func TestCloseResponseBody(t *testing.T) {
 	closeResponseBody(&errFailingCloser{}, logger, "testing close fail should log: errFailingCloser always fails")
 }
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+)
+
+// MockActionMenuSvc mock action menu service.
+type MockActionMenuSvc struct {
+	service.Action
+	service.Message
+	ProtocolName       string
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) (string, error)
+	AcceptFunc         func(string) bool
+	SendMenuFunc       func(connectionID string, menu *actionmenu.Menu) (string, error)
+	RequestMenuFunc    func(connectionID string) (string, error)
+	ActiveMenuFunc     func(connectionID string) (*actionmenu.Menu, error)
+	PerformFunc        func(connectionID, name string, params map[string]string) (string, error)
+	ActionsFunc        func() ([]actionmenu.Action, error)
+	ActionContinueFunc func(piID string, opt actionmenu.Opt) error
+	ActionStopFunc     func(piID string, err error) error
+}
+
+// HandleInbound msg.
+func (m *MockActionMenuSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockActionMenuSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockActionMenuSvc) Accept(msgType string) bool {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(msgType)
+	}
+
+	return true
+}
+
+// Name return service name.
+func (m *MockActionMenuSvc) Name() string {
+	if m.ProtocolName != "" {
+		return m.ProtocolName
+	}
+
+	return actionmenu.ActionMenu
+}
+
+// SendMenu sends the menu.
+func (m *MockActionMenuSvc) SendMenu(connectionID string, menu *actionmenu.Menu) (string, error) {
+	if m.SendMenuFunc != nil {
+		return m.SendMenuFunc(connectionID, menu)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// RequestMenu requests the menu.
+func (m *MockActionMenuSvc) RequestMenu(connectionID string) (string, error) {
+	if m.RequestMenuFunc != nil {
+		return m.RequestMenuFunc(connectionID)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// ActiveMenu returns the active menu.
+func (m *MockActionMenuSvc) ActiveMenu(connectionID string) (*actionmenu.Menu, error) {
+	if m.ActiveMenuFunc != nil {
+		return m.ActiveMenuFunc(connectionID)
+	}
+
+	return &actionmenu.Menu{}, nil
+}
+
+// Perform performs the menu option.
+func (m *MockActionMenuSvc) Perform(connectionID, name string, params map[string]string) (string, error) {
+	if m.PerformFunc != nil {
+		return m.PerformFunc(connectionID, name, params)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// Actions returns pending actions.
+func (m *MockActionMenuSvc) Actions() ([]actionmenu.Action, error) {
+	if m.ActionsFunc != nil {
+		return m.ActionsFunc()
+	}
+
+	return nil, nil
+}
+
+// ActionContinue continues the action.
+func (m *MockActionMenuSvc) ActionContinue(piID string, opt actionmenu.Opt) error {
+	if m.ActionContinueFunc != nil {
+		return m.ActionContinueFunc(piID, opt)
+	}
+
+	return nil
+}
+
+// ActionStop stops the action.
+func (m *MockActionMenuSvc) ActionStop(piID string, err error) error {
+	if m.ActionStopFunc != nil {
+		return m.ActionStopFunc(piID, err)
+	}
+
+	return nil
+}
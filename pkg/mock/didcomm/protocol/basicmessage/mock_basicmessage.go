@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/basicmessage"
+)
+
+// MockBasicMessageSvc mock basic message service.
+type MockBasicMessageSvc struct {
+	service.Action
+	service.Message
+	ProtocolName       string
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) (string, error)
+	AcceptFunc         func(string) bool
+	SendMessageFunc    func(connectionID string, msg *basicmessage.Message) (string, error)
+	HistoryFunc        func(params *basicmessage.QueryParams) (*basicmessage.QueryResult, error)
+	DeleteHistoryFunc  func(connectionID string) error
+}
+
+// HandleInbound msg.
+func (m *MockBasicMessageSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockBasicMessageSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockBasicMessageSvc) Accept(msgType string) bool {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(msgType)
+	}
+
+	return true
+}
+
+// Name return service name.
+func (m *MockBasicMessageSvc) Name() string {
+	if m.ProtocolName != "" {
+		return m.ProtocolName
+	}
+
+	return basicmessage.BasicMessage
+}
+
+// SendMessage sends the message.
+func (m *MockBasicMessageSvc) SendMessage(connectionID string, msg *basicmessage.Message) (string, error) {
+	if m.SendMessageFunc != nil {
+		return m.SendMessageFunc(connectionID, msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// History returns the message history.
+func (m *MockBasicMessageSvc) History(params *basicmessage.QueryParams) (*basicmessage.QueryResult, error) {
+	if m.HistoryFunc != nil {
+		return m.HistoryFunc(params)
+	}
+
+	return &basicmessage.QueryResult{}, nil
+}
+
+// DeleteHistory deletes the message history of the connection.
+func (m *MockBasicMessageSvc) DeleteHistory(connectionID string) error {
+	if m.DeleteHistoryFunc != nil {
+		return m.DeleteHistoryFunc(connectionID)
+	}
+
+	return nil
+}
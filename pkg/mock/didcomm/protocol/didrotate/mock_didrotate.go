@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+)
+
+// MockDIDRotateSvc mock DID rotate service.
+type MockDIDRotateSvc struct {
+	service.Action
+	service.Message
+	ProtocolName       string
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) (string, error)
+	AcceptFunc         func(string) bool
+	RotateDIDFunc      func(connectionID, newDID string, routerConnections []string) (string, error)
+}
+
+// HandleInbound msg.
+func (m *MockDIDRotateSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockDIDRotateSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockDIDRotateSvc) Accept(msgType string) bool {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(msgType)
+	}
+
+	return true
+}
+
+// Name return service name.
+func (m *MockDIDRotateSvc) Name() string {
+	if m.ProtocolName != "" {
+		return m.ProtocolName
+	}
+
+	return didrotate.DIDRotate
+}
+
+// RotateDID replaces my DID on the connection.
+func (m *MockDIDRotateSvc) RotateDID(connectionID, newDID string, routerConnections []string) (string, error) {
+	if m.RotateDIDFunc != nil {
+		return m.RotateDIDFunc(connectionID, newDID, routerConnections)
+	}
+
+	return uuid.New().String(), nil
+}
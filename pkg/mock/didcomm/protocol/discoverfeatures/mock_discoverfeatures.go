@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+)
+
+// MockDiscoverFeaturesSvc mock discover-features service.
+type MockDiscoverFeaturesSvc struct {
+	service.Action
+	service.Message
+	ProtocolName       string
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) (string, error)
+	AcceptFunc         func(string) bool
+	QueryFunc          func(connectionID, query string, options ...discoverfeatures.ClientOption) ([]discoverfeatures.ProtocolDescriptor, error) // nolint: lll
+	FeaturesFunc       func(query string) ([]discoverfeatures.ProtocolDescriptor, error)
+	DisclosurePolicy   discoverfeatures.DisclosurePolicy
+}
+
+// HandleInbound msg.
+func (m *MockDiscoverFeaturesSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockDiscoverFeaturesSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockDiscoverFeaturesSvc) Accept(msgType string) bool {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(msgType)
+	}
+
+	return true
+}
+
+// Name return service name.
+func (m *MockDiscoverFeaturesSvc) Name() string {
+	if m.ProtocolName != "" {
+		return m.ProtocolName
+	}
+
+	return discoverfeatures.Name
+}
+
+// Query asks the other agent which protocols it supports.
+func (m *MockDiscoverFeaturesSvc) Query(connectionID, query string,
+	options ...discoverfeatures.ClientOption) ([]discoverfeatures.ProtocolDescriptor, error) {
+	if m.QueryFunc != nil {
+		return m.QueryFunc(connectionID, query, options...)
+	}
+
+	return nil, nil
+}
+
+// Features returns the protocols supported by the agent.
+func (m *MockDiscoverFeaturesSvc) Features(query string) ([]discoverfeatures.ProtocolDescriptor, error) {
+	if m.FeaturesFunc != nil {
+		return m.FeaturesFunc(query)
+	}
+
+	return nil, nil
+}
+
+// SetDisclosurePolicy sets the disclosure policy.
+func (m *MockDiscoverFeaturesSvc) SetDisclosurePolicy(policy discoverfeatures.DisclosurePolicy) {
+	m.DisclosurePolicy = policy
+}
@@ -8,11 +8,12 @@ package outofband
 import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 // MockOobService is a mock of OobService interface.
 type MockOobService struct {
-	AcceptInvitationHandle      func(*outofband.Invitation, string, []string) (string, error)
+	AcceptInvitationHandle      func(*outofband.Invitation, outofband.Options) (string, error)
 	AcceptRequestHandle         func(*outofband.Request, string, []string) (string, error)
 	ActionContinueHandle        func(string, outofband.Options) error
 	ActionStopHandle            func(string, error) error
@@ -20,15 +21,18 @@ type MockOobService struct {
 	RegisterActionEventHandle   func(chan<- service.DIDCommAction) error
 	RegisterMsgEventHandle      func(chan<- service.StateMsg) error
 	SaveInvitationHandle        func(*outofband.Invitation) error
+	SaveInvitationPolicyHandle  func(*connection.InvitationPolicy) error
+	GetInvitationPolicyHandle   func(string) (*connection.InvitationPolicy, error)
+	RevokeInvitationHandle      func(string) error
 	SaveRequestHandle           func(*outofband.Request) error
 	UnregisterActionEventHandle func(chan<- service.DIDCommAction) error
 	UnregisterMsgEventHandle    func(chan<- service.StateMsg) error
 }
 
 // AcceptInvitation mock implementation.
-func (m *MockOobService) AcceptInvitation(arg0 *outofband.Invitation, arg1 string, arg2 []string) (string, error) {
+func (m *MockOobService) AcceptInvitation(arg0 *outofband.Invitation, arg1 outofband.Options) (string, error) {
 	if m.AcceptInvitationHandle != nil {
-		return m.AcceptInvitationHandle(arg0, arg1, arg2)
+		return m.AcceptInvitationHandle(arg0, arg1)
 	}
 
 	return "", nil
@@ -97,6 +101,33 @@ func (m *MockOobService) SaveInvitation(arg0 *outofband.Invitation) error {
 	return nil
 }
 
+// SaveInvitationPolicy mock implementation.
+func (m *MockOobService) SaveInvitationPolicy(arg0 *connection.InvitationPolicy) error {
+	if m.SaveInvitationPolicyHandle != nil {
+		return m.SaveInvitationPolicyHandle(arg0)
+	}
+
+	return nil
+}
+
+// GetInvitationPolicy mock implementation.
+func (m *MockOobService) GetInvitationPolicy(arg0 string) (*connection.InvitationPolicy, error) {
+	if m.GetInvitationPolicyHandle != nil {
+		return m.GetInvitationPolicyHandle(arg0)
+	}
+
+	return &connection.InvitationPolicy{InvitationID: arg0}, nil
+}
+
+// RevokeInvitation mock implementation.
+func (m *MockOobService) RevokeInvitation(arg0 string) error {
+	if m.RevokeInvitationHandle != nil {
+		return m.RevokeInvitationHandle(arg0)
+	}
+
+	return nil
+}
+
 // SaveRequest mock implementation.
 func (m *MockOobService) SaveRequest(arg0 *outofband.Request) error {
 	if m.SaveRequestHandle != nil {
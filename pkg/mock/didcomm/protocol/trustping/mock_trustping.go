@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+)
+
+// MockTrustPingSvc mock trust ping service.
+type MockTrustPingSvc struct {
+	service.Action
+	service.Message
+	ProtocolName       string
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) (string, error)
+	AcceptFunc         func(string) bool
+	PingFunc           func(connectionID string, options ...trustping.ClientOption) (*trustping.Result, error)
+}
+
+// HandleInbound msg.
+func (m *MockTrustPingSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockTrustPingSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockTrustPingSvc) Accept(msgType string) bool {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(msgType)
+	}
+
+	return true
+}
+
+// Name return service name.
+func (m *MockTrustPingSvc) Name() string {
+	if m.ProtocolName != "" {
+		return m.ProtocolName
+	}
+
+	return trustping.TrustPing
+}
+
+// Ping pings the agent on the other end of the connection.
+func (m *MockTrustPingSvc) Ping(connectionID string, options ...trustping.ClientOption) (*trustping.Result, error) {
+	if m.PingFunc != nil {
+		return m.PingFunc(connectionID, options...)
+	}
+
+	return &trustping.Result{}, nil
+}
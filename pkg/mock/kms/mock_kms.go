@@ -39,6 +39,9 @@ type KeyManager struct {
 	ImportPrivateKeyErr      error
 	ImportPrivateKeyID       string
 	ImportPrivateKeyValue    *keyset.Handle
+	ListValue                []kmsservice.KeyMetadata
+	ListErr                  error
+	DeleteErr                error
 }
 
 // Create a new mock ey/keyset/key handle for the type kt.
@@ -105,6 +108,20 @@ func (k *KeyManager) ImportPrivateKey(privKey interface{}, keyType kmsservice.Ke
 	return k.ImportPrivateKeyID, k.ImportPrivateKeyValue, nil
 }
 
+// List returns the mocked metadata of the keys.
+func (k *KeyManager) List(opts ...kmsservice.ListOpts) ([]kmsservice.KeyMetadata, error) {
+	if k.ListErr != nil {
+		return nil, k.ListErr
+	}
+
+	return k.ListValue, nil
+}
+
+// Delete will emulate deleting a key.
+func (k *KeyManager) Delete(keyID string) error {
+	return k.DeleteErr
+}
+
 func createMockKeyHandle(ks *tinkpb.Keyset) (*keyset.Handle, error) {
 	primaryKey := ks.Key[0]
 
@@ -8,6 +8,7 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -115,12 +116,19 @@ func (s *MockStore) Iterator(start, limit string) storage.StoreIterator {
 
 	var batch [][]string
 
+	end := strings.TrimSuffix(limit, storage.EndKeySuffix)
+
 	for k, v := range s.Store {
-		if strings.HasPrefix(k, start) {
-			batch = append(batch, []string{k, string(v)})
+		if !strings.HasPrefix(k, start) && (limit == "" || k < start || k >= end && !strings.HasPrefix(k, end)) {
+			continue
 		}
+
+		batch = append(batch, []string{k, string(v)})
 	}
 
+	// the records are iterated in the order of the keys like the stores do
+	sort.Slice(batch, func(i, j int) bool { return batch[i][0] < batch[j][0] })
+
 	return NewMockIterator(batch)
 }
 
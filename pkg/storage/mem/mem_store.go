@@ -145,33 +145,14 @@ func (s *memStore) Iterator(start, limit string) storage.StoreIterator {
 
 	sort.Strings(keys)
 
-	var (
-		sIDx, eIDx = -1, len(keys)
-		skip       bool
-	)
-
-	for i, k := range keys {
-		if !skip && strings.HasPrefix(k, start) {
-			sIDx = i
-			skip = true
-		}
-
-		if strings.HasPrefix(k, strings.TrimSuffix(limit, storage.EndKeySuffix)) {
-			eIDx = i
+	end := strings.TrimSuffix(limit, storage.EndKeySuffix)
 
-			if limit == k {
-				break
-			}
-
-			eIDx++
+	for _, k := range keys {
+		// the keys starting with the end of the range are in the range, except the end itself
+		if k < start || (k >= end && (!strings.HasPrefix(k, end) || k == limit)) {
+			continue
 		}
-	}
-
-	if sIDx == -1 {
-		return NewMemIterator(nil, nil)
-	}
 
-	for _, k := range keys[sIDx:eIDx] {
 		batch = append(batch, []string{k, string(data[k])})
 	}
 
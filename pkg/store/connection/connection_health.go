@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const healthKeyPrefix = "connhealth"
+
+// Health is the liveness of the connection recorded by the trust ping protocol. It is kept apart from
+// the connection record, the pings neither race with the updates of the record nor change its UpdatedTime.
+type Health struct {
+	ConnectionID string
+	// LastSeen is the time the last trust ping or ping response was received over the connection.
+	LastSeen *time.Time `json:",omitempty"`
+	// RoundTripTime is the round-trip time of the last trust ping sent over the connection.
+	RoundTripTime time.Duration `json:",omitempty"`
+}
+
+// SaveConnectionHealth saves the liveness of the connection.
+func (c *Recorder) SaveConnectionHealth(health *Health) error {
+	if health.ConnectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return marshalAndSave(getHealthKeyPrefix()(health.ConnectionID), health, c.store)
+}
+
+// GetConnectionHealth returns the liveness of the connection, storage.ErrDataNotFound is returned
+// if the connection was never seen alive.
+func (c *Lookup) GetConnectionHealth(connectionID string) (*Health, error) {
+	if connectionID == "" {
+		return nil, fmt.Errorf(errMsgInvalidKey)
+	}
+
+	var health Health
+
+	err := getAndUnmarshal(getHealthKeyPrefix()(connectionID), &health, c.store)
+	if err != nil {
+		return nil, fmt.Errorf("get connection health: %w", err)
+	}
+
+	return &health, nil
+}
+
+// getHealthKeyPrefix key prefix for the connection liveness persisted.
+func getHealthKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, healthKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
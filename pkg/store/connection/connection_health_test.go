@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+func TestConnectionRecorder_ConnectionHealth(t *testing.T) {
+	t.Run("save, get and remove along with the connection", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		record := &Record{
+			ThreadID:     threadIDValue,
+			ConnectionID: uuid.New().String(),
+			State:        StateNameCompleted,
+			Namespace:    TheirNSPrefix,
+			MyDID:        "did:mydid:123",
+			TheirDID:     "did:theirdid:123",
+		}
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+
+		_, err = recorder.GetConnectionHealth(record.ConnectionID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		lastSeen := time.Now().UTC()
+
+		require.NoError(t, recorder.SaveConnectionHealth(&Health{
+			ConnectionID:  record.ConnectionID,
+			LastSeen:      &lastSeen,
+			RoundTripTime: time.Millisecond,
+		}))
+
+		health, err := recorder.GetConnectionHealth(record.ConnectionID)
+		require.NoError(t, err)
+		require.True(t, lastSeen.Equal(*health.LastSeen))
+		require.Equal(t, time.Millisecond, health.RoundTripTime)
+
+		// the health is not part of the connection record
+		records, err := recorder.QueryConnectionRecords()
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+
+		require.NoError(t, recorder.RemoveConnection(record.ConnectionID))
+
+		_, err = recorder.GetConnectionHealth(record.ConnectionID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("invalid connection ID", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.EqualError(t, recorder.SaveConnectionHealth(&Health{}), errMsgInvalidKey)
+
+		_, err = recorder.GetConnectionHealth("")
+		require.EqualError(t, err, errMsgInvalidKey)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -57,6 +58,46 @@ type Record struct {
 	InvitationDID   string
 	Implicit        bool
 	Namespace       string
+	// MyDIDRotation is set once MyDID was rotated (did-rotate protocol).
+	MyDIDRotation *DIDRotation `json:",omitempty"`
+	// TheirDIDRotation is set once TheirDID was rotated (did-rotate protocol).
+	TheirDIDRotation *DIDRotation `json:",omitempty"`
+	// Metadata is arbitrary data of the application (e.g alias, notes or business IDs).
+	Metadata map[string]string `json:",omitempty"`
+	// Tags are labels of the application used to filter the connections.
+	Tags []string `json:",omitempty"`
+	// CreatedTime is the time the connection record was first saved.
+	CreatedTime *time.Time `json:",omitempty"`
+	// UpdatedTime is the time the connection record was last saved.
+	UpdatedTime *time.Time `json:",omitempty"`
+}
+
+// HasTags returns true if the record has all the given tags.
+func (r *Record) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		found := false
+
+		for _, t := range r.Tags {
+			if t == tag {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DIDRotation describes the DID replaced by the did-rotate protocol. The connection is still found by
+// the previous DID until the grace period expires.
+type DIDRotation struct {
+	PreviousDID string
+	Expires     time.Time
 }
 
 // NewLookup returns new connection lookup instance.
@@ -103,7 +144,12 @@ func (c *Lookup) GetConnectionRecord(connectionID string) (*Record, error) {
 // QueryConnectionRecords returns connection records found in underlying store
 // for given query criteria.
 func (c *Lookup) QueryConnectionRecords() ([]*Record, error) {
-	// TODO https://github.com/hyperledger/aries-framework-go/issues/655 query criteria to be added as part of issue
+	return c.queryRecords(func(*Record) bool { return true })
+}
+
+// queryRecords returns the connection records matching the filter, the records of the permanent store take
+// precedence over the records of the protocol state store.
+func (c *Lookup) queryRecords(match func(*Record) bool) ([]*Record, error) {
 	searchKey := getConnectionKeyPrefix()("")
 
 	itr := c.store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
@@ -123,7 +169,9 @@ func (c *Lookup) QueryConnectionRecords() ([]*Record, error) {
 
 		keys[string(itr.Key())] = struct{}{}
 
-		records = append(records, &record)
+		if match(&record) {
+			records = append(records, &record)
+		}
 	}
 
 	protocolStateItr := c.protocolStateStore.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
@@ -141,7 +189,9 @@ func (c *Lookup) QueryConnectionRecords() ([]*Record, error) {
 			return nil, fmt.Errorf("query connection records from protocol state store : %w", err)
 		}
 
-		records = append(records, &record)
+		if match(&record) {
+			records = append(records, &record)
+		}
 	}
 
 	return records, nil
@@ -421,3 +421,13 @@ func (p *mockProvider) StorageProvider() storage.Provider {
 
 	return mockstorage.NewMockStoreProvider()
 }
+
+func TestRecord_HasTags(t *testing.T) {
+	record := &Record{Tags: []string{"customer", "gold"}}
+
+	require.True(t, record.HasTags())
+	require.True(t, record.HasTags("gold"))
+	require.True(t, record.HasTags("customer", "gold"))
+	require.False(t, record.HasTags("customer", "silver"))
+	require.False(t, (&Record{}).HasTags("gold"))
+}
@@ -0,0 +1,371 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Sort orders of the connection query.
+const (
+	// SortByConnectionID sorts the connection records by connection ID (default).
+	SortByConnectionID = "id"
+	// SortByCreatedTime sorts the connection records by the time they were created, the records without
+	// the time are first (last in descending order).
+	SortByCreatedTime = "created"
+	// SortByUpdatedTime sorts the connection records by the time they were last updated, the records without
+	// the time are first (last in descending order).
+	SortByUpdatedTime = "updated"
+)
+
+const (
+	connIndexKeyPrefix = "connidx"
+	// connIndexVersionKey is saved once the records saved before the index existed are indexed.
+	connIndexVersionKey = "connidxversion"
+	connIndexVersion    = "1"
+)
+
+// Indexed filters and sort directions of the connection query.
+const (
+	indexAscending    = "asc"
+	indexDescending   = "desc"
+	indexAll          = "all"
+	indexState        = "state"
+	indexMyDID        = "mydid"
+	indexTheirDID     = "theirdid"
+	indexInvitationID = "inv"
+	indexNamespace    = "ns"
+	indexTag          = "tag"
+)
+
+// QueryParams holds the filters, the sort order and the page of the connection query.
+// Empty filters match all connection records.
+type QueryParams struct {
+	State        string
+	MyDID        string
+	TheirDID     string
+	InvitationID string
+	Namespace    string
+	// LabelPrefix matches the records whose TheirLabel starts with the prefix.
+	LabelPrefix string
+	// Tags matches the records having all the tags.
+	Tags []string
+	// SortBy is one of SortByConnectionID, SortByCreatedTime and SortByUpdatedTime.
+	SortBy     string
+	Descending bool
+	// Limit is the maximum number of records of the page, all the records are returned if not set.
+	Limit int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+}
+
+// QueryResult is the page of connection records.
+type QueryResult struct {
+	Records []*Record
+	// NextCursor is the cursor of the next page, it is empty on the last page.
+	NextCursor string
+}
+
+// cursor is the position of the last record of the page in the sort order.
+type cursor struct {
+	Time         int64  `json:"t,omitempty"`
+	ConnectionID string `json:"id"`
+}
+
+// QueryConnections returns the page of the connection records matching the query parameters.
+// The records are read through the index of the filter and the sort order (see SaveConnectionRecord), the
+// query starts at the position of the cursor and stops once the page is full.
+func (c *Lookup) QueryConnections(params *QueryParams) (*QueryResult, error) {
+	sortBy := params.SortBy
+	if sortBy == "" {
+		sortBy = SortByConnectionID
+	}
+
+	if sortBy != SortByConnectionID && sortBy != SortByCreatedTime && sortBy != SortByUpdatedTime {
+		return nil, fmt.Errorf("unsupported sort order %s", params.SortBy)
+	}
+
+	if params.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", params.Limit)
+	}
+
+	page := &queryPage{lookup: c, params: params, sortBy: sortBy, filter: params.indexFilter()}
+
+	if params.Cursor != "" {
+		after, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		page.after = page.key(after)
+	}
+
+	if err := page.read(); err != nil {
+		return nil, err
+	}
+
+	result := &QueryResult{Records: page.records}
+
+	if params.Limit > 0 && len(page.records) > params.Limit {
+		result.Records = page.records[:params.Limit]
+		result.NextCursor = encodeCursor(position(result.Records[params.Limit-1], sortBy))
+	}
+
+	return result, nil
+}
+
+// queryPage reads the records of the page from the index entries of the query.
+type queryPage struct {
+	lookup  *Lookup
+	params  *QueryParams
+	sortBy  string
+	filter  string
+	after   string
+	records []*Record
+}
+
+// key returns the index key of the position in the sort order of the query.
+func (p *queryPage) key(c *cursor) string {
+	return getConnectionIndexKeyPrefix()(p.sortBy, indexDirection(p.params.Descending), p.filter,
+		indexPosition(c, p.sortBy, p.params.Descending))
+}
+
+// read reads the index entries of the query from the position of the cursor, the index of the descending
+// order is separate so the store iterates both orders from the cursor.
+func (p *queryPage) read() error {
+	prefix := getConnectionIndexKeyPrefix()(p.sortBy, indexDirection(p.params.Descending), p.filter, "")
+
+	start := prefix
+	if p.after != "" {
+		start = p.after
+	}
+
+	itr := p.lookup.store.Iterator(start, prefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	for itr.Next() {
+		key := string(itr.Key())
+
+		if key == p.after {
+			continue
+		}
+
+		full, err := p.add(key, string(itr.Value()))
+		if err != nil || full {
+			return err
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return fmt.Errorf("iterate connection index: %w", err)
+	}
+
+	return nil
+}
+
+// add adds the connection record of the index entry to the page if the record matches the query, it returns
+// true once the page holds one record more than the limit. The entries left by records which are gone (e.g
+// the protocol state store is not persistent) or were saved since the entry was read are skipped.
+func (p *queryPage) add(key, connectionID string) (bool, error) {
+	record, err := p.lookup.GetConnectionRecord(connectionID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("get connection record %s: %w", connectionID, err)
+	}
+
+	if !hasIndexKey(record, key) || !p.params.matches(record) {
+		return false, nil
+	}
+
+	p.records = append(p.records, record)
+
+	return p.params.Limit > 0 && len(p.records) > p.params.Limit, nil
+}
+
+func (p *QueryParams) matches(record *Record) bool {
+	return (p.State == "" || p.State == record.State) &&
+		(p.MyDID == "" || p.MyDID == record.MyDID) &&
+		(p.TheirDID == "" || p.TheirDID == record.TheirDID) &&
+		(p.InvitationID == "" || p.InvitationID == record.InvitationID) &&
+		(p.Namespace == "" || p.Namespace == record.Namespace) &&
+		strings.HasPrefix(record.TheirLabel, p.LabelPrefix) &&
+		record.HasTags(p.Tags...)
+}
+
+// indexFilter returns the indexed filter the query reads, the other filters are matched on the records.
+func (p *QueryParams) indexFilter() string {
+	switch {
+	case p.TheirDID != "":
+		return indexFilter(indexTheirDID, p.TheirDID)
+	case p.MyDID != "":
+		return indexFilter(indexMyDID, p.MyDID)
+	case p.InvitationID != "":
+		return indexFilter(indexInvitationID, p.InvitationID)
+	case len(p.Tags) != 0:
+		return indexFilter(indexTag, p.Tags[0])
+	case p.State != "":
+		return indexFilter(indexState, p.State)
+	case p.Namespace != "":
+		return indexFilter(indexNamespace, p.Namespace)
+	default:
+		return indexAll
+	}
+}
+
+// position returns the position of the record in the sort order. The records without the time (saved
+// before the times were recorded) are at time zero, they are first in ascending order and last in
+// descending order, ordered by the connection ID.
+func position(record *Record, sortBy string) *cursor {
+	var t *time.Time
+
+	switch sortBy {
+	case SortByCreatedTime:
+		t = record.CreatedTime
+	case SortByUpdatedTime:
+		t = record.UpdatedTime
+	}
+
+	c := &cursor{ConnectionID: record.ConnectionID}
+
+	if t != nil {
+		c.Time = t.UnixNano()
+	}
+
+	return c
+}
+
+// indexPosition returns the part of the index key which orders the records, the time is zero padded so that
+// the keys sort like the times. In descending order the time and the connection ID are inverted.
+func indexPosition(c *cursor, sortBy string, descending bool) string {
+	t, connectionID := c.Time, c.ConnectionID
+
+	if descending {
+		t, connectionID = math.MaxInt64-t, invertedID(connectionID)
+	}
+
+	if sortBy == SortByConnectionID {
+		return connectionID
+	}
+
+	return fmt.Sprintf("%020d%s%s", t, keySeparator, connectionID)
+}
+
+// invertedID returns the hex encoded complement of the connection ID terminated by 0xff, the inverted IDs
+// sort in the reverse order of the IDs (an ID sorts after the IDs it is a prefix of).
+func invertedID(connectionID string) string {
+	inverted := make([]byte, len(connectionID)+1)
+
+	for i := 0; i < len(connectionID); i++ {
+		inverted[i] = ^connectionID[i]
+	}
+
+	inverted[len(connectionID)] = 0xff
+
+	return hex.EncodeToString(inverted)
+}
+
+// indexDirection returns the part of the index key of the sort direction.
+func indexDirection(descending bool) string {
+	if descending {
+		return indexDescending
+	}
+
+	return indexAscending
+}
+
+// indexFilter returns the indexed filter of the field value, the value is hex encoded so that it does not
+// contain the key separator.
+func indexFilter(field, value string) string {
+	return field + "-" + hex.EncodeToString([]byte(value))
+}
+
+// indexKeys returns the index keys of the record, the record is indexed by each of its filter values for
+// each sort order in both directions.
+func indexKeys(record *Record) []string {
+	filters := []string{indexAll}
+
+	add := func(field, value string) {
+		if value != "" {
+			filters = append(filters, indexFilter(field, value))
+		}
+	}
+
+	add(indexState, record.State)
+	add(indexMyDID, record.MyDID)
+	add(indexTheirDID, record.TheirDID)
+	add(indexInvitationID, record.InvitationID)
+	add(indexNamespace, record.Namespace)
+
+	for _, tag := range record.Tags {
+		add(indexTag, tag)
+	}
+
+	var keys []string
+
+	for _, sortBy := range []string{SortByConnectionID, SortByCreatedTime, SortByUpdatedTime} {
+		for _, descending := range []bool{false, true} {
+			for _, filter := range filters {
+				keys = append(keys, getConnectionIndexKeyPrefix()(sortBy, indexDirection(descending), filter,
+					indexPosition(position(record, sortBy), sortBy, descending)))
+			}
+		}
+	}
+
+	return keys
+}
+
+// hasIndexKey returns true if the key is one of the index keys of the record.
+func hasIndexKey(record *Record, key string) bool {
+	for _, k := range indexKeys(record) {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getConnectionIndexKeyPrefix key prefix for the index entries of the connection records.
+func getConnectionIndexKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, connIndexKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
+func encodeCursor(c *cursor) string {
+	// nolint: errcheck
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	c := &cursor{}
+
+	if err = json.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return c, nil
+}
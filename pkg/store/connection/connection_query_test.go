@@ -0,0 +1,344 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestLookup_QueryConnections(t *testing.T) {
+	const count = 6
+
+	created := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	newLookup := func(t *testing.T) *Lookup {
+		t.Helper()
+
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+		protocolStateStore, err := mem.NewProvider().OpenStore(Namespace)
+		require.NoError(t, err)
+
+		for i := 0; i < count; i++ {
+			createdTime := created.Add(time.Duration(count-i) * time.Hour)
+			updatedTime := created.Add(time.Duration(i) * time.Hour)
+
+			record := &Record{
+				ConnectionID: fmt.Sprint(i),
+				State:        StateNameCompleted,
+				TheirLabel:   fmt.Sprintf("bob-%d", i),
+				InvitationID: "inv-1",
+				Namespace:    MyNSPrefix,
+				CreatedTime:  &createdTime,
+				UpdatedTime:  &updatedTime,
+			}
+
+			if i%2 == 1 {
+				record.State = "requested"
+				record.TheirLabel = fmt.Sprintf("alice-%d", i)
+				record.InvitationID = "inv-2"
+				record.Namespace = TheirNSPrefix
+			}
+
+			val, jsonErr := json.Marshal(record)
+			require.NoError(t, jsonErr)
+
+			if record.State == StateNameCompleted {
+				require.NoError(t, store.Put(getConnectionKeyPrefix()(record.ConnectionID), val))
+			} else {
+				require.NoError(t, protocolStateStore.Put(getConnectionKeyPrefix()(record.ConnectionID), val))
+			}
+		}
+
+		// the records saved before the index existed are indexed by the recorder
+		recorder, err := NewRecorder(&mockProvider{store: store, protocolStateStore: protocolStateStore})
+		require.NoError(t, err)
+
+		return recorder.Lookup
+	}
+
+	ids := func(records []*Record) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.ConnectionID)
+		}
+
+		return result
+	}
+
+	t.Run("test query all connections", func(t *testing.T) {
+		result, err := newLookup(t).QueryConnections(&QueryParams{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "2", "3", "4", "5"}, ids(result.Records))
+		require.Empty(t, result.NextCursor)
+	})
+
+	t.Run("test query connections with filters", func(t *testing.T) {
+		lookup := newLookup(t)
+
+		result, err := lookup.QueryConnections(&QueryParams{State: StateNameCompleted})
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "2", "4"}, ids(result.Records))
+
+		result, err = lookup.QueryConnections(&QueryParams{LabelPrefix: "alice"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "3", "5"}, ids(result.Records))
+
+		result, err = lookup.QueryConnections(&QueryParams{InvitationID: "inv-2", Namespace: TheirNSPrefix})
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "3", "5"}, ids(result.Records))
+
+		result, err = lookup.QueryConnections(&QueryParams{InvitationID: "inv-1", Namespace: TheirNSPrefix})
+		require.NoError(t, err)
+		require.Empty(t, result.Records)
+	})
+
+	t.Run("test query connections sorted by time", func(t *testing.T) {
+		lookup := newLookup(t)
+
+		result, err := lookup.QueryConnections(&QueryParams{SortBy: SortByCreatedTime})
+		require.NoError(t, err)
+		require.Equal(t, []string{"5", "4", "3", "2", "1", "0"}, ids(result.Records))
+
+		result, err = lookup.QueryConnections(&QueryParams{SortBy: SortByUpdatedTime, Descending: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"5", "4", "3", "2", "1", "0"}, ids(result.Records))
+	})
+
+	t.Run("test query connections pages", func(t *testing.T) {
+		lookup := newLookup(t)
+
+		params := &QueryParams{SortBy: SortByCreatedTime, Limit: 4}
+
+		result, err := lookup.QueryConnections(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"5", "4", "3", "2"}, ids(result.Records))
+		require.NotEmpty(t, result.NextCursor)
+
+		params.Cursor = result.NextCursor
+
+		result, err = lookup.QueryConnections(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "0"}, ids(result.Records))
+		require.Empty(t, result.NextCursor)
+
+		params = &QueryParams{State: StateNameCompleted, Descending: true, Limit: 2}
+
+		result, err = lookup.QueryConnections(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"4", "2"}, ids(result.Records))
+
+		params.Cursor = result.NextCursor
+
+		result, err = lookup.QueryConnections(params)
+		require.NoError(t, err)
+		require.Equal(t, []string{"0"}, ids(result.Records))
+		require.Empty(t, result.NextCursor)
+	})
+
+	t.Run("test query connections with invalid parameters", func(t *testing.T) {
+		lookup := newLookup(t)
+
+		_, err := lookup.QueryConnections(&QueryParams{SortBy: "label"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported sort order")
+
+		_, err = lookup.QueryConnections(&QueryParams{Limit: -1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid limit")
+
+		_, err = lookup.QueryConnections(&QueryParams{Cursor: "!!!"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid cursor")
+
+		_, err = lookup.QueryConnections(&QueryParams{Cursor: "bm90LWpzb24"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid cursor")
+	})
+
+	t.Run("test query connections failure", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		require.NoError(t, store.Put(getConnectionKeyPrefix()("abc"), []byte("-----")))
+
+		require.NoError(t, store.Put(getConnectionIndexKeyPrefix()(SortByConnectionID, indexAscending, indexAll, "abc"),
+			[]byte("abc")))
+
+		lookup, err := NewLookup(&mockProvider{store: store})
+		require.NoError(t, err)
+
+		result, err := lookup.QueryConnections(&QueryParams{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record abc")
+		require.Nil(t, result)
+	})
+	t.Run("test query connections iterator failure", func(t *testing.T) {
+		lookup, err := NewLookup(&mockProvider{store: &mockstorage.MockStore{
+			Store:  make(map[string][]byte),
+			ErrItr: errors.New("iterator error"),
+		}})
+		require.NoError(t, err)
+
+		_, err = lookup.QueryConnections(&QueryParams{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator error")
+	})
+}
+
+func TestLookup_QueryConnectionsWithoutTime(t *testing.T) {
+	store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+	updated := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// the records b and d were saved before the times were recorded
+	for _, record := range []*Record{
+		{ConnectionID: "a", State: StateNameCompleted, CreatedTime: &updated, UpdatedTime: &updated},
+		{ConnectionID: "b", State: StateNameCompleted},
+		{ConnectionID: "c", State: StateNameCompleted, CreatedTime: &updated, UpdatedTime: &updated},
+		{ConnectionID: "d", State: StateNameCompleted},
+	} {
+		val, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(getConnectionKeyPrefix()(record.ConnectionID), val))
+	}
+
+	recorder, err := NewRecorder(&mockProvider{store: store})
+	require.NoError(t, err)
+
+	ids := func(result *QueryResult) []string {
+		var connectionIDs []string
+		for _, record := range result.Records {
+			connectionIDs = append(connectionIDs, record.ConnectionID)
+		}
+
+		return connectionIDs
+	}
+
+	result, err := recorder.QueryConnections(&QueryParams{SortBy: SortByCreatedTime})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "d", "a", "c"}, ids(result))
+
+	result, err = recorder.QueryConnections(&QueryParams{SortBy: SortByUpdatedTime, Descending: true, Limit: 3})
+	require.NoError(t, err)
+	require.Equal(t, []string{"c", "a", "d"}, ids(result))
+
+	result, err = recorder.QueryConnections(&QueryParams{
+		SortBy: SortByUpdatedTime, Descending: true, Limit: 3, Cursor: result.NextCursor,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, ids(result))
+	require.Empty(t, result.NextCursor)
+}
+
+func TestRecorder_ConnectionIndex(t *testing.T) {
+	store, err := mem.NewProvider().OpenStore(Namespace)
+	require.NoError(t, err)
+
+	recorder, err := NewRecorder(&mockProvider{store: store})
+	require.NoError(t, err)
+
+	for _, connectionID := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{
+			ConnectionID: connectionID,
+			ThreadID:     "thid-" + connectionID,
+			Namespace:    MyNSPrefix,
+			State:        stateNameInvited,
+			TheirDID:     "did:example:" + connectionID,
+			Tags:         []string{"gold"},
+		}))
+	}
+
+	query := func(params *QueryParams) []string {
+		result, queryErr := recorder.QueryConnections(params)
+		require.NoError(t, queryErr)
+
+		var connectionIDs []string
+		for _, record := range result.Records {
+			connectionIDs = append(connectionIDs, record.ConnectionID)
+		}
+
+		return connectionIDs
+	}
+
+	t.Run("test the index entries of the previous version are removed when the record is saved", func(t *testing.T) {
+		record, err := recorder.GetConnectionRecord("a")
+		require.NoError(t, err)
+
+		previous := indexKeys(record)
+
+		record.State = StateNameCompleted
+		record.Tags = nil
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+
+		current := indexKeys(record)
+
+		for _, key := range previous {
+			_, err = store.Get(key)
+			require.Equal(t, hasIndexKey(record, key), err == nil, key)
+		}
+
+		for _, key := range current {
+			_, err = store.Get(key)
+			require.NoError(t, err, key)
+		}
+
+		require.Equal(t, []string{"b", "c", "d"}, query(&QueryParams{State: stateNameInvited}))
+		require.Equal(t, []string{"a"}, query(&QueryParams{State: StateNameCompleted}))
+		require.Equal(t, []string{"b", "c", "d"}, query(&QueryParams{Tags: []string{"gold"}}))
+		require.Equal(t, []string{"b", "c", "d", "a"}, query(&QueryParams{SortBy: SortByUpdatedTime}))
+		require.Equal(t, []string{"a", "d", "c", "b"}, query(&QueryParams{SortBy: SortByUpdatedTime, Descending: true}))
+		require.Equal(t, []string{"c"}, query(&QueryParams{TheirDID: "did:example:c", Tags: []string{"gold"}}))
+	})
+
+	t.Run("test the query starts at the cursor of a removed record", func(t *testing.T) {
+		ascending, err := recorder.QueryConnections(&QueryParams{Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, "a", ascending.Records[0].ConnectionID)
+
+		descending, err := recorder.QueryConnections(&QueryParams{Descending: true, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, "d", descending.Records[0].ConnectionID)
+
+		require.NoError(t, recorder.RemoveConnection("a"))
+		require.NoError(t, recorder.RemoveConnection("d"))
+
+		require.Equal(t, []string{"b", "c"}, query(&QueryParams{Limit: 2, Cursor: ascending.NextCursor}))
+		require.Equal(t, []string{"c", "b"},
+			query(&QueryParams{Descending: true, Limit: 2, Cursor: descending.NextCursor}))
+	})
+
+	t.Run("test the index entries are removed with the record", func(t *testing.T) {
+		record, err := recorder.GetConnectionRecord("b")
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.RemoveConnection("b"))
+
+		for _, key := range indexKeys(record) {
+			_, err = store.Get(key)
+			require.True(t, errors.Is(err, storage.ErrDataNotFound), key)
+		}
+
+		require.Equal(t, []string{"c"}, query(&QueryParams{}))
+	})
+}
+
+func TestInvertedID(t *testing.T) {
+	ids := []string{"", "a", "ab", "abc", "b", "b_1", "ba"}
+
+	for i := 1; i < len(ids); i++ {
+		require.Less(t, invertedID(ids[i]), invertedID(ids[i-1]), ids[i])
+	}
+}
@@ -14,10 +14,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
+// nolint:gochecknoglobals
+var logger = log.New("aries-framework/store/connection")
+
 const (
 	// StateNameCompleted completed state.
 	StateNameCompleted = "completed"
@@ -39,12 +45,21 @@ func NewRecorder(p provider) (*Recorder, error) {
 		return nil, fmt.Errorf("failed to create new connection recorder : %w", err)
 	}
 
-	return &Recorder{lookup}, nil
+	recorder := &Recorder{Lookup: lookup}
+
+	// the index is built again by the next recorder if it fails
+	if err = recorder.indexRecords(); err != nil {
+		logger.Warnf("failed to index connection records : %s", err)
+	}
+
+	return recorder, nil
 }
 
 // Recorder is read-write connection store.
 type Recorder struct {
 	*Lookup
+	// invitationLock serializes the uses of the invitations counted by the recorder.
+	invitationLock sync.Mutex
 }
 
 // SaveInvitation saves invitation in permanent store for given key.
@@ -57,8 +72,23 @@ func (c *Recorder) SaveInvitation(id string, invitation interface{}) error {
 	return marshalAndSave(getInvitationKeyPrefix()(id), invitation, c.store)
 }
 
-// SaveConnectionRecord saves given connection records in underlying store.
+// SaveConnectionRecord saves given connection records in underlying store, the created and updated
+// times of the record are set. The record is indexed in the permanent store by its filter values for
+// each sort order of QueryConnections, the index entries of the previous version are removed.
 func (c *Recorder) SaveConnectionRecord(record *Record) error {
+	previous, err := c.GetConnectionRecord(record.ConnectionID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("get previous connection record: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	if record.CreatedTime == nil {
+		record.CreatedTime = &now
+	}
+
+	record.UpdatedTime = &now
+
 	if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
 		record, c.protocolStateStore); err != nil {
 		return fmt.Errorf("save connection record in protocol state store: %w", err)
@@ -85,6 +115,108 @@ func (c *Recorder) SaveConnectionRecord(record *Record) error {
 		}
 	}
 
+	if err := c.saveIndex(previous, record); err != nil {
+		return fmt.Errorf("save connection index in store: %w", err)
+	}
+
+	return nil
+}
+
+// saveIndex replaces the index entries of the previous version of the record with the entries of the record.
+func (c *Recorder) saveIndex(previous, record *Record) error {
+	keys := indexKeys(record)
+
+	current := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		current[key] = struct{}{}
+	}
+
+	saved := make(map[string]struct{})
+
+	if previous != nil {
+		for _, key := range indexKeys(previous) {
+			saved[key] = struct{}{}
+
+			if _, ok := current[key]; ok {
+				continue
+			}
+
+			if err := c.store.Delete(key); err != nil {
+				return fmt.Errorf("delete index entry %s: %w", key, err)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if _, ok := saved[key]; ok {
+			continue
+		}
+
+		if err := c.store.Put(key, []byte(record.ConnectionID)); err != nil {
+			return fmt.Errorf("save index entry %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// removeIndex removes the index entries of the record.
+func (c *Recorder) removeIndex(record *Record) error {
+	for _, key := range indexKeys(record) {
+		if err := c.store.Delete(key); err != nil {
+			return fmt.Errorf("delete index entry %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// indexRecords indexes the connection records saved before the index existed, it is done once.
+func (c *Recorder) indexRecords() error {
+	_, err := c.store.Get(connIndexVersionKey)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("get connection index version: %w", err)
+	}
+
+	records, err := c.QueryConnectionRecords()
+	if err != nil {
+		return fmt.Errorf("index connection records: %w", err)
+	}
+
+	// nothing to index yet, the records saved from now on are indexed by SaveConnectionRecord
+	if len(records) == 0 {
+		return nil
+	}
+
+	for _, record := range records {
+		if err = c.saveIndex(nil, record); err != nil {
+			return fmt.Errorf("index connection record %s: %w", record.ConnectionID, err)
+		}
+	}
+
+	return c.store.Put(connIndexVersionKey, []byte(connIndexVersion))
+}
+
+// SaveDIDMapping creates the mapping between the DIDs and the connection ID, e.g the connection is found
+// by the rotated DID before the connection record is updated.
+func (c *Recorder) SaveDIDMapping(myDID, theirDID, connectionID string) error {
+	if err := c.store.Put(getDIDConnMapKeyPrefix()(myDID, theirDID), []byte(connectionID)); err != nil {
+		return fmt.Errorf("save did and connection map in store: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveDIDMapping removes the mapping between the DIDs and the connection ID.
+func (c *Recorder) RemoveDIDMapping(myDID, theirDID string) error {
+	if err := c.store.Delete(getDIDConnMapKeyPrefix()(myDID, theirDID)); err != nil {
+		return fmt.Errorf("delete did and connection map from store: %w", err)
+	}
+
 	return nil
 }
 
@@ -157,18 +289,41 @@ func (c *Recorder) RemoveConnection(connectionID string) error {
 		return fmt.Errorf("unable to delete connection record from the store: connectionid=%s err=%w", connectionID, err)
 	}
 
+	err = c.store.Delete(getHealthKeyPrefix()(connectionID))
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("unable to delete connection health from the store: connectionid=%s err=%w", connectionID, err)
+	}
+
 	err = c.store.Delete(getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID))
 	if err != nil {
 		return fmt.Errorf("unable to delete did mapping connection record from the store: connectionid=%s err=%w",
 			connectionID, err)
 	}
 
+	// remove the mappings of the previous DIDs which are still in the grace period
+	if record.MyDIDRotation != nil {
+		if err = c.RemoveDIDMapping(record.MyDIDRotation.PreviousDID, record.TheirDID); err != nil {
+			return err
+		}
+	}
+
+	if record.TheirDIDRotation != nil {
+		if err = c.RemoveDIDMapping(record.MyDID, record.TheirDIDRotation.PreviousDID); err != nil {
+			return err
+		}
+	}
+
 	// remove namespace, threadID and connection ID mapping from protocol state store
 	err = removeMappings(c, record)
 	if err != nil {
 		return fmt.Errorf("unable to delete connection record with namespace mappings: %w", err)
 	}
 
+	if err = c.removeIndex(record); err != nil {
+		return fmt.Errorf("unable to delete connection index from the store: connectionid=%s err=%w",
+			connectionID, err)
+	}
+
 	return nil
 }
 
@@ -10,6 +10,7 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -733,6 +734,59 @@ func TestConnectionRecorder_ConnectionRecordMappings(t *testing.T) {
 	})
 }
 
+func TestConnectionRecorder_DIDMapping(t *testing.T) {
+	recorder, err := NewRecorder(&protocol.MockProvider{})
+	require.NoError(t, err)
+
+	t.Run("save and remove did mapping", func(t *testing.T) {
+		err = recorder.SaveDIDMapping("did:mydid:123", "did:theirdid:456", sampleConnID)
+		require.NoError(t, err)
+
+		connID, err := recorder.GetConnectionIDByDIDs("did:mydid:123", "did:theirdid:456")
+		require.NoError(t, err)
+		require.Equal(t, sampleConnID, connID)
+
+		err = recorder.RemoveDIDMapping("did:mydid:123", "did:theirdid:456")
+		require.NoError(t, err)
+
+		_, err = recorder.GetConnectionIDByDIDs("did:mydid:123", "did:theirdid:456")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+	t.Run("remove connection removes the mappings of the rotated dids", func(t *testing.T) {
+		record := &Record{
+			ThreadID:         threadIDValue,
+			ConnectionID:     uuid.New().String(),
+			State:            StateNameCompleted,
+			Namespace:        TheirNSPrefix,
+			MyDID:            "did:mydid:new",
+			TheirDID:         "did:theirdid:new",
+			MyDIDRotation:    &DIDRotation{PreviousDID: "did:mydid:old"},
+			TheirDIDRotation: &DIDRotation{PreviousDID: "did:theirdid:old"},
+		}
+		require.NoError(t, recorder.SaveDIDMapping("did:mydid:old", record.TheirDID, record.ConnectionID))
+		require.NoError(t, recorder.SaveDIDMapping(record.MyDID, "did:theirdid:old", record.ConnectionID))
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+
+		require.NoError(t, recorder.RemoveConnection(record.ConnectionID))
+
+		_, err = recorder.GetConnectionIDByDIDs("did:mydid:old", record.TheirDID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = recorder.GetConnectionIDByDIDs(record.MyDID, "did:theirdid:old")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+	t.Run("save did mapping error", func(t *testing.T) {
+		store := mockstorage.NewMockStoreProvider()
+		store.Store.ErrPut = errors.New("put error")
+
+		recorder, err := NewRecorder(&protocol.MockProvider{StoreProvider: store})
+		require.NoError(t, err)
+
+		err = recorder.SaveDIDMapping("did:mydid:123", "did:theirdid:456", sampleConnID)
+		require.EqualError(t, err, "save did and connection map in store: put error")
+	})
+}
+
 func TestConnectionRecorder_CreateNSKeys(t *testing.T) {
 	t.Run("creating their namespace key success", func(t *testing.T) {
 		key, err := CreateNamespaceKey(TheirNSPrefix, threadIDValue)
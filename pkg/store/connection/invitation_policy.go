@@ -0,0 +1,211 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	invPolicyKeyPrefix  = "invpolicy"
+	invRevokedKeyPrefix = "invrevoked"
+)
+
+var (
+	// ErrInvitationRevoked is returned when the invitation was revoked.
+	ErrInvitationRevoked = errors.New("invitation revoked")
+	// ErrInvitationExpired is returned when the invitation expired.
+	ErrInvitationExpired = errors.New("invitation expired")
+	// ErrInvitationUsedUp is returned when the invitation reached its maximum number of uses.
+	ErrInvitationUsedUp = errors.New("invitation used up")
+)
+
+// InvitationPolicy limits the use of an invitation created by the agent and counts its uses.
+type InvitationPolicy struct {
+	InvitationID string `json:"invitation_id"`
+	// MaxUses is the maximum number of requests accepted for the invitation, it is unlimited if zero.
+	MaxUses int `json:"max_uses,omitempty"`
+	// Expires is the time after which the invitation is not accepted, it never expires if not set.
+	Expires *time.Time `json:"expires,omitempty"`
+	// Revoked invitations are not accepted anymore.
+	Revoked bool `json:"revoked,omitempty"`
+	// Uses is the number of requests accepted for the invitation.
+	Uses int `json:"uses"`
+}
+
+// Check returns an error if the invitation cannot be used at the given time.
+func (p *InvitationPolicy) Check(now time.Time) error {
+	switch {
+	case p.Revoked:
+		return ErrInvitationRevoked
+	case p.Expires != nil && now.After(*p.Expires):
+		return ErrInvitationExpired
+	case p.MaxUses > 0 && p.Uses >= p.MaxUses:
+		return ErrInvitationUsedUp
+	default:
+		return nil
+	}
+}
+
+// GetInvitationPolicy returns the policy of the invitation. An invitation created without policy
+// has none unless it was revoked.
+func (c *Lookup) GetInvitationPolicy(invitationID string) (*InvitationPolicy, error) {
+	if invitationID == "" {
+		return nil, fmt.Errorf(errMsgInvalidKey)
+	}
+
+	revoked, err := c.isInvitationRevoked(invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &InvitationPolicy{}
+
+	err = getAndUnmarshal(getInvitationPolicyKeyPrefix()(invitationID), policy, c.store)
+	if errors.Is(err, storage.ErrDataNotFound) && revoked {
+		return &InvitationPolicy{InvitationID: invitationID, Revoked: true}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy.Revoked = revoked
+
+	return policy, nil
+}
+
+// CheckInvitation returns an error if the invitation cannot be used, the invitations without policy are unlimited.
+func (c *Lookup) CheckInvitation(invitationID string) error {
+	policy, err := c.GetInvitationPolicy(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get invitation policy: %w", err)
+	}
+
+	return policy.Check(time.Now())
+}
+
+// isInvitationRevoked returns true if the invitation was revoked.
+func (c *Lookup) isInvitationRevoked(invitationID string) (bool, error) {
+	_, err := c.store.Get(getInvitationRevokedKeyPrefix()(invitationID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SaveInvitationPolicy saves the policy of the invitation in permanent store.
+func (c *Recorder) SaveInvitationPolicy(policy *InvitationPolicy) error {
+	if policy.InvitationID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	c.invitationLock.Lock()
+	defer c.invitationLock.Unlock()
+
+	err := marshalAndSave(getInvitationPolicyKeyPrefix()(policy.InvitationID), policy, c.store)
+	if err != nil || !policy.Revoked {
+		return err
+	}
+
+	return c.store.Put(getInvitationRevokedKeyPrefix()(policy.InvitationID), []byte(policy.InvitationID))
+}
+
+// UseInvitation checks the policy of the invitation and counts the use. The invitations without policy
+// are unlimited and their uses are not counted.
+func (c *Recorder) UseInvitation(invitationID string) error {
+	c.invitationLock.Lock()
+	defer c.invitationLock.Unlock()
+
+	policy, err := c.GetInvitationPolicy(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get invitation policy: %w", err)
+	}
+
+	if err = policy.Check(time.Now()); err != nil {
+		return err
+	}
+
+	policy.Uses++
+
+	return marshalAndSave(getInvitationPolicyKeyPrefix()(invitationID), policy, c.store)
+}
+
+// ReleaseInvitation restores the use of the invitation counted by UseInvitation when the exchange
+// for the invitation failed.
+func (c *Recorder) ReleaseInvitation(invitationID string) error {
+	c.invitationLock.Lock()
+	defer c.invitationLock.Unlock()
+
+	policy, err := c.GetInvitationPolicy(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get invitation policy: %w", err)
+	}
+
+	if policy.Uses == 0 {
+		return nil
+	}
+
+	policy.Uses--
+
+	return marshalAndSave(getInvitationPolicyKeyPrefix()(invitationID), policy, c.store)
+}
+
+// RevokeInvitation revokes the invitation, the requests for the invitation are not accepted anymore.
+// The revocation is saved apart from the policy, it is not lost if the uses are counted by another recorder.
+func (c *Recorder) RevokeInvitation(invitationID string) error {
+	if invitationID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	_, err := c.store.Get(getInvitationPolicyKeyPrefix()(invitationID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		_, err = c.store.Get(getInvitationKeyPrefix()(invitationID))
+	}
+
+	if err != nil {
+		return fmt.Errorf("revoke invitation: %w", err)
+	}
+
+	return c.store.Put(getInvitationRevokedKeyPrefix()(invitationID), []byte(invitationID))
+}
+
+// getInvitationPolicyKeyPrefix key prefix for saving invitation policies.
+func getInvitationPolicyKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, invPolicyKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
+// getInvitationRevokedKeyPrefix key prefix for saving the revocation of invitations.
+func getInvitationRevokedKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, invRevokedKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
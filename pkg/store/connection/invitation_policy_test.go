@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+func TestInvitationPolicy_Check(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	require.NoError(t, (&InvitationPolicy{}).Check(now))
+	require.NoError(t, (&InvitationPolicy{MaxUses: 2, Uses: 1}).Check(now))
+	require.True(t, errors.Is((&InvitationPolicy{Revoked: true}).Check(now), ErrInvitationRevoked))
+	require.True(t, errors.Is((&InvitationPolicy{Expires: &past}).Check(now), ErrInvitationExpired))
+	require.True(t, errors.Is((&InvitationPolicy{MaxUses: 2, Uses: 2}).Check(now), ErrInvitationUsedUp))
+}
+
+func TestRecorder_InvitationPolicy(t *testing.T) {
+	t.Run("test use invitation with policy", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveInvitationPolicy(&InvitationPolicy{InvitationID: "inv-1", MaxUses: 2}))
+
+		require.NoError(t, recorder.UseInvitation("inv-1"))
+		require.NoError(t, recorder.UseInvitation("inv-1"))
+
+		err = recorder.UseInvitation("inv-1")
+		require.True(t, errors.Is(err, ErrInvitationUsedUp))
+
+		policy, err := recorder.GetInvitationPolicy("inv-1")
+		require.NoError(t, err)
+		require.Equal(t, 2, policy.Uses)
+	})
+
+	t.Run("test use invitation without policy", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.CheckInvitation("inv-1"))
+		require.NoError(t, recorder.UseInvitation("inv-1"))
+		require.NoError(t, recorder.ReleaseInvitation("inv-1"))
+
+		_, err = recorder.GetInvitationPolicy("inv-1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("test release invitation", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveInvitationPolicy(&InvitationPolicy{InvitationID: "inv-1", MaxUses: 1}))
+		require.NoError(t, recorder.ReleaseInvitation("inv-1"))
+
+		require.NoError(t, recorder.UseInvitation("inv-1"))
+		require.True(t, errors.Is(recorder.CheckInvitation("inv-1"), ErrInvitationUsedUp))
+
+		require.NoError(t, recorder.ReleaseInvitation("inv-1"))
+		require.NoError(t, recorder.CheckInvitation("inv-1"))
+
+		policy, err := recorder.GetInvitationPolicy("inv-1")
+		require.NoError(t, err)
+		require.Zero(t, policy.Uses)
+	})
+
+	t.Run("test use expired invitation", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		expires := time.Now().Add(-time.Second)
+		require.NoError(t, recorder.SaveInvitationPolicy(&InvitationPolicy{InvitationID: "inv-1", Expires: &expires}))
+
+		err = recorder.UseInvitation("inv-1")
+		require.True(t, errors.Is(err, ErrInvitationExpired))
+	})
+
+	t.Run("test revoke invitation", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveInvitation("inv-1", &struct{ ID string }{ID: "inv-1"}))
+		require.NoError(t, recorder.RevokeInvitation("inv-1"))
+
+		err = recorder.UseInvitation("inv-1")
+		require.True(t, errors.Is(err, ErrInvitationRevoked))
+
+		require.NoError(t, recorder.SaveInvitationPolicy(&InvitationPolicy{InvitationID: "inv-2", MaxUses: 5}))
+		require.NoError(t, recorder.UseInvitation("inv-2"))
+		require.NoError(t, recorder.RevokeInvitation("inv-2"))
+
+		policy, err := recorder.GetInvitationPolicy("inv-2")
+		require.NoError(t, err)
+		require.True(t, policy.Revoked)
+		require.Equal(t, 1, policy.Uses)
+		require.Equal(t, 5, policy.MaxUses)
+	})
+
+	t.Run("test revoke invitation used by another recorder", func(t *testing.T) {
+		provider := &protocol.MockProvider{StoreProvider: mockstorage.NewMockStoreProvider()}
+
+		recorder, err := NewRecorder(provider)
+		require.NoError(t, err)
+
+		other, err := NewRecorder(provider)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveInvitationPolicy(&InvitationPolicy{InvitationID: "inv-1", MaxUses: 5}))
+		require.NoError(t, other.RevokeInvitation("inv-1"))
+		require.True(t, errors.Is(recorder.UseInvitation("inv-1"), ErrInvitationRevoked))
+
+		policy, err := other.GetInvitationPolicy("inv-1")
+		require.NoError(t, err)
+		require.True(t, policy.Revoked)
+		require.Zero(t, policy.Uses)
+	})
+
+	t.Run("test revoke unknown invitation", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.RevokeInvitation("inv-1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("test invalid invitation ID", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		_, err = recorder.GetInvitationPolicy("")
+		require.EqualError(t, err, errMsgInvalidKey)
+
+		require.EqualError(t, recorder.SaveInvitationPolicy(&InvitationPolicy{}), errMsgInvalidKey)
+		require.Error(t, recorder.UseInvitation(""))
+		require.Error(t, recorder.ReleaseInvitation(""))
+		require.Error(t, recorder.CheckInvitation(""))
+		require.EqualError(t, recorder.RevokeInvitation(""), errMsgInvalidKey)
+	})
+
+	t.Run("test invitation policy store errors", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: errors.New("get error"),
+			}),
+		})
+		require.NoError(t, err)
+
+		err = recorder.UseInvitation("inv-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+
+		err = recorder.ReleaseInvitation("inv-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+
+		err = recorder.RevokeInvitation("inv-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+	})
+}